@@ -0,0 +1,49 @@
+// Package alerthistoryapp provides HTTP handlers for past alert firings.
+package alerthistoryapp
+
+import (
+	"context"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/alerthistorybus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles alert history HTTP requests.
+type App struct {
+	log             *logger.Logger
+	alertHistoryBus *alerthistorybus.Business
+}
+
+// NewApp constructs a new alert history app.
+func NewApp(log *logger.Logger, alertHistoryBus *alerthistorybus.Business) *App {
+	return &App{
+		log:             log,
+		alertHistoryBus: alertHistoryBus,
+	}
+}
+
+// Query handles GET /api/v1/alerts/history requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	if a.alertHistoryBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "alert history is not configured")
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	firings, err := a.alertHistoryBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if firings == nil {
+		firings = []alerthistorybus.Firing{}
+	}
+
+	return web.JSONResponse{Data: firings}
+}
@@ -0,0 +1,51 @@
+package alerthistoryapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/alerthistorybus"
+)
+
+// defaultRange is how far back a query without from looks.
+const defaultRange = 24 * time.Hour
+
+func parseFilter(r *http.Request) (alerthistorybus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	filter := alerthistorybus.QueryFilter{
+		To:     time.Now().UTC(),
+		Target: values.Get("target"),
+		Rule:   values.Get("rule"),
+	}
+
+	fieldErrs := make(map[string]string)
+
+	if to := values.Get("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			fieldErrs["to"] = "must be an RFC 3339 timestamp"
+		}
+		filter.To = t
+	}
+
+	filter.From = filter.To.Add(-defaultRange)
+	if from := values.Get("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			fieldErrs["from"] = "must be an RFC 3339 timestamp"
+		}
+		filter.From = t
+	}
+
+	if len(fieldErrs) == 0 && !filter.From.Before(filter.To) {
+		fieldErrs["from"] = "must be before to"
+	}
+
+	if len(fieldErrs) > 0 {
+		return alerthistorybus.QueryFilter{}, errs.FieldErrors(fieldErrs)
+	}
+
+	return filter, nil
+}
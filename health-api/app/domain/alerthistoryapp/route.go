@@ -0,0 +1,30 @@
+package alerthistoryapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/alerthistorybus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log             *logger.Logger
+	AlertHistoryBus *alerthistorybus.Business
+	Timeout         time.Duration
+	Auth            *auth.Auth
+}
+
+// Routes registers all alert history routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.AlertHistoryBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer))
+
+	v1.HandlerFunc(http.MethodGet, "/alerts/history", api.Query)
+}
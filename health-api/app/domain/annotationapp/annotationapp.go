@@ -0,0 +1,80 @@
+// Package annotationapp provides HTTP handlers for annotations such as
+// deploy events.
+package annotationapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/annotationbus"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles annotation HTTP requests.
+type App struct {
+	log           *logger.Logger
+	annotationBus *annotationbus.Business
+}
+
+// NewApp constructs a new annotation app.
+func NewApp(log *logger.Logger, annotationBus *annotationbus.Business) *App {
+	return &App{
+		log:           log,
+		annotationBus: annotationBus,
+	}
+}
+
+// Create handles POST /api/v1/annotations requests.
+func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
+	var na annotationbus.NewAnnotation
+	if err := web.Decode(r, &na); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ann, err := a.annotationBus.Create(ctx, na)
+	if err != nil {
+		if errors.Is(err, tenant.ErrNamespace) {
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "create: %w", err)
+	}
+
+	return web.JSONResponse{Data: ann, StatusCode: http.StatusCreated}
+}
+
+// Query handles GET /api/v1/annotations requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	annotations, err := a.annotationBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if annotations == nil {
+		annotations = []annotationbus.Annotation{}
+	}
+
+	return web.JSONResponse{Data: annotations}
+}
+
+// Delete handles DELETE /api/v1/annotations/{id} requests.
+func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	if err := a.annotationBus.Delete(ctx, id); err != nil {
+		if errors.Is(err, annotationbus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "annotation %s not found", id)
+		}
+		return errs.Newf(errs.Internal, "delete: %w", err)
+	}
+
+	return nil
+}
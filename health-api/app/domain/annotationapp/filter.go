@@ -0,0 +1,33 @@
+package annotationapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/annotationbus"
+)
+
+func parseFilter(r *http.Request) (annotationbus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter annotationbus.QueryFilter
+
+	if target := values.Get("target"); target != "" {
+		filter.Target = &target
+	}
+
+	if kind := values.Get("kind"); kind != "" {
+		filter.Kind = &kind
+	}
+
+	if since := values.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return annotationbus.QueryFilter{}, errs.FieldErrors(map[string]string{"since": "must be an RFC 3339 timestamp"})
+		}
+		filter.Since = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,34 @@
+package annotationapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/annotationbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log           *logger.Logger
+	AnnotationBus *annotationbus.Business
+	Timeout       time.Duration
+	Auth          *auth.Auth
+}
+
+// Routes registers all annotation routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.AnnotationBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth))
+	viewer := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleViewer))
+	operator := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleOperator))
+
+	viewer.HandlerFunc(http.MethodGet, "/annotations", api.Query)
+	operator.HandlerFunc(http.MethodPost, "/annotations", api.Create)
+	operator.HandlerFunc(http.MethodDelete, "/annotations/{id}", api.Delete)
+}
@@ -0,0 +1,75 @@
+// Package compareapp provides HTTP handlers for comparing two targets, such
+// as a canary and its baseline, and for aggregating the probes of one.
+package compareapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/comparebus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles compare HTTP requests.
+type App struct {
+	log        *logger.Logger
+	compareBus *comparebus.Business
+}
+
+// NewApp constructs a new compare app.
+func NewApp(log *logger.Logger, compareBus *comparebus.Business) *App {
+	return &App{
+		log:        log,
+		compareBus: compareBus,
+	}
+}
+
+// Compare handles GET /api/v1/compare requests.
+func (a *App) Compare(ctx context.Context, r *http.Request) web.Encoder {
+	if a.compareBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "comparisons need prometheus")
+	}
+
+	q, err := parseQuery(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cmp, err := a.compareBus.Compare(ctx, q.a, q.b, q.window)
+	if err != nil {
+		if errors.Is(err, comparebus.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "compare: %w", err)
+	}
+
+	return web.JSONResponse{Data: cmp}
+}
+
+// Aggregate handles GET /api/v1/health/{target}/aggregate requests.
+func (a *App) Aggregate(ctx context.Context, r *http.Request) web.Encoder {
+	if a.compareBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "aggregations need prometheus")
+	}
+
+	q, err := parseAggregateQuery(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	agg, err := a.compareBus.Aggregate(ctx, web.Param(r, "target"), q.fn, q.window, q.end)
+	if err != nil {
+		switch {
+		case errors.Is(err, comparebus.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		case errors.Is(err, comparebus.ErrUnknownFn):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "aggregate: %w", err)
+	}
+
+	return web.JSONResponse{Data: agg}
+}
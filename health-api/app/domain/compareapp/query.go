@@ -0,0 +1,105 @@
+package compareapp
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/comparebus"
+)
+
+// defaultWindow is the window of requests that don't set one.
+const defaultWindow = time.Hour
+
+type query struct {
+	a      string
+	b      string
+	window time.Duration
+}
+
+func parseQuery(r *http.Request) (query, error) {
+	values := r.URL.Query()
+
+	q := query{
+		a:      values.Get("a"),
+		b:      values.Get("b"),
+		window: defaultWindow,
+	}
+	fields := make(map[string]string)
+
+	if q.a == "" {
+		fields["a"] = "required"
+	}
+	if q.b == "" {
+		fields["b"] = "required"
+	}
+
+	if v := values.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		switch {
+		case err != nil:
+			fields["window"] = "must be a duration, e.g. 30m or 1h"
+		case d < comparebus.MinWindow || d > comparebus.MaxWindow:
+			fields["window"] = fmt.Sprintf("must be between %s and %s", comparebus.MinWindow, comparebus.MaxWindow)
+		}
+		q.window = d
+	}
+
+	if len(fields) > 0 {
+		return query{}, errs.FieldErrors(fields)
+	}
+
+	return q, nil
+}
+
+type aggregateQuery struct {
+	fn     string
+	window time.Duration
+	end    time.Time
+}
+
+func parseAggregateQuery(r *http.Request) (aggregateQuery, error) {
+	values := r.URL.Query()
+
+	q := aggregateQuery{
+		fn:     values.Get("fn"),
+		window: defaultWindow,
+		end:    time.Now(),
+	}
+	fields := make(map[string]string)
+
+	if !slices.Contains(comparebus.Fns, q.fn) {
+		fields["fn"] = "must be one of " + strings.Join(comparebus.Fns, ", ")
+	}
+
+	if v := values.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		switch {
+		case err != nil:
+			fields["window"] = "must be a duration, e.g. 30m or 1h"
+		case d < comparebus.MinWindow || d > comparebus.MaxWindow:
+			fields["window"] = fmt.Sprintf("must be between %s and %s", comparebus.MinWindow, comparebus.MaxWindow)
+		}
+		q.window = d
+	}
+
+	if v := values.Get("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		switch {
+		case err != nil:
+			fields["end"] = "must be an RFC 3339 timestamp"
+		case t.After(q.end):
+			fields["end"] = "must not be in the future"
+		}
+		q.end = t
+	}
+
+	if len(fields) > 0 {
+		return aggregateQuery{}, errs.FieldErrors(fields)
+	}
+
+	return q, nil
+}
@@ -0,0 +1,54 @@
+package forecastapp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/forecastbus"
+)
+
+func parseFilter(r *http.Request) (forecastbus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter forecastbus.QueryFilter
+	fieldErrs := make(map[string]string)
+
+	if kind := values.Get("kind"); kind != "" {
+		k, err := forecastbus.ParseKind(kind)
+		if err != nil {
+			fieldErrs["kind"] = "must be certificate or disk"
+		}
+		filter.Kind = k
+	}
+
+	if within := values.Get("within"); within != "" {
+		d, err := parseDuration(within)
+		if err != nil || d <= 0 {
+			fieldErrs["within"] = "must be a positive duration, e.g. 72h or 30d"
+		}
+		filter.Within = d
+	}
+
+	if len(fieldErrs) > 0 {
+		return forecastbus.QueryFilter{}, errs.FieldErrors(fieldErrs)
+	}
+
+	return filter, nil
+}
+
+// parseDuration parses a Go duration, or a whole number of days such as
+// "30d", the natural unit for expiries.
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,49 @@
+// Package forecastapp provides HTTP handlers for expiry forecasts.
+package forecastapp
+
+import (
+	"context"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/forecastbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles forecast HTTP requests.
+type App struct {
+	log         *logger.Logger
+	forecastBus *forecastbus.Business
+}
+
+// NewApp constructs a new forecast app.
+func NewApp(log *logger.Logger, forecastBus *forecastbus.Business) *App {
+	return &App{
+		log:         log,
+		forecastBus: forecastBus,
+	}
+}
+
+// Query handles GET /api/v1/forecasts requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	if a.forecastBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "forecasts are not configured")
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	forecasts, err := a.forecastBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if forecasts == nil {
+		forecasts = []forecastbus.Forecast{}
+	}
+
+	return web.JSONResponse{Data: forecasts}
+}
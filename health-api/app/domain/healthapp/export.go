@@ -0,0 +1,54 @@
+package healthapp
+
+import (
+	"strconv"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/web"
+)
+
+var checkHeader = []string{"target", "status", "last_checked", "probe", "team", "severity", "duration_seconds", "http_status_code", "ssl_expiry_days"}
+
+func checkRows(checks []healthbus.HealthCheck) [][]string {
+	rows := make([][]string, len(checks))
+	for i, c := range checks {
+		rows[i] = []string{
+			c.Target,
+			string(c.Status),
+			c.LastChecked.Format(time.RFC3339),
+			c.Probe,
+			c.Team,
+			c.Severity,
+			strconv.FormatFloat(c.DurationSeconds, 'f', -1, 64),
+			strconv.Itoa(c.HTTPStatusCode),
+			strconv.FormatFloat(c.SSLExpiryDays, 'f', 1, 64),
+		}
+	}
+	return rows
+}
+
+var alertHeader = []string{"title", "state", "active_at", "value", "target", "severity"}
+
+func alertRows(alerts []healthbus.Alert) [][]string {
+	rows := make([][]string, len(alerts))
+	for i, a := range alerts {
+		rows[i] = []string{
+			a.Title,
+			a.State,
+			a.ActiveAt,
+			a.Value,
+			a.Labels["target"],
+			string(a.Severity),
+		}
+	}
+	return rows
+}
+
+// export returns the table in the requested spreadsheet format.
+func export(format string, header []string, rows [][]string) web.Encoder {
+	if format == web.FormatXLSX {
+		return web.XLSXResponse{Header: header, Rows: rows}
+	}
+	return web.CSVResponse{Header: header, Rows: rows}
+}
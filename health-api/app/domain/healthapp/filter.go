@@ -0,0 +1,113 @@
+package healthapp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/healthbus"
+)
+
+func parseFilter(r *http.Request) (healthbus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter healthbus.QueryFilter
+
+	if team := values.Get("team"); team != "" {
+		filter.Team = &team
+	}
+
+	if s := values.Get("filter"); s != "" {
+		expr, err := healthbus.ParseFilter(s)
+		if err != nil {
+			return healthbus.QueryFilter{}, errs.FieldErrors(map[string]string{"filter": err.Error()})
+		}
+		filter.Expr = expr
+	}
+
+	return filter, nil
+}
+
+func parseAlertFilter(r *http.Request) (healthbus.AlertFilter, error) {
+	values := r.URL.Query()
+
+	var filter healthbus.AlertFilter
+
+	filter.GroupBy = splitParam(values.Get("group_by"))
+
+	for _, s := range splitParam(values.Get("severity")) {
+		sev, ok := healthbus.ParseSeverity(s)
+		if !ok {
+			return healthbus.AlertFilter{}, errs.FieldErrors(map[string]string{"severity": "must be critical, warning or info"})
+		}
+		filter.Severity = append(filter.Severity, sev)
+	}
+
+	return filter, nil
+}
+
+// splitParam splits a comma-separated query parameter, dropping empty
+// entries.
+func splitParam(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func parseSince(r *http.Request) (time.Time, error) {
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		return time.Time{}, errs.FieldErrors(map[string]string{"since": "required"})
+	}
+
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, errs.FieldErrors(map[string]string{"since": "must be an RFC 3339 timestamp"})
+	}
+
+	return t, nil
+}
+
+// defaultWindows are the trend windows of the summary when none are asked
+// for: an hour and a day ago.
+var defaultWindows = []string{"1h", "24h"}
+
+const (
+	maxWindows = 5
+	maxWindow  = 30 * 24 * time.Hour
+)
+
+// window is a trend window of the summary, keeping the name it was asked
+// for so "24h" isn't echoed back as "24h0m0s".
+type window struct {
+	name     string
+	duration time.Duration
+}
+
+func parseWindows(r *http.Request) ([]window, error) {
+	names := defaultWindows
+	if v := r.URL.Query().Get("windows"); v != "" {
+		names = splitParam(v)
+	}
+
+	if len(names) > maxWindows {
+		return nil, errs.FieldErrors(map[string]string{"windows": "at most 5 windows"})
+	}
+
+	windows := make([]window, 0, len(names))
+	for _, name := range names {
+		d, err := time.ParseDuration(name)
+		if err != nil || d <= 0 || d > maxWindow {
+			return nil, errs.FieldErrors(map[string]string{"windows": "must be durations between 0 and 720h"})
+		}
+
+		windows = append(windows, window{name: name, duration: d})
+	}
+
+	return windows, nil
+}
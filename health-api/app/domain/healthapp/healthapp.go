@@ -4,35 +4,72 @@ package healthapp
 import (
 	"context"
 	"net/http"
+	"strconv"
+	"time"
 
 	"health-api/app/sdk/errs"
+	"health-api/business/domain/annotationbus"
 	"health-api/business/domain/healthbus"
+	"health-api/business/domain/historybus"
+	"health-api/business/domain/oncallbus"
+	"health-api/business/domain/proberbus"
 	"health-api/foundation/logger"
 	"health-api/foundation/web"
 )
 
 // App handles health check HTTP requests.
 type App struct {
-	log       *logger.Logger
-	healthBus *healthbus.Business
+	log              *logger.Logger
+	healthBus        *healthbus.Business
+	historyBus       *historybus.Business
+	onCallBus        *oncallbus.Business
+	annotationBus    *annotationbus.Business
+	proberBus        *proberbus.Business
+	readinessTimeout time.Duration
 }
 
-// NewApp constructs a new health app.
-func NewApp(log *logger.Logger, healthBus *healthbus.Business) *App {
+// NewApp constructs a new health app. The on-call, annotation and prober
+// business layers are optional.
+func NewApp(log *logger.Logger, healthBus *healthbus.Business, historyBus *historybus.Business, onCallBus *oncallbus.Business, annotationBus *annotationbus.Business, proberBus *proberbus.Business, readinessTimeout time.Duration) *App {
 	return &App{
-		log:       log,
-		healthBus: healthBus,
+		log:              log,
+		healthBus:        healthBus,
+		historyBus:       historyBus,
+		onCallBus:        onCallBus,
+		annotationBus:    annotationBus,
+		proberBus:        proberBus,
+		readinessTimeout: readinessTimeout,
 	}
 }
 
 // QueryHealthChecks handles GET /api/v1/health requests.
 func (a *App) QueryHealthChecks(ctx context.Context, r *http.Request) web.Encoder {
-	summary, err := a.healthBus.QueryHealthChecks(ctx)
+	filter, err := parseFilter(r)
 	if err != nil {
-		return errs.Newf(errs.Internal, "query health checks: %s", err)
+		return errs.New(errs.InvalidArgument, err)
 	}
 
-	return web.JSONResponse{Data: summary}
+	summary, err := a.healthBus.QueryHealthChecks(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query health checks: %w", err)
+	}
+
+	a.applyDeploys(ctx, summary.Checks)
+	a.setSnapshotAge(ctx)
+
+	if format := web.Format(r); format != web.FormatJSON {
+		return export(format, checkHeader, checkRows(summary.Checks))
+	}
+
+	// Dashboards polling the list can ask for a smaller binary encoding.
+	switch web.Encoding(r) {
+	case web.EncodingMsgpack:
+		return web.MsgpackResponse{Data: summary, StatusCode: partialStatus(summary.Partial)}
+	case web.EncodingProtobuf:
+		return web.ProtobufResponse{Data: marshalSummary(summary), StatusCode: partialStatus(summary.Partial)}
+	}
+
+	return web.JSONResponse{Data: summary, StatusCode: partialStatus(summary.Partial)}
 }
 
 // QueryHealthCheckByTarget handles GET /api/v1/health/{target} requests.
@@ -44,31 +81,157 @@ func (a *App) QueryHealthCheckByTarget(ctx context.Context, r *http.Request) web
 
 	check, err := a.healthBus.QueryHealthCheckByTarget(ctx, target)
 	if err != nil {
-		return errs.Newf(errs.NotFound, "health check not found: %s", err)
+		return errs.Newf(errs.NotFound, "health check not found: %w", err)
 	}
 
-	return web.JSONResponse{Data: check}
+	checks := []healthbus.HealthCheck{check}
+	a.applyDeploys(ctx, checks)
+	a.setSnapshotAge(ctx)
+
+	return web.JSONResponse{Data: checks[0]}
+}
+
+// QueryChanges handles GET /api/v1/health/changes requests. It returns the
+// current checks of the targets whose status changed after since.
+func (a *App) QueryChanges(ctx context.Context, r *http.Request) web.Encoder {
+	since, err := parseSince(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	until := time.Now().UTC()
+
+	changes, err := a.historyBus.Query(ctx, historybus.QueryFilter{Since: &since, Until: &until})
+	if err != nil {
+		return errs.Newf(errs.Internal, "query changes: %w", err)
+	}
+
+	changed := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		changed[c.Target] = true
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	summary, err := a.healthBus.QueryHealthChecks(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query health checks: %w", err)
+	}
+
+	set := ChangeSet{
+		Since:  since,
+		Until:  until,
+		Checks: []healthbus.HealthCheck{},
+	}
+	for _, check := range summary.Checks {
+		if changed[check.Target] {
+			set.Checks = append(set.Checks, check)
+		}
+	}
+	set.Total = len(set.Checks)
+
+	a.setSnapshotAge(ctx)
+
+	return web.JSONResponse{Data: set}
 }
 
 // QueryAlerts handles GET /api/v1/alerts requests.
 func (a *App) QueryAlerts(ctx context.Context, r *http.Request) web.Encoder {
-	summary, err := a.healthBus.QueryAlerts(ctx)
+	filter, err := parseAlertFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	summary, err := a.healthBus.QueryAlerts(ctx, filter)
 	if err != nil {
-		return errs.Newf(errs.Internal, "query alerts: %s", err)
+		return errs.Newf(errs.Internal, "query alerts: %w", err)
 	}
 
-	return web.JSONResponse{Data: summary}
+	a.applyOnCall(ctx, summary)
+	a.setSnapshotAge(ctx)
+
+	if format := web.Format(r); format != web.FormatJSON {
+		return export(format, alertHeader, alertRows(summary.Alerts))
+	}
+
+	return web.JSONResponse{Data: summary, StatusCode: partialStatus(summary.Partial)}
 }
 
-// Readiness handles GET /readiness requests.
+// applyOnCall names who is on call for the team of each alert, listed
+// or grouped. Lookups are best effort; an alert whose team has no
+// schedule, or whose schedule can't be read, keeps no name.
+func (a *App) applyOnCall(ctx context.Context, summary healthbus.AlertSummary) {
+	if a.onCallBus == nil {
+		return
+	}
+
+	apply := func(alerts []healthbus.Alert) {
+		for i, al := range alerts {
+			if oc, err := a.onCallBus.Query(ctx, al.Team); err == nil {
+				alerts[i].OnCall = oc.Name
+			}
+		}
+	}
+
+	apply(summary.Alerts)
+	for _, g := range summary.Groups {
+		apply(g.Alerts)
+	}
+}
+
+// applyDeploys sets the last recorded deploy of each check's target. The
+// lookup is best effort; checks keep no deploy when it fails.
+func (a *App) applyDeploys(ctx context.Context, checks []healthbus.HealthCheck) {
+	if a.annotationBus == nil {
+		return
+	}
+
+	deploys, err := a.annotationBus.LatestDeploys(ctx)
+	if err != nil {
+		a.log.Error(ctx, "healthapp", "status", "query deploys failed", "error", err)
+		return
+	}
+
+	for i, c := range checks {
+		if d, ok := deploys[c.Target]; ok {
+			checks[i].LastDeploy = &healthbus.Deploy{
+				ID:      d.ID,
+				At:      d.Time,
+				Version: d.Version,
+				Text:    d.Text,
+				Author:  d.Author,
+				URL:     d.URL,
+			}
+		}
+	}
+}
+
+// partialStatus answers results missing the data of failed backends with
+// 207 Multi-Status, so clients notice them without parsing the body.
+func partialStatus(partial bool) int {
+	if partial {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
+// Readiness handles GET /readiness requests. It returns 503 when a required
+// backend is unreachable.
 func (a *App) Readiness(ctx context.Context, r *http.Request) web.Encoder {
-	data := struct {
-		Status string `json:"status"`
-	}{
-		Status: "ok",
+	ctx, cancel := context.WithTimeout(ctx, a.readinessTimeout)
+	defer cancel()
+
+	readiness := a.healthBus.CheckDependencies(ctx)
+
+	statusCode := http.StatusOK
+	if !readiness.Ready() {
+		statusCode = http.StatusServiceUnavailable
 	}
 
-	return web.JSONResponse{Data: data}
+	return web.JSONResponse{Data: readiness, StatusCode: statusCode}
 }
 
 // Liveness handles GET /liveness requests.
@@ -81,3 +244,17 @@ func (a *App) Liveness(ctx context.Context, r *http.Request) web.Encoder {
 
 	return web.JSONResponse{Data: data}
 }
+
+// setSnapshotAge reports the age in seconds of the data served when it comes
+// from the poller's snapshot rather than a live store query.
+func (a *App) setSnapshotAge(ctx context.Context) {
+	takenAt, ok := a.healthBus.SnapshotTime()
+	if !ok {
+		return
+	}
+
+	if w := web.GetWriter(ctx); w != nil {
+		age := int(time.Since(takenAt).Seconds())
+		w.Header().Set("X-Snapshot-Age", strconv.Itoa(age))
+	}
+}
@@ -0,0 +1,61 @@
+package healthapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/web"
+)
+
+// Inject handles POST /api/v1/debug/inject requests. The target reports
+// the requested status for the given minutes.
+func (a *App) Inject(ctx context.Context, r *http.Request) web.Encoder {
+	var ni NewInjection
+	if err := web.Decode(r, &ni); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	inj, err := a.healthBus.Inject(ctx, healthbus.NewInjection{
+		Target:   ni.Target,
+		Status:   healthbus.Status(ni.Status),
+		Duration: time.Duration(ni.Minutes) * time.Minute,
+		Reason:   ni.Reason,
+		By:       mid.GetClaims(ctx).Subject,
+	})
+	if err != nil {
+		if errors.Is(err, healthbus.ErrInvalidInjection) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.NotFound, "health check not found: %w", err)
+	}
+
+	return web.JSONResponse{Data: inj, StatusCode: http.StatusCreated}
+}
+
+// QueryInjections handles GET /api/v1/debug/inject requests.
+func (a *App) QueryInjections(ctx context.Context, r *http.Request) web.Encoder {
+	return web.JSONResponse{Data: a.healthBus.QueryInjections(ctx)}
+}
+
+// StopInjection handles DELETE /api/v1/debug/inject/{target} requests.
+func (a *App) StopInjection(ctx context.Context, r *http.Request) web.Encoder {
+	target := web.Param(r, "target")
+
+	if _, err := a.healthBus.QueryHealthCheckByTarget(ctx, target); err != nil {
+		return errs.Newf(errs.NotFound, "health check not found: %w", err)
+	}
+
+	if err := a.healthBus.StopInjection(ctx, target); err != nil {
+		if errors.Is(err, healthbus.ErrNoInjection) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "stop: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package healthapp
+
+import (
+	"time"
+
+	"health-api/business/domain/healthbus"
+)
+
+// ChangeSet is the response of the changes endpoint. Pass Until as the
+// since parameter of the next request to receive only newer changes.
+type ChangeSet struct {
+	Since  time.Time               `json:"since"`
+	Until  time.Time               `json:"until"`
+	Checks []healthbus.HealthCheck `json:"checks"`
+	Total  int                     `json:"total"`
+}
+
+// NewInjection is the request body of the inject endpoint.
+type NewInjection struct {
+	Target  string `json:"target" validate:"required"`
+	Status  string `json:"status" validate:"required"`
+	Minutes int    `json:"minutes" validate:"min=1,max=60"`
+	Reason  string `json:"reason"`
+}
+
+// HealthMeta is the meta of the v2 health check list: the counts of the
+// listed checks and, for partial results, the backends that failed.
+type HealthMeta struct {
+	Total       int                     `json:"total"`
+	Healthy     int                     `json:"healthy"`
+	Degraded    int                     `json:"degraded"`
+	Down        int                     `json:"down"`
+	Unknown     int                     `json:"unknown"`
+	Flapping    int                     `json:"flapping"`
+	Maintenance int                     `json:"maintenance"`
+	Paused      int                     `json:"paused"`
+	Partial     bool                    `json:"partial,omitempty"`
+	Errors      []healthbus.SourceError `json:"errors,omitempty"`
+}
+
+func toHealthMeta(s healthbus.HealthSummary) HealthMeta {
+	return HealthMeta{
+		Total:       s.Total,
+		Healthy:     s.Healthy,
+		Degraded:    s.Degraded,
+		Down:        s.Down,
+		Unknown:     s.Unknown,
+		Flapping:    s.Flapping,
+		Maintenance: s.Maintenance,
+		Paused:      s.Paused,
+		Partial:     s.Partial,
+		Errors:      s.Errors,
+	}
+}
+
+// AlertMeta is the meta of the v2 alert list.
+type AlertMeta struct {
+	Total    int                     `json:"total"`
+	Firing   int                     `json:"firing"`
+	Pending  int                     `json:"pending"`
+	Normal   int                     `json:"normal"`
+	Critical int                     `json:"critical"`
+	Warning  int                     `json:"warning"`
+	Info     int                     `json:"info"`
+	Partial  bool                    `json:"partial,omitempty"`
+	Errors   []healthbus.SourceError `json:"errors,omitempty"`
+}
+
+func toAlertMeta(s healthbus.AlertSummary) AlertMeta {
+	return AlertMeta{
+		Total:    s.Total,
+		Firing:   s.Firing,
+		Pending:  s.Pending,
+		Normal:   s.Normal,
+		Critical: s.Critical,
+		Warning:  s.Warning,
+		Info:     s.Info,
+		Partial:  s.Partial,
+		Errors:   s.Errors,
+	}
+}
+
+// Counts holds the number of targets in each status.
+type Counts struct {
+	Total       int `json:"total"`
+	Healthy     int `json:"healthy"`
+	Degraded    int `json:"degraded"`
+	Down        int `json:"down"`
+	Unknown     int `json:"unknown"`
+	Flapping    int `json:"flapping"`
+	Maintenance int `json:"maintenance"`
+	Paused      int `json:"paused"`
+}
+
+// add counts a target in status.
+func (c *Counts) add(status healthbus.Status) {
+	c.Total++
+
+	switch status {
+	case healthbus.StatusHealthy:
+		c.Healthy++
+	case healthbus.StatusDegraded:
+		c.Degraded++
+	case healthbus.StatusDown:
+		c.Down++
+	case healthbus.StatusUnknown:
+		c.Unknown++
+	case healthbus.StatusFlapping:
+		c.Flapping++
+	case healthbus.StatusMaintenance:
+		c.Maintenance++
+	case healthbus.StatusPaused:
+		c.Paused++
+	}
+}
+
+// sub returns the counts of c less those of past, so a count that grew is
+// positive.
+func (c Counts) sub(past Counts) Counts {
+	return Counts{
+		Total:       c.Total - past.Total,
+		Healthy:     c.Healthy - past.Healthy,
+		Degraded:    c.Degraded - past.Degraded,
+		Down:        c.Down - past.Down,
+		Unknown:     c.Unknown - past.Unknown,
+		Flapping:    c.Flapping - past.Flapping,
+		Maintenance: c.Maintenance - past.Maintenance,
+		Paused:      c.Paused - past.Paused,
+	}
+}
+
+// Trend holds the counts of the summary's targets Window before it and the
+// change since.
+type Trend struct {
+	Window string    `json:"window"`
+	At     time.Time `json:"at"`
+	Counts Counts    `json:"counts"`
+	Delta  Counts    `json:"delta"`
+}
+
+// Summary is the response of the summary endpoint: the current counts and
+// how they moved over each window.
+type Summary struct {
+	At      time.Time               `json:"at"`
+	Counts  Counts                  `json:"counts"`
+	Trends  []Trend                 `json:"trends"`
+	Partial bool                    `json:"partial,omitempty"`
+	Errors  []healthbus.SourceError `json:"errors,omitempty"`
+}
@@ -0,0 +1,184 @@
+package healthapp
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The protobuf encoding of the health check list is written by hand
+// following health.proto, which saves generated code for three messages.
+// As in proto3, fields holding their zero value are left out.
+
+func marshalSummary(s healthbus.HealthSummary) []byte {
+	var b []byte
+
+	b = appendInt(b, 1, s.Total)
+	b = appendInt(b, 2, s.Healthy)
+	b = appendInt(b, 3, s.Degraded)
+	b = appendInt(b, 4, s.Down)
+	b = appendInt(b, 5, s.Unknown)
+	b = appendInt(b, 6, s.Flapping)
+	b = appendInt(b, 7, s.Maintenance)
+	for _, c := range s.Checks {
+		b = appendMessage(b, 8, marshalCheck(c))
+	}
+	b = appendBool(b, 9, s.Partial)
+	for _, e := range s.Errors {
+		var m []byte
+		m = appendString(m, 1, e.Source)
+		m = appendString(m, 2, e.Error)
+		b = appendMessage(b, 10, m)
+	}
+	b = appendInt(b, 11, s.Paused)
+
+	return b
+}
+
+func marshalCheck(c healthbus.HealthCheck) []byte {
+	var b []byte
+
+	b = appendString(b, 1, c.Target)
+	b = appendString(b, 2, string(c.Status))
+	if !c.LastChecked.IsZero() {
+		b = appendMessage(b, 3, marshalTimestamp(c.LastChecked))
+	}
+	b = appendString(b, 4, c.Probe)
+	b = appendString(b, 5, c.Namespace)
+	b = appendString(b, 6, c.Instance)
+	b = appendDouble(b, 7, c.DurationSeconds)
+	b = appendInt(b, 8, c.HTTPStatusCode)
+	b = appendDouble(b, 9, c.SSLExpiryDays)
+	b = appendDouble(b, 10, c.DNSLookupSeconds)
+	for _, st := range c.Steps {
+		var m []byte
+		m = appendString(m, 1, st.Name)
+		m = appendBool(m, 2, st.Success)
+		m = appendInt(m, 3, st.StatusCode)
+		m = appendDouble(m, 4, st.DurationSeconds)
+		m = appendString(m, 5, st.Error)
+		b = appendMessage(b, 11, m)
+	}
+	b = appendBool(b, 12, c.Suppressed)
+	b = appendString(b, 13, c.RootCause)
+	b = appendDouble(b, 14, c.FlapScore)
+	b = appendDouble(b, 15, c.LatencySLO)
+	b = appendString(b, 16, c.DegradedReason)
+	b = appendString(b, 17, c.Maintenance)
+	b = appendBool(b, 18, c.Injected)
+	b = appendString(b, 19, c.Team)
+	b = appendString(b, 20, c.RunbookURL)
+	b = appendString(b, 21, c.Severity)
+
+	// Map entries are sorted so equal checks encode equally, which keeps
+	// ETags stable.
+	keys := make([]string, 0, len(c.Tags))
+	for k := range c.Tags {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		var m []byte
+		m = protowire.AppendTag(m, 1, protowire.BytesType)
+		m = protowire.AppendString(m, k)
+		m = protowire.AppendTag(m, 2, protowire.BytesType)
+		m = protowire.AppendString(m, c.Tags[k])
+		b = appendMessage(b, 22, m)
+	}
+
+	keys = keys[:0]
+	for k := range c.Families {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		var m []byte
+		m = protowire.AppendTag(m, 1, protowire.BytesType)
+		m = protowire.AppendString(m, k)
+		m = protowire.AppendTag(m, 2, protowire.BytesType)
+		m = protowire.AppendString(m, string(c.Families[k]))
+		b = appendMessage(b, 23, m)
+	}
+
+	keys = keys[:0]
+	for k := range c.Regions {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		var m []byte
+		m = protowire.AppendTag(m, 1, protowire.BytesType)
+		m = protowire.AppendString(m, k)
+		m = protowire.AppendTag(m, 2, protowire.BytesType)
+		m = protowire.AppendString(m, string(c.Regions[k]))
+		b = appendMessage(b, 24, m)
+	}
+	if c.PausedUntil != nil {
+		b = appendMessage(b, 25, marshalTimestamp(*c.PausedUntil))
+	}
+	if l := c.TCPConnect; l != nil {
+		var m []byte
+		m = appendInt(m, 1, l.Samples)
+		m = appendDouble(m, 2, l.P50Seconds)
+		m = appendDouble(m, 3, l.P95Seconds)
+		m = appendDouble(m, 4, l.P99Seconds)
+		b = appendMessage(b, 26, m)
+	}
+	if c.SnoozedUntil != nil {
+		b = appendMessage(b, 27, marshalTimestamp(*c.SnoozedUntil))
+	}
+
+	return b
+}
+
+// marshalTimestamp encodes t as a google.protobuf.Timestamp.
+func marshalTimestamp(t time.Time) []byte {
+	var b []byte
+	if s := t.Unix(); s != 0 {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(s))
+	}
+	b = appendInt(b, 2, t.Nanosecond())
+	return b
+}
+
+func appendInt(b []byte, num protowire.Number, v int) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(int32(v)))
+}
+
+func appendBool(b []byte, num protowire.Number, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendMessage(b []byte, num protowire.Number, m []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m)
+}
@@ -0,0 +1,54 @@
+package healthapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/proberbus"
+	"health-api/foundation/web"
+)
+
+// Recheck handles POST /api/v1/health/{target}/check requests. A target
+// run by the built-in prober is probed right away; every target is then
+// read fresh from the backends instead of the last sync.
+func (a *App) Recheck(ctx context.Context, r *http.Request) web.Encoder {
+	target := web.Param(r, "target")
+	if target == "" {
+		return errs.Newf(errs.InvalidArgument, "target parameter required")
+	}
+
+	// The probe below already counts as a re-check, so a target outside the
+	// caller's namespaces is turned away first.
+	if err := a.healthBus.CheckAccess(ctx, target); err != nil {
+		return errs.New(errs.NotFound, err)
+	}
+
+	if a.proberBus != nil {
+		if _, err := a.proberBus.RunNow(ctx, target); err != nil {
+			switch {
+			case errors.Is(err, proberbus.ErrNotFound):
+				// Not a prober check; the backends are queried below.
+			case errors.Is(err, proberbus.ErrRunning):
+				return errs.Newf(errs.Aborted, "check of %s already running, try again shortly", target)
+			default:
+				return errs.Newf(errs.Internal, "run check: %w", err)
+			}
+		}
+	}
+
+	check, err := a.healthBus.Recheck(ctx, target)
+	if err != nil {
+		if errors.Is(err, healthbus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "health check not found: %w", err)
+		}
+		return errs.Newf(errs.Unavailable, "recheck: %w", err)
+	}
+
+	checks := []healthbus.HealthCheck{check}
+	a.applyDeploys(ctx, checks)
+
+	return web.JSONResponse{Data: checks[0]}
+}
@@ -2,31 +2,84 @@ package healthapp
 
 import (
 	"net/http"
+	"time"
 
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/annotationbus"
 	"health-api/business/domain/healthbus"
+	"health-api/business/domain/historybus"
+	"health-api/business/domain/oncallbus"
+	"health-api/business/domain/proberbus"
 	"health-api/foundation/logger"
 	"health-api/foundation/web"
 )
 
 // Config contains dependencies needed to construct handlers.
 type Config struct {
-	Log       *logger.Logger
-	HealthBus *healthbus.Business
+	Log              *logger.Logger
+	HealthBus        *healthbus.Business
+	HistoryBus       *historybus.Business
+	OnCallBus        *oncallbus.Business
+	AnnotationBus    *annotationbus.Business
+	ProberBus        *proberbus.Business
+	ReadinessTimeout time.Duration
+	QueryTimeout     time.Duration
+	Auth             *auth.Auth
+
+	// Deprecation is announced on the v1 routes that have a v2 successor.
+	Deprecation mid.Deprecation
 }
 
 // Routes registers all health check routes.
 func Routes(app *web.App, cfg Config) {
-	const version = "/api/v1"
+	const (
+		version   = "/api/v1"
+		versionV2 = "/api/v2"
+	)
+
+	api := NewApp(cfg.Log, cfg.HealthBus, cfg.HistoryBus, cfg.OnCallBus, cfg.AnnotationBus, cfg.ProberBus, cfg.ReadinessTimeout)
+
+	// Health check endpoints (with full middleware). Dashboards poll these
+	// frequently so they support If-None-Match.
+	v1 := app.Group(version, mid.Timeout(cfg.QueryTimeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer), mid.ETag())
+
+	v1.HandlerFunc(http.MethodGet, "/health/changes", api.QueryChanges)
+	v1.HandlerFunc(http.MethodGet, "/summary", api.QuerySummary)
+
+	deprecation := cfg.Deprecation
+	deprecation.From, deprecation.To = version+"/", versionV2+"/"
+	v1.Use(mid.Deprecated(deprecation))
+
+	v1.HandlerFunc(http.MethodGet, "/health", api.QueryHealthChecks)
+	v1.HandlerFunc(http.MethodGet, "/health/{target}", api.QueryHealthCheckByTarget)
+	v1.HandlerFunc(http.MethodGet, "/alerts", api.QueryAlerts)
 
-	api := NewApp(cfg.Log, cfg.HealthBus)
+	// The same endpoints in the v2 envelope.
+	v2 := app.Group(versionV2, mid.Timeout(cfg.QueryTimeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer), mid.ETag())
 
-	// Health check endpoints (with full middleware)
-	app.HandlerFunc(http.MethodGet, version, "/health", api.QueryHealthChecks)
-	app.HandlerFunc(http.MethodGet, version, "/health/{target}", api.QueryHealthCheckByTarget)
-	app.HandlerFunc(http.MethodGet, version, "/alerts", api.QueryAlerts)
+	v2.HandlerFunc(http.MethodGet, "/health", api.QueryHealthChecksV2)
+	v2.HandlerFunc(http.MethodGet, "/health/{target}", api.QueryHealthCheckByTargetV2)
+	v2.HandlerFunc(http.MethodGet, "/alerts", api.QueryAlertsV2)
+
+	// A re-check costs a probe or backend round-trip, so viewers can't
+	// trigger one.
+	operator := app.Group(version, mid.Timeout(cfg.QueryTimeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleOperator))
+
+	operator.HandlerFunc(http.MethodPost, "/health/{target}/check", api.Recheck)
+
+	// Synthetic failures rehearse alert routing and dashboards, so only
+	// admins may start them.
+	admin := app.Group(version, mid.Timeout(cfg.QueryTimeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleAdmin))
+
+	admin.HandlerFunc(http.MethodPost, "/debug/inject", api.Inject)
+	admin.HandlerFunc(http.MethodGet, "/debug/inject", api.QueryInjections)
+	admin.HandlerFunc(http.MethodDelete, "/debug/inject/{target}", api.StopInjection)
 
 	// Liveness and readiness probes (no middleware except CORS)
-	app.HandlerFuncNoMid(http.MethodGet, "", "/liveness", api.Liveness)
-	app.HandlerFuncNoMid(http.MethodGet, "", "/readiness", api.Readiness)
-	app.HandlerFuncNoMid(http.MethodGet, "", "/healthz", api.Liveness) // Legacy endpoint
+	probes := app.Group("")
+
+	probes.HandlerFuncNoMid(http.MethodGet, "/liveness", api.Liveness)
+	probes.HandlerFuncNoMid(http.MethodGet, "/readiness", api.Readiness)
+	probes.HandlerFuncNoMid(http.MethodGet, "/healthz", api.Liveness) // Legacy endpoint
 }
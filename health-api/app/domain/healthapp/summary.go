@@ -0,0 +1,72 @@
+package healthapp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/foundation/web"
+)
+
+// QuerySummary handles GET /api/v1/summary requests. It returns the current
+// status counts and, for each window, the counts of the same targets as of
+// that long ago, rebuilt from the status history.
+func (a *App) QuerySummary(ctx context.Context, r *http.Request) web.Encoder {
+	windows, err := parseWindows(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	health, err := a.healthBus.QueryHealthChecks(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query health checks: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	summary := Summary{
+		At:      now,
+		Trends:  make([]Trend, 0, len(windows)),
+		Partial: health.Partial,
+		Errors:  health.Errors,
+	}
+	for _, check := range health.Checks {
+		summary.Counts.add(check.Status)
+	}
+
+	for _, window := range windows {
+		at := now.Add(-window.duration)
+
+		statuses, err := a.historyBus.StatusesAt(ctx, at)
+		if err != nil {
+			return errs.Newf(errs.Internal, "query summary: %w", err)
+		}
+
+		// Only the targets still listed are counted so removed targets
+		// don't show up as a change. Targets added since are left out of
+		// the past counts.
+		var past Counts
+		for _, check := range health.Checks {
+			if status, ok := statuses[check.Target]; ok {
+				past.add(status)
+			}
+		}
+
+		summary.Trends = append(summary.Trends, Trend{
+			Window: window.name,
+			At:     at,
+			Counts: past,
+			Delta:  summary.Counts.sub(past),
+		})
+	}
+
+	a.setSnapshotAge(ctx)
+
+	return web.JSONResponse{Data: summary, StatusCode: partialStatus(summary.Partial)}
+}
@@ -0,0 +1,99 @@
+package healthapp
+
+import (
+	"context"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/web"
+)
+
+// The v2 handlers serve the same data as v1 in the versioned envelope:
+// lists in data with their counts in meta, and a self link.
+
+// QueryHealthChecksV2 handles GET /api/v2/health requests.
+func (a *App) QueryHealthChecksV2(ctx context.Context, r *http.Request) web.Encoder {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	summary, err := a.healthBus.QueryHealthChecks(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query health checks: %w", err)
+	}
+
+	a.applyDeploys(ctx, summary.Checks)
+	a.setSnapshotAge(ctx)
+
+	if format := web.Format(r); format != web.FormatJSON {
+		return export(format, checkHeader, checkRows(summary.Checks))
+	}
+
+	return web.Envelope{
+		Data:       summary.Checks,
+		Meta:       toHealthMeta(summary),
+		Links:      selfLink(r),
+		StatusCode: partialStatus(summary.Partial),
+	}
+}
+
+// QueryHealthCheckByTargetV2 handles GET /api/v2/health/{target} requests.
+func (a *App) QueryHealthCheckByTargetV2(ctx context.Context, r *http.Request) web.Encoder {
+	target := web.Param(r, "target")
+	if target == "" {
+		return errs.Newf(errs.InvalidArgument, "target parameter required")
+	}
+
+	check, err := a.healthBus.QueryHealthCheckByTarget(ctx, target)
+	if err != nil {
+		return errs.Newf(errs.NotFound, "health check not found: %w", err)
+	}
+
+	checks := []healthbus.HealthCheck{check}
+	a.applyDeploys(ctx, checks)
+	a.setSnapshotAge(ctx)
+
+	return web.Envelope{
+		Data:  checks[0],
+		Links: selfLink(r),
+	}
+}
+
+// QueryAlertsV2 handles GET /api/v2/alerts requests. With group_by the
+// data holds the groups instead of the alerts.
+func (a *App) QueryAlertsV2(ctx context.Context, r *http.Request) web.Encoder {
+	filter, err := parseAlertFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	summary, err := a.healthBus.QueryAlerts(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query alerts: %w", err)
+	}
+
+	a.applyOnCall(ctx, summary)
+	a.setSnapshotAge(ctx)
+
+	if format := web.Format(r); format != web.FormatJSON {
+		return export(format, alertHeader, alertRows(summary.Alerts))
+	}
+
+	var data any = summary.Alerts
+	if len(filter.GroupBy) > 0 {
+		data = summary.Groups
+	}
+
+	return web.Envelope{
+		Data:       data,
+		Meta:       toAlertMeta(summary),
+		Links:      selfLink(r),
+		StatusCode: partialStatus(summary.Partial),
+	}
+}
+
+func selfLink(r *http.Request) web.Links {
+	return web.Links{"self": r.URL.RequestURI()}
+}
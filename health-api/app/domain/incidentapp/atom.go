@@ -0,0 +1,106 @@
+package incidentapp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+
+	"health-api/business/domain/incidentbus"
+)
+
+// feedLimit caps the number of entries rendered in the feed.
+const feedLimit = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Summary atomSummary `xml:"summary"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomSummary struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// Encode implements the web.Encoder interface.
+func (f atomFeed) Encode() ([]byte, string, error) {
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal atom: %w", err)
+	}
+	return append([]byte(xml.Header), data...), "application/atom+xml; charset=utf-8", nil
+}
+
+// toAtomFeed renders incidents as a feed with one entry per outage and one
+// per recovery, newest first.
+func toAtomFeed(selfURL string, incs []incidentbus.Incident) atomFeed {
+	var entries []atomEntry
+
+	for _, inc := range incs {
+		entries = append(entries, atomEntry{
+			ID:      fmt.Sprintf("urn:health-api:incident:%s:opened", inc.ID),
+			Title:   fmt.Sprintf("%s is down", inc.Target),
+			Updated: inc.StartedAt.Format(time.RFC3339),
+			Author:  atomAuthor{Name: "health-api"},
+			Summary: atomSummary{
+				Type: "text",
+				Text: fmt.Sprintf("Incident %s opened for %s at %s.", inc.ID, inc.Target, inc.StartedAt.Format(time.RFC1123)),
+			},
+		})
+
+		if inc.EndedAt != nil {
+			entries = append(entries, atomEntry{
+				ID:      fmt.Sprintf("urn:health-api:incident:%s:resolved", inc.ID),
+				Title:   fmt.Sprintf("%s recovered", inc.Target),
+				Updated: inc.EndedAt.Format(time.RFC3339),
+				Author:  atomAuthor{Name: "health-api"},
+				Summary: atomSummary{
+					Type: "text",
+					Text: fmt.Sprintf("%s recovered after %s.", inc.Target, (time.Duration(inc.DurationSeconds) * time.Second).String()),
+				},
+			})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Updated > entries[j].Updated
+	})
+
+	if len(entries) > feedLimit {
+		entries = entries[:feedLimit]
+	}
+
+	updated := time.Now().UTC().Format(time.RFC3339)
+	if len(entries) > 0 {
+		updated = entries[0].Updated
+	}
+
+	return atomFeed{
+		ID:      "urn:health-api:incidents",
+		Title:   "Health API incidents",
+		Updated: updated,
+		Link:    atomLink{Href: selfURL, Rel: "self"},
+		Entries: entries,
+	}
+}
@@ -0,0 +1,46 @@
+package incidentapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/incidentbus"
+)
+
+func parseFilter(r *http.Request) (incidentbus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter incidentbus.QueryFilter
+	fields := make(map[string]string)
+
+	if target := values.Get("target"); target != "" {
+		filter.Target = &target
+	}
+
+	if startDate := values.Get("start_date"); startDate != "" {
+		t, err := time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			fields["start_date"] = "must be an RFC 3339 timestamp"
+		}
+		filter.StartDate = &t
+	}
+
+	if endDate := values.Get("end_date"); endDate != "" {
+		t, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			fields["end_date"] = "must be an RFC 3339 timestamp"
+		}
+		filter.EndDate = &t
+	}
+
+	if len(fields) == 0 && filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		fields["end_date"] = "must not be before start_date"
+	}
+
+	if len(fields) > 0 {
+		return incidentbus.QueryFilter{}, errs.FieldErrors(fields)
+	}
+
+	return filter, nil
+}
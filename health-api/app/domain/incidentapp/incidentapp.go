@@ -0,0 +1,83 @@
+// Package incidentapp provides HTTP handlers for incident timelines.
+package incidentapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/incidentbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles incident HTTP requests.
+type App struct {
+	log         *logger.Logger
+	incidentBus *incidentbus.Business
+}
+
+// NewApp constructs a new incident app.
+func NewApp(log *logger.Logger, incidentBus *incidentbus.Business) *App {
+	return &App{
+		log:         log,
+		incidentBus: incidentBus,
+	}
+}
+
+// Query handles GET /api/v1/incidents requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	incs, err := a.incidentBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if incs == nil {
+		incs = []incidentbus.Incident{}
+	}
+
+	return web.JSONResponse{Data: incs}
+}
+
+// Feed handles GET /api/v1/incidents/feed.atom requests.
+func (a *App) Feed(ctx context.Context, r *http.Request) web.Encoder {
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	incs, err := a.incidentBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	selfURL := fmt.Sprintf("%s://%s%s", scheme, r.Host, r.URL.RequestURI())
+
+	return toAtomFeed(selfURL, incs)
+}
+
+// QueryByID handles GET /api/v1/incidents/{id} requests.
+func (a *App) QueryByID(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	inc, err := a.incidentBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, incidentbus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "incident %s not found", id)
+		}
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	return web.JSONResponse{Data: inc}
+}
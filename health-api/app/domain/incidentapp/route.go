@@ -0,0 +1,32 @@
+package incidentapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/incidentbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log         *logger.Logger
+	IncidentBus *incidentbus.Business
+	Timeout     time.Duration
+	Auth        *auth.Auth
+}
+
+// Routes registers all incident routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.IncidentBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer))
+
+	v1.HandlerFunc(http.MethodGet, "/incidents", api.Query)
+	v1.HandlerFunc(http.MethodGet, "/incidents/feed.atom", api.Feed)
+	v1.HandlerFunc(http.MethodGet, "/incidents/{id}", api.QueryByID)
+}
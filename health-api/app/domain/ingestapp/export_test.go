@@ -0,0 +1,28 @@
+package ingestapp
+
+import "health-api/business/domain/ingestbus"
+
+// Set of limits exposed to the tests.
+const (
+	MaxClockSkew   = maxClockSkew
+	MaxWriteSeries = maxWriteSeries
+)
+
+// Verify exposes verify so tests can check signatures without a handler.
+var Verify = verify
+
+// Sign exposes sign so tests can sign reports as agents do.
+var Sign = sign
+
+// MQTTResult exposes mqttResult so tests can map payloads directly.
+var MQTTResult = mqttResult
+
+// DecodeReports decodes a remote-write request into the reports Receive
+// would ingest.
+func DecodeReports(b []byte) ([]ingestbus.Report, error) {
+	series, err := decodeWriteRequest(b)
+	if err != nil {
+		return nil, err
+	}
+	return toReports(series), nil
+}
@@ -0,0 +1,135 @@
+// Package ingestapp provides HTTP handlers for agents pushing health
+// results.
+package ingestapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/ingestbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/validate"
+	"health-api/foundation/web"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+// maxReportSize bounds the size of a report body.
+const maxReportSize = 1 << 20
+
+// maxWriteRequestSize bounds the size of a remote-write body, compressed
+// and decompressed.
+const maxWriteRequestSize = 32 << 20
+
+// maxWriteSeries bounds the probe_* series of a remote-write request. An
+// agent with more should shard its remote write across several requests.
+const maxWriteSeries = 10_000
+
+// App handles ingest HTTP requests.
+type App struct {
+	log       *logger.Logger
+	ingestBus *ingestbus.Business
+	secret    string
+}
+
+// NewApp constructs a new ingest app. Reports must be signed with secret.
+func NewApp(log *logger.Logger, ingestBus *ingestbus.Business, secret string) *App {
+	return &App{
+		log:       log,
+		ingestBus: ingestBus,
+		secret:    secret,
+	}
+}
+
+// Ingest handles POST /api/v1/ingest requests.
+func (a *App) Ingest(ctx context.Context, r *http.Request) web.Encoder {
+	// The bus also runs for the MQTT bridge alone; pushes need the secret.
+	if a.ingestBus == nil || a.secret == "" {
+		return errs.Newf(errs.FailedPrecondition, "ingestion is not configured")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReportSize))
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "reading body: %w", err)
+	}
+
+	if err := verify(r, body, a.secret, time.Now()); err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	var rpt ingestbus.Report
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rpt); err != nil {
+		return errs.Newf(errs.InvalidArgument, "decode json: %w", err)
+	}
+
+	if err := validate.Check(rpt); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	results, err := a.ingestBus.Ingest(ctx, rpt)
+	if err != nil {
+		return errs.Newf(errs.Internal, "ingest: %w", err)
+	}
+
+	return web.JSONResponse{Data: results, StatusCode: http.StatusAccepted}
+}
+
+// Receive handles POST /api/v1/receive requests, the Prometheus remote-write
+// 1.0 protocol. Senders authenticate with the ingest secret as bearer
+// token. Only probe_* series are read: each probe_success series becomes a
+// result for its instance, reported by the agent in its agent or job label.
+func (a *App) Receive(ctx context.Context, r *http.Request) web.Encoder {
+	if a.ingestBus == nil || a.secret == "" {
+		return errs.Newf(errs.FailedPrecondition, "ingestion is not configured")
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(a.secret)) != 1 {
+		return errs.Newf(errs.Unauthenticated, "invalid or missing bearer token")
+	}
+
+	if enc := r.Header.Get("Content-Encoding"); enc != "snappy" {
+		return errs.Newf(errs.InvalidArgument, "unsupported content encoding %q, want snappy", enc)
+	}
+
+	if ct := r.Header.Get("Content-Type"); ct != "" && ct != "application/x-protobuf" {
+		return errs.Newf(errs.InvalidArgument, "unsupported content type %q, only remote-write 1.0 is accepted", ct)
+	}
+
+	compressed, err := io.ReadAll(io.LimitReader(r.Body, maxWriteRequestSize+1))
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "reading body: %w", err)
+	}
+	if len(compressed) > maxWriteRequestSize {
+		return errs.Newf(errs.InvalidArgument, "body exceeds %d bytes", maxWriteRequestSize)
+	}
+
+	if n, err := snappy.DecodedLen(compressed); err != nil || n > maxWriteRequestSize {
+		return errs.Newf(errs.InvalidArgument, "invalid snappy body or decoded size above %d bytes", maxWriteRequestSize)
+	}
+
+	body, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "decompressing body: %w", err)
+	}
+
+	series, err := decodeWriteRequest(body)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "decode write request: %w", err)
+	}
+
+	if _, err := a.ingestBus.IngestMany(ctx, toReports(series)); err != nil {
+		return errs.Newf(errs.Internal, "ingest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package ingestapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/business/domain/ingestbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/mqtt"
+)
+
+// mqttAgent is the agent MQTT results are ingested as.
+const mqttAgent = "mqtt"
+
+// Set of reconnect delays of the MQTT bridge.
+const (
+	mqttMinBackoff = time.Second
+	mqttMaxBackoff = time.Minute
+)
+
+// MQTTRule maps the topics matching Filter to a target. Target may refer to
+// the levels the filter's wildcards matched as {1}, {2} and so on, e.g. the
+// filter plant/+/+/health with target plant-{1}-{2}.
+type MQTTRule struct {
+	Filter string
+	Target string
+}
+
+// target returns the target of topic under the rule.
+func (r MQTTRule) target(topic string) (string, bool) {
+	levels, ok := mqtt.Match(r.Filter, topic)
+	if !ok {
+		return "", false
+	}
+
+	target := r.Target
+	for i, level := range levels {
+		target = strings.ReplaceAll(target, "{"+strconv.Itoa(i+1)+"}", level)
+	}
+
+	return target, true
+}
+
+// ServeMQTT subscribes to the filters of the rules and ingests every message
+// as the result of the target of the first matching rule, until ctx is
+// canceled. Lost connections are retried with backoff.
+func ServeMQTT(ctx context.Context, log *logger.Logger, cfg mqtt.Config, rules []MQTTRule, ingestBus *ingestbus.Business) {
+	filters := make([]string, len(rules))
+	for i, r := range rules {
+		filters[i] = r.Filter
+	}
+
+	handle := func(msg mqtt.Message) {
+		for _, r := range rules {
+			target, ok := r.target(msg.Topic)
+			if !ok {
+				continue
+			}
+
+			rpt := ingestbus.Report{
+				Agent:   mqttAgent,
+				Results: []ingestbus.NewResult{mqttResult(target, msg.Payload)},
+			}
+			if _, err := ingestBus.Ingest(ctx, rpt); err != nil {
+				log.Error(ctx, "mqtt", "status", "ingesting message failed", "topic", msg.Topic, "error", err)
+			}
+			return
+		}
+	}
+
+	backoff := mqttMinBackoff
+	for {
+		connected := time.Now()
+		err := mqtt.Subscribe(ctx, cfg, filters, handle)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that held for a while starts the backoff over.
+		if time.Since(connected) > mqttMaxBackoff {
+			backoff = mqttMinBackoff
+		}
+
+		log.Warn(ctx, "mqtt", "status", "connection lost, retrying", "error", err, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, mqttMaxBackoff)
+	}
+}
+
+// mqttResult maps a message payload to a result. A JSON object may carry
+// success, status, duration_seconds and error. Other payloads are read as
+// a status word or number; an empty payload is a plain heartbeat and
+// counts as success.
+func mqttResult(target string, payload []byte) ingestbus.NewResult {
+	res := ingestbus.NewResult{
+		Target: target,
+		Probe:  mqttAgent,
+	}
+
+	payload = bytes.TrimSpace(payload)
+
+	var msg struct {
+		Success         *bool   `json:"success"`
+		Status          string  `json:"status"`
+		DurationSeconds float64 `json:"duration_seconds"`
+		Error           string  `json:"error"`
+	}
+	if len(payload) > 0 && payload[0] == '{' && json.Unmarshal(payload, &msg) == nil {
+		res.DurationSeconds = max(msg.DurationSeconds, 0)
+		res.Error = msg.Error
+
+		switch {
+		case msg.Success != nil:
+			res.Success = *msg.Success
+		case msg.Status != "":
+			res.Success = healthyWord(msg.Status)
+		default:
+			res.Success = msg.Error == ""
+		}
+
+		if !res.Success && res.Error == "" && msg.Status != "" {
+			res.Error = "reported " + msg.Status
+		}
+
+		return res
+	}
+
+	res.Success = len(payload) == 0 || healthyWord(string(payload))
+	if !res.Success {
+		res.Error = "reported " + string(payload)
+	}
+
+	return res
+}
+
+// healthyWord reports whether a device's status word means healthy.
+func healthyWord(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "ok", "up", "true", "healthy", "online":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package ingestapp_test
+
+import (
+	"testing"
+
+	"health-api/app/domain/ingestapp"
+)
+
+func Test_MQTTResult(t *testing.T) {
+	tests := []struct {
+		payload  string
+		success  bool
+		err      string
+		duration float64
+	}{
+		{payload: "", success: true},
+		{payload: "ok", success: true},
+		{payload: " Online\n", success: true},
+		{payload: "1", success: true},
+		{payload: "0", success: false, err: "reported 0"},
+		{payload: "offline", success: false, err: "reported offline"},
+		{payload: `{"success":true,"duration_seconds":0.4}`, success: true, duration: 0.4},
+		{payload: `{"success":false,"error":"pump stalled"}`, success: false, err: "pump stalled"},
+		{payload: `{"status":"healthy"}`, success: true},
+		{payload: `{"status":"degraded"}`, success: false, err: "reported degraded"},
+		{payload: `{"error":"sensor timeout"}`, success: false, err: "sensor timeout"},
+		{payload: `{"duration_seconds":-3}`, success: true},
+		{payload: `{broken`, success: false, err: "reported {broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			res := ingestapp.MQTTResult("plant-1-pump", []byte(tt.payload))
+
+			if res.Target != "plant-1-pump" || res.Probe != "mqtt" {
+				t.Errorf("Should report the target as probed over mqtt, got %q %q", res.Target, res.Probe)
+			}
+			if res.Success != tt.success || res.Error != tt.err || res.DurationSeconds != tt.duration {
+				t.Errorf("Should map to %t %q %v, got %t %q %v", tt.success, tt.err, tt.duration, res.Success, res.Error, res.DurationSeconds)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package ingestapp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"health-api/business/domain/ingestbus"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// defaultAgent names the agent of series that carry neither an agent nor a
+// job label.
+const defaultAgent = "remote-write"
+
+// series is one decoded time series of a remote-write request, reduced to
+// its newest sample.
+type series struct {
+	labels    map[string]string
+	value     float64
+	timestamp int64
+}
+
+// decodeWriteRequest decodes the prometheus.WriteRequest protobuf of the
+// remote-write 1.0 protocol. Only the series of metrics named probe_* are
+// kept, at most maxWriteSeries of them; metadata and the other series are
+// skipped.
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+func decodeWriteRequest(b []byte) ([]series, error) {
+	var out []series
+
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		if num != 1 || typ != protowire.BytesType {
+			return nil
+		}
+
+		s, err := decodeTimeSeries(v)
+		if err != nil {
+			return fmt.Errorf("timeseries: %w", err)
+		}
+
+		if strings.HasPrefix(s.labels["__name__"], "probe_") && s.timestamp != math.MinInt64 {
+			if len(out) == maxWriteSeries {
+				return fmt.Errorf("more than %d probe series", maxWriteSeries)
+			}
+			out = append(out, s)
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+func decodeTimeSeries(b []byte) (series, error) {
+	s := series{
+		labels:    make(map[string]string),
+		timestamp: math.MinInt64,
+	}
+
+	err := walk(b, func(num protowire.Number, typ protowire.Type, v []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+
+		switch num {
+		case 1:
+			var name, value string
+			err := walk(v, func(num protowire.Number, typ protowire.Type, v []byte) error {
+				switch {
+				case num == 1 && typ == protowire.BytesType:
+					name = string(v)
+				case num == 2 && typ == protowire.BytesType:
+					value = string(v)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("label: %w", err)
+			}
+			s.labels[name] = value
+
+		case 2:
+			var value float64
+			var ts int64
+			err := walk(v, func(num protowire.Number, typ protowire.Type, v []byte) error {
+				switch {
+				case num == 1 && typ == protowire.Fixed64Type:
+					bits, _ := protowire.ConsumeFixed64(v)
+					value = math.Float64frombits(bits)
+				case num == 2 && typ == protowire.VarintType:
+					u, _ := protowire.ConsumeVarint(v)
+					ts = int64(u)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("sample: %w", err)
+			}
+			if ts >= s.timestamp {
+				s.value, s.timestamp = value, ts
+			}
+		}
+
+		return nil
+	})
+
+	return s, err
+}
+
+// walk calls fn for every field of the protobuf message in b. Scalar
+// fields are passed in their wire encoding, length-delimited fields
+// without their length prefix.
+func walk(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var v []byte
+		switch typ {
+		case protowire.BytesType:
+			var m int
+			if v, m = protowire.ConsumeBytes(b); m < 0 {
+				return protowire.ParseError(m)
+			}
+			n = m
+		default:
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return protowire.ParseError(n)
+			}
+			v = b[:n]
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// toReports groups the series into one report per agent, with a result per
+// probed instance. The agent is taken from the agent label, then the job,
+// and its region from the region label, then the cluster.
+// probe_success decides the result; probe_duration_seconds and
+// probe_http_status_code add to it when present.
+func toReports(all []series) []ingestbus.Report {
+	type key struct{ agent, target string }
+
+	results := make(map[key]*ingestbus.NewResult)
+	regions := make(map[string]string)
+	var keys []key
+
+	for _, s := range all {
+		// Stale markers are NaN; the agent's TTL covers targets that are
+		// no longer probed.
+		if s.labels["__name__"] != "probe_success" || math.IsNaN(s.value) {
+			continue
+		}
+
+		k := key{agentOf(s.labels), s.labels["instance"]}
+		if k.target == "" {
+			continue
+		}
+
+		if _, ok := results[k]; !ok {
+			keys = append(keys, k)
+		}
+		if r := regionOf(s.labels); r != "" {
+			regions[k.agent] = r
+		}
+		results[k] = &ingestbus.NewResult{
+			Target:  k.target,
+			Success: s.value == 1,
+			Probe:   s.labels["probe"],
+		}
+	}
+
+	for _, s := range all {
+		res, ok := results[key{agentOf(s.labels), s.labels["instance"]}]
+		if !ok || math.IsNaN(s.value) {
+			continue
+		}
+
+		switch s.labels["__name__"] {
+		case "probe_duration_seconds":
+			res.DurationSeconds = max(s.value, 0)
+		case "probe_http_status_code":
+			res.HTTPStatusCode = int(s.value)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].agent != keys[j].agent {
+			return keys[i].agent < keys[j].agent
+		}
+		return keys[i].target < keys[j].target
+	})
+
+	var reports []ingestbus.Report
+	for _, k := range keys {
+		if len(reports) == 0 || reports[len(reports)-1].Agent != k.agent {
+			reports = append(reports, ingestbus.Report{Agent: k.agent, Region: regions[k.agent]})
+		}
+		rpt := &reports[len(reports)-1]
+		rpt.Results = append(rpt.Results, *results[k])
+	}
+
+	return reports
+}
+
+func regionOf(labels map[string]string) string {
+	if r := labels["region"]; r != "" {
+		return r
+	}
+	return labels["cluster"]
+}
+
+func agentOf(labels map[string]string) string {
+	switch {
+	case labels["agent"] != "":
+		return labels["agent"]
+	case labels["job"] != "":
+		return labels["job"]
+	}
+	return defaultAgent
+}
@@ -0,0 +1,203 @@
+package ingestapp_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"health-api/app/domain/ingestapp"
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/ingestbus"
+	"health-api/business/domain/ingestbus/stores/ingestdb"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// sample is a single sample of a series at ts, in unix milliseconds.
+type sample struct {
+	value float64
+	ts    int64
+}
+
+// timeSeries encodes a prometheus.TimeSeries.
+func timeSeries(labels map[string]string, samples ...sample) []byte {
+	var ts []byte
+	for name, value := range labels {
+		var l []byte
+		l = protowire.AppendTag(l, 1, protowire.BytesType)
+		l = protowire.AppendString(l, name)
+		l = protowire.AppendTag(l, 2, protowire.BytesType)
+		l = protowire.AppendString(l, value)
+
+		ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, l)
+	}
+
+	for _, s := range samples {
+		var b []byte
+		b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(s.value))
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(s.ts))
+
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, b)
+	}
+
+	return ts
+}
+
+// writeRequest encodes a prometheus.WriteRequest of the series.
+func writeRequest(series ...[]byte) []byte {
+	var req []byte
+	for _, ts := range series {
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return req
+}
+
+func Test_DecodeWriteRequest(t *testing.T) {
+	now := time.Now().UnixMilli()
+
+	// Metadata (field 3) comes along and is skipped.
+	metadata := protowire.AppendTag(nil, 3, protowire.BytesType)
+	metadata = protowire.AppendBytes(metadata, []byte("ignored"))
+
+	req := writeRequest(
+		timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://shop.example.com", "job": "blackbox", "cluster": "eu-1"},
+			sample{0, now - 30_000}, sample{1, now}, sample{0, now - 15_000}),
+		timeSeries(map[string]string{"__name__": "probe_duration_seconds", "instance": "https://shop.example.com", "job": "blackbox"},
+			sample{0.25, now}),
+		timeSeries(map[string]string{"__name__": "probe_http_status_code", "instance": "https://shop.example.com", "job": "blackbox"},
+			sample{200, now}),
+		timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://api.example.com", "agent": "edge-berlin-1", "job": "blackbox", "region": "eu"},
+			sample{0, now}),
+		timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://gone.example.com"},
+			sample{math.NaN(), now}),
+		timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://legacy.example.com"}),
+		timeSeries(map[string]string{"__name__": "node_load1", "instance": "https://shop.example.com", "job": "blackbox"},
+			sample{2, now}),
+	)
+	req = append(req, metadata...)
+
+	reports, err := ingestapp.DecodeReports(req)
+	if err != nil {
+		t.Fatalf("Should be able to decode the request: %s", err)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("Should report per agent and skip stale and sampleless series, got %+v", reports)
+	}
+
+	blackbox, edge := reports[0], reports[1]
+
+	if blackbox.Agent != "blackbox" || blackbox.Region != "eu-1" || len(blackbox.Results) != 1 {
+		t.Fatalf("Should take the agent from the job and the region from the cluster, got %+v", blackbox)
+	}
+	shop := blackbox.Results[0]
+	if !shop.Success || shop.DurationSeconds != 0.25 || shop.HTTPStatusCode != 200 {
+		t.Errorf("Should keep the newest sample and add duration and status code, got %+v", shop)
+	}
+
+	if edge.Agent != "edge-berlin-1" || edge.Region != "eu" || len(edge.Results) != 1 || edge.Results[0].Success {
+		t.Errorf("Should prefer the agent and region labels, got %+v", edge)
+	}
+}
+
+func Test_DecodeWriteRequestErrors(t *testing.T) {
+	now := time.Now().UnixMilli()
+
+	t.Run("truncated", func(t *testing.T) {
+		req := writeRequest(timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://shop.example.com"}, sample{1, now}))
+
+		if _, err := ingestapp.DecodeReports(req[:len(req)-3]); err == nil {
+			t.Error("Should reject a truncated request")
+		}
+	})
+
+	t.Run("too many series", func(t *testing.T) {
+		series := make([][]byte, 0, ingestapp.MaxWriteSeries+1)
+		for i := range ingestapp.MaxWriteSeries + 1 {
+			series = append(series, timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://host-" + strconv.Itoa(i)}, sample{1, now}))
+		}
+
+		if _, err := ingestapp.DecodeReports(writeRequest(series...)); err == nil {
+			t.Errorf("Should reject more than %d probe series", ingestapp.MaxWriteSeries)
+		}
+
+		if _, err := ingestapp.DecodeReports(writeRequest(series[:ingestapp.MaxWriteSeries]...)); err != nil {
+			t.Errorf("Should accept %d probe series: %s", ingestapp.MaxWriteSeries, err)
+		}
+	})
+}
+
+func Test_Receive(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+	db, err := jsondb.Open("")
+	if err != nil {
+		t.Fatalf("Should be able to open the db: %s", err)
+	}
+
+	store, err := ingestdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the ingest store: %s", err)
+	}
+	ingestBus := ingestbus.NewBusiness(log, store, 5*time.Minute)
+
+	app := ingestapp.NewApp(log, ingestBus, "secret")
+
+	req := writeRequest(timeSeries(map[string]string{"__name__": "probe_success", "instance": "https://shop.example.com", "job": "blackbox"}, sample{1, time.Now().UnixMilli()}))
+
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		want     errs.ErrCode
+	}{
+		{name: "snappy", body: snappy.Encode(nil, req), encoding: "snappy", want: errs.OK},
+		{name: "uncompressed", body: req, encoding: "", want: errs.InvalidArgument},
+		{name: "corrupt snappy", body: []byte("\x05not snappy"), encoding: "snappy", want: errs.InvalidArgument},
+		{name: "oversized", body: snappy.Encode(nil, make([]byte, 33<<20)), encoding: "snappy", want: errs.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "/api/v1/receive", strings.NewReader(string(tt.body)))
+			if err != nil {
+				t.Fatalf("Should be able to create the request: %s", err)
+			}
+			r.Header.Set("Authorization", "Bearer secret")
+			r.Header.Set("Content-Encoding", tt.encoding)
+
+			resp := app.Receive(context.Background(), r)
+
+			var e *errs.Error
+			if err, ok := resp.(error); ok && errors.As(err, &e) {
+				if e.Code != tt.want {
+					t.Errorf("Should answer %s, got %s: %s", tt.want, e.Code, e.Message)
+				}
+				return
+			}
+
+			if tt.want != errs.OK {
+				t.Errorf("Should answer %s, got %v", tt.want, resp)
+			}
+		})
+	}
+
+	results, err := ingestBus.Query(context.Background())
+	if err != nil || len(results) != 1 || results[0].Target != "https://shop.example.com" {
+		t.Errorf("Should ingest the snappy request only, got %+v %v", results, err)
+	}
+}
@@ -0,0 +1,30 @@
+package ingestapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/ingestbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log       *logger.Logger
+	IngestBus *ingestbus.Business
+	Secret    string
+	Timeout   time.Duration
+}
+
+// Routes registers all ingest routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.IngestBus, cfg.Secret)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout))
+
+	v1.HandlerFunc(http.MethodPost, "/ingest", api.Ingest)
+	v1.HandlerFunc(http.MethodPost, "/receive", api.Receive)
+}
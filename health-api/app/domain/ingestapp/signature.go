@@ -0,0 +1,61 @@
+package ingestapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Set of headers carrying the report signature.
+const (
+	headerSignature = "X-Signature"
+	headerTimestamp = "X-Signature-Timestamp"
+)
+
+// maxClockSkew bounds how old a signed report may be, limiting replays.
+const maxClockSkew = 5 * time.Minute
+
+// verify checks that the body was signed with secret. Agents send the unix
+// time in X-Signature-Timestamp and "sha256=" followed by the hex HMAC-SHA256
+// of "<timestamp>.<body>" in X-Signature.
+func verify(r *http.Request, body []byte, secret string, now time.Time) error {
+	ts := r.Header.Get(headerTimestamp)
+	sig, ok := strings.CutPrefix(r.Header.Get(headerSignature), "sha256=")
+	if ts == "" || !ok {
+		return errors.New("missing signature")
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp: %w", err)
+	}
+
+	if skew := now.Sub(time.Unix(unix, 0)); skew > maxClockSkew || skew < -maxClockSkew {
+		return errors.New("signature timestamp outside the allowed window")
+	}
+
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	if !hmac.Equal(got, sign(secret, ts, body)) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+func sign(secret, ts string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
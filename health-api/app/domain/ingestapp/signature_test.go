@@ -0,0 +1,63 @@
+package ingestapp_test
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"health-api/app/domain/ingestapp"
+)
+
+func Test_Verify(t *testing.T) {
+	const secret = "agent-secret"
+	body := []byte(`{"agent":"edge-berlin-1","results":[]}`)
+	now := time.Unix(1_770_000_000, 0)
+
+	signed := func(secret string, at time.Time, body []byte) http.Header {
+		ts := strconv.FormatInt(at.Unix(), 10)
+		return http.Header{
+			"X-Signature-Timestamp": {ts},
+			"X-Signature":           {"sha256=" + hex.EncodeToString(ingestapp.Sign(secret, ts, body))},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{name: "valid", header: signed(secret, now, body), body: body, ok: true},
+		{name: "skew past", header: signed(secret, now.Add(-ingestapp.MaxClockSkew), body), body: body, ok: true},
+		{name: "skew ahead", header: signed(secret, now.Add(ingestapp.MaxClockSkew), body), body: body, ok: true},
+		{name: "too old", header: signed(secret, now.Add(-ingestapp.MaxClockSkew-time.Second), body), body: body},
+		{name: "too far ahead", header: signed(secret, now.Add(ingestapp.MaxClockSkew+time.Second), body), body: body},
+		{name: "wrong secret", header: signed("other-secret", now, body), body: body},
+		{name: "tampered body", header: signed(secret, now, body), body: []byte(`{"agent":"edge-berlin-2","results":[]}`)},
+		{name: "missing signature", header: http.Header{"X-Signature-Timestamp": {strconv.FormatInt(now.Unix(), 10)}}, body: body},
+		{name: "missing timestamp", header: http.Header{"X-Signature": signed(secret, now, body)["X-Signature"]}, body: body},
+		{name: "no sha256 prefix", header: http.Header{"X-Signature-Timestamp": signed(secret, now, body)["X-Signature-Timestamp"], "X-Signature": {"deadbeef"}}, body: body},
+		{name: "bad hex", header: http.Header{"X-Signature-Timestamp": signed(secret, now, body)["X-Signature-Timestamp"], "X-Signature": {"sha256=zz"}}, body: body},
+		{name: "bad timestamp", header: http.Header{"X-Signature-Timestamp": {"yesterday"}, "X-Signature": signed(secret, now, body)["X-Signature"]}, body: body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "/api/v1/ingest", nil)
+			if err != nil {
+				t.Fatalf("Should be able to create the request: %s", err)
+			}
+			r.Header = tt.header
+
+			err = ingestapp.Verify(r, tt.body, secret, now)
+			if tt.ok && err != nil {
+				t.Errorf("Should accept the signature: %s", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("Should reject the signature")
+			}
+		})
+	}
+}
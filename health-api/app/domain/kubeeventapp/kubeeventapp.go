@@ -0,0 +1,61 @@
+// Package kubeeventapp provides HTTP handlers for the Kubernetes Events of
+// targets.
+package kubeeventapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/kubeeventbus"
+	"health-api/business/domain/targetbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles event HTTP requests.
+type App struct {
+	log          *logger.Logger
+	kubeEventBus *kubeeventbus.Business
+}
+
+// NewApp constructs a new event app.
+func NewApp(log *logger.Logger, kubeEventBus *kubeeventbus.Business) *App {
+	return &App{
+		log:          log,
+		kubeEventBus: kubeEventBus,
+	}
+}
+
+// QueryByTarget handles GET /api/v1/health/{target}/events requests.
+func (a *App) QueryByTarget(ctx context.Context, r *http.Request) web.Encoder {
+	if a.kubeEventBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "kubernetes events are not enabled")
+	}
+
+	target := web.Param(r, "target")
+
+	var since *time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return errs.New(errs.InvalidArgument, errs.FieldErrors(map[string]string{"since": "must be an RFC 3339 timestamp"}))
+		}
+		since = &t
+	}
+
+	evts, err := a.kubeEventBus.QueryByTarget(ctx, target, since)
+	if err != nil {
+		switch {
+		case errors.Is(err, targetbus.ErrNotFound):
+			return errs.Newf(errs.NotFound, "target %s not found", target)
+		case errors.Is(err, kubeeventbus.ErrNoNamespace):
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "query events: %w", err)
+	}
+
+	return web.JSONResponse{Data: evts}
+}
@@ -0,0 +1,30 @@
+package kubeeventapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/kubeeventbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log          *logger.Logger
+	KubeEventBus *kubeeventbus.Business
+	Timeout      time.Duration
+	Auth         *auth.Auth
+}
+
+// Routes registers all event routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.KubeEventBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer))
+
+	v1.HandlerFunc(http.MethodGet, "/health/{target}/events", api.QueryByTarget)
+}
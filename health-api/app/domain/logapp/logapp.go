@@ -0,0 +1,60 @@
+// Package logapp provides HTTP handlers for target log lookups.
+package logapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/logbus"
+	"health-api/business/domain/targetbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles log HTTP requests.
+type App struct {
+	log    *logger.Logger
+	logBus *logbus.Business
+}
+
+// NewApp constructs a new log app.
+func NewApp(log *logger.Logger, logBus *logbus.Business) *App {
+	return &App{
+		log:    log,
+		logBus: logBus,
+	}
+}
+
+// QueryByTarget handles GET /api/v1/health/{target}/logs requests.
+func (a *App) QueryByTarget(ctx context.Context, r *http.Request) web.Encoder {
+	if a.logBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "loki is not configured")
+	}
+
+	target := web.Param(r, "target")
+
+	var at *time.Time
+	if v := r.URL.Query().Get("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return errs.New(errs.InvalidArgument, errs.FieldErrors(map[string]string{"at": "must be an RFC 3339 timestamp"}))
+		}
+		at = &t
+	}
+
+	logs, err := a.logBus.QueryByTarget(ctx, target, at)
+	if err != nil {
+		switch {
+		case errors.Is(err, targetbus.ErrNotFound):
+			return errs.Newf(errs.NotFound, "target %s not found", target)
+		case errors.Is(err, logbus.ErrNoSelector):
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "query logs: %w", err)
+	}
+
+	return web.JSONResponse{Data: logs}
+}
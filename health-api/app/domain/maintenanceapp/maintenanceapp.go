@@ -0,0 +1,93 @@
+// Package maintenanceapp provides HTTP handlers for maintenance windows.
+package maintenanceapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles maintenance window HTTP requests.
+type App struct {
+	log            *logger.Logger
+	maintenanceBus *maintenancebus.Business
+}
+
+// NewApp constructs a new maintenance app.
+func NewApp(log *logger.Logger, maintenanceBus *maintenancebus.Business) *App {
+	return &App{
+		log:            log,
+		maintenanceBus: maintenanceBus,
+	}
+}
+
+// Create handles POST /api/v1/maintenance requests.
+func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
+	var nw maintenancebus.NewWindow
+	if err := web.Decode(r, &nw); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := validateWindow(nw); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	w, err := a.maintenanceBus.Create(ctx, nw)
+	if err != nil {
+		if errors.Is(err, tenant.ErrNamespace) {
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "create: %w", err)
+	}
+
+	return web.JSONResponse{Data: w, StatusCode: http.StatusCreated}
+}
+
+// Query handles GET /api/v1/maintenance requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	windows, err := a.maintenanceBus.Query(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if windows == nil {
+		windows = []maintenancebus.Window{}
+	}
+
+	return web.JSONResponse{Data: windows}
+}
+
+// QueryByID handles GET /api/v1/maintenance/{id} requests.
+func (a *App) QueryByID(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	w, err := a.maintenanceBus.QueryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, maintenancebus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "maintenance window %s not found", id)
+		}
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	return web.JSONResponse{Data: w}
+}
+
+// Delete handles DELETE /api/v1/maintenance/{id} requests.
+func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	if err := a.maintenanceBus.Delete(ctx, id); err != nil {
+		if errors.Is(err, maintenancebus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "maintenance window %s not found", id)
+		}
+		return errs.Newf(errs.Internal, "delete: %w", err)
+	}
+
+	return nil
+}
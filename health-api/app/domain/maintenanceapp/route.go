@@ -0,0 +1,35 @@
+package maintenanceapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/maintenancebus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log            *logger.Logger
+	MaintenanceBus *maintenancebus.Business
+	Timeout        time.Duration
+	Auth           *auth.Auth
+}
+
+// Routes registers all maintenance window routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.MaintenanceBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth))
+	viewer := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleViewer))
+	operator := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleOperator))
+
+	viewer.HandlerFunc(http.MethodGet, "/maintenance", api.Query)
+	viewer.HandlerFunc(http.MethodGet, "/maintenance/{id}", api.QueryByID)
+	operator.HandlerFunc(http.MethodPost, "/maintenance", api.Create)
+	operator.HandlerFunc(http.MethodDelete, "/maintenance/{id}", api.Delete)
+}
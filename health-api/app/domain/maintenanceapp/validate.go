@@ -0,0 +1,42 @@
+package maintenanceapp
+
+import (
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/maintenancebus"
+	"health-api/foundation/rrule"
+)
+
+// validateWindow checks the timing of a window beyond what its validate
+// tags express.
+func validateWindow(nw maintenancebus.NewWindow) error {
+	fields := make(map[string]string)
+
+	if !nw.End.After(nw.Start) {
+		fields["end"] = "must be after start"
+	}
+
+	loc, err := time.LoadLocation(nw.Timezone)
+	if err != nil {
+		fields["timezone"] = "must be an IANA time zone such as Europe/Berlin"
+	}
+
+	if nw.RRule != "" {
+		r, err := rrule.Parse(nw.RRule)
+		switch {
+		case err != nil:
+			fields["rrule"] = err.Error()
+		case loc != nil:
+			if _, ok := r.First(nw.Start.In(loc)); !ok {
+				fields["rrule"] = "never occurs from start"
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return errs.FieldErrors(fields)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package probeapp provides HTTP handlers for blackbox exporter probe modules.
+package probeapp
+
+import (
+	"context"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/probebus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles probe HTTP requests.
+type App struct {
+	log      *logger.Logger
+	probeBus *probebus.Business
+}
+
+// NewApp constructs a new probe app.
+func NewApp(log *logger.Logger, probeBus *probebus.Business) *App {
+	return &App{
+		log:      log,
+		probeBus: probeBus,
+	}
+}
+
+// QueryModules handles GET /api/v1/probes/modules requests.
+func (a *App) QueryModules(ctx context.Context, r *http.Request) web.Encoder {
+	if a.probeBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "blackbox exporter is not configured")
+	}
+
+	modules, err := a.probeBus.QueryModules(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query modules: %w", err)
+	}
+
+	return web.JSONResponse{Data: modules}
+}
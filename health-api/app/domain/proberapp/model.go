@@ -0,0 +1,8 @@
+package proberapp
+
+// HeartbeatToken holds the ping token of a heartbeat check.
+type HeartbeatToken struct {
+	Check string `json:"check"`
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
@@ -0,0 +1,95 @@
+// Package proberapp provides HTTP handlers for the results of the built-in
+// prober.
+package proberapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/proberbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles prober HTTP requests.
+type App struct {
+	log       *logger.Logger
+	proberBus *proberbus.Business
+}
+
+// NewApp constructs a new prober app.
+func NewApp(log *logger.Logger, proberBus *proberbus.Business) *App {
+	return &App{
+		log:       log,
+		proberBus: proberBus,
+	}
+}
+
+// Query handles GET /api/v1/probes/checks requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	if a.proberBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "prober is not configured")
+	}
+
+	return web.JSONResponse{Data: a.proberBus.Query(ctx)}
+}
+
+// QueryByName handles GET /api/v1/probes/checks/{check} requests.
+func (a *App) QueryByName(ctx context.Context, r *http.Request) web.Encoder {
+	if a.proberBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "prober is not configured")
+	}
+
+	result, err := a.proberBus.QueryByName(ctx, web.Param(r, "check"))
+	if err != nil {
+		if errors.Is(err, proberbus.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	return web.JSONResponse{Data: result}
+}
+
+// Ping handles POST /api/v1/heartbeat/{token} requests.
+func (a *App) Ping(ctx context.Context, r *http.Request) web.Encoder {
+	if a.proberBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "prober is not configured")
+	}
+
+	result, err := a.proberBus.PingToken(ctx, web.Param(r, "token"))
+	if err != nil {
+		if errors.Is(err, proberbus.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "ping: %w", err)
+	}
+
+	return web.JSONResponse{Data: result}
+}
+
+// QueryToken handles GET /api/v1/probes/checks/{check}/token requests. It
+// returns the token and path a job pings the heartbeat check with.
+func (a *App) QueryToken(ctx context.Context, r *http.Request) web.Encoder {
+	if a.proberBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "prober is not configured")
+	}
+
+	name := web.Param(r, "check")
+
+	token, err := a.proberBus.Token(ctx, name)
+	if err != nil {
+		if errors.Is(err, proberbus.ErrNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "query token: %w", err)
+	}
+
+	return web.JSONResponse{Data: HeartbeatToken{
+		Check: name,
+		Token: token,
+		Path:  "/api/v1/heartbeat/" + token,
+	}}
+}
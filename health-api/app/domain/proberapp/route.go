@@ -0,0 +1,39 @@
+package proberapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/proberbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log       *logger.Logger
+	ProberBus *proberbus.Business
+	Timeout   time.Duration
+	Auth      *auth.Auth
+}
+
+// Routes registers all prober routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.ProberBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout))
+	viewer := v1.Group("", mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer))
+
+	viewer.HandlerFunc(http.MethodGet, "/probes/checks", api.Query)
+	viewer.HandlerFunc(http.MethodGet, "/probes/checks/{check}", api.QueryByName)
+
+	operator := v1.Group("", mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleOperator))
+	operator.HandlerFunc(http.MethodGet, "/probes/checks/{check}/token", api.QueryToken)
+
+	// Heartbeats are pinged by jobs that hold no API credentials, so the
+	// check's token stands in for them.
+	v1.HandlerFunc(http.MethodPost, "/heartbeat/{token}", api.Ping)
+}
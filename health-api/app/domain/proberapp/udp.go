@@ -0,0 +1,65 @@
+package proberapp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"health-api/app/sdk/metrics"
+	"health-api/business/domain/proberbus"
+	"health-api/foundation/heartbeat"
+	"health-api/foundation/logger"
+)
+
+// Set of UDP heartbeat settings.
+const (
+	// maxDatagram bounds the datagrams read; valid ones are at most 298
+	// bytes.
+	maxDatagram = 512
+
+	// maxSkew is how far a datagram's time may be from ours, matching the
+	// window of signed agent reports.
+	maxSkew = 5 * time.Minute
+)
+
+// ServeUDP feeds the signed heartbeat datagrams received on conn into the
+// heartbeat checks until ctx is canceled. Datagrams that fail verification
+// are dropped silently apart from a counter, so a spoofing or misconfigured
+// device can't flood the log.
+func ServeUDP(ctx context.Context, log *logger.Logger, conn net.PacketConn, proberBus *proberbus.Business, key []byte) {
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, maxDatagram)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Warn(ctx, "heartbeat", "status", "reading datagram failed", "error", err)
+			continue
+		}
+
+		beat, err := heartbeat.Decode(buf[:n], key, time.Now(), maxSkew)
+		if err != nil {
+			result := "invalid"
+			if errors.Is(err, heartbeat.ErrStale) {
+				result = "stale"
+			}
+			metrics.HeartbeatDatagramsTotal.WithLabelValues(result).Inc()
+			continue
+		}
+
+		if _, err := proberBus.PingStatus(ctx, beat.ID, beat.Status); err != nil {
+			metrics.HeartbeatDatagramsTotal.WithLabelValues("unknown_check").Inc()
+			log.Debug(ctx, "heartbeat", "status", "datagram for unknown check", "check", beat.ID, "addr", addr.String())
+			continue
+		}
+
+		metrics.HeartbeatDatagramsTotal.WithLabelValues("accepted").Inc()
+	}
+}
@@ -0,0 +1,39 @@
+package prometheusapp
+
+import (
+	"strconv"
+
+	"health-api/business/domain/prometheusbus"
+)
+
+// Metric is the response of a metric query. The result keeps the shape of
+// a Prometheus instant vector so clients of the original server keep
+// working.
+type Metric struct {
+	Metric string   `json:"metric"`
+	Result []Sample `json:"result"`
+}
+
+// Sample is a single series, with its value as [unix seconds, "value"].
+type Sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]any            `json:"value"`
+}
+
+func toAppMetric(m prometheusbus.Metric) Metric {
+	samples := make([]Sample, len(m.Samples))
+	for i, s := range m.Samples {
+		samples[i] = Sample{
+			Metric: s.Labels,
+			Value: [2]any{
+				float64(s.Timestamp.UnixMilli()) / 1000,
+				strconv.FormatFloat(s.Value, 'f', -1, 64),
+			},
+		}
+	}
+
+	return Metric{
+		Metric: m.Name,
+		Result: samples,
+	}
+}
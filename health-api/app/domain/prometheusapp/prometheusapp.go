@@ -0,0 +1,47 @@
+// Package prometheusapp provides HTTP handlers for raw Prometheus metric
+// queries.
+package prometheusapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/prometheusbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles metric HTTP requests.
+type App struct {
+	log           *logger.Logger
+	prometheusBus *prometheusbus.Business
+}
+
+// NewApp constructs a new prometheus app.
+func NewApp(log *logger.Logger, prometheusBus *prometheusbus.Business) *App {
+	return &App{
+		log:           log,
+		prometheusBus: prometheusBus,
+	}
+}
+
+// QueryMetric handles GET /api/v1/metrics/{metric} requests.
+func (a *App) QueryMetric(ctx context.Context, r *http.Request) web.Encoder {
+	if a.prometheusBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "prometheus is not configured")
+	}
+
+	name := web.Param(r, "metric")
+
+	metric, err := a.prometheusBus.QueryMetric(ctx, name)
+	if err != nil {
+		if errors.Is(err, prometheusbus.ErrInvalidMetric) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "query metric: %w", err)
+	}
+
+	return web.JSONResponse{Data: toAppMetric(metric)}
+}
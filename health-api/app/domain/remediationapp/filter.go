@@ -0,0 +1,33 @@
+package remediationapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/remediationbus"
+)
+
+func parseFilter(r *http.Request) (remediationbus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter remediationbus.QueryFilter
+
+	if target := values.Get("target"); target != "" {
+		filter.Target = &target
+	}
+
+	if rule := values.Get("rule"); rule != "" {
+		filter.Rule = &rule
+	}
+
+	if since := values.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return remediationbus.QueryFilter{}, errs.FieldErrors(map[string]string{"since": "must be an RFC 3339 timestamp"})
+		}
+		filter.Since = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,50 @@
+// Package remediationapp provides HTTP handlers for the remediation audit
+// trail.
+package remediationapp
+
+import (
+	"context"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/remediationbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles remediation HTTP requests.
+type App struct {
+	log            *logger.Logger
+	remediationBus *remediationbus.Business
+}
+
+// NewApp constructs a new remediation app.
+func NewApp(log *logger.Logger, remediationBus *remediationbus.Business) *App {
+	return &App{
+		log:            log,
+		remediationBus: remediationBus,
+	}
+}
+
+// Query handles GET /api/v1/remediations requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	if a.remediationBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "remediation is not enabled")
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	attempts, err := a.remediationBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if attempts == nil {
+		attempts = []remediationbus.Attempt{}
+	}
+
+	return web.JSONResponse{Data: attempts}
+}
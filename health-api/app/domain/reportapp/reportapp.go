@@ -0,0 +1,81 @@
+// Package reportapp provides HTTP handlers for SLA reports.
+package reportapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/reportbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles report HTTP requests.
+type App struct {
+	log       *logger.Logger
+	reportBus *reportbus.Business
+}
+
+// NewApp constructs a new report app.
+func NewApp(log *logger.Logger, reportBus *reportbus.Business) *App {
+	return &App{
+		log:       log,
+		reportBus: reportBus,
+	}
+}
+
+// QuerySLA handles GET /api/v1/reports/sla requests. The period defaults to
+// the current month. Use ?format=csv|xlsx or the Accept header for exports.
+func (a *App) QuerySLA(ctx context.Context, r *http.Request) web.Encoder {
+	name := r.URL.Query().Get("period")
+	if name == "" {
+		name = time.Now().UTC().Format("2006-01")
+	}
+
+	period, err := reportbus.ParsePeriod(name)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rpt, err := a.reportBus.SLA(ctx, period)
+	if err != nil {
+		if errors.Is(err, reportbus.ErrNotStarted) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "sla: %w", err)
+	}
+
+	switch web.Format(r) {
+	case web.FormatCSV:
+		return web.CSVResponse{Header: slaHeader, Rows: slaRows(rpt)}
+	case web.FormatXLSX:
+		return web.XLSXResponse{Header: slaHeader, Rows: slaRows(rpt)}
+	}
+
+	return web.JSONResponse{Data: rpt}
+}
+
+var slaHeader = []string{"period", "target", "team", "availability_percent", "downtime_seconds", "incidents", "calendar", "measured_seconds"}
+
+func slaRows(rpt reportbus.SLAReport) [][]string {
+	var rows [][]string
+
+	for _, ts := range rpt.Targets {
+		rows = append(rows, []string{
+			rpt.Period,
+			ts.Target,
+			ts.Team,
+			strconv.FormatFloat(ts.AvailabilityPercent, 'f', 3, 64),
+			strconv.FormatFloat(ts.DowntimeSeconds, 'f', 0, 64),
+			strconv.Itoa(ts.Incidents),
+			ts.Calendar,
+			strconv.FormatFloat(ts.MeasuredSeconds, 'f', 0, 64),
+		})
+	}
+
+	return rows
+}
@@ -0,0 +1,50 @@
+package snoozeapp
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/snoozebus"
+)
+
+// snoozeRequest holds the query parameters of a snooze.
+type snoozeRequest struct {
+	duration time.Duration
+	reason   string
+}
+
+func parseSnooze(r *http.Request) (snoozeRequest, error) {
+	values := r.URL.Query()
+
+	d, err := time.ParseDuration(values.Get("duration"))
+	if err != nil {
+		return snoozeRequest{}, errs.FieldErrors(map[string]string{"duration": "must be a duration such as 2h"})
+	}
+
+	return snoozeRequest{
+		duration: d,
+		reason:   values.Get("reason"),
+	}, nil
+}
+
+func parseFilter(r *http.Request) (snoozebus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter snoozebus.QueryFilter
+
+	if target := values.Get("target"); target != "" {
+		filter.Target = &target
+	}
+
+	if active := values.Get("active"); active != "" {
+		b, err := strconv.ParseBool(active)
+		if err != nil {
+			return snoozebus.QueryFilter{}, errs.FieldErrors(map[string]string{"active": "must be true or false"})
+		}
+		filter.Active = b
+	}
+
+	return filter, nil
+}
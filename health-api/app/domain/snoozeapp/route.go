@@ -0,0 +1,36 @@
+package snoozeapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/snoozebus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log       *logger.Logger
+	SnoozeBus *snoozebus.Business
+	HealthBus *healthbus.Business
+	Timeout   time.Duration
+	Auth      *auth.Auth
+}
+
+// Routes registers all snooze routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.SnoozeBus, cfg.HealthBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth))
+	viewer := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleViewer))
+	operator := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleOperator))
+
+	viewer.HandlerFunc(http.MethodGet, "/snoozes", api.Query)
+	operator.HandlerFunc(http.MethodPost, "/health/{target}/snooze", api.Create)
+	operator.HandlerFunc(http.MethodDelete, "/health/{target}/snooze", api.End)
+}
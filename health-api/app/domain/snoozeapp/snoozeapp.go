@@ -0,0 +1,114 @@
+// Package snoozeapp provides HTTP handlers for snoozing the notifications
+// of a target.
+package snoozeapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/snoozebus"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles snooze HTTP requests.
+type App struct {
+	log       *logger.Logger
+	snoozeBus *snoozebus.Business
+	healthBus *healthbus.Business
+}
+
+// NewApp constructs a new snooze app.
+func NewApp(log *logger.Logger, snoozeBus *snoozebus.Business, healthBus *healthbus.Business) *App {
+	return &App{
+		log:       log,
+		snoozeBus: snoozeBus,
+		healthBus: healthBus,
+	}
+}
+
+// Create handles POST /api/v1/health/{target}/snooze requests. The target
+// keeps reporting its status but doesn't notify anyone for the duration.
+func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
+	if a.snoozeBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "snoozes are not enabled")
+	}
+
+	req, err := parseSnooze(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	target := web.Param(r, "target")
+
+	check, err := a.healthBus.QueryHealthCheckByTarget(ctx, target)
+	if err != nil {
+		return errs.Newf(errs.NotFound, "health check not found: %w", err)
+	}
+
+	s, err := a.snoozeBus.Create(ctx, snoozebus.NewSnooze{
+		Target:    check.Target,
+		Namespace: check.Namespace,
+		Duration:  req.duration,
+		Reason:    req.reason,
+		By:        mid.GetClaims(ctx).Subject,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, snoozebus.ErrInvalidDuration):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, tenant.ErrNamespace):
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "create: %w", err)
+	}
+
+	return web.JSONResponse{Data: s, StatusCode: http.StatusCreated}
+}
+
+// End handles DELETE /api/v1/health/{target}/snooze requests.
+func (a *App) End(ctx context.Context, r *http.Request) web.Encoder {
+	if a.snoozeBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "snoozes are not enabled")
+	}
+
+	target := web.Param(r, "target")
+
+	if err := a.snoozeBus.End(ctx, target, mid.GetClaims(ctx).Subject); err != nil {
+		if errors.Is(err, snoozebus.ErrNotSnoozed) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "end: %w", err)
+	}
+
+	return nil
+}
+
+// Query handles GET /api/v1/snoozes requests. Ended and expired snoozes
+// are listed too, as the audit trail of who silenced which target.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	if a.snoozeBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "snoozes are not enabled")
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	snoozes, err := a.snoozeBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if snoozes == nil {
+		snoozes = []snoozebus.Snooze{}
+	}
+
+	return web.JSONResponse{Data: snoozes}
+}
@@ -0,0 +1,29 @@
+package storageapp
+
+import (
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/storagebus"
+)
+
+func parseFilter(r *http.Request) (storagebus.QueryFilter, error) {
+	values := r.URL.Query()
+
+	var filter storagebus.QueryFilter
+
+	if ns := values.Get("namespace"); ns != "" {
+		filter.Namespace = &ns
+	}
+
+	if status := values.Get("status"); status != "" {
+		switch status {
+		case storagebus.StatusOK, storagebus.StatusWarning, storagebus.StatusCritical:
+		default:
+			return storagebus.QueryFilter{}, errs.FieldErrors(map[string]string{"status": "must be one of ok, warning, critical"})
+		}
+		filter.Status = &status
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,49 @@
+// Package storageapp provides HTTP handlers for persistent volume usage.
+package storageapp
+
+import (
+	"context"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/storagebus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles storage HTTP requests.
+type App struct {
+	log        *logger.Logger
+	storageBus *storagebus.Business
+}
+
+// NewApp constructs a new storage app.
+func NewApp(log *logger.Logger, storageBus *storagebus.Business) *App {
+	return &App{
+		log:        log,
+		storageBus: storageBus,
+	}
+}
+
+// Query handles GET /api/v1/storage requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	if a.storageBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "storage monitoring is not enabled")
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	volumes, err := a.storageBus.Query(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	if volumes == nil {
+		volumes = []storagebus.Volume{}
+	}
+
+	return web.JSONResponse{Data: volumes}
+}
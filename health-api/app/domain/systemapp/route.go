@@ -0,0 +1,33 @@
+package systemapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/notifybus"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Build     string
+	StartedAt time.Time
+	Stores    []string
+	HealthBus *healthbus.Business
+	NotifyBus *notifybus.Business
+	Timeout   time.Duration
+	Auth      *auth.Auth
+}
+
+// Routes registers all system routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Build, cfg.StartedAt, cfg.Stores, cfg.HealthBus, cfg.NotifyBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth), mid.Authorize(cfg.Auth, auth.RoleViewer))
+
+	v1.HandlerFunc(http.MethodGet, "/system", api.QuerySystem)
+}
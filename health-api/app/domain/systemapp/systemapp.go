@@ -0,0 +1,130 @@
+// Package systemapp provides HTTP handlers for introspecting the service itself.
+package systemapp
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/notifybus"
+	"health-api/foundation/web"
+)
+
+// App handles system information HTTP requests.
+type App struct {
+	build     string
+	startedAt time.Time
+	stores    []string
+	healthBus *healthbus.Business
+	notifyBus *notifybus.Business
+}
+
+// NewApp constructs a new system app. The notify business layer is
+// optional; without it no notification channels are reported.
+func NewApp(build string, startedAt time.Time, stores []string, healthBus *healthbus.Business, notifyBus *notifybus.Business) *App {
+	return &App{
+		build:     build,
+		startedAt: startedAt,
+		stores:    stores,
+		healthBus: healthBus,
+		notifyBus: notifyBus,
+	}
+}
+
+// QuerySystem handles GET /api/v1/system requests.
+func (a *App) QuerySystem(ctx context.Context, r *http.Request) web.Encoder {
+	info := System{
+		Build:         a.build,
+		GoVersion:     runtime.Version(),
+		StartedAt:     a.startedAt,
+		Uptime:        time.Since(a.startedAt).Round(time.Second).String(),
+		Goroutines:    runtime.NumGoroutine(),
+		Stores:        a.stores,
+		Cache:         toAppCache(a.healthBus.CacheStats()),
+		Notifications: []Channel{},
+	}
+
+	if lastSync := a.healthBus.LastSync(); !lastSync.IsZero() {
+		info.LastSync = &lastSync
+	}
+
+	if a.notifyBus != nil {
+		info.Notifications = toAppChannels(a.notifyBus.Channels())
+	}
+
+	return web.JSONResponse{Data: info}
+}
+
+// =============================================================================
+
+// System represents runtime information about the service.
+type System struct {
+	Build         string     `json:"build"`
+	GoVersion     string     `json:"go_version"`
+	StartedAt     time.Time  `json:"started_at"`
+	Uptime        string     `json:"uptime"`
+	Goroutines    int        `json:"goroutines"`
+	Stores        []string   `json:"stores"`
+	LastSync      *time.Time `json:"last_sync,omitempty"`
+	Cache         Cache      `json:"cache"`
+	Notifications []Channel  `json:"notifications"`
+}
+
+// Cache represents how well the health snapshot serves queries. The
+// snapshot fields are left out until the first sync.
+type Cache struct {
+	Hits     int64      `json:"hits"`
+	Misses   int64      `json:"misses"`
+	HitRatio float64    `json:"hit_ratio"`
+	TakenAt  *time.Time `json:"taken_at,omitempty"`
+	Age      string     `json:"age,omitempty"`
+	Checks   int        `json:"checks"`
+	Alerts   int        `json:"alerts"`
+}
+
+func toAppCache(stats healthbus.CacheStats) Cache {
+	cache := Cache{
+		Hits:   stats.Hits,
+		Misses: stats.Misses,
+		Checks: stats.Checks,
+		Alerts: stats.Alerts,
+	}
+
+	if total := stats.Hits + stats.Misses; total > 0 {
+		cache.HitRatio = float64(stats.Hits) / float64(total)
+	}
+
+	if !stats.TakenAt.IsZero() {
+		cache.TakenAt = &stats.TakenAt
+		cache.Age = stats.Age.Round(time.Second).String()
+	}
+
+	return cache
+}
+
+// Channel represents the delivery results of a notification channel.
+type Channel struct {
+	Name        string     `json:"name"`
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	Deliveries  int        `json:"deliveries"`
+	Failures    int        `json:"failures"`
+}
+
+func toAppChannels(statuses []notifybus.ChannelStatus) []Channel {
+	channels := make([]Channel, len(statuses))
+	for i, st := range statuses {
+		channels[i] = Channel{
+			Name:        st.Name,
+			LastAttempt: st.LastAttempt,
+			LastSuccess: st.LastSuccess,
+			LastError:   st.LastError,
+			Deliveries:  st.Deliveries,
+			Failures:    st.Failures,
+		}
+	}
+	return channels
+}
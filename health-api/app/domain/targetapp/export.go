@@ -0,0 +1,113 @@
+package targetapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/validate"
+	"health-api/foundation/web"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// maxImportSize bounds the size of an imported target inventory.
+const maxImportSize = 4 << 20
+
+// Export handles GET /api/v1/targets/export requests. It returns the
+// targets visible to the caller as a YAML document that Import and
+// TARGETS_FILE accept.
+func (a *App) Export(ctx context.Context, r *http.Request) web.Encoder {
+	file, err := a.targetBus.Export(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "export: %w", err)
+	}
+
+	return yamlResponse{Data: file}
+}
+
+// Import handles PUT /api/v1/targets/export requests. The YAML document
+// replaces the targets visible to the caller.
+func (a *App) Import(ctx context.Context, r *http.Request) web.Encoder {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxImportSize+1))
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "read body: %w", err)
+	}
+	if len(data) > maxImportSize {
+		return errs.Newf(errs.InvalidArgument, "inventory exceeds %d bytes", maxImportSize)
+	}
+
+	var file targetbus.File
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return errs.Newf(errs.InvalidArgument, "decode yaml: %w", err)
+	}
+
+	if err := validateFile(file); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	result, err := a.targetBus.Import(ctx, file)
+	if err != nil {
+		if errors.Is(err, tenant.ErrNamespace) {
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "import: %w", err)
+	}
+
+	return web.JSONResponse{Data: result}
+}
+
+// validateFile checks every target of an imported inventory and rejects
+// duplicate names.
+func validateFile(file targetbus.File) error {
+	fields := make(map[string]string)
+
+	if err := validate.Check(file); err != nil {
+		var verrs validate.Errors
+		if !errors.As(err, &verrs) {
+			return err
+		}
+		for field, msg := range verrs {
+			fields[field] = msg
+		}
+	}
+
+	seen := make(map[string]bool, len(file.Targets))
+	for i, nt := range file.Targets {
+		if nt.Name != "" && seen[nt.Name] {
+			fields[fmt.Sprintf("targets[%d].name", i)] = "must be unique"
+		}
+		seen[nt.Name] = true
+
+		if nt.Name != "" && slices.Contains(nt.DependsOn, nt.Name) {
+			fields[fmt.Sprintf("targets[%d].depends_on", i)] = "must not include the target itself"
+		}
+	}
+
+	if len(fields) > 0 {
+		return errs.FieldErrors(fields)
+	}
+
+	return nil
+}
+
+// yamlResponse encodes data as a YAML document.
+type yamlResponse struct {
+	Data any
+}
+
+// Encode implements web.Encoder.
+func (r yamlResponse) Encode() ([]byte, string, error) {
+	data, err := yaml.Marshal(r.Data)
+	if err != nil {
+		return nil, "", fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return data, "application/yaml", nil
+}
@@ -0,0 +1,7 @@
+package targetapp
+
+// PauseRequest is the optional body of a pause request.
+type PauseRequest struct {
+	TTLSeconds int    `json:"ttl_seconds" validate:"min=0"`
+	Reason     string `json:"reason"`
+}
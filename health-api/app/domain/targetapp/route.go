@@ -0,0 +1,44 @@
+package targetapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/alertrulebus"
+	"health-api/business/domain/targetbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log          *logger.Logger
+	TargetBus    *targetbus.Business
+	AlertRuleBus *alertrulebus.Business
+	Timeout      time.Duration
+	Auth         *auth.Auth
+}
+
+// Routes registers all target routes.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.TargetBus, cfg.AlertRuleBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth))
+	viewer := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleViewer))
+	operator := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleOperator))
+	admin := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleAdmin))
+
+	viewer.HandlerFunc(http.MethodGet, "/targets", api.Query)
+	viewer.HandlerFunc(http.MethodGet, "/targets/{target}", api.QueryByName)
+	viewer.HandlerFunc(http.MethodGet, "/targets/export", api.Export)
+	operator.HandlerFunc(http.MethodPost, "/targets", api.Create)
+	operator.HandlerFunc(http.MethodPut, "/targets/{target}", api.Upsert)
+	operator.HandlerFunc(http.MethodPost, "/targets/{target}/alert", api.ProvisionAlert)
+	admin.HandlerFunc(http.MethodDelete, "/targets/{target}", api.Delete)
+	admin.HandlerFunc(http.MethodPost, "/targets/{target}/pause", api.Pause)
+	admin.HandlerFunc(http.MethodPost, "/targets/{target}/resume", api.Resume)
+	admin.HandlerFunc(http.MethodPut, "/targets/export", api.Import)
+}
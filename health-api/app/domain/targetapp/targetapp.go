@@ -0,0 +1,237 @@
+// Package targetapp provides HTTP handlers for managing target metadata.
+package targetapp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/alertrulebus"
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles target HTTP requests.
+type App struct {
+	log          *logger.Logger
+	targetBus    *targetbus.Business
+	alertRuleBus *alertrulebus.Business
+}
+
+// NewApp constructs a new target app. alertRuleBus may be nil when alert
+// provisioning is not configured.
+func NewApp(log *logger.Logger, targetBus *targetbus.Business, alertRuleBus *alertrulebus.Business) *App {
+	return &App{
+		log:          log,
+		targetBus:    targetBus,
+		alertRuleBus: alertRuleBus,
+	}
+}
+
+// Create handles POST /api/v1/targets requests.
+func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
+	var nt targetbus.NewTarget
+	if err := web.Decode(r, &nt); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := validateDependsOn(nt.Name, nt.DependsOn); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tgt, err := a.targetBus.Create(ctx, nt)
+	if err != nil {
+		if errors.Is(err, targetbus.ErrExists) {
+			return errs.Newf(errs.AlreadyExists, "target %s already exists", nt.Name)
+		}
+		if errors.Is(err, tenant.ErrNamespace) {
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "create: %w", err)
+	}
+
+	setETag(ctx, tgt)
+
+	return web.JSONResponse{Data: tgt, StatusCode: http.StatusCreated}
+}
+
+// Upsert handles PUT /api/v1/targets/{target} requests. The target is
+// created if it does not exist and updated otherwise. With If-Match the
+// write only succeeds if the target's ETag matches.
+func (a *App) Upsert(ctx context.Context, r *http.Request) web.Encoder {
+	name := web.Param(r, "target")
+
+	var ut targetbus.UpdateTarget
+	if err := web.Decode(r, &ut); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := validateDependsOn(name, ut.DependsOn); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	var version int
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		tgt, err := a.targetBus.QueryByName(ctx, name)
+		if err != nil {
+			if errors.Is(err, targetbus.ErrNotFound) {
+				return errs.Newf(errs.PreconditionFailed, "target %s does not exist", name)
+			}
+			return errs.Newf(errs.Internal, "query: %w", err)
+		}
+
+		if !etagMatch(ifMatch, etag(tgt)) {
+			return errs.Newf(errs.PreconditionFailed, "target %s has changed", name)
+		}
+		version = tgt.Version
+	}
+
+	tgt, created, err := a.targetBus.Upsert(ctx, name, ut, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, targetbus.ErrVersion):
+			return errs.Newf(errs.PreconditionFailed, "target %s has changed", name)
+		case errors.Is(err, targetbus.ErrExists):
+			return errs.Newf(errs.AlreadyExists, "target %s already exists", name)
+		case errors.Is(err, tenant.ErrNamespace):
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "upsert: %w", err)
+	}
+
+	setETag(ctx, tgt)
+
+	statusCode := http.StatusOK
+	if created {
+		statusCode = http.StatusCreated
+	}
+
+	return web.JSONResponse{Data: tgt, StatusCode: statusCode}
+}
+
+// Delete handles DELETE /api/v1/targets/{target} requests.
+func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
+	name := web.Param(r, "target")
+
+	if err := a.targetBus.Delete(ctx, name); err != nil {
+		return queryError(name, err)
+	}
+
+	return nil
+}
+
+// Query handles GET /api/v1/targets requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	tgts, err := a.targetBus.Query(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	return web.JSONResponse{Data: tgts}
+}
+
+// QueryByName handles GET /api/v1/targets/{target} requests.
+func (a *App) QueryByName(ctx context.Context, r *http.Request) web.Encoder {
+	name := web.Param(r, "target")
+
+	tgt, err := a.targetBus.QueryByName(ctx, name)
+	if err != nil {
+		return queryError(name, err)
+	}
+
+	setETag(ctx, tgt)
+
+	return web.JSONResponse{Data: tgt}
+}
+
+// Pause handles POST /api/v1/targets/{target}/pause requests. The body is
+// optional; ttl_seconds resumes the target automatically.
+func (a *App) Pause(ctx context.Context, r *http.Request) web.Encoder {
+	name := web.Param(r, "target")
+
+	var req PauseRequest
+	if err := web.Decode(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	tgt, err := a.targetBus.Pause(ctx, name, time.Duration(req.TTLSeconds)*time.Second, req.Reason)
+	if err != nil {
+		return queryError(name, err)
+	}
+
+	return web.JSONResponse{Data: tgt}
+}
+
+// Resume handles POST /api/v1/targets/{target}/resume requests.
+func (a *App) Resume(ctx context.Context, r *http.Request) web.Encoder {
+	name := web.Param(r, "target")
+
+	tgt, err := a.targetBus.Resume(ctx, name)
+	if err != nil {
+		return queryError(name, err)
+	}
+
+	return web.JSONResponse{Data: tgt}
+}
+
+// ProvisionAlert handles POST /api/v1/targets/{target}/alert requests.
+func (a *App) ProvisionAlert(ctx context.Context, r *http.Request) web.Encoder {
+	if a.alertRuleBus == nil {
+		return errs.Newf(errs.FailedPrecondition, "alert provisioning is not configured")
+	}
+
+	name := web.Param(r, "target")
+
+	rule, err := a.alertRuleBus.Provision(ctx, name)
+	if err != nil {
+		if errors.Is(err, targetbus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "target %s not found", name)
+		}
+		return errs.Newf(errs.Internal, "provision alert: %w", err)
+	}
+
+	statusCode := http.StatusOK
+	if rule.Created {
+		statusCode = http.StatusCreated
+	}
+
+	return web.JSONResponse{Data: rule, StatusCode: statusCode}
+}
+
+func queryError(name string, err error) *errs.Error {
+	if errors.Is(err, targetbus.ErrNotFound) {
+		return errs.Newf(errs.NotFound, "target %s not found", name)
+	}
+	return errs.Newf(errs.Internal, "query: %w", err)
+}
+
+// etag returns the entity tag of the target's current version.
+func etag(tgt targetbus.Target) string {
+	return `"` + tgt.ID + "-" + strconv.Itoa(tgt.Version) + `"`
+}
+
+// setETag reports the target's version in the ETag response header.
+func setETag(ctx context.Context, tgt targetbus.Target) {
+	if w := web.GetWriter(ctx); w != nil {
+		w.Header().Set("ETag", etag(tgt))
+	}
+}
+
+// etagMatch reports whether the If-Match header value matches etag. Weak
+// tags never match, as If-Match requires strong comparison.
+func etagMatch(header string, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
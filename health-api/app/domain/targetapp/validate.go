@@ -0,0 +1,19 @@
+package targetapp
+
+import (
+	"slices"
+
+	"health-api/app/sdk/errs"
+)
+
+// validateDependsOn rejects a target that depends on itself. The remaining
+// payload rules are declared as validate tags and checked by web.Decode.
+func validateDependsOn(name string, dependsOn []string) error {
+	if name != "" && slices.Contains(dependsOn, name) {
+		return errs.FieldErrors(map[string]string{
+			"depends_on": "must not include the target itself",
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package uiapp serves the dashboard frontend.
+package uiapp
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"net/http"
+	"os"
+
+	"health-api/app/sdk/errs"
+	"health-api/foundation/web"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Config contains dependencies needed to serve the frontend.
+type Config struct {
+	// Dir serves the frontend from disk instead of the embedded build.
+	Dir string
+}
+
+// Routes registers the frontend at the root path. Paths under /api/ that
+// no route matches are answered with a JSON 404 rather than the frontend.
+func Routes(app *web.App, cfg Config) {
+	var fsys fs.FS = os.DirFS(cfg.Dir)
+
+	if cfg.Dir == "" {
+		sub, err := fs.Sub(dist, "dist")
+		if err != nil {
+			panic(err)
+		}
+		fsys = sub
+	}
+
+	app.FileServer("/", fsys)
+	app.Group("/api").HandlerFunc(http.MethodGet, "/", notFound)
+}
+
+func notFound(ctx context.Context, r *http.Request) web.Encoder {
+	return errs.Newf(errs.NotFound, "no route for %s", r.URL.Path)
+}
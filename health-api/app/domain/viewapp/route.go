@@ -0,0 +1,35 @@
+package viewapp
+
+import (
+	"net/http"
+	"time"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/viewbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// Config contains dependencies needed to construct handlers.
+type Config struct {
+	Log     *logger.Logger
+	ViewBus *viewbus.Business
+	Timeout time.Duration
+	Auth    *auth.Auth
+}
+
+// Routes registers all saved view routes. Views are personal or shared
+// within the tenant, so every viewer can manage their own.
+func Routes(app *web.App, cfg Config) {
+	const version = "/api/v1"
+
+	api := NewApp(cfg.Log, cfg.ViewBus)
+	v1 := app.Group(version, mid.Timeout(cfg.Timeout), mid.Authenticate(cfg.Auth))
+	viewer := v1.Group("", mid.Authorize(cfg.Auth, auth.RoleViewer))
+
+	viewer.HandlerFunc(http.MethodGet, "/views", api.Query)
+	viewer.HandlerFunc(http.MethodGet, "/views/{id}", api.QueryByID)
+	viewer.HandlerFunc(http.MethodPost, "/views", api.Create)
+	viewer.HandlerFunc(http.MethodDelete, "/views/{id}", api.Delete)
+}
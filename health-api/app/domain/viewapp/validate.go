@@ -0,0 +1,35 @@
+package viewapp
+
+import (
+	"fmt"
+	"strings"
+
+	"health-api/app/sdk/errs"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/viewbus"
+)
+
+// validateView checks the filter and grouping of a view, so a saved view
+// cannot fail when the dashboard applies it.
+func validateView(nv viewbus.NewView) error {
+	fields := make(map[string]string)
+
+	if nv.Filter != "" {
+		if _, err := healthbus.ParseFilter(nv.Filter); err != nil {
+			fields["filter"] = err.Error()
+		}
+	}
+
+	for _, field := range nv.GroupBy {
+		if !healthbus.FilterField(strings.ToLower(field)) {
+			fields["group_by"] = fmt.Sprintf("unknown field %q", field)
+			break
+		}
+	}
+
+	if len(fields) > 0 {
+		return errs.FieldErrors(fields)
+	}
+
+	return nil
+}
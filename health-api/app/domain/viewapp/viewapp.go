@@ -0,0 +1,89 @@
+// Package viewapp provides HTTP handlers for saved views.
+package viewapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"health-api/app/sdk/errs"
+	"health-api/app/sdk/mid"
+	"health-api/business/domain/viewbus"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+)
+
+// App handles saved view HTTP requests.
+type App struct {
+	log     *logger.Logger
+	viewBus *viewbus.Business
+}
+
+// NewApp constructs a new view app.
+func NewApp(log *logger.Logger, viewBus *viewbus.Business) *App {
+	return &App{
+		log:     log,
+		viewBus: viewBus,
+	}
+}
+
+// Create handles POST /api/v1/views requests.
+func (a *App) Create(ctx context.Context, r *http.Request) web.Encoder {
+	var nv viewbus.NewView
+	if err := web.Decode(r, &nv); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := validateView(nv); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	v, err := a.viewBus.Create(ctx, mid.GetClaims(ctx).Subject, nv)
+	if err != nil {
+		return errs.Newf(errs.Internal, "create: %w", err)
+	}
+
+	return web.JSONResponse{Data: v, StatusCode: http.StatusCreated}
+}
+
+// Query handles GET /api/v1/views requests.
+func (a *App) Query(ctx context.Context, r *http.Request) web.Encoder {
+	views, err := a.viewBus.Query(ctx, mid.GetClaims(ctx).Subject)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	return web.JSONResponse{Data: views}
+}
+
+// QueryByID handles GET /api/v1/views/{id} requests.
+func (a *App) QueryByID(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	v, err := a.viewBus.QueryByID(ctx, mid.GetClaims(ctx).Subject, id)
+	if err != nil {
+		if errors.Is(err, viewbus.ErrNotFound) {
+			return errs.Newf(errs.NotFound, "view %s not found", id)
+		}
+		return errs.Newf(errs.Internal, "query: %w", err)
+	}
+
+	return web.JSONResponse{Data: v}
+}
+
+// Delete handles DELETE /api/v1/views/{id} requests.
+func (a *App) Delete(ctx context.Context, r *http.Request) web.Encoder {
+	id := web.Param(r, "id")
+
+	if err := a.viewBus.Delete(ctx, mid.GetClaims(ctx).Subject, id); err != nil {
+		switch {
+		case errors.Is(err, viewbus.ErrNotFound):
+			return errs.Newf(errs.NotFound, "view %s not found", id)
+		case errors.Is(err, viewbus.ErrNotOwner):
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "delete: %w", err)
+	}
+
+	return nil
+}
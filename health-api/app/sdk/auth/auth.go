@@ -0,0 +1,184 @@
+// Package auth provides authentication and role-based authorization for the
+// API. Callers present either a JWT signed with the shared secret or an API
+// key from the configured key file; both resolve to a set of roles.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.yaml.in/yaml/v2"
+)
+
+// Set of roles, each granting everything the previous one does.
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// roleRank orders roles by privilege.
+var roleRank = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Set of errors returned by the auth package.
+var (
+	ErrUnauthenticated = errors.New("unauthenticated")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// Claims represents the authorization claims of a caller. Tenant and
+// Namespaces scope the caller to its own Kubernetes namespaces; a caller
+// without namespaces sees everything.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles      []string `json:"roles"`
+	Tenant     string   `json:"tenant,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// HasRole reports whether the claims grant role, directly or through a more
+// privileged role.
+func (c Claims) HasRole(role string) bool {
+	want := roleRank[role]
+	return slices.ContainsFunc(c.Roles, func(r string) bool {
+		return roleRank[r] >= want
+	})
+}
+
+// APIKey is a static credential assigned a single role and, optionally, a
+// tenant with its namespaces.
+type APIKey struct {
+	Name       string   `yaml:"name"`
+	Key        string   `yaml:"key"`
+	Role       string   `yaml:"role"`
+	Tenant     string   `yaml:"tenant"`
+	Namespaces []string `yaml:"namespaces"`
+}
+
+// Config holds the credentials accepted by the API.
+type Config struct {
+	JWTSecret string
+	JWTIssuer string
+	APIKeys   []APIKey
+}
+
+// Auth authenticates and authorizes callers.
+type Auth struct {
+	secret []byte
+	parser *jwt.Parser
+	keys   map[[sha256.Size]byte]APIKey
+}
+
+// New constructs an Auth. It returns nil when no credentials are configured,
+// which leaves the API open.
+func New(cfg Config) (*Auth, error) {
+	if cfg.JWTSecret == "" && len(cfg.APIKeys) == 0 {
+		return nil, nil
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.JWTIssuer))
+	}
+
+	a := Auth{
+		secret: []byte(cfg.JWTSecret),
+		parser: jwt.NewParser(opts...),
+		keys:   make(map[[sha256.Size]byte]APIKey, len(cfg.APIKeys)),
+	}
+
+	for _, k := range cfg.APIKeys {
+		if k.Key == "" {
+			return nil, fmt.Errorf("api key %q: key required", k.Name)
+		}
+		if _, ok := roleRank[k.Role]; !ok {
+			return nil, fmt.Errorf("api key %q: unknown role %q", k.Name, k.Role)
+		}
+		a.keys[sha256.Sum256([]byte(k.Key))] = k
+	}
+
+	return &a, nil
+}
+
+// LoadAPIKeys reads API keys from a YAML file with a top level "keys" list.
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading api keys: %w", err)
+	}
+
+	var file struct {
+		Keys []APIKey `yaml:"keys"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing api keys: %w", err)
+	}
+
+	return file.Keys, nil
+}
+
+// Authenticate resolves a bearer token, either an API key or a JWT, to the
+// caller's claims.
+func (a *Auth) Authenticate(token string) (Claims, error) {
+	if token == "" {
+		return Claims{}, fmt.Errorf("%w: missing token", ErrUnauthenticated)
+	}
+
+	if k, ok := a.lookupKey(token); ok {
+		return Claims{
+			RegisteredClaims: jwt.RegisteredClaims{Subject: k.Name},
+			Roles:            []string{k.Role},
+			Tenant:           k.Tenant,
+			Namespaces:       k.Namespaces,
+		}, nil
+	}
+
+	if len(a.secret) == 0 {
+		return Claims{}, fmt.Errorf("%w: unknown api key", ErrUnauthenticated)
+	}
+
+	var claims Claims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.keyFunc); err != nil {
+		return Claims{}, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+
+	return claims, nil
+}
+
+// Authorize checks that the claims grant role.
+func (a *Auth) Authorize(claims Claims, role string) error {
+	if !claims.HasRole(role) {
+		return fmt.Errorf("%w: %s role required", ErrForbidden, role)
+	}
+
+	return nil
+}
+
+func (a *Auth) keyFunc(*jwt.Token) (any, error) {
+	return a.secret, nil
+}
+
+// lookupKey finds the API key by its hash, so the comparison does not leak
+// how much of a key matched.
+func (a *Auth) lookupKey(token string) (APIKey, bool) {
+	sum := sha256.Sum256([]byte(token))
+
+	k, ok := a.keys[sum]
+	if !ok {
+		return APIKey{}, false
+	}
+
+	return k, subtle.ConstantTimeCompare([]byte(k.Key), []byte(token)) == 1
+}
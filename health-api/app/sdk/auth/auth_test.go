@@ -0,0 +1,181 @@
+package auth_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"health-api/app/sdk/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const secret = "test-secret"
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims auth.Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("Should be able to sign the token: %s", err)
+	}
+	return token
+}
+
+func Test_JWT(t *testing.T) {
+	a, err := auth.New(auth.Config{JWTSecret: secret, JWTIssuer: "health-api"})
+	if err != nil {
+		t.Fatalf("Should be able to construct auth: %s", err)
+	}
+
+	valid := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "jane",
+			Issuer:    "health-api",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles:      []string{auth.RoleOperator},
+		Tenant:     "shop",
+		Namespaces: []string{"shop"},
+	}
+
+	for _, method := range []jwt.SigningMethod{jwt.SigningMethodHS256, jwt.SigningMethodHS384, jwt.SigningMethodHS512} {
+		claims, err := a.Authenticate(sign(t, method, []byte(secret), valid))
+		if err != nil {
+			t.Errorf("Should accept a token signed with %s: %s", method.Alg(), err)
+			continue
+		}
+		if claims.Subject != "jane" || claims.Tenant != "shop" || !claims.HasRole(auth.RoleOperator) {
+			t.Errorf("Should return the token's claims for %s, got %+v", method.Alg(), claims)
+		}
+	}
+
+	expired := valid
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	noExpiry := valid
+	noExpiry.ExpiresAt = nil
+
+	otherIssuer := valid
+	otherIssuer.Issuer = "someone-else"
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("Should be able to build an unsigned token: %s", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "missing", token: ""},
+		{name: "garbage", token: "not-a-token"},
+		{name: "wrong secret", token: sign(t, jwt.SigningMethodHS256, []byte("other-secret"), valid)},
+		{name: "alg none", token: none},
+		{name: "expired", token: sign(t, jwt.SigningMethodHS256, []byte(secret), expired)},
+		{name: "no expiry", token: sign(t, jwt.SigningMethodHS256, []byte(secret), noExpiry)},
+		{name: "other issuer", token: sign(t, jwt.SigningMethodHS256, []byte(secret), otherIssuer)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.Authenticate(tt.token); !errors.Is(err, auth.ErrUnauthenticated) {
+				t.Errorf("Should reject the token as unauthenticated, got %v", err)
+			}
+		})
+	}
+}
+
+func Test_APIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	data := `keys:
+  - name: ci
+    key: ci-key
+    role: operator
+  - name: shop-dashboard
+    key: shop-key
+    role: viewer
+    tenant: shop
+    namespaces: [shop, shop-staging]
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Should be able to write the key file: %s", err)
+	}
+
+	keys, err := auth.LoadAPIKeys(path)
+	if err != nil {
+		t.Fatalf("Should be able to load the keys: %s", err)
+	}
+
+	// Without a JWT secret only the keys are accepted.
+	a, err := auth.New(auth.Config{APIKeys: keys})
+	if err != nil {
+		t.Fatalf("Should be able to construct auth: %s", err)
+	}
+
+	claims, err := a.Authenticate("shop-key")
+	if err != nil {
+		t.Fatalf("Should accept a known key: %s", err)
+	}
+	if claims.Subject != "shop-dashboard" || claims.Tenant != "shop" || len(claims.Namespaces) != 2 || !claims.HasRole(auth.RoleViewer) {
+		t.Errorf("Should resolve the key to its name, tenant and role, got %+v", claims)
+	}
+
+	if _, err := a.Authenticate("shop-ke"); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Should reject an unknown key, got %v", err)
+	}
+
+	if _, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{Name: "ci", Key: "k", Role: "root"}}}); err == nil {
+		t.Error("Should reject a key with an unknown role")
+	}
+
+	if _, err := auth.New(auth.Config{APIKeys: []auth.APIKey{{Name: "ci", Role: auth.RoleViewer}}}); err == nil {
+		t.Error("Should reject a key without a key")
+	}
+
+	if a, err := auth.New(auth.Config{}); a != nil || err != nil {
+		t.Errorf("Should leave the API open without credentials, got %v %v", a, err)
+	}
+
+	if _, err := auth.LoadAPIKeys(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Should fail on a missing key file")
+	}
+}
+
+func Test_Roles(t *testing.T) {
+	tests := []struct {
+		roles []string
+		want  map[string]bool
+	}{
+		{roles: []string{auth.RoleViewer}, want: map[string]bool{auth.RoleViewer: true, auth.RoleOperator: false, auth.RoleAdmin: false}},
+		{roles: []string{auth.RoleOperator}, want: map[string]bool{auth.RoleViewer: true, auth.RoleOperator: true, auth.RoleAdmin: false}},
+		{roles: []string{auth.RoleAdmin}, want: map[string]bool{auth.RoleViewer: true, auth.RoleOperator: true, auth.RoleAdmin: true}},
+		{roles: []string{"guest"}, want: map[string]bool{auth.RoleViewer: false, auth.RoleOperator: false, auth.RoleAdmin: false}},
+		{roles: nil, want: map[string]bool{auth.RoleViewer: false}},
+	}
+
+	a, err := auth.New(auth.Config{JWTSecret: secret})
+	if err != nil {
+		t.Fatalf("Should be able to construct auth: %s", err)
+	}
+
+	for _, tt := range tests {
+		claims := auth.Claims{Roles: tt.roles}
+
+		for role, want := range tt.want {
+			if got := claims.HasRole(role); got != want {
+				t.Errorf("Should report %v having %s as %t, got %t", tt.roles, role, want, got)
+			}
+
+			err := a.Authorize(claims, role)
+			if want && err != nil {
+				t.Errorf("Should authorize %v for %s, got %v", tt.roles, role, err)
+			}
+			if !want && !errors.Is(err, auth.ErrForbidden) {
+				t.Errorf("Should forbid %v for %s, got %v", tt.roles, role, err)
+			}
+		}
+	}
+}
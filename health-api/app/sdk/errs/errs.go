@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+
+	"health-api/foundation/validate"
 )
 
 // ErrCode represents the type of error.
@@ -31,6 +33,7 @@ const (
 	Unavailable
 	DataLoss
 	InternalOnlyLog // Internal error that should not be exposed to clients
+	PreconditionFailed
 )
 
 // String implements the Stringer interface.
@@ -72,35 +75,77 @@ func (c ErrCode) String() string {
 		return "DataLoss"
 	case InternalOnlyLog:
 		return "InternalOnlyLog"
+	case PreconditionFailed:
+		return "PreconditionFailed"
 	default:
 		return "Unknown"
 	}
 }
 
-// Error represents an application error.
+// Error represents an application error. Fields optionally reports which
+// request fields failed validation and why. The original error is kept so
+// errors.Is and errors.As still match the errors it wraps.
 type Error struct {
-	Code     ErrCode `json:"code"`
-	Message  string  `json:"message"`
-	FuncName string  `json:"-"`
-	FileName string  `json:"-"`
+	Code     ErrCode           `json:"code"`
+	Message  string            `json:"message"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	FuncName string            `json:"-"`
+	FileName string            `json:"-"`
+	err      error
 }
 
-// New creates a new Error with caller information.
+// New creates a new Error wrapping err, with caller information. The field
+// errors of an Error or validate.Errors wrapped by err are kept.
 func New(code ErrCode, err error) *Error {
 	pc, filename, line, _ := runtime.Caller(1)
 	funcName := runtime.FuncForPC(pc).Name()
 
+	var fields map[string]string
+	var e *Error
+	var ve validate.Errors
+	switch {
+	case errors.As(err, &e):
+		fields = e.Fields
+	case errors.As(err, &ve):
+		fields = ve
+	}
+
 	return &Error{
 		Code:     code,
 		Message:  err.Error(),
+		Fields:   fields,
 		FuncName: funcName,
 		FileName: fmt.Sprintf("%s:%d", filename, line),
+		err:      err,
 	}
 }
 
-// Newf creates a new Error with formatted message.
+// FieldErrors creates an InvalidArgument error reporting the fields, keyed
+// by name, that failed validation.
+func FieldErrors(fields map[string]string) *Error {
+	pc, filename, line, _ := runtime.Caller(1)
+	funcName := runtime.FuncForPC(pc).Name()
+
+	return &Error{
+		Code:     InvalidArgument,
+		Message:  validate.Errors(fields).Error(),
+		Fields:   fields,
+		FuncName: funcName,
+		FileName: fmt.Sprintf("%s:%d", filename, line),
+	}
+}
+
+// Newf creates a new Error with formatted message. Use %w to keep the
+// wrapped error matchable.
 func Newf(code ErrCode, format string, v ...any) *Error {
-	return New(code, fmt.Errorf(format, v...))
+	pc, filename, line, _ := runtime.Caller(1)
+	funcName := runtime.FuncForPC(pc).Name()
+
+	e := New(code, fmt.Errorf(format, v...))
+	e.FuncName = funcName
+	e.FileName = fmt.Sprintf("%s:%d", filename, line)
+
+	return e
 }
 
 // Error implements the error interface.
@@ -108,6 +153,11 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the wrapped error.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
 // Encode implements the web.Encoder interface.
 func (e *Error) Encode() ([]byte, string, error) {
 	data, err := json.Marshal(e)
@@ -142,6 +192,7 @@ var httpStatus = map[ErrCode]int{
 	Unavailable:        http.StatusServiceUnavailable,
 	DataLoss:           http.StatusInternalServerError,
 	InternalOnlyLog:    http.StatusInternalServerError,
+	PreconditionFailed: http.StatusPreconditionFailed,
 }
 
 // IsError checks if the error is an Error type.
@@ -0,0 +1,67 @@
+package errs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem renders an Error as RFC 7807 problem details. Code, Fields and
+// TraceID are extension members.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	TraceID  string            `json:"trace_id,omitempty"`
+
+	err *Error
+}
+
+// Problem returns the problem details of the error, identifying the
+// occurrence by the request path and trace ID.
+func (e *Error) Problem(instance string, traceID string) *Problem {
+	status := e.HTTPStatus()
+
+	return &Problem{
+		Type:     "urn:health-api:error:" + e.Code.String(),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code.String(),
+		Fields:   e.Fields,
+		TraceID:  traceID,
+		err:      e,
+	}
+}
+
+// Error implements the error interface.
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+// Unwrap returns the Error the problem was rendered from.
+func (p *Problem) Unwrap() error {
+	return p.err
+}
+
+// Encode implements the web.Encoder interface.
+func (p *Problem) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal problem: %w", err)
+	}
+	return data, ProblemContentType, nil
+}
+
+// HTTPStatus returns the HTTP status code for the problem.
+func (p *Problem) HTTPStatus() int {
+	return p.Status
+}
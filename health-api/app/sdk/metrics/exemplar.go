@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Observe records v on o. When ctx carries a sampled span of our own
+// tracer, its trace ID is attached as exemplar so Grafana can jump from a
+// latency bucket to the trace. Trace contexts merely propagated by a caller
+// are ignored; their traces may not be in our backend.
+func Observe(ctx context.Context, o prometheus.Observer, v float64) {
+	span := trace.SpanFromContext(ctx)
+	sc := span.SpanContext()
+
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && span.IsRecording() && sc.IsSampled() {
+		eo.ObserveWithExemplar(v, prometheus.Labels{"trace_id": sc.TraceID().String()})
+		return
+	}
+
+	o.Observe(v)
+}
+
+// Handler serves the default registry. OpenMetrics is negotiated when the
+// scraper asks for it, since exemplars are only exposed in that format.
+func Handler() http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+			EnableOpenMetrics: true,
+		}),
+	)
+}
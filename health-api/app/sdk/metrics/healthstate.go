@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// healthStatuses are the statuses exposed by health_target_status.
+var healthStatuses = []healthbus.Status{
+	healthbus.StatusHealthy,
+	healthbus.StatusDegraded,
+	healthbus.StatusDown,
+	healthbus.StatusUnknown,
+	healthbus.StatusFlapping,
+	healthbus.StatusMaintenance,
+	healthbus.StatusPaused,
+}
+
+var healthLabels = []string{"target", "probe", "team", "namespace"}
+
+var (
+	targetUpDesc = prometheus.NewDesc(
+		"health_target_up",
+		"Whether the target is up (healthy or degraded: 1, down: 0) as aggregated by the health API",
+		healthLabels, nil,
+	)
+
+	targetStatusDesc = prometheus.NewDesc(
+		"health_target_status",
+		"Current status of the target, 1 for the status it is in",
+		append(healthLabels, "status"), nil,
+	)
+
+	targetLatencyDesc = prometheus.NewDesc(
+		"health_target_latency_seconds",
+		"Duration of the target's latest check in seconds",
+		healthLabels, nil,
+	)
+)
+
+// healthState exports the health snapshot as Prometheus metrics.
+type healthState struct {
+	query func(ctx context.Context) ([]healthbus.HealthCheck, bool)
+}
+
+// RegisterHealthState exposes the aggregated health of every target, read
+// from the snapshot on each scrape, so other Prometheus instances can
+// scrape the merged view of all stores. Nothing is exported before the
+// first sync, so a scrape never reaches the backends.
+func RegisterHealthState(query func(ctx context.Context) ([]healthbus.HealthCheck, bool)) {
+	prometheus.MustRegister(healthState{query: query})
+}
+
+// Describe implements prometheus.Collector.
+func (h healthState) Describe(ch chan<- *prometheus.Desc) {
+	ch <- targetUpDesc
+	ch <- targetStatusDesc
+	ch <- targetLatencyDesc
+}
+
+// Collect implements prometheus.Collector.
+func (h healthState) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	checks, ok := h.query(ctx)
+	if !ok {
+		return
+	}
+
+	// A target reported twice would fail the whole scrape.
+	seen := make(map[string]bool, len(checks))
+
+	for _, check := range checks {
+		if seen[check.Target] {
+			continue
+		}
+		seen[check.Target] = true
+
+		labels := []string{check.Target, check.Probe, check.Team, check.Namespace}
+
+		switch check.Status {
+		case healthbus.StatusHealthy, healthbus.StatusDegraded:
+			ch <- prometheus.MustNewConstMetric(targetUpDesc, prometheus.GaugeValue, 1, labels...)
+		case healthbus.StatusDown:
+			ch <- prometheus.MustNewConstMetric(targetUpDesc, prometheus.GaugeValue, 0, labels...)
+		}
+
+		for _, status := range healthStatuses {
+			var v float64
+			if check.Status == status {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(targetStatusDesc, prometheus.GaugeValue, v, append(labels, string(status))...)
+		}
+
+		if check.DurationSeconds > 0 {
+			ch <- prometheus.MustNewConstMetric(targetLatencyDesc, prometheus.GaugeValue, check.DurationSeconds, labels...)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package metrics
 
 import (
+	"math"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -13,7 +16,7 @@ var (
 			Name: "health_api_http_requests_total",
 			Help: "Total number of HTTP requests",
 		},
-		[]string{"method", "path", "status"},
+		[]string{"method", "path", "status", "tenant"},
 	)
 
 	HTTPRequestDuration = promauto.NewHistogramVec(
@@ -22,7 +25,7 @@ var (
 			Help:    "HTTP request duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"method", "path"},
+		[]string{"method", "path", "tenant"},
 	)
 
 	// Application metrics
@@ -31,7 +34,7 @@ var (
 			Name: "health_api_http_errors_total",
 			Help: "Total number of HTTP errors",
 		},
-		[]string{"method", "path", "code"},
+		[]string{"method", "path", "code", "tenant"},
 	)
 
 	PanicsTotal = promauto.NewCounter(
@@ -66,4 +69,30 @@ var (
 		},
 		[]string{"endpoint"},
 	)
+
+	HeartbeatDatagramsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "health_api_heartbeat_datagrams_total",
+			Help: "Total number of UDP heartbeat datagrams received",
+		},
+		[]string{"result"},
+	)
 )
+
+// RegisterSnapshotAge exposes the age of the health snapshot served by the
+// background poller. The gauge is NaN until the first sync succeeds.
+func RegisterSnapshotAge(snapshotTime func() (time.Time, bool)) {
+	promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "health_api_snapshot_age_seconds",
+			Help: "Age of the health snapshot in seconds",
+		},
+		func() float64 {
+			takenAt, ok := snapshotTime()
+			if !ok {
+				return math.NaN()
+			}
+			return time.Since(takenAt).Seconds()
+		},
+	)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GrafanaTransport records GrafanaRequestsTotal and GrafanaRequestDuration
+// for every request sent through it.
+type GrafanaTransport struct {
+	base http.RoundTripper
+}
+
+// NewGrafanaTransport wraps base, or http.DefaultTransport when base is nil.
+func NewGrafanaTransport(base http.RoundTripper) *GrafanaTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &GrafanaTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *GrafanaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.URL.Path
+	start := time.Now()
+
+	resp, err := t.base.RoundTrip(req)
+
+	Observe(req.Context(), GrafanaRequestDuration.WithLabelValues(endpoint), time.Since(start).Seconds())
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	GrafanaRequestsTotal.WithLabelValues(endpoint, status).Inc()
+
+	return resp, err
+}
@@ -0,0 +1,83 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/errs"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/web"
+)
+
+type claimsKey int
+
+const claimKey claimsKey = 1
+
+func setClaims(ctx context.Context, claims auth.Claims) context.Context {
+	return context.WithValue(ctx, claimKey, claims)
+}
+
+// GetClaims returns the claims of the authenticated caller.
+func GetClaims(ctx context.Context) auth.Claims {
+	v, ok := ctx.Value(claimKey).(auth.Claims)
+	if !ok {
+		return auth.Claims{}
+	}
+	return v
+}
+
+// Authenticate resolves the bearer token of the request to the caller's
+// claims and scopes the request to the caller's tenant. A nil Auth disables
+// authentication.
+func Authenticate(a *auth.Auth) web.Middleware {
+	m := func(handler web.HandlerFunc) web.HandlerFunc {
+		if a == nil {
+			return handler
+		}
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+			claims, err := a.Authenticate(token)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			// The tenant is recorded on the request values so outer
+			// middleware, such as metrics, can label the request.
+			web.GetValues(ctx).Tenant = claims.Tenant
+
+			ctx = setClaims(ctx, claims)
+			ctx = tenant.Set(ctx, tenant.Scope{
+				Name:       claims.Tenant,
+				Namespaces: claims.Namespaces,
+			})
+
+			return handler(ctx, r)
+		}
+		return h
+	}
+	return m
+}
+
+// Authorize requires the authenticated caller to hold role. It must run
+// after Authenticate. A nil Auth disables authorization.
+func Authorize(a *auth.Auth, role string) web.Middleware {
+	m := func(handler web.HandlerFunc) web.HandlerFunc {
+		if a == nil {
+			return handler
+		}
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if err := a.Authorize(GetClaims(ctx), role); err != nil {
+				return errs.New(errs.PermissionDenied, err)
+			}
+
+			return handler(ctx, r)
+		}
+		return h
+	}
+	return m
+}
@@ -3,26 +3,109 @@ package mid
 import (
 	"context"
 	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"health-api/foundation/web"
 )
 
-// Cors adds CORS headers to responses.
-func Cors(origin string) web.Middleware {
+// CORSPolicy describes which cross-origin requests are allowed.
+type CORSPolicy struct {
+	// AllowedOrigins lists exact origins. "*" allows any origin.
+	AllowedOrigins []string
+
+	// AllowedOriginPatterns matches origins by regular expression, e.g.
+	// `^https://[a-z0-9-]+\.example\.com$`.
+	AllowedOriginPatterns []*regexp.Regexp
+
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	MaxAge           time.Duration
+	AllowCredentials bool
+}
+
+// DefaultCORSPolicy returns a policy allowing the given origins with the
+// methods and headers used by the API.
+func DefaultCORSPolicy(origins ...string) CORSPolicy {
+	return CORSPolicy{
+		AllowedOrigins: origins,
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "If-None-Match", "If-Match", "X-Request-Timeout"},
+		ExposedHeaders: []string{"ETag"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// allowOrigin returns the value for Access-Control-Allow-Origin, or an empty
+// string if the origin is not allowed.
+func (p CORSPolicy) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+
+	if slices.Contains(p.AllowedOrigins, "*") {
+		// Credentials cannot be combined with a wildcard origin.
+		if p.AllowCredentials {
+			return origin
+		}
+		return "*"
+	}
+
+	if slices.Contains(p.AllowedOrigins, origin) {
+		return origin
+	}
+
+	for _, re := range p.AllowedOriginPatterns {
+		if re.MatchString(origin) {
+			return origin
+		}
+	}
+
+	return ""
+}
+
+// Cors applies the CORS policy to responses and answers preflight requests.
+func Cors(policy CORSPolicy) web.Middleware {
+	methods := strings.Join(policy.AllowedMethods, ", ")
+	headers := strings.Join(policy.AllowedHeaders, ", ")
+	exposed := strings.Join(policy.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(policy.MaxAge.Seconds()))
+
 	m := func(handler web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
-			// Get the response writer
 			w := web.GetWriter(ctx)
-			if w != nil {
-				w.Header().Set("Access-Control-Allow-Origin", origin)
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-				// Handle preflight requests
-				if r.Method == http.MethodOptions {
-					w.WriteHeader(http.StatusOK)
-					return nil
+			if w == nil {
+				return handler(ctx, r)
+			}
+
+			w.Header().Add("Vary", "Origin")
+
+			allowed := policy.allowOrigin(r.Header.Get("Origin"))
+			if allowed != "" {
+				w.Header().Set("Access-Control-Allow-Origin", allowed)
+				if policy.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+				if exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposed)
+				}
+			}
+
+			// Handle preflight requests
+			if r.Method == http.MethodOptions {
+				if allowed != "" {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+					if policy.MaxAge > 0 {
+						w.Header().Set("Access-Control-Max-Age", maxAge)
+					}
 				}
+				w.WriteHeader(http.StatusNoContent)
+				return nil
 			}
 
 			return handler(ctx, r)
@@ -0,0 +1,55 @@
+package mid
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/foundation/web"
+)
+
+// Deprecation announces that routes are deprecated in favour of a newer
+// API version. A zero Since leaves the routes undeprecated.
+type Deprecation struct {
+	// Since is when the routes were deprecated.
+	Since time.Time
+
+	// Sunset is when the routes will be removed, if already decided.
+	Sunset time.Time
+
+	// From and To replace the version prefix of the request path to link
+	// the successor, e.g. "/api/v1/" and "/api/v2/".
+	From string
+	To   string
+}
+
+// Deprecated sets the Deprecation (RFC 9745) and Sunset (RFC 8594) headers
+// on responses, with a Link to the successor version of the route.
+func Deprecated(d Deprecation) web.Middleware {
+	m := func(handler web.HandlerFunc) web.HandlerFunc {
+		if d.Since.IsZero() {
+			return handler
+		}
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if w := web.GetWriter(ctx); w != nil {
+				w.Header().Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+
+				if !d.Sunset.IsZero() {
+					w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+				}
+
+				if path := r.URL.EscapedPath(); d.From != "" && strings.HasPrefix(path, d.From) {
+					successor := d.To + strings.TrimPrefix(path, d.From)
+					w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+				}
+			}
+
+			return handler(ctx, r)
+		}
+		return h
+	}
+	return m
+}
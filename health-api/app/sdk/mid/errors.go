@@ -4,14 +4,17 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 
 	"health-api/app/sdk/errs"
 	"health-api/foundation/logger"
 	"health-api/foundation/web"
 )
 
-// Errors handles errors from handlers.
-func Errors(log *logger.Logger) web.Middleware {
+// Errors handles errors from handlers. Errors are rendered as RFC 7807
+// problem details when problemDetails is set, the client accepts
+// application/problem+json or the route belongs to the v2 API.
+func Errors(log *logger.Logger, problemDetails bool) web.Middleware {
 	m := func(handler web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
 			resp := handler(ctx, r)
@@ -20,11 +23,22 @@ func Errors(log *logger.Logger) web.Middleware {
 				return resp
 			}
 
+			err := resp.(error)
+
 			var appErr *errs.Error
-			if !errors.As(resp.(error), &appErr) {
+			if !errors.As(err, &appErr) {
 				appErr = errs.Newf(errs.Internal, "internal server error")
 			}
 
+			// A handler that failed because the request context ended
+			// reports that, whatever code it chose.
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				appErr = withCode(appErr, errs.DeadlineExceeded)
+			case errors.Is(err, context.Canceled):
+				appErr = withCode(appErr, errs.Canceled)
+			}
+
 			log.Error(ctx, "error handling request",
 				"code", appErr.Code.String(),
 				"message", appErr.Message,
@@ -37,9 +51,21 @@ func Errors(log *logger.Logger) web.Middleware {
 				appErr = errs.Newf(errs.Internal, "internal server error")
 			}
 
+			// The v2 API only speaks problem details.
+			if problemDetails || strings.HasPrefix(r.URL.Path, "/api/v2/") || strings.Contains(r.Header.Get("Accept"), errs.ProblemContentType) {
+				return appErr.Problem(r.URL.Path, web.GetValues(ctx).TraceID)
+			}
+
 			return appErr
 		}
 		return h
 	}
 	return m
 }
+
+// withCode returns a copy of the error with the code replaced.
+func withCode(e *errs.Error, code errs.ErrCode) *errs.Error {
+	c := *e
+	c.Code = code
+	return &c
+}
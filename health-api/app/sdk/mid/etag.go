@@ -0,0 +1,85 @@
+package mid
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"health-api/app/sdk/errs"
+	"health-api/foundation/web"
+)
+
+// ETag adds conditional request support to GET and HEAD handlers. The
+// response is encoded once and hashed; if the client's If-None-Match
+// matches the hash a 304 Not Modified is returned without a body.
+func ETag() web.Middleware {
+	m := func(handler web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			resp := handler(ctx, r)
+
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				return resp
+			}
+
+			if resp == nil || checkIsError(resp) {
+				return resp
+			}
+
+			status := http.StatusOK
+			if v, ok := resp.(interface{ HTTPStatus() int }); ok {
+				status = v.HTTPStatus()
+			}
+			if status != http.StatusOK {
+				return resp
+			}
+
+			data, contentType, err := resp.Encode()
+			if err != nil {
+				return errs.Newf(errs.Internal, "encode: %w", err)
+			}
+
+			sum := sha256.Sum256(data)
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+			if w := web.GetWriter(ctx); w != nil {
+				w.Header().Set("ETag", etag)
+			}
+
+			if etagMatch(r.Header.Get("If-None-Match"), etag) {
+				return encoded{contentType: contentType, status: http.StatusNotModified}
+			}
+
+			return encoded{data: data, contentType: contentType, status: status}
+		}
+		return h
+	}
+	return m
+}
+
+// etagMatch reports whether the If-None-Match header value matches etag.
+func etagMatch(header string, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// encoded is a response that has already been encoded.
+type encoded struct {
+	data        []byte
+	contentType string
+	status      int
+}
+
+func (e encoded) Encode() ([]byte, string, error) {
+	return e.data, e.contentType, nil
+}
+
+func (e encoded) HTTPStatus() int {
+	return e.status
+}
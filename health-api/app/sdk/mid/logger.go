@@ -4,14 +4,95 @@ package mid
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"health-api/foundation/logger"
 	"health-api/foundation/web"
 )
 
+// Set of optional access log fields.
+const (
+	FieldRemote    = "remote"
+	FieldQuery     = "query"
+	FieldUserAgent = "user_agent"
+	FieldBytes     = "bytes"
+	FieldRequestID = "request_id"
+	FieldTenant    = "tenant"
+)
+
+// redacted replaces the value of sensitive query parameters.
+const redacted = "REDACTED"
+
+// AccessLog configures the request lines written by Logger.
+type AccessLog struct {
+	// Fields lists the optional fields logged in addition to method, path,
+	// status and duration. See the Field constants.
+	Fields []string
+
+	// RedactParams lists query parameters whose values are never logged.
+	// Matching is case-insensitive.
+	RedactParams []string
+
+	// SkipPaths lists request paths that are not logged at all, e.g.
+	// Kubernetes probes.
+	SkipPaths []string
+}
+
+// DefaultAccessLog returns the access log configuration used when none is
+// provided.
+func DefaultAccessLog() AccessLog {
+	return AccessLog{
+		Fields:       []string{FieldRemote, FieldQuery},
+		RedactParams: []string{"token", "access_token", "api_key", "apikey", "password", "secret"},
+		SkipPaths:    []string{"/liveness", "/readiness", "/healthz"},
+	}
+}
+
+// has reports whether the optional field is enabled.
+func (c AccessLog) has(field string) bool {
+	return slices.Contains(c.Fields, field)
+}
+
+// skip reports whether requests for path are not logged.
+func (c AccessLog) skip(path string) bool {
+	return slices.Contains(c.SkipPaths, path)
+}
+
+// redactQuery returns the raw query with sensitive parameter values
+// replaced.
+func (c AccessLog) redactQuery(rawQuery string) string {
+	if rawQuery == "" || len(c.RedactParams) == 0 {
+		return rawQuery
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// An unparsable query may still carry secrets.
+		return redacted
+	}
+
+	changed := false
+	for name := range values {
+		if slices.ContainsFunc(c.RedactParams, func(p string) bool { return strings.EqualFold(p, name) }) {
+			for i := range values[name] {
+				values[name][i] = redacted
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return rawQuery
+	}
+
+	return values.Encode()
+}
+
 // Logger logs each request.
-func Logger(log *logger.Logger) web.Middleware {
+func Logger(log *logger.Logger, cfg AccessLog) web.Middleware {
 	m := func(handler web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
 			v := &web.Values{
@@ -20,20 +101,51 @@ func Logger(log *logger.Logger) web.Middleware {
 			}
 			ctx = web.SetValues(ctx, v)
 
-			log.Info(ctx, "request started",
+			if cfg.skip(r.URL.Path) {
+				return handler(ctx, r)
+			}
+
+			fields := []any{
 				"method", r.Method,
 				"path", r.URL.Path,
-				"remote", r.RemoteAddr,
-			)
+			}
+			if cfg.has(FieldQuery) && r.URL.RawQuery != "" {
+				fields = append(fields, "query", cfg.redactQuery(r.URL.RawQuery))
+			}
+			if cfg.has(FieldRemote) {
+				fields = append(fields, "remote", r.RemoteAddr)
+			}
+			if cfg.has(FieldUserAgent) {
+				fields = append(fields, "user_agent", r.UserAgent())
+			}
+			if id := web.RequestID(r); cfg.has(FieldRequestID) && id != "" {
+				fields = append(fields, "request_id", id)
+			}
+
+			log.Info(ctx, "request started", fields...)
 
 			resp := handler(ctx, r)
 
-			log.Info(ctx, "request completed",
-				"method", r.Method,
-				"path", r.URL.Path,
-				"status", v.StatusCode,
+			// Write the response here rather than after the middleware
+			// returns so the size is known when the request is logged.
+			if cfg.has(FieldBytes) {
+				if err := web.Respond(ctx, web.GetWriter(ctx), resp); err != nil {
+					log.Error(ctx, "request respond", "error", err)
+				}
+			}
+
+			fields = append(fields,
+				"status", web.Status(ctx, resp),
 				"duration", time.Since(v.Now).String(),
 			)
+			if cfg.has(FieldTenant) && v.Tenant != "" {
+				fields = append(fields, "tenant", v.Tenant)
+			}
+			if w := web.GetResponseWriter(ctx); cfg.has(FieldBytes) && w != nil {
+				fields = append(fields, "bytes", w.Size())
+			}
+
+			log.Info(ctx, "request completed", fields...)
 
 			return resp
 		}
@@ -23,23 +23,22 @@ func Prometheus() web.Middleware {
 			// Calculate duration
 			duration := time.Since(start).Seconds()
 
-			// Get path and method
-			path := r.URL.Path
+			// Get the route template and method. The raw path would create
+			// a series per target name.
+			path := web.GetRoute(ctx)
 			method := r.Method
 
-			// Record duration
-			metrics.HTTPRequestDuration.WithLabelValues(method, path).Observe(duration)
+			// The tenant is set by Authenticate further down the chain.
+			tenant := web.GetValues(ctx).Tenant
+
+			// Record duration, linked to the request's trace
+			metrics.Observe(ctx, metrics.HTTPRequestDuration.WithLabelValues(method, path, tenant), duration)
 
 			// Get status code
-			statusCode := 200
-			if resp != nil {
-				if v, ok := resp.(interface{ HTTPStatus() int }); ok {
-					statusCode = v.HTTPStatus()
-				}
-			}
+			statusCode := web.Status(ctx, resp)
 
 			// Record request count
-			metrics.HTTPRequestsTotal.WithLabelValues(method, path, strconv.Itoa(statusCode)).Inc()
+			metrics.HTTPRequestsTotal.WithLabelValues(method, path, strconv.Itoa(statusCode), tenant).Inc()
 
 			// Record errors if status >= 400
 			if statusCode >= 400 {
@@ -47,7 +46,7 @@ func Prometheus() web.Middleware {
 				if e, ok := resp.(*errs.Error); ok {
 					errCode = e.Code.String()
 				}
-				metrics.HTTPErrorsTotal.WithLabelValues(method, path, errCode).Inc()
+				metrics.HTTPErrorsTotal.WithLabelValues(method, path, errCode, tenant).Inc()
 			}
 
 			return resp
@@ -0,0 +1,115 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-api/app/sdk/errs"
+	"health-api/foundation/web"
+)
+
+// Timeout bounds the handler context with the given budget. Clients can
+// shorten it with an X-Request-Timeout header (a duration such as "2s", or
+// seconds) or a grpc-timeout header (such as "500m"); a longer client
+// timeout leaves the budget unchanged. A handler that fails because the
+// budget ran out is reported as a 504 DeadlineExceeded error. A zero or
+// negative budget leaves requests bounded by client headers only.
+func Timeout(budget time.Duration) web.Middleware {
+	m := func(handler web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			limit, err := requestTimeout(r)
+			if err != nil {
+				return errs.New(errs.InvalidArgument, err)
+			}
+
+			if budget > 0 && (limit <= 0 || budget < limit) {
+				limit = budget
+			}
+
+			if limit <= 0 {
+				return handler(ctx, r)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, limit)
+			defer cancel()
+
+			resp := handler(ctx, r)
+
+			if checkIsError(resp) && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errs.Newf(errs.DeadlineExceeded, "request exceeded its %s budget", limit)
+			}
+
+			return resp
+		}
+		return h
+	}
+	return m
+}
+
+// requestTimeout returns the timeout asked for by the client, or 0 when it
+// asks for none. X-Request-Timeout takes precedence over grpc-timeout.
+func requestTimeout(r *http.Request) (time.Duration, error) {
+	if v := r.Header.Get("X-Request-Timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			secs, serr := strconv.ParseFloat(v, 64)
+			if serr != nil {
+				return 0, fmt.Errorf("invalid X-Request-Timeout %q", v)
+			}
+			d = time.Duration(secs * float64(time.Second))
+		}
+		if d <= 0 {
+			return 0, fmt.Errorf("invalid X-Request-Timeout %q: must be positive", v)
+		}
+		return d, nil
+	}
+
+	if v := r.Header.Get("grpc-timeout"); v != "" {
+		d, err := parseGRPCTimeout(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid grpc-timeout %q", v)
+		}
+		return d, nil
+	}
+
+	return 0, nil
+}
+
+// grpcUnits maps the unit suffixes of the gRPC over HTTP2 Timeout header.
+var grpcUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// parseGRPCTimeout parses a timeout of at most eight digits followed by a
+// unit, as in "250m" or "5S".
+func parseGRPCTimeout(v string) (time.Duration, error) {
+	if len(v) < 2 || len(v) > 9 {
+		return 0, errors.New("malformed")
+	}
+
+	unit, ok := grpcUnits[v[len(v)-1]]
+	if !ok {
+		return 0, errors.New("unknown unit")
+	}
+
+	n, err := strconv.ParseUint(v[:len(v)-1], 10, 64)
+	if err != nil || n == 0 {
+		return 0, errors.New("malformed")
+	}
+
+	if n > uint64(math.MaxInt64/unit) {
+		return math.MaxInt64, nil
+	}
+
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,37 @@
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"health-api/foundation/logger"
+)
+
+// logLevelHandler reports the logger's level on GET and changes it on PUT
+// or POST with ?level=debug|info|warn|error.
+func logLevelHandler(log *logger.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+
+		case http.MethodPut, http.MethodPost:
+			level, err := logger.ParseLevel(r.URL.Query().Get("level"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			from := log.Level()
+			log.SetLevel(level)
+			log.Info(r.Context(), "loglevel", "status", "level changed", "from", from.String(), "to", level.String())
+
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"level": log.Level().String()})
+	}
+}
@@ -0,0 +1,51 @@
+package mux
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"health-api/app/sdk/metrics"
+	"health-api/foundation/web"
+)
+
+// MetricsConfig controls serving Prometheus metrics on the API listener, for
+// clusters where only the API port is exposed through the Service.
+type MetricsConfig struct {
+	OnAPI bool
+
+	// User and Password enable basic auth on /metrics when set.
+	User     string
+	Password string
+}
+
+// metricsHandler serves the Prometheus registry, optionally behind basic
+// auth.
+func metricsHandler(cfg MetricsConfig) web.HandlerFunc {
+	prom := metrics.Handler()
+
+	h := func(ctx context.Context, r *http.Request) web.Encoder {
+		w := web.GetWriter(ctx)
+		if w == nil {
+			return nil
+		}
+
+		if cfg.User != "" || cfg.Password != "" {
+			user, pass, ok := r.BasicAuth()
+			if !ok || !equal(user, cfg.User) || !equal(pass, cfg.Password) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return nil
+			}
+		}
+
+		prom.ServeHTTP(w, r)
+		return nil
+	}
+
+	return h
+}
+
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -6,17 +6,27 @@ import (
 	"net/http"
 	"net/http/pprof"
 
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/metrics"
 	"health-api/app/sdk/mid"
 	"health-api/foundation/logger"
 	"health-api/foundation/web"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Config contains dependencies needed to construct the server.
 type Config struct {
-	Log    *logger.Logger
-	Tracer trace.Tracer
+	Log     *logger.Logger
+	Tracer  trace.Tracer
+	Auth    *auth.Auth
+	CORS    mid.CORSPolicy
+	Metrics MetricsConfig
+
+	// AccessLog selects the fields and redaction of request log lines.
+	AccessLog mid.AccessLog
+
+	// ProblemDetails renders every error as application/problem+json.
+	ProblemDetails bool
 }
 
 // RouteAdder defines the interface for adding routes to the app.
@@ -25,18 +35,25 @@ type RouteAdder interface {
 }
 
 // WebAPI constructs an HTTP server with the specified configuration.
-func WebAPI(cfg Config, routeAdder RouteAdder, corsOrigin string) *web.App {
+func WebAPI(cfg Config, routeAdder RouteAdder) *web.App {
 	// Create app with middleware stack
 	app := web.NewApp(
 		cfg.Tracer,
-		mid.Logger(cfg.Log),
-		mid.Errors(cfg.Log),
+		mid.Logger(cfg.Log, cfg.AccessLog),
+		mid.Errors(cfg.Log, cfg.ProblemDetails),
 		mid.Prometheus(),
 		mid.Metrics(),
 		mid.Panics(),
-		mid.Cors(corsOrigin),
 	)
 
+	// CORS wraps every route and answers preflight requests
+	app.EnableCORS(mid.Cors(cfg.CORS))
+
+	// Optionally expose Prometheus metrics on the API listener
+	if cfg.Metrics.OnAPI {
+		app.Group("").HandlerFuncNoMid(http.MethodGet, "/metrics", metricsHandler(cfg.Metrics))
+	}
+
 	// Add routes via route adder
 	if routeAdder != nil {
 		routeAdder.Add(app, cfg)
@@ -46,7 +63,7 @@ func WebAPI(cfg Config, routeAdder RouteAdder, corsOrigin string) *web.App {
 }
 
 // DebugMux registers debug and profiling routes.
-func DebugMux() *http.ServeMux {
+func DebugMux(log *logger.Logger) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Register pprof handlers
@@ -60,7 +77,10 @@ func DebugMux() *http.ServeMux {
 	mux.Handle("/debug/vars", expvar.Handler())
 
 	// Register Prometheus metrics handler
-	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Register runtime log level switch
+	mux.HandleFunc("/debug/loglevel", logLevelHandler(log))
 
 	return mux
 }
@@ -0,0 +1,99 @@
+// Package main is a thin alias for deployments that still start the legacy
+// Prometheus-only server. Its endpoints, including /api/v1/metrics/{metric},
+// are served by app/services/health-api; this program maps the legacy
+// settings onto that service's and execs it in its place, so signals,
+// timeouts and shutdown are the service's own.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// service is the name of the layered service's binary.
+const service = "health-api"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "health-api-legacy:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	bin, err := binary()
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{bin}, os.Args[1:]...)
+	if err := syscall.Exec(bin, args, legacyEnv(os.Environ())); err != nil {
+		return fmt.Errorf("exec %s: %w", bin, err)
+	}
+
+	return nil
+}
+
+// binary locates the layered service: HEALTH_API_BIN if set, else a
+// health-api binary next to this one, else one on the PATH.
+func binary() (string, error) {
+	if bin := os.Getenv("HEALTH_API_BIN"); bin != "" {
+		return bin, nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locating executable: %w", err)
+	}
+
+	bin := filepath.Join(filepath.Dir(exe), service)
+	if _, err := os.Stat(bin); err == nil {
+		return bin, nil
+	}
+
+	bin, err = exec.LookPath(service)
+	if err != nil {
+		return "", fmt.Errorf("locating %s, set HEALTH_API_BIN: %w", service, err)
+	}
+
+	return bin, nil
+}
+
+// legacyEnv maps the legacy server's settings onto the service's: PORT
+// becomes API_HOST, and PROMETHEUS_URL keeps its legacy default. Settings
+// the service already has are left alone.
+func legacyEnv(environ []string) []string {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+
+	set := make(map[string]string)
+
+	if port := env["PORT"]; port != "" && env["API_HOST"] == "" {
+		set["API_HOST"] = ":" + port
+	}
+
+	if env["PROMETHEUS_URL"] == "" {
+		set["PROMETHEUS_URL"] = "http://localhost:9090"
+	}
+
+	// An empty entry would shadow one appended after it.
+	out := make([]string, 0, len(environ)+len(set))
+	for _, kv := range environ {
+		if k, _, _ := strings.Cut(kv, "="); set[k] == "" {
+			out = append(out, kv)
+		}
+	}
+	for k, v := range set {
+		out = append(out, k+"="+v)
+	}
+
+	return out
+}
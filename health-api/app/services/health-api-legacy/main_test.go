@@ -0,0 +1,46 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_LegacyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ []string
+		want    []string
+	}{
+		{
+			name:    "defaults",
+			environ: []string{"HOME=/root"},
+			want:    []string{"HOME=/root", "PROMETHEUS_URL=http://localhost:9090"},
+		},
+		{
+			name:    "port",
+			environ: []string{"PORT=9000", "PROMETHEUS_URL=http://prometheus:9090"},
+			want:    []string{"API_HOST=:9000", "PORT=9000", "PROMETHEUS_URL=http://prometheus:9090"},
+		},
+		{
+			name:    "api host wins",
+			environ: []string{"PORT=9000", "API_HOST=:8081", "PROMETHEUS_URL=http://prometheus:9090"},
+			want:    []string{"API_HOST=:8081", "PORT=9000", "PROMETHEUS_URL=http://prometheus:9090"},
+		},
+		{
+			name:    "empty entries",
+			environ: []string{"API_HOST=", "PORT=9000", "PROMETHEUS_URL="},
+			want:    []string{"API_HOST=:9000", "PORT=9000", "PROMETHEUS_URL=http://localhost:9090"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := legacyEnv(tt.environ)
+			slices.Sort(got)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Should map the legacy settings to %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
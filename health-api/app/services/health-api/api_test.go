@@ -0,0 +1,1647 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"health-api/app/domain/healthapp"
+	"health-api/app/domain/proberapp"
+	"health-api/app/domain/systemapp"
+	"health-api/app/sdk/errs"
+	"health-api/app/sdk/mid"
+	"health-api/app/sdk/mux"
+	"health-api/business/domain/alerthistorybus"
+	"health-api/business/domain/annotationbus"
+	"health-api/business/domain/annotationbus/stores/annotationdb"
+	"health-api/business/domain/comparebus"
+	"health-api/business/domain/forecastbus"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/memorystore"
+	"health-api/business/domain/healthbus/stores/multistore"
+	"health-api/business/domain/historybus"
+	"health-api/business/domain/historybus/stores/historydb"
+	"health-api/business/domain/incidentbus"
+	"health-api/business/domain/incidentbus/stores/incidentdb"
+	"health-api/business/domain/ingestbus"
+	"health-api/business/domain/ingestbus/stores/ingestdb"
+	"health-api/business/domain/kubeeventbus"
+	"health-api/business/domain/logbus"
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/domain/maintenancebus/stores/maintenancedb"
+	"health-api/business/domain/notifybus"
+	"health-api/business/domain/oncallbus"
+	"health-api/business/domain/proberbus"
+	"health-api/business/domain/remediationbus"
+	"health-api/business/domain/remediationbus/stores/remediationdb"
+	"health-api/business/domain/reportbus"
+	"health-api/business/domain/snoozebus"
+	"health-api/business/domain/snoozebus/stores/snoozedb"
+	"health-api/business/domain/storagebus"
+	"health-api/business/domain/targetbus"
+	"health-api/business/domain/targetbus/stores/targetdb"
+	"health-api/business/domain/viewbus"
+	"health-api/business/domain/viewbus/stores/viewdb"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ingestSecret authenticates agents pushing results in the tests.
+const ingestSecret = "test-secret"
+
+const heartbeatToken = "0123456789abcdef0123456789abcdef"
+
+// apiTest runs the full API, as wired by Routes, on top of a memorystore.
+type apiTest struct {
+	t         *testing.T
+	srv       *httptest.Server
+	store     *memorystore.Store
+	backup    *memorystore.Store
+	ingestBus *ingestbus.Business
+}
+
+func newAPITest(t *testing.T) *apiTest {
+	log := logger.New(io.Discard, logger.LevelError, "TEST", traceIDFunc)
+	if testing.Verbose() {
+		log = logger.New(os.Stdout, logger.LevelInfo, "TEST", traceIDFunc)
+	}
+
+	db, err := jsondb.Open("")
+	if err != nil {
+		t.Fatalf("Should be able to open the db: %s", err)
+	}
+
+	store := memorystore.NewStore()
+	store.SetChecks(
+		healthbus.HealthCheck{Target: "https://shop.example.com", Status: healthbus.StatusDown, LastChecked: time.Now(), Probe: "http_2xx"},
+		healthbus.HealthCheck{Target: "https://api.example.com", Status: healthbus.StatusHealthy, LastChecked: time.Now(), Probe: "http_2xx"},
+	)
+	store.SetAlerts(
+		healthbus.Alert{Title: "https://shop.example.com is down", State: "firing", Labels: map[string]string{"instance": "https://shop.example.com", "severity": "page"}},
+		healthbus.Alert{Title: "Disk usage high", State: "inactive", Labels: map[string]string{"severity": "warning"}},
+	)
+
+	dlg := delegate.New(log)
+
+	targetStore, err := targetdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the target store: %s", err)
+	}
+	targetBus := targetbus.NewBusiness(log, dlg, targetStore)
+
+	maintenanceStore, err := maintenancedb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the maintenance store: %s", err)
+	}
+	maintenanceBus := maintenancebus.NewBusiness(log, maintenanceStore)
+
+	annotationStore, err := annotationdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the annotation store: %s", err)
+	}
+	annotationBus := annotationbus.NewBusiness(log, annotationStore)
+
+	snoozeStore, err := snoozedb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the snooze store: %s", err)
+	}
+	snoozeBus := snoozebus.NewBusiness(log, snoozeStore)
+
+	viewStore, err := viewdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the view store: %s", err)
+	}
+	viewBus := viewbus.NewBusiness(log, viewStore)
+
+	// A second, empty backend lets one of two backends fail.
+	backup := memorystore.NewStore()
+	backends := multistore.NewStore(log,
+		multistore.Backend{Name: "memory", Storer: store},
+		multistore.Backend{Name: "backup", Storer: backup},
+	)
+
+	healthBus := healthbus.NewBusiness(log, dlg, backends, targetBus, maintenanceBus, snoozeBus, healthbus.Config{Regions: healthbus.RegionConfig{DownQuorum: 2}},
+		healthbus.Dependency{Name: "memory", Required: true, Checker: store},
+	)
+
+	events, stop := context.WithCancel(context.Background())
+	t.Cleanup(stop)
+	go healthBus.RunEvents(events)
+
+	incidentStore, err := incidentdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the incident store: %s", err)
+	}
+	incidentBus := incidentbus.NewBusiness(log, dlg, incidentStore, healthBus)
+
+	historyStore, err := historydb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the history store: %s", err)
+	}
+	historyBus := historybus.NewBusiness(log, dlg, historyStore, 0)
+
+	// The shop was healthy two hours ago, before the tests observe it down.
+	if err := historyStore.Create(context.Background(), historybus.Change{ID: "seed-shop", Target: "https://shop.example.com", From: healthbus.StatusUnknown, To: healthbus.StatusHealthy, At: time.Now().Add(-2 * time.Hour).UTC()}); err != nil {
+		t.Fatalf("Should be able to seed the history: %s", err)
+	}
+
+	// The shop fired for ten minutes an hour ago and fires again now.
+	now := time.Now().UTC()
+	shop := map[string]string{"target": "https://shop.example.com"}
+	alertHistoryBus := alerthistorybus.NewBusiness(log, alertHistoryStore{
+		{Time: now.Add(-time.Hour), RuleUID: "probe-shop", Title: "ProbeFailed", Labels: shop, Previous: "Pending", Current: "Alerting"},
+		{Time: now.Add(-50 * time.Minute), RuleUID: "probe-shop", Title: "ProbeFailed", Labels: shop, Previous: "Alerting", Current: "Normal"},
+		{Time: now.Add(-time.Minute), RuleUID: "probe-shop", Title: "ProbeFailed", Labels: shop, Previous: "Normal", Current: "Alerting"},
+	})
+
+	logBus := logbus.NewBusiness(log, logStore{}, targetBus, historyBus, logbus.Config{Window: 5 * time.Minute, Limit: 100})
+
+	onCallBus := oncallbus.NewBusiness(log, onCallStore{}, oncallbus.Config{
+		Schedules: map[string]string{oncallbus.DefaultSchedule: "sre-primary", "payments": "payments-primary"},
+		CacheTTL:  time.Minute,
+	})
+
+	forecastBus := forecastbus.NewBusiness(log, forecastStore{}, forecastbus.Config{Lookback: 2 * 24 * time.Hour, Step: time.Hour})
+
+	compareBus := comparebus.NewBusiness(log, statsStore{}, healthBus)
+
+	storageBus, err := storagebus.NewBusiness(log, volumeStore{}, storagebus.Threshold{Warning: 80, Critical: 90}, []storagebus.Threshold{{Match: "shop/logs-*", Warning: 95, Critical: 99}})
+	if err != nil {
+		t.Fatalf("Should be able to construct the storage business: %s", err)
+	}
+
+	// The shop's SLA only counts weekday office hours in Berlin.
+	reportBus, err := reportbus.NewBusiness(log, incidentBus, targetBus, maintenanceBus, reportbus.Config{
+		Calendars: []reportbus.Calendar{
+			{Name: "business", Timezone: "Europe/Berlin", Start: "08:00", End: "20:00", Targets: []string{"https://shop.example.com"}},
+		},
+		ExcludeMaintenance: true,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to construct the report business: %s", err)
+	}
+
+	kubeEventBus := kubeeventbus.NewBusiness(log, kubeEventStore{}, targetBus, historyBus, 15*time.Minute)
+
+	remediationStore, err := remediationdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the remediation store: %s", err)
+	}
+	remediationBus := remediationbus.NewBusiness(log, dlg, remediationStore, nil, nil)
+
+	ingestStore, err := ingestdb.NewStore(log, db)
+	if err != nil {
+		t.Fatalf("Should be able to open the ingest store: %s", err)
+	}
+	ingestBus := ingestbus.NewBusiness(log, ingestStore, 5*time.Minute)
+
+	// Jobs ping the nightly backup with its token, never its name.
+	proberBus := proberbus.NewBusiness(log, []proberbus.Check{
+		{Name: "nightly-backup", Type: proberbus.TypeHeartbeat, Interval: time.Hour, Token: heartbeatToken},
+	}, nil, targetBus, proberbus.Config{})
+
+	// Status changes are paged through a channel that never delivers.
+	notifyBus := notifybus.NewBusiness(log, dlg, nil, notifybus.NewChannel("pager", pagerNotifier{}))
+
+	routes := Routes{
+		Build:            "test",
+		StartedAt:        time.Now(),
+		Stores:           []string{"memory"},
+		HealthBus:        healthBus,
+		NotifyBus:        notifyBus,
+		TargetBus:        targetBus,
+		IncidentBus:      incidentBus,
+		HistoryBus:       historyBus,
+		OnCallBus:        onCallBus,
+		MaintenanceBus:   maintenanceBus,
+		AnnotationBus:    annotationBus,
+		SnoozeBus:        snoozeBus,
+		ViewBus:          viewBus,
+		ReportBus:        reportBus,
+		AlertHistoryBus:  alertHistoryBus,
+		ForecastBus:      forecastBus,
+		CompareBus:       compareBus,
+		StorageBus:       storageBus,
+		LogBus:           logBus,
+		KubeEventBus:     kubeEventBus,
+		RemediationBus:   remediationBus,
+		IngestBus:        ingestBus,
+		IngestSecret:     ingestSecret,
+		ProberBus:        proberBus,
+		ReadinessTimeout: time.Second,
+		QueryTimeout:     200 * time.Millisecond,
+		RequestTimeout:   time.Second,
+		V1Deprecation: mid.Deprecation{
+			Since:  time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Sunset: time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	app := mux.WebAPI(mux.Config{
+		Log:       log,
+		CORS:      mid.DefaultCORSPolicy("*"),
+		AccessLog: mid.DefaultAccessLog(),
+	}, routes)
+
+	srv := httptest.NewServer(app)
+	t.Cleanup(srv.Close)
+
+	return &apiTest{
+		t:         t,
+		srv:       srv,
+		store:     store,
+		backup:    backup,
+		ingestBus: ingestBus,
+	}
+}
+
+// do sends a request and decodes a JSON response body into out, if set. A
+// *[]byte out receives the body as is.
+func (at *apiTest) do(method string, path string, body string, header http.Header, out any) *http.Response {
+	at.t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), method, at.srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		at.t.Fatalf("Should be able to create the request: %s", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		at.t.Fatalf("Should be able to send %s %s: %s", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		at.t.Fatalf("Should be able to read the response: %s", err)
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return resp
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			at.t.Fatalf("Should be able to decode the response %q: %s", data, err)
+		}
+	}
+
+	return resp
+}
+
+// alertHistoryStore serves fixed state transitions.
+type alertHistoryStore []alerthistorybus.Transition
+
+func (s alertHistoryStore) QueryTransitions(ctx context.Context, from, to time.Time) ([]alerthistorybus.Transition, error) {
+	var transitions []alerthistorybus.Transition
+	for _, t := range s {
+		if !t.Time.Before(from) && !t.Time.After(to) {
+			transitions = append(transitions, t)
+		}
+	}
+	return transitions, nil
+}
+
+// forecastStore serves a certificate losing a day per day with ten days
+// left, and a disk with constant free space.
+// onCallStore puts the same person on call for every schedule except the
+// payments team's, which has nobody.
+type onCallStore struct{}
+
+func (onCallStore) QueryOnCall(ctx context.Context, schedule string, at time.Time) (oncallbus.OnCall, error) {
+	if schedule == "payments-primary" {
+		return oncallbus.OnCall{}, oncallbus.ErrNobodyOnCall
+	}
+	return oncallbus.OnCall{Schedule: schedule, Name: "Ada Lovelace"}, nil
+}
+
+type forecastStore struct{}
+
+func (forecastStore) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]forecastbus.Series, error) {
+	if strings.Contains(query, "ssl") {
+		return []forecastbus.Series{{
+			Labels: map[string]string{"instance": "https://shop.example.com"},
+			Points: []forecastbus.Point{
+				{Time: end.Add(-48 * time.Hour), Value: 12},
+				{Time: end.Add(-24 * time.Hour), Value: 11},
+				{Time: end, Value: 10},
+			},
+		}}, nil
+	}
+
+	return []forecastbus.Series{{
+		Labels: map[string]string{"instance": "node-1:9100", "mountpoint": "/"},
+		Points: []forecastbus.Point{
+			{Time: end.Add(-48 * time.Hour), Value: 5e9},
+			{Time: end.Add(-24 * time.Hour), Value: 5e9},
+			{Time: end, Value: 5e9},
+		},
+	}}, nil
+}
+
+// statsStore reports the api as always up and fast, and the shop as up
+// half the time and slower.
+type statsStore struct{}
+
+func (statsStore) QueryStats(ctx context.Context, target string, window time.Duration, at time.Time) (comparebus.Stats, error) {
+	if target == "https://shop.example.com" {
+		return comparebus.Stats{Samples: 120, SuccessRate: 0.5, Latency: comparebus.Latency{Avg: 0.3, P50: 0.25, P95: 0.6, P99: 0.9, Max: 1.2}}, nil
+	}
+	return comparebus.Stats{Samples: 120, SuccessRate: 1, Latency: comparebus.Latency{Avg: 0.1, P50: 0.1, P95: 0.2, P99: 0.3, Max: 0.4}}, nil
+}
+
+// volumeStore reports a shop database volume that is nearly full and a
+// logs volume that is fuller still but has a higher threshold.
+type volumeStore struct{}
+
+func (volumeStore) QueryVolumes(ctx context.Context, at time.Time) ([]storagebus.VolumeStats, error) {
+	return []storagebus.VolumeStats{
+		{Namespace: "shop", PVC: "data-db-0", CapacityBytes: 100, UsedBytes: 85, AvailableBytes: 15},
+		{Namespace: "shop", PVC: "logs-0", CapacityBytes: 100, UsedBytes: 92, AvailableBytes: 8},
+	}, nil
+}
+
+// logStore echoes the query as a single log line.
+type logStore struct{}
+
+func (logStore) QueryLogs(ctx context.Context, selector string, from, to time.Time, limit int) ([]logbus.Line, error) {
+	return []logbus.Line{{Time: to, Labels: map[string]string{"app": "shop"}, Line: selector}}, nil
+}
+
+// kubeEventStore serves fixed events of the shop namespace.
+type kubeEventStore struct{}
+
+func (kubeEventStore) QueryEvents(ctx context.Context, namespace string) ([]kubeeventbus.Event, error) {
+	now := time.Now().UTC()
+	return []kubeeventbus.Event{
+		{Type: "Warning", Reason: "BackOff", Kind: "Pod", Name: "web-7d9f8-x2k4q", Count: 3, LastSeen: now.Add(-time.Minute)},
+		{Type: "Normal", Reason: "ScalingReplicaSet", Kind: "Deployment", Name: "web", Count: 1, LastSeen: now.Add(-2 * time.Minute)},
+		{Type: "Warning", Reason: "FailedMount", Kind: "Pod", Name: "worker-0", Count: 1, LastSeen: now.Add(-time.Minute)},
+		{Type: "Normal", Reason: "Pulled", Kind: "Pod", Name: "web-7d9f8-x2k4q", Count: 1, LastSeen: now.Add(-2 * time.Hour)},
+	}, nil
+}
+
+// pagerNotifier fails every delivery, as a pager that is unreachable.
+type pagerNotifier struct{}
+
+func (pagerNotifier) Notify(ctx context.Context, n notifybus.Notification) error {
+	return errors.New("pager unreachable")
+}
+
+func checkStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+
+	if resp.StatusCode != want {
+		t.Fatalf("Should receive status %d, got %d", want, resp.StatusCode)
+	}
+}
+
+// eventually retries fn until it holds or a second has passed, for state
+// that status change events update off the request path.
+func eventually(fn func() bool) bool {
+	for deadline := time.Now().Add(time.Second); !fn(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// =============================================================================
+
+func Test_API(t *testing.T) {
+	at := newAPITest(t)
+
+	t.Run("health", at.health)
+	t.Run("healthByTarget", at.healthByTarget)
+	t.Run("etag", at.etag)
+	t.Run("fields", at.fields)
+	t.Run("v2", at.v2)
+	t.Run("binary", at.binary)
+	t.Run("alerts", at.alerts)
+	t.Run("alertHistory", at.alertHistory)
+	t.Run("forecasts", at.forecasts)
+	t.Run("compare", at.compare)
+	t.Run("aggregate", at.aggregate)
+	t.Run("storage", at.storage)
+	t.Run("logs", at.logs)
+	t.Run("events", at.events)
+	t.Run("remediations", at.remediations)
+	t.Run("receive", at.receive)
+	t.Run("changes", at.changes)
+	t.Run("targets", at.targets)
+	t.Run("pause", at.pause)
+	t.Run("filter", at.filter)
+	t.Run("views", at.views)
+	t.Run("probes", at.probes)
+	t.Run("heartbeat", at.heartbeat)
+	t.Run("problemDetails", at.problemDetails)
+	t.Run("maintenance", at.maintenance)
+	t.Run("sla", at.sla)
+	t.Run("annotations", at.annotations)
+	t.Run("inject", at.inject)
+	t.Run("recheck", at.recheck)
+	t.Run("snooze", at.snooze)
+	t.Run("summary", at.summary)
+	t.Run("regions", at.regions)
+	t.Run("system", at.system)
+
+	// Failure injection changes the store for every later subtest.
+	t.Run("storeError", at.storeError)
+	t.Run("partialResults", at.partialResults)
+	t.Run("storeLatency", at.storeLatency)
+}
+
+func (at *apiTest) health(t *testing.T) {
+	var summary healthbus.HealthSummary
+	resp := at.do(http.MethodGet, "/api/v1/health", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Should receive JSON, got %q", ct)
+	}
+
+	if summary.Total != 2 || summary.Healthy != 1 || summary.Down != 1 {
+		t.Errorf("Should count 2 checks, 1 healthy and 1 down, got %d/%d/%d", summary.Total, summary.Healthy, summary.Down)
+	}
+
+	if len(summary.Checks) != summary.Total {
+		t.Errorf("Should return %d checks, got %d", summary.Total, len(summary.Checks))
+	}
+}
+
+func (at *apiTest) healthByTarget(t *testing.T) {
+	var check healthbus.HealthCheck
+	resp := at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+	checkStatus(t, resp, http.StatusOK)
+
+	if check.Target != "https://shop.example.com" || check.Status != healthbus.StatusDown {
+		t.Errorf("Should report the shop as down, got %s %s", check.Target, check.Status)
+	}
+
+	var errResp struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	resp = at.do(http.MethodGet, "/api/v1/health/missing", "", nil, &errResp)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	if errResp.Message == "" {
+		t.Error("Should explain the error")
+	}
+}
+
+func (at *apiTest) etag(t *testing.T) {
+	resp := at.do(http.MethodGet, "/api/v1/health", "", nil, nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("Should receive an ETag")
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health", "", http.Header{"If-None-Match": {etag}}, nil)
+	checkStatus(t, resp, http.StatusNotModified)
+}
+
+func (at *apiTest) alerts(t *testing.T) {
+	var summary healthbus.AlertSummary
+	resp := at.do(http.MethodGet, "/api/v1/alerts", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if summary.Total != 2 || summary.Firing != 1 || summary.Normal != 1 {
+		t.Errorf("Should count 2 alerts, 1 firing and 1 normal, got %d/%d/%d", summary.Total, summary.Firing, summary.Normal)
+	}
+
+	if len(summary.Groups) != 0 {
+		t.Errorf("Should not group alerts unless asked to, got %d groups", len(summary.Groups))
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/alerts?group_by=instance", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	// One alert has the instance label, the other is grouped without it.
+	if len(summary.Groups) != 2 || summary.Groups[0].Count != 1 || summary.Groups[1].Labels["instance"] != "https://shop.example.com" {
+		t.Errorf("Should group the alerts by instance, got %+v", summary.Groups)
+	}
+
+	summary = healthbus.AlertSummary{}
+	resp = at.do(http.MethodGet, "/api/v1/alerts?severity=critical", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if summary.Total != 1 || summary.Critical != 1 || summary.Alerts[0].Severity != healthbus.SeverityCritical {
+		t.Errorf("Should only return the critical alert, got %+v", summary)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/alerts?severity=urgent", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	// Neither alert has a team, so both fall back to the default schedule.
+	summary = healthbus.AlertSummary{}
+	at.do(http.MethodGet, "/api/v1/alerts?group_by=instance", "", nil, &summary)
+
+	for _, a := range summary.Alerts {
+		if a.OnCall != "Ada Lovelace" {
+			t.Errorf("Should name who is on call for %q, got %q", a.Title, a.OnCall)
+		}
+	}
+	if len(summary.Groups) == 0 || summary.Groups[0].Alerts[0].OnCall != "Ada Lovelace" {
+		t.Errorf("Should name who is on call in groups too, got %+v", summary.Groups)
+	}
+}
+
+func (at *apiTest) alertHistory(t *testing.T) {
+	var firings []alerthistorybus.Firing
+	resp := at.do(http.MethodGet, "/api/v1/alerts/history", "", nil, &firings)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(firings) != 2 || !firings[0].Active || firings[1].Active || firings[1].DurationSeconds != 600 {
+		t.Errorf("Should list the active firing first, then the ten minute one, got %+v", firings)
+	}
+
+	// Starting inside the first firing reports it as starting at from.
+	from := time.Now().Add(-55 * time.Minute).UTC().Format(time.RFC3339)
+	resp = at.do(http.MethodGet, "/api/v1/alerts/history?target=https://shop.example.com&from="+from, "", nil, &firings)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(firings) != 2 || firings[1].DurationSeconds > 360 {
+		t.Errorf("Should cut the first firing at from, got %+v", firings)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/alerts/history?target=https://api.example.com", "", nil, &firings)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(firings) != 0 {
+		t.Errorf("Should not list firings of other targets, got %+v", firings)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/alerts/history?from=yesterday", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) forecasts(t *testing.T) {
+	var forecasts []forecastbus.Forecast
+	resp := at.do(http.MethodGet, "/api/v1/forecasts", "", nil, &forecasts)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(forecasts) != 2 {
+		t.Fatalf("Should forecast the certificate and the disk, got %+v", forecasts)
+	}
+
+	cert := forecasts[0]
+	if cert.Kind != forecastbus.KindCertificate || cert.DaysLeft == nil || *cert.DaysLeft < 9.9 || *cert.DaysLeft > 10.1 {
+		t.Errorf("Should project the certificate to expire first, in ten days, got %+v", cert)
+	}
+
+	if forecasts[1].ExhaustsAt != nil {
+		t.Errorf("Should not project constant free space to run out, got %v", forecasts[1].ExhaustsAt)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/forecasts?within=30d", "", nil, &forecasts)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(forecasts) != 1 || forecasts[0].Target != "https://shop.example.com" {
+		t.Errorf("Should only return what runs out within 30 days, got %+v", forecasts)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/forecasts?kind=quota", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) compare(t *testing.T) {
+	var cmp comparebus.Comparison
+	resp := at.do(http.MethodGet, "/api/v1/compare?a=https:%2F%2Fapi.example.com&b=https:%2F%2Fshop.example.com&window=30m", "", nil, &cmp)
+	checkStatus(t, resp, http.StatusOK)
+
+	if cmp.A.Target != "https://api.example.com" || cmp.B.Target != "https://shop.example.com" || cmp.WindowSeconds != 1800 {
+		t.Fatalf("Should compare the api with the shop over 30 minutes, got %+v", cmp)
+	}
+
+	if cmp.Delta.SuccessRate != -0.5 || cmp.Delta.LatencyP95 < 0.39 || cmp.Delta.LatencyP95 > 0.41 {
+		t.Errorf("Should report the shop as less available and slower, got %+v", cmp.Delta)
+	}
+
+	var errResp struct {
+		Fields map[string]string `json:"fields"`
+	}
+	resp = at.do(http.MethodGet, "/api/v1/compare?a=https:%2F%2Fapi.example.com&window=1y", "", nil, &errResp)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	if errResp.Fields["b"] == "" || errResp.Fields["window"] == "" {
+		t.Errorf("Should name the missing target and the invalid window, got %+v", errResp.Fields)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/compare?a=https:%2F%2Fapi.example.com&b=https:%2F%2Fnope.example.com", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+}
+
+func (at *apiTest) aggregate(t *testing.T) {
+	resp := at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com/aggregate?fn=median", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fmissing.example.com/aggregate?fn=availability", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	tests := []struct {
+		fn   string
+		want float64
+		unit string
+	}{
+		{fn: "availability", want: 50, unit: "percent"},
+		{fn: "error_count", want: 60, unit: "count"},
+		{fn: "p95_latency", want: 0.6, unit: "seconds"},
+	}
+
+	for _, tt := range tests {
+		var agg comparebus.Aggregate
+		resp := at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com/aggregate?window=30m&fn="+tt.fn, "", nil, &agg)
+		checkStatus(t, resp, http.StatusOK)
+
+		if agg.Value == nil || *agg.Value != tt.want || agg.Unit != tt.unit {
+			t.Errorf("Should compute %s as %v %s, got %v %s", tt.fn, tt.want, tt.unit, agg.Value, agg.Unit)
+		}
+		if agg.WindowSeconds != 1800 || agg.Samples != 120 {
+			t.Errorf("Should aggregate 120 samples over 30m, got %d over %vs", agg.Samples, agg.WindowSeconds)
+		}
+	}
+}
+
+func (at *apiTest) storage(t *testing.T) {
+	var volumes []storagebus.Volume
+	resp := at.do(http.MethodGet, "/api/v1/storage", "", nil, &volumes)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(volumes) != 2 || volumes[0].PVC != "logs-0" {
+		t.Fatalf("Should list both volumes, fullest first, got %+v", volumes)
+	}
+
+	if volumes[0].Status != storagebus.StatusOK || volumes[1].Status != storagebus.StatusWarning {
+		t.Errorf("Should rate logs-0 against its own threshold and data-db-0 against the default, got %s and %s", volumes[0].Status, volumes[1].Status)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/storage?status=warning", "", nil, &volumes)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(volumes) != 1 || volumes[0].PVC != "data-db-0" {
+		t.Errorf("Should filter by status, got %+v", volumes)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/storage?status=full", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) logs(t *testing.T) {
+	resp := at.do(http.MethodPut, "/api/v1/targets/https:%2F%2Flogs.example.com", `{"log_selector":"{app=\"shop\"}"}`, nil, nil)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		t.Fatalf("Should be able to register the target, got %d", resp.StatusCode)
+	}
+
+	at10 := time.Date(2026, 1, 4, 10, 0, 0, 0, time.UTC)
+
+	var logs logbus.Logs
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Flogs.example.com/logs?at="+at10.Format(time.RFC3339), "", nil, &logs)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(logs.Lines) != 1 || logs.Lines[0].Line != `{app="shop"}` || !logs.From.Equal(at10.Add(-5*time.Minute)) || !logs.To.Equal(at10.Add(5*time.Minute)) {
+		t.Errorf("Should query the target's selector five minutes around at, got %+v", logs)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fmissing.example.com/logs", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+}
+
+func (at *apiTest) events(t *testing.T) {
+	resp := at.do(http.MethodPut, "/api/v1/targets/https:%2F%2Fweb.example.com", `{"namespace":"shop","workload":"web"}`, nil, nil)
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		t.Fatalf("Should be able to register the target, got %d", resp.StatusCode)
+	}
+
+	var evts kubeeventbus.Events
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fweb.example.com/events", "", nil, &evts)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(evts.Events) != 2 || evts.Warnings != 1 || evts.Events[0].Reason != "BackOff" {
+		t.Errorf("Should return the recent events of the web workload, latest first, got %+v", evts)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fapi.example.com/events", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+}
+
+func (at *apiTest) remediations(t *testing.T) {
+	var attempts []remediationbus.Attempt
+	resp := at.do(http.MethodGet, "/api/v1/remediations?target=https:%2F%2Fshop.example.com", "", nil, &attempts)
+	checkStatus(t, resp, http.StatusOK)
+
+	if attempts == nil || len(attempts) > 0 {
+		t.Errorf("Should return an empty audit trail, got %+v", attempts)
+	}
+
+	var errResp struct {
+		Fields map[string]string `json:"fields"`
+	}
+	resp = at.do(http.MethodGet, "/api/v1/remediations?since=yesterday", "", nil, &errResp)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	if errResp.Fields["since"] == "" {
+		t.Errorf("Should name the since parameter, got %+v", errResp.Fields)
+	}
+}
+
+func (at *apiTest) receive(t *testing.T) {
+	body := writeRequest(
+		remoteSeries{"probe_success", "http://10.0.0.1", 1},
+		remoteSeries{"probe_duration_seconds", "http://10.0.0.1", 0.12},
+		remoteSeries{"probe_success", "http://10.0.0.2", 0},
+		remoteSeries{"node_load1", "http://10.0.0.1", 2},
+	)
+
+	header := http.Header{
+		"Content-Encoding": {"snappy"},
+		"Content-Type":     {"application/x-protobuf"},
+	}
+
+	resp := at.do(http.MethodPost, "/api/v1/receive", body, header, nil)
+	checkStatus(t, resp, http.StatusUnauthorized)
+
+	header.Set("Authorization", "Bearer "+ingestSecret)
+	resp = at.do(http.MethodPost, "/api/v1/receive", body, header, nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	results, err := at.ingestBus.Query(context.Background())
+	if err != nil {
+		t.Fatalf("Should be able to query ingested results: %s", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Should ingest a result per probed instance, got %+v", results)
+	}
+
+	for _, r := range results {
+		if r.Agent != "edge-berlin-1" {
+			t.Errorf("Should take the agent from the job label, got %q", r.Agent)
+		}
+		if want := r.Target == "http://10.0.0.1"; r.Success != want {
+			t.Errorf("Should report %s success as %t", r.Target, want)
+		}
+		if r.Target == "http://10.0.0.1" && r.DurationSeconds != 0.12 {
+			t.Errorf("Should take the duration from probe_duration_seconds, got %v", r.DurationSeconds)
+		}
+	}
+
+	header.Del("Content-Encoding")
+	resp = at.do(http.MethodPost, "/api/v1/receive", body, header, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+// remoteSeries is a series pushed by the edge-berlin-1 job.
+type remoteSeries struct {
+	name     string
+	instance string
+	value    float64
+}
+
+// writeRequest encodes the series as a snappy compressed remote-write
+// request.
+func writeRequest(series ...remoteSeries) string {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+
+		labels := map[string]string{"__name__": s.name, "instance": s.instance, "job": "edge-berlin-1"}
+		for name, value := range labels {
+			var l []byte
+			l = protowire.AppendTag(l, 1, protowire.BytesType)
+			l = protowire.AppendString(l, name)
+			l = protowire.AppendTag(l, 2, protowire.BytesType)
+			l = protowire.AppendString(l, value)
+
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, l)
+		}
+
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(time.Now().UnixMilli()))
+
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+
+	return string(snappy.Encode(nil, req))
+}
+
+func (at *apiTest) changes(t *testing.T) {
+	since := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+	var set struct {
+		Checks []healthbus.HealthCheck `json:"checks"`
+		Total  int                     `json:"total"`
+	}
+	// Both targets were first observed by the earlier subtests.
+	ok := eventually(func() bool {
+		resp := at.do(http.MethodGet, "/api/v1/health/changes?since="+since, "", nil, &set)
+		checkStatus(t, resp, http.StatusOK)
+		return set.Total == 2
+	})
+	if !ok {
+		t.Errorf("Should report both newly observed targets, got %d", set.Total)
+	}
+
+	resp := at.do(http.MethodGet, "/api/v1/health/changes", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) summary(t *testing.T) {
+	var summary healthapp.Summary
+	resp := at.do(http.MethodGet, "/api/v1/summary", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(summary.Trends) != 2 || summary.Trends[0].Window != "1h" || summary.Trends[1].Window != "24h" {
+		t.Fatalf("Should compare with an hour and a day ago, got %+v", summary.Trends)
+	}
+
+	if summary.Counts.Down != 1 {
+		t.Errorf("Should count the shop as down, got %+v", summary.Counts)
+	}
+
+	// An hour ago only the shop had a recorded status, and it was healthy.
+	hour := summary.Trends[0]
+	if hour.Counts.Total != 1 || hour.Counts.Healthy != 1 || hour.Delta.Down != 1 {
+		t.Errorf("Should report the shop going down within the hour, got %+v", hour)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/summary?windows=3h", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(summary.Trends) != 1 || summary.Trends[0].Counts.Total != 0 {
+		t.Errorf("Should report no statuses before the history starts, got %+v", summary.Trends)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/summary?windows=-1h", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) targets(t *testing.T) {
+	var tgt targetbus.Target
+	resp := at.do(http.MethodPut, "/api/v1/targets/https:%2F%2Fshop.example.com", `{"team":"payments"}`, nil, &tgt)
+	checkStatus(t, resp, http.StatusCreated)
+
+	if tgt.ID == "" || tgt.Version != 1 || tgt.Team != "payments" {
+		t.Errorf("Should create the target at version 1, got id %q version %d team %q", tgt.ID, tgt.Version, tgt.Team)
+	}
+
+	etag := resp.Header.Get("ETag")
+
+	resp = at.do(http.MethodPut, "/api/v1/targets/https:%2F%2Fshop.example.com", `{"team":"payments"}`, nil, &tgt)
+	checkStatus(t, resp, http.StatusOK)
+
+	if tgt.Version != 1 {
+		t.Errorf("Should not change the version for an identical upsert, got %d", tgt.Version)
+	}
+
+	resp = at.do(http.MethodPut, "/api/v1/targets/https:%2F%2Fshop.example.com", `{"team":"checkout"}`, http.Header{"If-Match": {`"stale"`}}, nil)
+	checkStatus(t, resp, http.StatusPreconditionFailed)
+
+	resp = at.do(http.MethodPut, "/api/v1/targets/https:%2F%2Fshop.example.com", `{"team":"checkout"}`, http.Header{"If-Match": {etag}}, &tgt)
+	checkStatus(t, resp, http.StatusOK)
+
+	if tgt.Version != 2 || tgt.Team != "checkout" {
+		t.Errorf("Should update the target to version 2, got version %d team %q", tgt.Version, tgt.Team)
+	}
+
+	// Metadata shows up on the health check.
+	var check healthbus.HealthCheck
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+	if check.Team != "checkout" {
+		t.Errorf("Should attach the team to the health check, got %q", check.Team)
+	}
+
+	resp = at.do(http.MethodPost, "/api/v1/targets", `{"name":"x","unknown":true}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) pause(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/targets/https:%2F%2Fmissing.example.com/pause", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	resp = at.do(http.MethodPost, "/api/v1/targets/https:%2F%2Fshop.example.com/pause", `{"ttl_seconds":-1}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	var tgt targetbus.Target
+	resp = at.do(http.MethodPost, "/api/v1/targets/https:%2F%2Fshop.example.com/pause", `{"ttl_seconds":3600,"reason":"migration"}`, nil, &tgt)
+	checkStatus(t, resp, http.StatusOK)
+
+	if !tgt.Paused || tgt.PausedUntil == nil || tgt.Version != 2 {
+		t.Errorf("Should pause the target for an hour without changing its version, got %+v", tgt)
+	}
+
+	var summary healthbus.HealthSummary
+	at.do(http.MethodGet, "/api/v1/health", "", nil, &summary)
+
+	if summary.Paused != 1 || summary.Down != 0 {
+		t.Errorf("Should count the paused shop instead of down, got paused %d down %d", summary.Paused, summary.Down)
+	}
+
+	var check healthbus.HealthCheck
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.Status != healthbus.StatusPaused || !check.Suppressed || check.PausedUntil == nil {
+		t.Errorf("Should report the shop as paused and suppressed, got %s", check.Status)
+	}
+
+	tgt = targetbus.Target{}
+	resp = at.do(http.MethodPost, "/api/v1/targets/https:%2F%2Fshop.example.com/resume", "", nil, &tgt)
+	checkStatus(t, resp, http.StatusOK)
+
+	if tgt.Paused || tgt.PausedUntil != nil {
+		t.Errorf("Should resume the target, got %+v", tgt)
+	}
+
+	check = healthbus.HealthCheck{}
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.Status != healthbus.StatusDown {
+		t.Errorf("Should report the shop as down again once resumed, got %s", check.Status)
+	}
+}
+
+func (at *apiTest) filter(t *testing.T) {
+	var summary healthbus.HealthSummary
+	resp := at.do(http.MethodGet, "/api/v1/health?filter="+url.QueryEscape("status=down AND (team=checkout OR probe=icmp)"), "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if summary.Total != 1 || summary.Checks[0].Target != "https://shop.example.com" {
+		t.Errorf("Should match the shop alone, got %+v", summary.Checks)
+	}
+
+	summary = healthbus.HealthSummary{}
+	at.do(http.MethodGet, "/api/v1/health?filter="+url.QueryEscape(`NOT status=down AND target~"API."`), "", nil, &summary)
+
+	if summary.Total != 1 || summary.Checks[0].Target != "https://api.example.com" {
+		t.Errorf("Should match the api alone, got %+v", summary.Checks)
+	}
+
+	var errResp struct {
+		Fields map[string]string `json:"fields"`
+	}
+	resp = at.do(http.MethodGet, "/api/v1/health?filter="+url.QueryEscape("status=down AND (team=checkout"), "", nil, &errResp)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	if !strings.HasPrefix(errResp.Fields["filter"], "position 17:") {
+		t.Errorf("Should report the position of the unclosed parenthesis, got %q", errResp.Fields["filter"])
+	}
+
+	resp = at.do(http.MethodGet, "/api/v2/health?filter="+url.QueryEscape("colour=red"), "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) views(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/views", `{"name":"payments down","filter":"status=down AND (team=payments","group_by":["colour"]}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	var v viewbus.View
+	resp = at.do(http.MethodPost, "/api/v1/views", `{"name":"payments down","shared":true,"filter":"status=down AND team=payments","group_by":["namespace"]}`, nil, &v)
+	checkStatus(t, resp, http.StatusCreated)
+
+	if v.ID == "" || !v.Shared || v.Filter != "status=down AND team=payments" {
+		t.Errorf("Should save the view, got %+v", v)
+	}
+
+	var views []viewbus.View
+	resp = at.do(http.MethodGet, "/api/v1/views", "", nil, &views)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(views) != 1 || views[0].ID != v.ID {
+		t.Errorf("Should list the saved view, got %+v", views)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/views/"+v.ID, "", nil, nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	resp = at.do(http.MethodDelete, "/api/v1/views/"+v.ID, "", nil, nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	resp = at.do(http.MethodGet, "/api/v1/views/"+v.ID, "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+}
+
+func (at *apiTest) probes(t *testing.T) {
+	resp := at.do(http.MethodGet, "/liveness", "", nil, nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	resp = at.do(http.MethodGet, "/readiness", "", nil, nil)
+	checkStatus(t, resp, http.StatusOK)
+}
+
+func (at *apiTest) heartbeat(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/heartbeat/nightly-backup", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	var token proberapp.HeartbeatToken
+	resp = at.do(http.MethodGet, "/api/v1/probes/checks/nightly-backup/token", "", nil, &token)
+	checkStatus(t, resp, http.StatusOK)
+
+	if token.Path != "/api/v1/heartbeat/"+heartbeatToken {
+		t.Fatalf("Should return the ping path of the check, got %q", token.Path)
+	}
+
+	var result proberbus.Result
+	resp = at.do(http.MethodPost, token.Path, "", nil, &result)
+	checkStatus(t, resp, http.StatusOK)
+
+	if result.Check != "nightly-backup" || !result.Success {
+		t.Errorf("Should record the ping, got %+v", result)
+	}
+}
+
+func (at *apiTest) problemDetails(t *testing.T) {
+	var problem struct {
+		Type   string `json:"type"`
+		Status int    `json:"status"`
+		Code   string `json:"code"`
+	}
+	resp := at.do(http.MethodGet, "/api/v1/targets/missing", "", http.Header{"Accept": {"application/problem+json"}}, &problem)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Should receive a problem document, got %q", ct)
+	}
+
+	if problem.Status != http.StatusNotFound || problem.Code != "NotFound" {
+		t.Errorf("Should describe a NotFound problem, got %d %s", problem.Status, problem.Code)
+	}
+
+	// Unknown API paths are not handed to the dashboard, even from a browser.
+	resp = at.do(http.MethodGet, "/api/v1/unknown", "", http.Header{"Accept": {"text/html,application/json"}}, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Should answer an unknown API path with JSON, got %q", ct)
+	}
+
+	resp = at.do(http.MethodGet, "/targets/shop", "", http.Header{"Accept": {"text/html"}}, nil)
+	checkStatus(t, resp, http.StatusOK)
+}
+
+func (at *apiTest) maintenance(t *testing.T) {
+	start := time.Now().Add(-25 * time.Hour).UTC().Format(time.RFC3339)
+	end := time.Now().Add(-23 * time.Hour).UTC().Format(time.RFC3339)
+
+	resp := at.do(http.MethodPost, "/api/v1/maintenance", `{"name":"patch","targets":["https://shop.example.com"],"start":"`+start+`","end":"`+end+`","rrule":"FREQ=SECONDLY"}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	resp = at.do(http.MethodPost, "/api/v1/maintenance", `{"name":"patch","targets":["https://shop.example.com"],"start":"`+start+`","end":"`+end+`","rrule":"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30"}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	var w struct {
+		ID     string `json:"id"`
+		Active bool   `json:"active"`
+	}
+	resp = at.do(http.MethodPost, "/api/v1/maintenance", `{"name":"patch","targets":["https://shop.example.com"],"start":"`+start+`","end":"`+end+`","rrule":"FREQ=DAILY"}`, nil, &w)
+	checkStatus(t, resp, http.StatusCreated)
+
+	if !w.Active {
+		t.Error("Should report today's occurrence of the daily window as active")
+	}
+
+	var summary healthbus.HealthSummary
+	at.do(http.MethodGet, "/api/v1/health", "", nil, &summary)
+
+	if summary.Down != 0 || summary.Maintenance != 1 {
+		t.Errorf("Should count the shop as under maintenance, got %d down and %d maintenance", summary.Down, summary.Maintenance)
+	}
+
+	resp = at.do(http.MethodDelete, "/api/v1/maintenance/"+w.ID, "", nil, nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	var check healthbus.HealthCheck
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.Status != healthbus.StatusDown || check.Maintenance != "" {
+		t.Errorf("Should report the shop as down once the window is deleted, got %s %q", check.Status, check.Maintenance)
+	}
+}
+
+func (at *apiTest) sla(t *testing.T) {
+	var w struct {
+		ID string `json:"id"`
+	}
+	resp := at.do(http.MethodPost, "/api/v1/maintenance", `{"name":"upgrade","targets":["https://shop.example.com"],"start":"2026-01-07T10:00:00+01:00","end":"2026-01-07T12:00:00+01:00"}`, nil, &w)
+	checkStatus(t, resp, http.StatusCreated)
+	defer at.do(http.MethodDelete, "/api/v1/maintenance/"+w.ID, "", nil, nil)
+
+	var rpt reportbus.SLAReport
+	resp = at.do(http.MethodGet, "/api/v1/reports/sla?period=2026-W02", "", nil, &rpt)
+	checkStatus(t, resp, http.StatusOK)
+
+	var shop *reportbus.TargetSLA
+	for i, ts := range rpt.Targets {
+		if ts.Target == "https://shop.example.com" {
+			shop = &rpt.Targets[i]
+		}
+	}
+
+	if shop == nil {
+		t.Fatalf("Should report the shop, got %+v", rpt.Targets)
+	}
+
+	// Five days of twelve hours, less the two hours of maintenance.
+	if shop.Calendar != "business" || shop.MeasuredSeconds != 5*12*3600-2*3600 {
+		t.Errorf("Should measure the shop's business hours less maintenance, got %q %v", shop.Calendar, shop.MeasuredSeconds)
+	}
+	if shop.AvailabilityPercent != 100 {
+		t.Errorf("Should report the shop as fully available, got %v", shop.AvailabilityPercent)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/reports/sla?period=2999-01", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+}
+
+func (at *apiTest) annotations(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/annotations", `{"version":"v1.4.2"}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	var a annotationbus.Annotation
+	resp = at.do(http.MethodPost, "/api/v1/annotations", `{"targets":["https://shop.example.com"],"version":"v1.4.2","author":"ci"}`, nil, &a)
+	checkStatus(t, resp, http.StatusCreated)
+
+	if a.Kind != annotationbus.KindDeploy {
+		t.Errorf("Should default the kind to deploy, got %q", a.Kind)
+	}
+
+	var check healthbus.HealthCheck
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.LastDeploy == nil || check.LastDeploy.Version != "v1.4.2" {
+		t.Errorf("Should report the deploy on the shop's check, got %+v", check.LastDeploy)
+	}
+
+	var annotations []annotationbus.Annotation
+	at.do(http.MethodGet, "/api/v1/annotations?target=https://shop.example.com", "", nil, &annotations)
+
+	if len(annotations) != 1 || annotations[0].ID != a.ID {
+		t.Errorf("Should list the deploy for the shop, got %+v", annotations)
+	}
+
+	resp = at.do(http.MethodDelete, "/api/v1/annotations/"+a.ID, "", nil, nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	check = healthbus.HealthCheck{}
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.LastDeploy != nil {
+		t.Errorf("Should drop the deploy once deleted, got %+v", check.LastDeploy)
+	}
+}
+
+func (at *apiTest) fields(t *testing.T) {
+	var summary map[string]any
+	resp := at.do(http.MethodGet, "/api/v1/health?fields=target,status", "", nil, &summary)
+	checkStatus(t, resp, http.StatusOK)
+
+	if summary["total"] != float64(2) {
+		t.Errorf("Should keep the summary counts, got %v", summary["total"])
+	}
+
+	checks, _ := summary["checks"].([]any)
+	if len(checks) != 2 {
+		t.Fatalf("Should keep every check, got %d", len(checks))
+	}
+
+	for _, c := range checks {
+		if check := c.(map[string]any); len(check) != 2 || check["target"] == nil || check["status"] == nil {
+			t.Errorf("Should only keep target and status, got %v", check)
+		}
+	}
+
+	var check map[string]any
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fapi.example.com?fields=status", "", nil, &check)
+
+	if len(check) != 1 || check["status"] != "healthy" {
+		t.Errorf("Should only keep the status of a single check, got %v", check)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fmissing.example.com?fields=status", "", nil, &check)
+	checkStatus(t, resp, http.StatusNotFound)
+}
+
+func (at *apiTest) v2(t *testing.T) {
+	var list struct {
+		Data  []healthbus.HealthCheck `json:"data"`
+		Meta  healthapp.HealthMeta    `json:"meta"`
+		Links web.Links               `json:"links"`
+	}
+	resp := at.do(http.MethodGet, "/api/v2/health?fields=target", "", nil, &list)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(list.Data) != 2 || list.Meta.Total != 2 || list.Meta.Down != 1 {
+		t.Errorf("Should list the checks with their counts, got %d checks, meta %+v", len(list.Data), list.Meta)
+	}
+
+	if list.Links["self"] != "/api/v2/health?fields=target" {
+		t.Errorf("Should link the request, got %q", list.Links["self"])
+	}
+
+	if h := resp.Header.Get("Deprecation"); h != "" {
+		t.Errorf("Should not deprecate v2, got %q", h)
+	}
+
+	var one struct {
+		Data healthbus.HealthCheck `json:"data"`
+	}
+	at.do(http.MethodGet, "/api/v2/health/https:%2F%2Fapi.example.com", "", nil, &one)
+
+	if one.Data.Status != healthbus.StatusHealthy {
+		t.Errorf("Should wrap the check in data, got %+v", one.Data)
+	}
+
+	var alerts struct {
+		Data []healthbus.Alert   `json:"data"`
+		Meta healthapp.AlertMeta `json:"meta"`
+	}
+	at.do(http.MethodGet, "/api/v2/alerts?severity=critical", "", nil, &alerts)
+
+	if len(alerts.Data) != 1 || alerts.Meta.Critical != 1 {
+		t.Errorf("Should list the critical alert, got %d alerts, meta %+v", len(alerts.Data), alerts.Meta)
+	}
+
+	var problem errs.Problem
+	resp = at.do(http.MethodGet, "/api/v2/health/https:%2F%2Fmissing.example.com", "", nil, &problem)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	if resp.Header.Get("Content-Type") != errs.ProblemContentType || problem.Code != "NotFound" {
+		t.Errorf("Should answer v2 errors with problem details, got %q %+v", resp.Header.Get("Content-Type"), problem)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fapi.example.com", "", nil, nil)
+
+	if h := resp.Header.Get("Deprecation"); h != "@1767225600" {
+		t.Errorf("Should deprecate v1, got %q", h)
+	}
+	if h := resp.Header.Get("Sunset"); h != "Thu, 31 Dec 2026 00:00:00 GMT" {
+		t.Errorf("Should announce the v1 sunset, got %q", h)
+	}
+	if h := resp.Header.Get("Link"); h != `</api/v2/health/https:%2F%2Fapi.example.com>; rel="successor-version"` {
+		t.Errorf("Should link the v2 route, got %q", h)
+	}
+}
+
+func (at *apiTest) binary(t *testing.T) {
+	resp := at.do(http.MethodGet, "/api/v1/health", "", nil, nil)
+	jsonSize := resp.ContentLength
+
+	var body []byte
+	resp = at.do(http.MethodGet, "/api/v1/health", "", http.Header{"Accept": {"application/x-msgpack"}}, &body)
+	checkStatus(t, resp, http.StatusOK)
+
+	dec := msgpack.NewDecoder(bytes.NewReader(body))
+	dec.SetCustomStructTag("json")
+
+	var summary healthbus.HealthSummary
+	if err := dec.Decode(&summary); err != nil {
+		t.Fatalf("Should decode msgpack: %s", err)
+	}
+
+	if summary.Total != 2 || len(summary.Checks) != 2 || summary.Checks[0].LastChecked.IsZero() {
+		t.Errorf("Should encode the summary, got %+v", summary)
+	}
+
+	if int64(len(body)) >= jsonSize {
+		t.Errorf("Should be smaller than JSON, got %d bytes vs %d", len(body), jsonSize)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health?format=protobuf", "", nil, &body)
+	checkStatus(t, resp, http.StatusOK)
+
+	var total uint64
+	var checks int
+	for len(body) > 0 {
+		num, typ, n := protowire.ConsumeTag(body)
+		if n < 0 {
+			t.Fatalf("Should decode protobuf: %s", protowire.ParseError(n))
+		}
+		body = body[n:]
+
+		if num == 1 && typ == protowire.VarintType {
+			total, _ = protowire.ConsumeVarint(body)
+		}
+		if num == 8 {
+			checks++
+		}
+		body = body[protowire.ConsumeFieldValue(num, typ, body):]
+	}
+
+	if total != 2 || checks != 2 {
+		t.Errorf("Should encode the summary, got total %d and %d checks", total, checks)
+	}
+}
+
+func (at *apiTest) inject(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/debug/inject", `{"target":"https://api.example.com","status":"unknown","minutes":5}`, nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	resp = at.do(http.MethodPost, "/api/v1/debug/inject", `{"target":"https://missing.example.com","status":"down","minutes":5}`, nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	var inj healthbus.Injection
+	resp = at.do(http.MethodPost, "/api/v1/debug/inject", `{"target":"https://api.example.com","status":"down","minutes":5,"reason":"routing drill"}`, nil, &inj)
+	checkStatus(t, resp, http.StatusCreated)
+
+	if d := inj.Until.Sub(inj.StartedAt); d != 5*time.Minute {
+		t.Errorf("Should inject the failure for five minutes, got %s", d)
+	}
+
+	var check healthbus.HealthCheck
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fapi.example.com", "", nil, &check)
+
+	if check.Status != healthbus.StatusDown || !check.Injected {
+		t.Errorf("Should report the api as down while injected, got %s", check.Status)
+	}
+
+	var injections []healthbus.Injection
+	resp = at.do(http.MethodGet, "/api/v1/debug/inject", "", nil, &injections)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(injections) != 1 || injections[0].Reason != "routing drill" {
+		t.Errorf("Should list the active injection, got %+v", injections)
+	}
+
+	resp = at.do(http.MethodDelete, "/api/v1/debug/inject/https:%2F%2Fapi.example.com", "", nil, nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	check = healthbus.HealthCheck{}
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fapi.example.com", "", nil, &check)
+
+	if check.Status != healthbus.StatusHealthy || check.Injected {
+		t.Errorf("Should report the api as healthy once the injection stops, got %s", check.Status)
+	}
+
+	resp = at.do(http.MethodDelete, "/api/v1/debug/inject/https:%2F%2Fapi.example.com", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+}
+
+func (at *apiTest) recheck(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fmissing.example.com/check", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	at.backup.SetChecks(healthbus.HealthCheck{Target: "https://cdn.example.com", Status: healthbus.StatusDown, LastChecked: time.Now(), Probe: "http_2xx"})
+	defer at.backup.SetChecks()
+
+	var check healthbus.HealthCheck
+	resp = at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fcdn.example.com/check", "", nil, &check)
+	checkStatus(t, resp, http.StatusOK)
+
+	if check.Target != "https://cdn.example.com" || check.Status != healthbus.StatusDown {
+		t.Errorf("Should report the cdn as down, got %s %s", check.Target, check.Status)
+	}
+
+	at.backup.SetChecks(healthbus.HealthCheck{Target: "https://cdn.example.com", Status: healthbus.StatusHealthy, LastChecked: time.Now(), Probe: "http_2xx"})
+
+	check = healthbus.HealthCheck{}
+	at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fcdn.example.com/check", "", nil, &check)
+
+	if check.Status != healthbus.StatusHealthy {
+		t.Errorf("Should report the fresh result of the re-check, got %s", check.Status)
+	}
+}
+
+func (at *apiTest) snooze(t *testing.T) {
+	resp := at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fshop.example.com/snooze?duration=soon", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	resp = at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fshop.example.com/snooze?duration=720h", "", nil, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	resp = at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fmissing.example.com/snooze?duration=2h", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	var s snoozebus.Snooze
+	resp = at.do(http.MethodPost, "/api/v1/health/https:%2F%2Fshop.example.com/snooze?duration=2h&reason=vendor+outage", "", nil, &s)
+	checkStatus(t, resp, http.StatusCreated)
+
+	if d := s.Until.Sub(s.StartedAt); d != 2*time.Hour {
+		t.Errorf("Should snooze the shop for two hours, got %s", d)
+	}
+
+	var check healthbus.HealthCheck
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.Status != healthbus.StatusDown {
+		t.Errorf("Should still report the snoozed shop as down, got %s", check.Status)
+	}
+	if !check.Suppressed || check.SnoozedUntil == nil || !check.SnoozedUntil.Equal(s.Until) {
+		t.Errorf("Should suppress the shop until %s, got %v %v", s.Until, check.Suppressed, check.SnoozedUntil)
+	}
+
+	resp = at.do(http.MethodDelete, "/api/v1/health/https:%2F%2Fshop.example.com/snooze", "", nil, nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	check = healthbus.HealthCheck{}
+	at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fshop.example.com", "", nil, &check)
+
+	if check.Suppressed || check.SnoozedUntil != nil {
+		t.Errorf("Should stop suppressing the shop once the snooze ends, got %v %v", check.Suppressed, check.SnoozedUntil)
+	}
+
+	resp = at.do(http.MethodDelete, "/api/v1/health/https:%2F%2Fshop.example.com/snooze", "", nil, nil)
+	checkStatus(t, resp, http.StatusNotFound)
+
+	var snoozes []snoozebus.Snooze
+	resp = at.do(http.MethodGet, "/api/v1/snoozes?target=https://shop.example.com", "", nil, &snoozes)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(snoozes) != 1 || snoozes[0].Reason != "vendor outage" || snoozes[0].EndedAt == nil {
+		t.Errorf("Should keep the ended snooze in the audit trail, got %+v", snoozes)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/snoozes?active=true", "", nil, &snoozes)
+	checkStatus(t, resp, http.StatusOK)
+
+	if len(snoozes) != 0 {
+		t.Errorf("Should list no active snoozes, got %+v", snoozes)
+	}
+}
+
+func (at *apiTest) regions(t *testing.T) {
+	now := time.Now()
+	at.backup.SetChecks(
+		healthbus.HealthCheck{Target: "https://edge.example.com", Region: "eu", Instance: "agent-eu", Status: healthbus.StatusHealthy, LastChecked: now},
+		healthbus.HealthCheck{Target: "https://edge.example.com", Region: "us", Instance: "agent-us-1", Status: healthbus.StatusHealthy, LastChecked: now},
+		healthbus.HealthCheck{Target: "https://edge.example.com", Region: "us", Instance: "agent-us-2", Status: healthbus.StatusDown, LastChecked: now},
+	)
+	defer at.backup.SetChecks()
+
+	var check healthbus.HealthCheck
+	resp := at.do(http.MethodGet, "/api/v1/health/https:%2F%2Fedge.example.com", "", nil, &check)
+	checkStatus(t, resp, http.StatusOK)
+
+	if check.Status != healthbus.StatusDegraded {
+		t.Errorf("Should report one failing region of two as degraded, got %s", check.Status)
+	}
+	if check.Regions["eu"] != healthbus.StatusHealthy || check.Regions["us"] != healthbus.StatusDown {
+		t.Errorf("Should report the worst status per region, got %v", check.Regions)
+	}
+	if check.DegradedReason != "1 of 2 regions down: us" {
+		t.Errorf("Should name the failing region, got %q", check.DegradedReason)
+	}
+
+	at.backup.SetChecks(
+		healthbus.HealthCheck{Target: "https://edge.example.com", Region: "eu", Status: healthbus.StatusDown, LastChecked: now},
+		healthbus.HealthCheck{Target: "https://edge.example.com", Region: "us", Status: healthbus.StatusDown, LastChecked: now},
+		healthbus.HealthCheck{Target: "https://edge.example.com", Region: "ap", Status: healthbus.StatusHealthy, LastChecked: now},
+	)
+
+	var summary healthbus.HealthSummary
+	at.do(http.MethodGet, "/api/v1/health", "", nil, &summary)
+
+	var edge []healthbus.HealthCheck
+	for _, c := range summary.Checks {
+		if c.Target == "https://edge.example.com" {
+			edge = append(edge, c)
+		}
+	}
+	if len(edge) != 1 || edge[0].Status != healthbus.StatusDown {
+		t.Errorf("Should merge the regions into one down check at the quorum, got %+v", edge)
+	}
+}
+
+func (at *apiTest) system(t *testing.T) {
+	var info systemapp.System
+	eventually(func() bool {
+		resp := at.do(http.MethodGet, "/api/v1/system", "", nil, &info)
+		checkStatus(t, resp, http.StatusOK)
+		return len(info.Notifications) == 1 && info.Notifications[0].LastAttempt != nil
+	})
+
+	// No poller runs, so every query so far went to the store.
+	if info.Cache.Misses == 0 || info.Cache.Hits != 0 || info.Cache.TakenAt != nil {
+		t.Errorf("Should count the queries without a snapshot as misses, got %+v", info.Cache)
+	}
+
+	if len(info.Notifications) != 1 {
+		t.Fatalf("Should report the pager channel, got %+v", info.Notifications)
+	}
+
+	pager := info.Notifications[0]
+	if pager.Name != "pager" || pager.Deliveries == 0 || pager.Failures != pager.Deliveries {
+		t.Errorf("Should count the failed deliveries of the pager, got %+v", pager)
+	}
+	if pager.LastAttempt == nil || pager.LastSuccess != nil || pager.LastError != "pager unreachable" {
+		t.Errorf("Should report the last failure of the pager, got %+v", pager)
+	}
+}
+
+func (at *apiTest) storeError(t *testing.T) {
+	at.store.SetError(errors.New("backend unavailable"))
+	defer at.store.SetError(nil)
+	at.backup.SetError(errors.New("backend unavailable"))
+	defer at.backup.SetError(nil)
+
+	resp := at.do(http.MethodGet, "/api/v1/health", "", nil, nil)
+	checkStatus(t, resp, http.StatusInternalServerError)
+
+	resp = at.do(http.MethodGet, "/readiness", "", nil, nil)
+	checkStatus(t, resp, http.StatusServiceUnavailable)
+
+	resp = at.do(http.MethodGet, "/liveness", "", nil, nil)
+	checkStatus(t, resp, http.StatusOK)
+}
+
+func (at *apiTest) partialResults(t *testing.T) {
+	at.backup.SetError(errors.New("backend unavailable"))
+	defer at.backup.SetError(nil)
+
+	var summary healthbus.HealthSummary
+	resp := at.do(http.MethodGet, "/api/v1/health", "", nil, &summary)
+	checkStatus(t, resp, http.StatusMultiStatus)
+
+	if !summary.Partial || len(summary.Errors) != 1 || summary.Errors[0].Source != "backup" {
+		t.Errorf("Should name the failed backend, got %+v", summary.Errors)
+	}
+
+	if summary.Total != 2 {
+		t.Errorf("Should keep the checks of the other backend, got %d", summary.Total)
+	}
+
+	var alerts healthbus.AlertSummary
+	resp = at.do(http.MethodGet, "/api/v1/alerts", "", nil, &alerts)
+	checkStatus(t, resp, http.StatusMultiStatus)
+
+	if !alerts.Partial || alerts.Total != 2 {
+		t.Errorf("Should keep the alerts of the other backend, got %d", alerts.Total)
+	}
+}
+
+func (at *apiTest) storeLatency(t *testing.T) {
+	at.store.SetLatency(time.Second)
+	defer at.store.SetLatency(0)
+	at.backup.SetLatency(time.Second)
+
+	start := time.Now()
+
+	resp := at.do(http.MethodGet, "/api/v1/health", "", nil, nil)
+	checkStatus(t, resp, http.StatusGatewayTimeout)
+
+	if d := time.Since(start); d > 900*time.Millisecond {
+		t.Errorf("Should give up after the query timeout, took %s", d)
+	}
+
+	start = time.Now()
+
+	resp = at.do(http.MethodGet, "/api/v1/health", "", http.Header{"X-Request-Timeout": {"50ms"}}, nil)
+	checkStatus(t, resp, http.StatusGatewayTimeout)
+
+	if d := time.Since(start); d > 150*time.Millisecond {
+		t.Errorf("Should give up after the client's timeout, took %s", d)
+	}
+
+	start = time.Now()
+
+	resp = at.do(http.MethodGet, "/api/v1/health", "", http.Header{"Grpc-Timeout": {"50m"}}, nil)
+	checkStatus(t, resp, http.StatusGatewayTimeout)
+
+	if d := time.Since(start); d > 150*time.Millisecond {
+		t.Errorf("Should give up after the grpc-timeout, took %s", d)
+	}
+
+	resp = at.do(http.MethodGet, "/api/v1/health", "", http.Header{"X-Request-Timeout": {"soon"}}, nil)
+	checkStatus(t, resp, http.StatusBadRequest)
+
+	// With only one backend slow the others' checks are served.
+	at.backup.SetLatency(0)
+
+	var summary healthbus.HealthSummary
+	resp = at.do(http.MethodGet, "/api/v1/health", "", http.Header{"X-Request-Timeout": {"50ms"}}, &summary)
+	checkStatus(t, resp, http.StatusMultiStatus)
+
+	if len(summary.Errors) != 1 || summary.Errors[0].Source != "memory" {
+		t.Errorf("Should name the backend that timed out, got %+v", summary.Errors)
+	}
+}
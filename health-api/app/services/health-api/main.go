@@ -5,30 +5,139 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"health-api/app/domain/alerthistoryapp"
+	"health-api/app/domain/annotationapp"
+	"health-api/app/domain/compareapp"
+	"health-api/app/domain/forecastapp"
 	"health-api/app/domain/healthapp"
+	"health-api/app/domain/incidentapp"
+	"health-api/app/domain/ingestapp"
+	"health-api/app/domain/kubeeventapp"
+	"health-api/app/domain/logapp"
+	"health-api/app/domain/maintenanceapp"
+	"health-api/app/domain/probeapp"
+	"health-api/app/domain/proberapp"
+	"health-api/app/domain/prometheusapp"
+	"health-api/app/domain/remediationapp"
+	"health-api/app/domain/reportapp"
+	"health-api/app/domain/snoozeapp"
+	"health-api/app/domain/storageapp"
+	"health-api/app/domain/systemapp"
+	"health-api/app/domain/targetapp"
+	"health-api/app/domain/uiapp"
+	"health-api/app/domain/viewapp"
+	"health-api/app/sdk/auth"
+	"health-api/app/sdk/metrics"
+	"health-api/app/sdk/mid"
 	"health-api/app/sdk/mux"
+	"health-api/business/domain/alerthistorybus"
+	"health-api/business/domain/alerthistorybus/stores/grafanahistory"
+	"health-api/business/domain/alerthistorybus/stores/lokihistory"
+	"health-api/business/domain/alertrulebus"
+	"health-api/business/domain/alertrulebus/stores/grafanarule"
+	"health-api/business/domain/annotationbus"
+	"health-api/business/domain/annotationbus/stores/annotationdb"
+	"health-api/business/domain/comparebus"
+	"health-api/business/domain/dashboardbus"
+	"health-api/business/domain/dashboardbus/stores/grafanadashboard"
+	"health-api/business/domain/eventbus"
+	"health-api/business/domain/forecastbus"
 	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/clusterstore"
+	"health-api/business/domain/healthbus/stores/cronjobstore"
+	"health-api/business/domain/healthbus/stores/gitopsstore"
 	"health-api/business/domain/healthbus/stores/grafanastore"
+	"health-api/business/domain/healthbus/stores/helmstore"
+	"health-api/business/domain/healthbus/stores/ingeststore"
+	"health-api/business/domain/healthbus/stores/kubestore"
+	"health-api/business/domain/healthbus/stores/metricstore"
+	"health-api/business/domain/healthbus/stores/multistore"
+	"health-api/business/domain/healthbus/stores/proberstore"
+	"health-api/business/domain/healthbus/stores/prometheusstore"
+	"health-api/business/domain/healthbus/stores/sharedstore"
+	"health-api/business/domain/healthbus/stores/storagestore"
+	"health-api/business/domain/historybus"
+	"health-api/business/domain/historybus/stores/historydb"
+	"health-api/business/domain/incidentbus"
+	"health-api/business/domain/incidentbus/stores/incidentdb"
+	"health-api/business/domain/ingestbus"
+	"health-api/business/domain/ingestbus/stores/ingestdb"
+	"health-api/business/domain/kubeeventbus"
+	"health-api/business/domain/kubeeventbus/stores/kubeevent"
+	"health-api/business/domain/logbus"
+	"health-api/business/domain/logbus/stores/lokilog"
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/domain/maintenancebus/stores/maintenancedb"
+	"health-api/business/domain/notifybus"
+	"health-api/business/domain/oncallbus"
+	"health-api/business/domain/oncallbus/stores/opsgeniestore"
+	"health-api/business/domain/oncallbus/stores/pagerdutystore"
+	"health-api/business/domain/probebus"
+	"health-api/business/domain/probebus/stores/blackboxstore"
+	"health-api/business/domain/proberbus"
+	"health-api/business/domain/prometheusbus"
+	"health-api/business/domain/remediationbus"
+	"health-api/business/domain/remediationbus/stores/kubeaction"
+	"health-api/business/domain/remediationbus/stores/remediationdb"
+	"health-api/business/domain/reportbus"
+	"health-api/business/domain/snoozebus"
+	"health-api/business/domain/snoozebus/stores/snoozedb"
+	"health-api/business/domain/storagebus"
+	"health-api/business/domain/targetbus"
+	"health-api/business/domain/targetbus/stores/targetdb"
+	"health-api/business/domain/viewbus"
+	"health-api/business/domain/viewbus/stores/viewdb"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/cron"
+	"health-api/foundation/kube"
 	"health-api/foundation/logger"
+	"health-api/foundation/mqtt"
+	"health-api/foundation/nats"
 	"health-api/foundation/otel"
+	"health-api/foundation/s3"
+	"health-api/foundation/secret"
+	"health-api/foundation/snmp"
 	"health-api/foundation/web"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 var build = "develop"
 
 func main() {
 	// Initialize logger
-	log := logger.New(os.Stdout, logger.LevelInfo, "HEALTH-API", traceIDFunc)
+	level, levelErr := logger.ParseLevel(getEnv("LOG_LEVEL", "info"))
+	if levelErr != nil {
+		level = logger.LevelInfo
+	}
+
+	// JSON in cluster; LOG_FORMAT=console for readable local output.
+	var log *logger.Logger
+	switch getEnv("LOG_FORMAT", "json") {
+	case "console", "text":
+		log = logger.NewConsole(os.Stdout, level, "HEALTH-API", traceIDFunc)
+	default:
+		log = logger.New(os.Stdout, level, "HEALTH-API", traceIDFunc)
+	}
 
 	ctx := context.Background()
 
+	if levelErr != nil {
+		log.Warn(ctx, "startup", "status", "invalid LOG_LEVEL, using info", "error", levelErr)
+	}
+
 	if err := run(ctx, log); err != nil {
 		log.Error(ctx, "startup", "error", err)
 		os.Exit(1)
@@ -39,6 +148,8 @@ func run(ctx context.Context, log *logger.Logger) error {
 	// -------------------------------------------------------------------------
 	// GOMAXPROCS
 
+	startedAt := time.Now().UTC()
+
 	log.Info(ctx, "startup", "GOMAXPROCS", runtime.GOMAXPROCS(0), "build", build)
 
 	// -------------------------------------------------------------------------
@@ -46,56 +157,624 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	cfg := struct {
 		Web struct {
-			ReadTimeout     time.Duration
-			WriteTimeout    time.Duration
-			IdleTimeout     time.Duration
-			ShutdownTimeout time.Duration
-			APIHost         string
-			DebugHost       string
-			CORSOrigin      string
+			ReadTimeout      time.Duration
+			WriteTimeout     time.Duration
+			IdleTimeout      time.Duration
+			ShutdownTimeout  time.Duration
+			ReadinessTimeout time.Duration
+			QueryTimeout     time.Duration
+			RequestTimeout   time.Duration
+			APIHost          string
+			DebugHost        string
+			ProblemDetails   string
+			V1Deprecation    string
+			V1Sunset         string
+		}
+		Log struct {
+			SampleFirst      string
+			SampleThereafter string
+			SampleWindow     string
+			AccessFields     string
+			RedactParams     string
+			SkipPaths        string
+		}
+		Auth struct {
+			JWTSecret   string
+			JWTIssuer   string
+			APIKeysFile string
+		}
+		CORS struct {
+			Origins          string
+			OriginRegex      string
+			AllowCredentials string
+		}
+		Metrics struct {
+			OnAPI    string
+			User     string
+			Password string
+		}
+		Backend struct {
+			MaxIdleConns        string
+			MaxIdleConnsPerHost string
+			MaxConnsPerHost     string
+			IdleConnTimeout     string
+			KeepAlive           string
+			HTTP2               string
+			CAFile              string
+			InsecureSkipVerify  string
+			Proxy               string
+			Interface           string
 		}
 		Grafana struct {
+			URL                string
+			User               string
+			Password           string
+			DatasourceUID      string
+			AlertFolder        string
+			AlertGroup         string
+			AlertFor           string
+			AlertProvision     string
+			SecretDir          string
+			SecretReload       string
+			DashboardProvision string
+			DashboardUID       string
+			DashboardTitle     string
+			DashboardFolder    string
+		}
+		Prometheus struct {
+			URL string
+		}
+		Forecast struct {
+			Lookback string
+			Step     string
+		}
+		Storage struct {
+			Volumes  string
+			Warning  string
+			Critical string
+			File     string
+		}
+		Loki struct {
+			URL       string
+			LogWindow string
+			LogLimit  string
+		}
+		Kube struct {
+			Events           string
+			EventsWindow     string
+			Workloads        string
+			Namespaces       string
+			GitOps           string
+			GitOpsNamespaces string
+			Helm             string
+			HelmNamespaces   string
+			HelmPending      string
+			Cluster          string
+			ClusterPending   string
+			CronJobs         string
+			CronJobNS        string
+			CronJobMissed    string
+		}
+		Blackbox struct {
+			URL    string
+			Reload string
+		}
+		Poller struct {
+			Interval string
+		}
+		Flap struct {
+			Window    string
+			Threshold string
+		}
+		Degraded struct {
+			LatencySLO string
+		}
+		Startup struct {
+			Timeout    string
+			Backoff    string
+			MaxBackoff string
+			FailFast   string
+		}
+		Prober struct {
+			File           string
+			MaxConcurrency string
+			Jitter         string
+			UDPAddr        string
+			UDPSecret      string
+		}
+		Ingest struct {
+			Secret string
+			TTL    string
+		}
+		MQTT struct {
 			URL      string
+			ClientID string
 			User     string
 			Password string
+			Rules    string
+		}
+		Regions struct {
+			DownQuorum string
+		}
+		DB struct {
+			Dir string
+		}
+		History struct {
+			Retention string
+		}
+		Targets struct {
+			File string
+		}
+		UI struct {
+			Dir string
+		}
+		Notify struct {
+			WebhookURL   string
+			SMTPHost     string
+			SMTPPort     string
+			SMTPUsername string
+			SMTPPassword string
+			EmailFrom    string
+			EmailTo      string
+			EmailDigest  string
+			Channels     string
+			Routes       string
+			DefaultRoute string
+			SNMP         struct {
+				Receivers    string
+				Version      string
+				Community    string
+				User         string
+				AuthProtocol string
+				AuthPassword string
+				PrivProtocol string
+				PrivPassword string
+				EngineID     string
+				OID          string
+			}
+		}
+		Events struct {
+			NATSURL       string
+			SubjectPrefix string
+			Source        string
+		}
+		Remediation struct {
+			File string
+		}
+		OnCall struct {
+			Provider  string
+			URL       string
+			APIKey    string
+			Schedules string
+			CacheTTL  string
+		}
+		Reports struct {
+			Schedule   string
+			Period     string
+			WebhookURL string
+			S3Bucket   string
+			S3Region   string
+			S3Endpoint string
+			S3Prefix   string
+			Calendars  string
 		}
 		Otel struct {
-			ReporterURI string
-			Probability float64
+			ReporterURI        string
+			Protocol           string
+			Probability        string
+			Insecure           string
+			CAFile             string
+			ResourceAttributes string
+			Namespace          string
+			PodName            string
+			NodeName           string
 		}
 	}{
 		Web: struct {
-			ReadTimeout     time.Duration
-			WriteTimeout    time.Duration
-			IdleTimeout     time.Duration
-			ShutdownTimeout time.Duration
-			APIHost         string
-			DebugHost       string
-			CORSOrigin      string
-		}{
-			ReadTimeout:     5 * time.Second,
-			WriteTimeout:    10 * time.Second,
-			IdleTimeout:     120 * time.Second,
-			ShutdownTimeout: 20 * time.Second,
-			APIHost:         getEnv("API_HOST", ":8080"),
-			DebugHost:       getEnv("DEBUG_HOST", ":4000"),
-			CORSOrigin:      getEnv("CORS_ORIGIN", "*"),
+			ReadTimeout      time.Duration
+			WriteTimeout     time.Duration
+			IdleTimeout      time.Duration
+			ShutdownTimeout  time.Duration
+			ReadinessTimeout time.Duration
+			QueryTimeout     time.Duration
+			RequestTimeout   time.Duration
+			APIHost          string
+			DebugHost        string
+			ProblemDetails   string
+			V1Deprecation    string
+			V1Sunset         string
+		}{
+			ReadTimeout:      5 * time.Second,
+			WriteTimeout:     10 * time.Second,
+			IdleTimeout:      120 * time.Second,
+			ShutdownTimeout:  20 * time.Second,
+			ReadinessTimeout: 2 * time.Second,
+			QueryTimeout:     8 * time.Second,
+			RequestTimeout:   5 * time.Second,
+			APIHost:          getEnv("API_HOST", ":8080"),
+			DebugHost:        getEnv("DEBUG_HOST", ":4000"),
+			ProblemDetails:   getEnv("PROBLEM_DETAILS", "false"),
+			V1Deprecation:    getEnv("API_V1_DEPRECATION", ""),
+			V1Sunset:         getEnv("API_V1_SUNSET", ""),
+		},
+		Log: struct {
+			SampleFirst      string
+			SampleThereafter string
+			SampleWindow     string
+			AccessFields     string
+			RedactParams     string
+			SkipPaths        string
+		}{
+			SampleFirst:      getEnv("LOG_SAMPLE_FIRST", "10"),
+			SampleThereafter: getEnv("LOG_SAMPLE_THEREAFTER", "100"),
+			SampleWindow:     getEnv("LOG_SAMPLE_WINDOW", "1m"),
+			AccessFields:     getEnv("LOG_ACCESS_FIELDS", "remote,query"),
+			RedactParams:     getEnv("LOG_REDACT_PARAMS", "token,access_token,api_key,apikey,password,secret"),
+			SkipPaths:        getEnv("LOG_SKIP_PATHS", "/liveness,/readiness,/healthz"),
+		},
+		Auth: struct {
+			JWTSecret   string
+			JWTIssuer   string
+			APIKeysFile string
+		}{
+			JWTSecret:   getEnv("AUTH_JWT_SECRET", ""),
+			JWTIssuer:   getEnv("AUTH_JWT_ISSUER", ""),
+			APIKeysFile: getEnv("AUTH_API_KEYS_FILE", ""),
+		},
+		CORS: struct {
+			Origins          string
+			OriginRegex      string
+			AllowCredentials string
+		}{
+			Origins:          getEnv("CORS_ORIGIN", "*"),
+			OriginRegex:      getEnv("CORS_ORIGIN_REGEX", ""),
+			AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "false"),
+		},
+		Metrics: struct {
+			OnAPI    string
+			User     string
+			Password string
+		}{
+			OnAPI:    getEnv("METRICS_ON_API", "false"),
+			User:     getEnv("METRICS_USER", ""),
+			Password: getEnv("METRICS_PASSWORD", ""),
 		},
 		Grafana: struct {
+			URL                string
+			User               string
+			Password           string
+			DatasourceUID      string
+			AlertFolder        string
+			AlertGroup         string
+			AlertFor           string
+			AlertProvision     string
+			SecretDir          string
+			SecretReload       string
+			DashboardProvision string
+			DashboardUID       string
+			DashboardTitle     string
+			DashboardFolder    string
+		}{
+			URL:                getEnv("GRAFANA_URL", ""),
+			User:               getEnv("GRAFANA_USER", "admin"),
+			Password:           getEnv("GRAFANA_PASSWORD", "admin"),
+			DatasourceUID:      getEnv("GRAFANA_DATASOURCE_UID", "prometheus-ds"),
+			AlertFolder:        getEnv("GRAFANA_ALERT_FOLDER_UID", "probe-alerts"),
+			AlertGroup:         getEnv("GRAFANA_ALERT_RULE_GROUP", "probe-alerts"),
+			AlertFor:           getEnv("GRAFANA_ALERT_FOR", "5m"),
+			AlertProvision:     getEnv("GRAFANA_ALERT_PROVISION", "true"),
+			SecretDir:          getEnv("GRAFANA_SECRET_DIR", ""),
+			SecretReload:       getEnv("GRAFANA_SECRET_RELOAD", "30s"),
+			DashboardProvision: getEnv("GRAFANA_DASHBOARD_PROVISION", "false"),
+			DashboardUID:       getEnv("GRAFANA_DASHBOARD_UID", "service-health"),
+			DashboardTitle:     getEnv("GRAFANA_DASHBOARD_TITLE", "Service Health"),
+			DashboardFolder:    getEnv("GRAFANA_DASHBOARD_FOLDER_UID", ""),
+		},
+		Backend: struct {
+			MaxIdleConns        string
+			MaxIdleConnsPerHost string
+			MaxConnsPerHost     string
+			IdleConnTimeout     string
+			KeepAlive           string
+			HTTP2               string
+			CAFile              string
+			InsecureSkipVerify  string
+			Proxy               string
+			Interface           string
+		}{
+			MaxIdleConns:        getEnv("BACKEND_MAX_IDLE_CONNS", "100"),
+			MaxIdleConnsPerHost: getEnv("BACKEND_MAX_IDLE_CONNS_PER_HOST", "32"),
+			MaxConnsPerHost:     getEnv("BACKEND_MAX_CONNS_PER_HOST", "0"),
+			IdleConnTimeout:     getEnv("BACKEND_IDLE_CONN_TIMEOUT", "90s"),
+			KeepAlive:           getEnv("BACKEND_KEEP_ALIVE", "30s"),
+			HTTP2:               getEnv("BACKEND_HTTP2", "true"),
+			CAFile:              getEnv("BACKEND_TLS_CA_FILE", ""),
+			InsecureSkipVerify:  getEnv("BACKEND_TLS_INSECURE_SKIP_VERIFY", "false"),
+			Proxy:               getEnv("BACKEND_PROXY", ""),
+			Interface:           getEnv("BACKEND_INTERFACE", ""),
+		},
+		Prometheus: struct {
+			URL string
+		}{
+			URL: getEnv("PROMETHEUS_URL", ""),
+		},
+		Forecast: struct {
+			Lookback string
+			Step     string
+		}{
+			Lookback: getEnv("FORECAST_LOOKBACK", "168h"),
+			Step:     getEnv("FORECAST_STEP", "1h"),
+		},
+		Storage: struct {
+			Volumes  string
+			Warning  string
+			Critical string
+			File     string
+		}{
+			Volumes:  getEnv("STORAGE_VOLUMES", "false"),
+			Warning:  getEnv("STORAGE_WARNING_PERCENT", "80"),
+			Critical: getEnv("STORAGE_CRITICAL_PERCENT", "90"),
+			File:     getEnv("STORAGE_FILE", ""),
+		},
+		Loki: struct {
+			URL       string
+			LogWindow string
+			LogLimit  string
+		}{
+			URL:       getEnv("LOKI_URL", ""),
+			LogWindow: getEnv("LOKI_LOG_WINDOW", "5m"),
+			LogLimit:  getEnv("LOKI_LOG_LIMIT", "500"),
+		},
+		Kube: struct {
+			Events           string
+			EventsWindow     string
+			Workloads        string
+			Namespaces       string
+			GitOps           string
+			GitOpsNamespaces string
+			Helm             string
+			HelmNamespaces   string
+			HelmPending      string
+			Cluster          string
+			ClusterPending   string
+			CronJobs         string
+			CronJobNS        string
+			CronJobMissed    string
+		}{
+			Events:           getEnv("KUBE_EVENTS", "false"),
+			EventsWindow:     getEnv("KUBE_EVENTS_WINDOW", "15m"),
+			Workloads:        getEnv("KUBE_WORKLOADS", "false"),
+			Namespaces:       getEnv("KUBE_NAMESPACES", ""),
+			GitOps:           getEnv("KUBE_GITOPS", ""),
+			GitOpsNamespaces: getEnv("KUBE_GITOPS_NAMESPACES", ""),
+			Helm:             getEnv("KUBE_HELM", "false"),
+			HelmNamespaces:   getEnv("KUBE_HELM_NAMESPACES", ""),
+			HelmPending:      getEnv("KUBE_HELM_PENDING_TIMEOUT", "15m"),
+			Cluster:          getEnv("KUBE_CLUSTER", "false"),
+			ClusterPending:   getEnv("KUBE_CLUSTER_PENDING_GRACE", "5m"),
+			CronJobs:         getEnv("KUBE_CRONJOBS", "false"),
+			CronJobNS:        getEnv("KUBE_CRONJOB_NAMESPACES", ""),
+			CronJobMissed:    getEnv("KUBE_CRONJOB_MISSED", "2"),
+		},
+		Blackbox: struct {
+			URL    string
+			Reload string
+		}{
+			URL:    getEnv("BLACKBOX_URL", ""),
+			Reload: getEnv("BLACKBOX_RELOAD", "false"),
+		},
+		Poller: struct {
+			Interval string
+		}{
+			Interval: getEnv("SYNC_INTERVAL", "30s"),
+		},
+		Flap: struct {
+			Window    string
+			Threshold string
+		}{
+			Window:    getEnv("FLAP_WINDOW", "10m"),
+			Threshold: getEnv("FLAP_THRESHOLD", "4"),
+		},
+		Degraded: struct {
+			LatencySLO string
+		}{
+			LatencySLO: getEnv("DEGRADED_LATENCY_SLO", "0s"),
+		},
+		Startup: struct {
+			Timeout    string
+			Backoff    string
+			MaxBackoff string
+			FailFast   string
+		}{
+			Timeout:    getEnv("STARTUP_WAIT_TIMEOUT", "5m"),
+			Backoff:    getEnv("STARTUP_BACKOFF", "1s"),
+			MaxBackoff: getEnv("STARTUP_MAX_BACKOFF", "30s"),
+			FailFast:   getEnv("STARTUP_FAIL_FAST", "false"),
+		},
+		Prober: struct {
+			File           string
+			MaxConcurrency string
+			Jitter         string
+			UDPAddr        string
+			UDPSecret      string
+		}{
+			File:           getEnv("PROBER_FILE", ""),
+			MaxConcurrency: getEnv("PROBER_MAX_CONCURRENCY", "32"),
+			Jitter:         getEnv("PROBER_JITTER", "0.1"),
+			UDPAddr:        getEnv("PROBER_UDP_ADDR", ""),
+			UDPSecret:      getEnv("PROBER_UDP_SECRET", ""),
+		},
+		Regions: struct {
+			DownQuorum string
+		}{
+			DownQuorum: getEnv("REGION_DOWN_QUORUM", "2"),
+		},
+		Ingest: struct {
+			Secret string
+			TTL    string
+		}{
+			Secret: getEnv("INGEST_SECRET", ""),
+			TTL:    getEnv("INGEST_TTL", "5m"),
+		},
+		MQTT: struct {
 			URL      string
+			ClientID string
 			User     string
 			Password string
+			Rules    string
+		}{
+			URL:      getEnv("MQTT_URL", ""),
+			ClientID: getEnv("MQTT_CLIENT_ID", "health-api"),
+			User:     getEnv("MQTT_USER", ""),
+			Password: getEnv("MQTT_PASSWORD", ""),
+			Rules:    getEnv("MQTT_RULES", ""),
+		},
+		DB: struct {
+			Dir string
+		}{
+			Dir: getEnv("DB_DIR", ""),
+		},
+		History: struct {
+			Retention string
+		}{
+			Retention: getEnv("HISTORY_RETENTION", "720h"),
+		},
+		Targets: struct {
+			File string
+		}{
+			File: getEnv("TARGETS_FILE", ""),
+		},
+		UI: struct {
+			Dir string
+		}{
+			Dir: getEnv("UI_DIR", ""),
+		},
+		Notify: struct {
+			WebhookURL   string
+			SMTPHost     string
+			SMTPPort     string
+			SMTPUsername string
+			SMTPPassword string
+			EmailFrom    string
+			EmailTo      string
+			EmailDigest  string
+			Channels     string
+			Routes       string
+			DefaultRoute string
+			SNMP         struct {
+				Receivers    string
+				Version      string
+				Community    string
+				User         string
+				AuthProtocol string
+				AuthPassword string
+				PrivProtocol string
+				PrivPassword string
+				EngineID     string
+				OID          string
+			}
+		}{
+			WebhookURL:   getEnv("NOTIFY_WEBHOOK_URL", ""),
+			SMTPHost:     getEnv("NOTIFY_SMTP_HOST", ""),
+			SMTPPort:     getEnv("NOTIFY_SMTP_PORT", "587"),
+			SMTPUsername: getEnv("NOTIFY_SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("NOTIFY_SMTP_PASSWORD", ""),
+			EmailFrom:    getEnv("NOTIFY_EMAIL_FROM", ""),
+			EmailTo:      getEnv("NOTIFY_EMAIL_TO", ""),
+			EmailDigest:  getEnv("NOTIFY_EMAIL_DIGEST", ""),
+			Channels:     getEnv("NOTIFY_CHANNELS", ""),
+			Routes:       getEnv("NOTIFY_ROUTES", ""),
+			DefaultRoute: getEnv("NOTIFY_DEFAULT_ROUTE", ""),
+			SNMP: struct {
+				Receivers    string
+				Version      string
+				Community    string
+				User         string
+				AuthProtocol string
+				AuthPassword string
+				PrivProtocol string
+				PrivPassword string
+				EngineID     string
+				OID          string
+			}{
+				Receivers:    getEnv("NOTIFY_SNMP_RECEIVERS", ""),
+				Version:      getEnv("NOTIFY_SNMP_VERSION", "v2c"),
+				Community:    getEnv("NOTIFY_SNMP_COMMUNITY", "public"),
+				User:         getEnv("NOTIFY_SNMP_USER", ""),
+				AuthProtocol: getEnv("NOTIFY_SNMP_AUTH_PROTOCOL", "SHA"),
+				AuthPassword: getEnv("NOTIFY_SNMP_AUTH_PASSWORD", ""),
+				PrivProtocol: getEnv("NOTIFY_SNMP_PRIV_PROTOCOL", "AES"),
+				PrivPassword: getEnv("NOTIFY_SNMP_PRIV_PASSWORD", ""),
+				EngineID:     getEnv("NOTIFY_SNMP_ENGINE_ID", ""),
+				OID:          getEnv("NOTIFY_SNMP_OID", notifybus.DefaultTrapOID),
+			},
+		},
+		Events: struct {
+			NATSURL       string
+			SubjectPrefix string
+			Source        string
+		}{
+			NATSURL:       getEnv("EVENTS_NATS_URL", ""),
+			SubjectPrefix: getEnv("EVENTS_SUBJECT_PREFIX", "healthapi"),
+			Source:        getEnv("EVENTS_SOURCE", "health-api"),
+		},
+		Remediation: struct {
+			File string
+		}{
+			File: getEnv("REMEDIATION_FILE", ""),
+		},
+		OnCall: struct {
+			Provider  string
+			URL       string
+			APIKey    string
+			Schedules string
+			CacheTTL  string
+		}{
+			Provider:  getEnv("ONCALL_PROVIDER", ""),
+			URL:       getEnv("ONCALL_URL", ""),
+			APIKey:    getEnv("ONCALL_API_KEY", ""),
+			Schedules: getEnv("ONCALL_SCHEDULES", ""),
+			CacheTTL:  getEnv("ONCALL_CACHE_TTL", "5m"),
+		},
+		Reports: struct {
+			Schedule   string
+			Period     string
+			WebhookURL string
+			S3Bucket   string
+			S3Region   string
+			S3Endpoint string
+			S3Prefix   string
+			Calendars  string
 		}{
-			URL:      getEnv("GRAFANA_URL", ""),
-			User:     getEnv("GRAFANA_USER", "admin"),
-			Password: getEnv("GRAFANA_PASSWORD", "admin"),
+			Schedule:   getEnv("REPORT_SCHEDULE", ""),
+			Period:     getEnv("REPORT_PERIOD", "monthly"),
+			WebhookURL: getEnv("REPORT_WEBHOOK_URL", ""),
+			S3Bucket:   getEnv("REPORT_S3_BUCKET", ""),
+			S3Region:   getEnv("REPORT_S3_REGION", "us-east-1"),
+			S3Endpoint: getEnv("REPORT_S3_ENDPOINT", ""),
+			S3Prefix:   getEnv("REPORT_S3_PREFIX", "reports"),
+			Calendars:  getEnv("REPORT_CALENDARS_FILE", ""),
 		},
 		Otel: struct {
-			ReporterURI string
-			Probability float64
+			ReporterURI        string
+			Protocol           string
+			Probability        string
+			Insecure           string
+			CAFile             string
+			ResourceAttributes string
+			Namespace          string
+			PodName            string
+			NodeName           string
 		}{
-			ReporterURI: getEnv("OTEL_REPORTER_URI", ""),
-			Probability: 0.05, // 5% sampling
+			ReporterURI:        getEnv("OTEL_REPORTER_URI", ""),
+			Protocol:           getEnv("OTEL_PROTOCOL", otel.ProtocolGRPC),
+			Probability:        getEnv("OTEL_SAMPLING_PROBABILITY", "0.05"), // 5% sampling
+			Insecure:           getEnv("OTEL_INSECURE", "true"),
+			CAFile:             getEnv("OTEL_CA_FILE", ""),
+			ResourceAttributes: getEnv("OTEL_RESOURCE_ATTRIBUTES", ""),
+			Namespace:          getEnv("POD_NAMESPACE", ""),
+			PodName:            getEnv("POD_NAME", ""),
+			NodeName:           getEnv("NODE_NAME", ""),
 		},
 	}
 
@@ -103,16 +782,62 @@ func run(ctx context.Context, log *logger.Logger) error {
 		"api_host", cfg.Web.APIHost,
 		"debug_host", cfg.Web.DebugHost,
 		"grafana_configured", cfg.Grafana.URL != "",
+		"grafana_secret_dir", cfg.Grafana.SecretDir,
+		"prometheus_configured", cfg.Prometheus.URL != "",
+		"loki_configured", cfg.Loki.URL != "",
+		"db_dir", cfg.DB.Dir,
 		"otel_configured", cfg.Otel.ReporterURI != "",
 	)
 
+	// -------------------------------------------------------------------------
+	// Log Sampling
+
+	sampleFirst, err := strconv.Atoi(cfg.Log.SampleFirst)
+	if err != nil {
+		return fmt.Errorf("parsing log sample first: %w", err)
+	}
+
+	sampleThereafter, err := strconv.Atoi(cfg.Log.SampleThereafter)
+	if err != nil {
+		return fmt.Errorf("parsing log sample thereafter: %w", err)
+	}
+
+	sampleWindow, err := time.ParseDuration(cfg.Log.SampleWindow)
+	if err != nil {
+		return fmt.Errorf("parsing log sample window: %w", err)
+	}
+
+	log.SetSampling(logger.Sampling{
+		First:      sampleFirst,
+		Thereafter: sampleThereafter,
+		Window:     sampleWindow,
+	})
+
 	// -------------------------------------------------------------------------
 	// Initialize OpenTelemetry
 
+	probability, err := strconv.ParseFloat(cfg.Otel.Probability, 64)
+	if err != nil {
+		return fmt.Errorf("parsing otel sampling probability: %w", err)
+	}
+
+	insecure, err := strconv.ParseBool(cfg.Otel.Insecure)
+	if err != nil {
+		return fmt.Errorf("parsing otel insecure: %w", err)
+	}
+
 	tracer, shutdown, err := otel.InitTracing(otel.Config{
-		ServiceName: "health-api",
-		ReporterURI: cfg.Otel.ReporterURI,
-		Probability: cfg.Otel.Probability,
+		ServiceName:    "health-api",
+		ServiceVersion: build,
+		ReporterURI:    cfg.Otel.ReporterURI,
+		Probability:    probability,
+		Protocol:       cfg.Otel.Protocol,
+		Insecure:       insecure,
+		CAFile:         cfg.Otel.CAFile,
+		Namespace:      cfg.Otel.Namespace,
+		PodName:        cfg.Otel.PodName,
+		NodeName:       cfg.Otel.NodeName,
+		Attributes:     parseAttributes(cfg.Otel.ResourceAttributes),
 	})
 	if err != nil {
 		return fmt.Errorf("initializing tracing: %w", err)
@@ -124,7 +849,7 @@ func run(ctx context.Context, log *logger.Logger) error {
 
 	log.Info(ctx, "startup", "status", "debug service started", "host", cfg.Web.DebugHost)
 
-	debugMux := mux.DebugMux()
+	debugMux := mux.DebugMux(log)
 	debugServer := http.Server{
 		Addr:           cfg.Web.DebugHost,
 		Handler:        debugMux,
@@ -140,86 +865,1314 @@ func run(ctx context.Context, log *logger.Logger) error {
 		}
 	}()
 
+	// SIGUSR1 toggles between debug logging and the configured level.
+	baseLevel := log.Level()
+	levelChan := make(chan os.Signal, 1)
+	signal.Notify(levelChan, syscall.SIGUSR1)
+
+	go func() {
+		for range levelChan {
+			to := logger.LevelDebug
+			if log.Level() == logger.LevelDebug {
+				to = baseLevel
+			}
+
+			log.SetLevel(to)
+			log.Info(ctx, "loglevel", "status", "level changed", "to", to.String())
+		}
+	}()
+
 	// -------------------------------------------------------------------------
 	// Initialize Business Layer
 
-	grafanaStore := grafanastore.NewStore(log, cfg.Grafana.URL, cfg.Grafana.User, cfg.Grafana.Password)
-	healthBus := healthbus.NewBusiness(log, grafanaStore)
+	delegate := delegate.New(log)
 
-	// -------------------------------------------------------------------------
-	// Start API Service
+	db, err := jsondb.Open(cfg.DB.Dir)
+	if err != nil {
+		return fmt.Errorf("opening db: %w", err)
+	}
 
-	log.Info(ctx, "startup", "status", "initializing API", "host", cfg.Web.APIHost)
+	targetStore, err := targetdb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing target store: %w", err)
+	}
+	targetBus := targetbus.NewBusiness(log, delegate, targetStore)
 
-	// Create route adder
-	routeAdder := Routes{
-		HealthBus: healthBus,
+	if cfg.Targets.File != "" {
+		if err := targetBus.LoadFile(ctx, cfg.Targets.File); err != nil {
+			return fmt.Errorf("loading targets: %w", err)
+		}
 	}
 
-	// Create API app
-	apiApp := mux.WebAPI(mux.Config{
-		Log:    log,
-		Tracer: tracer,
-	}, routeAdder, cfg.Web.CORSOrigin)
+	// Credentials from a mounted Secret take precedence over the env vars
+	// and follow its rotation without a restart.
+	if cfg.Grafana.SecretDir != "" {
+		creds, err := secret.Read(cfg.Grafana.SecretDir, "username", "password")
+		if err != nil {
+			return fmt.Errorf("reading grafana secret: %w", err)
+		}
+		cfg.Grafana.User = creds["username"]
+		cfg.Grafana.Password = creds["password"]
+	}
 
-	apiServer := http.Server{
-		Addr:           cfg.Web.APIHost,
-		Handler:        apiApp,
-		ReadTimeout:    cfg.Web.ReadTimeout,
-		WriteTimeout:   cfg.Web.WriteTimeout,
-		IdleTimeout:    cfg.Web.IdleTimeout,
-		MaxHeaderBytes: 1 << 20,
+	// All backend clients share one pool, so dashboards fanning out many
+	// queries reuse connections instead of dialing for each one.
+	poolCfg := web.PoolConfig{
+		DisableHTTP2:       cfg.Backend.HTTP2 != "true",
+		CAFile:             cfg.Backend.CAFile,
+		InsecureSkipVerify: cfg.Backend.InsecureSkipVerify == "true",
 	}
 
-	serverErrors := make(chan error, 1)
+	for _, v := range []struct {
+		name  string
+		value string
+		dst   *int
+	}{
+		{"backend max idle conns", cfg.Backend.MaxIdleConns, &poolCfg.MaxIdleConns},
+		{"backend max idle conns per host", cfg.Backend.MaxIdleConnsPerHost, &poolCfg.MaxIdleConnsPerHost},
+		{"backend max conns per host", cfg.Backend.MaxConnsPerHost, &poolCfg.MaxConnsPerHost},
+	} {
+		if *v.dst, err = strconv.Atoi(v.value); err != nil || *v.dst < 0 {
+			return fmt.Errorf("parsing %s: invalid value %q", v.name, v.value)
+		}
+	}
 
-	go func() {
-		log.Info(ctx, "startup", "status", "api router started", "host", apiServer.Addr)
-		serverErrors <- apiServer.ListenAndServe()
-	}()
+	if poolCfg.IdleConnTimeout, err = time.ParseDuration(cfg.Backend.IdleConnTimeout); err != nil {
+		return fmt.Errorf("parsing backend idle conn timeout: %w", err)
+	}
 
-	// -------------------------------------------------------------------------
-	// Shutdown
+	if poolCfg.KeepAlive, err = time.ParseDuration(cfg.Backend.KeepAlive); err != nil {
+		return fmt.Errorf("parsing backend keep alive: %w", err)
+	}
 
-	shutdownChan := make(chan os.Signal, 1)
-	signal.Notify(shutdownChan, syscall.SIGINT, syscall.SIGTERM)
+	backendPool, err := web.NewPoolTransport(poolCfg)
+	if err != nil {
+		return fmt.Errorf("configuring backend transport: %w", err)
+	}
 
-	select {
-	case err := <-serverErrors:
-		return fmt.Errorf("server error: %w", err)
+	// Backends with their own proxy or source interface get a copy of the
+	// pool routed accordingly.
+	egressPools, err := backendEgressPools(backendPool, parseAttributes(cfg.Backend.Proxy), parseAttributes(cfg.Backend.Interface))
+	if err != nil {
+		return fmt.Errorf("configuring backend egress: %w", err)
+	}
 
-	case sig := <-shutdownChan:
-		log.Info(ctx, "shutdown", "status", "shutdown started", "signal", sig)
-		defer log.Info(ctx, "shutdown", "status", "shutdown complete", "signal", sig)
+	poolFor := func(backend string) *http.Transport {
+		if t, ok := egressPools[backend]; ok {
+			return t
+		}
+		return backendPool
+	}
 
-		ctx, cancel := context.WithTimeout(ctx, cfg.Web.ShutdownTimeout)
-		defer cancel()
+	var deps []healthbus.Dependency
+	var backends []multistore.Backend
+	var stores []string
+
+	var grafanaStore *grafanastore.Store
+	if cfg.Grafana.URL != "" {
+		grafanaStore = grafanastore.NewStore(log, cfg.Grafana.URL, cfg.Grafana.User, cfg.Grafana.Password,
+			backendTransport("grafana", metrics.NewGrafanaTransport(poolFor("grafana"))))
+
+		deps = append(deps, healthbus.Dependency{
+			Name:     "grafana",
+			Required: true,
+			Checker:  grafanaStore,
+		})
+		backends = append(backends, multistore.Backend{Name: "grafana", Storer: metricstore.NewStore("grafana", grafanaStore)})
+		stores = append(stores, "grafana")
+	}
+
+	// Alert history comes from Loki when Grafana writes its state history
+	// there, otherwise from Grafana's own history API.
+	var alertHistoryBus *alerthistorybus.Business
+	var grafanaHistoryStore *grafanahistory.Store
+	switch {
+	case cfg.Loki.URL != "":
+		lokiStore := lokihistory.NewStore(log, cfg.Loki.URL, backendTransport("loki", poolFor("loki")))
+
+		deps = append(deps, healthbus.Dependency{
+			Name:    "loki",
+			Checker: lokiStore,
+		})
+
+		alertHistoryBus = alerthistorybus.NewBusiness(log, lokiStore)
 
-		if err := apiServer.Shutdown(ctx); err != nil {
-			apiServer.Close()
-			return fmt.Errorf("could not stop api server gracefully: %w", err)
+	case cfg.Grafana.URL != "":
+		grafanaHistoryStore = grafanahistory.NewStore(log, cfg.Grafana.URL, cfg.Grafana.User, cfg.Grafana.Password,
+			backendTransport("grafana", metrics.NewGrafanaTransport(poolFor("grafana"))))
+
+		alertHistoryBus = alerthistorybus.NewBusiness(log, grafanaHistoryStore)
+	}
+
+	var kubeClient *kube.Client
+	if cfg.Kube.Events == "true" || cfg.Kube.Workloads == "true" || cfg.Kube.GitOps != "" || cfg.Kube.Helm == "true" || cfg.Kube.Cluster == "true" || cfg.Kube.CronJobs == "true" {
+		if kubeClient, err = kube.InCluster(); err != nil {
+			return fmt.Errorf("initializing kubernetes client: %w", err)
 		}
 
-		if err := debugServer.Shutdown(ctx); err != nil {
-			debugServer.Close()
-			return fmt.Errorf("could not stop debug server gracefully: %w", err)
+		deps = append(deps, healthbus.Dependency{
+			Name:    "kubernetes",
+			Checker: kubeClient,
+		})
+	}
+
+	if cfg.Kube.Workloads == "true" {
+		kubeStore := kubestore.NewStore(log, kubeClient, splitList(cfg.Kube.Namespaces))
+
+		// The informers fill the store's cache in the background; queries
+		// wait for the first lists.
+		go kubeStore.Run(ctx)
+
+		backends = append(backends, multistore.Backend{Name: "kubernetes", Storer: metricstore.NewStore("kubernetes", kubeStore)})
+		stores = append(stores, "kubernetes")
+	}
+
+	if cfg.Kube.GitOps != "" {
+		gitopsStore, err := gitopsstore.NewStore(log, kubeClient, splitList(cfg.Kube.GitOps), splitList(cfg.Kube.GitOpsNamespaces))
+		if err != nil {
+			return fmt.Errorf("initializing gitops store: %w", err)
 		}
+
+		go gitopsStore.Run(ctx)
+
+		backends = append(backends, multistore.Backend{Name: "gitops", Storer: metricstore.NewStore("gitops", gitopsStore)})
+		stores = append(stores, "gitops")
 	}
 
-	return nil
-}
+	if cfg.Kube.Helm == "true" {
+		helmPending, err := time.ParseDuration(cfg.Kube.HelmPending)
+		if err != nil {
+			return fmt.Errorf("parsing helm pending timeout: %w", err)
+		}
 
-// Routes implements mux.RouteAdder.
-type Routes struct {
-	HealthBus *healthbus.Business
-}
+		helmStore := helmstore.NewStore(log, kubeClient, splitList(cfg.Kube.HelmNamespaces), helmPending)
+		go helmStore.Run(ctx)
 
-// Add registers all routes for the service.
-func (r Routes) Add(app *web.App, cfg mux.Config) {
-	healthapp.Routes(app, healthapp.Config{
-		Log:       cfg.Log,
-		HealthBus: r.HealthBus,
-	})
+		backends = append(backends, multistore.Backend{Name: "helm", Storer: metricstore.NewStore("helm", helmStore)})
+		stores = append(stores, "helm")
+	}
+
+	if cfg.Kube.Cluster == "true" {
+		clusterPending, err := time.ParseDuration(cfg.Kube.ClusterPending)
+		if err != nil {
+			return fmt.Errorf("parsing cluster pending grace: %w", err)
+		}
+
+		clusterStore := clusterstore.NewStore(log, kubeClient, clusterPending)
+		go clusterStore.Run(ctx)
+
+		backends = append(backends, multistore.Backend{Name: "cluster", Storer: metricstore.NewStore("cluster", clusterStore)})
+		stores = append(stores, "cluster")
+	}
+
+	if cfg.Kube.CronJobs == "true" {
+		cronJobMissed, err := strconv.Atoi(cfg.Kube.CronJobMissed)
+		if err != nil {
+			return fmt.Errorf("parsing cronjob missed schedules: %w", err)
+		}
+
+		cronJobStore := cronjobstore.NewStore(log, kubeClient, splitList(cfg.Kube.CronJobNS), cronJobMissed)
+		go cronJobStore.Run(ctx)
+
+		backends = append(backends, multistore.Backend{Name: "cronjob", Storer: metricstore.NewStore("cronjob", cronJobStore)})
+		stores = append(stores, "cronjob")
+	}
+
+	var prometheusBus *prometheusbus.Business
+	var forecastBus *forecastbus.Business
+	var statsStore comparebus.Storer
+	var storageBus *storagebus.Business
+	if cfg.Prometheus.URL != "" {
+		prometheusStore, err := prometheusstore.NewStore(log, cfg.Prometheus.URL, backendTransport("prometheus", poolFor("prometheus")))
+		if err != nil {
+			return fmt.Errorf("initializing prometheus store: %w", err)
+		}
+
+		deps = append(deps, healthbus.Dependency{
+			Name:     "prometheus",
+			Required: true,
+			Checker:  prometheusStore,
+		})
+		backends = append(backends, multistore.Backend{Name: "prometheus", Storer: metricstore.NewStore("prometheus", prometheusStore)})
+		stores = append(stores, "prometheus")
+
+		prometheusBus = prometheusbus.NewBusiness(log, prometheusStore)
+
+		forecastLookback, err := time.ParseDuration(cfg.Forecast.Lookback)
+		if err != nil {
+			return fmt.Errorf("parsing forecast lookback: %w", err)
+		}
+
+		forecastStep, err := time.ParseDuration(cfg.Forecast.Step)
+		if err != nil {
+			return fmt.Errorf("parsing forecast step: %w", err)
+		}
+
+		forecastBus = forecastbus.NewBusiness(log, prometheusStore, forecastbus.Config{Lookback: forecastLookback, Step: forecastStep})
+		statsStore = prometheusStore
+
+		if cfg.Storage.Volumes == "true" {
+			if storageBus, err = newStorageBus(log, cfg.Storage.Warning, cfg.Storage.Critical, cfg.Storage.File, prometheusStore); err != nil {
+				return err
+			}
+
+			backends = append(backends, multistore.Backend{Name: "storage", Storer: metricstore.NewStore("storage", storagestore.NewStore(storageBus))})
+			stores = append(stores, "storage")
+		}
+	}
+
+	var proberBus *proberbus.Business
+	if cfg.Prober.File != "" {
+		checks, err := proberbus.LoadFile(cfg.Prober.File)
+		if err != nil {
+			return fmt.Errorf("loading prober checks: %w", err)
+		}
+
+		proberConcurrency, err := strconv.Atoi(cfg.Prober.MaxConcurrency)
+		if err != nil {
+			return fmt.Errorf("parsing prober max concurrency: %w", err)
+		}
+
+		proberJitter, err := strconv.ParseFloat(cfg.Prober.Jitter, 64)
+		if err != nil {
+			return fmt.Errorf("parsing prober jitter: %w", err)
+		}
+
+		proberBus = proberbus.NewBusiness(log, checks, nil, targetBus, proberbus.Config{
+			MaxConcurrency: proberConcurrency,
+			Jitter:         proberJitter,
+		})
+
+		backends = append(backends, multistore.Backend{Name: "prober", Storer: metricstore.NewStore("prober", proberstore.NewStore(proberBus))})
+		stores = append(stores, "prober")
+	}
+
+	var ingestBus *ingestbus.Business
+	if cfg.Ingest.Secret != "" || cfg.MQTT.URL != "" {
+		ingestTTL, err := time.ParseDuration(cfg.Ingest.TTL)
+		if err != nil {
+			return fmt.Errorf("parsing ingest ttl: %w", err)
+		}
+
+		ingestStore, err := ingestdb.NewStore(log, db)
+		if err != nil {
+			return fmt.Errorf("initializing ingest store: %w", err)
+		}
+		ingestBus = ingestbus.NewBusiness(log, ingestStore, ingestTTL)
+
+		backends = append(backends, multistore.Backend{Name: "ingest", Storer: metricstore.NewStore("ingest", ingeststore.NewStore(ingestBus))})
+		stores = append(stores, "ingest")
+	}
+
+	flapWindow, err := time.ParseDuration(cfg.Flap.Window)
+	if err != nil {
+		return fmt.Errorf("parsing flap window: %w", err)
+	}
+
+	flapThreshold, err := strconv.Atoi(cfg.Flap.Threshold)
+	if err != nil {
+		return fmt.Errorf("parsing flap threshold: %w", err)
+	}
+
+	latencySLO, err := time.ParseDuration(cfg.Degraded.LatencySLO)
+	if err != nil {
+		return fmt.Errorf("parsing degraded latency slo: %w", err)
+	}
+
+	startupTimeout, err := time.ParseDuration(cfg.Startup.Timeout)
+	if err != nil {
+		return fmt.Errorf("parsing startup wait timeout: %w", err)
+	}
+
+	startupBackoff, err := time.ParseDuration(cfg.Startup.Backoff)
+	if err != nil {
+		return fmt.Errorf("parsing startup backoff: %w", err)
+	}
+
+	startupMaxBackoff, err := time.ParseDuration(cfg.Startup.MaxBackoff)
+	if err != nil {
+		return fmt.Errorf("parsing startup max backoff: %w", err)
+	}
+
+	regionDownQuorum, err := strconv.Atoi(cfg.Regions.DownQuorum)
+	if err != nil {
+		return fmt.Errorf("parsing region down quorum: %w", err)
+	}
+
+	healthCfg := healthbus.Config{
+		Flap: healthbus.FlapConfig{
+			Window:    flapWindow,
+			Threshold: flapThreshold,
+		},
+		LatencySLO: latencySLO,
+		Startup: healthbus.StartupConfig{
+			Timeout:    startupTimeout,
+			Backoff:    startupBackoff,
+			MaxBackoff: startupMaxBackoff,
+			FailFast:   cfg.Startup.FailFast == "true",
+		},
+		Regions: healthbus.RegionConfig{
+			DownQuorum: regionDownQuorum,
+		},
+	}
+
+	maintenanceStore, err := maintenancedb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing maintenance store: %w", err)
+	}
+	maintenanceBus := maintenancebus.NewBusiness(log, maintenanceStore)
+
+	annotationStore, err := annotationdb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing annotation store: %w", err)
+	}
+	annotationBus := annotationbus.NewBusiness(log, annotationStore)
+
+	snoozeStore, err := snoozedb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing snooze store: %w", err)
+	}
+	snoozeBus := snoozebus.NewBusiness(log, snoozeStore)
+
+	viewStore, err := viewdb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing view store: %w", err)
+	}
+	viewBus := viewbus.NewBusiness(log, viewStore)
+
+	healthBus := healthbus.NewBusiness(log, delegate, sharedstore.NewStore(multistore.NewStore(log, backends...), cfg.Web.QueryTimeout), targetBus, maintenanceBus, snoozeBus, healthCfg, deps...)
+
+	incidentStore, err := incidentdb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing incident store: %w", err)
+	}
+	incidentBus := incidentbus.NewBusiness(log, delegate, incidentStore, healthBus)
+
+	var compareBus *comparebus.Business
+	if statsStore != nil {
+		compareBus = comparebus.NewBusiness(log, statsStore, healthBus)
+	}
+
+	historyRetention, err := time.ParseDuration(cfg.History.Retention)
+	if err != nil {
+		return fmt.Errorf("parsing history retention: %w", err)
+	}
+
+	historyStore, err := historydb.NewStore(log, db)
+	if err != nil {
+		return fmt.Errorf("initializing history store: %w", err)
+	}
+	historyBus := historybus.NewBusiness(log, delegate, historyStore, historyRetention)
+
+	var logBus *logbus.Business
+	if cfg.Loki.URL != "" {
+		logWindow, err := time.ParseDuration(cfg.Loki.LogWindow)
+		if err != nil {
+			return fmt.Errorf("parsing loki log window: %w", err)
+		}
+
+		logLimit, err := strconv.Atoi(cfg.Loki.LogLimit)
+		if err != nil {
+			return fmt.Errorf("parsing loki log limit: %w", err)
+		}
+
+		logStore := lokilog.NewStore(log, cfg.Loki.URL, backendTransport("loki", poolFor("loki")))
+		logBus = logbus.NewBusiness(log, logStore, targetBus, historyBus, logbus.Config{
+			Window: logWindow,
+			Limit:  logLimit,
+		})
+	}
+
+	var kubeEventBus *kubeeventbus.Business
+	if cfg.Kube.Events == "true" {
+		eventsWindow, err := time.ParseDuration(cfg.Kube.EventsWindow)
+		if err != nil {
+			return fmt.Errorf("parsing kube events window: %w", err)
+		}
+
+		kubeEventBus = kubeeventbus.NewBusiness(log, kubeevent.NewStore(log, kubeClient), targetBus, historyBus, eventsWindow)
+	}
+
+	var remediationBus *remediationbus.Business
+	if cfg.Remediation.File != "" {
+		rules, err := remediationbus.LoadFile(cfg.Remediation.File)
+		if err != nil {
+			return fmt.Errorf("loading remediation rules: %w", err)
+		}
+
+		var cluster remediationbus.Cluster
+		if remediationbus.NeedsCluster(rules) {
+			if kubeClient == nil {
+				if kubeClient, err = kube.InCluster(); err != nil {
+					return fmt.Errorf("initializing kubernetes client: %w", err)
+				}
+			}
+			cluster = kubeaction.NewCluster(log, kubeClient)
+		}
+
+		remediationStore, err := remediationdb.NewStore(log, db)
+		if err != nil {
+			return fmt.Errorf("initializing remediation store: %w", err)
+		}
+		remediationBus = remediationbus.NewBusiness(log, delegate, remediationStore, cluster, rules)
+	}
+
+	// Alerts and notifications name who is on call for the owning team.
+	var onCallStore oncallbus.Storer
+	onCallURL := cfg.OnCall.URL
+	switch cfg.OnCall.Provider {
+	case "":
+	case "pagerduty":
+		if onCallURL == "" {
+			onCallURL = pagerdutystore.DefaultURL
+		}
+		onCallStore = pagerdutystore.NewStore(log, onCallURL, cfg.OnCall.APIKey, backendTransport("pagerduty", poolFor("pagerduty")))
+	case "opsgenie":
+		if onCallURL == "" {
+			onCallURL = opsgeniestore.DefaultURL
+		}
+		onCallStore = opsgeniestore.NewStore(log, onCallURL, cfg.OnCall.APIKey, backendTransport("opsgenie", poolFor("opsgenie")))
+	default:
+		return fmt.Errorf("unknown on-call provider %q", cfg.OnCall.Provider)
+	}
+
+	var onCallBus *oncallbus.Business
+	if onCallStore != nil {
+		onCallCacheTTL, err := time.ParseDuration(cfg.OnCall.CacheTTL)
+		if err != nil {
+			return fmt.Errorf("parsing on-call cache ttl: %w", err)
+		}
+
+		onCallBus = oncallbus.NewBusiness(log, onCallStore, oncallbus.Config{
+			Schedules: parseAttributes(cfg.OnCall.Schedules),
+			CacheTTL:  onCallCacheTTL,
+		})
+	}
+
+	// channels names every notifier that routes can deliver to; each one
+	// records its deliveries for the system endpoint.
+	var notifiers []notifybus.Notifier
+	channels := make(map[string]*notifybus.Channel)
+
+	if cfg.Notify.WebhookURL != "" {
+		webhook := notifybus.NewChannel("webhook", notifybus.NewWebhookNotifier(cfg.Notify.WebhookURL))
+		notifiers = append(notifiers, webhook)
+		channels["webhook"] = webhook
+	}
+
+	if cfg.Notify.SNMP.Receivers != "" {
+		snmpNotifier, err := notifybus.NewSNMPNotifier(snmp.Config{
+			Version:      cfg.Notify.SNMP.Version,
+			Community:    cfg.Notify.SNMP.Community,
+			User:         cfg.Notify.SNMP.User,
+			AuthProtocol: cfg.Notify.SNMP.AuthProtocol,
+			AuthPassword: cfg.Notify.SNMP.AuthPassword,
+			PrivProtocol: cfg.Notify.SNMP.PrivProtocol,
+			PrivPassword: cfg.Notify.SNMP.PrivPassword,
+			EngineID:     cfg.Notify.SNMP.EngineID,
+		}, splitList(cfg.Notify.SNMP.Receivers), cfg.Notify.SNMP.OID)
+		if err != nil {
+			return fmt.Errorf("constructing snmp notifier: %w", err)
+		}
+		snmpChannel := notifybus.NewChannel("snmp", snmpNotifier)
+		notifiers = append(notifiers, snmpChannel)
+		channels["snmp"] = snmpChannel
+	}
+
+	var emailCfg notifybus.EmailConfig
+	if cfg.Notify.SMTPHost != "" {
+		smtpPort, err := strconv.Atoi(cfg.Notify.SMTPPort)
+		if err != nil {
+			return fmt.Errorf("parsing smtp port: %w", err)
+		}
+
+		emailCfg = notifybus.EmailConfig{
+			Host:     cfg.Notify.SMTPHost,
+			Port:     smtpPort,
+			Username: cfg.Notify.SMTPUsername,
+			Password: cfg.Notify.SMTPPassword,
+			From:     cfg.Notify.EmailFrom,
+		}
+	}
+
+	var emailDigest *notifybus.EmailNotifier
+	if cfg.Notify.SMTPHost != "" && cfg.Notify.EmailTo != "" {
+		ec := emailCfg
+		ec.To = splitList(cfg.Notify.EmailTo)
+		ec.Digest = cfg.Notify.EmailDigest != ""
+
+		email := notifybus.NewEmailNotifier(log, ec, incidentBus)
+		emailChannel := notifybus.NewChannel("email", email)
+		notifiers = append(notifiers, emailChannel)
+		channels["email"] = emailChannel
+
+		if cfg.Notify.EmailDigest != "" {
+			emailDigest = email
+		}
+	}
+
+	for name, dest := range parseAttributes(cfg.Notify.Channels) {
+		if _, ok := channels[name]; ok {
+			return fmt.Errorf("notify channel %s: name already taken", name)
+		}
+
+		switch {
+		case strings.HasPrefix(dest, "mailto:"):
+			if cfg.Notify.SMTPHost == "" {
+				return fmt.Errorf("notify channel %s: email channels need NOTIFY_SMTP_HOST", name)
+			}
+			ec := emailCfg
+			ec.To = []string{strings.TrimPrefix(dest, "mailto:")}
+			channels[name] = notifybus.NewChannel(name, notifybus.NewEmailNotifier(log, ec, incidentBus))
+
+		case strings.HasPrefix(dest, "http://"), strings.HasPrefix(dest, "https://"):
+			channels[name] = notifybus.NewChannel(name, notifybus.NewWebhookNotifier(dest))
+
+		default:
+			return fmt.Errorf("notify channel %s: unsupported destination %q", name, dest)
+		}
+	}
+
+	if cfg.Notify.Routes != "" {
+		router, err := notifyRouter(channels, parseAttributes(cfg.Notify.Routes), splitList(cfg.Notify.DefaultRoute), notifiers)
+		if err != nil {
+			return fmt.Errorf("constructing notify routes: %w", err)
+		}
+		notifiers = []notifybus.Notifier{router}
+	}
+	notifyBus := notifybus.NewBusiness(log, delegate, onCallBus, notifiers...)
+
+	if cfg.Events.NATSURL != "" {
+		publisher := nats.New(nats.Config{URL: cfg.Events.NATSURL, Name: cfg.Events.Source})
+		defer publisher.Close()
+
+		eventbus.NewBusiness(log, delegate, publisher, eventbus.Config{
+			SubjectPrefix: cfg.Events.SubjectPrefix,
+			Source:        cfg.Events.Source,
+		})
+	}
+
+	var publishers []reportbus.Publisher
+	if cfg.Reports.WebhookURL != "" {
+		publishers = append(publishers, reportbus.NewWebhookPublisher(cfg.Reports.WebhookURL))
+	}
+	if cfg.Reports.S3Bucket != "" {
+		s3Client := s3.New(s3.Config{
+			Bucket:          cfg.Reports.S3Bucket,
+			Region:          cfg.Reports.S3Region,
+			Endpoint:        cfg.Reports.S3Endpoint,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		})
+		publishers = append(publishers, reportbus.NewS3Publisher(s3Client, cfg.Reports.S3Prefix))
+	}
+
+	var reportCfg reportbus.Config
+	if cfg.Reports.Calendars != "" {
+		if reportCfg, err = reportbus.LoadConfig(cfg.Reports.Calendars); err != nil {
+			return fmt.Errorf("loading report calendars: %w", err)
+		}
+	}
+
+	reportBus, err := reportbus.NewBusiness(log, incidentBus, targetBus, maintenanceBus, reportCfg, publishers...)
+	if err != nil {
+		return fmt.Errorf("constructing report business: %w", err)
+	}
+
+	var alertRuleBus *alertrulebus.Business
+	var provisioner *grafanarule.Store
+	if cfg.Grafana.URL != "" {
+		alertFor, err := time.ParseDuration(cfg.Grafana.AlertFor)
+		if err != nil {
+			return fmt.Errorf("parsing grafana alert for: %w", err)
+		}
+
+		provisioner = grafanarule.NewStore(log, cfg.Grafana.URL, cfg.Grafana.User, cfg.Grafana.Password, cfg.Grafana.DatasourceUID,
+			backendTransport("grafana", metrics.NewGrafanaTransport(poolFor("grafana"))))
+
+		alertRuleBus = alertrulebus.NewBusiness(log, delegate, targetBus, provisioner, alertrulebus.Config{
+			FolderUID: cfg.Grafana.AlertFolder,
+			RuleGroup: cfg.Grafana.AlertGroup,
+			For:       alertFor,
+			OnSave:    cfg.Grafana.AlertProvision == "true",
+		})
+	}
+
+	var dashboardBus *dashboardbus.Business
+	var dashboardStore *grafanadashboard.Store
+	if cfg.Grafana.URL != "" && cfg.Grafana.DashboardProvision == "true" {
+		dashboardStore = grafanadashboard.NewStore(log, cfg.Grafana.URL, cfg.Grafana.User, cfg.Grafana.Password, cfg.Grafana.DatasourceUID,
+			backendTransport("grafana", metrics.NewGrafanaTransport(poolFor("grafana"))))
+
+		dashboardBus = dashboardbus.NewBusiness(log, delegate, targetBus, dashboardStore, dashboardbus.Config{
+			UID:       cfg.Grafana.DashboardUID,
+			Title:     cfg.Grafana.DashboardTitle,
+			FolderUID: cfg.Grafana.DashboardFolder,
+		})
+	}
+
+	var probeBus *probebus.Business
+	if cfg.Blackbox.URL != "" {
+		blackboxStore := blackboxstore.NewStore(log, cfg.Blackbox.URL, backendTransport("blackbox", poolFor("blackbox")))
+		probeBus = probebus.NewBusiness(log, delegate, blackboxStore, cfg.Blackbox.Reload == "true")
+	}
+
+	// -------------------------------------------------------------------------
+	// Startup Gate
+
+	// Fail fast refuses to start at all; otherwise the API starts right away
+	// and readiness reports starting until the backends answer.
+	if healthCfg.Startup.FailFast {
+		if err := healthBus.WaitForDependencies(ctx); err != nil {
+			return fmt.Errorf("startup gate: %w", err)
+		}
+	}
+
+	// -------------------------------------------------------------------------
+	// Start Background Workers
+
+	bgCtx, bgCancel := context.WithCancel(ctx)
+	defer bgCancel()
+
+	// Status changes seen by queries and the poller are delivered here, off
+	// the request path.
+	go healthBus.RunEvents(bgCtx)
+
+	// Pauses whose TTL ran out are resumed for good, which also resumes
+	// their alert rules.
+	go targetBus.RunPauseExpiry(bgCtx, time.Minute)
+
+	if proberBus != nil {
+		log.Info(ctx, "startup", "status", "prober started", "checks", proberBus.Checks())
+		go proberBus.Run(bgCtx)
+	}
+
+	if remediationBus != nil {
+		log.Info(ctx, "startup", "status", "remediation started", "rules", len(remediationBus.Rules()))
+		go remediationBus.Run(bgCtx)
+	}
+
+	if cfg.MQTT.URL != "" {
+		rules, err := mqttRules(cfg.MQTT.Rules)
+		if err != nil {
+			return fmt.Errorf("parsing mqtt rules: %w", err)
+		}
+
+		mqttCfg := mqtt.Config{
+			URL:      cfg.MQTT.URL,
+			ClientID: cfg.MQTT.ClientID,
+			Username: cfg.MQTT.User,
+			Password: cfg.MQTT.Password,
+		}
+
+		log.Info(ctx, "startup", "status", "mqtt bridge started", "url", cfg.MQTT.URL, "rules", len(rules))
+		go ingestapp.ServeMQTT(bgCtx, log, mqttCfg, rules, ingestBus)
+	}
+
+	if cfg.Prober.UDPAddr != "" {
+		if proberBus == nil || cfg.Prober.UDPSecret == "" {
+			return fmt.Errorf("udp heartbeats: need PROBER_FILE and PROBER_UDP_SECRET")
+		}
+
+		conn, err := net.ListenPacket("udp", cfg.Prober.UDPAddr)
+		if err != nil {
+			return fmt.Errorf("listening for udp heartbeats: %w", err)
+		}
+
+		log.Info(ctx, "startup", "status", "udp heartbeat listener started", "addr", conn.LocalAddr().String())
+		go proberapp.ServeUDP(bgCtx, log, conn, proberBus, []byte(cfg.Prober.UDPSecret))
+	}
+
+	syncInterval, err := time.ParseDuration(cfg.Poller.Interval)
+	if err != nil {
+		return fmt.Errorf("parsing sync interval: %w", err)
+	}
+
+	if syncInterval > 0 && len(backends) > 0 {
+		log.Info(ctx, "startup", "status", "health poller started", "interval", syncInterval)
+		go healthBus.RunPoller(bgCtx, syncInterval)
+	}
+
+	metrics.RegisterSnapshotAge(healthBus.SnapshotTime)
+	metrics.RegisterHealthState(healthBus.QuerySnapshot)
+
+	if dashboardBus != nil {
+		go func() {
+			d, saved, err := dashboardBus.Reconcile(bgCtx)
+			if err != nil {
+				log.Error(bgCtx, "dashboard", "status", "reconciling dashboard failed", "error", err)
+				return
+			}
+			log.Info(bgCtx, "dashboard", "status", "dashboard reconciled", "uid", d.UID, "targets", len(d.Targets), "saved", saved)
+		}()
+	}
+
+	if cfg.Grafana.SecretDir != "" && grafanaStore != nil {
+		reload, err := time.ParseDuration(cfg.Grafana.SecretReload)
+		if err != nil {
+			return fmt.Errorf("parsing grafana secret reload: %w", err)
+		}
+
+		current := secret.Values{"username": cfg.Grafana.User, "password": cfg.Grafana.Password}
+
+		log.Info(ctx, "startup", "status", "grafana secret watcher started", "dir", cfg.Grafana.SecretDir, "interval", reload)
+		go secret.Watch(bgCtx, reload, cfg.Grafana.SecretDir, []string{"username", "password"}, current, func(creds secret.Values, err error) {
+			if err != nil {
+				log.Warn(bgCtx, "secret", "status", "reading grafana secret failed, keeping current credentials", "error", err)
+				return
+			}
+
+			grafanaStore.SetCredentials(creds["username"], creds["password"])
+			provisioner.SetCredentials(creds["username"], creds["password"])
+			if grafanaHistoryStore != nil {
+				grafanaHistoryStore.SetCredentials(creds["username"], creds["password"])
+			}
+			if dashboardStore != nil {
+				dashboardStore.SetCredentials(creds["username"], creds["password"])
+			}
+			log.Info(bgCtx, "secret", "status", "grafana credentials reloaded")
+		})
+	}
+
+	if emailDigest != nil {
+		sched, err := cron.Parse(cfg.Notify.EmailDigest)
+		if err != nil {
+			return fmt.Errorf("parsing email digest schedule: %w", err)
+		}
+
+		log.Info(ctx, "startup", "status", "email digest started", "schedule", cfg.Notify.EmailDigest)
+		go emailDigest.RunDigest(bgCtx, sched)
+	}
+
+	if cfg.Reports.Schedule != "" {
+		sched, err := cron.Parse(cfg.Reports.Schedule)
+		if err != nil {
+			return fmt.Errorf("parsing report schedule: %w", err)
+		}
+
+		log.Info(ctx, "startup", "status", "report scheduler started", "schedule", cfg.Reports.Schedule, "period", cfg.Reports.Period)
+		go reportBus.RunSchedule(bgCtx, sched, cfg.Reports.Period)
+	}
+
+	// -------------------------------------------------------------------------
+	// Start API Service
+
+	log.Info(ctx, "startup", "status", "initializing API", "host", cfg.Web.APIHost)
+
+	var v1Deprecation mid.Deprecation
+	if cfg.Web.V1Deprecation != "" {
+		if v1Deprecation.Since, err = time.Parse(time.RFC3339, cfg.Web.V1Deprecation); err != nil {
+			return fmt.Errorf("parsing api v1 deprecation: %w", err)
+		}
+	}
+	if cfg.Web.V1Sunset != "" {
+		if v1Deprecation.Sunset, err = time.Parse(time.RFC3339, cfg.Web.V1Sunset); err != nil {
+			return fmt.Errorf("parsing api v1 sunset: %w", err)
+		}
+	}
+
+	// Create route adder
+	routeAdder := Routes{
+		Build:            build,
+		StartedAt:        startedAt,
+		Stores:           stores,
+		HealthBus:        healthBus,
+		NotifyBus:        notifyBus,
+		TargetBus:        targetBus,
+		IncidentBus:      incidentBus,
+		HistoryBus:       historyBus,
+		OnCallBus:        onCallBus,
+		MaintenanceBus:   maintenanceBus,
+		AnnotationBus:    annotationBus,
+		SnoozeBus:        snoozeBus,
+		ViewBus:          viewBus,
+		ReportBus:        reportBus,
+		AlertRuleBus:     alertRuleBus,
+		ProbeBus:         probeBus,
+		ProberBus:        proberBus,
+		PrometheusBus:    prometheusBus,
+		AlertHistoryBus:  alertHistoryBus,
+		ForecastBus:      forecastBus,
+		CompareBus:       compareBus,
+		StorageBus:       storageBus,
+		LogBus:           logBus,
+		KubeEventBus:     kubeEventBus,
+		RemediationBus:   remediationBus,
+		IngestBus:        ingestBus,
+		IngestSecret:     cfg.Ingest.Secret,
+		ReadinessTimeout: cfg.Web.ReadinessTimeout,
+		QueryTimeout:     cfg.Web.QueryTimeout,
+		RequestTimeout:   cfg.Web.RequestTimeout,
+		V1Deprecation:    v1Deprecation,
+		UIDir:            cfg.UI.Dir,
+	}
+
+	var apiKeys []auth.APIKey
+	if cfg.Auth.APIKeysFile != "" {
+		if apiKeys, err = auth.LoadAPIKeys(cfg.Auth.APIKeysFile); err != nil {
+			return fmt.Errorf("loading api keys: %w", err)
+		}
+	}
+
+	ath, err := auth.New(auth.Config{
+		JWTSecret: cfg.Auth.JWTSecret,
+		JWTIssuer: cfg.Auth.JWTIssuer,
+		APIKeys:   apiKeys,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
+
+	if ath == nil {
+		log.Warn(ctx, "startup", "status", "authentication disabled, no credentials configured")
+	}
+
+	corsPolicy := mid.DefaultCORSPolicy(strings.Split(cfg.CORS.Origins, ",")...)
+	corsPolicy.AllowCredentials = cfg.CORS.AllowCredentials == "true"
+	if cfg.CORS.OriginRegex != "" {
+		re, err := regexp.Compile(cfg.CORS.OriginRegex)
+		if err != nil {
+			return fmt.Errorf("parsing cors origin regex: %w", err)
+		}
+		corsPolicy.AllowedOriginPatterns = append(corsPolicy.AllowedOriginPatterns, re)
+	}
+
+	accessLog := mid.AccessLog{
+		Fields:       splitList(cfg.Log.AccessFields),
+		RedactParams: splitList(cfg.Log.RedactParams),
+		SkipPaths:    splitList(cfg.Log.SkipPaths),
+	}
+
+	// Create API app
+	apiApp := mux.WebAPI(mux.Config{
+		Log:            log,
+		Tracer:         tracer,
+		Auth:           ath,
+		CORS:           corsPolicy,
+		AccessLog:      accessLog,
+		ProblemDetails: cfg.Web.ProblemDetails == "true",
+		Metrics: mux.MetricsConfig{
+			OnAPI:    cfg.Metrics.OnAPI == "true",
+			User:     cfg.Metrics.User,
+			Password: cfg.Metrics.Password,
+		},
+	}, routeAdder)
+
+	apiServer := http.Server{
+		Addr:           cfg.Web.APIHost,
+		Handler:        apiApp,
+		ReadTimeout:    cfg.Web.ReadTimeout,
+		WriteTimeout:   cfg.Web.WriteTimeout,
+		IdleTimeout:    cfg.Web.IdleTimeout,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	serverErrors := make(chan error, 1)
+
+	go func() {
+		log.Info(ctx, "startup", "status", "api router started", "host", apiServer.Addr)
+		serverErrors <- apiServer.ListenAndServe()
+	}()
+
+	startupErrors := make(chan error, 1)
+
+	if !healthBus.Started() {
+		go func() {
+			log.Info(ctx, "startup", "status", "waiting for dependencies", "timeout", healthCfg.Startup.Timeout.String())
+			if err := healthBus.WaitForDependencies(bgCtx); err != nil {
+				startupErrors <- err
+			}
+		}()
+	}
+
+	// -------------------------------------------------------------------------
+	// Shutdown
+
+	shutdownChan := make(chan os.Signal, 1)
+	signal.Notify(shutdownChan, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErrors:
+		return fmt.Errorf("server error: %w", err)
+
+	case err := <-startupErrors:
+		apiServer.Close()
+		debugServer.Close()
+		return fmt.Errorf("startup gate: %w", err)
+
+	case sig := <-shutdownChan:
+		log.Info(ctx, "shutdown", "status", "shutdown started", "signal", sig)
+		defer log.Info(ctx, "shutdown", "status", "shutdown complete", "signal", sig)
+
+		ctx, cancel := context.WithTimeout(ctx, cfg.Web.ShutdownTimeout)
+		defer cancel()
+
+		if err := shutdownServers(ctx, &apiServer, &debugServer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Routes implements mux.RouteAdder.
+type Routes struct {
+	Build            string
+	StartedAt        time.Time
+	Stores           []string
+	HealthBus        *healthbus.Business
+	NotifyBus        *notifybus.Business
+	TargetBus        *targetbus.Business
+	IncidentBus      *incidentbus.Business
+	HistoryBus       *historybus.Business
+	OnCallBus        *oncallbus.Business
+	MaintenanceBus   *maintenancebus.Business
+	AnnotationBus    *annotationbus.Business
+	SnoozeBus        *snoozebus.Business
+	ViewBus          *viewbus.Business
+	ReportBus        *reportbus.Business
+	AlertRuleBus     *alertrulebus.Business
+	ProbeBus         *probebus.Business
+	ProberBus        *proberbus.Business
+	PrometheusBus    *prometheusbus.Business
+	AlertHistoryBus  *alerthistorybus.Business
+	ForecastBus      *forecastbus.Business
+	CompareBus       *comparebus.Business
+	StorageBus       *storagebus.Business
+	LogBus           *logbus.Business
+	KubeEventBus     *kubeeventbus.Business
+	RemediationBus   *remediationbus.Business
+	IngestBus        *ingestbus.Business
+	IngestSecret     string
+	ReadinessTimeout time.Duration
+	QueryTimeout     time.Duration
+	RequestTimeout   time.Duration
+	V1Deprecation    mid.Deprecation
+	UIDir            string
+}
+
+// Add registers all routes for the service.
+func (r Routes) Add(app *web.App, cfg mux.Config) {
+	healthapp.Routes(app, healthapp.Config{
+		Log:              cfg.Log,
+		HealthBus:        r.HealthBus,
+		HistoryBus:       r.HistoryBus,
+		OnCallBus:        r.OnCallBus,
+		AnnotationBus:    r.AnnotationBus,
+		ProberBus:        r.ProberBus,
+		ReadinessTimeout: r.ReadinessTimeout,
+		QueryTimeout:     r.QueryTimeout,
+		Auth:             cfg.Auth,
+		Deprecation:      r.V1Deprecation,
+	})
+
+	targetapp.Routes(app, targetapp.Config{
+		Log:          cfg.Log,
+		TargetBus:    r.TargetBus,
+		AlertRuleBus: r.AlertRuleBus,
+		Timeout:      r.RequestTimeout,
+		Auth:         cfg.Auth,
+	})
+
+	incidentapp.Routes(app, incidentapp.Config{
+		Log:         cfg.Log,
+		IncidentBus: r.IncidentBus,
+		Timeout:     r.RequestTimeout,
+		Auth:        cfg.Auth,
+	})
+
+	maintenanceapp.Routes(app, maintenanceapp.Config{
+		Log:            cfg.Log,
+		MaintenanceBus: r.MaintenanceBus,
+		Timeout:        r.RequestTimeout,
+		Auth:           cfg.Auth,
+	})
+
+	annotationapp.Routes(app, annotationapp.Config{
+		Log:           cfg.Log,
+		AnnotationBus: r.AnnotationBus,
+		Timeout:       r.RequestTimeout,
+		Auth:          cfg.Auth,
+	})
+
+	snoozeapp.Routes(app, snoozeapp.Config{
+		Log:       cfg.Log,
+		SnoozeBus: r.SnoozeBus,
+		HealthBus: r.HealthBus,
+		Timeout:   r.QueryTimeout,
+		Auth:      cfg.Auth,
+	})
+
+	viewapp.Routes(app, viewapp.Config{
+		Log:     cfg.Log,
+		ViewBus: r.ViewBus,
+		Timeout: r.RequestTimeout,
+		Auth:    cfg.Auth,
+	})
+
+	reportapp.Routes(app, reportapp.Config{
+		Log:       cfg.Log,
+		ReportBus: r.ReportBus,
+		Timeout:   r.RequestTimeout,
+		Auth:      cfg.Auth,
+	})
+
+	probeapp.Routes(app, probeapp.Config{
+		Log:      cfg.Log,
+		ProbeBus: r.ProbeBus,
+		Timeout:  r.RequestTimeout,
+		Auth:     cfg.Auth,
+	})
+
+	proberapp.Routes(app, proberapp.Config{
+		Log:       cfg.Log,
+		ProberBus: r.ProberBus,
+		Timeout:   r.RequestTimeout,
+		Auth:      cfg.Auth,
+	})
+
+	prometheusapp.Routes(app, prometheusapp.Config{
+		Log:           cfg.Log,
+		PrometheusBus: r.PrometheusBus,
+		Timeout:       r.QueryTimeout,
+		Auth:          cfg.Auth,
+	})
+
+	alerthistoryapp.Routes(app, alerthistoryapp.Config{
+		Log:             cfg.Log,
+		AlertHistoryBus: r.AlertHistoryBus,
+		Timeout:         r.QueryTimeout,
+		Auth:            cfg.Auth,
+	})
+
+	forecastapp.Routes(app, forecastapp.Config{
+		Log:         cfg.Log,
+		ForecastBus: r.ForecastBus,
+		Timeout:     r.QueryTimeout,
+		Auth:        cfg.Auth,
+	})
+
+	compareapp.Routes(app, compareapp.Config{
+		Log:        cfg.Log,
+		CompareBus: r.CompareBus,
+		Timeout:    r.QueryTimeout,
+		Auth:       cfg.Auth,
+	})
+
+	storageapp.Routes(app, storageapp.Config{
+		Log:        cfg.Log,
+		StorageBus: r.StorageBus,
+		Timeout:    r.QueryTimeout,
+		Auth:       cfg.Auth,
+	})
+
+	logapp.Routes(app, logapp.Config{
+		Log:     cfg.Log,
+		LogBus:  r.LogBus,
+		Timeout: r.QueryTimeout,
+		Auth:    cfg.Auth,
+	})
+
+	kubeeventapp.Routes(app, kubeeventapp.Config{
+		Log:          cfg.Log,
+		KubeEventBus: r.KubeEventBus,
+		Timeout:      r.QueryTimeout,
+		Auth:         cfg.Auth,
+	})
+
+	remediationapp.Routes(app, remediationapp.Config{
+		Log:            cfg.Log,
+		RemediationBus: r.RemediationBus,
+		Timeout:        r.RequestTimeout,
+		Auth:           cfg.Auth,
+	})
+
+	ingestapp.Routes(app, ingestapp.Config{
+		Log:       cfg.Log,
+		IngestBus: r.IngestBus,
+		Secret:    r.IngestSecret,
+		Timeout:   r.RequestTimeout,
+	})
+
+	systemapp.Routes(app, systemapp.Config{
+		Build:     r.Build,
+		StartedAt: r.StartedAt,
+		Stores:    r.Stores,
+		HealthBus: r.HealthBus,
+		NotifyBus: r.NotifyBus,
+		Timeout:   r.RequestTimeout,
+		Auth:      cfg.Auth,
+	})
+
+	uiapp.Routes(app, uiapp.Config{
+		Dir: r.UIDir,
+	})
+}
+
+// backendTransport returns the transport for requests to a backend: a client
+// span per request around propagation of the incoming trace context.
+func backendTransport(backend string, base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(web.NewTransport(base),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return backend + " " + r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// backendEgressPools clones base for every backend named in proxies or
+// interfaces, routed through its proxy and from its source interface.
+func backendEgressPools(base *http.Transport, proxies map[string]string, interfaces map[string]string) (map[string]*http.Transport, error) {
+	pools := make(map[string]*http.Transport)
+
+	for _, m := range []map[string]string{proxies, interfaces} {
+		for name := range m {
+			if _, ok := pools[name]; ok {
+				continue
+			}
+
+			t, err := web.WithEgress(base, web.Egress{Proxy: proxies[name], Interface: interfaces[name]})
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			pools[name] = t
+		}
+	}
+
+	return pools, nil
+}
+
+// notifyRouter builds the per-team notification routes. Each route lists
+// channel names separated by |. Teams without a route, and targets without
+// a team, take the default route or, without one, the global notifiers.
+func notifyRouter(channels map[string]*notifybus.Channel, routes map[string]string, defaultRoute []string, global []notifybus.Notifier) (*notifybus.Router, error) {
+	resolve := func(names []string) ([]notifybus.Notifier, error) {
+		var route []notifybus.Notifier
+		for _, name := range names {
+			nt, ok := channels[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown channel %q", name)
+			}
+			route = append(route, nt)
+		}
+		return route, nil
+	}
+
+	teams := make(map[string][]notifybus.Notifier, len(routes))
+	for team, list := range routes {
+		route, err := resolve(splitList(strings.ReplaceAll(list, "|", ",")))
+		if err != nil {
+			return nil, fmt.Errorf("team %s: %w", team, err)
+		}
+		teams[team] = route
+	}
+
+	fallback := global
+	if len(defaultRoute) > 0 {
+		var err error
+		if fallback, err = resolve(defaultRoute); err != nil {
+			return nil, fmt.Errorf("default route: %w", err)
+		}
+	}
+
+	return notifybus.NewRouter(teams, fallback), nil
+}
+
+// parseAttributes parses a comma-separated list of key=value pairs.
+func parseAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		attrs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return attrs
+}
+
+// mqttRules parses comma-separated filter=target pairs, keeping their order
+// since the first matching rule wins.
+func mqttRules(s string) ([]ingestapp.MQTTRule, error) {
+	var rules []ingestapp.MQTTRule
+	for _, pair := range splitList(s) {
+		filter, target, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(filter) == "" || strings.TrimSpace(target) == "" {
+			return nil, fmt.Errorf("invalid rule %q, want filter=target", pair)
+		}
+		rules = append(rules, ingestapp.MQTTRule{Filter: strings.TrimSpace(filter), Target: strings.TrimSpace(target)})
+	}
+
+	if len(rules) == 0 {
+		return nil, errors.New("MQTT_RULES is empty")
+	}
+
+	return rules, nil
+}
+
+// newStorageBus rates the volume usage of storer against the default
+// warning and critical percentages and the per-claim thresholds of file,
+// when set.
+func newStorageBus(log *logger.Logger, warning string, critical string, file string, storer storagebus.Storer) (*storagebus.Business, error) {
+	var defaults storagebus.Threshold
+	var err error
+
+	if defaults.Warning, err = strconv.ParseFloat(warning, 64); err != nil {
+		return nil, fmt.Errorf("parsing storage warning percent: %w", err)
+	}
+	if defaults.Critical, err = strconv.ParseFloat(critical, 64); err != nil {
+		return nil, fmt.Errorf("parsing storage critical percent: %w", err)
+	}
+
+	var thresholds []storagebus.Threshold
+	if file != "" {
+		if thresholds, err = storagebus.LoadFile(file); err != nil {
+			return nil, fmt.Errorf("loading storage thresholds: %w", err)
+		}
+	}
+
+	storageBus, err := storagebus.NewBusiness(log, storer, defaults, thresholds)
+	if err != nil {
+		return nil, fmt.Errorf("initializing storage: %w", err)
+	}
+
+	return storageBus, nil
+}
+
+// shutdownServers stops the servers from accepting connections and drains
+// their in-flight requests at the same time, so a slow API request does not
+// use up the debug server's share of the deadline. A server still draining
+// when ctx is done is closed.
+func shutdownServers(ctx context.Context, servers ...*http.Server) error {
+	results := make(chan error, len(servers))
+
+	for _, srv := range servers {
+		go func() {
+			if err := srv.Shutdown(ctx); err != nil {
+				srv.Close()
+				results <- fmt.Errorf("could not stop server %s gracefully: %w", srv.Addr, err)
+				return
+			}
+			results <- nil
+		}()
+	}
+
+	var err error
+	for range servers {
+		err = errors.Join(err, <-results)
+	}
+
+	return err
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
 
 // traceIDFunc extracts the trace ID from the context.
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// serve starts a server for handler on a free port.
+func serve(t *testing.T, handler http.HandlerFunc) *http.Server {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Should be able to listen: %s", err)
+	}
+
+	srv := &http.Server{Addr: ln.Addr().String(), Handler: handler}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	return srv
+}
+
+// get requests the root of srv in the background.
+func get(srv *http.Server) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		resp, err := http.Get("http://" + srv.Addr)
+		if err != nil {
+			done <- err
+			return
+		}
+		defer resp.Body.Close()
+
+		_, err = io.ReadAll(resp.Body)
+		done <- err
+	}()
+
+	return done
+}
+
+func Test_ShutdownServers(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		io.WriteString(w, "ok")
+	}
+
+	api := serve(t, slow)
+	debug := serve(t, slow)
+
+	apiDone := get(api)
+	debugDone := get(debug)
+	<-started
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- shutdownServers(ctx, api, debug) }()
+
+	// Both servers stop accepting before either has drained.
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, apiErr := net.Dial("tcp", api.Addr)
+		_, debugErr := net.Dial("tcp", debug.Addr)
+		if apiErr != nil && debugErr != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Should stop accepting connections on both servers")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(release)
+
+	if err := <-shutdown; err != nil {
+		t.Errorf("Should stop gracefully, got %s", err)
+	}
+	if err := <-apiDone; err != nil {
+		t.Errorf("Should finish the in-flight API request, got %s", err)
+	}
+	if err := <-debugDone; err != nil {
+		t.Errorf("Should finish the in-flight debug request, got %s", err)
+	}
+}
+
+func Test_ShutdownServersDeadline(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	srv := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	done := get(srv)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := shutdownServers(ctx, srv); err == nil {
+		t.Error("Should report a server that did not drain in time")
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Should close the connection of the request still running")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Should close the server once the deadline passed")
+	}
+}
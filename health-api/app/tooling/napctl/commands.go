@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/app/domain/targetapp"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/domain/targetbus"
+)
+
+func healthCmd(g globals, args []string) error {
+	if len(args) == 0 || args[0] != "list" {
+		return fmt.Errorf("%w: health needs the list subcommand", errUsage)
+	}
+
+	fs := newFlagSet("health list")
+	team := fs.String("team", "", "")
+	status := fs.String("status", "", "")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *team != "" {
+		query.Set("team", *team)
+	}
+
+	var summary healthbus.HealthSummary
+	if err := c.do(context.Background(), http.MethodGet, "/api/v1/health", query, nil, &summary); err != nil {
+		return err
+	}
+
+	if *status != "" {
+		checks := summary.Checks[:0]
+		for _, check := range summary.Checks {
+			if string(check.Status) == *status {
+				checks = append(checks, check)
+			}
+		}
+		summary.Checks = checks
+	}
+
+	if g.output == outputJSON {
+		return printJSON(summary.Checks)
+	}
+
+	rows := make([][]string, len(summary.Checks))
+	for i, check := range summary.Checks {
+		rows[i] = []string{
+			check.Target,
+			string(check.Status),
+			check.Namespace,
+			check.Team,
+			formatSeconds(check.DurationSeconds),
+			formatAge(check.LastChecked),
+		}
+	}
+
+	return printTable([]string{"TARGET", "STATUS", "NAMESPACE", "TEAM", "DURATION", "CHECKED"}, rows)
+}
+
+func targetCmd(g globals, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: target needs the add, pause or resume subcommand", errUsage)
+	}
+
+	switch args[0] {
+	case "add":
+		return targetAdd(g, args[1:])
+	case "pause":
+		return targetPause(g, args[1:])
+	case "resume":
+		return targetResume(g, args[1:])
+	}
+
+	return fmt.Errorf("%w: unknown target subcommand %q", errUsage, args[0])
+}
+
+func targetAdd(g globals, args []string) error {
+	fs := newFlagSet("target add")
+	namespace := fs.String("namespace", "", "")
+	team := fs.String("team", "", "")
+	runbook := fs.String("runbook", "", "")
+	severity := fs.String("severity", "", "")
+	module := fs.String("module", "", "")
+	dependsOn := fs.String("depends-on", "", "")
+	tags := fs.String("tags", "", "")
+	name, err := parseWithName(fs, args)
+	if err != nil {
+		return err
+	}
+
+	// Only flags given on the command line are sent, so adding an existing
+	// target leaves its other fields as they are.
+	var ut targetbus.UpdateTarget
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "namespace":
+			ut.Namespace = namespace
+		case "team":
+			ut.Team = team
+		case "runbook":
+			ut.RunbookURL = runbook
+		case "severity":
+			ut.Severity = severity
+		case "module":
+			ut.Module = module
+		case "depends-on":
+			ut.DependsOn = splitList(*dependsOn)
+		case "tags":
+			ut.Tags = make(map[string]string)
+			for _, pair := range splitList(*tags) {
+				k, v, _ := strings.Cut(pair, "=")
+				ut.Tags[k] = v
+			}
+		}
+	})
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var tgt targetbus.Target
+	if err := c.do(context.Background(), http.MethodPut, "/api/v1/targets/"+url.PathEscape(name), nil, ut, &tgt); err != nil {
+		return err
+	}
+
+	if g.output == outputJSON {
+		return printJSON(tgt)
+	}
+
+	fmt.Printf("target %s saved (version %d)\n", tgt.Name, tgt.Version)
+
+	return nil
+}
+
+func targetPause(g globals, args []string) error {
+	fs := newFlagSet("target pause")
+	ttl := fs.Duration("ttl", 0, "")
+	reason := fs.String("reason", "", "")
+	name, err := parseWithName(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *ttl < 0 {
+		return fmt.Errorf("%w: --ttl must not be negative", errUsage)
+	}
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	req := targetapp.PauseRequest{
+		TTLSeconds: int(ttl.Seconds()),
+		Reason:     *reason,
+	}
+
+	var tgt targetbus.Target
+	if err := c.do(context.Background(), http.MethodPost, "/api/v1/targets/"+url.PathEscape(name)+"/pause", nil, req, &tgt); err != nil {
+		return err
+	}
+
+	if g.output == outputJSON {
+		return printJSON(tgt)
+	}
+
+	if tgt.PausedUntil != nil {
+		fmt.Printf("target %s paused until %s\n", tgt.Name, tgt.PausedUntil.Local().Format(time.RFC3339))
+		return nil
+	}
+	fmt.Printf("target %s paused\n", tgt.Name)
+
+	return nil
+}
+
+func targetResume(g globals, args []string) error {
+	fs := newFlagSet("target resume")
+	name, err := parseWithName(fs, args)
+	if err != nil {
+		return err
+	}
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var tgt targetbus.Target
+	if err := c.do(context.Background(), http.MethodPost, "/api/v1/targets/"+url.PathEscape(name)+"/resume", nil, nil, &tgt); err != nil {
+		return err
+	}
+
+	if g.output == outputJSON {
+		return printJSON(tgt)
+	}
+
+	fmt.Printf("target %s resumed\n", tgt.Name)
+
+	return nil
+}
+
+func alertsCmd(g globals, args []string) error {
+	fs := newFlagSet("alerts")
+	firing := fs.Bool("firing", false, "")
+	severity := fs.String("severity", "", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *severity != "" {
+		query.Set("severity", *severity)
+	}
+
+	var summary healthbus.AlertSummary
+	if err := c.do(context.Background(), http.MethodGet, "/api/v1/alerts", query, nil, &summary); err != nil {
+		return err
+	}
+
+	alerts := summary.Alerts
+	if *firing {
+		alerts = nil
+		for _, alert := range summary.Alerts {
+			if isFiring(alert.State) {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Title < alerts[j].Title })
+
+	if g.output == outputJSON {
+		return printJSON(alerts)
+	}
+
+	rows := make([][]string, len(alerts))
+	for i, alert := range alerts {
+		rows[i] = []string{alert.Title, alert.State, string(alert.Severity), alert.ActiveAt}
+	}
+
+	return printTable([]string{"TITLE", "STATE", "SEVERITY", "ACTIVE SINCE"}, rows)
+}
+
+func maintenanceCmd(g globals, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: maintenance needs the create or list subcommand", errUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		return maintenanceCreate(g, args[1:])
+	case "list":
+		return maintenanceList(g, args[1:])
+	}
+
+	return fmt.Errorf("%w: unknown maintenance subcommand %q", errUsage, args[0])
+}
+
+func maintenanceCreate(g globals, args []string) error {
+	fs := newFlagSet("maintenance create")
+	targets := fs.String("targets", "", "")
+	start := fs.String("start", "", "")
+	duration := fs.Duration("duration", time.Hour, "")
+	rrule := fs.String("rrule", "", "")
+	timezone := fs.String("timezone", "", "")
+	namespace := fs.String("namespace", "", "")
+	name, err := parseWithName(fs, args)
+	if err != nil {
+		return err
+	}
+
+	startAt := time.Now()
+	if *start != "" {
+		if startAt, err = time.Parse(time.RFC3339, *start); err != nil {
+			return fmt.Errorf("%w: --start must be an RFC 3339 time", errUsage)
+		}
+	}
+
+	nw := maintenancebus.NewWindow{
+		Name:      name,
+		Namespace: *namespace,
+		Targets:   splitList(*targets),
+		Start:     startAt,
+		End:       startAt.Add(*duration),
+		Timezone:  *timezone,
+		RRule:     *rrule,
+	}
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var w maintenancebus.Window
+	if err := c.do(context.Background(), http.MethodPost, "/api/v1/maintenance", nil, nw, &w); err != nil {
+		return err
+	}
+
+	if g.output == outputJSON {
+		return printJSON(w)
+	}
+
+	fmt.Printf("maintenance window %s created (id %s)\n", w.Name, w.ID)
+
+	return nil
+}
+
+func maintenanceList(g globals, args []string) error {
+	fs := newFlagSet("maintenance list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var windows []maintenancebus.Window
+	if err := c.do(context.Background(), http.MethodGet, "/api/v1/maintenance", nil, nil, &windows); err != nil {
+		return err
+	}
+
+	if g.output == outputJSON {
+		return printJSON(windows)
+	}
+
+	rows := make([][]string, len(windows))
+	for i, w := range windows {
+		next := "-"
+		if w.NextStart != nil {
+			next = w.NextStart.Local().Format(time.DateTime)
+		}
+
+		schedule := w.RRule
+		if schedule == "" {
+			schedule = "once"
+		}
+
+		rows[i] = []string{w.ID, w.Name, strings.Join(w.Targets, ","), schedule, w.End.Sub(w.Start).String(), strconv.FormatBool(w.Active), next}
+	}
+
+	return printTable([]string{"ID", "NAME", "TARGETS", "SCHEDULE", "DURATION", "ACTIVE", "NEXT START"}, rows)
+}
+
+// isFiring reports whether the alert state, as reported by Prometheus or
+// Grafana, means the alert is firing.
+func isFiring(state string) bool {
+	switch strings.ToLower(state) {
+	case "firing", "alerting":
+		return true
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func formatSeconds(s float64) string {
+	if s == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(s*1000, 'f', 0, 64) + "ms"
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// Config is the napctl config file.
+type Config struct {
+	CurrentContext string    `yaml:"current-context"`
+	Contexts       []Context `yaml:"contexts"`
+}
+
+// Context names a health API and the credentials used to call it.
+type Context struct {
+	Name   string `yaml:"name" json:"name"`
+	Server string `yaml:"server" json:"server"`
+	Token  string `yaml:"token,omitempty" json:"-"`
+}
+
+func (c Config) find(name string) (Context, bool) {
+	i := slices.IndexFunc(c.Contexts, func(ctx Context) bool { return ctx.Name == name })
+	if i < 0 {
+		return Context{}, false
+	}
+	return c.Contexts[i], true
+}
+
+func configPath() (string, error) {
+	if path := os.Getenv("NAPCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".napctl", "config"), nil
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig() (Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return Config{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return Config{}, nil
+	case err != nil:
+		return Config{}, fmt.Errorf("reading config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// saveConfig writes the config file, readable only by the user since it
+// holds tokens.
+func saveConfig(cfg Config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating config dir: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing config: %w", err)
+	}
+
+	return nil
+}
+
+func configCmd(g globals, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: config needs a subcommand", errUsage)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	switch sub, args := args[0], args[1:]; sub {
+	case "set-context":
+		fs := newFlagSet("set-context")
+		server := fs.String("server", "", "")
+		token := fs.String("token", "", "")
+		name, err := parseWithName(fs, args)
+		if err != nil {
+			return err
+		}
+
+		ctx, _ := cfg.find(name)
+		ctx.Name = name
+		if *server != "" {
+			ctx.Server = *server
+		}
+		if *token != "" {
+			ctx.Token = *token
+		}
+		if ctx.Server == "" {
+			return fmt.Errorf("%w: context %q needs --server", errUsage, name)
+		}
+
+		cfg.Contexts = slices.DeleteFunc(cfg.Contexts, func(c Context) bool { return c.Name == name })
+		cfg.Contexts = append(cfg.Contexts, ctx)
+		if cfg.CurrentContext == "" {
+			cfg.CurrentContext = name
+		}
+
+		return saveConfig(cfg)
+
+	case "use-context":
+		if len(args) != 1 {
+			return fmt.Errorf("%w: use-context needs a context name", errUsage)
+		}
+		if _, ok := cfg.find(args[0]); !ok {
+			return fmt.Errorf("context %q not found", args[0])
+		}
+
+		cfg.CurrentContext = args[0]
+		return saveConfig(cfg)
+
+	case "delete-context":
+		if len(args) != 1 {
+			return fmt.Errorf("%w: delete-context needs a context name", errUsage)
+		}
+		if _, ok := cfg.find(args[0]); !ok {
+			return fmt.Errorf("context %q not found", args[0])
+		}
+
+		cfg.Contexts = slices.DeleteFunc(cfg.Contexts, func(c Context) bool { return c.Name == args[0] })
+		if cfg.CurrentContext == args[0] {
+			cfg.CurrentContext = ""
+		}
+		return saveConfig(cfg)
+
+	case "get-contexts":
+		if g.output == outputJSON {
+			return printJSON(cfg.Contexts)
+		}
+
+		rows := make([][]string, len(cfg.Contexts))
+		for i, ctx := range cfg.Contexts {
+			current := ""
+			if ctx.Name == cfg.CurrentContext {
+				current = "*"
+			}
+			rows[i] = []string{current, ctx.Name, ctx.Server}
+		}
+		return printTable([]string{"CURRENT", "NAME", "SERVER"}, rows)
+
+	default:
+		return fmt.Errorf("%w: unknown config subcommand %q", errUsage, sub)
+	}
+}
+
+// parseWithName parses flags that may appear before or after a single
+// positional name argument.
+func parseWithName(fs *flag.FlagSet, args []string) (string, error) {
+	var name string
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		name, args = args[0], args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: %s needs a name", errUsage, fs.Name())
+	}
+
+	return name, nil
+}
@@ -0,0 +1,145 @@
+// Napctl is a command line client for the health API. It keeps a set of
+// named contexts, each pointing at the API of one cluster, similar to
+// kubeconfig.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `napctl talks to the health API.
+
+Usage:
+  napctl [global flags] <command> [flags] [args]
+
+Commands:
+  health list [--team T] [--status S]     List health checks
+  target add <name> [flags]               Create or update a target
+  target pause <name> [--ttl D] [--reason R]
+                                          Stop probing and alerting
+  target resume <name>                    Resume a paused target
+  alerts [--firing] [--severity S,S]      List alerts
+  maintenance create <name> --targets T,T [--start TIME] [--duration D]
+                     [--rrule RULE] [--timezone TZ] [--namespace NS]
+  maintenance list                        List maintenance windows
+  config set-context <name> --server URL [--token T]
+  config use-context <name>
+  config get-contexts
+  config delete-context <name>
+
+Global flags:
+  --context NAME    Context to use instead of the current one
+  --server URL      API address, overriding the context
+  --token TOKEN     Bearer token or API key, overriding the context
+  -o, --output FMT  Output format: table (default) or json
+
+The config file is ~/.napctl/config, or $NAPCTL_CONFIG if set.
+`
+
+// errUsage reports invalid command line arguments.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+		}
+		fmt.Fprintln(os.Stderr, "napctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	var g globals
+
+	fs := newFlagSet("napctl")
+	fs.StringVar(&g.context, "context", "", "")
+	fs.StringVar(&g.server, "server", "", "")
+	fs.StringVar(&g.token, "token", "", "")
+	fs.StringVar(&g.output, "output", outputTable, "")
+	fs.StringVar(&g.output, "o", outputTable, "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if g.output != outputTable && g.output != outputJSON {
+		return fmt.Errorf("%w: unknown output format %q", errUsage, g.output)
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "health":
+		return healthCmd(g, args)
+	case "target", "targets":
+		return targetCmd(g, args)
+	case "alerts":
+		return alertsCmd(g, args)
+	case "maintenance":
+		return maintenanceCmd(g, args)
+	case "config":
+		return configCmd(g, args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+	}
+}
+
+// newFlagSet returns a flag set that prints the napctl usage on errors.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	return fs
+}
+
+// globals holds the flags accepted before the command.
+type globals struct {
+	context string
+	server  string
+	token   string
+	output  string
+}
+
+// client returns an API client for the selected context.
+func (g globals) client() (*client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx Context
+	name := g.context
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+	if name != "" {
+		var ok bool
+		if ctx, ok = cfg.find(name); !ok {
+			return nil, fmt.Errorf("context %q not found", name)
+		}
+	}
+
+	if g.server != "" {
+		ctx.Server = g.server
+	}
+	if g.token != "" {
+		ctx.Token = g.token
+	}
+
+	if ctx.Server == "" {
+		return nil, errors.New("no server configured, run napctl config set-context or pass --server")
+	}
+
+	return newClient(ctx.Server, ctx.Token), nil
+}
@@ -0,0 +1,124 @@
+// Package alerthistorybus provides business logic for past alert firings,
+// built from the state history Grafana records for its alert rules.
+package alerthistorybus
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// Storer defines the interface for reading alert state history.
+type Storer interface {
+	QueryTransitions(ctx context.Context, from, to time.Time) ([]Transition, error)
+}
+
+// Business manages alert history queries.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness creates a new alert history business layer.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Query returns the firings that overlap the filter's time range, latest
+// first. A firing that was already active at From is reported as starting
+// at From. Callers scoped to a tenant only see alerts from their
+// namespaces.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Firing, error) {
+	transitions, err := b.storer.QueryTransitions(ctx, filter.From, filter.To)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	scope := tenant.Get(ctx)
+
+	var matched []Transition
+	for _, t := range transitions {
+		if scope.Restricted() && !scope.Allows(t.Labels["namespace"]) {
+			continue
+		}
+		if filter.Target != "" && t.Labels["target"] != filter.Target {
+			continue
+		}
+		if filter.Rule != "" && t.Title != filter.Rule && t.RuleUID != filter.Rule {
+			continue
+		}
+		matched = append(matched, t)
+	}
+
+	firings := buildFirings(matched, filter.From, filter.To)
+
+	sort.SliceStable(firings, func(i, j int) bool { return firings[i].StartsAt.After(firings[j].StartsAt) })
+
+	return firings, nil
+}
+
+// buildFirings pairs the transitions into Alerting and the transitions out
+// of it per alert instance. Firings still open at the end of the history
+// are active, with their duration measured up to to.
+func buildFirings(transitions []Transition, from, to time.Time) []Firing {
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].Time.Before(transitions[j].Time) })
+
+	open := make(map[string]*Firing)
+	var firings []Firing
+
+	for _, t := range transitions {
+		key := instanceKey(t)
+
+		switch {
+		case isFiring(t.Current) && open[key] == nil:
+			open[key] = &Firing{
+				RuleUID:  t.RuleUID,
+				Title:    t.Title,
+				Labels:   t.Labels,
+				StartsAt: t.Time,
+			}
+
+		case !isFiring(t.Current) && (open[key] != nil || isFiring(t.Previous)):
+			f := open[key]
+			if f == nil {
+				f = &Firing{RuleUID: t.RuleUID, Title: t.Title, Labels: t.Labels, StartsAt: from}
+			}
+			delete(open, key)
+
+			ends := t.Time
+			f.EndsAt = &ends
+			f.DurationSeconds = ends.Sub(f.StartsAt).Seconds()
+			firings = append(firings, *f)
+		}
+	}
+
+	for _, f := range open {
+		f.Active = true
+		f.DurationSeconds = to.Sub(f.StartsAt).Seconds()
+		firings = append(firings, *f)
+	}
+
+	return firings
+}
+
+// instanceKey identifies an alert instance by its rule and labels.
+func instanceKey(t Transition) string {
+	var b strings.Builder
+	b.WriteString(t.RuleUID)
+
+	for _, k := range slices.Sorted(maps.Keys(t.Labels)) {
+		b.WriteString("\x00" + k + "=" + t.Labels[k])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package alerthistorybus
+
+import (
+	"strings"
+	"time"
+)
+
+// Transition is a recorded change of an alert instance's state, as kept by
+// Grafana's alert state history. States are Grafana's, e.g. "Normal",
+// "Pending" or "Alerting", optionally followed by a reason such as
+// "Normal (MissingSeries)".
+type Transition struct {
+	Time     time.Time
+	RuleUID  string
+	Title    string
+	Labels   map[string]string
+	Previous string
+	Current  string
+}
+
+// Firing is a period during which an alert instance was firing. EndsAt is
+// nil while the alert is still firing.
+type Firing struct {
+	RuleUID         string            `json:"rule_uid"`
+	Title           string            `json:"title"`
+	Labels          map[string]string `json:"labels"`
+	StartsAt        time.Time         `json:"starts_at"`
+	EndsAt          *time.Time        `json:"ends_at,omitempty"`
+	DurationSeconds float64           `json:"duration_seconds"`
+	Active          bool              `json:"active"`
+}
+
+// QueryFilter holds the available fields a history query can be filtered
+// on. From and To are required; Target and Rule match the target label and
+// the rule title or UID.
+type QueryFilter struct {
+	From   time.Time
+	To     time.Time
+	Target string
+	Rule   string
+}
+
+// isFiring reports whether a Grafana alert state means the alert fires.
+func isFiring(state string) bool {
+	return strings.HasPrefix(state, "Alerting")
+}
@@ -0,0 +1,137 @@
+// Package grafanahistory implements the alert history store using Grafana's
+// alert state history API.
+package grafanahistory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"health-api/business/domain/alerthistorybus"
+	"health-api/foundation/logger"
+)
+
+// limit is the maximum number of history entries requested at once.
+const limit = 5000
+
+// Store implements alerthistorybus.Storer using Grafana.
+type Store struct {
+	log         *logger.Logger
+	grafanaURL  string
+	credentials atomic.Pointer[credentials]
+	httpClient  *http.Client
+}
+
+// credentials holds the basic auth user and password for Grafana.
+type credentials struct {
+	user     string
+	password string
+}
+
+// NewStore creates a new Grafana-backed alert history store. The transport
+// carries tracing and request metrics; nil uses http.DefaultTransport.
+func NewStore(log *logger.Logger, grafanaURL, grafanaUser, grafanaPassword string, transport http.RoundTripper) *Store {
+	s := Store{
+		log:        log,
+		grafanaURL: grafanaURL,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+	s.SetCredentials(grafanaUser, grafanaPassword)
+
+	return &s
+}
+
+// SetCredentials replaces the credentials used for subsequent requests.
+func (s *Store) SetCredentials(user, password string) {
+	s.credentials.Store(&credentials{user: user, password: password})
+}
+
+// QueryTransitions retrieves the state transitions recorded between from
+// and to.
+func (s *Store) QueryTransitions(ctx context.Context, from, to time.Time) ([]alerthistorybus.Transition, error) {
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("to", strconv.FormatInt(to.Unix(), 10))
+	query.Set("limit", strconv.Itoa(limit))
+
+	historyURL := fmt.Sprintf("%s/api/v1/rules/history?%s", s.grafanaURL, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, historyURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating history request: %w", err)
+	}
+
+	if c := s.credentials.Load(); c.user != "" && c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying alert state history: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	}
+
+	// The history is a data frame with time, line and labels columns.
+	var frame struct {
+		Data struct {
+			Values [][]json.RawMessage `json:"values"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&frame); err != nil {
+		return nil, fmt.Errorf("decoding history response: %w", err)
+	}
+
+	if len(frame.Data.Values) < 2 {
+		return nil, nil
+	}
+
+	times, lines := frame.Data.Values[0], frame.Data.Values[1]
+
+	transitions := make([]alerthistorybus.Transition, 0, len(lines))
+	for i := range min(len(times), len(lines)) {
+		var ms int64
+		if err := json.Unmarshal(times[i], &ms); err != nil {
+			continue
+		}
+
+		var l line
+		if err := json.Unmarshal(lines[i], &l); err != nil {
+			s.log.Warn(ctx, "grafanahistory", "status", "skipping undecodable history entry", "error", err)
+			continue
+		}
+
+		transitions = append(transitions, l.toTransition(time.UnixMilli(ms)))
+	}
+
+	return transitions, nil
+}
+
+// line is an entry of Grafana's alert state history.
+type line struct {
+	Previous  string            `json:"previous"`
+	Current   string            `json:"current"`
+	RuleTitle string            `json:"ruleTitle"`
+	RuleUID   string            `json:"ruleUID"`
+	Labels    map[string]string `json:"labels"`
+}
+
+func (l line) toTransition(t time.Time) alerthistorybus.Transition {
+	return alerthistorybus.Transition{
+		Time:     t.UTC(),
+		RuleUID:  l.RuleUID,
+		Title:    l.RuleTitle,
+		Labels:   l.Labels,
+		Previous: l.Previous,
+		Current:  l.Current,
+	}
+}
@@ -0,0 +1,136 @@
+// Package lokihistory implements the alert history store by querying Loki
+// directly, for Grafana instances that write their alert state history to
+// Loki.
+package lokihistory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"health-api/business/domain/alerthistorybus"
+	"health-api/foundation/logger"
+)
+
+// selector matches the streams Grafana writes alert state history to.
+const selector = `{from="state-history"}`
+
+// limit is the maximum number of log lines requested at once.
+const limit = 5000
+
+// Store implements alerthistorybus.Storer using Loki.
+type Store struct {
+	log        *logger.Logger
+	lokiURL    string
+	httpClient *http.Client
+}
+
+// NewStore creates a new Loki-backed alert history store. The transport
+// carries tracing and request metrics; nil uses http.DefaultTransport.
+func NewStore(log *logger.Logger, lokiURL string, transport http.RoundTripper) *Store {
+	return &Store{
+		log:     log,
+		lokiURL: lokiURL,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+}
+
+// QueryTransitions retrieves the state transitions recorded between from
+// and to.
+func (s *Store) QueryTransitions(ctx context.Context, from, to time.Time) ([]alerthistorybus.Transition, error) {
+	query := url.Values{}
+	query.Set("query", selector)
+	query.Set("start", strconv.FormatInt(from.UnixNano(), 10))
+	query.Set("end", strconv.FormatInt(to.UnixNano(), 10))
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("direction", "forward")
+
+	queryURL := fmt.Sprintf("%s/loki/api/v1/query_range?%s", s.lokiURL, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating loki request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying loki: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Result []struct {
+				Values [][2]string `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding loki response: %w", err)
+	}
+
+	var transitions []alerthistorybus.Transition
+	for _, stream := range result.Data.Result {
+		for _, v := range stream.Values {
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			var l line
+			if err := json.Unmarshal([]byte(v[1]), &l); err != nil {
+				s.log.Warn(ctx, "lokihistory", "status", "skipping undecodable history line", "error", err)
+				continue
+			}
+
+			transitions = append(transitions, alerthistorybus.Transition{
+				Time:     time.Unix(0, ns).UTC(),
+				RuleUID:  l.RuleUID,
+				Title:    l.RuleTitle,
+				Labels:   l.Labels,
+				Previous: l.Previous,
+				Current:  l.Current,
+			})
+		}
+	}
+
+	return transitions, nil
+}
+
+// Check verifies that Loki is reachable and ready.
+func (s *Store) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.lokiURL+"/ready", nil)
+	if err != nil {
+		return fmt.Errorf("creating ready request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("querying loki readiness: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("loki returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// line is a log line of Grafana's alert state history.
+type line struct {
+	Previous  string            `json:"previous"`
+	Current   string            `json:"current"`
+	RuleTitle string            `json:"ruleTitle"`
+	RuleUID   string            `json:"ruleUID"`
+	Labels    map[string]string `json:"labels"`
+}
@@ -0,0 +1,143 @@
+// Package alertrulebus provides business logic for provisioning alert rules
+// for registered targets.
+package alertrulebus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+
+	"github.com/prometheus/common/model"
+)
+
+// Provisioner creates or updates alert rules in the alerting backend.
+// SetPaused reports whether a rule with the UID exists; a missing rule is
+// not an error.
+type Provisioner interface {
+	Upsert(ctx context.Context, rule Rule) (created bool, err error)
+	SetPaused(ctx context.Context, uid string, paused bool) (found bool, err error)
+}
+
+// Config holds the defaults applied to provisioned rules.
+type Config struct {
+	FolderUID string
+	RuleGroup string
+	For       time.Duration
+
+	// OnSave provisions a target's rule whenever the target is created or
+	// updated, not only on request.
+	OnSave bool
+}
+
+// Business manages alert rule provisioning.
+type Business struct {
+	log         *logger.Logger
+	delegate    *delegate.Delegate
+	targetBus   *targetbus.Business
+	provisioner Provisioner
+	cfg         Config
+}
+
+// NewBusiness creates a new alert rule business layer. It registers for
+// target pauses, so pausing a target pauses its alert. With cfg.OnSave it
+// also registers for target changes, so registering a target provisions
+// its alert.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, targetBus *targetbus.Business, provisioner Provisioner, cfg Config) *Business {
+	b := Business{
+		log:         log,
+		delegate:    delegate,
+		targetBus:   targetBus,
+		provisioner: provisioner,
+		cfg:         cfg,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Provision creates or updates the down alert for a registered target.
+func (b *Business) Provision(ctx context.Context, target string) (Rule, error) {
+	tgt, err := b.targetBus.QueryByName(ctx, target)
+	if err != nil {
+		return Rule{}, fmt.Errorf("query target: %w", err)
+	}
+
+	return b.upsert(ctx, tgt)
+}
+
+// upsert creates or updates the down alert for tgt.
+func (b *Business) upsert(ctx context.Context, tgt targetbus.Target) (Rule, error) {
+	rule := b.newRule(tgt)
+
+	created, err := b.provisioner.Upsert(ctx, rule)
+	if err != nil {
+		return Rule{}, fmt.Errorf("upsert rule: uid[%s]: %w", rule.UID, err)
+	}
+	rule.Created = created
+
+	b.log.Info(ctx, "alertrulebus", "status", "rule provisioned", "target", tgt.Name, "uid", rule.UID, "created", created)
+
+	return rule, nil
+}
+
+// setPaused pauses or resumes the down alert for tgt, leaving the rest of
+// the rule as it is.
+func (b *Business) setPaused(ctx context.Context, tgt targetbus.Target) error {
+	uid := ruleUID(tgt.Name)
+
+	found, err := b.provisioner.SetPaused(ctx, uid, tgt.Paused)
+	if err != nil {
+		return fmt.Errorf("set paused: uid[%s]: %w", uid, err)
+	}
+
+	if !found {
+		b.log.Info(ctx, "alertrulebus", "status", "no rule to pause", "target", tgt.Name, "uid", uid)
+		return nil
+	}
+
+	b.log.Info(ctx, "alertrulebus", "status", "rule paused", "target", tgt.Name, "uid", uid, "paused", tgt.Paused)
+
+	return nil
+}
+
+func (b *Business) newRule(tgt targetbus.Target) Rule {
+	severity := tgt.Severity
+	if severity == "" {
+		severity = "critical"
+	}
+
+	labels := map[string]string{
+		"severity": severity,
+		"probe":    "blackbox",
+		"target":   tgt.Name,
+	}
+	if tgt.Team != "" {
+		labels["team"] = tgt.Team
+	}
+
+	annotations := map[string]string{
+		"summary":     fmt.Sprintf("Probe target %s is down or unreachable", tgt.Name),
+		"description": fmt.Sprintf("The probe_success metric for %s has been 0 for more than %s, indicating the target is down or unreachable.", tgt.Name, model.Duration(b.cfg.For)),
+	}
+	if tgt.RunbookURL != "" {
+		annotations["runbook_url"] = tgt.RunbookURL
+	}
+
+	return Rule{
+		UID:         ruleUID(tgt.Name),
+		Title:       fmt.Sprintf("%s is down", tgt.Name),
+		Target:      tgt.Name,
+		Expr:        fmt.Sprintf(`probe_success{instance=%q}`, tgt.Name),
+		FolderUID:   b.cfg.FolderUID,
+		RuleGroup:   b.cfg.RuleGroup,
+		For:         model.Duration(b.cfg.For).String(),
+		Labels:      labels,
+		Annotations: annotations,
+		IsPaused:    tgt.Paused,
+	}
+}
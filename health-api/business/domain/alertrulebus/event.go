@@ -0,0 +1,72 @@
+package alertrulebus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+)
+
+// provisionTimeout bounds provisioning a saved target's rule, or pausing
+// it.
+const provisionTimeout = 10 * time.Second
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate == nil {
+		return
+	}
+
+	if b.cfg.OnSave {
+		b.delegate.Register(targetbus.DomainName, targetbus.ActionSaved, b.actionTargetSaved)
+	}
+	b.delegate.Register(targetbus.DomainName, targetbus.ActionPaused, b.actionTargetPaused)
+}
+
+// actionTargetSaved provisions the down alert of a created or updated
+// target. Grafana is called in the background, so a slow or failing
+// Grafana doesn't hold up the save; a failure is logged and the rule can be
+// provisioned again through POST /targets/{target}/alert.
+func (b *Business) actionTargetSaved(ctx context.Context, data delegate.Data) error {
+	var params targetbus.ActionSavedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), provisionTimeout)
+		defer cancel()
+
+		if _, err := b.upsert(ctx, params.Target); err != nil {
+			b.log.Error(ctx, "alertrulebus", "status", "provision on save failed", "target", params.Target.Name, "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// actionTargetPaused pauses the down alert of a paused target, and resumes
+// it when the target is resumed, so Grafana stops evaluating it meanwhile.
+// Like provisioning it runs in the background; a failure is logged and the
+// rule's state is set again with the next pause, resume or provisioning.
+func (b *Business) actionTargetPaused(ctx context.Context, data delegate.Data) error {
+	var params targetbus.ActionPausedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), provisionTimeout)
+		defer cancel()
+
+		if err := b.setPaused(ctx, params.Target); err != nil {
+			b.log.Error(ctx, "alertrulebus", "status", "pausing rule failed", "target", params.Target.Name, "paused", params.Target.Paused, "error", err)
+		}
+	}()
+
+	return nil
+}
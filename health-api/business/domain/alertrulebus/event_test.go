@@ -0,0 +1,100 @@
+package alertrulebus_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"health-api/business/domain/alertrulebus"
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+)
+
+type provisioner struct {
+	rules  chan alertrulebus.Rule
+	paused chan pause
+}
+
+type pause struct {
+	uid    string
+	paused bool
+}
+
+func (p *provisioner) Upsert(ctx context.Context, rule alertrulebus.Rule) (bool, error) {
+	p.rules <- rule
+	return true, nil
+}
+
+func (p *provisioner) SetPaused(ctx context.Context, uid string, paused bool) (bool, error) {
+	p.paused <- pause{uid: uid, paused: paused}
+	return true, nil
+}
+
+func Test_TargetSaved(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+	tgt := targetbus.Target{Name: "https://shop.example.com", Team: "payments", Severity: "warning"}
+
+	t.Run("on save", func(t *testing.T) {
+		d := delegate.New(log)
+		p := provisioner{rules: make(chan alertrulebus.Rule, 1)}
+		alertrulebus.NewBusiness(log, d, nil, &p, alertrulebus.Config{FolderUID: "probe-alerts", For: 5 * time.Minute, OnSave: true})
+
+		d.Call(context.Background(), targetbus.ActionSavedData(tgt))
+
+		select {
+		case rule := <-p.rules:
+			if rule.UID != "shop-example-com-down" || rule.Labels["team"] != "payments" || rule.Labels["severity"] != "warning" {
+				t.Errorf("Should provision the saved target's rule, got %s %v", rule.UID, rule.Labels)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Should provision the rule when the target is saved")
+		}
+	})
+
+	t.Run("on request only", func(t *testing.T) {
+		d := delegate.New(log)
+		p := provisioner{rules: make(chan alertrulebus.Rule, 1)}
+		alertrulebus.NewBusiness(log, d, nil, &p, alertrulebus.Config{})
+
+		d.Call(context.Background(), targetbus.ActionSavedData(tgt))
+
+		select {
+		case rule := <-p.rules:
+			t.Errorf("Should not provision on save unless enabled, got %s", rule.UID)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+func Test_TargetPaused(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+	d := delegate.New(log)
+	p := provisioner{paused: make(chan pause, 1)}
+	alertrulebus.NewBusiness(log, d, nil, &p, alertrulebus.Config{})
+
+	tests := []struct {
+		name   string
+		paused bool
+	}{
+		{name: "pause", paused: true},
+		{name: "resume", paused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d.Call(context.Background(), targetbus.ActionPausedData(targetbus.Target{Name: "https://shop.example.com", Paused: tt.paused}))
+
+			select {
+			case got := <-p.paused:
+				if got.uid != "shop-example-com-down" || got.paused != tt.paused {
+					t.Errorf("Should set the rule's paused state to %t, got %+v", tt.paused, got)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Should update the rule when the target is paused or resumed")
+			}
+		})
+	}
+}
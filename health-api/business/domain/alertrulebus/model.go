@@ -0,0 +1,38 @@
+package alertrulebus
+
+import (
+	"strings"
+)
+
+// Rule is the alert rule that fires when a target's probe fails.
+type Rule struct {
+	UID         string            `json:"uid"`
+	Title       string            `json:"title"`
+	Target      string            `json:"target"`
+	Expr        string            `json:"expr"`
+	FolderUID   string            `json:"folder_uid"`
+	RuleGroup   string            `json:"rule_group"`
+	For         string            `json:"for"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	IsPaused    bool              `json:"is_paused"`
+	Created     bool              `json:"created"`
+}
+
+// ruleUID derives the rule UID from the target the same way the Helm chart
+// does, so rules provisioned by the chart are updated rather than duplicated.
+func ruleUID(target string) string {
+	slug := strings.NewReplacer(
+		"https://", "",
+		"http://", "",
+		".", "-",
+		"/", "-",
+		":", "-",
+	).Replace(target)
+
+	if len(slug) > 63 {
+		slug = slug[:63]
+	}
+
+	return strings.TrimSuffix(slug, "-") + "-down"
+}
@@ -0,0 +1,254 @@
+// Package grafanarule implements alert rule provisioning through the
+// Grafana alerting provisioning API.
+package grafanarule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"health-api/business/domain/alertrulebus"
+	"health-api/foundation/logger"
+)
+
+// Store implements alertrulebus.Provisioner using Grafana.
+type Store struct {
+	log           *logger.Logger
+	grafanaURL    string
+	credentials   atomic.Pointer[credentials]
+	datasourceUID string
+	httpClient    *http.Client
+}
+
+// NewStore creates a Grafana alert rule provisioner. Rules query the
+// datasource identified by datasourceUID.
+func NewStore(log *logger.Logger, grafanaURL, user, password, datasourceUID string, transport http.RoundTripper) *Store {
+	s := Store{
+		log:           log,
+		grafanaURL:    grafanaURL,
+		datasourceUID: datasourceUID,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+	s.SetCredentials(user, password)
+
+	return &s
+}
+
+// credentials holds the basic auth user and password for Grafana.
+type credentials struct {
+	user     string
+	password string
+}
+
+// SetCredentials replaces the credentials used for subsequent requests,
+// e.g. after the Secret holding them was rotated.
+func (s *Store) SetCredentials(user, password string) {
+	s.credentials.Store(&credentials{user: user, password: password})
+}
+
+// Upsert creates the rule, or replaces it when a rule with the same UID
+// already exists.
+func (s *Store) Upsert(ctx context.Context, rule alertrulebus.Rule) (bool, error) {
+	body, err := json.Marshal(s.toGrafana(rule))
+	if err != nil {
+		return false, fmt.Errorf("marshal rule: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/provisioning/alert-rules/%s", s.grafanaURL, rule.UID)
+
+	status, err := s.do(ctx, http.MethodGet, url, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	switch status {
+	case http.StatusOK:
+		if status, err = s.do(ctx, http.MethodPut, url, body, nil); err != nil {
+			return false, err
+		}
+		if status != http.StatusOK {
+			return false, fmt.Errorf("update returned status %d", status)
+		}
+		return false, nil
+
+	case http.StatusNotFound:
+		createURL := fmt.Sprintf("%s/api/v1/provisioning/alert-rules", s.grafanaURL)
+		if status, err = s.do(ctx, http.MethodPost, createURL, body, nil); err != nil {
+			return false, err
+		}
+		if status != http.StatusCreated && status != http.StatusOK {
+			return false, fmt.Errorf("create returned status %d", status)
+		}
+		return true, nil
+	}
+
+	return false, fmt.Errorf("lookup returned status %d", status)
+}
+
+// SetPaused pauses or resumes the rule with the UID. The stored rule is
+// sent back with only isPaused changed, so rules provisioned by the Helm
+// chart or edited in the Grafana UI keep their definition.
+func (s *Store) SetPaused(ctx context.Context, uid string, paused bool) (bool, error) {
+	url := fmt.Sprintf("%s/api/v1/provisioning/alert-rules/%s", s.grafanaURL, uid)
+
+	var rule map[string]any
+	status, err := s.do(ctx, http.MethodGet, url, nil, &rule)
+	if err != nil {
+		return false, err
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("lookup returned status %d", status)
+	}
+
+	if rule["isPaused"] == paused {
+		return true, nil
+	}
+	rule["isPaused"] = paused
+
+	body, err := json.Marshal(rule)
+	if err != nil {
+		return false, fmt.Errorf("marshal rule: %w", err)
+	}
+
+	if status, err = s.do(ctx, http.MethodPut, url, body, nil); err != nil {
+		return false, err
+	}
+	if status != http.StatusOK {
+		return false, fmt.Errorf("update returned status %d", status)
+	}
+
+	return true, nil
+}
+
+// do sends the request and, for a 200 response, decodes the body into out
+// when set.
+func (s *Store) do(ctx context.Context, method, url string, body []byte, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	// Keep provisioned rules editable in the Grafana UI
+	req.Header.Set("X-Disable-Provenance", "true")
+
+	if c := s.credentials.Load(); c.user != "" && c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%s %s: %w", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return 0, fmt.Errorf("%s %s: decoding response: %w", method, req.URL.Path, err)
+		}
+	}
+
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// =============================================================================
+
+// grafanaRule is the provisioning API representation of an alert rule. The
+// query mirrors the rules generated by the Helm chart: A fetches
+// probe_success, B reduces it to the last value and C fires below 1.
+type grafanaRule struct {
+	UID          string            `json:"uid"`
+	Title        string            `json:"title"`
+	FolderUID    string            `json:"folderUID"`
+	RuleGroup    string            `json:"ruleGroup"`
+	Condition    string            `json:"condition"`
+	Data         []grafanaQuery    `json:"data"`
+	NoDataState  string            `json:"noDataState"`
+	ExecErrState string            `json:"execErrState"`
+	For          string            `json:"for"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	IsPaused     bool              `json:"isPaused"`
+}
+
+type grafanaQuery struct {
+	RefID             string            `json:"refId"`
+	RelativeTimeRange relativeTimeRange `json:"relativeTimeRange"`
+	DatasourceUID     string            `json:"datasourceUid"`
+	Model             map[string]any    `json:"model"`
+}
+
+type relativeTimeRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (s *Store) toGrafana(rule alertrulebus.Rule) grafanaRule {
+	window := relativeTimeRange{From: 300}
+
+	expression := func(refID string, model map[string]any) grafanaQuery {
+		model["refId"] = refID
+		model["datasource"] = map[string]any{"type": "__expr__", "uid": "__expr__"}
+		return grafanaQuery{
+			RefID:             refID,
+			RelativeTimeRange: window,
+			DatasourceUID:     "__expr__",
+			Model:             model,
+		}
+	}
+
+	return grafanaRule{
+		UID:       rule.UID,
+		Title:     rule.Title,
+		FolderUID: rule.FolderUID,
+		RuleGroup: rule.RuleGroup,
+		Condition: "C",
+		Data: []grafanaQuery{
+			{
+				RefID:             "A",
+				RelativeTimeRange: window,
+				DatasourceUID:     s.datasourceUID,
+				Model: map[string]any{
+					"refId":         "A",
+					"expr":          rule.Expr,
+					"intervalMs":    1000,
+					"maxDataPoints": 43200,
+				},
+			},
+			expression("B", map[string]any{
+				"type":       "reduce",
+				"expression": "A",
+				"reducer":    "last",
+			}),
+			expression("C", map[string]any{
+				"type":       "threshold",
+				"expression": "B",
+				"conditions": []any{
+					map[string]any{
+						"evaluator": map[string]any{"type": "lt", "params": []float64{1, 0}},
+					},
+				},
+			}),
+		},
+		NoDataState:  "NoData",
+		ExecErrState: "OK",
+		For:          rule.For,
+		Labels:       rule.Labels,
+		Annotations:  rule.Annotations,
+		IsPaused:     rule.IsPaused,
+	}
+}
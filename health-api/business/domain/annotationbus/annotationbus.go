@@ -0,0 +1,137 @@
+// Package annotationbus provides business logic for annotations, records of
+// events such as deploys that put target failures in context.
+package annotationbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// ErrNotFound is returned when an annotation does not exist.
+var ErrNotFound = errors.New("annotation not found")
+
+// Storer defines the interface for annotation data access.
+type Storer interface {
+	Create(ctx context.Context, a Annotation) error
+	Delete(ctx context.Context, id string) error
+	Query(ctx context.Context, filter QueryFilter) ([]Annotation, error)
+	QueryByID(ctx context.Context, id string) (Annotation, error)
+}
+
+// Business manages annotations.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness creates a new annotation business layer.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create records a new annotation.
+func (b *Business) Create(ctx context.Context, na NewAnnotation) (Annotation, error) {
+	if !tenant.Get(ctx).Allows(na.Namespace) {
+		return Annotation{}, fmt.Errorf("create: namespace[%s]: %w", na.Namespace, tenant.ErrNamespace)
+	}
+
+	now := time.Now().UTC()
+
+	a := Annotation{
+		ID:        newID(),
+		Kind:      na.Kind,
+		Namespace: na.Namespace,
+		Targets:   na.Targets,
+		Time:      now,
+		Version:   na.Version,
+		Text:      na.Text,
+		Author:    na.Author,
+		URL:       na.URL,
+		Tags:      na.Tags,
+		CreatedAt: now,
+	}
+	if a.Kind == "" {
+		a.Kind = KindDeploy
+	}
+	if na.Time != nil {
+		a.Time = na.Time.UTC()
+	}
+
+	if err := b.storer.Create(ctx, a); err != nil {
+		return Annotation{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.log.Info(ctx, "annotationbus", "status", "annotation created", "id", a.ID, "kind", a.Kind, "targets", a.Targets, "version", a.Version)
+
+	return a, nil
+}
+
+// Delete removes the specified annotation.
+func (b *Business) Delete(ctx context.Context, id string) error {
+	a, err := b.storer.QueryByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("delete: id[%s]: %w", id, err)
+	}
+
+	if !tenant.Get(ctx).Allows(a.Namespace) {
+		return fmt.Errorf("delete: id[%s]: %w", id, ErrNotFound)
+	}
+
+	if err := b.storer.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: id[%s]: %w", id, err)
+	}
+
+	return nil
+}
+
+// Query retrieves the annotations matching the filter that are visible to
+// the caller, newest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Annotation, error) {
+	annotations, err := b.storer.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return tenant.Filter(tenant.Get(ctx), annotations, func(a Annotation) string { return a.Namespace }), nil
+}
+
+// LatestDeploys returns the most recent deploy visible to the caller for
+// every target that has one. Deploys in the future are ignored.
+func (b *Business) LatestDeploys(ctx context.Context) (map[string]Annotation, error) {
+	kind := KindDeploy
+	deploys, err := b.Query(ctx, QueryFilter{Kind: &kind})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	latest := make(map[string]Annotation)
+	for _, a := range deploys {
+		if a.Time.After(now) {
+			continue
+		}
+		for _, target := range a.Targets {
+			if _, ok := latest[target]; !ok {
+				latest[target] = a
+			}
+		}
+	}
+
+	return latest, nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,52 @@
+package annotationbus
+
+import (
+	"slices"
+	"time"
+)
+
+// KindDeploy is the kind of deploy annotations, and of annotations created
+// without a kind.
+const KindDeploy = "deploy"
+
+// Annotation records an event that affected the listed targets, such as a
+// rollout. Time is when the event happened; CreatedAt when it was recorded.
+type Annotation struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace,omitempty"`
+	Targets   []string          `json:"targets"`
+	Time      time.Time         `json:"time"`
+	Version   string            `json:"version,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Author    string            `json:"author,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// NewAnnotation contains the information needed to create an annotation.
+// Time defaults to now.
+type NewAnnotation struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace"`
+	Targets   []string          `json:"targets" validate:"required"`
+	Time      *time.Time        `json:"time"`
+	Version   string            `json:"version"`
+	Text      string            `json:"text"`
+	Author    string            `json:"author"`
+	URL       string            `json:"url" validate:"url"`
+	Tags      map[string]string `json:"tags"`
+}
+
+// Covers reports whether the annotation applies to target.
+func (a Annotation) Covers(target string) bool {
+	return slices.Contains(a.Targets, target)
+}
+
+// QueryFilter narrows the annotations returned.
+type QueryFilter struct {
+	Target *string
+	Kind   *string
+	Since  *time.Time
+}
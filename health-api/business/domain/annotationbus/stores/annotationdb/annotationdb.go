@@ -0,0 +1,86 @@
+// Package annotationdb implements the annotation store on top of jsondb.
+package annotationdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"health-api/business/domain/annotationbus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements annotationbus.Storer.
+type Store struct {
+	log         *logger.Logger
+	annotations *jsondb.Collection[annotationbus.Annotation]
+}
+
+// NewStore opens the annotations collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	annotations, err := jsondb.NewCollection[annotationbus.Annotation](db, "annotations")
+	if err != nil {
+		return nil, fmt.Errorf("opening annotations: %w", err)
+	}
+
+	return &Store{
+		log:         log,
+		annotations: annotations,
+	}, nil
+}
+
+// Create inserts a new annotation.
+func (s *Store) Create(ctx context.Context, a annotationbus.Annotation) error {
+	if err := s.annotations.Insert(a.ID, a); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an annotation.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if err := s.annotations.Delete(id); err != nil {
+		if errors.Is(err, jsondb.ErrNotFound) {
+			return annotationbus.ErrNotFound
+		}
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves the annotations matching the filter, newest first.
+func (s *Store) Query(ctx context.Context, filter annotationbus.QueryFilter) ([]annotationbus.Annotation, error) {
+	var annotations []annotationbus.Annotation
+	for _, a := range s.annotations.All() {
+		if filter.Target != nil && !a.Covers(*filter.Target) {
+			continue
+		}
+		if filter.Kind != nil && a.Kind != *filter.Kind {
+			continue
+		}
+		if filter.Since != nil && a.Time.Before(*filter.Since) {
+			continue
+		}
+		annotations = append(annotations, a)
+	}
+
+	sort.Slice(annotations, func(i, j int) bool {
+		return annotations[i].Time.After(annotations[j].Time)
+	})
+
+	return annotations, nil
+}
+
+// QueryByID retrieves the annotation with the specified ID.
+func (s *Store) QueryByID(ctx context.Context, id string) (annotationbus.Annotation, error) {
+	a, ok := s.annotations.Get(id)
+	if !ok {
+		return annotationbus.Annotation{}, annotationbus.ErrNotFound
+	}
+
+	return a, nil
+}
@@ -0,0 +1,103 @@
+package comparebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Set of aggregation functions.
+const (
+	FnAvailability = "availability"
+	FnErrorCount   = "error_count"
+	FnSamples      = "samples"
+	FnAvgLatency   = "avg_latency"
+	FnP50Latency   = "p50_latency"
+	FnP95Latency   = "p95_latency"
+	FnP99Latency   = "p99_latency"
+	FnMaxLatency   = "max_latency"
+)
+
+// Fns lists the supported aggregation functions.
+var Fns = []string{FnAvailability, FnErrorCount, FnSamples, FnAvgLatency, FnP50Latency, FnP95Latency, FnP99Latency, FnMaxLatency}
+
+// ErrUnknownFn is returned for an unsupported aggregation function.
+var ErrUnknownFn = errors.New("unknown aggregation function")
+
+// Aggregate is the value of an aggregation function over the probes of a
+// target in the window ending at To. Value is nil when the window holds no
+// probes, as none of the functions but the counts means anything then.
+type Aggregate struct {
+	Target        string    `json:"target"`
+	Fn            string    `json:"fn"`
+	WindowSeconds float64   `json:"window_seconds"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Samples       int       `json:"samples"`
+	Value         *float64  `json:"value"`
+	Unit          string    `json:"unit"`
+}
+
+// Aggregate applies fn to the probes of target over the window ending at
+// end. The target must be visible to the caller.
+func (b *Business) Aggregate(ctx context.Context, target string, fn string, window time.Duration, end time.Time) (Aggregate, error) {
+	value, unit, ok := aggregation(fn)
+	if !ok {
+		return Aggregate{}, fmt.Errorf("aggregate: %w: %s", ErrUnknownFn, fn)
+	}
+
+	if _, err := b.healthBus.QueryHealthCheckByTarget(ctx, target); err != nil {
+		return Aggregate{}, fmt.Errorf("aggregate: target[%s]: %w", target, ErrNotFound)
+	}
+
+	end = end.UTC()
+
+	stats, err := b.storer.QueryStats(ctx, target, window, end)
+	if err != nil {
+		return Aggregate{}, fmt.Errorf("aggregate: %w", err)
+	}
+
+	agg := Aggregate{
+		Target:        target,
+		Fn:            fn,
+		WindowSeconds: window.Seconds(),
+		From:          end.Add(-window),
+		To:            end,
+		Samples:       stats.Samples,
+		Unit:          unit,
+	}
+
+	if stats.Samples > 0 || fn == FnSamples || fn == FnErrorCount {
+		v := value(stats)
+		agg.Value = &v
+	}
+
+	return agg, nil
+}
+
+// aggregation returns how fn is computed from stats and the unit of the
+// result. It reports false for an unknown fn.
+func aggregation(fn string) (func(Stats) float64, string, bool) {
+	switch fn {
+	case FnAvailability:
+		return func(s Stats) float64 { return 100 * s.SuccessRate }, "percent", true
+	case FnErrorCount:
+		return func(s Stats) float64 { return math.Round(float64(s.Samples) * (1 - s.SuccessRate)) }, "count", true
+	case FnSamples:
+		return func(s Stats) float64 { return float64(s.Samples) }, "count", true
+	case FnAvgLatency:
+		return func(s Stats) float64 { return s.Latency.Avg }, "seconds", true
+	case FnP50Latency:
+		return func(s Stats) float64 { return s.Latency.P50 }, "seconds", true
+	case FnP95Latency:
+		return func(s Stats) float64 { return s.Latency.P95 }, "seconds", true
+	case FnP99Latency:
+		return func(s Stats) float64 { return s.Latency.P99 }, "seconds", true
+	case FnMaxLatency:
+		return func(s Stats) float64 { return s.Latency.Max }, "seconds", true
+	}
+
+	return nil, "", false
+}
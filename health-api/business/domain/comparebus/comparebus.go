@@ -0,0 +1,95 @@
+// Package comparebus provides business logic for comparing the probe
+// results of two targets over the same window, as teams do for a canary
+// against its baseline, and for aggregating those of one target.
+package comparebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/logger"
+)
+
+// Set of window limits.
+const (
+	MinWindow = time.Minute
+	MaxWindow = 7 * 24 * time.Hour
+)
+
+// ErrNotFound is returned for a target that is unknown or outside the
+// caller's namespaces.
+var ErrNotFound = errors.New("target not found")
+
+// Storer defines the interface for reading probe statistics.
+type Storer interface {
+	QueryStats(ctx context.Context, target string, window time.Duration, at time.Time) (Stats, error)
+}
+
+// Business manages comparisons.
+type Business struct {
+	log       *logger.Logger
+	storer    Storer
+	healthBus *healthbus.Business
+}
+
+// NewBusiness creates a new compare business layer.
+func NewBusiness(log *logger.Logger, storer Storer, healthBus *healthbus.Business) *Business {
+	return &Business{
+		log:       log,
+		storer:    storer,
+		healthBus: healthBus,
+	}
+}
+
+// Compare returns the stats of targets a and b over the window ending now.
+// Both targets must be visible to the caller.
+func (b *Business) Compare(ctx context.Context, targetA string, targetB string, window time.Duration) (Comparison, error) {
+	targets := [2]string{targetA, targetB}
+
+	for _, target := range targets {
+		if _, err := b.healthBus.QueryHealthCheckByTarget(ctx, target); err != nil {
+			return Comparison{}, fmt.Errorf("compare: target[%s]: %w", target, ErrNotFound)
+		}
+	}
+
+	now := time.Now().UTC()
+
+	var (
+		wg    sync.WaitGroup
+		stats [2]Stats
+		errs  [2]error
+	)
+	for i, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats[i], errs[i] = b.storer.QueryStats(ctx, target, window, now)
+			stats[i].Target = target
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs[:]...); err != nil {
+		return Comparison{}, fmt.Errorf("compare: %w", err)
+	}
+
+	a, bs := stats[0], stats[1]
+
+	return Comparison{
+		WindowSeconds: window.Seconds(),
+		From:          now.Add(-window),
+		To:            now,
+		A:             a,
+		B:             bs,
+		Delta: Delta{
+			SuccessRate: bs.SuccessRate - a.SuccessRate,
+			LatencyAvg:  bs.Latency.Avg - a.Latency.Avg,
+			LatencyP95:  bs.Latency.P95 - a.Latency.P95,
+			LatencyP99:  bs.Latency.P99 - a.Latency.P99,
+		},
+	}, nil
+}
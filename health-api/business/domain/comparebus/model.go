@@ -0,0 +1,40 @@
+package comparebus
+
+import "time"
+
+// Stats summarizes the probes of a target over a window. Samples is zero
+// when the window holds no probes; the other fields are zero then too.
+type Stats struct {
+	Target      string  `json:"target"`
+	Samples     int     `json:"samples"`
+	SuccessRate float64 `json:"success_rate"`
+	Latency     Latency `json:"latency_seconds"`
+}
+
+// Latency holds the probe duration statistics of a target.
+type Latency struct {
+	Avg float64 `json:"avg"`
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Delta holds the differences of B from A, so a canary B that is slower
+// than its baseline A shows positive latencies.
+type Delta struct {
+	SuccessRate float64 `json:"success_rate"`
+	LatencyAvg  float64 `json:"latency_avg_seconds"`
+	LatencyP95  float64 `json:"latency_p95_seconds"`
+	LatencyP99  float64 `json:"latency_p99_seconds"`
+}
+
+// Comparison sets the stats of two targets side by side.
+type Comparison struct {
+	WindowSeconds float64   `json:"window_seconds"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	A             Stats     `json:"a"`
+	B             Stats     `json:"b"`
+	Delta         Delta     `json:"delta"`
+}
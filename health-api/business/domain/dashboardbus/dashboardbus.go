@@ -0,0 +1,100 @@
+// Package dashboardbus provides business logic for provisioning the
+// "Service Health" dashboard for all registered targets.
+package dashboardbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// Provisioner reads and saves dashboards in the dashboard backend.
+type Provisioner interface {
+	QueryRevision(ctx context.Context, uid string) (string, error)
+	Save(ctx context.Context, d Dashboard) error
+}
+
+// Config identifies the provisioned dashboard.
+type Config struct {
+	UID       string
+	Title     string
+	FolderUID string
+}
+
+// Business manages dashboard provisioning.
+type Business struct {
+	log         *logger.Logger
+	delegate    *delegate.Delegate
+	targetBus   *targetbus.Business
+	provisioner Provisioner
+	cfg         Config
+
+	mu sync.Mutex
+}
+
+// NewBusiness creates a new dashboard business layer and registers for
+// target changes, so the dashboard follows the target inventory.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, targetBus *targetbus.Business, provisioner Provisioner, cfg Config) *Business {
+	b := Business{
+		log:         log,
+		delegate:    delegate,
+		targetBus:   targetBus,
+		provisioner: provisioner,
+		cfg:         cfg,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Reconcile builds the dashboard from all registered targets, whoever the
+// caller is, and saves it unless the backend already has the same
+// revision. It reports whether the dashboard was saved.
+func (b *Business) Reconcile(ctx context.Context) (Dashboard, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tgts, err := b.targetBus.Query(tenant.Unscoped(ctx))
+	if err != nil {
+		return Dashboard{}, false, fmt.Errorf("reconcile: %w", err)
+	}
+
+	d := Dashboard{
+		UID:       b.cfg.UID,
+		Title:     b.cfg.Title,
+		FolderUID: b.cfg.FolderUID,
+		Targets:   make([]PanelTarget, len(tgts)),
+	}
+	for i, tgt := range tgts {
+		d.Targets[i] = PanelTarget{
+			Name:      tgt.Name,
+			Namespace: tgt.Namespace,
+			Team:      tgt.Team,
+		}
+	}
+	sortTargets(d.Targets)
+	d.Revision = revision(d)
+
+	current, err := b.provisioner.QueryRevision(ctx, d.UID)
+	if err != nil {
+		return Dashboard{}, false, fmt.Errorf("reconcile: query revision: uid[%s]: %w", d.UID, err)
+	}
+
+	if current == d.Revision {
+		return d, false, nil
+	}
+
+	if err := b.provisioner.Save(ctx, d); err != nil {
+		return Dashboard{}, false, fmt.Errorf("reconcile: save: uid[%s]: %w", d.UID, err)
+	}
+
+	b.log.Info(ctx, "dashboardbus", "status", "dashboard saved", "uid", d.UID, "targets", len(d.Targets), "revision", d.Revision)
+
+	return d, true, nil
+}
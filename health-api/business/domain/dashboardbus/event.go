@@ -0,0 +1,27 @@
+package dashboardbus
+
+import (
+	"context"
+	"fmt"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(targetbus.DomainName, targetbus.ActionSaved, b.actionTargetSaved)
+	}
+}
+
+// actionTargetSaved reconciles the dashboard when a target is created or
+// changed.
+func (b *Business) actionTargetSaved(ctx context.Context, data delegate.Data) error {
+	if _, _, err := b.Reconcile(ctx); err != nil {
+		return fmt.Errorf("target saved: %w", err)
+	}
+
+	return nil
+}
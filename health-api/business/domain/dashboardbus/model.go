@@ -0,0 +1,56 @@
+package dashboardbus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+// schemaVersion changes whenever the generated layout changes, so existing
+// dashboards are rebuilt after an upgrade even if the targets did not
+// change.
+const schemaVersion = "1"
+
+// Dashboard is the "Service Health" dashboard, one section per target.
+// Revision identifies its content; the dashboard is only saved when the
+// revision in the backend differs.
+type Dashboard struct {
+	UID       string        `json:"uid"`
+	Title     string        `json:"title"`
+	FolderUID string        `json:"folder_uid,omitempty"`
+	Revision  string        `json:"revision"`
+	Targets   []PanelTarget `json:"targets"`
+}
+
+// PanelTarget is a target shown on the dashboard.
+type PanelTarget struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Team      string `json:"team,omitempty"`
+}
+
+// revision hashes the parts of the dashboard that end up in the backend.
+func revision(d Dashboard) string {
+	var b strings.Builder
+	b.WriteString(schemaVersion + "\x00" + d.Title + "\x00" + d.FolderUID)
+
+	for _, t := range d.Targets {
+		b.WriteString("\x00" + t.Name + "\x01" + t.Namespace + "\x01" + t.Team)
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// sortTargets orders the targets by team, then name, so teams are grouped
+// on the dashboard.
+func sortTargets(targets []PanelTarget) {
+	slices.SortFunc(targets, func(a, b PanelTarget) int {
+		if c := strings.Compare(a.Team, b.Team); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,226 @@
+// Package grafanadashboard implements dashboard provisioning through the
+// Grafana dashboards API.
+package grafanadashboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"health-api/business/domain/dashboardbus"
+	"health-api/foundation/logger"
+)
+
+// Set of tags put on provisioned dashboards. The revision tag carries the
+// content hash the dashboard was built from.
+const (
+	tagManaged     = "health-api"
+	tagRevisionKey = "revision:"
+)
+
+// Store implements dashboardbus.Provisioner using Grafana.
+type Store struct {
+	log           *logger.Logger
+	grafanaURL    string
+	credentials   atomic.Pointer[credentials]
+	datasourceUID string
+	httpClient    *http.Client
+}
+
+// credentials holds the basic auth user and password for Grafana.
+type credentials struct {
+	user     string
+	password string
+}
+
+// NewStore creates a Grafana dashboard provisioner. Panels query the
+// datasource identified by datasourceUID.
+func NewStore(log *logger.Logger, grafanaURL, user, password, datasourceUID string, transport http.RoundTripper) *Store {
+	s := Store{
+		log:           log,
+		grafanaURL:    grafanaURL,
+		datasourceUID: datasourceUID,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+	s.SetCredentials(user, password)
+
+	return &s
+}
+
+// SetCredentials replaces the credentials used for subsequent requests,
+// e.g. after the Secret holding them was rotated.
+func (s *Store) SetCredentials(user, password string) {
+	s.credentials.Store(&credentials{user: user, password: password})
+}
+
+// QueryRevision returns the revision of the dashboard, or "" when it does
+// not exist or was not provisioned by this service.
+func (s *Store) QueryRevision(ctx context.Context, uid string) (string, error) {
+	status, body, err := s.do(ctx, http.MethodGet, s.grafanaURL+"/api/dashboards/uid/"+url.PathEscape(uid), nil)
+	if err != nil {
+		return "", err
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return "", nil
+	case http.StatusOK:
+	default:
+		return "", fmt.Errorf("lookup returned status %d", status)
+	}
+
+	var resp struct {
+		Dashboard struct {
+			Tags []string `json:"tags"`
+		} `json:"dashboard"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decoding dashboard: %w", err)
+	}
+
+	for _, tag := range resp.Dashboard.Tags {
+		if rev, ok := strings.CutPrefix(tag, tagRevisionKey); ok {
+			return rev, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Save creates the dashboard or overwrites it, adding a new version to
+// its history in Grafana.
+func (s *Store) Save(ctx context.Context, d dashboardbus.Dashboard) error {
+	body, err := json.Marshal(map[string]any{
+		"dashboard": s.toGrafana(d),
+		"folderUid": d.FolderUID,
+		"overwrite": true,
+		"message":   fmt.Sprintf("health-api: %d targets, revision %s", len(d.Targets), d.Revision),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal dashboard: %w", err)
+	}
+
+	status, _, err := s.do(ctx, http.MethodPost, s.grafanaURL+"/api/dashboards/db", body)
+	if err != nil {
+		return err
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("save returned status %d", status)
+	}
+
+	return nil
+}
+
+func (s *Store) do(ctx context.Context, method, url string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if c := s.credentials.Load(); c.user != "" && c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: %w", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s %s: reading response: %w", method, req.URL.Path, err)
+	}
+
+	return resp.StatusCode, data, nil
+}
+
+// =============================================================================
+
+// panelHeight is the height of a target's panels in grid units.
+const panelHeight = 5
+
+// toGrafana builds the dashboard JSON model: per target a stat panel with
+// the probe result next to a graph of the probe duration.
+func (s *Store) toGrafana(d dashboardbus.Dashboard) map[string]any {
+	datasource := map[string]any{"type": "prometheus", "uid": s.datasourceUID}
+
+	panels := make([]any, 0, 2*len(d.Targets))
+	for i, t := range d.Targets {
+		y := i * panelHeight
+
+		var about []string
+		if t.Team != "" {
+			about = append(about, "team "+t.Team)
+		}
+		if t.Namespace != "" {
+			about = append(about, "namespace "+t.Namespace)
+		}
+
+		panels = append(panels,
+			map[string]any{
+				"id":          2*i + 1,
+				"type":        "stat",
+				"title":       t.Name,
+				"description": strings.Join(about, ", "),
+				"gridPos":     map[string]int{"x": 0, "y": y, "w": 6, "h": panelHeight},
+				"datasource":  datasource,
+				"targets": []any{
+					map[string]any{"refId": "A", "expr": fmt.Sprintf(`probe_success{instance=%q}`, t.Name)},
+				},
+				"options": map[string]any{
+					"colorMode":     "background",
+					"reduceOptions": map[string]any{"calcs": []string{"lastNotNull"}},
+				},
+				"fieldConfig": map[string]any{
+					"defaults": map[string]any{
+						"mappings": []any{
+							map[string]any{
+								"type": "value",
+								"options": map[string]any{
+									"0": map[string]any{"text": "DOWN", "color": "red"},
+									"1": map[string]any{"text": "UP", "color": "green"},
+								},
+							},
+						},
+					},
+				},
+			},
+			map[string]any{
+				"id":         2*i + 2,
+				"type":       "timeseries",
+				"title":      t.Name + " probe duration",
+				"gridPos":    map[string]int{"x": 6, "y": y, "w": 18, "h": panelHeight},
+				"datasource": datasource,
+				"targets": []any{
+					map[string]any{"refId": "A", "expr": fmt.Sprintf(`probe_duration_seconds{instance=%q}`, t.Name)},
+				},
+				"fieldConfig": map[string]any{
+					"defaults": map[string]any{"unit": "s"},
+				},
+			},
+		)
+	}
+
+	return map[string]any{
+		"id":       nil,
+		"uid":      d.UID,
+		"title":    d.Title,
+		"tags":     []string{tagManaged, tagRevisionKey + d.Revision},
+		"editable": false,
+		"refresh":  "30s",
+		"time":     map[string]string{"from": "now-6h", "to": "now"},
+		"panels":   panels,
+	}
+}
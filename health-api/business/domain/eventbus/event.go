@@ -0,0 +1,68 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/incidentbus"
+	"health-api/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(healthbus.DomainName, healthbus.ActionStatusChanged, b.actionStatusChanged)
+		b.delegate.Register(incidentbus.DomainName, incidentbus.ActionOpened, b.actionIncident)
+		b.delegate.Register(incidentbus.DomainName, incidentbus.ActionResolved, b.actionIncident)
+	}
+}
+
+// actionStatusChanged publishes a status transition. The first observation
+// of a healthy target, as after every restart, is not a transition.
+func (b *Business) actionStatusChanged(ctx context.Context, data delegate.Data) error {
+	var params healthbus.ActionStatusChangedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	if params.From == "" && params.To == healthbus.StatusHealthy {
+		return nil
+	}
+
+	sc := StatusChanged{
+		From:       params.From,
+		To:         params.To,
+		Probe:      params.Check.Probe,
+		Namespace:  params.Check.Namespace,
+		Team:       params.Check.Team,
+		Severity:   params.Check.Severity,
+		Suppressed: params.Check.Suppressed,
+		RootCause:  params.Check.RootCause,
+		Tags:       params.Check.Tags,
+	}
+
+	return b.Publish(ctx, TypeStatusChanged, params.Target, params.At, sc)
+}
+
+// actionIncident publishes an opened or resolved incident.
+func (b *Business) actionIncident(ctx context.Context, data delegate.Data) error {
+	var params incidentbus.ActionIncidentParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	inc := params.Incident
+
+	typ, at := TypeIncidentOpened, inc.StartedAt
+	if data.Action == incidentbus.ActionResolved {
+		typ = TypeIncidentResolved
+		if inc.EndedAt != nil {
+			at = *inc.EndedAt
+		}
+	}
+
+	return b.Publish(ctx, typ, inc.Target, at, inc)
+}
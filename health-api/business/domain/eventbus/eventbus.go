@@ -0,0 +1,101 @@
+// Package eventbus provides business logic for publishing state transitions
+// and incidents to a message bus, so other platform services can react to
+// them without polling the API.
+package eventbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+)
+
+// publishTimeout bounds a publish, which runs in line with the health
+// poller.
+const publishTimeout = 5 * time.Second
+
+// Publisher delivers an encoded event on a subject.
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// Config holds the settings of the event business layer.
+type Config struct {
+	// SubjectPrefix is prepended to the event type to form the subject,
+	// e.g. healthapi.incident.opened.
+	SubjectPrefix string
+
+	// Source identifies this service instance in the events.
+	Source string
+}
+
+// Business publishes events.
+type Business struct {
+	log       *logger.Logger
+	delegate  *delegate.Delegate
+	publisher Publisher
+	cfg       Config
+}
+
+// NewBusiness creates a new event business layer and registers for health
+// status changes and incidents.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, publisher Publisher, cfg Config) *Business {
+	b := Business{
+		log:       log,
+		delegate:  delegate,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Publish wraps data in an event of type about target and publishes it.
+func (b *Business) Publish(ctx context.Context, typ string, target string, at time.Time, data any) error {
+	evt := Event{
+		ID:      newID(),
+		Type:    typ,
+		Version: SchemaVersion,
+		Source:  b.cfg.Source,
+		Time:    at.UTC(),
+		Target:  target,
+		Data:    data,
+	}
+
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	subject := b.subject(typ)
+	if err := b.publisher.Publish(ctx, subject, body); err != nil {
+		return fmt.Errorf("publish: subject[%s]: %w", subject, err)
+	}
+
+	b.log.Debug(ctx, "eventbus", "status", "event published", "subject", subject, "target", target)
+
+	return nil
+}
+
+func (b *Business) subject(typ string) string {
+	if b.cfg.SubjectPrefix == "" {
+		return typ
+	}
+	return b.cfg.SubjectPrefix + "." + typ
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
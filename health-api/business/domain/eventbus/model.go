@@ -0,0 +1,46 @@
+package eventbus
+
+import (
+	"time"
+
+	"health-api/business/domain/healthbus"
+)
+
+// SchemaVersion is the version of the event envelope and payloads. It is
+// raised for changes consumers can't ignore, such as removed or renamed
+// fields; added fields don't raise it.
+const SchemaVersion = 1
+
+// Set of event types.
+const (
+	TypeStatusChanged    = "health.status_changed"
+	TypeIncidentOpened   = "incident.opened"
+	TypeIncidentResolved = "incident.resolved"
+)
+
+// Event is the envelope of every published event. Data holds a
+// StatusChanged for health.status_changed and an incident for the
+// incident.* types.
+type Event struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	Version int       `json:"version"`
+	Source  string    `json:"source,omitempty"`
+	Time    time.Time `json:"time"`
+	Target  string    `json:"target"`
+	Data    any       `json:"data"`
+}
+
+// StatusChanged is the payload of a health status transition. From is
+// empty the first time a target is seen down.
+type StatusChanged struct {
+	From       healthbus.Status  `json:"from"`
+	To         healthbus.Status  `json:"to"`
+	Probe      string            `json:"probe,omitempty"`
+	Namespace  string            `json:"namespace,omitempty"`
+	Team       string            `json:"team,omitempty"`
+	Severity   string            `json:"severity,omitempty"`
+	Suppressed bool              `json:"suppressed,omitempty"`
+	RootCause  string            `json:"root_cause,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
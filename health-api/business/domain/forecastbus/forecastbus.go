@@ -0,0 +1,177 @@
+// Package forecastbus provides business logic for projecting when steadily
+// decreasing quantities, such as certificate lifetimes and free disk space,
+// run out. A line is fitted through each series' recent history and
+// extended to zero.
+package forecastbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// ErrUnknownKind is returned for a filter naming an unknown kind.
+var ErrUnknownKind = errors.New("unknown forecast kind")
+
+// minSamples is the fewest points a series needs to be forecast.
+const minSamples = 3
+
+// source describes how a kind is read: the query returning its series, the
+// unit of the values and the labels identifying a series.
+type source struct {
+	query string
+	unit  string
+	key   []string
+}
+
+var sources = map[Kind]source{
+	KindCertificate: {
+		query: `(probe_ssl_earliest_cert_expiry - time()) / 86400`,
+		unit:  "days",
+		key:   []string{"instance"},
+	},
+	KindDisk: {
+		query: `node_filesystem_avail_bytes{fstype!~"tmpfs|overlay|squashfs|ramfs"}`,
+		unit:  "bytes",
+		key:   []string{"instance", "mountpoint"},
+	},
+}
+
+// ParseKind validates a kind name.
+func ParseKind(s string) (Kind, error) {
+	if _, ok := sources[Kind(s)]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKind, s)
+	}
+	return Kind(s), nil
+}
+
+// Storer defines the interface for reading series history.
+type Storer interface {
+	QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error)
+}
+
+// Config holds the history the forecasts are based on.
+type Config struct {
+	// Lookback is how much history a line is fitted through.
+	Lookback time.Duration
+
+	// Step is the resolution of the history.
+	Step time.Duration
+}
+
+// Business manages forecasts.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+	cfg    Config
+}
+
+// NewBusiness creates a new forecast business layer.
+func NewBusiness(log *logger.Logger, storer Storer, cfg Config) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+		cfg:    cfg,
+	}
+}
+
+// Query forecasts every series of the filtered kinds, those running out
+// soonest first. Callers scoped to a tenant only see series from their
+// namespaces; node metrics carry none and are only visible unscoped.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Forecast, error) {
+	kinds := []Kind{KindCertificate, KindDisk}
+	if filter.Kind != "" {
+		kinds = []Kind{filter.Kind}
+	}
+
+	now := time.Now().UTC()
+	scope := tenant.Get(ctx)
+
+	var forecasts []Forecast
+	for _, kind := range kinds {
+		src, ok := sources[kind]
+		if !ok {
+			return nil, fmt.Errorf("query: %w: %q", ErrUnknownKind, kind)
+		}
+
+		series, err := b.storer.QueryRange(ctx, src.query, now.Add(-b.cfg.Lookback), now, b.cfg.Step)
+		if err != nil {
+			return nil, fmt.Errorf("query: kind[%s]: %w", kind, err)
+		}
+
+		for _, s := range series {
+			if !scope.Allows(s.Labels["namespace"]) {
+				continue
+			}
+
+			f, ok := forecast(kind, src, s, now)
+			if !ok {
+				continue
+			}
+
+			if filter.Within > 0 && (f.ExhaustsAt == nil || f.ExhaustsAt.After(now.Add(filter.Within))) {
+				continue
+			}
+
+			forecasts = append(forecasts, f)
+		}
+	}
+
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		a, b := forecasts[i].ExhaustsAt, forecasts[j].ExhaustsAt
+		switch {
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.Before(*b)
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return forecasts[i].Target < forecasts[j].Target
+	})
+
+	return forecasts, nil
+}
+
+// forecast fits a line through the series. It reports false for series
+// with too little history to fit.
+func forecast(kind Kind, src source, s Series, now time.Time) (Forecast, bool) {
+	if len(s.Points) < minSamples {
+		return Forecast{}, false
+	}
+
+	line, ok := linearFit(s.Points)
+	if !ok {
+		return Forecast{}, false
+	}
+
+	key := make([]string, 0, len(src.key))
+	for _, l := range src.key {
+		key = append(key, s.Labels[l])
+	}
+
+	f := Forecast{
+		Kind:        kind,
+		Target:      strings.Join(key, ":"),
+		Labels:      s.Labels,
+		Unit:        src.unit,
+		Value:       s.Points[len(s.Points)-1].Value,
+		RatePerDay:  line.slope * 86400,
+		Samples:     len(s.Points),
+		Confidence:  line.r2,
+		GeneratedAt: now,
+	}
+
+	if at, ok := line.zero(); ok {
+		at = at.UTC().Truncate(time.Second)
+		days := max(at.Sub(now).Hours()/24, 0)
+		f.ExhaustsAt = &at
+		f.DaysLeft = &days
+	}
+
+	return f, true
+}
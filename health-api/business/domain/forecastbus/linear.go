@@ -0,0 +1,75 @@
+package forecastbus
+
+import (
+	"math"
+	"time"
+)
+
+// fit is a least squares line through a series, with time in seconds
+// relative to the first point.
+type fit struct {
+	origin    time.Time
+	slope     float64
+	intercept float64
+	r2        float64
+}
+
+// linearFit fits a line through the points. It reports false for fewer
+// than two distinct timestamps.
+func linearFit(points []Point) (fit, bool) {
+	if len(points) < 2 {
+		return fit{}, false
+	}
+
+	origin := points[0].Time
+	n := float64(len(points))
+
+	var sumX, sumY, sumXX, sumXY float64
+	for _, p := range points {
+		x := p.Time.Sub(origin).Seconds()
+		sumX += x
+		sumY += p.Value
+		sumXX += x * x
+		sumXY += x * p.Value
+	}
+
+	den := n*sumXX - sumX*sumX
+	if den == 0 {
+		return fit{}, false
+	}
+
+	f := fit{origin: origin}
+	f.slope = (n*sumXY - sumX*sumY) / den
+	f.intercept = (sumY - f.slope*sumX) / n
+
+	// The coefficient of determination tells how well a line explains the
+	// series: 1 for a steady decline, near 0 for noise.
+	meanY := sumY / n
+	var ssTot, ssRes float64
+	for _, p := range points {
+		x := p.Time.Sub(origin).Seconds()
+		ssTot += (p.Value - meanY) * (p.Value - meanY)
+		ssRes += (p.Value - f.at(x)) * (p.Value - f.at(x))
+	}
+	f.r2 = 1
+	if ssTot > 0 {
+		f.r2 = math.Max(0, 1-ssRes/ssTot)
+	}
+
+	return f, true
+}
+
+func (f fit) at(x float64) float64 {
+	return f.intercept + f.slope*x
+}
+
+// zero returns when the line crosses zero. It reports false when the line
+// is not decreasing.
+func (f fit) zero() (time.Time, bool) {
+	if f.slope >= 0 {
+		return time.Time{}, false
+	}
+
+	x := -f.intercept / f.slope
+	return f.origin.Add(time.Duration(x * float64(time.Second))), true
+}
@@ -0,0 +1,50 @@
+package forecastbus
+
+import "time"
+
+// Kind names a quantity that runs out over time.
+type Kind string
+
+// Set of forecasted kinds.
+const (
+	KindCertificate Kind = "certificate"
+	KindDisk        Kind = "disk"
+)
+
+// Point is one sample of a series.
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is the history of one labelled series over the lookback window.
+type Series struct {
+	Labels map[string]string
+	Points []Point
+}
+
+// Forecast projects when a series reaches zero. ExhaustsAt is nil when the
+// series is not decreasing, so it is not expected to run out.
+type Forecast struct {
+	Kind        Kind              `json:"kind"`
+	Target      string            `json:"target"`
+	Labels      map[string]string `json:"labels"`
+	Unit        string            `json:"unit"`
+	Value       float64           `json:"value"`
+	RatePerDay  float64           `json:"rate_per_day"`
+	ExhaustsAt  *time.Time        `json:"exhausts_at,omitempty"`
+	DaysLeft    *float64          `json:"days_left,omitempty"`
+	Samples     int               `json:"samples"`
+	Confidence  float64           `json:"confidence"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// QueryFilter selects the forecasts to return.
+type QueryFilter struct {
+	// Kind limits the forecasts to one kind; empty returns all.
+	Kind Kind
+
+	// Within only returns series projected to run out within the
+	// duration; zero returns every series.
+	Within time.Duration
+}
@@ -0,0 +1,185 @@
+package healthbus
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GroupByAll groups alerts by all of their labels, as in Alertmanager.
+const GroupByAll = "..."
+
+// AlertFilter holds the options of an alert query. Empty fields are not
+// applied.
+type AlertFilter struct {
+	// Severity keeps the alerts of any of the severities.
+	Severity []Severity
+
+	// GroupBy lists the labels alerts are grouped by, mirroring the
+	// Alertmanager group_by setting. An alert without a label is grouped
+	// with the alerts that lack it too.
+	GroupBy []string
+}
+
+// AlertGroup represents the alerts sharing the values of the grouped by
+// labels. FirstSeen is the earliest activation of its alerts, when known;
+// LastSeen is when the alerts were last fetched from the backends.
+type AlertGroup struct {
+	Labels    map[string]string `json:"labels"`
+	Count     int               `json:"count"`
+	Firing    int               `json:"firing"`
+	Pending   int               `json:"pending"`
+	Normal    int               `json:"normal"`
+	FirstSeen *time.Time        `json:"first_seen,omitempty"`
+	LastSeen  time.Time         `json:"last_seen"`
+	Alerts    []Alert           `json:"alerts"`
+}
+
+// apply drops the alerts not matching the filter and recounts the summary.
+func (af AlertFilter) apply(summary AlertSummary) AlertSummary {
+	if len(af.Severity) == 0 {
+		return summary
+	}
+
+	alerts := make([]Alert, 0, len(summary.Alerts))
+	for _, a := range summary.Alerts {
+		if slices.Contains(af.Severity, a.Severity) {
+			alerts = append(alerts, a)
+		}
+	}
+
+	return countAlerts(alerts)
+}
+
+// alertLabels returns the labels of the alert, including alertname, which
+// Grafana only reports as the rule title.
+func alertLabels(a Alert) map[string]string {
+	if _, ok := a.Labels["alertname"]; ok || a.Title == "" {
+		return a.Labels
+	}
+
+	labels := maps.Clone(a.Labels)
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels["alertname"] = a.Title
+
+	return labels
+}
+
+// fingerprint identifies an alert by its labels, so the same alert reported
+// by several backends is only listed once.
+func fingerprint(labels map[string]string) string {
+	names := slices.Sorted(maps.Keys(labels))
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte(0)
+		b.WriteString(labels[name])
+		b.WriteByte(0)
+	}
+
+	return b.String()
+}
+
+// stateRank orders alert states so the most urgent one wins when duplicates
+// are merged.
+var stateRank = map[string]int{
+	"firing":   2,
+	"alerting": 2,
+	"pending":  1,
+}
+
+// dedupeAlerts merges alerts with identical labels, keeping the most urgent
+// state and the earliest activation, sets their severity and recounts the
+// summary.
+func dedupeAlerts(summary AlertSummary) AlertSummary {
+	seen := make(map[string]int, len(summary.Alerts))
+	alerts := make([]Alert, 0, len(summary.Alerts))
+
+	for _, a := range applySeverity(summary.Alerts) {
+		fp := fingerprint(alertLabels(a))
+
+		i, ok := seen[fp]
+		if !ok {
+			seen[fp] = len(alerts)
+			alerts = append(alerts, a)
+			continue
+		}
+
+		if stateRank[a.State] > stateRank[alerts[i].State] {
+			alerts[i].State = a.State
+		}
+		if a.ActiveAt != "" && (alerts[i].ActiveAt == "" || activeAt(a).Before(activeAt(alerts[i]))) {
+			alerts[i].ActiveAt = a.ActiveAt
+		}
+	}
+
+	return countAlerts(alerts)
+}
+
+// groupAlerts groups the alerts by the labels, ordered by the group labels.
+func groupAlerts(alerts []Alert, by []string, seenAt time.Time) []AlertGroup {
+	all := slices.Contains(by, GroupByAll)
+
+	index := make(map[string]int)
+	var groups []AlertGroup
+
+	for _, a := range alerts {
+		labels := alertLabels(a)
+
+		key := make(map[string]string, len(by))
+		for name, value := range labels {
+			if all || slices.Contains(by, name) {
+				key[name] = value
+			}
+		}
+
+		fp := fingerprint(key)
+		i, ok := index[fp]
+		if !ok {
+			i = len(groups)
+			index[fp] = i
+			groups = append(groups, AlertGroup{
+				Labels:   key,
+				LastSeen: seenAt,
+			})
+		}
+
+		g := &groups[i]
+		g.Alerts = append(g.Alerts, a)
+		g.Count++
+
+		switch a.State {
+		case "firing":
+			g.Firing++
+		case "pending":
+			g.Pending++
+		case "inactive", "normal":
+			g.Normal++
+		}
+
+		if at := activeAt(a); !at.IsZero() && (g.FirstSeen == nil || at.Before(*g.FirstSeen)) {
+			g.FirstSeen = &at
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return fingerprint(groups[i].Labels) < fingerprint(groups[j].Labels)
+	})
+
+	return groups
+}
+
+// activeAt parses the activation time of the alert. Backends report the zero
+// time or nothing for inactive alerts.
+func activeAt(a Alert) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, a.ActiveAt)
+	if err != nil || t.Year() <= 1 {
+		return time.Time{}
+	}
+	return t.UTC()
+}
@@ -0,0 +1,46 @@
+package healthbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// applyDegraded marks healthy checks as degraded when they are slower than
+// their latency SLO or when some of their steps failed. A target's own SLO
+// takes precedence over the configured default.
+func (b *Business) applyDegraded(checks []HealthCheck) {
+	for i, check := range checks {
+		if check.Status != StatusHealthy {
+			continue
+		}
+
+		slo := check.LatencySLO
+		if slo == 0 {
+			slo = b.cfg.LatencySLO.Seconds()
+		}
+
+		switch {
+		case slo > 0 && check.DurationSeconds > slo:
+			checks[i].Status = StatusDegraded
+			checks[i].DegradedReason = fmt.Sprintf("duration %s exceeds SLO %s", seconds(check.DurationSeconds), seconds(slo))
+
+		case failedSteps(check.Steps) > 0:
+			checks[i].Status = StatusDegraded
+			checks[i].DegradedReason = fmt.Sprintf("%d of %d steps failed", failedSteps(check.Steps), len(check.Steps))
+		}
+	}
+}
+
+func failedSteps(steps []StepTiming) int {
+	var n int
+	for _, s := range steps {
+		if !s.Success {
+			n++
+		}
+	}
+	return n
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+}
@@ -0,0 +1,51 @@
+package healthbus
+
+import (
+	"context"
+	"time"
+
+	"health-api/business/sdk/delegate"
+)
+
+// Set of limits for delivering status change events.
+const (
+	eventQueue   = 1024
+	eventTimeout = 30 * time.Second
+)
+
+func newEventQueue() chan delegate.Data {
+	return make(chan delegate.Data, eventQueue)
+}
+
+// enqueue hands a status change to the dispatcher without blocking the
+// caller. Changes are dropped and logged once the queue is full.
+func (b *Business) enqueue(ctx context.Context, data delegate.Data) {
+	select {
+	case b.events <- data:
+	default:
+		b.log.Error(ctx, "healthbus", "status", "event queue full, status change dropped", "action", data.Action)
+	}
+}
+
+// RunEvents delivers queued status changes to the registered domains until
+// ctx is canceled. Each delivery gets its own deadline, so a slow handler
+// holds up later events but never the query that observed the change.
+func (b *Business) RunEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data := <-b.events:
+			b.dispatch(ctx, data)
+		}
+	}
+}
+
+func (b *Business) dispatch(ctx context.Context, data delegate.Data) {
+	ctx, cancel := context.WithTimeout(ctx, eventTimeout)
+	defer cancel()
+
+	if err := b.delegate.Call(ctx, data); err != nil {
+		b.log.Error(ctx, "healthbus", "status", "delegate call failed", "error", err)
+	}
+}
@@ -0,0 +1,76 @@
+package healthbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/memorystore"
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+)
+
+func Test_StatusEvents(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+	store := memorystore.NewStore()
+	store.SetChecks(healthbus.HealthCheck{Target: "https://shop.example.com", Status: healthbus.StatusDown, LastChecked: time.Now()})
+
+	release := make(chan struct{})
+	received := make(chan healthbus.ActionStatusChangedParms, 1)
+
+	dlg := delegate.New(log)
+	dlg.Register(healthbus.DomainName, healthbus.ActionStatusChanged, func(ctx context.Context, data delegate.Data) error {
+		<-release
+
+		if err := ctx.Err(); err != nil {
+			t.Errorf("Should deliver with a live context, got %s", err)
+		}
+
+		var params healthbus.ActionStatusChangedParms
+		if err := json.Unmarshal(data.RawParams, &params); err != nil {
+			t.Errorf("Should be able to decode the event: %s", err)
+		}
+		received <- params
+
+		return nil
+	})
+
+	b := healthbus.NewBusiness(log, dlg, store, nil, nil, nil, healthbus.Config{})
+
+	events, stop := context.WithCancel(context.Background())
+	defer stop()
+	go b.RunEvents(events)
+
+	// The query returns while the handler is still blocked, and canceling
+	// its context does not reach the delivery.
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := b.QueryHealthChecks(ctx, healthbus.QueryFilter{})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Should be able to query the checks: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Should not wait on the status change handler")
+	}
+	cancel()
+	close(release)
+
+	select {
+	case params := <-received:
+		if params.Target != "https://shop.example.com" || params.To != healthbus.StatusDown {
+			t.Errorf("Should report the observed status, got %s", params.String())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Should deliver the status change")
+	}
+}
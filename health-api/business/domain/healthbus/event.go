@@ -0,0 +1,58 @@
+package healthbus
+
+import (
+	"encoding/json"
+	"time"
+
+	"health-api/business/sdk/delegate"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "health"
+
+// Set of delegate actions for health.
+const (
+	ActionStatusChanged = "statuschanged"
+)
+
+// ActionStatusChangedParms represents the parameters for the status changed
+// action. From is empty the first time a target is observed.
+type ActionStatusChangedParms struct {
+	Target string      `json:"target"`
+	From   Status      `json:"from"`
+	To     Status      `json:"to"`
+	At     time.Time   `json:"at"`
+	Check  HealthCheck `json:"check"`
+}
+
+// String returns a string representation of the action parameters.
+func (sc *ActionStatusChangedParms) String() string {
+	return "target=" + sc.Target + " from=" + string(sc.From) + " to=" + string(sc.To)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (sc *ActionStatusChangedParms) Marshal() ([]byte, error) {
+	return json.Marshal(sc)
+}
+
+// ActionStatusChangedData constructs the data for the status changed action.
+func ActionStatusChangedData(check HealthCheck, from Status) delegate.Data {
+	params := ActionStatusChangedParms{
+		Target: check.Target,
+		From:   from,
+		To:     check.Status,
+		At:     time.Now().UTC(),
+		Check:  check,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionStatusChanged,
+		RawParams: rawParams,
+	}
+}
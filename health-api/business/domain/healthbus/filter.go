@@ -0,0 +1,100 @@
+package healthbus
+
+import (
+	"strings"
+
+	"health-api/business/sdk/filterexpr"
+)
+
+// QueryFilter holds the available fields a health check query can be
+// filtered on. Nil fields are not applied.
+type QueryFilter struct {
+	Team *string
+
+	// Expr is a filter expression from ParseFilter, applied after the
+	// other fields.
+	Expr *filterexpr.Expr
+}
+
+func (qf QueryFilter) apply(checks []HealthCheck) []HealthCheck {
+	if qf.Team == nil && qf.Expr == nil {
+		return checks
+	}
+
+	filtered := make([]HealthCheck, 0, len(checks))
+	for _, check := range checks {
+		if qf.Team != nil && check.Team != *qf.Team {
+			continue
+		}
+		if qf.Expr != nil && !qf.Expr.Match(check.lookup) {
+			continue
+		}
+		filtered = append(filtered, check)
+	}
+
+	return filtered
+}
+
+// =============================================================================
+
+// tagPrefix selects a target tag in a filter expression, as in
+// tag.env=prod.
+const tagPrefix = "tag."
+
+// filterFields maps the fields a filter expression can compare to their
+// values on a check.
+var filterFields = map[string]func(c HealthCheck) []string{
+	"target":          func(c HealthCheck) []string { return []string{c.Target} },
+	"status":          func(c HealthCheck) []string { return []string{string(c.Status)} },
+	"probe":           func(c HealthCheck) []string { return []string{c.Probe} },
+	"namespace":       func(c HealthCheck) []string { return []string{c.Namespace} },
+	"instance":        func(c HealthCheck) []string { return []string{c.Instance} },
+	"team":            func(c HealthCheck) []string { return []string{c.Team} },
+	"severity":        func(c HealthCheck) []string { return []string{c.Severity} },
+	"maintenance":     func(c HealthCheck) []string { return []string{c.Maintenance} },
+	"root_cause":      func(c HealthCheck) []string { return []string{c.RootCause} },
+	"degraded_reason": func(c HealthCheck) []string { return []string{c.DegradedReason} },
+	"region": func(c HealthCheck) []string {
+		if len(c.Regions) == 0 {
+			return []string{c.Region}
+		}
+		regions := make([]string, 0, len(c.Regions))
+		for region := range c.Regions {
+			regions = append(regions, region)
+		}
+		return regions
+	},
+}
+
+// ParseFilter parses a filter expression over health check fields, such as
+// status=down AND (team=payments OR probe=icmp). Errors are
+// *filterexpr.SyntaxError values carrying the position of the problem.
+func ParseFilter(s string) (*filterexpr.Expr, error) {
+	return filterexpr.Parse(s, FilterField)
+}
+
+// FilterField reports whether field, in lower case, is a health check field
+// filter expressions and groupings can refer to.
+func FilterField(field string) bool {
+	if key, ok := strings.CutPrefix(field, tagPrefix); ok {
+		return key != ""
+	}
+	_, ok := filterFields[field]
+	return ok
+}
+
+// lookup returns the values of a filter expression field on the check.
+func (c HealthCheck) lookup(field string) []string {
+	if key, ok := strings.CutPrefix(field, tagPrefix); ok {
+		if v, ok := c.Tags[key]; ok {
+			return []string{v}
+		}
+		return nil
+	}
+
+	if fn, ok := filterFields[field]; ok {
+		return fn(c)
+	}
+
+	return nil
+}
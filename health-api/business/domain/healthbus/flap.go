@@ -0,0 +1,77 @@
+package healthbus
+
+import (
+	"math"
+	"time"
+)
+
+// FlapConfig controls flap detection. A target that changes status at
+// least Threshold times within Window is flapping. A zero Threshold
+// disables detection.
+type FlapConfig struct {
+	Window    time.Duration
+	Threshold int
+}
+
+// recordChange remembers a status change of target. The caller must hold
+// b.mu.
+func (b *Business) recordChange(target string, at time.Time) {
+	if b.cfg.Flap.Threshold <= 0 {
+		return
+	}
+
+	b.changes[target] = append(b.pruneChanges(target, at), at)
+}
+
+// flapScore returns the number of status changes of target within the
+// window relative to the threshold; 1 or more means flapping. The caller
+// must hold b.mu.
+func (b *Business) flapScore(target string, now time.Time) float64 {
+	if b.cfg.Flap.Threshold <= 0 {
+		return 0
+	}
+
+	changes := b.pruneChanges(target, now)
+	if len(changes) == 0 {
+		delete(b.changes, target)
+		return 0
+	}
+	b.changes[target] = changes
+
+	score := float64(len(changes)) / float64(b.cfg.Flap.Threshold)
+
+	return math.Round(score*100) / 100
+}
+
+// applyFlapping sets the flap score of each check and marks flapping
+// targets with StatusFlapping, unless they are under maintenance.
+func (b *Business) applyFlapping(checks []HealthCheck) {
+	if b.cfg.Flap.Threshold <= 0 {
+		return
+	}
+
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, check := range checks {
+		score := b.flapScore(check.Target, now)
+
+		checks[i].FlapScore = score
+		if score >= 1 && check.Status != StatusMaintenance && check.Status != StatusPaused {
+			checks[i].Status = StatusFlapping
+		}
+	}
+}
+
+func (b *Business) pruneChanges(target string, now time.Time) []time.Time {
+	changes := b.changes[target]
+
+	cutoff := now.Add(-b.cfg.Flap.Window)
+	for len(changes) > 0 && !changes[0].After(cutoff) {
+		changes = changes[1:]
+	}
+
+	return changes
+}
@@ -3,15 +3,50 @@ package healthbus
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/domain/snoozebus"
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
 	"health-api/foundation/logger"
 )
 
+// ErrNotFound is returned when no store knows a target, or the target is
+// outside the caller's namespaces. Stores wrap it so callers can tell a
+// missing target from a failing backend.
+var ErrNotFound = errors.New("target not found")
+
 // Business manages health check operations.
 type Business struct {
-	log    *logger.Logger
-	storer Storer
+	log            *logger.Logger
+	delegate       *delegate.Delegate
+	storer         Storer
+	targetBus      *targetbus.Business
+	maintenanceBus *maintenancebus.Business
+	snoozeBus      *snoozebus.Business
+	deps           []Dependency
+	cfg            Config
+	lastSync       atomic.Int64
+	snapshot       atomic.Pointer[snapshot]
+	cacheHits      atomic.Int64
+	cacheMisses    atomic.Int64
+	started        atomic.Bool
+
+	mu       sync.Mutex
+	statuses map[string]Status
+	changes  map[string][]time.Time
+
+	events chan delegate.Data
+
+	injectMu   sync.Mutex
+	injections map[string]Injection
 }
 
 // Storer defines the interface for health check data access.
@@ -21,24 +56,91 @@ type Storer interface {
 	QueryAlerts(ctx context.Context) (AlertSummary, error)
 }
 
-// NewBusiness creates a new health check business layer.
-func NewBusiness(log *logger.Logger, storer Storer) *Business {
-	return &Business{
-		log:    log,
-		storer: storer,
+// Checker defines the behavior for verifying connectivity to a backend.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Dependency represents a backend the service relies on. Required
+// dependencies cause readiness to fail when they are unreachable.
+type Dependency struct {
+	Name     string
+	Required bool
+	Checker  Checker
+}
+
+// Config holds the settings that shape how check results are interpreted.
+type Config struct {
+	Flap FlapConfig
+
+	// LatencySLO marks healthy checks slower than it as degraded. Targets
+	// can override it; zero disables the default.
+	LatencySLO time.Duration
+
+	Regions RegionConfig
+
+	Startup StartupConfig
+}
+
+// NewBusiness creates a new health check business layer. The target,
+// maintenance and snooze business layers are optional.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, targetBus *targetbus.Business, maintenanceBus *maintenancebus.Business, snoozeBus *snoozebus.Business, cfg Config, deps ...Dependency) *Business {
+	b := Business{
+		log:            log,
+		delegate:       delegate,
+		storer:         storer,
+		targetBus:      targetBus,
+		maintenanceBus: maintenanceBus,
+		snoozeBus:      snoozeBus,
+		deps:           deps,
+		cfg:            cfg,
+		statuses:       make(map[string]Status),
+		changes:        make(map[string][]time.Time),
+		injections:     make(map[string]Injection),
 	}
+
+	b.events = newEventQueue()
+
+	// Without a startup gate readiness only depends on the checks.
+	b.started.Store(!cfg.Startup.enabled())
+
+	return &b
 }
 
-// QueryHealthChecks retrieves all health checks.
-func (b *Business) QueryHealthChecks(ctx context.Context) (HealthSummary, error) {
-	checks, err := b.storer.QueryHealthChecks(ctx)
-	if err != nil {
-		return HealthSummary{}, err
+// QueryHealthChecks retrieves all health checks matching the filter.
+func (b *Business) QueryHealthChecks(ctx context.Context, filter QueryFilter) (HealthSummary, error) {
+	var checks []HealthCheck
+	var sourceErrs []SourceError
+
+	if snap := b.snapshot.Load(); snap != nil {
+		checks, sourceErrs = slices.Clone(snap.checks), snap.checkErrs
+		b.cacheHits.Add(1)
+	} else {
+		b.cacheMisses.Add(1)
+
+		var err error
+		if checks, err = b.storer.QueryHealthChecks(ctx); err != nil {
+			var ok bool
+			if sourceErrs, ok = partialErrors(err); !ok {
+				return HealthSummary{}, err
+			}
+		}
+
+		b.markSynced()
 	}
 
+	checks = b.applyMetadata(ctx, checks)
+	b.observe(ctx, checks)
+	b.applyFlapping(checks)
+
+	checks = scopeChecks(tenant.Get(ctx), checks)
+	checks = filter.apply(checks)
+
 	summary := HealthSummary{
-		Checks: checks,
-		Total:  len(checks),
+		Checks:  checks,
+		Total:   len(checks),
+		Partial: len(sourceErrs) > 0,
+		Errors:  sourceErrs,
 	}
 
 	// Count statuses
@@ -46,24 +148,369 @@ func (b *Business) QueryHealthChecks(ctx context.Context) (HealthSummary, error)
 		switch check.Status {
 		case StatusHealthy:
 			summary.Healthy++
+		case StatusDegraded:
+			summary.Degraded++
 		case StatusDown:
 			summary.Down++
 		case StatusUnknown:
 			summary.Unknown++
+		case StatusFlapping:
+			summary.Flapping++
+		case StatusMaintenance:
+			summary.Maintenance++
+		case StatusPaused:
+			summary.Paused++
 		}
 	}
 
 	return summary, nil
 }
 
-// QueryHealthCheckByTarget retrieves a specific health check by target.
+// QueryHealthCheckByTarget retrieves a specific health check by target. A
+// check outside the caller's namespaces is reported as not found.
 func (b *Business) QueryHealthCheckByTarget(ctx context.Context, target string) (HealthCheck, error) {
-	return b.storer.QueryHealthCheckByTarget(ctx, target)
+	if snap := b.snapshot.Load(); snap != nil {
+		if checks := targetChecks(snap.checks, target); len(checks) > 0 {
+			b.cacheHits.Add(1)
+			checks = b.applyMetadata(ctx, checks)
+			b.applyFlapping(checks)
+			return b.scopeCheck(ctx, checks[0])
+		}
+	}
+	b.cacheMisses.Add(1)
+
+	checks, err := b.queryTarget(ctx, target)
+	if err != nil {
+		return HealthCheck{}, err
+	}
+
+	checks = b.applyMetadata(ctx, checks)
+	b.observe(ctx, checks)
+	b.applyFlapping(checks)
+
+	return b.scopeCheck(ctx, checks[0])
+}
+
+// Recheck queries the store for target, bypassing the snapshot, and puts
+// the fresh result in the snapshot so later queries see it too. A target
+// outside the caller's namespaces is reported as not found before the
+// snapshot or the notifications are touched.
+func (b *Business) Recheck(ctx context.Context, target string) (HealthCheck, error) {
+	if err := b.CheckAccess(ctx, target); err != nil {
+		return HealthCheck{}, err
+	}
+
+	checks, err := b.queryTarget(ctx, target)
+	if err != nil {
+		return HealthCheck{}, err
+	}
+
+	// The store may be the first to name the target's namespace.
+	scoped := b.applyMetadata(ctx, slices.Clone(checks))
+	if _, err := b.scopeCheck(ctx, scoped[0]); err != nil {
+		return HealthCheck{}, err
+	}
+
+	b.replaceTarget(target, checks)
+	b.observe(ctx, scoped)
+	b.applyFlapping(scoped)
+
+	return scoped[0], nil
+}
+
+// CheckAccess reports ErrNotFound when target is known to be outside the
+// caller's namespaces, going by its registered namespace or else the one
+// of its last synced check. A target known to neither passes.
+func (b *Business) CheckAccess(ctx context.Context, target string) error {
+	ns, known := b.namespaceOf(ctx, target)
+	if known && !tenant.Get(ctx).Allows(ns) {
+		return fmt.Errorf("%w: %s", ErrNotFound, target)
+	}
+
+	return nil
+}
+
+func (b *Business) namespaceOf(ctx context.Context, target string) (string, bool) {
+	if b.targetBus != nil {
+		if tgt, err := b.targetBus.QueryByName(tenant.Unscoped(ctx), target); err == nil && tgt.Namespace != "" {
+			return tgt.Namespace, true
+		}
+	}
+
+	if snap := b.snapshot.Load(); snap != nil {
+		if checks := targetChecks(snap.checks, target); len(checks) > 0 {
+			return checks[0].Namespace, true
+		}
+	}
+
+	return "", false
+}
+
+// queryTarget asks the store for the checks of target, one per region for
+// targets probed from several regions.
+func (b *Business) queryTarget(ctx context.Context, target string) ([]HealthCheck, error) {
+	check, err := b.storer.QueryHealthCheckByTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := []HealthCheck{check}
+
+	// A target probed from several regions is reported by the store one
+	// region at a time; the status needs all of them.
+	if check.Region != "" {
+		all, err := b.storer.QueryHealthChecks(ctx)
+		if _, ok := partialErrors(err); err != nil && !ok {
+			return nil, err
+		}
+		if matched := targetChecks(all, target); len(matched) > 0 {
+			checks = matched
+		}
+	}
+
+	b.markSynced()
+
+	return checks, nil
 }
 
-// QueryAlerts retrieves alert information.
-func (b *Business) QueryAlerts(ctx context.Context) (AlertSummary, error) {
-	return b.storer.QueryAlerts(ctx)
+// targetChecks returns the checks of the target, one per region for
+// targets probed from several regions.
+func targetChecks(checks []HealthCheck, target string) []HealthCheck {
+	var matched []HealthCheck
+	for _, c := range checks {
+		if c.Target == target {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// scopeCheck hides a check outside the caller's namespaces.
+func (b *Business) scopeCheck(ctx context.Context, check HealthCheck) (HealthCheck, error) {
+	if !tenant.Get(ctx).Allows(check.Namespace) {
+		return HealthCheck{}, fmt.Errorf("%w: %s", ErrNotFound, check.Target)
+	}
+
+	return check, nil
+}
+
+// QueryAlerts retrieves the alerts visible to the caller and matching the
+// filter. Alerts reported by several backends are listed once, and grouped
+// when the filter asks for it.
+func (b *Business) QueryAlerts(ctx context.Context, filter AlertFilter) (AlertSummary, error) {
+	var summary AlertSummary
+	var sourceErrs []SourceError
+	var seenAt time.Time
+
+	if snap := b.snapshot.Load(); snap != nil {
+		summary, sourceErrs, seenAt = snap.alerts, snap.alertErrs, snap.takenAt
+		b.cacheHits.Add(1)
+	} else {
+		b.cacheMisses.Add(1)
+
+		var err error
+		if summary, err = b.storer.QueryAlerts(ctx); err != nil {
+			var ok bool
+			if sourceErrs, ok = partialErrors(err); !ok {
+				return AlertSummary{}, err
+			}
+		}
+
+		b.markSynced()
+		seenAt = time.Now().UTC()
+	}
+
+	summary = scopeAlerts(tenant.Get(ctx), summary)
+	summary = dedupeAlerts(summary)
+	summary = filter.apply(summary)
+	b.applyAlertTeams(ctx, summary.Alerts)
+
+	if len(filter.GroupBy) > 0 {
+		summary.Groups = groupAlerts(summary.Alerts, filter.GroupBy, seenAt)
+	}
+
+	summary.Partial = len(sourceErrs) > 0
+	summary.Errors = sourceErrs
+
+	return summary, nil
+}
+
+// LastSync returns the time of the last successful store query. The zero
+// time is returned if no query has succeeded yet.
+func (b *Business) LastSync() time.Time {
+	n := b.lastSync.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
+
+// applyAlertTeams sets the team of each alert from its team label or, for
+// alerts labeled with a target, the target's metadata. The alerts must
+// not be shared with the snapshot.
+func (b *Business) applyAlertTeams(ctx context.Context, alerts []Alert) {
+	var tgts []targetbus.Target
+	if b.targetBus != nil && len(alerts) > 0 {
+		var err error
+		if tgts, err = b.targetBus.Query(tenant.Unscoped(ctx)); err != nil {
+			b.log.Warn(ctx, "healthbus", "status", "metadata lookup failed", "error", err)
+		}
+	}
+
+	teams := make(map[string]string, len(tgts))
+	for _, tgt := range tgts {
+		if tgt.Team != "" {
+			teams[tgt.Name] = tgt.Team
+		}
+	}
+
+	for i, a := range alerts {
+		switch {
+		case a.Labels["team"] != "":
+			alerts[i].Team = a.Labels["team"]
+		case teams[a.Labels["target"]] != "":
+			alerts[i].Team = teams[a.Labels["target"]]
+		default:
+			alerts[i].Team = teams[a.Labels["instance"]]
+		}
+	}
+}
+
+// applyMetadata merges the checks of targets probed from several regions,
+// attaches the target ownership metadata to each check and derives the
+// statuses that depend on it, including injected failures, maintenance
+// windows, paused targets and snoozes. Metadata is best effort; a lookup
+// failure leaves the checks without it. All targets are looked up, whatever
+// the caller's scope, since root causes may sit in another namespace.
+func (b *Business) applyMetadata(ctx context.Context, checks []HealthCheck) []HealthCheck {
+	checks = b.mergeRegions(checks)
+
+	var tgts []targetbus.Target
+	if b.targetBus != nil {
+		var err error
+		if tgts, err = b.targetBus.Query(tenant.Unscoped(ctx)); err != nil {
+			b.log.Warn(ctx, "healthbus", "status", "metadata lookup failed", "error", err)
+		}
+	}
+
+	byName := make(map[string]targetbus.Target, len(tgts))
+	upstreams := make(map[string][]string)
+	for _, tgt := range tgts {
+		byName[tgt.Name] = tgt
+		if len(tgt.DependsOn) > 0 {
+			upstreams[tgt.Name] = tgt.DependsOn
+		}
+	}
+
+	for i, check := range checks {
+		tgt, ok := byName[check.Target]
+		if !ok {
+			continue
+		}
+
+		checks[i].Team = tgt.Team
+		checks[i].RunbookURL = tgt.RunbookURL
+		checks[i].Severity = tgt.Severity
+		checks[i].Tags = tgt.Tags
+		checks[i].LatencySLO = tgt.LatencySLO
+		if tgt.Namespace != "" {
+			checks[i].Namespace = tgt.Namespace
+		}
+	}
+
+	b.applyInjections(checks, time.Now())
+	b.applyDegraded(checks)
+	b.applyRootCause(checks, upstreams)
+	b.applyMaintenance(ctx, checks, time.Now())
+	b.applyPause(checks, byName)
+	b.applySnooze(ctx, checks, time.Now())
+
+	return checks
+}
+
+// observe records the latest status of each check and queues a status
+// changed event for RunEvents when a target's status changes, so queries
+// never wait on the domains that react to it. Changes of a flapping target
+// are still reported, but marked suppressed so notifications are dampened.
+func (b *Business) observe(ctx context.Context, checks []HealthCheck) {
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, check := range checks {
+		prev, ok := b.statuses[check.Target]
+		if ok && prev == check.Status {
+			continue
+		}
+
+		b.statuses[check.Target] = check.Status
+		if ok {
+			b.recordChange(check.Target, now)
+		}
+
+		if score := b.flapScore(check.Target, now); score >= 1 {
+			check.FlapScore = score
+			check.Suppressed = true
+		}
+
+		b.enqueue(ctx, ActionStatusChangedData(check, prev))
+	}
+}
+
+func (b *Business) markSynced() {
+	b.lastSync.Store(time.Now().UnixNano())
+}
+
+// CheckDependencies checks connectivity to every configured backend
+// concurrently. The caller controls the overall deadline through ctx. Until
+// the startup gate opens the status is starting even when every backend is
+// reachable.
+func (b *Business) CheckDependencies(ctx context.Context) Readiness {
+	statuses := make([]DependencyStatus, len(b.deps))
+
+	var wg sync.WaitGroup
+	for i, dep := range b.deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			start := time.Now()
+			err := dep.Checker.Check(ctx)
+
+			ds := DependencyStatus{
+				Name:     dep.Name,
+				Status:   DependencyOK,
+				Required: dep.Required,
+				Latency:  time.Since(start).String(),
+			}
+			if err != nil {
+				ds.Status = DependencyUnreachable
+				ds.Error = err.Error()
+			}
+
+			statuses[i] = ds
+		}()
+	}
+	wg.Wait()
+
+	readiness := Readiness{
+		Status:       DependencyOK,
+		Dependencies: statuses,
+	}
+
+	for _, ds := range statuses {
+		if ds.Required && ds.Status != DependencyOK {
+			readiness.Status = DependencyUnreachable
+			b.log.Warn(ctx, "readiness", "dependency", ds.Name, "error", ds.Error)
+		}
+	}
+
+	if readiness.Status == DependencyOK && !b.started.Load() {
+		readiness.Status = DependencyStarting
+	}
+
+	return readiness
 }
 
 // =============================================================================
@@ -72,27 +519,107 @@ func (b *Business) QueryAlerts(ctx context.Context) (AlertSummary, error) {
 type Status string
 
 const (
-	StatusHealthy Status = "healthy"
-	StatusDown    Status = "down"
-	StatusUnknown Status = "unknown"
+	StatusHealthy  Status = "healthy"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+	StatusUnknown  Status = "unknown"
+	StatusFlapping Status = "flapping"
+
+	// StatusMaintenance replaces any status but healthy while a maintenance
+	// window covers the target.
+	StatusMaintenance Status = "maintenance"
+
+	// StatusPaused replaces any status while the target is paused.
+	StatusPaused Status = "paused"
 )
 
-// HealthCheck represents a single health check result.
+// HealthCheck represents a single health check result. The probe details
+// are only set when the backing store reports them.
 type HealthCheck struct {
-	Target      string    `json:"target"`
-	Status      Status    `json:"status"`
-	LastChecked time.Time `json:"last_checked"`
-	Probe       string    `json:"probe"`
-	Instance    string    `json:"instance,omitempty"`
+	Target           string          `json:"target"`
+	Status           Status          `json:"status"`
+	LastChecked      time.Time       `json:"last_checked"`
+	Probe            string          `json:"probe"`
+	Namespace        string          `json:"namespace,omitempty"`
+	Instance         string          `json:"instance,omitempty"`
+	DurationSeconds  float64         `json:"duration_seconds,omitempty"`
+	HTTPStatusCode   int             `json:"http_status_code,omitempty"`
+	SSLExpiryDays    float64         `json:"ssl_expiry_days,omitempty"`
+	DNSLookupSeconds float64         `json:"dns_lookup_seconds,omitempty"`
+	Steps            []StepTiming    `json:"steps,omitempty"`
+	TCPConnect       *ConnectLatency `json:"tcp_connect,omitempty"`
+	Suppressed       bool            `json:"suppressed,omitempty"`
+	RootCause        string          `json:"root_cause,omitempty"`
+	FlapScore        float64         `json:"flap_score,omitempty"`
+	LatencySLO       float64         `json:"latency_slo_seconds,omitempty"`
+	DegradedReason   string          `json:"degraded_reason,omitempty"`
+	Maintenance      string          `json:"maintenance,omitempty"`
+	Injected         bool            `json:"injected,omitempty"`
+	PausedUntil      *time.Time      `json:"paused_until,omitempty"`
+	SnoozedUntil     *time.Time      `json:"snoozed_until,omitempty"`
+	LastDeploy       *Deploy         `json:"last_deploy,omitempty"`
+
+	// Region is the vantage point a store's check was probed from. Checks
+	// of a target from several regions are merged into one, with the
+	// status per region in Regions.
+	Region  string            `json:"region,omitempty"`
+	Regions map[string]Status `json:"regions,omitempty"`
+
+	// Families holds the status over each address family of checks
+	// probed over several, e.g. {"ipv4": "healthy", "ipv6": "down"}.
+	Families map[string]Status `json:"families,omitempty"`
+
+	Team       string            `json:"team,omitempty"`
+	RunbookURL string            `json:"runbook_url,omitempty"`
+	Severity   string            `json:"severity,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+// ConnectLatency holds the TCP connect time percentiles of a target over
+// its recent connects.
+type ConnectLatency struct {
+	Samples    int     `json:"samples"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+}
+
+// Deploy describes the most recent rollout recorded for a target, so a
+// failure can be matched against it at a glance.
+type Deploy struct {
+	ID      string    `json:"id"`
+	At      time.Time `json:"at"`
+	Version string    `json:"version,omitempty"`
+	Text    string    `json:"text,omitempty"`
+	Author  string    `json:"author,omitempty"`
+	URL     string    `json:"url,omitempty"`
+}
+
+// StepTiming records the outcome of one step of a multi-step check.
+type StepTiming struct {
+	Name            string  `json:"name"`
+	Success         bool    `json:"success"`
+	StatusCode      int     `json:"status_code,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
 }
 
 // HealthSummary represents a summary of all health checks.
 type HealthSummary struct {
-	Total   int           `json:"total"`
-	Healthy int           `json:"healthy"`
-	Down    int           `json:"down"`
-	Unknown int           `json:"unknown"`
-	Checks  []HealthCheck `json:"checks"`
+	Total       int           `json:"total"`
+	Healthy     int           `json:"healthy"`
+	Degraded    int           `json:"degraded"`
+	Down        int           `json:"down"`
+	Unknown     int           `json:"unknown"`
+	Flapping    int           `json:"flapping"`
+	Maintenance int           `json:"maintenance"`
+	Paused      int           `json:"paused"`
+	Checks      []HealthCheck `json:"checks"`
+
+	// Partial is set when some store backends failed; Errors names them
+	// and Checks holds the results of the others.
+	Partial bool          `json:"partial,omitempty"`
+	Errors  []SourceError `json:"errors,omitempty"`
 }
 
 // Alert represents a single alert.
@@ -104,13 +631,66 @@ type Alert struct {
 	Annotations map[string]string `json:"annotations"`
 	ActiveAt    string            `json:"activeAt,omitempty"`
 	Value       string            `json:"value,omitempty"`
+	Severity    Severity          `json:"severity,omitempty"`
+
+	// Team owns the alert, taken from its team label or the metadata of
+	// the target it is about. OnCall names who is on call for the team,
+	// when an on-call schedule is configured.
+	Team   string `json:"team,omitempty"`
+	OnCall string `json:"on_call,omitempty"`
 }
 
 // AlertSummary represents a summary of all alerts.
 type AlertSummary struct {
-	Total   int     `json:"total"`
-	Firing  int     `json:"firing"`
-	Pending int     `json:"pending"`
-	Normal  int     `json:"normal"`
-	Alerts  []Alert `json:"alerts"`
+	Total   int          `json:"total"`
+	Firing  int          `json:"firing"`
+	Pending int          `json:"pending"`
+	Normal  int          `json:"normal"`
+	Alerts  []Alert      `json:"alerts"`
+	Groups  []AlertGroup `json:"groups,omitempty"`
+
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Info     int `json:"info"`
+
+	Partial bool          `json:"partial,omitempty"`
+	Errors  []SourceError `json:"errors,omitempty"`
+}
+
+// Set of dependency states reported by readiness checks.
+const (
+	DependencyOK          = "ok"
+	DependencyUnreachable = "unreachable"
+	DependencyStarting    = "starting"
+)
+
+// DependencyStatus represents the result of checking a single backend.
+type DependencyStatus struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Required bool   `json:"required"`
+	Latency  string `json:"latency"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Readiness represents the aggregate result of checking all backends.
+type Readiness struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// Ready reports whether all required dependencies are reachable.
+func (r Readiness) Ready() bool {
+	return r.Status == DependencyOK
+}
+
+// unreachable returns the names of the unreachable required dependencies.
+func (r Readiness) unreachable() []string {
+	var names []string
+	for _, ds := range r.Dependencies {
+		if ds.Required && ds.Status != DependencyOK {
+			names = append(names, ds.Name)
+		}
+	}
+	return names
 }
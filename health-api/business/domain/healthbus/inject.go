@@ -0,0 +1,154 @@
+package healthbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxInjection bounds how long a synthetic failure can last, so a
+// forgotten rehearsal heals on its own.
+const MaxInjection = time.Hour
+
+// ErrInvalidInjection is returned for an injection with an unsupported
+// status or duration.
+var ErrInvalidInjection = errors.New("invalid injection")
+
+// ErrNoInjection is returned when stopping a target without an active
+// injection.
+var ErrNoInjection = errors.New("no active injection")
+
+// Injection forces a target to report a status until it expires, to
+// rehearse alert routing and dashboards without breaking the service.
+type Injection struct {
+	Target    string    `json:"target"`
+	Status    Status    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	By        string    `json:"by,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Until     time.Time `json:"until"`
+}
+
+// NewInjection describes a synthetic failure to start.
+type NewInjection struct {
+	Target   string
+	Status   Status
+	Duration time.Duration
+	Reason   string
+	By       string
+}
+
+// Inject forces the target to report the status, down or degraded, for the
+// duration. The target must be visible to the caller. Starting an
+// injection for a target replaces its previous one.
+func (b *Business) Inject(ctx context.Context, ni NewInjection) (Injection, error) {
+	if ni.Status != StatusDown && ni.Status != StatusDegraded {
+		return Injection{}, fmt.Errorf("%w: status must be %s or %s", ErrInvalidInjection, StatusDown, StatusDegraded)
+	}
+
+	if ni.Duration <= 0 || ni.Duration > MaxInjection {
+		return Injection{}, fmt.Errorf("%w: duration must be positive and at most %s", ErrInvalidInjection, MaxInjection)
+	}
+
+	if _, err := b.QueryHealthCheckByTarget(ctx, ni.Target); err != nil {
+		return Injection{}, err
+	}
+
+	now := time.Now().UTC()
+	inj := Injection{
+		Target:    ni.Target,
+		Status:    ni.Status,
+		Reason:    ni.Reason,
+		By:        ni.By,
+		StartedAt: now,
+		Until:     now.Add(ni.Duration),
+	}
+
+	b.injectMu.Lock()
+	b.injections[inj.Target] = inj
+	b.injectMu.Unlock()
+
+	b.log.Info(ctx, "healthbus", "status", "failure injected", "target", inj.Target, "injected", inj.Status, "until", inj.Until, "by", inj.By)
+
+	return inj, nil
+}
+
+// StopInjection ends the target's injection before it expires.
+func (b *Business) StopInjection(ctx context.Context, target string) error {
+	b.injectMu.Lock()
+	_, ok := b.injections[target]
+	delete(b.injections, target)
+	b.injectMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("stop: target[%s]: %w", target, ErrNoInjection)
+	}
+
+	b.log.Info(ctx, "healthbus", "status", "injection stopped", "target", target)
+
+	return nil
+}
+
+// QueryInjections returns the active injections visible to the caller,
+// ordered by target.
+func (b *Business) QueryInjections(ctx context.Context) []Injection {
+	active := b.activeInjections(time.Now())
+
+	injections := make([]Injection, 0, len(active))
+	for _, inj := range active {
+		if _, err := b.QueryHealthCheckByTarget(ctx, inj.Target); err != nil {
+			continue
+		}
+		injections = append(injections, inj)
+	}
+
+	sort.Slice(injections, func(i, j int) bool { return injections[i].Target < injections[j].Target })
+
+	return injections
+}
+
+// activeInjections returns the injections active at now, dropping the
+// expired ones.
+func (b *Business) activeInjections(now time.Time) map[string]Injection {
+	b.injectMu.Lock()
+	defer b.injectMu.Unlock()
+
+	active := make(map[string]Injection, len(b.injections))
+	for target, inj := range b.injections {
+		if !now.Before(inj.Until) {
+			delete(b.injections, target)
+			continue
+		}
+		active[target] = inj
+	}
+
+	return active
+}
+
+// applyInjections replaces the status of injected targets. It runs before
+// the derived statuses, so an injected failure reaches dependents as root
+// cause like a real one.
+func (b *Business) applyInjections(checks []HealthCheck, now time.Time) {
+	active := b.activeInjections(now)
+	if len(active) == 0 {
+		return
+	}
+
+	for i, check := range checks {
+		inj, ok := active[check.Target]
+		if !ok {
+			continue
+		}
+
+		checks[i].Status = inj.Status
+		checks[i].Injected = true
+		if inj.Status == StatusDegraded {
+			checks[i].DegradedReason = "injected"
+			if inj.Reason != "" {
+				checks[i].DegradedReason += ": " + inj.Reason
+			}
+		}
+	}
+}
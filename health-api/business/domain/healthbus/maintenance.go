@@ -0,0 +1,41 @@
+package healthbus
+
+import (
+	"context"
+	"time"
+)
+
+// applyMaintenance marks checks covered by an active maintenance window,
+// expanding recurring windows at now. Checks that are not healthy take the
+// maintenance status so they neither count as down nor page anyone.
+// Windows are best effort; a lookup failure leaves the checks unchanged.
+func (b *Business) applyMaintenance(ctx context.Context, checks []HealthCheck, now time.Time) {
+	if b.maintenanceBus == nil {
+		return
+	}
+
+	windows, err := b.maintenanceBus.Active(ctx, now)
+	if err != nil {
+		b.log.Warn(ctx, "healthbus", "status", "maintenance lookup failed", "error", err)
+		return
+	}
+
+	if len(windows) == 0 {
+		return
+	}
+
+	for i, check := range checks {
+		for _, w := range windows {
+			if !w.Covers(check.Target) {
+				continue
+			}
+
+			checks[i].Maintenance = w.Name
+			checks[i].Suppressed = true
+			if check.Status != StatusHealthy {
+				checks[i].Status = StatusMaintenance
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package healthbus
+
+import (
+	"errors"
+	"strings"
+)
+
+// SourceError names a store backend that failed to answer a query.
+type SourceError struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// PartialError is returned by stores together with the results of the
+// backends that answered, when other backends failed. Callers that can
+// serve partial data use the results and report the errors; all others
+// treat it as any other error.
+type PartialError struct {
+	Errors []SourceError
+}
+
+// Error implements the error interface.
+func (e *PartialError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, se := range e.Errors {
+		msgs[i] = se.Source + ": " + se.Error
+	}
+	return "partial results: " + strings.Join(msgs, "; ")
+}
+
+// partialErrors returns the source errors of a partial result. It reports
+// false when err is nil or not a PartialError.
+func partialErrors(err error) ([]SourceError, bool) {
+	var pe *PartialError
+	if !errors.As(err, &pe) {
+		return nil, false
+	}
+	return pe.Errors, true
+}
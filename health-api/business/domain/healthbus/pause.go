@@ -0,0 +1,19 @@
+package healthbus
+
+import "health-api/business/domain/targetbus"
+
+// applyPause marks the checks of paused targets. A paused target isn't
+// probed, so whatever status the store still reports is replaced by the
+// paused status, and it mustn't page anyone.
+func (b *Business) applyPause(checks []HealthCheck, byName map[string]targetbus.Target) {
+	for i, check := range checks {
+		tgt, ok := byName[check.Target]
+		if !ok || !tgt.Paused {
+			continue
+		}
+
+		checks[i].Status = StatusPaused
+		checks[i].Suppressed = true
+		checks[i].PausedUntil = tgt.PausedUntil
+	}
+}
@@ -0,0 +1,55 @@
+package healthbus_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/memorystore"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+func Test_Recheck(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+	store := memorystore.NewStore()
+	store.SetChecks(healthbus.HealthCheck{Target: "https://billing.example.com", Namespace: "billing", Status: healthbus.StatusDown, LastChecked: time.Now()})
+
+	b := healthbus.NewBusiness(log, delegate.New(log), store, nil, nil, nil, healthbus.Config{})
+	if err := b.Sync(context.Background()); err != nil {
+		t.Fatalf("Should be able to sync: %s", err)
+	}
+
+	store.SetChecks(healthbus.HealthCheck{Target: "https://billing.example.com", Namespace: "billing", Status: healthbus.StatusHealthy, LastChecked: time.Now()})
+
+	// A caller outside the namespace is turned away before the snapshot is
+	// refreshed.
+	shop := tenant.Set(context.Background(), tenant.Scope{Name: "shop", Namespaces: []string{"shop"}})
+	if _, err := b.Recheck(shop, "https://billing.example.com"); !errors.Is(err, healthbus.ErrNotFound) {
+		t.Fatalf("Should hide the target from another tenant, got %v", err)
+	}
+
+	check, err := b.QueryHealthCheckByTarget(context.Background(), "https://billing.example.com")
+	if err != nil || check.Status != healthbus.StatusDown {
+		t.Fatalf("Should keep the synced check, got %s %v", check.Status, err)
+	}
+
+	check, err = b.Recheck(context.Background(), "https://billing.example.com")
+	if err != nil || check.Status != healthbus.StatusHealthy {
+		t.Fatalf("Should return the fresh check, got %s %v", check.Status, err)
+	}
+
+	if _, err := b.Recheck(context.Background(), "https://missing.example.com"); !errors.Is(err, healthbus.ErrNotFound) {
+		t.Errorf("Should report an unknown target as not found, got %v", err)
+	}
+
+	store.SetError(errors.New("backend down"))
+	if _, err := b.Recheck(context.Background(), "https://billing.example.com"); err == nil || errors.Is(err, healthbus.ErrNotFound) {
+		t.Errorf("Should report a failing backend as such, got %v", err)
+	}
+}
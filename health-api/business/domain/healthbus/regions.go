@@ -0,0 +1,105 @@
+package healthbus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RegionConfig holds the policy for targets probed from several regions.
+type RegionConfig struct {
+	// DownQuorum is how many regions must fail for the target to be down.
+	// Fewer failing regions leave it degraded, unless every region
+	// probing it fails. Zero or one makes any failing region take it down.
+	DownQuorum int
+}
+
+// statusRank orders the statuses a region can report from best to worst.
+var statusRank = map[Status]int{
+	StatusHealthy:  0,
+	StatusUnknown:  1,
+	StatusDegraded: 2,
+	StatusDown:     3,
+}
+
+// mergeRegions folds the checks of a target reported from several regions
+// into one check carrying the status per region. A region takes the worst
+// status of the agents probing from it, and the target's status follows
+// the region policy. Checks without a region are left alone.
+func (b *Business) mergeRegions(checks []HealthCheck) []HealthCheck {
+	byTarget := make(map[string][]HealthCheck)
+	merged := make([]HealthCheck, 0, len(checks))
+
+	for _, c := range checks {
+		if c.Region == "" {
+			merged = append(merged, c)
+			continue
+		}
+
+		if _, ok := byTarget[c.Target]; !ok {
+			// Keep the target's place; filled in below.
+			merged = append(merged, HealthCheck{Target: c.Target, Region: c.Region})
+		}
+		byTarget[c.Target] = append(byTarget[c.Target], c)
+	}
+
+	for i, c := range merged {
+		if c.Region == "" {
+			continue
+		}
+		merged[i] = b.mergeTarget(byTarget[c.Target])
+	}
+
+	return merged
+}
+
+func (b *Business) mergeTarget(checks []HealthCheck) HealthCheck {
+	slices.SortFunc(checks, func(a, b HealthCheck) int {
+		return strings.Compare(a.Region, b.Region)
+	})
+
+	check := checks[0]
+	check.Region = ""
+	check.Instance = ""
+	check.DegradedReason = ""
+	check.Regions = make(map[string]Status)
+
+	for _, c := range checks {
+		if cur, ok := check.Regions[c.Region]; !ok || statusRank[c.Status] > statusRank[cur] {
+			check.Regions[c.Region] = c.Status
+		}
+		if c.LastChecked.After(check.LastChecked) {
+			check.LastChecked = c.LastChecked
+		}
+		check.DurationSeconds = max(check.DurationSeconds, c.DurationSeconds)
+	}
+
+	var down []string
+	for region, status := range check.Regions {
+		if status == StatusDown {
+			down = append(down, region)
+		}
+	}
+	slices.Sort(down)
+
+	quorum := max(b.cfg.Regions.DownQuorum, 1)
+
+	switch {
+	case len(down) >= quorum || (len(down) > 0 && len(down) == len(check.Regions)):
+		check.Status = StatusDown
+
+	case len(down) > 0:
+		check.Status = StatusDegraded
+		check.DegradedReason = fmt.Sprintf("%d of %d regions down: %s", len(down), len(check.Regions), strings.Join(down, ", "))
+
+	default:
+		check.Status = StatusHealthy
+		for _, status := range check.Regions {
+			if statusRank[status] > statusRank[check.Status] {
+				check.Status = status
+			}
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,53 @@
+package healthbus
+
+// applyRootCause suppresses notifications for down checks whose upstream
+// dependency is also down, recording the furthest upstream down target as the
+// root cause. Statuses of targets not among checks come from the last
+// observation, so a single check can still be attributed.
+func (b *Business) applyRootCause(checks []HealthCheck, upstreams map[string][]string) {
+	if len(upstreams) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	statuses := make(map[string]Status, len(b.statuses)+len(checks))
+	for target, status := range b.statuses {
+		statuses[target] = status
+	}
+	b.mu.Unlock()
+
+	for _, check := range checks {
+		statuses[check.Target] = check.Status
+	}
+
+	for i, check := range checks {
+		if check.Status != StatusDown {
+			continue
+		}
+
+		seen := map[string]bool{check.Target: true}
+		if root := rootCause(check.Target, upstreams, statuses, seen); root != "" {
+			checks[i].Suppressed = true
+			checks[i].RootCause = root
+		}
+	}
+}
+
+// rootCause walks the dependency graph upwards from target and returns the
+// furthest down upstream target, or "" when every upstream is up. Cycles are
+// broken by seen.
+func rootCause(target string, upstreams map[string][]string, statuses map[string]Status, seen map[string]bool) string {
+	for _, up := range upstreams[target] {
+		if seen[up] || statuses[up] != StatusDown {
+			continue
+		}
+		seen[up] = true
+
+		if root := rootCause(up, upstreams, statuses, seen); root != "" {
+			return root
+		}
+		return up
+	}
+
+	return ""
+}
@@ -0,0 +1,54 @@
+package healthbus
+
+import "strings"
+
+// Severity represents how urgent an alert is.
+type Severity string
+
+// Set of alert severities.
+const (
+	SeverityCritical Severity = "critical"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+// severities maps the severity label values in common use to a Severity.
+var severities = map[string]Severity{
+	"critical":      SeverityCritical,
+	"crit":          SeverityCritical,
+	"page":          SeverityCritical,
+	"high":          SeverityCritical,
+	"emergency":     SeverityCritical,
+	"p1":            SeverityCritical,
+	"warning":       SeverityWarning,
+	"warn":          SeverityWarning,
+	"medium":        SeverityWarning,
+	"major":         SeverityWarning,
+	"p2":            SeverityWarning,
+	"p3":            SeverityWarning,
+	"info":          SeverityInfo,
+	"informational": SeverityInfo,
+	"low":           SeverityInfo,
+	"minor":         SeverityInfo,
+	"none":          SeverityInfo,
+	"p4":            SeverityInfo,
+	"p5":            SeverityInfo,
+}
+
+// ParseSeverity maps a severity label value to a Severity, ignoring case.
+// It reports false for values it does not know.
+func ParseSeverity(s string) (Severity, bool) {
+	sev, ok := severities[strings.ToLower(strings.TrimSpace(s))]
+	return sev, ok
+}
+
+// applySeverity sets the severity of each alert from its severity label.
+// The alerts are copied, as they may be shared with the snapshot.
+func applySeverity(alerts []Alert) []Alert {
+	out := make([]Alert, len(alerts))
+	for i, a := range alerts {
+		a.Severity, _ = ParseSeverity(a.Labels["severity"])
+		out[i] = a
+	}
+	return out
+}
@@ -0,0 +1,158 @@
+package healthbus
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// snapshot holds the health data fetched by the last successful sync.
+type snapshot struct {
+	checks    []HealthCheck
+	checkErrs []SourceError
+	alerts    AlertSummary
+	alertErrs []SourceError
+	takenAt   time.Time
+}
+
+// CacheStats describes how well the snapshot serves queries. TakenAt and
+// Age are zero while no snapshot exists.
+type CacheStats struct {
+	Hits    int64
+	Misses  int64
+	TakenAt time.Time
+	Age     time.Duration
+	Checks  int
+	Alerts  int
+}
+
+// Sync refreshes the in-memory snapshot from the store and notifies
+// interested domains of status changes. Once a snapshot exists queries are
+// served from it instead of the store. Partial results are kept, along
+// with the errors of the backends that failed.
+func (b *Business) Sync(ctx context.Context) error {
+	checks, err := b.storer.QueryHealthChecks(ctx)
+	checkErrs, partial := partialErrors(err)
+	if err != nil && !partial {
+		return fmt.Errorf("query health checks: %w", err)
+	}
+
+	alerts, err := b.storer.QueryAlerts(ctx)
+	alertErrs, partial := partialErrors(err)
+	if err != nil && !partial {
+		return fmt.Errorf("query alerts: %w", err)
+	}
+
+	b.snapshot.Store(&snapshot{
+		checks:    checks,
+		checkErrs: checkErrs,
+		alerts:    alerts,
+		alertErrs: alertErrs,
+		takenAt:   time.Now().UTC(),
+	})
+	b.markSynced()
+
+	b.observe(ctx, b.applyMetadata(ctx, slices.Clone(checks)))
+
+	return nil
+}
+
+// RunPoller syncs immediately and then every interval until ctx is
+// canceled. Each sync is bounded by the interval.
+func (b *Business) RunPoller(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b.syncOnce(ctx, interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Business) syncOnce(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := b.Sync(ctx); err != nil {
+		b.log.Error(ctx, "healthbus", "status", "sync failed", "error", err)
+	}
+}
+
+// replaceTarget swaps the checks of target in the current snapshot for
+// checks. A sync that lands meanwhile wins, as its data is as fresh.
+func (b *Business) replaceTarget(target string, checks []HealthCheck) {
+	snap := b.snapshot.Load()
+	if snap == nil {
+		return
+	}
+
+	next := *snap
+	next.checks = make([]HealthCheck, 0, len(snap.checks)+len(checks))
+
+	replaced := false
+	for _, c := range snap.checks {
+		if c.Target != target {
+			next.checks = append(next.checks, c)
+			continue
+		}
+		if !replaced {
+			next.checks = append(next.checks, checks...)
+			replaced = true
+		}
+	}
+	if !replaced {
+		next.checks = append(next.checks, checks...)
+	}
+
+	b.snapshot.CompareAndSwap(snap, &next)
+}
+
+// SnapshotTime returns when the current snapshot was taken. It reports
+// false when no snapshot exists, i.e. queries go to the store directly.
+func (b *Business) SnapshotTime() (time.Time, bool) {
+	snap := b.snapshot.Load()
+	if snap == nil {
+		return time.Time{}, false
+	}
+	return snap.takenAt, true
+}
+
+// CacheStats reports how many queries the snapshot answered and how many
+// went to the store, along with the age and size of the current snapshot.
+func (b *Business) CacheStats() CacheStats {
+	stats := CacheStats{
+		Hits:   b.cacheHits.Load(),
+		Misses: b.cacheMisses.Load(),
+	}
+
+	if snap := b.snapshot.Load(); snap != nil {
+		stats.TakenAt = snap.takenAt
+		stats.Age = time.Since(snap.takenAt)
+		stats.Checks = len(snap.checks)
+		stats.Alerts = len(snap.alerts.Alerts)
+	}
+
+	return stats
+}
+
+// QuerySnapshot returns the checks of the current snapshot, unscoped and
+// with metadata and flapping applied as for queries. Unlike
+// QueryHealthChecks it never reaches the store; it reports false when no
+// snapshot exists.
+func (b *Business) QuerySnapshot(ctx context.Context) ([]HealthCheck, bool) {
+	snap := b.snapshot.Load()
+	if snap == nil {
+		return nil, false
+	}
+
+	checks := b.applyMetadata(ctx, slices.Clone(snap.checks))
+	b.applyFlapping(checks)
+
+	return checks, true
+}
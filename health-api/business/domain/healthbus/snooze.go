@@ -0,0 +1,32 @@
+package healthbus
+
+import (
+	"context"
+	"time"
+)
+
+// applySnooze marks the checks of targets snoozed at now. Unlike a pause
+// or a maintenance window a snooze keeps the status as reported; it only
+// stops the target from paging anyone. Snoozes are best effort; a lookup
+// failure leaves the checks unchanged.
+func (b *Business) applySnooze(ctx context.Context, checks []HealthCheck, now time.Time) {
+	if b.snoozeBus == nil {
+		return
+	}
+
+	snoozes, err := b.snoozeBus.Active(ctx, now)
+	if err != nil {
+		b.log.Warn(ctx, "healthbus", "status", "snooze lookup failed", "error", err)
+		return
+	}
+
+	for i, check := range checks {
+		s, ok := snoozes[check.Target]
+		if !ok {
+			continue
+		}
+
+		checks[i].Suppressed = true
+		checks[i].SnoozedUntil = &s.Until
+	}
+}
@@ -0,0 +1,93 @@
+package healthbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StartupConfig controls the startup gate that holds readiness back until
+// the required dependencies are reachable. A zero Timeout without FailFast
+// disables the gate.
+type StartupConfig struct {
+	// Timeout bounds how long to wait for the dependencies.
+	Timeout time.Duration
+
+	// Backoff is the delay after the first failed attempt. It doubles after
+	// every further attempt up to MaxBackoff, which also bounds a single
+	// attempt.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// FailFast checks the dependencies once instead of waiting.
+	FailFast bool
+}
+
+// enabled reports whether readiness is gated.
+func (c StartupConfig) enabled() bool {
+	return c.Timeout > 0 || c.FailFast
+}
+
+// WaitForDependencies blocks until every required dependency is reachable,
+// retrying with exponential backoff, and then opens readiness. It returns an
+// error when the timeout passes first or, with FailFast, when the first
+// attempt fails.
+func (b *Business) WaitForDependencies(ctx context.Context) error {
+	cfg := b.cfg.Startup
+	if !cfg.enabled() {
+		return nil
+	}
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	backoff := cfg.Backoff
+
+	for attempt := 1; ; attempt++ {
+		// The status is starting until the gate opens, so only the
+		// dependencies themselves decide.
+		unreachable := b.checkAttempt(ctx, cfg.MaxBackoff).unreachable()
+		if len(unreachable) == 0 {
+			b.started.Store(true)
+			b.log.Info(ctx, "startup", "status", "dependencies reachable", "attempts", attempt, "waited", time.Since(start).Round(time.Millisecond).String())
+			return nil
+		}
+
+		if cfg.FailFast {
+			return fmt.Errorf("dependencies unreachable: %s", strings.Join(unreachable, ", "))
+		}
+
+		b.log.Info(ctx, "startup", "status", "waiting for dependencies", "attempt", attempt, "unreachable", unreachable, "retry_in", backoff.String())
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("dependencies unreachable after %s: %s", cfg.Timeout, strings.Join(unreachable, ", "))
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; cfg.MaxBackoff > 0 && backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}
+
+// Started reports whether the startup gate has opened.
+func (b *Business) Started() bool {
+	return b.started.Load()
+}
+
+// checkAttempt checks the dependencies once, bounded by timeout when set.
+func (b *Business) checkAttempt(ctx context.Context, timeout time.Duration) Readiness {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return b.CheckDependencies(ctx)
+}
@@ -0,0 +1,195 @@
+// Package clusterstore implements the health check store from the state of
+// the cluster itself: the Ready and pressure conditions of every node and
+// the pods the scheduler can't place. Nodes and pending pods are watched by
+// informers, so the health, alert and by-target queries of a poll read the
+// same cache instead of listing them from the API server.
+package clusterstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// probe is the probe name reported for cluster checks.
+const probe = "cluster"
+
+// Store implements healthbus.Storer using the Kubernetes API.
+type Store struct {
+	log          *logger.Logger
+	client       *kube.Client
+	pendingGrace time.Duration
+	cache        *kube.Cache
+	nodes        corelisters.NodeLister
+	pods         corelisters.PodLister
+}
+
+// NewStore creates a store reporting the nodes and the pods pending for
+// longer than pendingGrace. Run must be running for queries to be
+// answered.
+func NewStore(log *logger.Logger, client *kube.Client, pendingGrace time.Duration) *Store {
+	nodes := client.Factories(nil)[0].Core().V1().Nodes()
+
+	// Only pending pods are watched; the running ones would be most of the
+	// cluster's pods and are of no interest here.
+	pending := informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+		o.FieldSelector = "status.phase=Pending"
+	})
+	pods := client.Factories(nil, pending)[0].Core().V1().Pods()
+
+	s := Store{
+		log:          log,
+		client:       client,
+		pendingGrace: pendingGrace,
+		cache:        kube.NewCache(),
+		nodes:        nodes.Lister(),
+		pods:         pods.Lister(),
+	}
+
+	s.cache.Add(nodes.Informer())
+	s.cache.Add(pods.Informer())
+
+	return &s
+}
+
+// Run watches the nodes and pending pods until ctx is canceled.
+func (s *Store) Run(ctx context.Context) {
+	s.cache.Run(ctx)
+}
+
+// QueryHealthChecks reports every node and the pending pods.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if err := s.cache.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	now := time.Now()
+
+	nodes, err := s.nodes.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	pending, err := s.pendingCheck(now)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cache is unordered; keep the order of a list call.
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	checks := make([]healthbus.HealthCheck, 0, len(nodes)+1)
+	for _, n := range nodes {
+		checks = append(checks, nodeCheck(n, now))
+	}
+	checks = append(checks, pending)
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget reports a single node, named as "node/<name>",
+// or the pending pods.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	name, isNode := strings.CutPrefix(target, nodePrefix)
+	if target != targetPendingPods && !isNode {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if err := s.cache.Wait(ctx); err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	now := time.Now()
+
+	if !isNode {
+		return s.pendingCheck(now)
+	}
+
+	n, err := s.nodes.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+		}
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	return nodeCheck(n, now), nil
+}
+
+// pendingCheck reports the cached pending pods.
+func (s *Store) pendingCheck(now time.Time) (healthbus.HealthCheck, error) {
+	pods, err := s.pods.List(labels.Everything())
+	if err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("listing pending pods: %w", err)
+	}
+
+	return pendingCheck(pods, s.pendingGrace, now), nil
+}
+
+// QueryAlerts reports a firing alert for every node that is not healthy,
+// critical when not Ready and warning otherwise, and a warning while pods
+// are pending.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	checks, err := s.QueryHealthChecks(ctx)
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, check := range checks {
+		if check.Status == healthbus.StatusHealthy {
+			continue
+		}
+
+		name := "NodeNotReady"
+		switch {
+		case check.Target == targetPendingPods:
+			name = "PodsPending"
+		case check.Status == healthbus.StatusDegraded:
+			name = "NodeUnderPressure"
+		}
+
+		severity := "warning"
+		if check.Status == healthbus.StatusDown {
+			severity = "critical"
+		}
+
+		summary.Alerts = append(summary.Alerts, healthbus.Alert{
+			UID:   check.Target,
+			Title: name,
+			State: "firing",
+			Labels: map[string]string{
+				"alertname": name,
+				"instance":  check.Target,
+				"severity":  severity,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is %s", check.Target, check.Status),
+			},
+		})
+		summary.Total++
+		summary.Firing++
+	}
+
+	return summary, nil
+}
+
+// Check verifies that the Kubernetes API is reachable.
+func (s *Store) Check(ctx context.Context) error {
+	return s.client.Check(ctx)
+}
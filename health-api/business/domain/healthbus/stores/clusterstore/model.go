@@ -0,0 +1,127 @@
+package clusterstore
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Set of targets the store reports besides the nodes.
+const (
+	targetPendingPods = "cluster/pods/pending"
+	nodePrefix        = "node/"
+)
+
+// maxPendingSteps bounds the pending pods listed as steps.
+const maxPendingSteps = 20
+
+// pressureConditions are the node conditions that report a resource
+// running low when true.
+var pressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// =============================================================================
+
+// nodeCheck maps a node's conditions to a health check: down when it is
+// not Ready, degraded when it reports pressure or is cordoned.
+func nodeCheck(n *corev1.Node, now time.Time) healthbus.HealthCheck {
+	check := healthbus.HealthCheck{
+		Target:      nodePrefix + n.Name,
+		Status:      healthbus.StatusUnknown,
+		LastChecked: now,
+		Probe:       probe,
+		Instance:    n.Name,
+	}
+
+	var pressure []string
+	for _, c := range n.Status.Conditions {
+		switch {
+		case c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue:
+			check.Status = healthbus.StatusHealthy
+		case c.Type == corev1.NodeReady:
+			check.Status = healthbus.StatusDown
+			check.Steps = append(check.Steps, conditionStep(c))
+		case c.Status == corev1.ConditionTrue && slices.Contains(pressureConditions, c.Type):
+			pressure = append(pressure, string(c.Type))
+			check.Steps = append(check.Steps, conditionStep(c))
+		}
+	}
+
+	if n.Spec.Unschedulable {
+		pressure = append(pressure, "cordoned")
+	}
+
+	if check.Status == healthbus.StatusHealthy && len(pressure) > 0 {
+		check.Status = healthbus.StatusDegraded
+		check.DegradedReason = strings.Join(pressure, ", ")
+	}
+
+	return check
+}
+
+// pendingCheck reports the pods pending for longer than grace: degraded
+// when any is, with the oldest listed as steps and the scheduler's reason
+// they can't be placed.
+func pendingCheck(pods []*corev1.Pod, grace time.Duration, now time.Time) healthbus.HealthCheck {
+	check := healthbus.HealthCheck{
+		Target:      targetPendingPods,
+		Status:      healthbus.StatusHealthy,
+		LastChecked: now,
+		Probe:       probe,
+	}
+
+	var pending []*corev1.Pod
+	for _, p := range pods {
+		if now.Sub(p.CreationTimestamp.Time) > grace {
+			pending = append(pending, p)
+		}
+	}
+
+	if len(pending) == 0 {
+		return check
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreationTimestamp.Before(&pending[j].CreationTimestamp)
+	})
+
+	check.Status = healthbus.StatusDegraded
+	check.DegradedReason = fmt.Sprintf("%d pods pending for over %s", len(pending), grace)
+
+	for i, p := range pending {
+		if i == maxPendingSteps {
+			break
+		}
+
+		step := healthbus.StepTiming{
+			Name:  p.Namespace + "/" + p.Name,
+			Error: "pending",
+		}
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status != corev1.ConditionTrue {
+				step.Error = strings.TrimSpace(c.Reason + " " + c.Message)
+			}
+		}
+		check.Steps = append(check.Steps, step)
+	}
+
+	return check
+}
+
+// conditionStep reports a node condition as a failed step.
+func conditionStep(c corev1.NodeCondition) healthbus.StepTiming {
+	return healthbus.StepTiming{
+		Name:  string(c.Type),
+		Error: strings.TrimSpace(c.Reason + " " + c.Message),
+	}
+}
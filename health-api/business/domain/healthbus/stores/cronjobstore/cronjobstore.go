@@ -0,0 +1,170 @@
+// Package cronjobstore implements the health check store from the
+// schedules of CronJobs, reporting jobs that stopped succeeding. A CronJob
+// whose runs fail, or which is never started, raises no error anywhere
+// else; it only stops updating its last successful time.
+package cronjobstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+)
+
+// probe is the probe name reported for CronJob checks.
+const probe = "cronjob"
+
+// Store implements healthbus.Storer using the Kubernetes API. CronJobs are
+// watched by informers, so queries read them from the cache instead of
+// listing them from the API server.
+type Store struct {
+	log        *logger.Logger
+	client     *kube.Client
+	namespaces []string
+	down       int
+	cache      *kube.Cache
+
+	// listers holds a lister per namespace, or one for the cluster.
+	listers []batchlisters.CronJobLister
+}
+
+// NewStore creates a store reporting the CronJobs in the namespaces, or in
+// every namespace when none are given. A CronJob is down once it missed
+// down schedules in a row. Run must be running for queries to be answered.
+func NewStore(log *logger.Logger, client *kube.Client, namespaces []string, down int) *Store {
+	s := Store{
+		log:        log,
+		client:     client,
+		namespaces: namespaces,
+		down:       max(down, 1),
+		cache:      kube.NewCache(),
+	}
+
+	for _, f := range client.Factories(namespaces) {
+		cronJobs := f.Batch().V1().CronJobs()
+		s.cache.Add(cronJobs.Informer())
+		s.listers = append(s.listers, cronJobs.Lister())
+	}
+
+	return &s
+}
+
+// Run watches the CronJobs until ctx is canceled.
+func (s *Store) Run(ctx context.Context) {
+	s.cache.Run(ctx)
+}
+
+// QueryHealthChecks reports every CronJob.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if err := s.cache.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("listing cronjobs: %w", err)
+	}
+
+	now := time.Now()
+	var checks []healthbus.HealthCheck
+
+	for _, l := range s.listers {
+		cronJobs, err := l.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("listing cronjobs: %w", err)
+		}
+
+		for _, cj := range cronJobs {
+			checks = append(checks, toHealthCheck(cj, s.down, now))
+		}
+	}
+
+	// The cache is unordered; keep the order of a list call.
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Target < checks[j].Target })
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget reports a single CronJob, named as
+// "cronjob/<namespace>/<name>".
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	ns, name, err := parseTarget(target)
+	if err != nil {
+		return healthbus.HealthCheck{}, err
+	}
+
+	i := 0
+	if len(s.namespaces) > 0 {
+		i = slices.Index(s.namespaces, ns)
+	}
+	if i < 0 {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if err := s.cache.Wait(ctx); err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	cj, err := s.listers[i].CronJobs(ns).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+		}
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	return toHealthCheck(cj, s.down, time.Now()), nil
+}
+
+// QueryAlerts reports a firing CronJobMissedSchedule alert for every
+// CronJob that missed a schedule: critical when down, warning otherwise.
+// Suspended CronJobs and unreadable schedules raise none.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	checks, err := s.QueryHealthChecks(ctx)
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, check := range checks {
+		if check.Status != healthbus.StatusDown && (check.Status != healthbus.StatusDegraded || check.DegradedReason == "suspended") {
+			continue
+		}
+
+		severity := "warning"
+		if check.Status == healthbus.StatusDown {
+			severity = "critical"
+		}
+
+		summary.Alerts = append(summary.Alerts, healthbus.Alert{
+			UID:   check.Target,
+			Title: "CronJobMissedSchedule",
+			State: "firing",
+			Labels: map[string]string{
+				"alertname": "CronJobMissedSchedule",
+				"instance":  check.Target,
+				"namespace": check.Namespace,
+				"severity":  severity,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is %s", check.Target, check.Status),
+			},
+		})
+		summary.Total++
+		summary.Firing++
+	}
+
+	return summary, nil
+}
+
+// Check verifies that the Kubernetes API is reachable.
+func (s *Store) Check(ctx context.Context) error {
+	return s.client.Check(ctx)
+}
@@ -0,0 +1,140 @@
+package cronjobstore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/cron"
+
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// maxMissed bounds the missed schedules counted, so a job running every
+// minute that stopped weeks ago is not walked minute by minute.
+const maxMissed = 100
+
+// =============================================================================
+
+// targetName returns the target a CronJob is reported as, for example
+// "cronjob/shop/nightly-export".
+func targetName(cj *batchv1.CronJob) string {
+	return "cronjob/" + cj.Namespace + "/" + cj.Name
+}
+
+// parseTarget splits a target name into its namespace and name.
+func parseTarget(target string) (namespace, name string, err error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 3 || parts[0] != "cronjob" || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return parts[1], parts[2], nil
+}
+
+// schedule parses the CronJob's schedule in its time zone, given by
+// spec.timeZone or a CRON_TZ= or TZ= prefix, defaulting to UTC.
+func schedule(cj *batchv1.CronJob) (cron.Schedule, *time.Location, error) {
+	expr, zone := cj.Spec.Schedule, "UTC"
+	if cj.Spec.TimeZone != nil {
+		zone = *cj.Spec.TimeZone
+	}
+
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if rest, ok := strings.CutPrefix(expr, prefix); ok {
+			zone, expr, _ = strings.Cut(rest, " ")
+		}
+	}
+
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return cron.Schedule{}, nil, fmt.Errorf("time zone %q: %w", zone, err)
+	}
+
+	s, err := cron.Parse(strings.TrimSpace(expr))
+	if err != nil {
+		return cron.Schedule{}, nil, err
+	}
+
+	return s, loc, nil
+}
+
+// missed counts the activations since the last success, or since the
+// CronJob was created, that passed without one. An activation counts once
+// the next one has come, which gives every run until then to succeed.
+func missed(s cron.Schedule, loc *time.Location, since time.Time, now time.Time) int {
+	var n int
+
+	at := s.Next(since.In(loc))
+	for n < maxMissed && !at.IsZero() {
+		next := s.Next(at)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		n++
+		at = next
+	}
+
+	return n
+}
+
+// toHealthCheck rates a CronJob by the schedules it missed: healthy with
+// none, degraded with fewer than down, and down from there. Suspended
+// CronJobs are degraded; their schedules are not counted.
+func toHealthCheck(cj *batchv1.CronJob, down int, now time.Time) healthbus.HealthCheck {
+	check := healthbus.HealthCheck{
+		Target:      targetName(cj),
+		Status:      healthbus.StatusHealthy,
+		LastChecked: now,
+		Probe:       probe,
+		Namespace:   cj.Namespace,
+		Instance:    cj.Name,
+	}
+
+	if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+		check.Status = healthbus.StatusDegraded
+		check.DegradedReason = "suspended"
+		return check
+	}
+
+	s, loc, err := schedule(cj)
+	if err != nil {
+		check.Status = healthbus.StatusUnknown
+		check.Steps = []healthbus.StepTiming{{Name: "schedule", Error: err.Error()}}
+		return check
+	}
+
+	since, last := cj.CreationTimestamp.Time, "never succeeded"
+	if t := cj.Status.LastSuccessfulTime; t != nil {
+		since, last = t.Time, "last success "+t.UTC().Format(time.RFC3339)
+	}
+
+	n := missed(s, loc, since, now)
+	switch {
+	case n == 0:
+		return check
+	case n >= down:
+		check.Status = healthbus.StatusDown
+	default:
+		check.Status = healthbus.StatusDegraded
+	}
+
+	var reason string
+	switch n {
+	case 1:
+		reason = "missed 1 schedule, " + last
+	case maxMissed:
+		reason = fmt.Sprintf("missed %d+ schedules, %s", n, last)
+	default:
+		reason = fmt.Sprintf("missed %d schedules, %s", n, last)
+	}
+
+	if check.Status == healthbus.StatusDegraded {
+		check.DegradedReason = reason
+	} else {
+		check.Steps = []healthbus.StepTiming{{Name: "schedule", Error: reason}}
+	}
+
+	return check
+}
@@ -0,0 +1,186 @@
+// Package gitopsstore implements the health check store from the sync and
+// health status of Argo CD Applications and Flux Kustomizations, so drift
+// from Git and failed rollouts appear in the same summary as probed
+// targets.
+package gitopsstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// probe is the probe name reported for GitOps checks.
+const probe = "gitops"
+
+// Store implements healthbus.Storer using the Kubernetes API. The objects
+// are watched by informers, so queries read them from the cache instead of
+// listing them from the API server.
+type Store struct {
+	log        *logger.Logger
+	client     *kube.Client
+	kinds      []string
+	namespaces []string
+	cache      *kube.Cache
+
+	// listers holds, per kind, a lister per namespace or one for the
+	// cluster.
+	listers map[string][]cache.GenericLister
+}
+
+// NewStore creates a store reporting the objects of the tools, ToolArgoCD
+// or ToolFlux, in the namespaces, or in every namespace when none are
+// given. Run must be running for queries to be answered.
+func NewStore(log *logger.Logger, client *kube.Client, tools []string, namespaces []string) (*Store, error) {
+	s := Store{
+		log:        log,
+		client:     client,
+		namespaces: namespaces,
+		cache:      kube.NewCache(),
+		listers:    make(map[string][]cache.GenericLister),
+	}
+
+	for _, tool := range tools {
+		kind, ok := kinds[tool]
+		if !ok {
+			return nil, fmt.Errorf("unknown gitops tool %q, want %s or %s", tool, ToolArgoCD, ToolFlux)
+		}
+		s.kinds = append(s.kinds, kind)
+	}
+
+	for _, f := range client.DynamicFactories(namespaces) {
+		for _, kind := range s.kinds {
+			inf := f.ForResource(resources[kind])
+			s.cache.Add(inf.Informer())
+			s.listers[kind] = append(s.listers[kind], inf.Lister())
+		}
+	}
+
+	return &s, nil
+}
+
+// Run watches the objects until ctx is canceled.
+func (s *Store) Run(ctx context.Context) {
+	s.cache.Run(ctx)
+}
+
+// QueryHealthChecks reports every Application and Kustomization.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if err := s.cache.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("listing gitops objects: %w", err)
+	}
+
+	now := time.Now()
+	var checks []healthbus.HealthCheck
+
+	for _, kind := range s.kinds {
+		for _, l := range s.listers[kind] {
+			objs, err := l.List(labels.Everything())
+			if err != nil {
+				return nil, fmt.Errorf("listing %ss: %w", kind, err)
+			}
+
+			for _, obj := range objs {
+				check, err := toCheck(kind, obj, now)
+				if err != nil {
+					return nil, err
+				}
+				checks = append(checks, check)
+			}
+		}
+	}
+
+	// The cache is unordered; keep the order of a list call.
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Target < checks[j].Target })
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget reports a single object, named as
+// "<kind>/<namespace>/<name>".
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	kind, ns, name, err := parseTarget(target)
+	if err != nil {
+		return healthbus.HealthCheck{}, err
+	}
+
+	i := 0
+	if len(s.namespaces) > 0 {
+		i = slices.Index(s.namespaces, ns)
+	}
+	if !slices.Contains(s.kinds, kind) || i < 0 {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if err := s.cache.Wait(ctx); err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	obj, err := s.listers[kind][i].ByNamespace(ns).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+		}
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	return toCheck(kind, obj, time.Now())
+}
+
+// QueryAlerts reports a firing alert for every object that is not healthy:
+// critical when down, warning otherwise.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	checks, err := s.QueryHealthChecks(ctx)
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, check := range checks {
+		if check.Status == healthbus.StatusHealthy {
+			continue
+		}
+
+		severity := "warning"
+		if check.Status == healthbus.StatusDown {
+			severity = "critical"
+		}
+
+		summary.Alerts = append(summary.Alerts, healthbus.Alert{
+			UID:   check.Target,
+			Title: "GitOpsNotHealthy",
+			State: "firing",
+			Labels: map[string]string{
+				"alertname": "GitOpsNotHealthy",
+				"instance":  check.Target,
+				"namespace": check.Namespace,
+				"severity":  severity,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is %s", check.Target, check.Status),
+			},
+		})
+		summary.Total++
+		summary.Firing++
+	}
+
+	return summary, nil
+}
+
+// Check verifies that the Kubernetes API is reachable.
+func (s *Store) Check(ctx context.Context) error {
+	return s.client.Check(ctx)
+}
@@ -0,0 +1,260 @@
+package gitopsstore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Set of GitOps tools the store reads, as configured.
+const (
+	ToolArgoCD = "argocd"
+	ToolFlux   = "flux"
+)
+
+// Set of object kinds the store reports, as used in target names.
+const (
+	kindApplication   = "application"
+	kindKustomization = "kustomization"
+)
+
+// resources maps an object kind to its API resource.
+var resources = map[string]schema.GroupVersionResource{
+	kindApplication:   {Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"},
+	kindKustomization: {Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"},
+}
+
+// kinds maps a tool to the kind of object it reconciles.
+var kinds = map[string]string{
+	ToolArgoCD: kindApplication,
+	ToolFlux:   kindKustomization,
+}
+
+// metadata is the object metadata the store reads.
+type metadata struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// application is the subset of an Argo CD Application the store reads.
+type application struct {
+	Metadata metadata `json:"metadata"`
+	Status   struct {
+		Sync struct {
+			Status string `json:"status"`
+		} `json:"sync"`
+		Health struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"health"`
+		OperationState *struct {
+			Phase   string `json:"phase"`
+			Message string `json:"message"`
+		} `json:"operationState"`
+		Resources []struct {
+			Kind      string `json:"kind"`
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+			Status    string `json:"status"`
+			Health    *struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"health"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+// kustomization is the subset of a Flux Kustomization the store reads.
+type kustomization struct {
+	Metadata metadata `json:"metadata"`
+	Spec     struct {
+		Suspend bool `json:"suspend"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// =============================================================================
+
+// targetName returns the target an object is reported as, for example
+// "application/argocd/shop".
+func targetName(kind string, m metadata) string {
+	return kind + "/" + m.Namespace + "/" + m.Name
+}
+
+// parseTarget splits a target name into its kind, namespace and name.
+func parseTarget(target string) (kind, namespace, name string, err error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if _, ok := resources[parts[0]]; !ok {
+		return "", "", "", fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// toCheck maps an object of the kind, as cached by the informer, to a
+// health check.
+func toCheck(kind string, obj runtime.Object, now time.Time) (healthbus.HealthCheck, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return healthbus.HealthCheck{}, fmt.Errorf("unexpected %s object %T", kind, obj)
+	}
+
+	switch kind {
+	case kindApplication:
+		var a application
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &a); err != nil {
+			return healthbus.HealthCheck{}, fmt.Errorf("decoding %s %s/%s: %w", kind, u.GetNamespace(), u.GetName(), err)
+		}
+		return applicationCheck(a, now), nil
+
+	default:
+		var k kustomization
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &k); err != nil {
+			return healthbus.HealthCheck{}, fmt.Errorf("decoding %s %s/%s: %w", kind, u.GetNamespace(), u.GetName(), err)
+		}
+		return kustomizationCheck(k, now), nil
+	}
+}
+
+// newCheck returns a healthy check of the object.
+func newCheck(kind string, m metadata, now time.Time) healthbus.HealthCheck {
+	return healthbus.HealthCheck{
+		Target:      targetName(kind, m),
+		Status:      healthbus.StatusHealthy,
+		LastChecked: now,
+		Probe:       probe,
+		Namespace:   m.Namespace,
+		Instance:    m.Name,
+	}
+}
+
+// applicationCheck maps an Application to a health check. Degraded or
+// missing resources take it down; drift from Git, a failed sync or
+// resources still progressing degrade it, and an unknown health leaves it
+// unknown. The resources that aren't synced and healthy are listed as
+// steps.
+func applicationCheck(a application, now time.Time) healthbus.HealthCheck {
+	check := newCheck(kindApplication, a.Metadata, now)
+
+	var reasons []string
+
+	switch a.Status.Health.Status {
+	case "Degraded", "Missing":
+		check.Status = healthbus.StatusDown
+	case "Progressing":
+		check.Status = healthbus.StatusDegraded
+		reasons = append(reasons, "progressing")
+	case "", "Unknown":
+		check.Status = healthbus.StatusUnknown
+	}
+
+	if a.Status.Sync.Status == "OutOfSync" {
+		reasons = append(reasons, "out of sync")
+	}
+
+	if op := a.Status.OperationState; op != nil && (op.Phase == "Failed" || op.Phase == "Error") {
+		reasons = append(reasons, "sync failed: "+op.Message)
+	}
+
+	if len(reasons) > 0 && check.Status != healthbus.StatusDown && check.Status != healthbus.StatusUnknown {
+		check.Status = healthbus.StatusDegraded
+		check.DegradedReason = strings.Join(reasons, ", ")
+	}
+
+	if check.Status == healthbus.StatusHealthy {
+		return check
+	}
+
+	for _, r := range a.Status.Resources {
+		step := healthbus.StepTiming{
+			Name:    strings.ToLower(r.Kind) + "/" + r.Name,
+			Success: true,
+		}
+		if r.Namespace != "" {
+			step.Name = strings.ToLower(r.Kind) + "/" + r.Namespace + "/" + r.Name
+		}
+
+		var errs []string
+		if r.Status == "OutOfSync" {
+			errs = append(errs, "out of sync")
+		}
+		if r.Health != nil && r.Health.Status != "" && r.Health.Status != "Healthy" {
+			errs = append(errs, strings.TrimSpace(strings.ToLower(r.Health.Status)+" "+r.Health.Message))
+		}
+		if len(errs) == 0 {
+			continue
+		}
+
+		step.Success = false
+		step.Error = strings.Join(errs, ", ")
+		check.Steps = append(check.Steps, step)
+	}
+
+	return check
+}
+
+// kustomizationCheck maps a Kustomization to a health check from its Ready
+// condition: down when the applied workloads fail their health checks,
+// degraded when the latest revision could not be applied or is still being
+// reconciled, or when reconciliation is suspended. The Ready condition of a
+// Kustomization that isn't healthy is listed as a step, explaining why.
+func kustomizationCheck(k kustomization, now time.Time) healthbus.HealthCheck {
+	check := newCheck(kindKustomization, k.Metadata, now)
+	check.Status = healthbus.StatusUnknown
+
+	var reasons []string
+
+	for _, c := range k.Status.Conditions {
+		if c.Type != "Ready" {
+			continue
+		}
+
+		switch c.Status {
+		case "True":
+			check.Status = healthbus.StatusHealthy
+			continue
+		case "False":
+			check.Status = healthbus.StatusDegraded
+			if c.Reason == "HealthCheckFailed" {
+				check.Status = healthbus.StatusDown
+			}
+			reasons = append(reasons, c.Reason)
+		default:
+			check.Status = healthbus.StatusDegraded
+			reasons = append(reasons, "reconciling")
+		}
+
+		check.Steps = append(check.Steps, healthbus.StepTiming{
+			Name:  "Ready",
+			Error: strings.TrimSpace(c.Reason + " " + c.Message),
+		})
+	}
+
+	if k.Spec.Suspend && check.Status != healthbus.StatusDown {
+		check.Status = healthbus.StatusDegraded
+		reasons = append(reasons, "suspended")
+	}
+
+	if check.Status == healthbus.StatusDegraded {
+		check.DegradedReason = strings.Join(reasons, ", ")
+	}
+
+	return check
+}
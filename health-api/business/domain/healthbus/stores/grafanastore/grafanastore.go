@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"health-api/business/domain/healthbus"
@@ -14,24 +16,48 @@ import (
 
 // Store implements healthbus.Storer using Grafana.
 type Store struct {
-	log             *logger.Logger
-	grafanaURL      string
-	grafanaUser     string
-	grafanaPassword string
-	httpClient      *http.Client
+	log         *logger.Logger
+	grafanaURL  string
+	credentials atomic.Pointer[credentials]
+	httpClient  *http.Client
 }
 
-// NewStore creates a new Grafana-backed health check store.
-func NewStore(log *logger.Logger, grafanaURL, grafanaUser, grafanaPassword string) *Store {
-	return &Store{
-		log:             log,
-		grafanaURL:      grafanaURL,
-		grafanaUser:     grafanaUser,
-		grafanaPassword: grafanaPassword,
+// credentials holds the basic auth user and password for Grafana.
+type credentials struct {
+	user     string
+	password string
+}
+
+// NewStore creates a new Grafana-backed health check store. The transport
+// carries tracing and request metrics; nil uses http.DefaultTransport.
+func NewStore(log *logger.Logger, grafanaURL, grafanaUser, grafanaPassword string, transport http.RoundTripper) *Store {
+	s := Store{
+		log:        log,
+		grafanaURL: grafanaURL,
 		httpClient: &http.Client{
+			Transport: transport,
+
+			// A backstop for calls without a deadline, such as a stuck
+			// poll; route timeouts end API requests well before it.
 			Timeout: 30 * time.Second,
 		},
 	}
+	s.SetCredentials(grafanaUser, grafanaPassword)
+
+	return &s
+}
+
+// SetCredentials replaces the credentials used for subsequent requests,
+// e.g. after the Secret holding them was rotated.
+func (s *Store) SetCredentials(user, password string) {
+	s.credentials.Store(&credentials{user: user, password: password})
+}
+
+// setAuth adds the current credentials to req.
+func (s *Store) setAuth(req *http.Request) {
+	if c := s.credentials.Load(); c.user != "" && c.password != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
 }
 
 // QueryHealthChecks retrieves all health checks from Grafana alerts.
@@ -47,9 +73,7 @@ func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck,
 		return nil, fmt.Errorf("creating state request: %w", err)
 	}
 
-	if s.grafanaUser != "" && s.grafanaPassword != "" {
-		stateReq.SetBasicAuth(s.grafanaUser, s.grafanaPassword)
-	}
+	s.setAuth(stateReq)
 
 	stateResp, err := s.httpClient.Do(stateReq)
 	if err != nil {
@@ -130,7 +154,9 @@ func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck,
 				Status:      status,
 				LastChecked: lastChecked,
 				Probe:       labels["probe"],
+				Namespace:   labels["namespace"],
 			}
+			applyProbeAnnotations(&check, getStringMap(r, "annotations"))
 
 			checks = append(checks, check)
 		}
@@ -152,9 +178,7 @@ func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (he
 		return healthbus.HealthCheck{}, fmt.Errorf("creating state request: %w", err)
 	}
 
-	if s.grafanaUser != "" && s.grafanaPassword != "" {
-		stateReq.SetBasicAuth(s.grafanaUser, s.grafanaPassword)
-	}
+	s.setAuth(stateReq)
 
 	stateResp, err := s.httpClient.Do(stateReq)
 	if err != nil {
@@ -174,12 +198,12 @@ func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (he
 	// Find the specific target in the alert rules
 	data, ok := stateData["data"].(map[string]any)
 	if !ok {
-		return healthbus.HealthCheck{}, fmt.Errorf("target not found: %s", target)
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
 	}
 
 	groups, ok := data["groups"].([]any)
 	if !ok {
-		return healthbus.HealthCheck{}, fmt.Errorf("target not found: %s", target)
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
 	}
 
 	for _, group := range groups {
@@ -227,16 +251,20 @@ func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (he
 				status = healthbus.StatusUnknown
 			}
 
-			return healthbus.HealthCheck{
+			check := healthbus.HealthCheck{
 				Target:      target,
 				Status:      status,
 				LastChecked: lastChecked,
 				Probe:       labels["probe"],
-			}, nil
+				Namespace:   labels["namespace"],
+			}
+			applyProbeAnnotations(&check, getStringMap(r, "annotations"))
+
+			return check, nil
 		}
 	}
 
-	return healthbus.HealthCheck{}, fmt.Errorf("target not found: %s", target)
+	return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
 }
 
 // QueryAlerts retrieves alert summary from Grafana.
@@ -252,9 +280,7 @@ func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error)
 		return healthbus.AlertSummary{}, fmt.Errorf("creating state request: %w", err)
 	}
 
-	if s.grafanaUser != "" && s.grafanaPassword != "" {
-		stateReq.SetBasicAuth(s.grafanaUser, s.grafanaPassword)
-	}
+	s.setAuth(stateReq)
 
 	stateResp, err := s.httpClient.Do(stateReq)
 	if err != nil {
@@ -325,7 +351,7 @@ func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error)
 				summary.Firing++
 			case "pending":
 				summary.Pending++
-			case "normal":
+			case "normal", "inactive":
 				summary.Normal++
 			}
 		}
@@ -334,6 +360,31 @@ func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error)
 	return summary, nil
 }
 
+// Check verifies that Grafana is reachable and reports itself healthy.
+func (s *Store) Check(ctx context.Context) error {
+	if s.grafanaURL == "" {
+		return fmt.Errorf("grafana not configured")
+	}
+
+	healthURL := fmt.Sprintf("%s/api/health", s.grafanaURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating health request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("querying grafana health: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("grafana returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 // Helper functions
 
 func getString(m map[string]any, key string) string {
@@ -343,6 +394,23 @@ func getString(m map[string]any, key string) string {
 	return ""
 }
 
+// applyProbeAnnotations populates probe details from alert rule annotations
+// such as duration_seconds or http_status_code. Unparsable values are ignored.
+func applyProbeAnnotations(check *healthbus.HealthCheck, annotations map[string]string) {
+	if v, err := strconv.ParseFloat(annotations["duration_seconds"], 64); err == nil {
+		check.DurationSeconds = v
+	}
+	if v, err := strconv.Atoi(annotations["http_status_code"]); err == nil {
+		check.HTTPStatusCode = v
+	}
+	if v, err := strconv.ParseFloat(annotations["ssl_expiry_days"], 64); err == nil {
+		check.SSLExpiryDays = v
+	}
+	if v, err := strconv.ParseFloat(annotations["dns_lookup_seconds"], 64); err == nil {
+		check.DNSLookupSeconds = v
+	}
+}
+
 func getStringMap(m map[string]any, key string) map[string]string {
 	result := make(map[string]string)
 	if v, ok := m[key].(map[string]any); ok {
@@ -0,0 +1,38 @@
+package grafanastore_test
+
+import (
+	"os"
+	"testing"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/grafanastore"
+	"health-api/business/domain/healthbus/storetest"
+	"health-api/foundation/logger"
+)
+
+func Test_Conformance(t *testing.T) {
+	newStore := func(t *testing.T, url string) healthbus.Storer {
+		log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+		return grafanastore.NewStore(log, url, "admin", "admin", nil)
+	}
+
+	want := storetest.Expect{
+		Checks: map[string]healthbus.Status{
+			"https://shop.example.com": healthbus.StatusDown,
+			"https://api.example.com":  healthbus.StatusUnknown,
+			"https://docs.example.com": healthbus.StatusHealthy,
+		},
+		Namespaces: map[string]string{
+			"https://shop.example.com": "shop",
+			"https://api.example.com":  "platform",
+		},
+		Alerts: healthbus.AlertSummary{
+			Total:   4,
+			Firing:  1,
+			Pending: 1,
+			Normal:  2,
+		},
+	}
+
+	storetest.Run(t, newStore, "testdata/recording.json", want)
+}
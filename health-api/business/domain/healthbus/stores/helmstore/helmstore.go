@@ -0,0 +1,183 @@
+// Package helmstore implements the health check store from the status of
+// Helm releases, so chart rollouts are reported next to probed targets.
+// Helm keeps every release revision in a Secret labelled with the release
+// name, revision and status; the store watches only the metadata of those
+// Secrets, never the release payload, and reads the status from the
+// labels. Only the default secret storage driver is supported.
+package helmstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/metadata/metadatalister"
+)
+
+// probe is the probe name reported for release checks.
+const probe = "helm"
+
+// Store implements healthbus.Storer using the Kubernetes API. The release
+// Secrets are watched by a metadata informer, so queries read them from
+// the cache instead of listing them from the API server.
+type Store struct {
+	log            *logger.Logger
+	client         *kube.Client
+	namespaces     []string
+	pendingTimeout time.Duration
+	cache          *kube.Cache
+
+	// listers holds a lister per namespace, or one for the cluster.
+	listers []metadatalister.Lister
+}
+
+// NewStore creates a store reporting the Helm releases in the namespaces,
+// or in every namespace when none are given. Releases pending for longer
+// than pendingTimeout are reported down; zero never does. Run must be
+// running for queries to be answered.
+func NewStore(log *logger.Logger, client *kube.Client, namespaces []string, pendingTimeout time.Duration) *Store {
+	s := Store{
+		log:            log,
+		client:         client,
+		namespaces:     namespaces,
+		pendingTimeout: pendingTimeout,
+		cache:          kube.NewCache(),
+	}
+
+	// Only the Secrets Helm stores releases in are watched.
+	owned := func(o *metav1.ListOptions) {
+		o.LabelSelector = "owner=helm"
+	}
+
+	for _, f := range client.MetadataFactories(namespaces, owned) {
+		inf := f.ForResource(secrets)
+		s.cache.Add(inf.Informer())
+		s.listers = append(s.listers, metadatalister.New(inf.Informer().GetIndexer(), secrets))
+	}
+
+	return &s
+}
+
+// Run watches the release Secrets until ctx is canceled.
+func (s *Store) Run(ctx context.Context) {
+	s.cache.Run(ctx)
+}
+
+// QueryHealthChecks reports the latest revision of every release.
+// Uninstalled releases kept with --keep-history are left out.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if err := s.cache.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("listing releases: %w", err)
+	}
+
+	now := time.Now()
+	var checks []healthbus.HealthCheck
+
+	for _, l := range s.listers {
+		revisions, err := l.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("listing releases: %w", err)
+		}
+
+		for _, r := range latestReleases(revisions) {
+			if r.status == statusUninstalled {
+				continue
+			}
+			checks = append(checks, toHealthCheck(r, now, s.pendingTimeout))
+		}
+	}
+
+	// The cache is unordered; keep the order of a list call.
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Target < checks[j].Target })
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget reports a single release, named as
+// "release/<namespace>/<name>".
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	ns, name, err := parseTarget(target)
+	if err != nil {
+		return healthbus.HealthCheck{}, err
+	}
+
+	i := 0
+	if len(s.namespaces) > 0 {
+		i = slices.Index(s.namespaces, ns)
+	}
+	if i < 0 {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if err := s.cache.Wait(ctx); err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	revisions, err := s.listers[i].Namespace(ns).List(labels.SelectorFromSet(labels.Set{"name": name}))
+	if err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	releases := latestReleases(revisions)
+	if len(releases) == 0 || releases[0].status == statusUninstalled {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return toHealthCheck(releases[0], time.Now(), s.pendingTimeout), nil
+}
+
+// QueryAlerts reports a firing alert for every release that is not
+// deployed: critical when down, warning otherwise.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	checks, err := s.QueryHealthChecks(ctx)
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, check := range checks {
+		if check.Status == healthbus.StatusHealthy {
+			continue
+		}
+
+		severity := "warning"
+		if check.Status == healthbus.StatusDown {
+			severity = "critical"
+		}
+
+		summary.Alerts = append(summary.Alerts, healthbus.Alert{
+			UID:   check.Target,
+			Title: "HelmReleaseNotDeployed",
+			State: "firing",
+			Labels: map[string]string{
+				"alertname": "HelmReleaseNotDeployed",
+				"instance":  check.Target,
+				"namespace": check.Namespace,
+				"severity":  severity,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is %s", check.Target, check.Status),
+			},
+		})
+		summary.Total++
+		summary.Firing++
+	}
+
+	return summary, nil
+}
+
+// Check verifies that the Kubernetes API is reachable.
+func (s *Store) Check(ctx context.Context) error {
+	return s.client.Check(ctx)
+}
@@ -0,0 +1,85 @@
+package helmstore_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/helmstore"
+	"health-api/business/domain/healthbus/storetest"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+)
+
+func Test_Conformance(t *testing.T) {
+	newStore := func(t *testing.T, url string) healthbus.Storer {
+		log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+		store := helmstore.NewStore(log, kube.New(url, "", http.DefaultTransport), nil, 0)
+		go store.Run(t.Context())
+
+		return store
+	}
+
+	want := storetest.Expect{
+		Checks: map[string]healthbus.Status{
+			"release/monitoring/is-it-up-tho": healthbus.StatusHealthy,
+			"release/shop/web":                healthbus.StatusDown,
+			"release/shop/cache":              healthbus.StatusDegraded,
+		},
+		Namespaces: map[string]string{
+			"release/monitoring/is-it-up-tho": "monitoring",
+			"release/shop/web":                "shop",
+			"release/shop/cache":              "shop",
+		},
+		Alerts: healthbus.AlertSummary{
+			Total:  2,
+			Firing: 2,
+		},
+	}
+
+	storetest.Run(t, newStore, "testdata/recording.json", want)
+}
+
+func Test_Releases(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+	url := storetest.Replay(t, "testdata/recording.json").URL
+
+	store := helmstore.NewStore(log, kube.New(url, "", http.DefaultTransport), nil, time.Hour)
+	go store.Run(t.Context())
+
+	checks, err := store.QueryHealthChecks(context.Background())
+	if err != nil {
+		t.Fatalf("Should be able to query the releases: %s", err)
+	}
+
+	byTarget := make(map[string]healthbus.HealthCheck)
+	for _, check := range checks {
+		byTarget[check.Target] = check
+	}
+
+	if _, ok := byTarget["release/shop/legacy"]; ok {
+		t.Error("Should leave out uninstalled releases")
+	}
+
+	if check := byTarget["release/shop/cache"]; check.Status != healthbus.StatusDown {
+		t.Errorf("Should report a release pending past the timeout as down, got %s", check.Status)
+	}
+
+	if check := byTarget["release/monitoring/is-it-up-tho"]; check.Instance != "is-it-up-tho" || check.Probe != "helm" {
+		t.Errorf("Should report the release name and probe, got %q %q", check.Instance, check.Probe)
+	}
+
+	check, err := store.QueryHealthCheckByTarget(context.Background(), "release/shop/web")
+	if err != nil || check.Status != healthbus.StatusDown {
+		t.Errorf("Should report the failed revision 4 of web, got %s %v", check.Status, err)
+	}
+
+	if _, err := store.QueryHealthCheckByTarget(context.Background(), "release/shop"); !errors.Is(err, healthbus.ErrNotFound) {
+		t.Errorf("Should reject a malformed target as not found, got %v", err)
+	}
+}
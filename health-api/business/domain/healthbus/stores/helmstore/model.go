@@ -0,0 +1,143 @@
+package helmstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// kind is the kind of object the store reports, as used in target names.
+const kind = "release"
+
+// Set of Helm release statuses, as found in the status label.
+const (
+	statusDeployed        = "deployed"
+	statusFailed          = "failed"
+	statusUninstalled     = "uninstalled"
+	statusUninstalling    = "uninstalling"
+	statusPendingInstall  = "pending-install"
+	statusPendingUpgrade  = "pending-upgrade"
+	statusPendingRollback = "pending-rollback"
+)
+
+// secrets is the resource Helm's default storage driver keeps releases in.
+var secrets = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// release is the latest revision of a Helm release.
+type release struct {
+	name      string
+	namespace string
+	revision  int
+	status    string
+	modified  time.Time
+}
+
+// =============================================================================
+
+// latestReleases reduces the revisions in the secrets to the latest one of
+// every release.
+func latestReleases(secrets []*metav1.PartialObjectMetadata) []release {
+	var releases []release
+	index := make(map[string]int)
+
+	for _, s := range secrets {
+		r, ok := toRelease(s)
+		if !ok {
+			continue
+		}
+
+		key := r.namespace + "/" + r.name
+		i, seen := index[key]
+		switch {
+		case !seen:
+			index[key] = len(releases)
+			releases = append(releases, r)
+		case r.revision > releases[i].revision:
+			releases[i] = r
+		}
+	}
+
+	return releases
+}
+
+// toRelease reads a release revision from the labels Helm sets on its
+// storage Secret.
+func toRelease(s *metav1.PartialObjectMetadata) (release, bool) {
+	labels := s.Labels
+
+	revision, err := strconv.Atoi(labels["version"])
+	if err != nil || labels["name"] == "" {
+		return release{}, false
+	}
+
+	r := release{
+		name:      labels["name"],
+		namespace: s.Namespace,
+		revision:  revision,
+		status:    labels["status"],
+		modified:  s.CreationTimestamp.Time,
+	}
+
+	// Helm records when it last changed the revision's status in
+	// modifiedAt, as Unix seconds.
+	if sec, err := strconv.ParseInt(labels["modifiedAt"], 10, 64); err == nil {
+		r.modified = time.Unix(sec, 0)
+	}
+
+	return r, true
+}
+
+// targetName returns the target a release is reported as, for example
+// "release/monitoring/is-it-up-tho".
+func targetName(r release) string {
+	return kind + "/" + r.namespace + "/" + r.name
+}
+
+// parseTarget splits a target name into its namespace and release name.
+func parseTarget(target string) (namespace, name string, err error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 3 || parts[0] != kind || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return parts[1], parts[2], nil
+}
+
+// toHealthCheck maps the status of a release's latest revision to a health
+// check: healthy when deployed, down when failed, and degraded while an
+// install, upgrade, rollback or uninstall is pending. A pending status that
+// outlives pendingTimeout is left behind by an interrupted Helm run and
+// blocks further upgrades, so it counts as down.
+func toHealthCheck(r release, now time.Time, pendingTimeout time.Duration) healthbus.HealthCheck {
+	check := healthbus.HealthCheck{
+		Target:      targetName(r),
+		Status:      healthbus.StatusHealthy,
+		LastChecked: now,
+		Probe:       probe,
+		Namespace:   r.namespace,
+		Instance:    r.name,
+	}
+
+	switch r.status {
+	case statusDeployed:
+	case statusFailed:
+		check.Status = healthbus.StatusDown
+	case statusPendingInstall, statusPendingUpgrade, statusPendingRollback, statusUninstalling:
+		check.Status = healthbus.StatusDegraded
+		check.DegradedReason = fmt.Sprintf("revision %d %s", r.revision, r.status)
+		if pendingTimeout > 0 && now.Sub(r.modified) > pendingTimeout {
+			check.Status = healthbus.StatusDown
+			check.DegradedReason = ""
+		}
+	default:
+		check.Status = healthbus.StatusUnknown
+	}
+
+	return check
+}
@@ -0,0 +1,94 @@
+// Package ingeststore implements the health check store using results
+// pushed by agents. Results whose agent missed its heartbeat are down.
+package ingeststore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/ingestbus"
+)
+
+// Store implements healthbus.Storer using ingested agent results.
+type Store struct {
+	ingestBus *ingestbus.Business
+}
+
+// NewStore creates an ingest-backed health check store.
+func NewStore(ingestBus *ingestbus.Business) *Store {
+	return &Store{
+		ingestBus: ingestBus,
+	}
+}
+
+// QueryHealthChecks returns a health check for every agent and target.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	results, err := s.ingestBus.Query(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	checks := make([]healthbus.HealthCheck, len(results))
+	for i, r := range results {
+		checks[i] = toHealthCheck(r, now)
+	}
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget returns the health check for the target. When
+// several agents report it, a down result wins; healthbus merges the
+// results of agents in different regions itself.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	results, err := s.ingestBus.QueryByTarget(ctx, target)
+	if err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	now := time.Now()
+
+	check := toHealthCheck(results[0], now)
+	for _, r := range results[1:] {
+		if c := toHealthCheck(r, now); c.Status == healthbus.StatusDown {
+			check = c
+		}
+	}
+
+	return check, nil
+}
+
+// QueryAlerts returns an empty summary; agents do not raise alerts.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	return healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}, nil
+}
+
+// =============================================================================
+
+func toHealthCheck(r ingestbus.Result, now time.Time) healthbus.HealthCheck {
+	status := healthbus.StatusDown
+	if r.Success && !r.Expired(now) {
+		status = healthbus.StatusHealthy
+	}
+
+	probe := r.Probe
+	if probe == "" {
+		probe = "agent"
+	}
+
+	return healthbus.HealthCheck{
+		Target:          r.Target,
+		Status:          status,
+		LastChecked:     r.ReceivedAt,
+		Probe:           probe,
+		Instance:        r.Agent,
+		Region:          r.Region,
+		DurationSeconds: r.DurationSeconds,
+		HTTPStatusCode:  r.HTTPStatusCode,
+	}
+}
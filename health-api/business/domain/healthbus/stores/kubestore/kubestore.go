@@ -0,0 +1,248 @@
+// Package kubestore implements the health check store from the readiness
+// of Deployments and StatefulSets in the cluster, so in-cluster workloads
+// are reported next to externally probed targets. Pods of workloads that
+// are not ready are reported as steps, explaining why.
+package kubestore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// probe is the probe name reported for workload checks.
+const probe = "kubernetes"
+
+// Store implements healthbus.Storer using the Kubernetes API. Workloads
+// and pods are watched by informers, so queries read them from the cache
+// instead of listing them from the API server.
+type Store struct {
+	log        *logger.Logger
+	client     *kube.Client
+	namespaces []string
+	cache      *kube.Cache
+	listers    []listers
+}
+
+// listers reads the cached objects of one namespace, or of the cluster.
+type listers struct {
+	deployments  appslisters.DeploymentLister
+	statefulSets appslisters.StatefulSetLister
+	pods         corelisters.PodLister
+}
+
+// NewStore creates a store reporting the workloads in the namespaces, or
+// in every namespace when none are given. Run must be running for queries
+// to be answered.
+func NewStore(log *logger.Logger, client *kube.Client, namespaces []string) *Store {
+	s := Store{
+		log:        log,
+		client:     client,
+		namespaces: namespaces,
+		cache:      kube.NewCache(),
+	}
+
+	for _, f := range client.Factories(namespaces) {
+		deployments := f.Apps().V1().Deployments()
+		statefulSets := f.Apps().V1().StatefulSets()
+		pods := f.Core().V1().Pods()
+
+		s.cache.Add(deployments.Informer())
+		s.cache.Add(statefulSets.Informer())
+		s.cache.Add(pods.Informer())
+
+		s.listers = append(s.listers, listers{
+			deployments:  deployments.Lister(),
+			statefulSets: statefulSets.Lister(),
+			pods:         pods.Lister(),
+		})
+	}
+
+	return &s
+}
+
+// Run watches the workloads and pods until ctx is canceled.
+func (s *Store) Run(ctx context.Context) {
+	s.cache.Run(ctx)
+}
+
+// QueryHealthChecks reports every Deployment and StatefulSet.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if err := s.cache.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("listing workloads: %w", err)
+	}
+
+	now := time.Now()
+	var checks []healthbus.HealthCheck
+
+	for _, l := range s.listers {
+		deployments, err := l.deployments.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("listing deployments: %w", err)
+		}
+		for _, d := range deployments {
+			checks = append(checks, s.check(ctx, l, fromDeployment(d), now))
+		}
+
+		statefulSets, err := l.statefulSets.List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("listing statefulsets: %w", err)
+		}
+		for _, ss := range statefulSets {
+			checks = append(checks, s.check(ctx, l, fromStatefulSet(ss), now))
+		}
+	}
+
+	// The cache is unordered; keep the order of a list call.
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Target < checks[j].Target })
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget reports a single workload, named as
+// "<kind>/<namespace>/<name>".
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	kind, ns, name, err := parseTarget(target)
+	if err != nil {
+		return healthbus.HealthCheck{}, err
+	}
+
+	l, ok := s.listersFor(ns)
+	if !ok {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if err := s.cache.Wait(ctx); err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("querying %s: %w", target, err)
+	}
+
+	var w workload
+	switch kind {
+	case kindDeployment:
+		d, err := l.deployments.Deployments(ns).Get(name)
+		if err != nil {
+			return healthbus.HealthCheck{}, lookupError(target, err)
+		}
+		w = fromDeployment(d)
+
+	case kindStatefulSet:
+		ss, err := l.statefulSets.StatefulSets(ns).Get(name)
+		if err != nil {
+			return healthbus.HealthCheck{}, lookupError(target, err)
+		}
+		w = fromStatefulSet(ss)
+	}
+
+	return s.check(ctx, l, w, time.Now()), nil
+}
+
+// QueryAlerts reports a firing alert for every workload that is not fully
+// ready: critical when no replica is ready, warning otherwise.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	checks, err := s.QueryHealthChecks(ctx)
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, check := range checks {
+		if check.Status == healthbus.StatusHealthy {
+			continue
+		}
+
+		severity := "warning"
+		if check.Status == healthbus.StatusDown {
+			severity = "critical"
+		}
+
+		summary.Alerts = append(summary.Alerts, healthbus.Alert{
+			UID:   check.Target,
+			Title: "WorkloadNotReady",
+			State: "firing",
+			Labels: map[string]string{
+				"alertname": "WorkloadNotReady",
+				"instance":  check.Target,
+				"namespace": check.Namespace,
+				"severity":  severity,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is %s", check.Target, check.Status),
+			},
+		})
+		summary.Total++
+		summary.Firing++
+	}
+
+	return summary, nil
+}
+
+// Check verifies that the Kubernetes API is reachable.
+func (s *Store) Check(ctx context.Context) error {
+	return s.client.Check(ctx)
+}
+
+// check maps the workload to a health check and, when it is not healthy,
+// adds a step per pod. Reading the pods is best effort.
+func (s *Store) check(ctx context.Context, l listers, w workload, now time.Time) healthbus.HealthCheck {
+	check := toHealthCheck(w, now)
+	if check.Status == healthbus.StatusHealthy || w.selector == nil {
+		return check
+	}
+
+	// An empty selector would match every pod of the namespace.
+	selector, err := metav1.LabelSelectorAsSelector(w.selector)
+	if err != nil || selector.Empty() {
+		return check
+	}
+
+	pods, err := l.pods.Pods(w.namespace).List(selector)
+	if err != nil {
+		s.log.Warn(ctx, "kubestore", "target", check.Target, "pods", err)
+		return check
+	}
+
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	for _, p := range pods {
+		check.Steps = append(check.Steps, toStep(p))
+	}
+
+	return check
+}
+
+// listersFor returns the listers holding the namespace, if the store
+// watches it.
+func (s *Store) listersFor(namespace string) (listers, bool) {
+	if len(s.namespaces) == 0 {
+		return s.listers[0], true
+	}
+
+	i := slices.Index(s.namespaces, namespace)
+	if i < 0 {
+		return listers{}, false
+	}
+
+	return s.listers[i], true
+}
+
+// lookupError maps a lister's error for target.
+func lookupError(target string, err error) error {
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+	return fmt.Errorf("querying %s: %w", target, err)
+}
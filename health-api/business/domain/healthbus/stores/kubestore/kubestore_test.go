@@ -0,0 +1,155 @@
+package kubestore_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/kubestore"
+	"health-api/business/domain/healthbus/storetest"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+)
+
+func Test_Conformance(t *testing.T) {
+	newStore := func(t *testing.T, url string) healthbus.Storer {
+		log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+		store := kubestore.NewStore(log, kube.New(url, "", http.DefaultTransport), nil)
+		go store.Run(t.Context())
+
+		return store
+	}
+
+	want := storetest.Expect{
+		Checks: map[string]healthbus.Status{
+			"deployment/shop/web":     healthbus.StatusDegraded,
+			"deployment/platform/api": healthbus.StatusHealthy,
+			"statefulset/shop/db":     healthbus.StatusDown,
+		},
+		Namespaces: map[string]string{
+			"deployment/shop/web":     "shop",
+			"deployment/platform/api": "platform",
+			"statefulset/shop/db":     "shop",
+		},
+		Alerts: healthbus.AlertSummary{
+			Total:  2,
+			Firing: 2,
+		},
+	}
+
+	storetest.Run(t, newStore, "testdata/recording.json", want)
+}
+
+// Test_Client runs the store against a TLS API server that checks the
+// service account token, so the client's auth, TLS and error mapping are
+// exercised and not only the recorded responses.
+func Test_Client(t *testing.T) {
+	const deployment = `{"metadata":{"name":"web","namespace":"shop"},"spec":{"replicas":2,"selector":{"matchLabels":{"app":"web"}}},"status":{"replicas":2,"readyReplicas":1}}`
+	const pods = `{"metadata":{"name":"web-1","namespace":"shop","labels":{"app":"web"}},"status":{"phase":"Pending"}},` +
+		`{"metadata":{"name":"api-1","namespace":"shop","labels":{"app":"api"}},"status":{"phase":"Pending"}}`
+
+	var mu sync.Mutex
+	var lists []string
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Header.Get("Authorization") != "Bearer sa-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`))
+			return
+		}
+
+		// Hold watches open; the cache only needs the initial lists.
+		if r.URL.Query().Get("watch") == "true" {
+			<-r.Context().Done()
+			return
+		}
+
+		mu.Lock()
+		lists = append(lists, r.URL.Path)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/apis/apps/v1/namespaces/shop/deployments":
+			w.Write([]byte(`{"metadata":{"resourceVersion":"1"},"items":[` + deployment + `]}`))
+		case "/apis/apps/v1/namespaces/shop/statefulsets":
+			w.Write([]byte(`{"metadata":{"resourceVersion":"1"},"items":[]}`))
+		case "/api/v1/namespaces/shop/pods":
+			w.Write([]byte(`{"metadata":{"resourceVersion":"1"},"items":[` + pods + `]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("sa-token\n"), 0o600); err != nil {
+		t.Fatalf("Should be able to write the token: %s", err)
+	}
+
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+	store := kubestore.NewStore(log, kube.New(srv.URL, tokenFile, transport), []string{"shop"})
+	go store.Run(t.Context())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	checks, err := store.QueryHealthChecks(ctx)
+	if err != nil {
+		t.Fatalf("Should be able to query the checks: %s", err)
+	}
+	if len(checks) != 1 || checks[0].Status != healthbus.StatusDegraded || len(checks[0].Steps) != 1 || checks[0].Steps[0].Name != "web-1" {
+		t.Fatalf("Should report the degraded deployment with its pod, got %+v", checks)
+	}
+
+	if _, err := store.QueryHealthCheckByTarget(ctx, "deployment/shop/api"); !errors.Is(err, healthbus.ErrNotFound) {
+		t.Errorf("Should report a missing workload as not found, got %v", err)
+	}
+	if _, err := store.QueryHealthCheckByTarget(ctx, "deployment/billing/api"); !errors.Is(err, healthbus.ErrNotFound) {
+		t.Errorf("Should report a workload outside the namespaces as not found, got %v", err)
+	}
+
+	// Queries are answered from the cache, not by listing again.
+	for range 3 {
+		if _, err := store.QueryHealthChecks(ctx); err != nil {
+			t.Fatalf("Should be able to query the checks: %s", err)
+		}
+	}
+	mu.Lock()
+	if len(lists) != 3 {
+		t.Errorf("Should list each resource once, got %q", lists)
+	}
+	mu.Unlock()
+
+	// A token the API server rejects is a failure, not an empty cluster.
+	if err := os.WriteFile(tokenFile, []byte("expired"), 0o600); err != nil {
+		t.Fatalf("Should be able to write the token: %s", err)
+	}
+
+	rejected := kubestore.NewStore(log, kube.New(srv.URL, tokenFile, transport), []string{"shop"})
+	go rejected.Run(t.Context())
+
+	if _, err := rejected.QueryHealthChecks(ctx); err == nil {
+		t.Error("Should fail when the API server rejects the token")
+	}
+	if _, err := rejected.QueryHealthCheckByTarget(ctx, "deployment/shop/web"); err == nil || errors.Is(err, healthbus.ErrNotFound) {
+		t.Errorf("Should not report a rejected token as not found, got %v", err)
+	}
+}
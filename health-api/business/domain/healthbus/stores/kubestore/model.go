@@ -0,0 +1,147 @@
+package kubestore
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Set of workload kinds the store reports, as used in target names.
+const (
+	kindDeployment  = "deployment"
+	kindStatefulSet = "statefulset"
+)
+
+// workload is the subset of a Deployment or StatefulSet the store reads.
+type workload struct {
+	kind      string
+	namespace string
+	name      string
+	replicas  *int32
+	selector  *metav1.LabelSelector
+	ready     int32
+}
+
+func fromDeployment(d *appsv1.Deployment) workload {
+	return workload{
+		kind:      kindDeployment,
+		namespace: d.Namespace,
+		name:      d.Name,
+		replicas:  d.Spec.Replicas,
+		selector:  d.Spec.Selector,
+		ready:     d.Status.ReadyReplicas,
+	}
+}
+
+func fromStatefulSet(ss *appsv1.StatefulSet) workload {
+	return workload{
+		kind:      kindStatefulSet,
+		namespace: ss.Namespace,
+		name:      ss.Name,
+		replicas:  ss.Spec.Replicas,
+		selector:  ss.Spec.Selector,
+		ready:     ss.Status.ReadyReplicas,
+	}
+}
+
+// =============================================================================
+
+// targetName returns the target a workload is reported as, for example
+// "deployment/shop/web".
+func (w workload) targetName() string {
+	return w.kind + "/" + w.namespace + "/" + w.name
+}
+
+// parseTarget splits a target name into its kind, namespace and name.
+func parseTarget(target string) (kind, namespace, name string, err error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	if parts[0] != kindDeployment && parts[0] != kindStatefulSet {
+		return "", "", "", fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// desired returns the number of replicas the workload asks for. The API
+// server defaults an omitted count to one.
+func (w workload) desired() int32 {
+	if w.replicas == nil {
+		return 1
+	}
+	return *w.replicas
+}
+
+// toHealthCheck maps a workload's readiness to a health check: healthy
+// when every desired replica is ready, down when none is and degraded in
+// between. Workloads scaled to zero are healthy.
+func toHealthCheck(w workload, now time.Time) healthbus.HealthCheck {
+	check := healthbus.HealthCheck{
+		Target:      w.targetName(),
+		Status:      healthbus.StatusHealthy,
+		LastChecked: now,
+		Probe:       probe,
+		Namespace:   w.namespace,
+		Instance:    w.name,
+	}
+
+	desired, ready := w.desired(), w.ready
+	switch {
+	case desired == 0 || ready >= desired:
+	case ready == 0:
+		check.Status = healthbus.StatusDown
+	default:
+		check.Status = healthbus.StatusDegraded
+		check.DegradedReason = fmt.Sprintf("%d/%d replicas ready", ready, desired)
+	}
+
+	return check
+}
+
+// toStep reports a pod as one step of the check: successful when its Ready
+// condition is true, otherwise explained by the first waiting or
+// terminated container, or by the condition itself.
+func toStep(p *corev1.Pod) healthbus.StepTiming {
+	step := healthbus.StepTiming{
+		Name: p.Name,
+	}
+
+	for _, c := range p.Status.Conditions {
+		if c.Type != corev1.PodReady {
+			continue
+		}
+
+		if c.Status == corev1.ConditionTrue {
+			step.Success = true
+			return step
+		}
+
+		step.Error = strings.TrimSpace(c.Reason + " " + c.Message)
+	}
+
+	for _, cs := range p.Status.ContainerStatuses {
+		switch {
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+			step.Error = cs.Name + ": " + cs.State.Waiting.Reason
+			return step
+		case cs.State.Terminated != nil:
+			step.Error = fmt.Sprintf("%s: %s (exit code %d)", cs.Name, cs.State.Terminated.Reason, cs.State.Terminated.ExitCode)
+			return step
+		}
+	}
+
+	if step.Error == "" {
+		step.Error = "pod " + strings.ToLower(string(p.Status.Phase))
+	}
+
+	return step
+}
@@ -0,0 +1,147 @@
+// Package memorystore implements the health check store in memory with
+// programmable fixtures and failure injection, for tests and local runs.
+package memorystore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"health-api/business/domain/healthbus"
+)
+
+// Store implements healthbus.Storer using data set by the caller.
+type Store struct {
+	mu      sync.RWMutex
+	checks  []healthbus.HealthCheck
+	alerts  []healthbus.Alert
+	latency time.Duration
+	err     error
+}
+
+// NewStore creates an empty in-memory health check store.
+func NewStore() *Store {
+	return &Store{}
+}
+
+// SetChecks replaces the health checks the store reports.
+func (s *Store) SetChecks(checks ...healthbus.HealthCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checks = slices.Clone(checks)
+}
+
+// SetAlerts replaces the alerts the store reports. Alert states are
+// counted the way Prometheus reports them: firing, pending and inactive.
+func (s *Store) SetAlerts(alerts ...healthbus.Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.alerts = slices.Clone(alerts)
+}
+
+// SetLatency delays every call by d, or until the context is done.
+func (s *Store) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// SetError makes every call fail with err until it is cleared with nil.
+func (s *Store) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// QueryHealthChecks returns the configured health checks.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if err := s.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.checks), nil
+}
+
+// QueryHealthCheckByTarget returns the configured health check of target.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	if err := s.wait(ctx); err != nil {
+		return healthbus.HealthCheck{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, check := range s.checks {
+		if check.Target == target {
+			return check, nil
+		}
+	}
+
+	return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+}
+
+// QueryAlerts returns the configured alerts.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	if err := s.wait(ctx); err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := healthbus.AlertSummary{
+		Alerts: slices.Clone(s.alerts),
+		Total:  len(s.alerts),
+	}
+	if summary.Alerts == nil {
+		summary.Alerts = []healthbus.Alert{}
+	}
+
+	for _, alert := range s.alerts {
+		switch alert.State {
+		case "firing":
+			summary.Firing++
+		case "pending":
+			summary.Pending++
+		case "inactive", "normal":
+			summary.Normal++
+		}
+	}
+
+	return summary, nil
+}
+
+// Check reports the injected error, so the store can stand in for a
+// dependency in readiness checks.
+func (s *Store) Check(ctx context.Context) error {
+	return s.wait(ctx)
+}
+
+// wait applies the injected latency and error.
+func (s *Store) wait(ctx context.Context) error {
+	s.mu.RLock()
+	latency, err := s.latency, s.err
+	s.mu.RUnlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return err
+}
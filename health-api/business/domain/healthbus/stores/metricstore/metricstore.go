@@ -0,0 +1,94 @@
+// Package metricstore decorates a health check store with Prometheus
+// metrics so backends can be compared.
+package metricstore
+
+import (
+	"context"
+	"time"
+
+	"health-api/business/domain/healthbus"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	queryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "health_api_store_query_duration_seconds",
+			Help:    "Store query duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"store", "method"},
+	)
+
+	queryResults = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "health_api_store_query_results",
+			Help: "Number of results returned by the last successful store query",
+		},
+		[]string{"store", "method"},
+	)
+
+	queryErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "health_api_store_query_errors_total",
+			Help: "Total number of failed store queries",
+		},
+		[]string{"store", "method"},
+	)
+)
+
+// Store implements healthbus.Storer by recording metrics around another
+// store.
+type Store struct {
+	name   string
+	storer healthbus.Storer
+}
+
+// NewStore wraps storer, labelling its metrics with name.
+func NewStore(name string, storer healthbus.Storer) *Store {
+	return &Store{
+		name:   name,
+		storer: storer,
+	}
+}
+
+// QueryHealthChecks retrieves all health checks from the wrapped store.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	start := time.Now()
+	checks, err := s.storer.QueryHealthChecks(ctx)
+	s.observe("QueryHealthChecks", start, len(checks), err)
+
+	return checks, err
+}
+
+// QueryHealthCheckByTarget retrieves a single health check from the wrapped
+// store.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	start := time.Now()
+	check, err := s.storer.QueryHealthCheckByTarget(ctx, target)
+	s.observe("QueryHealthCheckByTarget", start, 1, err)
+
+	return check, err
+}
+
+// QueryAlerts retrieves alerts from the wrapped store.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	start := time.Now()
+	summary, err := s.storer.QueryAlerts(ctx)
+	s.observe("QueryAlerts", start, len(summary.Alerts), err)
+
+	return summary, err
+}
+
+func (s *Store) observe(method string, start time.Time, results int, err error) {
+	queryDuration.WithLabelValues(s.name, method).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		queryErrors.WithLabelValues(s.name, method).Inc()
+		return
+	}
+
+	queryResults.WithLabelValues(s.name, method).Set(float64(results))
+}
@@ -0,0 +1,244 @@
+// Package multistore implements the health check store by merging the
+// results of several backend stores.
+package multistore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/logger"
+)
+
+// Backend is a named store that contributes results.
+type Backend struct {
+	Name   string
+	Storer healthbus.Storer
+}
+
+// Store implements healthbus.Storer across multiple backends.
+type Store struct {
+	log      *logger.Logger
+	backends []Backend
+}
+
+// NewStore creates a store that merges results from the given backends.
+func NewStore(log *logger.Logger, backends ...Backend) *Store {
+	return &Store{
+		log:      log,
+		backends: backends,
+	}
+}
+
+// QueryHealthChecks retrieves health checks from every backend and merges
+// checks that refer to the same target. When some backends fail the checks
+// of the others are returned with a healthbus.PartialError; only when all
+// fail is the query an error.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	if len(s.backends) == 0 {
+		return nil, fmt.Errorf("no stores configured")
+	}
+
+	results := make([][]healthbus.HealthCheck, len(s.backends))
+	errs := make([]error, len(s.backends))
+
+	var wg sync.WaitGroup
+	for i, b := range s.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = b.Storer.QueryHealthChecks(ctx)
+		}()
+	}
+	wg.Wait()
+
+	partial, err := s.partial(ctx, errs)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []healthbus.HealthCheck
+	index := make(map[string]int)
+
+	for i, result := range results {
+		if errs[i] != nil {
+			continue
+		}
+
+		for _, check := range result {
+			// Rows from different regions stay apart for healthbus to merge.
+			key := check.Target + "\x00" + check.Region
+
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(checks)
+				checks = append(checks, check)
+				continue
+			}
+			checks[i] = merge(checks[i], check)
+		}
+	}
+
+	return checks, partial
+}
+
+// QueryHealthCheckByTarget retrieves a specific health check by target,
+// merging the results of every backend that knows the target.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	if len(s.backends) == 0 {
+		return healthbus.HealthCheck{}, fmt.Errorf("no stores configured")
+	}
+
+	var check healthbus.HealthCheck
+	var found bool
+	var failed []error
+
+	for _, b := range s.backends {
+		c, err := b.Storer.QueryHealthCheckByTarget(ctx, target)
+		if err != nil {
+			if !errors.Is(err, healthbus.ErrNotFound) {
+				failed = append(failed, fmt.Errorf("%s: %w", b.Name, err))
+			}
+			continue
+		}
+
+		if !found {
+			check = c
+			found = true
+			continue
+		}
+		check = merge(check, c)
+	}
+
+	// A backend that failed may know the target, so it isn't reported
+	// missing.
+	if !found && len(failed) > 0 {
+		return healthbus.HealthCheck{}, errors.Join(failed...)
+	}
+
+	if !found {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return check, nil
+}
+
+// QueryAlerts retrieves alerts from every backend and combines them. As
+// with health checks, failing backends make the result partial unless all
+// of them fail.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	if len(s.backends) == 0 {
+		return healthbus.AlertSummary{}, fmt.Errorf("no stores configured")
+	}
+
+	results := make([]healthbus.AlertSummary, len(s.backends))
+	errs := make([]error, len(s.backends))
+
+	for i, b := range s.backends {
+		results[i], errs[i] = b.Storer.QueryAlerts(ctx)
+	}
+
+	partial, err := s.partial(ctx, errs)
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for i, as := range results {
+		if errs[i] != nil {
+			continue
+		}
+
+		summary.Total += as.Total
+		summary.Firing += as.Firing
+		summary.Pending += as.Pending
+		summary.Normal += as.Normal
+		summary.Alerts = append(summary.Alerts, as.Alerts...)
+	}
+
+	return summary, partial
+}
+
+// partial summarises the backend errors of a query: a healthbus.PartialError
+// when some backends failed, and err when all of them did.
+func (s *Store) partial(ctx context.Context, errs []error) (partial error, err error) {
+	var failed []healthbus.SourceError
+	var first error
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if first == nil {
+			first = fmt.Errorf("%s: %w", s.backends[i].Name, err)
+		}
+		failed = append(failed, healthbus.SourceError{
+			Source: s.backends[i].Name,
+			Error:  err.Error(),
+		})
+	}
+
+	switch {
+	case len(failed) == 0:
+		return nil, nil
+	case len(failed) == len(errs):
+		return nil, first
+	}
+
+	s.log.Warn(ctx, "multistore", "status", "partial results", "failed", len(failed))
+
+	return &healthbus.PartialError{Errors: failed}, nil
+}
+
+// Helper functions
+
+// statusRank orders statuses so the most severe one wins a merge.
+var statusRank = map[healthbus.Status]int{
+	healthbus.StatusHealthy:  0,
+	healthbus.StatusDegraded: 1,
+	healthbus.StatusUnknown:  2,
+	healthbus.StatusDown:     3,
+}
+
+func merge(a, b healthbus.HealthCheck) healthbus.HealthCheck {
+	if statusRank[b.Status] > statusRank[a.Status] {
+		a.Status = b.Status
+	}
+	if b.LastChecked.After(a.LastChecked) {
+		a.LastChecked = b.LastChecked
+	}
+	if a.Probe == "" {
+		a.Probe = b.Probe
+	}
+	if a.Namespace == "" {
+		a.Namespace = b.Namespace
+	}
+	if a.Instance == "" {
+		a.Instance = b.Instance
+	}
+	if a.DurationSeconds == 0 {
+		a.DurationSeconds = b.DurationSeconds
+	}
+	if a.HTTPStatusCode == 0 {
+		a.HTTPStatusCode = b.HTTPStatusCode
+	}
+	if a.SSLExpiryDays == 0 {
+		a.SSLExpiryDays = b.SSLExpiryDays
+	}
+	if a.DNSLookupSeconds == 0 {
+		a.DNSLookupSeconds = b.DNSLookupSeconds
+	}
+	if a.Steps == nil {
+		a.Steps = b.Steps
+	}
+	if a.TCPConnect == nil {
+		a.TCPConnect = b.TCPConnect
+	}
+	return a
+}
@@ -0,0 +1,118 @@
+// Package proberstore implements the health check store using the results
+// of the built-in prober.
+package proberstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/proberbus"
+	"health-api/business/sdk/tenant"
+)
+
+// Store implements healthbus.Storer using the built-in prober.
+type Store struct {
+	proberBus *proberbus.Business
+}
+
+// NewStore creates a prober-backed health check store.
+func NewStore(proberBus *proberbus.Business) *Store {
+	return &Store{
+		proberBus: proberBus,
+	}
+}
+
+// QueryHealthChecks returns a health check for every check that has run.
+// Results are read unscoped; healthbus applies the caller's scope.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	results := s.proberBus.Query(tenant.Unscoped(ctx))
+
+	checks := make([]healthbus.HealthCheck, len(results))
+	for i, r := range results {
+		checks[i] = toHealthCheck(r)
+	}
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget returns the health check for the named check.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	r, err := s.proberBus.QueryByName(tenant.Unscoped(ctx), target)
+	if err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return toHealthCheck(r), nil
+}
+
+// QueryAlerts returns an empty summary; the prober does not raise alerts.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	return healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}, nil
+}
+
+// =============================================================================
+
+func toHealthCheck(r proberbus.Result) healthbus.HealthCheck {
+	status := healthbus.StatusDown
+	if r.Success {
+		status = healthbus.StatusHealthy
+	}
+
+	hc := healthbus.HealthCheck{
+		Target:          r.Check,
+		Status:          status,
+		LastChecked:     r.StartedAt,
+		Probe:           r.Type,
+		Namespace:       r.Namespace,
+		DurationSeconds: r.DurationSeconds,
+	}
+
+	if r.Type == proberbus.TypeDNS {
+		hc.DNSLookupSeconds = r.DurationSeconds
+	}
+
+	if r.Connect != nil {
+		hc.TCPConnect = &healthbus.ConnectLatency{
+			Samples:    r.Connect.Samples,
+			P50Seconds: r.Connect.P50Seconds,
+			P95Seconds: r.Connect.P95Seconds,
+			P99Seconds: r.Connect.P99Seconds,
+		}
+	}
+
+	// A check up over some families but not others is degraded rather
+	// than down; it still serves the clients of the families that work.
+	if len(r.Families) > 0 {
+		hc.Families = make(map[string]healthbus.Status, len(r.Families))
+
+		var down []string
+		for _, f := range r.Families {
+			hc.Families[f.Family] = healthbus.StatusHealthy
+			if !f.Success {
+				hc.Families[f.Family] = healthbus.StatusDown
+				down = append(down, f.Family)
+			}
+		}
+
+		if len(down) > 0 && len(down) < len(r.Families) {
+			hc.Status = healthbus.StatusDegraded
+			hc.DegradedReason = strings.Join(down, ", ") + " down"
+		}
+	}
+
+	for _, s := range r.Steps {
+		hc.Steps = append(hc.Steps, healthbus.StepTiming{
+			Name:            s.Name,
+			Success:         s.Success,
+			StatusCode:      s.StatusCode,
+			DurationSeconds: s.DurationSeconds,
+			Error:           s.Error,
+		})
+	}
+
+	return hc
+}
@@ -0,0 +1,398 @@
+// Package prometheusstore implements the health check store using the
+// Prometheus HTTP API and blackbox exporter probe_* metrics. It also serves
+// raw metric queries for prometheusbus, history for forecastbus, probe
+// statistics for comparebus and volume usage for storagebus.
+package prometheusstore
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"health-api/business/domain/comparebus"
+	"health-api/business/domain/forecastbus"
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/prometheusbus"
+	"health-api/business/domain/storagebus"
+	"health-api/foundation/logger"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// Set of probe metric queries used to populate health checks.
+const (
+	querySuccess   = `probe_success`
+	queryDuration  = `probe_duration_seconds`
+	queryHTTPCode  = `probe_http_status_code`
+	querySSLExpiry = `(probe_ssl_earliest_cert_expiry - time()) / 86400`
+	queryDNSLookup = `probe_dns_lookup_time_seconds`
+)
+
+// Set of probe statistics queries over a window, formatted with the
+// instance selector and the range. A target probed from several regions
+// has a series per region: rates are averaged over them, and latencies
+// take the slowest region.
+const (
+	statsSuccess  = `avg(avg_over_time(probe_success{%s}[%s]))`
+	statsSamples  = `sum(count_over_time(probe_success{%s}[%s]))`
+	statsAvg      = `avg(avg_over_time(probe_duration_seconds{%s}[%s]))`
+	statsQuantile = `max(quantile_over_time(%g, probe_duration_seconds{%s}[%s]))`
+	statsMax      = `max(max_over_time(probe_duration_seconds{%s}[%s]))`
+)
+
+// Set of kubelet volume usage queries, one series per claim. A claim
+// mounted on several nodes reports from each; they agree, so max picks one.
+const (
+	volumeCapacity   = `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_capacity_bytes)`
+	volumeUsed       = `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_used_bytes)`
+	volumeAvailable  = `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_available_bytes)`
+	volumeInodes     = `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_inodes)`
+	volumeInodesUsed = `max by (namespace, persistentvolumeclaim) (kubelet_volume_stats_inodes_used)`
+)
+
+// Store implements healthbus.Storer using Prometheus.
+type Store struct {
+	log    *logger.Logger
+	client api.Client
+	api    v1.API
+}
+
+// NewStore creates a new Prometheus-backed health check store.
+func NewStore(log *logger.Logger, prometheusURL string, transport http.RoundTripper) (*Store, error) {
+	client, err := api.NewClient(api.Config{
+		Address: prometheusURL,
+		Client: &http.Client{
+			Transport: transport,
+			Timeout:   30 * time.Second,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating prometheus client: %w", err)
+	}
+
+	return &Store{
+		log:    log,
+		client: client,
+		api:    v1.NewAPI(client),
+	}, nil
+}
+
+// QueryHealthChecks retrieves all health checks from probe_* metrics.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	now := time.Now()
+
+	success, err := s.queryVector(ctx, querySuccess, now)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]healthbus.HealthCheck, 0, len(success))
+	index := make(map[string]int, len(success))
+
+	for _, sample := range success {
+		target := string(sample.Metric["instance"])
+		if target == "" {
+			continue
+		}
+
+		status := healthbus.StatusDown
+		if sample.Value == 1 {
+			status = healthbus.StatusHealthy
+		}
+
+		index[target] = len(checks)
+		checks = append(checks, healthbus.HealthCheck{
+			Target:      target,
+			Status:      status,
+			LastChecked: sample.Timestamp.Time(),
+			Probe:       string(sample.Metric["probe"]),
+			Namespace:   string(sample.Metric["namespace"]),
+		})
+	}
+
+	// Latency details are best effort; a failed query leaves the fields empty.
+	enrich := []struct {
+		query string
+		apply func(check *healthbus.HealthCheck, v float64)
+	}{
+		{queryDuration, func(c *healthbus.HealthCheck, v float64) { c.DurationSeconds = v }},
+		{queryHTTPCode, func(c *healthbus.HealthCheck, v float64) { c.HTTPStatusCode = int(v) }},
+		{querySSLExpiry, func(c *healthbus.HealthCheck, v float64) { c.SSLExpiryDays = v }},
+		{queryDNSLookup, func(c *healthbus.HealthCheck, v float64) { c.DNSLookupSeconds = v }},
+	}
+
+	for _, e := range enrich {
+		vector, err := s.queryVector(ctx, e.query, now)
+		if err != nil {
+			s.log.Warn(ctx, "prometheusstore", "query", e.query, "error", err)
+			continue
+		}
+
+		for _, sample := range vector {
+			i, ok := index[string(sample.Metric["instance"])]
+			if !ok {
+				continue
+			}
+			e.apply(&checks[i], float64(sample.Value))
+		}
+	}
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget retrieves a specific health check by target.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	checks, err := s.QueryHealthChecks(ctx)
+	if err != nil {
+		return healthbus.HealthCheck{}, err
+	}
+
+	for _, check := range checks {
+		if check.Target == target {
+			return check, nil
+		}
+	}
+
+	return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+}
+
+// QueryAlerts retrieves the active alerts known to Prometheus.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	result, err := s.api.Alerts(ctx)
+	if err != nil {
+		return healthbus.AlertSummary{}, fmt.Errorf("querying alerts: %w", err)
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, a := range result.Alerts {
+		alert := healthbus.Alert{
+			Title:       string(a.Labels[model.AlertNameLabel]),
+			State:       string(a.State),
+			Labels:      labelSetToMap(a.Labels),
+			Annotations: labelSetToMap(a.Annotations),
+			Value:       a.Value,
+		}
+		if !a.ActiveAt.IsZero() {
+			alert.ActiveAt = a.ActiveAt.Format(time.RFC3339)
+		}
+
+		summary.Alerts = append(summary.Alerts, alert)
+		summary.Total++
+
+		switch a.State {
+		case v1.AlertStateFiring:
+			summary.Firing++
+		case v1.AlertStatePending:
+			summary.Pending++
+		case v1.AlertStateInactive:
+			summary.Normal++
+		}
+	}
+
+	return summary, nil
+}
+
+// Check verifies that Prometheus is reachable and ready to serve queries.
+func (s *Store) Check(ctx context.Context) error {
+	u := s.client.URL("/-/ready", nil)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating ready request: %w", err)
+	}
+
+	resp, _, err := s.client.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("querying prometheus ready: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// QueryMetric runs an instant query and returns the resulting samples.
+func (s *Store) QueryMetric(ctx context.Context, query string, at time.Time) ([]prometheusbus.Sample, error) {
+	vector, err := s.queryVector(ctx, query, at)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]prometheusbus.Sample, len(vector))
+	for i, sample := range vector {
+		samples[i] = prometheusbus.Sample{
+			Labels:    labelSetToMap(model.LabelSet(sample.Metric)),
+			Value:     float64(sample.Value),
+			Timestamp: sample.Timestamp.Time(),
+		}
+	}
+
+	return samples, nil
+}
+
+// QueryRange runs a range query and returns the resulting series.
+func (s *Store) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]forecastbus.Series, error) {
+	result, warnings, err := s.api.QueryRange(ctx, query, v1.Range{Start: start, End: end, Step: step})
+	if err != nil {
+		return nil, fmt.Errorf("querying range %q: %w", query, err)
+	}
+
+	if len(warnings) > 0 {
+		s.log.Warn(ctx, "prometheusstore", "query", query, "warnings", warnings)
+	}
+
+	matrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("query %q returned %s, expected matrix", query, result.Type())
+	}
+
+	series := make([]forecastbus.Series, len(matrix))
+	for i, stream := range matrix {
+		points := make([]forecastbus.Point, len(stream.Values))
+		for j, v := range stream.Values {
+			points[j] = forecastbus.Point{Time: v.Timestamp.Time(), Value: float64(v.Value)}
+		}
+
+		series[i] = forecastbus.Series{
+			Labels: labelSetToMap(model.LabelSet(stream.Metric)),
+			Points: points,
+		}
+	}
+
+	return series, nil
+}
+
+// QueryStats summarizes the probes of the target over the window ending
+// at at.
+func (s *Store) QueryStats(ctx context.Context, target string, window time.Duration, at time.Time) (comparebus.Stats, error) {
+	selector := "instance=" + strconv.Quote(target)
+	rng := model.Duration(window).String()
+
+	var stats comparebus.Stats
+
+	samples, err := s.queryScalar(ctx, fmt.Sprintf(statsSamples, selector, rng), at)
+	if err != nil || samples == 0 {
+		return stats, err
+	}
+	stats.Samples = int(samples)
+
+	queries := []struct {
+		query string
+		dest  *float64
+	}{
+		{fmt.Sprintf(statsSuccess, selector, rng), &stats.SuccessRate},
+		{fmt.Sprintf(statsAvg, selector, rng), &stats.Latency.Avg},
+		{fmt.Sprintf(statsQuantile, 0.5, selector, rng), &stats.Latency.P50},
+		{fmt.Sprintf(statsQuantile, 0.95, selector, rng), &stats.Latency.P95},
+		{fmt.Sprintf(statsQuantile, 0.99, selector, rng), &stats.Latency.P99},
+		{fmt.Sprintf(statsMax, selector, rng), &stats.Latency.Max},
+	}
+
+	for _, q := range queries {
+		v, err := s.queryScalar(ctx, q.query, at)
+		if err != nil {
+			return comparebus.Stats{}, err
+		}
+		*q.dest = v
+	}
+
+	return stats, nil
+}
+
+// QueryVolumes reports the usage of every PersistentVolumeClaim the
+// kubelets report at at.
+func (s *Store) QueryVolumes(ctx context.Context, at time.Time) ([]storagebus.VolumeStats, error) {
+	type key struct{ namespace, pvc string }
+
+	volumes := make(map[key]*storagebus.VolumeStats)
+	var order []key
+
+	queries := []struct {
+		query string
+		dest  func(v *storagebus.VolumeStats) *float64
+	}{
+		{volumeCapacity, func(v *storagebus.VolumeStats) *float64 { return &v.CapacityBytes }},
+		{volumeUsed, func(v *storagebus.VolumeStats) *float64 { return &v.UsedBytes }},
+		{volumeAvailable, func(v *storagebus.VolumeStats) *float64 { return &v.AvailableBytes }},
+		{volumeInodes, func(v *storagebus.VolumeStats) *float64 { return &v.Inodes }},
+		{volumeInodesUsed, func(v *storagebus.VolumeStats) *float64 { return &v.InodesUsed }},
+	}
+
+	for _, q := range queries {
+		vector, err := s.queryVector(ctx, q.query, at)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sample := range vector {
+			k := key{string(sample.Metric["namespace"]), string(sample.Metric["persistentvolumeclaim"])}
+
+			v, ok := volumes[k]
+			if !ok {
+				v = &storagebus.VolumeStats{Namespace: k.namespace, PVC: k.pvc}
+				volumes[k] = v
+				order = append(order, k)
+			}
+			*q.dest(v) = float64(sample.Value)
+		}
+	}
+
+	stats := make([]storagebus.VolumeStats, len(order))
+	for i, k := range order {
+		stats[i] = *volumes[k]
+	}
+
+	return stats, nil
+}
+
+// Helper functions
+
+// queryScalar runs an aggregating query, which yields one sample or none
+// when nothing matched. None reads as zero.
+func (s *Store) queryScalar(ctx context.Context, query string, ts time.Time) (float64, error) {
+	vector, err := s.queryVector(ctx, query, ts)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(vector) == 0 {
+		return 0, nil
+	}
+
+	return float64(vector[0].Value), nil
+}
+
+func (s *Store) queryVector(ctx context.Context, query string, ts time.Time) (model.Vector, error) {
+	result, warnings, err := s.api.Query(ctx, query, ts)
+	if err != nil {
+		return nil, fmt.Errorf("querying %q: %w", query, err)
+	}
+
+	if len(warnings) > 0 {
+		s.log.Warn(ctx, "prometheusstore", "query", query, "warnings", warnings)
+	}
+
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("query %q returned %s, expected vector", query, result.Type())
+	}
+
+	return vector, nil
+}
+
+func labelSetToMap(ls model.LabelSet) map[string]string {
+	result := make(map[string]string, len(ls))
+	for k, v := range ls {
+		result[string(k)] = string(v)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package prometheusstore_test
+
+import (
+	"os"
+	"testing"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/healthbus/stores/prometheusstore"
+	"health-api/business/domain/healthbus/storetest"
+	"health-api/foundation/logger"
+)
+
+func Test_Conformance(t *testing.T) {
+	newStore := func(t *testing.T, url string) healthbus.Storer {
+		log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+
+		st, err := prometheusstore.NewStore(log, url, nil)
+		if err != nil {
+			t.Fatalf("Should be able to construct the store: %s", err)
+		}
+		return st
+	}
+
+	want := storetest.Expect{
+		Checks: map[string]healthbus.Status{
+			"https://shop.example.com": healthbus.StatusDown,
+			"https://api.example.com":  healthbus.StatusHealthy,
+			"https://docs.example.com": healthbus.StatusHealthy,
+		},
+		Namespaces: map[string]string{
+			"https://shop.example.com": "shop",
+			"https://api.example.com":  "platform",
+		},
+		Alerts: healthbus.AlertSummary{
+			Total:   2,
+			Firing:  1,
+			Pending: 1,
+		},
+	}
+
+	storetest.Run(t, newStore, "testdata/recording.json", want)
+}
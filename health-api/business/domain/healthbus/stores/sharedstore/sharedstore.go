@@ -0,0 +1,85 @@
+// Package sharedstore decorates a health check store so concurrent
+// identical queries share one backend round-trip. Stores return unscoped
+// results and healthbus applies the caller's tenant afterwards, so callers
+// of different tenants can share a query.
+package sharedstore
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/singleflight"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var sharedQueries = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "health_api_store_shared_queries_total",
+		Help: "Total number of store queries answered by a concurrent identical query",
+	},
+	[]string{"method"},
+)
+
+// Store implements healthbus.Storer by deduplicating concurrent calls to
+// another store.
+type Store struct {
+	storer healthbus.Storer
+	checks singleflight.Group[[]healthbus.HealthCheck]
+	check  singleflight.Group[healthbus.HealthCheck]
+	alerts singleflight.Group[healthbus.AlertSummary]
+}
+
+// NewStore wraps storer. Each shared query is bounded by timeout rather
+// than by the deadline of whichever caller started it.
+func NewStore(storer healthbus.Storer, timeout time.Duration) *Store {
+	s := Store{
+		storer: storer,
+	}
+	s.checks.Timeout = timeout
+	s.check.Timeout = timeout
+	s.alerts.Timeout = timeout
+
+	return &s
+}
+
+// QueryHealthChecks retrieves all health checks, joining a query already
+// in flight. Each caller gets its own copy of the slice.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	checks, shared, err := s.checks.Do(ctx, "", s.storer.QueryHealthChecks)
+	s.observe("QueryHealthChecks", shared)
+
+	return slices.Clone(checks), err
+}
+
+// QueryHealthCheckByTarget retrieves a single health check, joining a
+// query for the same target already in flight.
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	check, shared, err := s.check.Do(ctx, target, func(ctx context.Context) (healthbus.HealthCheck, error) {
+		return s.storer.QueryHealthCheckByTarget(ctx, target)
+	})
+	s.observe("QueryHealthCheckByTarget", shared)
+
+	return check, err
+}
+
+// QueryAlerts retrieves alerts, joining a query already in flight. Each
+// caller gets its own copy of the alert slice.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	summary, shared, err := s.alerts.Do(ctx, "", s.storer.QueryAlerts)
+	s.observe("QueryAlerts", shared)
+
+	summary.Alerts = slices.Clone(summary.Alerts)
+	summary.Groups = slices.Clone(summary.Groups)
+
+	return summary, err
+}
+
+func (s *Store) observe(method string, shared bool) {
+	if shared {
+		sharedQueries.WithLabelValues(method).Inc()
+	}
+}
@@ -0,0 +1,143 @@
+// Package storagestore implements the health check store from the usage of
+// persistent volumes, so claims filling up count in the same summary as
+// probed targets.
+package storagestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/storagebus"
+)
+
+// probe is the probe name reported for volume checks.
+const probe = "storage"
+
+// Store implements healthbus.Storer using the volume usage of storagebus.
+type Store struct {
+	storageBus *storagebus.Business
+}
+
+// NewStore creates a volume-backed health check store.
+func NewStore(storageBus *storagebus.Business) *Store {
+	return &Store{
+		storageBus: storageBus,
+	}
+}
+
+// QueryHealthChecks returns a health check for every claim.
+func (s *Store) QueryHealthChecks(ctx context.Context) ([]healthbus.HealthCheck, error) {
+	volumes, err := s.storageBus.Query(ctx, storagebus.QueryFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	checks := make([]healthbus.HealthCheck, len(volumes))
+	for i, v := range volumes {
+		checks[i] = toHealthCheck(v, now)
+	}
+
+	return checks, nil
+}
+
+// QueryHealthCheckByTarget returns the health check of a claim, named as
+// "pvc/<namespace>/<name>".
+func (s *Store) QueryHealthCheckByTarget(ctx context.Context, target string) (healthbus.HealthCheck, error) {
+	parts := strings.Split(target, "/")
+	if len(parts) != 3 || parts[0] != "pvc" {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	v, err := s.storageBus.QueryByClaim(ctx, parts[1], parts[2])
+	if err != nil {
+		return healthbus.HealthCheck{}, fmt.Errorf("%w: %s", healthbus.ErrNotFound, target)
+	}
+
+	return toHealthCheck(v, time.Now()), nil
+}
+
+// QueryAlerts reports a firing VolumeFillingUp alert for every claim past
+// its warning threshold, with the claim's status as severity.
+func (s *Store) QueryAlerts(ctx context.Context) (healthbus.AlertSummary, error) {
+	volumes, err := s.storageBus.Query(ctx, storagebus.QueryFilter{})
+	if err != nil {
+		return healthbus.AlertSummary{}, err
+	}
+
+	summary := healthbus.AlertSummary{
+		Alerts: []healthbus.Alert{},
+	}
+
+	for _, v := range volumes {
+		if v.Status == storagebus.StatusOK {
+			continue
+		}
+
+		target := targetName(v)
+		summary.Alerts = append(summary.Alerts, healthbus.Alert{
+			UID:   target,
+			Title: "VolumeFillingUp",
+			State: "firing",
+			Labels: map[string]string{
+				"alertname": "VolumeFillingUp",
+				"instance":  target,
+				"namespace": v.Namespace,
+				"severity":  v.Status,
+			},
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is %s full", target, percent(max(v.UsedPercent, v.InodesUsedPercent))),
+			},
+		})
+		summary.Total++
+		summary.Firing++
+	}
+
+	return summary, nil
+}
+
+// =============================================================================
+
+func targetName(v storagebus.Volume) string {
+	return "pvc/" + v.Namespace + "/" + v.PVC
+}
+
+// toHealthCheck maps a volume to a health check: degraded past its warning
+// threshold and down past its critical one, with the usage as reason.
+func toHealthCheck(v storagebus.Volume, now time.Time) healthbus.HealthCheck {
+	check := healthbus.HealthCheck{
+		Target:      targetName(v),
+		Status:      healthbus.StatusHealthy,
+		LastChecked: now,
+		Probe:       probe,
+		Namespace:   v.Namespace,
+		Instance:    v.PVC,
+	}
+
+	switch v.Status {
+	case storagebus.StatusWarning:
+		check.Status = healthbus.StatusDegraded
+		check.DegradedReason = usage(v) + ", warning at " + percent(v.Warning)
+	case storagebus.StatusCritical:
+		check.Status = healthbus.StatusDown
+	}
+
+	return check
+}
+
+// usage describes what fills the volume, e.g. "92% used" or "95% inodes
+// used".
+func usage(v storagebus.Volume) string {
+	if v.InodesUsedPercent > v.UsedPercent {
+		return percent(v.InodesUsedPercent) + " inodes used"
+	}
+	return percent(v.UsedPercent) + " used"
+}
+
+func percent(p float64) string {
+	return fmt.Sprintf("%g%%", p)
+}
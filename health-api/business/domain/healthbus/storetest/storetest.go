@@ -0,0 +1,262 @@
+// Package storetest provides a conformance suite for healthbus.Storer
+// implementations, run against a backend that replays recorded responses.
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"health-api/business/domain/healthbus"
+)
+
+// Interaction is one recorded backend request and its response. A request
+// matches if the method and path are equal and every recorded form value,
+// taken from the query string or a form body, is present.
+type Interaction struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Form   map[string]string `json:"form,omitempty"`
+	Status int               `json:"status"`
+	Body   json.RawMessage   `json:"body"`
+}
+
+// Replay starts a backend that answers with the interactions recorded in
+// the JSON file at path. Unrecorded requests fail the test. Kubernetes
+// watches are held open until the client goes away, as nothing in a
+// recording changes.
+func Replay(t *testing.T, path string) *httptest.Server {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading recording: %s", err)
+	}
+
+	var recording []Interaction
+	if err := json.Unmarshal(data, &recording); err != nil {
+		t.Fatalf("decoding recording %s: %s", path, err)
+	}
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parsing form of %s %s: %s", r.Method, r.URL.Path, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.Form.Get("watch") == "true" {
+			<-r.Context().Done()
+			return
+		}
+
+		for _, in := range recording {
+			if !in.matches(r) {
+				continue
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(in.Status)
+			w.Write(in.Body)
+			return
+		}
+
+		t.Errorf("unrecorded request: %s %s %v", r.Method, r.URL.Path, r.Form)
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(h))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func (in Interaction) matches(r *http.Request) bool {
+	if in.Method != r.Method || in.Path != r.URL.Path {
+		return false
+	}
+
+	for k, v := range in.Form {
+		if r.Form.Get(k) != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Failing starts a backend that answers every request with a server error.
+func Failing(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// =============================================================================
+
+// Expect describes what a store must report for its recording.
+type Expect struct {
+	// Checks maps each target in the recording to its status.
+	Checks map[string]healthbus.Status
+
+	// Namespaces maps targets to the namespace they must carry.
+	Namespaces map[string]string
+
+	// Alerts holds the expected alert counts; the Alerts slice is ignored.
+	Alerts healthbus.AlertSummary
+}
+
+// NewStoreFunc constructs the store under test against the backend at url.
+type NewStoreFunc func(t *testing.T, url string) healthbus.Storer
+
+// Run runs the conformance suite: newStore is called once against the
+// recording and once against a failing backend.
+func Run(t *testing.T, newStore NewStoreFunc, recording string, want Expect) {
+	t.Run("QueryHealthChecks", func(t *testing.T) {
+		st := newStore(t, Replay(t, recording).URL)
+		queryHealthChecks(t, st, want)
+	})
+
+	t.Run("QueryHealthCheckByTarget", func(t *testing.T) {
+		st := newStore(t, Replay(t, recording).URL)
+		queryHealthCheckByTarget(t, st, want)
+	})
+
+	t.Run("QueryAlerts", func(t *testing.T) {
+		st := newStore(t, Replay(t, recording).URL)
+		queryAlerts(t, st, want)
+	})
+
+	t.Run("BackendError", func(t *testing.T) {
+		st := newStore(t, Failing(t).URL)
+		backendError(t, st)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		st := newStore(t, Replay(t, recording).URL)
+		canceled(t, st)
+	})
+}
+
+func queryHealthChecks(t *testing.T, st healthbus.Storer, want Expect) {
+	checks, err := st.QueryHealthChecks(context.Background())
+	if err != nil {
+		t.Fatalf("Should be able to query health checks: %s", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, check := range checks {
+		if seen[check.Target] {
+			t.Errorf("Should report target %s once", check.Target)
+		}
+		seen[check.Target] = true
+
+		status, ok := want.Checks[check.Target]
+		if !ok {
+			t.Errorf("Should not report unexpected target %s", check.Target)
+			continue
+		}
+
+		if check.Status != status {
+			t.Errorf("Should report %s as %s, got %s", check.Target, status, check.Status)
+		}
+
+		if ns, ok := want.Namespaces[check.Target]; ok && check.Namespace != ns {
+			t.Errorf("Should report %s in namespace %q, got %q", check.Target, ns, check.Namespace)
+		}
+
+		if check.LastChecked.IsZero() {
+			t.Errorf("Should report when %s was last checked", check.Target)
+		}
+	}
+
+	for target := range want.Checks {
+		if !seen[target] {
+			t.Errorf("Should report target %s", target)
+		}
+	}
+}
+
+func queryHealthCheckByTarget(t *testing.T, st healthbus.Storer, want Expect) {
+	for target, status := range want.Checks {
+		check, err := st.QueryHealthCheckByTarget(context.Background(), target)
+		if err != nil {
+			t.Errorf("Should be able to query %s: %s", target, err)
+			continue
+		}
+
+		if check.Target != target {
+			t.Errorf("Should return the check of %s, got %s", target, check.Target)
+		}
+
+		if check.Status != status {
+			t.Errorf("Should report %s as %s, got %s", target, status, check.Status)
+		}
+	}
+
+	if _, err := st.QueryHealthCheckByTarget(context.Background(), "https://unknown.invalid"); err == nil {
+		t.Error("Should fail for an unknown target")
+	}
+}
+
+func queryAlerts(t *testing.T, st healthbus.Storer, want Expect) {
+	summary, err := st.QueryAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("Should be able to query alerts: %s", err)
+	}
+
+	if summary.Alerts == nil {
+		t.Error("Should return an empty rather than nil alert list")
+	}
+
+	if summary.Total != len(summary.Alerts) {
+		t.Errorf("Should count every alert: total %d, alerts %d", summary.Total, len(summary.Alerts))
+	}
+
+	if sum := summary.Firing + summary.Pending + summary.Normal; sum > summary.Total {
+		t.Errorf("Should not count more states (%d) than alerts (%d)", sum, summary.Total)
+	}
+
+	got := [4]int{summary.Total, summary.Firing, summary.Pending, summary.Normal}
+	exp := [4]int{want.Alerts.Total, want.Alerts.Firing, want.Alerts.Pending, want.Alerts.Normal}
+	if got != exp {
+		t.Errorf("Should count total/firing/pending/normal as %v, got %v", exp, got)
+	}
+}
+
+func backendError(t *testing.T, st healthbus.Storer) {
+	ctx := context.Background()
+
+	if _, err := st.QueryHealthChecks(ctx); err == nil {
+		t.Error("Should fail to query health checks when the backend fails")
+	}
+
+	if _, err := st.QueryHealthCheckByTarget(ctx, "https://example.com"); err == nil {
+		t.Error("Should fail to query a health check when the backend fails")
+	}
+
+	if _, err := st.QueryAlerts(ctx); err == nil {
+		t.Error("Should fail to query alerts when the backend fails")
+	}
+}
+
+func canceled(t *testing.T, st healthbus.Storer) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := st.QueryHealthChecks(ctx); err == nil {
+		t.Error("Should fail to query health checks with a canceled context")
+	}
+
+	if _, err := st.QueryAlerts(ctx); err == nil {
+		t.Error("Should fail to query alerts with a canceled context")
+	}
+}
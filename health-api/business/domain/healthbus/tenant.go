@@ -0,0 +1,51 @@
+package healthbus
+
+import "health-api/business/sdk/tenant"
+
+// scopeChecks drops the checks outside the caller's namespaces.
+func scopeChecks(s tenant.Scope, checks []HealthCheck) []HealthCheck {
+	return tenant.Filter(s, checks, func(c HealthCheck) string { return c.Namespace })
+}
+
+// scopeAlerts drops the alerts outside the caller's namespaces, read from
+// the alert's namespace label, and recounts the summary.
+func scopeAlerts(s tenant.Scope, summary AlertSummary) AlertSummary {
+	if !s.Restricted() {
+		return summary
+	}
+
+	alerts := tenant.Filter(s, summary.Alerts, func(a Alert) string { return a.Labels["namespace"] })
+
+	return countAlerts(alerts)
+}
+
+// countAlerts builds the summary of the alerts, counting the severities set
+// by applySeverity.
+func countAlerts(alerts []Alert) AlertSummary {
+	summary := AlertSummary{
+		Total:  len(alerts),
+		Alerts: alerts,
+	}
+
+	for _, a := range alerts {
+		switch a.State {
+		case "firing":
+			summary.Firing++
+		case "pending":
+			summary.Pending++
+		case "inactive", "normal":
+			summary.Normal++
+		}
+
+		switch a.Severity {
+		case SeverityCritical:
+			summary.Critical++
+		case SeverityWarning:
+			summary.Warning++
+		case SeverityInfo:
+			summary.Info++
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,40 @@
+package historybus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(healthbus.DomainName, healthbus.ActionStatusChanged, b.actionStatusChanged)
+	}
+}
+
+// actionStatusChanged records every status change of a target.
+func (b *Business) actionStatusChanged(ctx context.Context, data delegate.Data) error {
+	var params healthbus.ActionStatusChangedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	c := Change{
+		Target:    params.Target,
+		Namespace: params.Check.Namespace,
+		From:      params.From,
+		To:        params.To,
+		At:        params.At,
+	}
+
+	if err := b.Record(ctx, c); err != nil {
+		return fmt.Errorf("record change: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Package historybus provides business logic for the persisted history of
+// target status changes.
+package historybus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// ErrNotFound is returned when no change has been recorded for a target.
+var ErrNotFound = errors.New("change not found")
+
+// pruneInterval bounds how often changes older than the retention are
+// removed.
+const pruneInterval = time.Hour
+
+// Storer defines the interface for change history data access.
+type Storer interface {
+	Create(ctx context.Context, c Change) error
+	Query(ctx context.Context, filter QueryFilter) ([]Change, error)
+	QueryLatestByTarget(ctx context.Context, target string) (Change, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// Business manages the status change history.
+type Business struct {
+	log       *logger.Logger
+	delegate  *delegate.Delegate
+	storer    Storer
+	retention time.Duration
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// NewBusiness creates a new history business layer and registers for health
+// status changes. Changes older than retention are discarded; zero keeps
+// them forever.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, retention time.Duration) *Business {
+	b := Business{
+		log:       log,
+		delegate:  delegate,
+		storer:    storer,
+		retention: retention,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Record adds a change to the history. A target observed for the first
+// time since startup is only recorded if its status differs from the last
+// recorded one, so restarts do not fill the history with non-changes.
+func (b *Business) Record(ctx context.Context, c Change) error {
+	if c.From == "" {
+		last, err := b.storer.QueryLatestByTarget(ctx, c.Target)
+		switch {
+		case err == nil:
+			if last.To == c.To {
+				return nil
+			}
+			c.From = last.To
+		case !errors.Is(err, ErrNotFound):
+			return fmt.Errorf("query latest: %w", err)
+		}
+	}
+
+	c.ID = newID()
+	c.At = c.At.UTC()
+
+	if err := b.storer.Create(ctx, c); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+
+	b.prune(ctx, c.At)
+
+	return nil
+}
+
+// Query retrieves the changes matching the filter visible to the caller,
+// oldest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Change, error) {
+	changes, err := b.storer.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return tenant.Filter(tenant.Get(ctx), changes, func(c Change) string { return c.Namespace }), nil
+}
+
+// QueryLatestFailure retrieves the latest change of the target to down or
+// degraded.
+func (b *Business) QueryLatestFailure(ctx context.Context, target string) (Change, error) {
+	changes, err := b.Query(ctx, QueryFilter{Target: &target})
+	if err != nil {
+		return Change{}, fmt.Errorf("query latest failure: %w", err)
+	}
+
+	for i := len(changes) - 1; i >= 0; i-- {
+		switch changes[i].To {
+		case healthbus.StatusDown, healthbus.StatusDegraded:
+			return changes[i], nil
+		}
+	}
+
+	return Change{}, fmt.Errorf("query latest failure: target[%s]: %w", target, ErrNotFound)
+}
+
+// StatusesAt returns the status of every target visible to the caller as
+// of t: the status of its latest change at or before t. Targets without a
+// change by then are left out.
+func (b *Business) StatusesAt(ctx context.Context, t time.Time) (map[string]healthbus.Status, error) {
+	changes, err := b.Query(ctx, QueryFilter{Until: &t})
+	if err != nil {
+		return nil, fmt.Errorf("statuses at: %w", err)
+	}
+
+	statuses := make(map[string]healthbus.Status)
+	for _, c := range changes {
+		statuses[c.Target] = c.To
+	}
+
+	return statuses, nil
+}
+
+// prune removes changes older than the retention, at most once per
+// pruneInterval. Failures are logged; the next change retries.
+func (b *Business) prune(ctx context.Context, now time.Time) {
+	if b.retention <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	if now.Sub(b.lastPruned) < pruneInterval {
+		b.mu.Unlock()
+		return
+	}
+	b.lastPruned = now
+	b.mu.Unlock()
+
+	n, err := b.storer.DeleteBefore(ctx, now.Add(-b.retention))
+	if err != nil {
+		b.log.Error(ctx, "historybus", "status", "prune failed", "error", err)
+		return
+	}
+
+	if n > 0 {
+		b.log.Info(ctx, "historybus", "status", "history pruned", "removed", n)
+	}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
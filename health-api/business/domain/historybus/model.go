@@ -0,0 +1,44 @@
+package historybus
+
+import (
+	"time"
+
+	"health-api/business/domain/healthbus"
+)
+
+// Change records a target moving from one status to another. From is empty
+// when the target was first observed.
+type Change struct {
+	ID        string           `json:"id"`
+	Target    string           `json:"target"`
+	Namespace string           `json:"namespace,omitempty"`
+	From      healthbus.Status `json:"from,omitempty"`
+	To        healthbus.Status `json:"to"`
+	At        time.Time        `json:"at"`
+}
+
+// QueryFilter holds the available fields a change query can be filtered
+// on. Since is exclusive and Until inclusive, so the Until of one query can
+// be passed as the Since of the next.
+type QueryFilter struct {
+	Target *string
+	Since  *time.Time
+	Until  *time.Time
+}
+
+// Match reports whether the change satisfies the filter.
+func (qf QueryFilter) Match(c Change) bool {
+	if qf.Target != nil && c.Target != *qf.Target {
+		return false
+	}
+
+	if qf.Since != nil && !c.At.After(*qf.Since) {
+		return false
+	}
+
+	if qf.Until != nil && c.At.After(*qf.Until) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,94 @@
+// Package historydb implements the change history store on top of jsondb.
+package historydb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"health-api/business/domain/historybus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements historybus.Storer.
+type Store struct {
+	log     *logger.Logger
+	changes *jsondb.Collection[historybus.Change]
+}
+
+// NewStore opens the history collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	changes, err := jsondb.NewCollection[historybus.Change](db, "history")
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+
+	return &Store{
+		log:     log,
+		changes: changes,
+	}, nil
+}
+
+// Create inserts a new change.
+func (s *Store) Create(ctx context.Context, c historybus.Change) error {
+	if err := s.changes.Insert(c.ID, c); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves changes matching the filter ordered oldest first.
+func (s *Store) Query(ctx context.Context, filter historybus.QueryFilter) ([]historybus.Change, error) {
+	var changes []historybus.Change
+	for _, c := range s.changes.All() {
+		if filter.Match(c) {
+			changes = append(changes, c)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].At.Before(changes[j].At)
+	})
+
+	return changes, nil
+}
+
+// QueryLatestByTarget retrieves the most recent change of the target.
+func (s *Store) QueryLatestByTarget(ctx context.Context, target string) (historybus.Change, error) {
+	var latest historybus.Change
+	found := false
+
+	for _, c := range s.changes.All() {
+		if c.Target == target && (!found || c.At.After(latest.At)) {
+			latest = c
+			found = true
+		}
+	}
+
+	if !found {
+		return historybus.Change{}, historybus.ErrNotFound
+	}
+
+	return latest, nil
+}
+
+// DeleteBefore removes changes recorded before the specified time and
+// returns how many were removed.
+func (s *Store) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	var n int
+	for _, c := range s.changes.All() {
+		if !c.At.Before(before) {
+			continue
+		}
+
+		if err := s.changes.Delete(c.ID); err != nil {
+			return n, fmt.Errorf("delete: id[%s]: %w", c.ID, err)
+		}
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package incidentbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/sdk/delegate"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "incident"
+
+// Set of delegate actions for incidents.
+const (
+	ActionOpened   = "opened"
+	ActionResolved = "resolved"
+)
+
+// ActionIncidentParms represents the parameters of the opened and resolved
+// actions.
+type ActionIncidentParms struct {
+	Incident Incident `json:"incident"`
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (ip *ActionIncidentParms) Marshal() ([]byte, error) {
+	return json.Marshal(ip)
+}
+
+// ActionIncidentData constructs the data for the opened or resolved action.
+func ActionIncidentData(action string, inc Incident) delegate.Data {
+	params := ActionIncidentParms{
+		Incident: inc,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    action,
+		RawParams: rawParams,
+	}
+}
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(healthbus.DomainName, healthbus.ActionStatusChanged, b.actionStatusChanged)
+	}
+}
+
+// actionStatusChanged opens an incident when a target goes down and resolves
+// it when the target recovers, even if only to a degraded state.
+func (b *Business) actionStatusChanged(ctx context.Context, data delegate.Data) error {
+	var params healthbus.ActionStatusChangedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	switch params.To {
+	case healthbus.StatusDown:
+		if _, err := b.Open(ctx, params.Target, params.Check.Namespace, params.At); err != nil {
+			return fmt.Errorf("open incident: %w", err)
+		}
+
+	case healthbus.StatusHealthy, healthbus.StatusDegraded:
+		if _, err := b.Resolve(ctx, params.Target, params.At); err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("resolve incident: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package incidentbus provides business logic for incidents, which coalesce
+// consecutive down states of a target into a single record.
+package incidentbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// ErrNotFound is returned when an incident does not exist.
+var ErrNotFound = errors.New("incident not found")
+
+// Storer defines the interface for incident data access.
+type Storer interface {
+	Create(ctx context.Context, inc Incident) error
+	Update(ctx context.Context, inc Incident) error
+	Query(ctx context.Context, filter QueryFilter) ([]Incident, error)
+	QueryByID(ctx context.Context, id string) (Incident, error)
+	QueryOpenByTarget(ctx context.Context, target string) (Incident, error)
+}
+
+// Business manages incidents.
+type Business struct {
+	log       *logger.Logger
+	delegate  *delegate.Delegate
+	storer    Storer
+	healthBus *healthbus.Business
+}
+
+// NewBusiness creates a new incident business layer and registers for
+// health status changes.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, healthBus *healthbus.Business) *Business {
+	b := Business{
+		log:       log,
+		delegate:  delegate,
+		storer:    storer,
+		healthBus: healthBus,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Open starts a new incident for the target unless one is already open. The
+// namespace scopes the incident to the tenants that own the target.
+func (b *Business) Open(ctx context.Context, target string, namespace string, at time.Time) (Incident, error) {
+	inc, err := b.storer.QueryOpenByTarget(ctx, target)
+	switch {
+	case err == nil:
+		return inc, nil
+	case !errors.Is(err, ErrNotFound):
+		return Incident{}, fmt.Errorf("query open: %w", err)
+	}
+
+	inc = Incident{
+		ID:        newID(),
+		Target:    target,
+		Namespace: namespace,
+		Status:    StatusOpen,
+		StartedAt: at,
+		Alerts:    b.relatedAlerts(ctx, target),
+	}
+
+	if err := b.storer.Create(ctx, inc); err != nil {
+		return Incident{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.log.Info(ctx, "incidentbus", "status", "incident opened", "id", inc.ID, "target", target)
+
+	b.notify(ctx, ActionOpened, inc)
+
+	return inc, nil
+}
+
+// Resolve closes the open incident for the target, if any.
+func (b *Business) Resolve(ctx context.Context, target string, at time.Time) (Incident, error) {
+	inc, err := b.storer.QueryOpenByTarget(ctx, target)
+	if err != nil {
+		return Incident{}, fmt.Errorf("query open: %w", err)
+	}
+
+	inc.Status = StatusResolved
+	inc.EndedAt = &at
+	inc.DurationSeconds = at.Sub(inc.StartedAt).Seconds()
+
+	if err := b.storer.Update(ctx, inc); err != nil {
+		return Incident{}, fmt.Errorf("update: %w", err)
+	}
+
+	b.log.Info(ctx, "incidentbus", "status", "incident resolved", "id", inc.ID, "target", target)
+
+	b.notify(ctx, ActionResolved, inc)
+
+	return inc, nil
+}
+
+// notify tells other domains that an incident was opened or resolved.
+func (b *Business) notify(ctx context.Context, action string, inc Incident) {
+	if b.delegate == nil {
+		return
+	}
+
+	if err := b.delegate.Call(ctx, ActionIncidentData(action, inc)); err != nil {
+		b.log.Error(ctx, "incidentbus", "status", "delegate call failed", "error", err)
+	}
+}
+
+// Query retrieves incidents matching the filter, newest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Incident, error) {
+	incs, err := b.storer.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	incs = tenant.Filter(tenant.Get(ctx), incs, func(inc Incident) string { return inc.Namespace })
+
+	now := time.Now().UTC()
+	for i := range incs {
+		if incs[i].Status == StatusOpen {
+			incs[i].DurationSeconds = now.Sub(incs[i].StartedAt).Seconds()
+		}
+	}
+
+	return incs, nil
+}
+
+// QueryByID retrieves the specified incident.
+func (b *Business) QueryByID(ctx context.Context, id string) (Incident, error) {
+	inc, err := b.storer.QueryByID(ctx, id)
+	if err != nil {
+		return Incident{}, fmt.Errorf("query: id[%s]: %w", id, err)
+	}
+
+	if !tenant.Get(ctx).Allows(inc.Namespace) {
+		return Incident{}, fmt.Errorf("query: id[%s]: %w", id, ErrNotFound)
+	}
+
+	if inc.Status == StatusOpen {
+		inc.DurationSeconds = time.Since(inc.StartedAt).Seconds()
+	}
+
+	return inc, nil
+}
+
+// relatedAlerts returns the titles of alerts labeled with the target.
+func (b *Business) relatedAlerts(ctx context.Context, target string) []string {
+	summary, err := b.healthBus.QueryAlerts(tenant.Unscoped(ctx), healthbus.AlertFilter{})
+	if err != nil {
+		b.log.Warn(ctx, "incidentbus", "status", "related alerts lookup failed", "error", err)
+		return nil
+	}
+
+	var titles []string
+	for _, alert := range summary.Alerts {
+		if alert.Labels["target"] == target || alert.Labels["instance"] == target {
+			titles = append(titles, alert.Title)
+		}
+	}
+
+	return titles
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package incidentbus
+
+import "time"
+
+// Set of incident states.
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+)
+
+// Incident represents a period during which a target was down.
+type Incident struct {
+	ID              string     `json:"id"`
+	Target          string     `json:"target"`
+	Namespace       string     `json:"namespace,omitempty"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	Alerts          []string   `json:"alerts,omitempty"`
+}
+
+// QueryFilter holds the available fields an incident query can be filtered
+// on. An incident matches a date range if any part of it overlaps the range.
+type QueryFilter struct {
+	Target    *string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// Match reports whether the incident satisfies the filter.
+func (qf QueryFilter) Match(inc Incident) bool {
+	if qf.Target != nil && inc.Target != *qf.Target {
+		return false
+	}
+
+	if qf.EndDate != nil && inc.StartedAt.After(*qf.EndDate) {
+		return false
+	}
+
+	if qf.StartDate != nil && inc.EndedAt != nil && inc.EndedAt.Before(*qf.StartDate) {
+		return false
+	}
+
+	return true
+}
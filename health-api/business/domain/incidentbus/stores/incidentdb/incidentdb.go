@@ -0,0 +1,90 @@
+// Package incidentdb implements the incident store on top of jsondb.
+package incidentdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"health-api/business/domain/incidentbus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements incidentbus.Storer.
+type Store struct {
+	log       *logger.Logger
+	incidents *jsondb.Collection[incidentbus.Incident]
+}
+
+// NewStore opens the incidents collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	incidents, err := jsondb.NewCollection[incidentbus.Incident](db, "incidents")
+	if err != nil {
+		return nil, fmt.Errorf("opening incidents: %w", err)
+	}
+
+	return &Store{
+		log:       log,
+		incidents: incidents,
+	}, nil
+}
+
+// Create inserts a new incident.
+func (s *Store) Create(ctx context.Context, inc incidentbus.Incident) error {
+	if err := s.incidents.Insert(inc.ID, inc); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an existing incident.
+func (s *Store) Update(ctx context.Context, inc incidentbus.Incident) error {
+	if err := s.incidents.Replace(inc.ID, inc); err != nil {
+		if errors.Is(err, jsondb.ErrNotFound) {
+			return incidentbus.ErrNotFound
+		}
+		return fmt.Errorf("replace: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves incidents matching the filter ordered newest first.
+func (s *Store) Query(ctx context.Context, filter incidentbus.QueryFilter) ([]incidentbus.Incident, error) {
+	var incs []incidentbus.Incident
+	for _, inc := range s.incidents.All() {
+		if filter.Match(inc) {
+			incs = append(incs, inc)
+		}
+	}
+
+	sort.Slice(incs, func(i, j int) bool {
+		return incs[i].StartedAt.After(incs[j].StartedAt)
+	})
+
+	return incs, nil
+}
+
+// QueryByID retrieves the incident with the specified ID.
+func (s *Store) QueryByID(ctx context.Context, id string) (incidentbus.Incident, error) {
+	inc, ok := s.incidents.Get(id)
+	if !ok {
+		return incidentbus.Incident{}, incidentbus.ErrNotFound
+	}
+
+	return inc, nil
+}
+
+// QueryOpenByTarget retrieves the open incident for the target.
+func (s *Store) QueryOpenByTarget(ctx context.Context, target string) (incidentbus.Incident, error) {
+	for _, inc := range s.incidents.All() {
+		if inc.Target == target && inc.Status == incidentbus.StatusOpen {
+			return inc, nil
+		}
+	}
+
+	return incidentbus.Incident{}, incidentbus.ErrNotFound
+}
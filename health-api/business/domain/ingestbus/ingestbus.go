@@ -0,0 +1,113 @@
+// Package ingestbus provides business logic for health results pushed by
+// agents in networks Prometheus can't scrape.
+package ingestbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"health-api/foundation/logger"
+)
+
+// ErrNotFound is returned when no agent reported the target.
+var ErrNotFound = errors.New("result not found")
+
+// keepExpired is how long a result outlives its TTL. Until then it reports
+// the agent's missed heartbeat as down; after that it is dropped so results
+// of retired agents don't pile up.
+const keepExpired = 24 * time.Hour
+
+// Storer defines the interface for ingested result data access.
+type Storer interface {
+	UpsertMany(ctx context.Context, results []Result, expiredBefore time.Time) error
+	Query(ctx context.Context) ([]Result, error)
+	QueryByTarget(ctx context.Context, target string) ([]Result, error)
+}
+
+// Business manages ingested results.
+type Business struct {
+	log        *logger.Logger
+	storer     Storer
+	defaultTTL time.Duration
+}
+
+// NewBusiness creates a new ingest business layer. Reports without a TTL
+// stay valid for defaultTTL.
+func NewBusiness(log *logger.Logger, storer Storer, defaultTTL time.Duration) *Business {
+	return &Business{
+		log:        log,
+		storer:     storer,
+		defaultTTL: defaultTTL,
+	}
+}
+
+// Ingest stores the results of an agent report, replacing the agent's
+// previous result for each target, and drops results that expired long
+// ago.
+func (b *Business) Ingest(ctx context.Context, rpt Report) ([]Result, error) {
+	return b.IngestMany(ctx, []Report{rpt})
+}
+
+// IngestMany stores the results of several reports with a single write, as
+// for the agents of one remote-write request.
+func (b *Business) IngestMany(ctx context.Context, rpts []Report) ([]Result, error) {
+	now := time.Now().UTC()
+
+	results := make([]Result, 0, len(rpts))
+	for _, rpt := range rpts {
+		ttl := b.defaultTTL
+		if rpt.TTLSeconds > 0 {
+			ttl = time.Duration(rpt.TTLSeconds) * time.Second
+		}
+
+		for _, nr := range rpt.Results {
+			results = append(results, Result{
+				Agent:           rpt.Agent,
+				Region:          rpt.Region,
+				Target:          nr.Target,
+				Success:         nr.Success,
+				Probe:           nr.Probe,
+				DurationSeconds: nr.DurationSeconds,
+				HTTPStatusCode:  nr.HTTPStatusCode,
+				Error:           nr.Error,
+				ReceivedAt:      now,
+				ExpiresAt:       now.Add(ttl),
+			})
+		}
+	}
+
+	if err := b.storer.UpsertMany(ctx, results, now.Add(-keepExpired)); err != nil {
+		return nil, fmt.Errorf("upsert: reports[%d]: %w", len(rpts), err)
+	}
+
+	b.log.Debug(ctx, "ingestbus", "status", "reports ingested", "reports", len(rpts), "results", len(results))
+
+	return results, nil
+}
+
+// Query retrieves the latest result of every agent and target.
+func (b *Business) Query(ctx context.Context) ([]Result, error) {
+	results, err := b.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return results, nil
+}
+
+// QueryByTarget retrieves the latest result of every agent reporting the
+// target.
+func (b *Business) QueryByTarget(ctx context.Context, target string) ([]Result, error) {
+	results, err := b.storer.QueryByTarget(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("query: target[%s]: %w", target, err)
+	}
+
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return results, nil
+}
@@ -0,0 +1,43 @@
+package ingestbus
+
+import "time"
+
+// Report is a batch of check results pushed by an agent. TTLSeconds is how
+// long the results stay valid; the agent is expected to report again
+// within it. Region names the agent's vantage point, e.g. its cluster or
+// cloud region.
+type Report struct {
+	Agent      string      `json:"agent" validate:"required"`
+	Region     string      `json:"region"`
+	TTLSeconds int         `json:"ttl_seconds" validate:"min=0"`
+	Results    []NewResult `json:"results"`
+}
+
+// NewResult is the outcome of one check run by an agent.
+type NewResult struct {
+	Target          string  `json:"target" validate:"required"`
+	Success         bool    `json:"success"`
+	Probe           string  `json:"probe"`
+	DurationSeconds float64 `json:"duration_seconds" validate:"min=0"`
+	HTTPStatusCode  int     `json:"http_status_code"`
+	Error           string  `json:"error"`
+}
+
+// Result is the latest result an agent reported for a target.
+type Result struct {
+	Agent           string    `json:"agent"`
+	Region          string    `json:"region,omitempty"`
+	Target          string    `json:"target"`
+	Success         bool      `json:"success"`
+	Probe           string    `json:"probe,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	HTTPStatusCode  int       `json:"http_status_code,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	ReceivedAt      time.Time `json:"received_at"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// Expired reports whether the agent missed its heartbeat for the result.
+func (r Result) Expired(now time.Time) bool {
+	return now.After(r.ExpiresAt)
+}
@@ -0,0 +1,72 @@
+// Package ingestdb implements the ingest store on top of jsondb.
+package ingestdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/ingestbus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements ingestbus.Storer.
+type Store struct {
+	log     *logger.Logger
+	results *jsondb.Collection[ingestbus.Result]
+}
+
+// NewStore opens the ingested results collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	results, err := jsondb.NewCollection[ingestbus.Result](db, "ingest")
+	if err != nil {
+		return nil, fmt.Errorf("opening ingest: %w", err)
+	}
+
+	return &Store{
+		log:     log,
+		results: results,
+	}, nil
+}
+
+// UpsertMany stores the results, replacing each agent's previous result
+// for the target, and drops the results that expired before expiredBefore,
+// with a single write.
+func (s *Store) UpsertMany(ctx context.Context, results []ingestbus.Result, expiredBefore time.Time) error {
+	docs := make(map[string]ingestbus.Result, len(results))
+	for _, res := range results {
+		docs[key(res.Agent, res.Target)] = res
+	}
+
+	drop := func(res ingestbus.Result) bool {
+		return res.ExpiresAt.Before(expiredBefore)
+	}
+
+	if err := s.results.PutMany(docs, drop); err != nil {
+		return fmt.Errorf("put: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves every stored result ordered by agent and target.
+func (s *Store) Query(ctx context.Context) ([]ingestbus.Result, error) {
+	return s.results.All(), nil
+}
+
+// QueryByTarget retrieves the results for the target from every agent.
+func (s *Store) QueryByTarget(ctx context.Context, target string) ([]ingestbus.Result, error) {
+	var results []ingestbus.Result
+	for _, res := range s.results.All() {
+		if res.Target == target {
+			results = append(results, res)
+		}
+	}
+
+	return results, nil
+}
+
+func key(agent, target string) string {
+	return agent + "|" + target
+}
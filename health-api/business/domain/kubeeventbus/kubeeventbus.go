@@ -0,0 +1,105 @@
+// Package kubeeventbus provides business logic for correlating target
+// failures with Kubernetes Events.
+package kubeeventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"health-api/business/domain/historybus"
+	"health-api/business/domain/targetbus"
+	"health-api/foundation/logger"
+)
+
+// ErrNoNamespace is returned when a target has no namespace to look up
+// events in.
+var ErrNoNamespace = errors.New("target has no namespace")
+
+// Storer defines the interface for reading Kubernetes Events.
+type Storer interface {
+	QueryEvents(ctx context.Context, namespace string) ([]Event, error)
+}
+
+// Business manages event lookups.
+type Business struct {
+	log        *logger.Logger
+	storer     Storer
+	targetBus  *targetbus.Business
+	historyBus *historybus.Business
+	window     time.Duration
+}
+
+// NewBusiness creates a new event business layer. Events are returned from
+// window before the target's latest failure.
+func NewBusiness(log *logger.Logger, storer Storer, targetBus *targetbus.Business, historyBus *historybus.Business, window time.Duration) *Business {
+	return &Business{
+		log:        log,
+		storer:     storer,
+		targetBus:  targetBus,
+		historyBus: historyBus,
+		window:     window,
+	}
+}
+
+// QueryByTarget returns the events in the target's namespace, narrowed to
+// its workload when set, that were last seen after since or, when since is
+// nil, after window before the target's latest failure. Targets that never
+// failed get the events of the last window. Events are ordered latest
+// first.
+func (b *Business) QueryByTarget(ctx context.Context, target string, since *time.Time) (Events, error) {
+	tgt, err := b.targetBus.QueryByName(ctx, target)
+	if err != nil {
+		return Events{}, fmt.Errorf("query: %w", err)
+	}
+
+	if tgt.Namespace == "" {
+		return Events{}, fmt.Errorf("query: target[%s]: %w", target, ErrNoNamespace)
+	}
+
+	evts := Events{
+		Target:    target,
+		Namespace: tgt.Namespace,
+		Workload:  tgt.Workload,
+		Since:     time.Now().UTC().Add(-b.window),
+	}
+
+	switch {
+	case since != nil:
+		evts.Since = since.UTC()
+	default:
+		failure, err := b.historyBus.QueryLatestFailure(ctx, target)
+		switch {
+		case err == nil:
+			evts.Since = failure.At.Add(-b.window)
+			evts.FailureAt = &failure.At
+		case !errors.Is(err, historybus.ErrNotFound):
+			return Events{}, fmt.Errorf("query: %w", err)
+		}
+	}
+
+	all, err := b.storer.QueryEvents(ctx, tgt.Namespace)
+	if err != nil {
+		return Events{}, fmt.Errorf("query: namespace[%s]: %w", tgt.Namespace, err)
+	}
+
+	evts.Events = []Event{}
+	for _, e := range all {
+		if e.LastSeen.Before(evts.Since) {
+			continue
+		}
+		if tgt.Workload != "" && !e.ownedBy(tgt.Workload) {
+			continue
+		}
+		if e.Type == "Warning" {
+			evts.Warnings++
+		}
+		evts.Events = append(evts.Events, e)
+	}
+
+	sort.SliceStable(evts.Events, func(i, j int) bool { return evts.Events[i].LastSeen.After(evts.Events[j].LastSeen) })
+
+	return evts, nil
+}
@@ -0,0 +1,38 @@
+package kubeeventbus
+
+import (
+	"strings"
+	"time"
+)
+
+// Event is a Kubernetes Event about an object in a target's namespace.
+type Event struct {
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Events holds the events of a target since a point in time. FailureAt is
+// set when that point derives from the target's latest failure.
+type Events struct {
+	Target    string     `json:"target"`
+	Namespace string     `json:"namespace"`
+	Workload  string     `json:"workload,omitempty"`
+	Since     time.Time  `json:"since"`
+	FailureAt *time.Time `json:"failure_at,omitempty"`
+	Warnings  int        `json:"warnings"`
+	Events    []Event    `json:"events"`
+}
+
+// ownedBy reports whether the event is about the workload or an object it
+// owns. Controllers name their ReplicaSets, Pods and Jobs after the
+// workload, e.g. web-7d9f8 and web-7d9f8-x2k4q for Deployment web or web-0
+// for StatefulSet web.
+func (e Event) ownedBy(workload string) bool {
+	return e.Name == workload || strings.HasPrefix(e.Name, workload+"-")
+}
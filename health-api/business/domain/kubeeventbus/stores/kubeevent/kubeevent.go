@@ -0,0 +1,80 @@
+// Package kubeevent implements the event store using the Kubernetes API.
+package kubeevent
+
+import (
+	"context"
+	"fmt"
+
+	"health-api/business/domain/kubeeventbus"
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Store implements kubeeventbus.Storer using the Kubernetes API.
+type Store struct {
+	log       *logger.Logger
+	clientset kubernetes.Interface
+}
+
+// NewStore creates a new Kubernetes-backed event store.
+func NewStore(log *logger.Logger, client *kube.Client) *Store {
+	return &Store{
+		log:       log,
+		clientset: client.Clientset(),
+	}
+}
+
+// QueryEvents retrieves the events of the namespace. Kubernetes keeps them
+// for an hour by default.
+func (s *Store) QueryEvents(ctx context.Context, namespace string) ([]kubeeventbus.Event, error) {
+	list, err := s.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list events: namespace[%s]: %w", namespace, err)
+	}
+
+	events := make([]kubeeventbus.Event, len(list.Items))
+	for i, item := range list.Items {
+		events[i] = toEvent(item)
+	}
+
+	return events, nil
+}
+
+// =============================================================================
+
+// toEvent converts the event. Events recorded through the events.k8s.io
+// API only set eventTime and series, so the timestamps fall back to those.
+func toEvent(e corev1.Event) kubeeventbus.Event {
+	first, last, count := e.FirstTimestamp.Time, e.LastTimestamp.Time, int(e.Count)
+
+	if first.IsZero() {
+		first = e.EventTime.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+	if e.Series != nil {
+		count = int(e.Series.Count)
+		if e.Series.LastObservedTime.After(last) {
+			last = e.Series.LastObservedTime.Time
+		}
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	return kubeeventbus.Event{
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Kind:      e.InvolvedObject.Kind,
+		Name:      e.InvolvedObject.Name,
+		Count:     count,
+		FirstSeen: first.UTC(),
+		LastSeen:  last.UTC(),
+	}
+}
@@ -0,0 +1,108 @@
+// Package logbus provides business logic for looking up the logs of a
+// target around its failures.
+package logbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/historybus"
+	"health-api/business/domain/targetbus"
+	"health-api/foundation/logger"
+)
+
+// ErrNoSelector is returned when a target has neither a log selector nor a
+// namespace to derive one from.
+var ErrNoSelector = errors.New("target has no log selector")
+
+// Storer defines the interface for querying logs.
+type Storer interface {
+	QueryLogs(ctx context.Context, selector string, from, to time.Time, limit int) ([]Line, error)
+}
+
+// Config holds the settings of log lookups. Window is how far before and
+// after the point of interest logs are returned; Limit caps the number of
+// lines.
+type Config struct {
+	Window time.Duration
+	Limit  int
+}
+
+// Business manages log lookups.
+type Business struct {
+	log        *logger.Logger
+	storer     Storer
+	targetBus  *targetbus.Business
+	historyBus *historybus.Business
+	cfg        Config
+}
+
+// NewBusiness creates a new log business layer.
+func NewBusiness(log *logger.Logger, storer Storer, targetBus *targetbus.Business, historyBus *historybus.Business, cfg Config) *Business {
+	return &Business{
+		log:        log,
+		storer:     storer,
+		targetBus:  targetBus,
+		historyBus: historyBus,
+		cfg:        cfg,
+	}
+}
+
+// QueryByTarget returns the target's logs around at or, when at is nil,
+// around the target's latest failure, falling back to the current time for
+// targets that never failed. Targets without a log selector use their
+// namespace as one.
+func (b *Business) QueryByTarget(ctx context.Context, target string, at *time.Time) (Logs, error) {
+	tgt, err := b.targetBus.QueryByName(ctx, target)
+	if err != nil {
+		return Logs{}, fmt.Errorf("query: %w", err)
+	}
+
+	selector := tgt.LogSelector
+	if selector == "" {
+		if tgt.Namespace == "" {
+			return Logs{}, fmt.Errorf("query: target[%s]: %w", target, ErrNoSelector)
+		}
+		selector = fmt.Sprintf(`{namespace=%q}`, tgt.Namespace)
+	}
+
+	now := time.Now().UTC()
+
+	logs := Logs{
+		Target:   target,
+		Selector: selector,
+	}
+
+	point := now
+	switch {
+	case at != nil:
+		point = at.UTC()
+	default:
+		failure, err := b.historyBus.QueryLatestFailure(ctx, target)
+		switch {
+		case err == nil:
+			point = failure.At
+			logs.FailureAt = &failure.At
+		case !errors.Is(err, historybus.ErrNotFound):
+			return Logs{}, fmt.Errorf("query: %w", err)
+		}
+	}
+
+	logs.From = point.Add(-b.cfg.Window)
+	logs.To = point.Add(b.cfg.Window)
+	if logs.To.After(now) && now.After(logs.From) {
+		logs.To = now
+	}
+
+	if logs.Lines, err = b.storer.QueryLogs(ctx, selector, logs.From, logs.To, b.cfg.Limit); err != nil {
+		return Logs{}, fmt.Errorf("query: target[%s]: %w", target, err)
+	}
+
+	if logs.Lines == nil {
+		logs.Lines = []Line{}
+	}
+
+	return logs, nil
+}
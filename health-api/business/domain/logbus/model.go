@@ -0,0 +1,21 @@
+package logbus
+
+import "time"
+
+// Line is a single log line.
+type Line struct {
+	Time   time.Time         `json:"time"`
+	Labels map[string]string `json:"labels"`
+	Line   string            `json:"line"`
+}
+
+// Logs holds the log lines of a target around a point in time. FailureAt is
+// set when that point is the target's latest failure.
+type Logs struct {
+	Target    string     `json:"target"`
+	Selector  string     `json:"selector"`
+	From      time.Time  `json:"from"`
+	To        time.Time  `json:"to"`
+	FailureAt *time.Time `json:"failure_at,omitempty"`
+	Lines     []Line     `json:"lines"`
+}
@@ -0,0 +1,94 @@
+// Package lokilog implements the log store using Loki.
+package lokilog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"health-api/business/domain/logbus"
+	"health-api/foundation/logger"
+)
+
+// Store implements logbus.Storer using Loki.
+type Store struct {
+	log        *logger.Logger
+	lokiURL    string
+	httpClient *http.Client
+}
+
+// NewStore creates a new Loki-backed log store. The transport carries
+// tracing and request metrics; nil uses http.DefaultTransport.
+func NewStore(log *logger.Logger, lokiURL string, transport http.RoundTripper) *Store {
+	return &Store{
+		log:     log,
+		lokiURL: lokiURL,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+}
+
+// QueryLogs returns up to limit lines matching the LogQL selector between
+// from and to, oldest first. When more lines match, the latest are kept.
+func (s *Store) QueryLogs(ctx context.Context, selector string, from, to time.Time, limit int) ([]logbus.Line, error) {
+	query := url.Values{}
+	query.Set("query", selector)
+	query.Set("start", strconv.FormatInt(from.UnixNano(), 10))
+	query.Set("end", strconv.FormatInt(to.UnixNano(), 10))
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("direction", "backward")
+
+	queryURL := fmt.Sprintf("%s/loki/api/v1/query_range?%s", s.lokiURL, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating loki request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying loki: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loki returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Result []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding loki response: %w", err)
+	}
+
+	var lines []logbus.Line
+	for _, stream := range result.Data.Result {
+		for _, v := range stream.Values {
+			ns, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				continue
+			}
+
+			lines = append(lines, logbus.Line{
+				Time:   time.Unix(0, ns).UTC(),
+				Labels: stream.Stream,
+				Line:   v[1],
+			})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+
+	return lines, nil
+}
@@ -0,0 +1,198 @@
+// Package maintenancebus provides business logic for maintenance windows,
+// one-off or recurring periods during which targets are expected to be
+// down.
+package maintenancebus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// ErrNotFound is returned when a window does not exist.
+var ErrNotFound = errors.New("maintenance window not found")
+
+// ErrNoOccurrence is returned for a window whose rule never matches.
+var ErrNoOccurrence = errors.New("rrule has no occurrence from start")
+
+// Storer defines the interface for maintenance window data access.
+type Storer interface {
+	Create(ctx context.Context, w Window) error
+	Delete(ctx context.Context, id string) error
+	Query(ctx context.Context) ([]Window, error)
+	QueryByID(ctx context.Context, id string) (Window, error)
+}
+
+// Business manages maintenance windows.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness creates a new maintenance business layer.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create adds a new window.
+func (b *Business) Create(ctx context.Context, nw NewWindow) (Window, error) {
+	if !tenant.Get(ctx).Allows(nw.Namespace) {
+		return Window{}, fmt.Errorf("create: namespace[%s]: %w", nw.Namespace, tenant.ErrNamespace)
+	}
+
+	s, err := parseSchedule(nw.Start, nw.End, nw.Timezone, nw.RRule)
+	if err != nil {
+		return Window{}, fmt.Errorf("create: %w", err)
+	}
+
+	// A rule that never matches would be searched in vain on every lookup.
+	if s.rule != nil {
+		if _, ok := s.rule.First(s.start); !ok {
+			return Window{}, fmt.Errorf("create: %w", ErrNoOccurrence)
+		}
+	}
+
+	w := Window{
+		ID:        newID(),
+		Name:      nw.Name,
+		Namespace: nw.Namespace,
+		Targets:   nw.Targets,
+		Start:     nw.Start.UTC(),
+		End:       nw.End.UTC(),
+		Timezone:  nw.Timezone,
+		RRule:     nw.RRule,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := b.storer.Create(ctx, w); err != nil {
+		return Window{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.log.Info(ctx, "maintenancebus", "status", "window created", "id", w.ID, "name", w.Name, "rrule", w.RRule)
+
+	return b.evaluate(w, time.Now()), nil
+}
+
+// Delete removes the specified window.
+func (b *Business) Delete(ctx context.Context, id string) error {
+	if _, err := b.QueryByID(ctx, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if err := b.storer.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: id[%s]: %w", id, err)
+	}
+
+	return nil
+}
+
+// Query retrieves the windows visible to the caller.
+func (b *Business) Query(ctx context.Context) ([]Window, error) {
+	windows, err := b.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	windows = tenant.Filter(tenant.Get(ctx), windows, func(w Window) string { return w.Namespace })
+
+	now := time.Now()
+	for i := range windows {
+		windows[i] = b.evaluate(windows[i], now)
+	}
+
+	return windows, nil
+}
+
+// QueryByID retrieves the specified window.
+func (b *Business) QueryByID(ctx context.Context, id string) (Window, error) {
+	w, err := b.storer.QueryByID(ctx, id)
+	if err != nil {
+		return Window{}, fmt.Errorf("query: id[%s]: %w", id, err)
+	}
+
+	if !tenant.Get(ctx).Allows(w.Namespace) {
+		return Window{}, fmt.Errorf("query: id[%s]: %w", id, ErrNotFound)
+	}
+
+	return b.evaluate(w, time.Now()), nil
+}
+
+// Active returns the windows, of any namespace, in effect at t, expanding
+// recurrences.
+func (b *Business) Active(ctx context.Context, t time.Time) ([]Window, error) {
+	windows, err := b.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	var active []Window
+	for _, w := range windows {
+		if w = b.evaluate(w, t); w.Active {
+			active = append(active, w)
+		}
+	}
+
+	return active, nil
+}
+
+// Occurrences returns the occurrences, of windows of any namespace, that
+// overlap [from, to), expanding recurrences.
+func (b *Business) Occurrences(ctx context.Context, from time.Time, to time.Time) ([]Occurrence, error) {
+	windows, err := b.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	var occurrences []Occurrence
+	for _, w := range windows {
+		s, err := w.schedule()
+		if err != nil {
+			b.log.Warn(ctx, "maintenancebus", "status", "invalid window", "id", w.ID, "error", err)
+			continue
+		}
+
+		for _, start := range s.occurrences(from, to) {
+			occurrences = append(occurrences, Occurrence{
+				Window:  w.ID,
+				Name:    w.Name,
+				Targets: w.Targets,
+				Start:   start.UTC(),
+				End:     start.Add(s.duration).UTC(),
+			})
+		}
+	}
+
+	return occurrences, nil
+}
+
+// evaluate sets whether the window is active at t and when it next starts.
+func (b *Business) evaluate(w Window, t time.Time) Window {
+	s, err := w.schedule()
+	if err != nil {
+		b.log.Warn(context.Background(), "maintenancebus", "status", "invalid window", "id", w.ID, "error", err)
+		return w
+	}
+
+	w.Active = s.activeAt(t)
+	if next, ok := s.nextStart(t); ok {
+		next = next.UTC()
+		w.NextStart = &next
+	}
+
+	return w
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,142 @@
+package maintenancebus
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"health-api/foundation/rrule"
+)
+
+// Window represents a planned maintenance during which the listed targets
+// are expected to be unavailable. Without a recurrence rule the window
+// covers Start to End once; with one, every occurrence starts at the time
+// the rule yields and lasts End minus Start.
+type Window struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace,omitempty"`
+	Targets   []string  `json:"targets"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Timezone  string    `json:"timezone,omitempty"`
+	RRule     string    `json:"rrule,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Computed when the window is read.
+	Active    bool       `json:"active"`
+	NextStart *time.Time `json:"next_start,omitempty"`
+}
+
+// NewWindow contains the information needed to create a window. Timezone is
+// an IANA name used to expand the recurrence, so a Sunday 02:00 window
+// stays at 02:00 local time across daylight saving changes; it defaults to
+// UTC.
+type NewWindow struct {
+	Name      string    `json:"name" validate:"required"`
+	Namespace string    `json:"namespace"`
+	Targets   []string  `json:"targets" validate:"required"`
+	Start     time.Time `json:"start" validate:"required"`
+	End       time.Time `json:"end" validate:"required"`
+	Timezone  string    `json:"timezone"`
+	RRule     string    `json:"rrule"`
+}
+
+// Covers reports whether the window applies to target.
+func (w Window) Covers(target string) bool {
+	return slices.Contains(w.Targets, target)
+}
+
+// Occurrence is one period during which a window is in effect.
+type Occurrence struct {
+	Window  string    `json:"window"`
+	Name    string    `json:"name"`
+	Targets []string  `json:"targets"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// Covers reports whether the occurrence applies to target.
+func (o Occurrence) Covers(target string) bool {
+	return slices.Contains(o.Targets, target)
+}
+
+// schedule is the parsed form of a window's timing.
+type schedule struct {
+	start    time.Time
+	duration time.Duration
+	rule     *rrule.Rule
+}
+
+// parseSchedule validates the timing of a window.
+func parseSchedule(start, end time.Time, timezone, rule string) (schedule, error) {
+	if !end.After(start) {
+		return schedule{}, fmt.Errorf("end must be after start")
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return schedule{}, fmt.Errorf("timezone: %w", err)
+	}
+
+	s := schedule{
+		start:    start.In(loc),
+		duration: end.Sub(start),
+	}
+
+	if rule != "" {
+		r, err := rrule.Parse(rule)
+		if err != nil {
+			return schedule{}, fmt.Errorf("rrule: %w", err)
+		}
+		s.rule = &r
+	}
+
+	return s, nil
+}
+
+// schedule returns the parsed timing of the window. Windows are validated
+// when created, so failures are not expected.
+func (w Window) schedule() (schedule, error) {
+	return parseSchedule(w.Start, w.End, w.Timezone, w.RRule)
+}
+
+// activeAt reports whether an occurrence of the window covers t.
+func (s schedule) activeAt(t time.Time) bool {
+	start := s.start
+	if s.rule != nil {
+		last, ok := s.rule.Last(s.start, t)
+		if !ok {
+			return false
+		}
+		start = last
+	}
+
+	return !t.Before(start) && t.Before(start.Add(s.duration))
+}
+
+// occurrences returns the start of every occurrence overlapping [from, to).
+func (s schedule) occurrences(from time.Time, to time.Time) []time.Time {
+	if s.rule == nil {
+		if s.start.Before(to) && s.start.Add(s.duration).After(from) {
+			return []time.Time{s.start}
+		}
+		return nil
+	}
+
+	// An occurrence starting up to a duration before from still overlaps.
+	return s.rule.Between(s.start, from.Add(-s.duration).Add(time.Nanosecond), to)
+}
+
+// nextStart returns the start of the first occurrence after t.
+func (s schedule) nextStart(t time.Time) (time.Time, bool) {
+	if s.rule != nil {
+		return s.rule.Next(s.start, t)
+	}
+
+	if s.start.After(t) {
+		return s.start, true
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,75 @@
+// Package maintenancedb implements the maintenance window store on top of
+// jsondb.
+package maintenancedb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements maintenancebus.Storer.
+type Store struct {
+	log     *logger.Logger
+	windows *jsondb.Collection[maintenancebus.Window]
+}
+
+// NewStore opens the maintenance collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	windows, err := jsondb.NewCollection[maintenancebus.Window](db, "maintenance")
+	if err != nil {
+		return nil, fmt.Errorf("opening maintenance: %w", err)
+	}
+
+	return &Store{
+		log:     log,
+		windows: windows,
+	}, nil
+}
+
+// Create inserts a new window.
+func (s *Store) Create(ctx context.Context, w maintenancebus.Window) error {
+	if err := s.windows.Insert(w.ID, w); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a window.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if err := s.windows.Delete(id); err != nil {
+		if errors.Is(err, jsondb.ErrNotFound) {
+			return maintenancebus.ErrNotFound
+		}
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves all windows ordered by start.
+func (s *Store) Query(ctx context.Context) ([]maintenancebus.Window, error) {
+	windows := s.windows.All()
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	return windows, nil
+}
+
+// QueryByID retrieves the window with the specified ID.
+func (s *Store) QueryByID(ctx context.Context, id string) (maintenancebus.Window, error) {
+	w, ok := s.windows.Get(id)
+	if !ok {
+		return maintenancebus.Window{}, maintenancebus.ErrNotFound
+	}
+
+	return w, nil
+}
@@ -0,0 +1,92 @@
+package notifybus
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Channel is a named notifier that remembers how its deliveries went, so
+// a channel that stopped delivering shows up before anyone misses a page.
+type Channel struct {
+	notifier Notifier
+
+	mu     sync.Mutex
+	status ChannelStatus
+}
+
+// NewChannel names notifier as a channel.
+func NewChannel(name string, notifier Notifier) *Channel {
+	return &Channel{
+		notifier: notifier,
+		status:   ChannelStatus{Name: name},
+	}
+}
+
+// Notify implements the Notifier interface.
+func (c *Channel) Notify(ctx context.Context, n Notification) error {
+	err := c.notifier.Notify(ctx, n)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UTC()
+	c.status.LastAttempt = &now
+	c.status.Deliveries++
+
+	if err != nil {
+		c.status.Failures++
+		c.status.LastError = err.Error()
+		return err
+	}
+
+	c.status.LastSuccess = &now
+	c.status.LastError = ""
+
+	return nil
+}
+
+// Status returns the channel's delivery results so far.
+func (c *Channel) Status() ChannelStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.status
+}
+
+// Channels returns the delivery results of every channel notifications go
+// to, routed or not, ordered by name.
+func (b *Business) Channels() []ChannelStatus {
+	var channels []*Channel
+
+	var collect func(nts []Notifier)
+	collect = func(nts []Notifier) {
+		for _, nt := range nts {
+			switch nt := nt.(type) {
+			case *Channel:
+				if !slices.Contains(channels, nt) {
+					channels = append(channels, nt)
+				}
+			case *Router:
+				for _, route := range nt.routes {
+					collect(route)
+				}
+				collect(nt.fallback)
+			}
+		}
+	}
+	collect(b.notifiers)
+
+	statuses := make([]ChannelStatus, 0, len(channels))
+	for _, c := range channels {
+		statuses = append(statuses, c.Status())
+	}
+
+	slices.SortFunc(statuses, func(a, b ChannelStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
@@ -0,0 +1,290 @@
+package notifybus
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"health-api/business/domain/incidentbus"
+	"health-api/foundation/cron"
+	"health-api/foundation/logger"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	textTemplates = template.Must(template.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.txt.tmpl"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html.tmpl"))
+)
+
+var templateFuncs = map[string]any{
+	"when": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	},
+	"duration": func(seconds float64) string {
+		return (time.Duration(seconds) * time.Second).Round(time.Minute).String()
+	},
+}
+
+// EmailConfig configures the SMTP notifier.
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	// Digest collects notifications for the digest sent by RunDigest
+	// instead of mailing each one as it happens.
+	Digest bool
+}
+
+// EmailNotifier mails notifications over SMTP, one email per status change
+// or, in digest mode, one summary per schedule. Emails carry both an HTML
+// and a plaintext part.
+type EmailNotifier struct {
+	log         *logger.Logger
+	cfg         EmailConfig
+	incidentBus *incidentbus.Business
+
+	mu      sync.Mutex
+	pending []Notification
+	since   time.Time
+}
+
+// NewEmailNotifier constructs an SMTP notifier. The incident business layer
+// lists the open incidents in digests and may be nil.
+func NewEmailNotifier(log *logger.Logger, cfg EmailConfig, incidentBus *incidentbus.Business) *EmailNotifier {
+	return &EmailNotifier{
+		log:         log,
+		cfg:         cfg,
+		incidentBus: incidentBus,
+		since:       time.Now().UTC(),
+	}
+}
+
+// Notify implements the Notifier interface.
+func (e *EmailNotifier) Notify(ctx context.Context, n Notification) error {
+	if e.cfg.Digest {
+		e.mu.Lock()
+		e.pending = append(e.pending, n)
+		e.mu.Unlock()
+		return nil
+	}
+
+	subject := fmt.Sprintf("%s is %s", n.Target, n.To)
+
+	msg, err := e.message(subject, "event", n)
+	if err != nil {
+		return err
+	}
+
+	return e.send(ctx, msg)
+}
+
+// RunDigest sends a digest each time the schedule fires. It blocks until
+// ctx is canceled.
+func (e *EmailNotifier) RunDigest(ctx context.Context, sched cron.Schedule) {
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			e.log.Error(ctx, "notifybus", "status", "digest schedule never fires")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		if err := e.SendDigest(ctx); err != nil {
+			e.log.Error(ctx, "notifybus", "status", "digest failed", "error", err)
+		}
+	}
+}
+
+// SendDigest mails the status changes collected since the previous digest
+// together with the incidents that are still open. When sending fails the
+// changes are kept for the next digest.
+func (e *EmailNotifier) SendDigest(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	e.mu.Lock()
+	d := Digest{
+		Since:   e.since,
+		Until:   now,
+		Changes: e.pending,
+	}
+	e.pending = nil
+	e.since = now
+	e.mu.Unlock()
+
+	if e.incidentBus != nil {
+		incs, err := e.incidentBus.Query(ctx, incidentbus.QueryFilter{})
+		if err != nil {
+			e.log.Error(ctx, "notifybus", "status", "digest incidents", "error", err)
+		}
+		for _, inc := range incs {
+			if inc.Status == incidentbus.StatusOpen {
+				d.Incidents = append(d.Incidents, inc)
+			}
+		}
+	}
+
+	subject := fmt.Sprintf("Health digest: %d status changes, %d open incidents", len(d.Changes), len(d.Incidents))
+
+	msg, err := e.message(subject, "digest", d)
+	if err == nil {
+		err = e.send(ctx, msg)
+	}
+
+	if err != nil {
+		e.mu.Lock()
+		e.pending = append(d.Changes, e.pending...)
+		e.since = d.Since
+		e.mu.Unlock()
+		return err
+	}
+
+	e.log.Info(ctx, "notifybus", "status", "digest sent", "changes", len(d.Changes), "incidents", len(d.Incidents))
+
+	return nil
+}
+
+// message renders the named templates into a multipart/alternative email
+// with the plaintext part first, as mail clients show the last part they
+// understand.
+func (e *EmailNotifier) message(subject string, name string, data any) ([]byte, error) {
+	var text, html bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+
+	parts := []struct {
+		contentType string
+		body        []byte
+	}{
+		{"text/plain; charset=utf-8", text.Bytes()},
+		{"text/html; charset=utf-8", html.Bytes()},
+	}
+
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create part: %w", err)
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write(p.body); err != nil {
+			return nil, fmt.Errorf("write part: %w", err)
+		}
+		if err := qw.Close(); err != nil {
+			return nil, fmt.Errorf("write part: %w", err)
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close message: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// send delivers msg to the recipients, upgrading the connection with
+// STARTTLS when the server offers it. Unlike smtp.SendMail it gives up when
+// ctx is done.
+func (e *EmailNotifier) send(ctx context.Context, msg []byte) error {
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dialing smtp: %w", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(30 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp greeting: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	from := e.cfg.From
+	if addr, err := mail.ParseAddress(from); err == nil {
+		from = addr.Address
+	}
+
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, to := range e.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp rcpt %s: %w", to, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+
+	return c.Quit()
+}
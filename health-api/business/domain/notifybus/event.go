@@ -0,0 +1,61 @@
+package notifybus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(healthbus.DomainName, healthbus.ActionStatusChanged, b.actionStatusChanged)
+	}
+}
+
+// actionStatusChanged notifies about a status change unless the check was
+// suppressed because an upstream dependency is the root cause or the target
+// is flapping.
+func (b *Business) actionStatusChanged(ctx context.Context, data delegate.Data) error {
+	var params healthbus.ActionStatusChangedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	// The first observation of a healthy target is not news
+	if params.From == "" && params.To == healthbus.StatusHealthy {
+		return nil
+	}
+
+	if params.Check.Suppressed {
+		b.log.Info(ctx, "notifybus", "status", "notification suppressed", "target", params.Target, "root_cause", params.Check.RootCause, "flap_score", params.Check.FlapScore)
+		return nil
+	}
+
+	n := newNotification(params)
+
+	if b.onCallBus != nil {
+		if oc, err := b.onCallBus.Query(ctx, n.Team); err == nil {
+			n.OnCall = oc.Name
+		}
+	}
+
+	return b.Send(ctx, n)
+}
+
+func newNotification(params healthbus.ActionStatusChangedParms) Notification {
+	return Notification{
+		Target:     params.Target,
+		From:       params.From,
+		To:         params.To,
+		At:         params.At,
+		Summary:    fmt.Sprintf("%s is %s", params.Target, params.To),
+		Severity:   params.Check.Severity,
+		Team:       params.Check.Team,
+		RunbookURL: params.Check.RunbookURL,
+	}
+}
@@ -0,0 +1,41 @@
+package notifybus
+
+import (
+	"time"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/domain/incidentbus"
+)
+
+// Notification describes a change in a target's health status.
+type Notification struct {
+	Target     string           `json:"target"`
+	From       healthbus.Status `json:"from,omitempty"`
+	To         healthbus.Status `json:"to"`
+	At         time.Time        `json:"at"`
+	Summary    string           `json:"summary"`
+	Severity   string           `json:"severity,omitempty"`
+	Team       string           `json:"team,omitempty"`
+	RunbookURL string           `json:"runbook_url,omitempty"`
+	OnCall     string           `json:"on_call,omitempty"`
+}
+
+// Digest summarizes the status changes since the previous digest and the
+// incidents still open when it was sent.
+type Digest struct {
+	Since     time.Time
+	Until     time.Time
+	Changes   []Notification
+	Incidents []incidentbus.Incident
+}
+
+// ChannelStatus reports how a channel's deliveries went. LastError is the
+// failure of the last delivery, empty once one succeeds again.
+type ChannelStatus struct {
+	Name        string
+	LastAttempt *time.Time
+	LastSuccess *time.Time
+	LastError   string
+	Deliveries  int
+	Failures    int
+}
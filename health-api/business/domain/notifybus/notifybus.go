@@ -0,0 +1,58 @@
+// Package notifybus provides business logic for notifying external systems
+// when a target's health status changes.
+package notifybus
+
+import (
+	"context"
+	"fmt"
+
+	"health-api/business/domain/oncallbus"
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+)
+
+// Notifier delivers notifications to an external destination.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Business manages status change notifications.
+type Business struct {
+	log       *logger.Logger
+	delegate  *delegate.Delegate
+	onCallBus *oncallbus.Business
+	notifiers []Notifier
+}
+
+// NewBusiness creates a new notification business layer and registers for
+// health status changes. The on-call business layer is optional; with it
+// notifications name who is on call for the target's team.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, onCallBus *oncallbus.Business, notifiers ...Notifier) *Business {
+	b := Business{
+		log:       log,
+		delegate:  delegate,
+		onCallBus: onCallBus,
+		notifiers: notifiers,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// Send delivers n to every notifier. Every notifier is tried; the first
+// failure is returned.
+func (b *Business) Send(ctx context.Context, n Notification) error {
+	var firstErr error
+
+	for _, nt := range b.notifiers {
+		if err := nt.Notify(ctx, n); err != nil {
+			b.log.Error(ctx, "notifybus", "status", "notify failed", "target", n.Target, "error", err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("notify: %w", err)
+			}
+		}
+	}
+
+	return firstErr
+}
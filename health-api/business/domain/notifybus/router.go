@@ -0,0 +1,43 @@
+package notifybus
+
+import (
+	"context"
+)
+
+// Router delivers each notification to the channels of the target's team.
+// Targets of a team without a route of its own, or without a team, take the
+// default route.
+type Router struct {
+	routes   map[string][]Notifier
+	fallback []Notifier
+}
+
+// NewRouter constructs a router from team routes and the default route.
+func NewRouter(routes map[string][]Notifier, fallback []Notifier) *Router {
+	return &Router{
+		routes:   routes,
+		fallback: fallback,
+	}
+}
+
+// Route returns the channels notifications for team are delivered to.
+func (r *Router) Route(team string) []Notifier {
+	if channels, ok := r.routes[team]; ok && team != "" {
+		return channels
+	}
+	return r.fallback
+}
+
+// Notify implements the Notifier interface. Every channel of the route is
+// tried; the first failure is returned.
+func (r *Router) Notify(ctx context.Context, n Notification) error {
+	var firstErr error
+
+	for _, nt := range r.Route(n.Team) {
+		if err := nt.Notify(ctx, n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
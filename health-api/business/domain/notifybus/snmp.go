@@ -0,0 +1,90 @@
+package notifybus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"health-api/business/domain/healthbus"
+	"health-api/foundation/snmp"
+)
+
+// DefaultTrapOID is the OID prefix used when none is configured. It lies in
+// the net-snmp experimental arc and should be replaced by an enterprise OID.
+const DefaultTrapOID = "1.3.6.1.4.1.8072.9999.1"
+
+// Set of status codes carried by traps next to the status name, for NOC
+// tooling that maps states numerically.
+var trapStatusCodes = map[healthbus.Status]int{
+	healthbus.StatusHealthy:     1,
+	healthbus.StatusDegraded:    2,
+	healthbus.StatusDown:        3,
+	healthbus.StatusUnknown:     4,
+	healthbus.StatusFlapping:    5,
+	healthbus.StatusMaintenance: 6,
+	healthbus.StatusPaused:      7,
+}
+
+// SNMPNotifier emits an SNMP trap for every status change. Under the OID
+// prefix P, the trap is P.0.1 and carries:
+//
+//	P.1.1 target       OCTET STRING
+//	P.1.2 status       OCTET STRING
+//	P.1.3 previous     OCTET STRING
+//	P.1.4 severity     OCTET STRING
+//	P.1.5 summary      OCTET STRING
+//	P.1.6 team         OCTET STRING
+//	P.1.7 on call      OCTET STRING
+//	P.1.8 status code  INTEGER (1 healthy, 2 degraded, 3 down, 4 unknown,
+//	                   5 flapping, 6 maintenance, 7 paused)
+type SNMPNotifier struct {
+	clients []*snmp.Client
+	prefix  string
+}
+
+// NewSNMPNotifier constructs a notifier sending traps to each receiver
+// address with the shared settings in cfg.
+func NewSNMPNotifier(cfg snmp.Config, addresses []string, prefix string) (*SNMPNotifier, error) {
+	if prefix == "" {
+		prefix = DefaultTrapOID
+	}
+
+	s := SNMPNotifier{
+		prefix: strings.TrimPrefix(prefix, "."),
+	}
+
+	for _, addr := range addresses {
+		cfg.Address = addr
+
+		c, err := snmp.New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("snmp receiver %s: %w", addr, err)
+		}
+		s.clients = append(s.clients, c)
+	}
+
+	return &s, nil
+}
+
+// Notify implements the Notifier interface.
+func (s *SNMPNotifier) Notify(ctx context.Context, n Notification) error {
+	vars := []snmp.VarBind{
+		{OID: s.prefix + ".1.1", Value: n.Target},
+		{OID: s.prefix + ".1.2", Value: string(n.To)},
+		{OID: s.prefix + ".1.3", Value: string(n.From)},
+		{OID: s.prefix + ".1.4", Value: n.Severity},
+		{OID: s.prefix + ".1.5", Value: n.Summary},
+		{OID: s.prefix + ".1.6", Value: n.Team},
+		{OID: s.prefix + ".1.7", Value: n.OnCall},
+		{OID: s.prefix + ".1.8", Value: trapStatusCodes[n.To]},
+	}
+
+	var firstErr error
+	for _, c := range s.clients {
+		if err := c.Trap(ctx, s.prefix+".0.1", vars); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,11 @@
+package oncallbus
+
+import "time"
+
+// OnCall is the person on call for a schedule.
+type OnCall struct {
+	Schedule string     `json:"schedule"`
+	Name     string     `json:"name"`
+	Email    string     `json:"email,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+}
@@ -0,0 +1,123 @@
+// Package oncallbus provides business logic for resolving who is on call
+// for a team, from the schedules of an external paging service.
+package oncallbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"health-api/foundation/logger"
+)
+
+// DefaultSchedule is the key of Config.Schedules used for teams without a
+// schedule of their own.
+const DefaultSchedule = "*"
+
+// ErrNoSchedule is returned for teams without a schedule.
+var ErrNoSchedule = errors.New("no on-call schedule")
+
+// ErrNobodyOnCall is returned by stores when a schedule has nobody on call.
+var ErrNobodyOnCall = errors.New("nobody on call")
+
+// Storer defines the interface for reading on-call schedules.
+type Storer interface {
+	QueryOnCall(ctx context.Context, schedule string, at time.Time) (OnCall, error)
+}
+
+// Config holds the settings of on-call resolution.
+type Config struct {
+	// Schedules maps team names to schedule identifiers of the paging
+	// service. DefaultSchedule applies to all other teams.
+	Schedules map[string]string
+
+	// CacheTTL is how long a resolved on-call is reused. Alert queries are
+	// frequent and rotations are not, so the paging service is asked at
+	// most once per schedule and TTL.
+	CacheTTL time.Duration
+}
+
+// Business manages on-call lookups.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+	cfg    Config
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+// cached is a resolved on-call and when it has to be resolved again.
+type cached struct {
+	oncall  OnCall
+	err     error
+	expires time.Time
+}
+
+// NewBusiness creates a new on-call business layer.
+func NewBusiness(log *logger.Logger, storer Storer, cfg Config) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+		cfg:    cfg,
+		cache:  make(map[string]cached),
+	}
+}
+
+// Query returns who is on call for the team now.
+func (b *Business) Query(ctx context.Context, team string) (OnCall, error) {
+	schedule, ok := b.cfg.Schedules[team]
+	if !ok {
+		schedule = b.cfg.Schedules[DefaultSchedule]
+	}
+	if schedule == "" {
+		return OnCall{}, fmt.Errorf("query: team[%s]: %w", team, ErrNoSchedule)
+	}
+
+	now := time.Now()
+
+	b.mu.Lock()
+	c, ok := b.cache[schedule]
+	b.mu.Unlock()
+
+	if !ok || !now.Before(c.expires) {
+		c = b.resolve(ctx, schedule, now)
+
+		// A lookup cut short by the caller says nothing about the
+		// paging service.
+		if c.err == nil || ctx.Err() == nil {
+			b.mu.Lock()
+			b.cache[schedule] = c
+			b.mu.Unlock()
+		}
+	}
+
+	if c.err != nil {
+		return OnCall{}, fmt.Errorf("query: schedule[%s]: %w", schedule, c.err)
+	}
+
+	return c.oncall, nil
+}
+
+// resolve asks the store who is on call. Failures are cached too, so an
+// unreachable paging service does not slow down every alert query; the
+// entry ends when the shift does, should that come first.
+func (b *Business) resolve(ctx context.Context, schedule string, now time.Time) cached {
+	oncall, err := b.storer.QueryOnCall(ctx, schedule, now)
+	if err != nil && !errors.Is(err, ErrNobodyOnCall) {
+		b.log.Warn(ctx, "oncallbus", "status", "on-call lookup failed", "schedule", schedule, "error", err)
+	}
+
+	c := cached{
+		oncall:  oncall,
+		err:     err,
+		expires: now.Add(b.cfg.CacheTTL),
+	}
+	if oncall.Until != nil && oncall.Until.Before(c.expires) {
+		c.expires = *oncall.Until
+	}
+
+	return c
+}
@@ -0,0 +1,95 @@
+// Package opsgeniestore implements the on-call store using the Opsgenie
+// Schedule API.
+package opsgeniestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"health-api/business/domain/oncallbus"
+	"health-api/foundation/logger"
+)
+
+// DefaultURL is the base URL of the Opsgenie API. Accounts in the EU
+// instance use https://api.eu.opsgenie.com.
+const DefaultURL = "https://api.opsgenie.com"
+
+// Store implements oncallbus.Storer using Opsgenie.
+type Store struct {
+	log        *logger.Logger
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewStore creates an Opsgenie on-call store authenticating with the API
+// key of an API integration. The transport carries tracing and request
+// metrics; nil uses http.DefaultTransport.
+func NewStore(log *logger.Logger, baseURL, apiKey string, transport http.RoundTripper) *Store {
+	return &Store{
+		log:     log,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+}
+
+// QueryOnCall returns the first recipient on call for the schedule, named
+// by its name, at the given time. Opsgenie reports recipients by username,
+// which is their email address.
+func (s *Store) QueryOnCall(ctx context.Context, schedule string, at time.Time) (oncallbus.OnCall, error) {
+	query := url.Values{}
+	query.Set("scheduleIdentifierType", "name")
+	query.Set("flat", "true")
+	query.Set("date", at.UTC().Format(time.RFC3339))
+
+	path := fmt.Sprintf("%s/v2/schedules/%s/on-calls?%s", s.baseURL, url.PathEscape(schedule), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return oncallbus.OnCall{}, fmt.Errorf("creating on-calls request: %w", err)
+	}
+	req.Header.Set("Authorization", "GenieKey "+s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return oncallbus.OnCall{}, fmt.Errorf("querying on-calls: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return oncallbus.OnCall{}, fmt.Errorf("opsgenie returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			OnCallRecipients []string `json:"onCallRecipients"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return oncallbus.OnCall{}, fmt.Errorf("decoding on-calls: %w", err)
+	}
+
+	if len(body.Data.OnCallRecipients) == 0 {
+		return oncallbus.OnCall{}, oncallbus.ErrNobodyOnCall
+	}
+
+	recipient := body.Data.OnCallRecipients[0]
+
+	oncall := oncallbus.OnCall{
+		Schedule: schedule,
+		Name:     recipient,
+	}
+	if strings.Contains(recipient, "@") {
+		oncall.Email = recipient
+	}
+
+	return oncall, nil
+}
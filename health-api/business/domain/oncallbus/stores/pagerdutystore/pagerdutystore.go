@@ -0,0 +1,111 @@
+// Package pagerdutystore implements the on-call store using the PagerDuty
+// REST API.
+package pagerdutystore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"health-api/business/domain/oncallbus"
+	"health-api/foundation/logger"
+)
+
+// DefaultURL is the base URL of the PagerDuty REST API.
+const DefaultURL = "https://api.pagerduty.com"
+
+// Store implements oncallbus.Storer using PagerDuty.
+type Store struct {
+	log        *logger.Logger
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewStore creates a PagerDuty on-call store authenticating with the REST
+// API token. The transport carries tracing and request metrics; nil uses
+// http.DefaultTransport.
+func NewStore(log *logger.Logger, baseURL, token string, transport http.RoundTripper) *Store {
+	return &Store{
+		log:     log,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+}
+
+// QueryOnCall returns the first escalation level of the schedule, named by
+// its ID, at the given time.
+func (s *Store) QueryOnCall(ctx context.Context, schedule string, at time.Time) (oncallbus.OnCall, error) {
+	query := url.Values{}
+	query.Set("schedule_ids[]", schedule)
+	query.Set("include[]", "users")
+	query.Set("since", at.UTC().Format(time.RFC3339))
+	query.Set("until", at.UTC().Add(time.Second).Format(time.RFC3339))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/oncalls?"+query.Encode(), nil)
+	if err != nil {
+		return oncallbus.OnCall{}, fmt.Errorf("creating oncalls request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+s.token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return oncallbus.OnCall{}, fmt.Errorf("querying oncalls: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return oncallbus.OnCall{}, fmt.Errorf("pagerduty returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		OnCalls []struct {
+			EscalationLevel int    `json:"escalation_level"`
+			End             string `json:"end"`
+			User            struct {
+				Name    string `json:"name"`
+				Summary string `json:"summary"`
+				Email   string `json:"email"`
+			} `json:"user"`
+		} `json:"oncalls"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return oncallbus.OnCall{}, fmt.Errorf("decoding oncalls: %w", err)
+	}
+
+	best := -1
+	for i, oc := range body.OnCalls {
+		if best < 0 || oc.EscalationLevel < body.OnCalls[best].EscalationLevel {
+			best = i
+		}
+	}
+	if best < 0 {
+		return oncallbus.OnCall{}, oncallbus.ErrNobodyOnCall
+	}
+
+	oc := body.OnCalls[best]
+
+	oncall := oncallbus.OnCall{
+		Schedule: schedule,
+		Name:     oc.User.Name,
+		Email:    oc.User.Email,
+	}
+	if oncall.Name == "" {
+		oncall.Name = oc.User.Summary
+	}
+
+	// Permanent assignments have no end.
+	if end, err := time.Parse(time.RFC3339, oc.End); err == nil {
+		oncall.Until = &end
+	}
+
+	return oncall, nil
+}
@@ -0,0 +1,77 @@
+package probebus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+)
+
+// Bounds on making a saved target's module available. Each attempt gets
+// ensureTimeout; a failed one is retried after ensureBackoff, doubling.
+const (
+	ensureAttempts = 3
+	ensureTimeout  = 5 * time.Second
+	ensureBackoff  = 2 * time.Second
+)
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(targetbus.DomainName, targetbus.ActionSaved, b.actionTargetSaved)
+	}
+}
+
+// actionTargetSaved makes sure the blackbox exporter has loaded the module a
+// newly saved target refers to. The target is saved either way, so the
+// exporter is queried and reloaded in the background rather than holding up
+// the request.
+func (b *Business) actionTargetSaved(ctx context.Context, data delegate.Data) error {
+	var params targetbus.ActionSavedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	module := params.Target.Module
+	if module == "" || b.isKnown(module) {
+		return nil
+	}
+
+	go b.ensureModule(context.WithoutCancel(ctx), params.Target.Name, module)
+
+	return nil
+}
+
+// ensureModule runs EnsureModule for target's module, retrying failures,
+// and logs the outcome.
+func (b *Business) ensureModule(ctx context.Context, target string, module string) {
+	backoff := ensureBackoff
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, ensureTimeout)
+		found, err := b.EnsureModule(attemptCtx, module)
+		cancel()
+
+		switch {
+		case err == nil && !found:
+			b.log.Warn(ctx, "probebus", "status", "module not configured in blackbox exporter", "target", target, "module", module)
+			return
+
+		case err == nil:
+			return
+
+		case attempt == ensureAttempts:
+			b.log.Error(ctx, "probebus", "status", "ensure module failed", "target", target, "module", module, "attempts", attempt, "error", err)
+			return
+		}
+
+		b.log.Warn(ctx, "probebus", "status", "ensure module failed", "target", target, "module", module, "attempt", attempt, "retry_in", backoff.String(), "error", err)
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
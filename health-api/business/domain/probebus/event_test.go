@@ -0,0 +1,89 @@
+package probebus_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"health-api/business/domain/probebus"
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+)
+
+// storer serves no modules until the exporter has been reloaded, and
+// blocks the reload until release is closed.
+type storer struct {
+	release chan struct{}
+
+	mu       sync.Mutex
+	reloaded bool
+}
+
+func (s *storer) QueryModules(ctx context.Context) ([]probebus.Module, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.reloaded {
+		return nil, nil
+	}
+	return []probebus.Module{{Name: "icmp", Prober: "icmp"}}, nil
+}
+
+func (s *storer) Reload(ctx context.Context) error {
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.mu.Lock()
+	s.reloaded = true
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *storer) isReloaded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reloaded
+}
+
+func Test_TargetSaved(t *testing.T) {
+	log := logger.New(os.Stdout, logger.LevelError, "TEST", nil)
+	d := delegate.New(log)
+
+	st := storer{release: make(chan struct{})}
+	b := probebus.NewBusiness(log, d, &st, true)
+
+	// Saving the target must not wait on the exporter's reload.
+	done := make(chan struct{})
+	go func() {
+		d.Call(context.Background(), targetbus.ActionSavedData(targetbus.Target{Name: "router", Module: "icmp"}))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Should return from the save before the exporter reloads")
+	}
+
+	close(st.release)
+
+	deadline := time.Now().Add(time.Second)
+	for !st.isReloaded() {
+		if time.Now().After(deadline) {
+			t.Fatal("Should reload the exporter in the background")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if found, err := b.EnsureModule(context.Background(), "icmp"); err != nil || !found {
+		t.Errorf("Should know the module after the reload, got %t %v", found, err)
+	}
+}
@@ -0,0 +1,10 @@
+package probebus
+
+// Module is a blackbox exporter module: a named prober configuration that
+// targets reference to choose how they are checked.
+type Module struct {
+	Name    string         `json:"name"`
+	Prober  string         `json:"prober"`
+	Timeout string         `json:"timeout,omitempty"`
+	Config  map[string]any `json:"config,omitempty"`
+}
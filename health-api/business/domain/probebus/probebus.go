@@ -0,0 +1,111 @@
+// Package probebus provides business logic for the blackbox exporter probe
+// modules that targets are checked with.
+package probebus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"health-api/business/sdk/delegate"
+	"health-api/foundation/logger"
+)
+
+// Storer defines the interface for reading and reloading the blackbox
+// exporter configuration.
+type Storer interface {
+	QueryModules(ctx context.Context) ([]Module, error)
+	Reload(ctx context.Context) error
+}
+
+// Business manages blackbox exporter modules.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+	reload   bool
+
+	mu    sync.Mutex
+	known map[string]bool
+}
+
+// NewBusiness creates a new probe business layer. When reload is true the
+// blackbox exporter is reloaded whenever a target is saved with a module it
+// does not yet know about.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, reload bool) *Business {
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		reload:   reload,
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// QueryModules returns the modules configured in the blackbox exporter,
+// sorted by name.
+func (b *Business) QueryModules(ctx context.Context) ([]Module, error) {
+	modules, err := b.storer.QueryModules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query modules: %w", err)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+
+	b.remember(modules)
+
+	return modules, nil
+}
+
+// EnsureModule reloads the blackbox exporter when name is not among the
+// modules it has loaded. It reports whether the module is available.
+func (b *Business) EnsureModule(ctx context.Context, name string) (bool, error) {
+	if b.isKnown(name) {
+		return true, nil
+	}
+
+	found, err := b.hasModule(ctx, name)
+	if err != nil || found || !b.reload {
+		return found, err
+	}
+
+	if err := b.storer.Reload(ctx); err != nil {
+		return false, fmt.Errorf("reload: %w", err)
+	}
+
+	b.log.Info(ctx, "probebus", "status", "blackbox exporter reloaded", "module", name)
+
+	return b.hasModule(ctx, name)
+}
+
+func (b *Business) hasModule(ctx context.Context, name string) (bool, error) {
+	if _, err := b.QueryModules(ctx); err != nil {
+		return false, err
+	}
+
+	return b.isKnown(name), nil
+}
+
+func (b *Business) isKnown(name string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.known[name]
+}
+
+func (b *Business) remember(modules []Module) {
+	known := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		known[m.Name] = true
+	}
+
+	b.mu.Lock()
+	b.known = known
+	b.mu.Unlock()
+}
@@ -0,0 +1,128 @@
+// Package blackboxstore implements the probe store using the blackbox
+// exporter's /config and /-/reload endpoints.
+package blackboxstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"health-api/business/domain/probebus"
+	"health-api/foundation/logger"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// Store implements probebus.Storer using the blackbox exporter.
+type Store struct {
+	log        *logger.Logger
+	url        string
+	httpClient *http.Client
+}
+
+// NewStore creates a blackbox exporter backed probe store.
+func NewStore(log *logger.Logger, blackboxURL string, transport http.RoundTripper) *Store {
+	return &Store{
+		log: log,
+		url: blackboxURL,
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+	}
+}
+
+// QueryModules reads the modules from the exporter's loaded configuration.
+func (s *Store) QueryModules(ctx context.Context) ([]probebus.Module, error) {
+	data, err := s.do(ctx, http.MethodGet, "/config")
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg struct {
+		Modules map[string]map[string]any `yaml:"modules"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing blackbox config: %w", err)
+	}
+
+	modules := make([]probebus.Module, 0, len(cfg.Modules))
+	for name, raw := range cfg.Modules {
+		modules = append(modules, toModule(name, raw))
+	}
+
+	return modules, nil
+}
+
+// Reload asks the exporter to re-read its configuration file.
+func (s *Store) Reload(ctx context.Context) error {
+	_, err := s.do(ctx, http.MethodPost, "/-/reload")
+	return err
+}
+
+func (s *Store) do(ctx context.Context, method, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: status %d: %s", method, path, resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// =============================================================================
+
+func toModule(name string, raw map[string]any) probebus.Module {
+	m := probebus.Module{
+		Name:   name,
+		Config: make(map[string]any),
+	}
+
+	for k, v := range raw {
+		switch k {
+		case "prober":
+			m.Prober, _ = v.(string)
+		case "timeout":
+			m.Timeout = fmt.Sprint(v)
+		default:
+			m.Config[k] = normalize(v)
+		}
+	}
+
+	return m
+}
+
+// normalize converts the map[interface{}]interface{} values produced by the
+// YAML decoder into map[string]any so they can be encoded as JSON.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+
+	case []any:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+		return v
+	}
+
+	return v
+}
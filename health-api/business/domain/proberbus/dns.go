@@ -0,0 +1,155 @@
+package proberbus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// dnsRecords maps the supported record types to their lookup.
+var dnsRecords = map[string]func(ctx context.Context, r *net.Resolver, name string) ([]string, error){
+	"A":     lookupIP("ip4"),
+	"AAAA":  lookupIP("ip6"),
+	"CNAME": lookupCNAME,
+	"MX":    lookupMX,
+	"NS":    lookupNS,
+	"TXT":   lookupTXT,
+}
+
+// runDNS resolves the configured name and records the resolution time.
+func runDNS(ctx context.Context, c Check) Result {
+	result := Result{
+		Check:     c.Name,
+		Type:      c.Type,
+		StartedAt: time.Now().UTC(),
+	}
+
+	record := strings.ToUpper(c.DNS.Record)
+	if record == "" {
+		record = "A"
+	}
+
+	resolver := net.DefaultResolver
+	if c.DNS.Server != "" || c.SourceInterface != "" {
+		dial, err := c.egress().DialContext()
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				if c.DNS.Server != "" {
+					address = c.DNS.Server
+				}
+				return dial(ctx, network, address)
+			},
+		}
+	}
+
+	answers, err := dnsRecords[record](ctx, resolver, c.DNS.Name)
+	result.DurationSeconds = time.Since(result.StartedAt).Seconds()
+	result.Answers = answers
+
+	sr := StepResult{
+		Name:            "resolve",
+		DurationSeconds: result.DurationSeconds,
+	}
+
+	switch {
+	case err != nil:
+		sr.Error = err.Error()
+	default:
+		if missing := missingAnswers(c.DNS.Expect, answers); len(missing) > 0 {
+			sr.Error = fmt.Sprintf("%s %s: missing %v", record, c.DNS.Name, missing)
+		}
+	}
+
+	sr.Success = sr.Error == ""
+	result.Steps = []StepResult{sr}
+	result.Success = sr.Success
+	result.Error = sr.Error
+
+	return result
+}
+
+// missingAnswers returns the expected values absent from answers. Names are
+// compared without their trailing dot and case-insensitively.
+func missingAnswers(expect, answers []string) []string {
+	got := make([]string, len(answers))
+	for i, a := range answers {
+		got[i] = normalizeAnswer(a)
+	}
+
+	var missing []string
+	for _, e := range expect {
+		if !slices.Contains(got, normalizeAnswer(e)) {
+			missing = append(missing, e)
+		}
+	}
+
+	return missing
+}
+
+func normalizeAnswer(s string) string {
+	return strings.ToLower(strings.TrimSuffix(s, "."))
+}
+
+// =============================================================================
+
+func lookupIP(network string) func(ctx context.Context, r *net.Resolver, name string) ([]string, error) {
+	return func(ctx context.Context, r *net.Resolver, name string) ([]string, error) {
+		ips, err := r.LookupNetIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+
+		answers := make([]string, len(ips))
+		for i, ip := range ips {
+			answers[i] = ip.Unmap().String()
+		}
+		return answers, nil
+	}
+}
+
+func lookupCNAME(ctx context.Context, r *net.Resolver, name string) ([]string, error) {
+	cname, err := r.LookupCNAME(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return []string{cname}, nil
+}
+
+func lookupMX(ctx context.Context, r *net.Resolver, name string) ([]string, error) {
+	mxs, err := r.LookupMX(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make([]string, len(mxs))
+	for i, mx := range mxs {
+		answers[i] = mx.Host
+	}
+	return answers, nil
+}
+
+func lookupNS(ctx context.Context, r *net.Resolver, name string) ([]string, error) {
+	nss, err := r.LookupNS(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make([]string, len(nss))
+	for i, ns := range nss {
+		answers[i] = ns.Host
+	}
+	return answers, nil
+}
+
+func lookupTXT(ctx context.Context, r *net.Resolver, name string) ([]string, error) {
+	return r.LookupTXT(ctx, name)
+}
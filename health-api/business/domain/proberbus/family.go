@@ -0,0 +1,60 @@
+package proberbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// probeFamilies runs c over each of its address families at once and
+// merges the runs. The check only succeeds when every family does, so a
+// target that answers over IPv4 but not IPv6 fails instead of hiding behind
+// the family the resolver prefers. Steps are those of the first failed run,
+// or of the first run when all of them succeed.
+func (b *Business) probeFamilies(ctx context.Context, c Check) Result {
+	results := make([]Result, len(c.IPFamilies))
+
+	var wg sync.WaitGroup
+	for i, family := range c.IPFamilies {
+		fc := c
+		fc.IPFamilies = nil
+		fc.family = family
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = b.probe(ctx, fc)
+		}()
+	}
+	wg.Wait()
+
+	merged := results[0]
+	merged.Success = true
+
+	var failed []string
+	for i, r := range results {
+		merged.Families = append(merged.Families, FamilyResult{
+			Family:          c.IPFamilies[i],
+			Success:         r.Success,
+			DurationSeconds: r.DurationSeconds,
+			Error:           r.Error,
+		})
+		merged.DurationSeconds = max(merged.DurationSeconds, r.DurationSeconds)
+		if i > 0 {
+			merged.connects = append(merged.connects, r.connects...)
+		}
+
+		if !r.Success {
+			if merged.Success {
+				merged.Steps = r.Steps
+				merged.Banner = r.Banner
+			}
+			merged.Success = false
+			failed = append(failed, fmt.Sprintf("%s: %s", c.IPFamilies[i], r.Error))
+		}
+	}
+	merged.Error = strings.Join(failed, "; ")
+
+	return merged
+}
@@ -0,0 +1,204 @@
+package proberbus
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"health-api/business/sdk/tenant"
+)
+
+// minTokenLen is the shortest heartbeat token accepted from the config;
+// generated tokens are 64 hex characters.
+const minTokenLen = 32
+
+// Ping records a heartbeat for the named heartbeat check.
+func (b *Business) Ping(ctx context.Context, name string) (Result, error) {
+	return b.PingStatus(ctx, name, 0)
+}
+
+// PingStatus records a heartbeat carrying the status the sender reported.
+// Status 0 is healthy; any other value fails the check until the next
+// healthy ping.
+func (b *Business) PingStatus(ctx context.Context, name string, status uint8) (Result, error) {
+	now := time.Now()
+
+	if !b.hb.ping(name, now, status) {
+		return Result{}, ErrNotFound
+	}
+
+	r, _ := b.hb.result(name, now)
+
+	return r, nil
+}
+
+// PingToken records a heartbeat for the heartbeat check holding token. The
+// ping URL carries the token rather than the check name, so knowing a
+// check's name is not enough to keep it alive.
+func (b *Business) PingToken(ctx context.Context, token string) (Result, error) {
+	name, ok := b.hb.byToken(token)
+	if !ok {
+		return Result{}, ErrNotFound
+	}
+
+	return b.PingStatus(ctx, name, 0)
+}
+
+// Token returns the ping token of the named heartbeat check.
+func (b *Business) Token(ctx context.Context, name string) (string, error) {
+	c, ok := b.hb.check(name)
+	if !ok || !tenant.Get(ctx).Allows(c.Namespace) {
+		return "", ErrNotFound
+	}
+
+	return c.Token, nil
+}
+
+// newToken returns a random heartbeat token.
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// heartbeats tracks the pings of heartbeat checks. Pings are kept in memory,
+// so after a restart every heartbeat gets a full interval to check in.
+type heartbeats struct {
+	mu        sync.Mutex
+	checks    map[string]Check
+	pings     map[string]time.Time
+	statuses  map[string]uint8
+	startedAt time.Time
+}
+
+func newHeartbeats(checks []Check) *heartbeats {
+	hb := heartbeats{
+		checks:    make(map[string]Check),
+		pings:     make(map[string]time.Time),
+		statuses:  make(map[string]uint8),
+		startedAt: time.Now(),
+	}
+
+	for _, c := range checks {
+		if c.Type == TypeHeartbeat {
+			hb.checks[c.Name] = c
+		}
+	}
+
+	return &hb
+}
+
+func (hb *heartbeats) ping(name string, now time.Time, status uint8) bool {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	if _, ok := hb.checks[name]; !ok {
+		return false
+	}
+
+	hb.pings[name] = now
+	hb.statuses[name] = status
+
+	return true
+}
+
+// byToken returns the name of the heartbeat check holding token. Every
+// check is compared in constant time so the response time doesn't leak how
+// much of a token matched.
+func (hb *heartbeats) byToken(token string) (string, bool) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	var name string
+	for _, c := range hb.checks {
+		if c.Token == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(c.Token), []byte(token)) == 1 {
+			name = c.Name
+		}
+	}
+
+	return name, name != ""
+}
+
+func (hb *heartbeats) check(name string) (Check, bool) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	c, ok := hb.checks[name]
+	return c, ok
+}
+
+// results returns the current result of every heartbeat that has pinged or
+// is overdue.
+func (hb *heartbeats) results(now time.Time) []Result {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	var results []Result
+	for name := range hb.checks {
+		if r, ok := hb.resultLocked(name, now); ok {
+			results = append(results, r)
+		}
+	}
+
+	return results
+}
+
+func (hb *heartbeats) result(name string, now time.Time) (Result, bool) {
+	hb.mu.Lock()
+	defer hb.mu.Unlock()
+
+	return hb.resultLocked(name, now)
+}
+
+// resultLocked reports a heartbeat as down once its deadline passes without
+// a ping. A heartbeat that never pinged has no result until then.
+func (hb *heartbeats) resultLocked(name string, now time.Time) (Result, bool) {
+	c, ok := hb.checks[name]
+	if !ok {
+		return Result{}, false
+	}
+
+	last, pinged := hb.pings[name]
+	since := last
+	if !pinged {
+		since = hb.startedAt
+	}
+
+	deadline := since.Add(c.Interval + c.Grace)
+	overdue := now.After(deadline)
+
+	if !pinged && !overdue {
+		return Result{}, false
+	}
+
+	r := Result{
+		Check:     c.Name,
+		Type:      c.Type,
+		Namespace: c.Namespace,
+		Success:   true,
+		StartedAt: last.UTC(),
+	}
+
+	switch {
+	case overdue:
+		r.Success = false
+		r.StartedAt = now.UTC()
+		r.Error = fmt.Sprintf("no ping since %s", since.UTC().Format(time.RFC3339))
+
+	case hb.statuses[name] != 0:
+		r.Success = false
+		r.Error = fmt.Sprintf("reported status %d", hb.statuses[name])
+	}
+
+	return r, true
+}
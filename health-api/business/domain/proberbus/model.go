@@ -0,0 +1,143 @@
+package proberbus
+
+import (
+	"time"
+
+	"health-api/foundation/web"
+)
+
+// Set of check types the prober runs.
+const (
+	TypeTransaction = "transaction"
+	TypeDNS         = "dns"
+	TypeSMTP        = "smtp"
+	TypeHeartbeat   = "heartbeat"
+	TypeTCP         = "tcp"
+)
+
+// Check is a synthetic check run by the built-in prober. Heartbeat checks
+// are not run but pinged: they are down once Interval plus Grace passes
+// without a ping. Namespace assigns the check to the tenants owning it.
+// Proxy and SourceInterface route the check's connections, see web.Egress.
+// IPFamilies runs the check once over each listed family, "ipv4" and
+// "ipv6", instead of over the family the resolver prefers. Token
+// authenticates the pings of a heartbeat check.
+type Check struct {
+	Name            string        `yaml:"name" json:"name"`
+	Namespace       string        `yaml:"namespace" json:"namespace,omitempty"`
+	Type            string        `yaml:"type" json:"type"`
+	Interval        time.Duration `yaml:"interval" json:"interval"`
+	Timeout         time.Duration `yaml:"timeout" json:"timeout"`
+	Grace           time.Duration `yaml:"grace" json:"grace,omitempty"`
+	Proxy           string        `yaml:"proxy" json:"-"`
+	SourceInterface string        `yaml:"source_interface" json:"source_interface,omitempty"`
+	IPFamilies      []string      `yaml:"ip_families" json:"ip_families,omitempty"`
+	Steps           []Step        `yaml:"steps" json:"steps,omitempty"`
+	DNS             *DNSCheck     `yaml:"dns" json:"dns,omitempty"`
+	SMTP            *SMTPCheck    `yaml:"smtp" json:"smtp,omitempty"`
+	TCP             *TCPCheck     `yaml:"tcp" json:"tcp,omitempty"`
+	Token           string        `yaml:"token" json:"-"`
+
+	family string
+}
+
+func (c Check) egress() web.Egress {
+	return web.Egress{
+		Proxy:     c.Proxy,
+		Interface: c.SourceInterface,
+		Family:    c.family,
+	}
+}
+
+// Step is one HTTP request of a transaction check. URL, header values and
+// body may reference values captured by earlier steps as {{name}}.
+type Step struct {
+	Name         string            `yaml:"name" json:"name"`
+	Method       string            `yaml:"method" json:"method,omitempty"`
+	URL          string            `yaml:"url" json:"url"`
+	Headers      map[string]string `yaml:"headers" json:"headers,omitempty"`
+	Body         string            `yaml:"body" json:"body,omitempty"`
+	ExpectStatus []int             `yaml:"expect_status" json:"expect_status,omitempty"`
+	Assert       []Assertion       `yaml:"assert" json:"assert,omitempty"`
+	Capture      map[string]string `yaml:"capture" json:"capture,omitempty"`
+}
+
+// DNSCheck resolves Name and, when Expect is set, requires every expected
+// value among the answers. Record is one of A, AAAA, CNAME, MX, NS or TXT
+// and defaults to A. Server, as host:port, overrides the system resolver.
+type DNSCheck struct {
+	Name   string   `yaml:"name" json:"name"`
+	Record string   `yaml:"record" json:"record,omitempty"`
+	Server string   `yaml:"server" json:"server,omitempty"`
+	Expect []string `yaml:"expect" json:"expect,omitempty"`
+}
+
+// SMTPCheck connects to a mail server, optionally matching its greeting
+// against ExpectBanner and requiring a successful STARTTLS handshake.
+type SMTPCheck struct {
+	Address      string `yaml:"address" json:"address"`
+	ExpectBanner string `yaml:"expect_banner" json:"expect_banner,omitempty"`
+	StartTLS     bool   `yaml:"starttls" json:"starttls,omitempty"`
+	ServerName   string `yaml:"server_name" json:"server_name,omitempty"`
+}
+
+// TCPCheck connects to Address, as host:port, Attempts times per run,
+// default 3, for services such as databases that don't speak HTTP.
+type TCPCheck struct {
+	Address  string `yaml:"address" json:"address"`
+	Attempts int    `yaml:"attempts" json:"attempts,omitempty"`
+}
+
+// Assertion checks a field of a JSON response body. Path is a dotted path
+// where numeric elements index arrays, e.g. "data.items.0.id". Without
+// Equals the field only has to exist.
+type Assertion struct {
+	Path   string  `yaml:"path" json:"path"`
+	Equals *string `yaml:"equals" json:"equals,omitempty"`
+}
+
+// Result is the outcome of the most recent run of a check.
+type Result struct {
+	Check           string          `json:"check"`
+	Namespace       string          `json:"namespace,omitempty"`
+	Type            string          `json:"type"`
+	Success         bool            `json:"success"`
+	StartedAt       time.Time       `json:"started_at"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Error           string          `json:"error,omitempty"`
+	Answers         []string        `json:"answers,omitempty"`
+	Banner          string          `json:"banner,omitempty"`
+	Steps           []StepResult    `json:"steps,omitempty"`
+	Families        []FamilyResult  `json:"families,omitempty"`
+	Connect         *ConnectLatency `json:"connect,omitempty"`
+
+	// connects holds the connect times of a TCP run until they are added
+	// to the check's window.
+	connects []float64
+}
+
+// ConnectLatency holds the percentiles of a TCP check's connect times over
+// its recent connects.
+type ConnectLatency struct {
+	Samples    int     `json:"samples"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+}
+
+// FamilyResult records the outcome of a check over one address family.
+type FamilyResult struct {
+	Family          string  `json:"family"`
+	Success         bool    `json:"success"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// StepResult records the outcome and timing of one step.
+type StepResult struct {
+	Name            string  `json:"name"`
+	Success         bool    `json:"success"`
+	StatusCode      int     `json:"status_code,omitempty"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
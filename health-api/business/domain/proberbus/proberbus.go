@@ -0,0 +1,551 @@
+// Package proberbus provides the built-in prober, which runs synthetic checks
+// that a single blackbox exporter request can't express: multi-step HTTP
+// transactions, DNS resolution and SMTP conversations.
+package proberbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+	"health-api/foundation/web"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.yaml.in/yaml/v2"
+)
+
+// ErrNotFound is returned when a check does not exist or has not run yet.
+var ErrNotFound = errors.New("check not found")
+
+// ErrRunning is returned when a check asked to run now is already running.
+var ErrRunning = errors.New("check already running")
+
+// Set of defaults applied to checks that don't set them.
+const (
+	defaultInterval = time.Minute
+	defaultTimeout  = 10 * time.Second
+)
+
+var (
+	probeSuccess = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "health_api_probe_success",
+			Help: "Whether the last run of a built-in check succeeded",
+		},
+		[]string{"check", "type", "namespace"},
+	)
+
+	probeStepDuration = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "health_api_probe_step_duration_seconds",
+			Help: "Duration of each step of the last run of a built-in check",
+		},
+		[]string{"check", "step", "namespace"},
+	)
+
+	probeConnectDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "health_api_probe_tcp_connect_seconds",
+			Help:    "Time from SYN to ESTABLISHED of the connects of built-in TCP checks",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+		},
+		[]string{"check", "namespace"},
+	)
+
+	probesRunning = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "health_api_prober_running",
+			Help: "Number of built-in checks currently running, including timed out runs that haven't returned yet",
+		},
+	)
+)
+
+// Config holds the scheduling settings of the prober.
+type Config struct {
+	// MaxConcurrency bounds how many checks run at the same time. Zero
+	// leaves it unbounded.
+	MaxConcurrency int
+
+	// Jitter delays each run by a random fraction of the check's interval
+	// of up to this value, e.g. 0.1 for up to 10%.
+	Jitter float64
+}
+
+// Business runs synthetic checks and keeps the latest result of each.
+type Business struct {
+	log       *logger.Logger
+	checks    []Check
+	transport http.RoundTripper
+	targetBus *targetbus.Business
+	cfg       Config
+	slots     chan struct{}
+
+	mu       sync.RWMutex
+	results  map[string]Result
+	running  map[string]bool
+	connects map[string][]float64
+	hb       *heartbeats
+}
+
+// NewBusiness creates a prober for checks. HTTP requests go through
+// transport. Checks of targets paused in targetBus are skipped; targetBus
+// may be nil.
+func NewBusiness(log *logger.Logger, checks []Check, transport http.RoundTripper, targetBus *targetbus.Business, cfg Config) *Business {
+	for i := range checks {
+		if checks[i].Interval <= 0 {
+			checks[i].Interval = defaultInterval
+		}
+		if checks[i].Timeout <= 0 {
+			checks[i].Timeout = defaultTimeout
+		}
+	}
+
+	var slots chan struct{}
+	if cfg.MaxConcurrency > 0 {
+		slots = make(chan struct{}, cfg.MaxConcurrency)
+	}
+
+	return &Business{
+		log:       log,
+		checks:    checks,
+		transport: transport,
+		targetBus: targetBus,
+		cfg:       cfg,
+		slots:     slots,
+		results:   make(map[string]Result),
+		running:   make(map[string]bool),
+		connects:  make(map[string][]float64),
+		hb:        newHeartbeats(checks),
+	}
+}
+
+// LoadFile reads check definitions from a YAML config file. Environment
+// variables referenced as ${NAME} are expanded so credentials can be kept
+// out of the file.
+func LoadFile(path string) ([]Check, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading prober file: %w", err)
+	}
+
+	var file struct {
+		Checks []Check `yaml:"checks"`
+	}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &file); err != nil {
+		return nil, fmt.Errorf("parsing prober file: %w", err)
+	}
+
+	seen := make(map[string]bool, len(file.Checks))
+	for i, c := range file.Checks {
+		if err := c.validate(); err != nil {
+			return nil, fmt.Errorf("check %q: %w", c.Name, err)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("check %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = true
+
+		// A heartbeat without a configured token gets a random one, which
+		// changes on every restart.
+		if c.Type == TypeHeartbeat && c.Token == "" {
+			token, err := newToken()
+			if err != nil {
+				return nil, fmt.Errorf("check %q: %w", c.Name, err)
+			}
+			file.Checks[i].Token = token
+		}
+	}
+
+	return file.Checks, nil
+}
+
+// Checks returns the number of configured checks.
+func (b *Business) Checks() int {
+	return len(b.checks)
+}
+
+// Run runs every check on its interval until ctx is canceled.
+func (b *Business) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range b.checks {
+		if c.Type == TypeHeartbeat {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.schedule(ctx, c)
+		}()
+	}
+	wg.Wait()
+}
+
+// Query returns the latest result of every check that has run and is
+// visible to the caller, sorted by check name.
+func (b *Business) Query(ctx context.Context) []Result {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	results := make([]Result, 0, len(b.results))
+	for _, r := range b.results {
+		results = append(results, r)
+	}
+	results = append(results, b.hb.results(time.Now())...)
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Check < results[j].Check
+	})
+
+	return tenant.Filter(tenant.Get(ctx), results, func(r Result) string { return r.Namespace })
+}
+
+// QueryByName returns the latest result of the named check.
+func (b *Business) QueryByName(ctx context.Context, name string) (Result, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	r, ok := b.results[name]
+	if !ok {
+		r, ok = b.hb.result(name, time.Now())
+	}
+
+	if !ok || !tenant.Get(ctx).Allows(r.Namespace) {
+		return Result{}, ErrNotFound
+	}
+
+	return r, nil
+}
+
+// RunNow runs the named check immediately, outside its schedule, and
+// returns the new result. Heartbeats are pushed by their jobs rather than
+// run, so their latest result is returned as is; so is that of a paused
+// target.
+func (b *Business) RunNow(ctx context.Context, name string) (Result, error) {
+	i := slices.IndexFunc(b.checks, func(c Check) bool { return c.Name == name })
+	if i < 0 || !tenant.Get(ctx).Allows(b.checks[i].Namespace) {
+		return Result{}, ErrNotFound
+	}
+	c := b.checks[i]
+
+	if c.Type != TypeHeartbeat {
+		b.mu.RLock()
+		busy := b.running[c.Name]
+		b.mu.RUnlock()
+
+		if busy {
+			return Result{}, ErrRunning
+		}
+
+		b.runOnce(ctx, c)
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	return b.QueryByName(ctx, name)
+}
+
+// schedule runs c every interval until ctx is canceled. The first run is
+// offset into the interval by a hash of the check's name, so checks sharing
+// an interval are spread over it instead of all firing at once, and every
+// run is delayed by up to the configured jitter.
+func (b *Business) schedule(ctx context.Context, c Check) {
+	next := time.Now().Add(offset(c.Name, c.Interval))
+
+	for {
+		timer := time.NewTimer(time.Until(next) + b.jitter(c.Interval))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		b.runOnce(ctx, c)
+
+		// Runs missed while the check was queued or slow are skipped
+		// rather than fired back to back.
+		now := time.Now()
+		for next = next.Add(c.Interval); !next.After(now); next = next.Add(c.Interval) {
+		}
+	}
+}
+
+// runOnce runs c once it gets a worker slot. The check's timeout starts
+// with the run, not while it waits for a slot. A check that doesn't return
+// within its timeout is recorded as failed and gives up its slot, so a hung
+// target can't hold up the others; its next run is skipped until the hung
+// one returns. Runs of a paused target are skipped altogether.
+func (b *Business) runOnce(ctx context.Context, c Check) {
+	if b.paused(ctx, c.Name) {
+		return
+	}
+
+	b.mu.Lock()
+	busy := b.running[c.Name]
+	if !busy {
+		b.running[c.Name] = true
+	}
+	b.mu.Unlock()
+
+	if busy {
+		b.record(ctx, Result{
+			Check:     c.Name,
+			Namespace: c.Namespace,
+			Type:      c.Type,
+			StartedAt: time.Now().UTC(),
+			Error:     "previous run still in progress",
+		})
+		return
+	}
+
+	if b.slots != nil {
+		select {
+		case b.slots <- struct{}{}:
+		case <-ctx.Done():
+			b.mu.Lock()
+			delete(b.running, c.Name)
+			b.mu.Unlock()
+			return
+		}
+		defer func() { <-b.slots }()
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan Result, 1)
+
+	probesRunning.Inc()
+	go func() {
+		defer func() {
+			probesRunning.Dec()
+			b.mu.Lock()
+			delete(b.running, c.Name)
+			b.mu.Unlock()
+		}()
+
+		done <- b.probe(runCtx, c)
+	}()
+
+	var result Result
+	select {
+	case result = <-done:
+	case <-runCtx.Done():
+		if ctx.Err() != nil {
+			return
+		}
+		result = Result{
+			Check:           c.Name,
+			Type:            c.Type,
+			StartedAt:       started.UTC(),
+			DurationSeconds: time.Since(started).Seconds(),
+			Error:           fmt.Sprintf("timed out after %s", c.Timeout),
+		}
+	}
+	result.Namespace = c.Namespace
+
+	b.record(ctx, result)
+}
+
+func (b *Business) probe(ctx context.Context, c Check) Result {
+	if len(c.IPFamilies) > 0 {
+		return b.probeFamilies(ctx, c)
+	}
+
+	switch c.Type {
+	case TypeTransaction:
+		return b.runTransaction(ctx, c)
+	case TypeDNS:
+		return runDNS(ctx, c)
+	case TypeSMTP:
+		return runSMTP(ctx, c)
+	case TypeTCP:
+		return runTCP(ctx, c)
+	}
+	return Result{}
+}
+
+// paused reports whether the check's target is paused. A failed lookup
+// probes the target anyway.
+func (b *Business) paused(ctx context.Context, name string) bool {
+	if b.targetBus == nil {
+		return false
+	}
+
+	tgt, err := b.targetBus.QueryByName(tenant.Unscoped(ctx), name)
+	if err != nil {
+		if !errors.Is(err, targetbus.ErrNotFound) {
+			b.log.Warn(ctx, "proberbus", "status", "pause lookup failed", "check", name, "error", err)
+		}
+		return false
+	}
+
+	return tgt.Paused
+}
+
+// offset returns the check's fixed position within its interval.
+func offset(name string, interval time.Duration) time.Duration {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return time.Duration(h.Sum64() % uint64(interval))
+}
+
+func (b *Business) jitter(interval time.Duration) time.Duration {
+	if b.cfg.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Float64() * b.cfg.Jitter * float64(interval))
+}
+
+func (b *Business) record(ctx context.Context, result Result) {
+	success := 0.0
+	if result.Success {
+		success = 1
+	}
+	probeSuccess.WithLabelValues(result.Check, result.Type, result.Namespace).Set(success)
+
+	for _, s := range result.Steps {
+		probeStepDuration.WithLabelValues(result.Check, s.Name, result.Namespace).Set(s.DurationSeconds)
+	}
+
+	for _, d := range result.connects {
+		probeConnectDuration.WithLabelValues(result.Check, result.Namespace).Observe(d)
+	}
+
+	if !result.Success {
+		b.log.Info(ctx, "proberbus", "status", "check failed", "check", result.Check, "error", result.Error)
+	}
+
+	b.mu.Lock()
+	if result.Type == TypeTCP {
+		result.Connect = b.connectLatency(result.Check, result.connects)
+		result.connects = nil
+	}
+	b.results[result.Check] = result
+	b.mu.Unlock()
+}
+
+// =============================================================================
+
+func (c Check) validate() error {
+	if c.Name == "" {
+		return errors.New("name required")
+	}
+
+	switch c.Type {
+	case TypeTransaction:
+		if len(c.Steps) == 0 {
+			return errors.New("at least one step required")
+		}
+		for i, s := range c.Steps {
+			if s.URL == "" {
+				return fmt.Errorf("step %d: url required", i)
+			}
+		}
+
+	case TypeDNS:
+		if c.DNS == nil || c.DNS.Name == "" {
+			return errors.New("dns.name required")
+		}
+		if _, ok := dnsRecords[strings.ToUpper(c.DNS.Record)]; !ok && c.DNS.Record != "" {
+			return fmt.Errorf("unsupported dns record %q", c.DNS.Record)
+		}
+
+	case TypeSMTP:
+		if c.SMTP == nil || c.SMTP.Address == "" {
+			return errors.New("smtp.address required")
+		}
+
+	case TypeTCP:
+		if c.TCP == nil || c.TCP.Address == "" {
+			return errors.New("tcp.address required")
+		}
+		if _, _, err := net.SplitHostPort(c.TCP.Address); err != nil {
+			return fmt.Errorf("tcp.address: %w", err)
+		}
+		if c.TCP.Attempts < 0 {
+			return errors.New("tcp.attempts must not be negative")
+		}
+		if c.Proxy != "" {
+			return errors.New("tcp checks can't use a proxy, it would time the proxy")
+		}
+
+	case TypeHeartbeat:
+		if c.Interval <= 0 {
+			return errors.New("interval required")
+		}
+		if c.Token != "" && len(c.Token) < minTokenLen {
+			return fmt.Errorf("token must be at least %d characters", minTokenLen)
+		}
+		if !c.egress().IsZero() {
+			return errors.New("heartbeat checks make no connections to route")
+		}
+
+	default:
+		return fmt.Errorf("unknown type %q", c.Type)
+	}
+
+	if c.Token != "" && c.Type != TypeHeartbeat {
+		return errors.New("token is only used by heartbeat checks")
+	}
+
+	if len(c.IPFamilies) > 0 {
+		if c.Type != TypeTransaction && c.Type != TypeSMTP && c.Type != TypeTCP {
+			return fmt.Errorf("ip_families is not supported by %s checks", c.Type)
+		}
+		if c.Proxy != "" {
+			return errors.New("ip_families can't be combined with a proxy")
+		}
+
+		seen := make(map[string]bool)
+		for _, f := range c.IPFamilies {
+			if f != web.FamilyIPv4 && f != web.FamilyIPv6 {
+				return fmt.Errorf("unknown ip family %q", f)
+			}
+			if seen[f] {
+				return fmt.Errorf("duplicate ip family %q", f)
+			}
+			seen[f] = true
+		}
+	}
+
+	switch c.Type {
+	case TypeTransaction:
+		if _, err := web.WithEgress(http.DefaultTransport.(*http.Transport), c.egress()); err != nil {
+			return err
+		}
+
+	case TypeDNS:
+		if c.Proxy != "" {
+			return errors.New("dns checks can't use a proxy")
+		}
+		if _, err := c.egress().DialContext(); err != nil {
+			return err
+		}
+
+	case TypeSMTP, TypeTCP:
+		if _, err := c.egress().DialContext(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package proberbus
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// runSMTP connects to the mail server, reads its greeting, introduces itself
+// and, when required, upgrades the connection with STARTTLS. Each phase is
+// recorded as a step.
+func runSMTP(ctx context.Context, c Check) Result {
+	result := Result{
+		Check:     c.Name,
+		Type:      c.Type,
+		StartedAt: time.Now().UTC(),
+	}
+
+	dial, err := c.egress().DialContext()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	s := smtpSession{check: c.SMTP, dial: dial}
+	defer s.close()
+
+	phases := []smtpPhase{
+		{"connect", s.connect},
+		{"banner", s.banner},
+		{"ehlo", s.ehlo},
+	}
+	if c.SMTP.StartTLS {
+		phases = append(phases, smtpPhase{"starttls", s.startTLS})
+	}
+
+	for _, p := range phases {
+		start := time.Now()
+		err := p.fn(ctx)
+
+		sr := StepResult{
+			Name:            p.name,
+			Success:         err == nil,
+			DurationSeconds: time.Since(start).Seconds(),
+		}
+		if err != nil {
+			sr.Error = err.Error()
+			result.Error = fmt.Sprintf("step %s: %s", p.name, err)
+		}
+		result.Steps = append(result.Steps, sr)
+
+		if err != nil {
+			break
+		}
+	}
+
+	result.Banner = s.greeting
+	result.DurationSeconds = time.Since(result.StartedAt).Seconds()
+	result.Success = result.Error == ""
+
+	if result.Success {
+		s.quit()
+	}
+
+	return result
+}
+
+// smtpPhase is one recorded part of the conversation.
+type smtpPhase struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// smtpSession holds the state of one probe conversation.
+type smtpSession struct {
+	check    *SMTPCheck
+	dial     func(ctx context.Context, network, addr string) (net.Conn, error)
+	conn     net.Conn
+	text     *textproto.Conn
+	greeting string
+	ext      string
+}
+
+func (s *smtpSession) connect(ctx context.Context) error {
+	conn, err := s.dial(ctx, "tcp", s.check.Address)
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	s.conn = conn
+	s.text = textproto.NewConn(conn)
+
+	return nil
+}
+
+func (s *smtpSession) banner(ctx context.Context) error {
+	_, msg, err := s.text.ReadResponse(220)
+	if err != nil {
+		return fmt.Errorf("greeting: %w", err)
+	}
+	s.greeting = msg
+
+	if s.check.ExpectBanner != "" && !strings.Contains(msg, s.check.ExpectBanner) {
+		return fmt.Errorf("banner %q does not contain %q", msg, s.check.ExpectBanner)
+	}
+
+	return nil
+}
+
+func (s *smtpSession) ehlo(ctx context.Context) error {
+	msg, err := s.cmd(250, "EHLO health-api")
+	if err != nil {
+		return err
+	}
+	s.ext = msg
+
+	return nil
+}
+
+func (s *smtpSession) startTLS(ctx context.Context) error {
+	if !strings.Contains(strings.ToUpper(s.ext), "STARTTLS") {
+		return fmt.Errorf("server does not advertise STARTTLS")
+	}
+
+	if _, err := s.cmd(220, "STARTTLS"); err != nil {
+		return err
+	}
+
+	serverName := s.check.ServerName
+	if serverName == "" {
+		serverName, _, _ = net.SplitHostPort(s.check.Address)
+	}
+
+	tlsConn := tls.Client(s.conn, &tls.Config{ServerName: serverName})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("tls handshake: %w", err)
+	}
+
+	s.conn = tlsConn
+	s.text = textproto.NewConn(tlsConn)
+
+	// The session starts over after the upgrade
+	return s.ehlo(ctx)
+}
+
+func (s *smtpSession) quit() {
+	s.cmd(221, "QUIT")
+}
+
+func (s *smtpSession) close() {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+}
+
+func (s *smtpSession) cmd(expect int, format string, args ...any) (string, error) {
+	id, err := s.text.Cmd(format, args...)
+	if err != nil {
+		return "", err
+	}
+
+	s.text.StartResponse(id)
+	defer s.text.EndResponse(id)
+
+	_, msg, err := s.text.ReadResponse(expect)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", strings.Fields(format)[0], err)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,121 @@
+package proberbus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"slices"
+	"time"
+
+	"health-api/foundation/web"
+)
+
+// Set of TCP check settings.
+const (
+	defaultConnectAttempts = 3
+
+	// connectWindow is how many recent connects the percentiles cover.
+	connectWindow = 100
+)
+
+// runTCP connects to the address the configured number of times and
+// records how long each connect took. The host is resolved once up front,
+// so the timings cover the handshake alone, from SYN to ESTABLISHED. The
+// check fails when any connect fails.
+func runTCP(ctx context.Context, c Check) (result Result) {
+	result = Result{
+		Check:     c.Name,
+		Type:      c.Type,
+		StartedAt: time.Now().UTC(),
+	}
+
+	defer func() {
+		result.DurationSeconds = time.Since(result.StartedAt).Seconds()
+		result.Success = result.Error == ""
+	}()
+
+	addr, err := resolveTCP(ctx, c.TCP.Address, c.family)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	dial, err := c.egress().DialContext()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	attempts := c.TCP.Attempts
+	if attempts <= 0 {
+		attempts = defaultConnectAttempts
+	}
+
+	for i := range attempts {
+		start := time.Now()
+		conn, err := dial(ctx, "tcp", addr)
+		if err != nil {
+			result.Error = fmt.Sprintf("connect %d: %s", i+1, err)
+			return result
+		}
+		result.connects = append(result.connects, time.Since(start).Seconds())
+		conn.Close()
+	}
+
+	return result
+}
+
+// resolveTCP returns address with its host resolved to an IP of family, or
+// of any family when family is empty.
+func resolveTCP(ctx context.Context, address string, family string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+
+	network := "ip"
+	switch family {
+	case web.FamilyIPv4:
+		network = "ip4"
+	case web.FamilyIPv6:
+		network = "ip6"
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, network, host)
+	if err != nil {
+		return "", fmt.Errorf("resolve: %w", err)
+	}
+
+	return net.JoinHostPort(ips[0].Unmap().String(), port), nil
+}
+
+// connectLatency adds the connect times of a run to the check's window and
+// returns the percentiles over it. The caller must hold b.mu.
+func (b *Business) connectLatency(check string, connects []float64) *ConnectLatency {
+	window := append(b.connects[check], connects...)
+	if len(window) > connectWindow {
+		window = window[len(window)-connectWindow:]
+	}
+	b.connects[check] = window
+
+	if len(window) == 0 {
+		return nil
+	}
+
+	sorted := slices.Clone(window)
+	slices.Sort(sorted)
+
+	return &ConnectLatency{
+		Samples:    len(sorted),
+		P50Seconds: percentile(sorted, 0.50),
+		P95Seconds: percentile(sorted, 0.95),
+		P99Seconds: percentile(sorted, 0.99),
+	}
+}
+
+// percentile returns the nearest-rank p percentile of sorted.
+func percentile(sorted []float64, p float64) float64 {
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(i, 0)]
+}
@@ -0,0 +1,239 @@
+package proberbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"health-api/foundation/web"
+)
+
+// maxBodySize bounds how much of a response body a step reads.
+const maxBodySize = 1 << 20
+
+// runTransaction runs the steps of c in order, stopping at the first failed
+// step. Cookies and captured values are carried from one step to the next,
+// so a login step can authenticate the steps that follow it.
+func (b *Business) runTransaction(ctx context.Context, c Check) Result {
+	result := Result{
+		Check:     c.Name,
+		Type:      c.Type,
+		StartedAt: time.Now().UTC(),
+	}
+
+	transport := b.transport
+	if e := c.egress(); !e.IsZero() {
+		t, err := b.egressTransport(e)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		defer t.CloseIdleConnections()
+		transport = t
+	}
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Transport: transport,
+		Jar:       jar,
+	}
+
+	vars := make(map[string]string)
+
+	for i, step := range c.Steps {
+		if step.Name == "" {
+			step.Name = strconv.Itoa(i + 1)
+		}
+
+		sr := runStep(ctx, client, step, vars)
+		result.Steps = append(result.Steps, sr)
+
+		if !sr.Success {
+			result.Error = fmt.Sprintf("step %s: %s", sr.Name, sr.Error)
+			break
+		}
+	}
+
+	result.DurationSeconds = time.Since(result.StartedAt).Seconds()
+	result.Success = result.Error == ""
+
+	return result
+}
+
+// egressTransport returns a transport for checks with their own route. It
+// is made per run and closed after it, so no connections outlive the run.
+func (b *Business) egressTransport(e web.Egress) (*http.Transport, error) {
+	base := http.DefaultTransport.(*http.Transport)
+	if t, ok := b.transport.(*http.Transport); ok {
+		base = t
+	}
+	return web.WithEgress(base, e)
+}
+
+func runStep(ctx context.Context, client *http.Client, step Step, vars map[string]string) (sr StepResult) {
+	sr.Name = step.Name
+
+	start := time.Now()
+	defer func() {
+		sr.DurationSeconds = time.Since(start).Seconds()
+	}()
+
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, expand(step.URL, vars), strings.NewReader(expand(step.Body, vars)))
+	if err != nil {
+		sr.Error = fmt.Sprintf("creating request: %s", err)
+		return sr
+	}
+
+	for k, v := range step.Headers {
+		req.Header.Set(k, expand(v, vars))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	defer resp.Body.Close()
+
+	sr.StatusCode = resp.StatusCode
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		sr.Error = fmt.Sprintf("reading body: %s", err)
+		return sr
+	}
+
+	if err := checkStatus(step.ExpectStatus, resp.StatusCode); err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+
+	if len(step.Assert) > 0 || len(step.Capture) > 0 {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			sr.Error = fmt.Sprintf("decoding json body: %s", err)
+			return sr
+		}
+
+		for _, a := range step.Assert {
+			if err := a.check(doc); err != nil {
+				sr.Error = err.Error()
+				return sr
+			}
+		}
+
+		for name, path := range step.Capture {
+			v, ok := lookup(doc, path)
+			if !ok {
+				sr.Error = fmt.Sprintf("capture %s: field %s not found", name, path)
+				return sr
+			}
+			vars[name] = stringify(v)
+		}
+	}
+
+	sr.Success = true
+
+	return sr
+}
+
+// checkStatus accepts any 2xx status when no statuses are expected.
+func checkStatus(expect []int, status int) error {
+	if len(expect) == 0 {
+		if status < 200 || status > 299 {
+			return fmt.Errorf("unexpected status %d", status)
+		}
+		return nil
+	}
+
+	if !slices.Contains(expect, status) {
+		return fmt.Errorf("unexpected status %d, want %v", status, expect)
+	}
+
+	return nil
+}
+
+func (a Assertion) check(doc any) error {
+	v, ok := lookup(doc, a.Path)
+	if !ok {
+		return fmt.Errorf("assert %s: field not found", a.Path)
+	}
+
+	if a.Equals != nil {
+		if got := stringify(v); got != *a.Equals {
+			return fmt.Errorf("assert %s: got %q, want %q", a.Path, got, *a.Equals)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+var varPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// expand replaces {{name}} with the captured value of name. Unknown names
+// are left as they are.
+func expand(s string, vars map[string]string) string {
+	return varPattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := varPattern.FindStringSubmatch(m)[1]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// lookup resolves a dotted path in a decoded JSON document.
+func lookup(doc any, path string) (any, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+func stringify(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,91 @@
+// Package prometheusbus provides business logic for querying raw Prometheus
+// metrics, as served by the original Prometheus-only server.
+package prometheusbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// ErrInvalidMetric is returned when the metric name is not a valid
+// Prometheus metric name.
+var ErrInvalidMetric = errors.New("invalid metric name")
+
+// metricName matches valid Prometheus metric names. Only plain names are
+// accepted so callers cannot run arbitrary PromQL.
+var metricName = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// Storer defines the interface for running instant queries.
+type Storer interface {
+	QueryMetric(ctx context.Context, query string, at time.Time) ([]Sample, error)
+}
+
+// Business manages metric queries.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness creates a new prometheus business layer.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// QueryMetric returns the current samples of the named metric. Callers
+// scoped to a tenant only see series from their namespaces.
+func (b *Business) QueryMetric(ctx context.Context, name string) (Metric, error) {
+	if !metricName.MatchString(name) {
+		return Metric{}, fmt.Errorf("query: metric[%s]: %w", name, ErrInvalidMetric)
+	}
+
+	query := name
+	if s := tenant.Get(ctx); s.Restricted() {
+		query = fmt.Sprintf(`%s{namespace=~"%s"}`, name, namespaceRegex(s.Namespaces))
+	}
+
+	samples, err := b.storer.QueryMetric(ctx, query, time.Now())
+	if err != nil {
+		return Metric{}, fmt.Errorf("query: metric[%s]: %w", name, err)
+	}
+
+	return Metric{
+		Name:    name,
+		Samples: samples,
+	}, nil
+}
+
+// namespaceRegex builds an anchored alternation matching exactly the
+// namespaces, escaping any regex metacharacters.
+func namespaceRegex(namespaces []string) string {
+	quoted := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		quoted[i] = strings.ReplaceAll(regexp.QuoteMeta(ns), `\`, `\\`)
+	}
+
+	return strings.Join(quoted, "|")
+}
+
+// =============================================================================
+
+// Metric holds the current samples of a metric.
+type Metric struct {
+	Name    string
+	Samples []Sample
+}
+
+// Sample is a single series value of a metric.
+type Sample struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
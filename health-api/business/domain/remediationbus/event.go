@@ -0,0 +1,36 @@
+package remediationbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"health-api/business/domain/healthbus"
+	"health-api/business/sdk/delegate"
+)
+
+// registerDelegateFunctions will register action functions with the
+// delegate system.
+func (b *Business) registerDelegateFunctions() {
+	if b.delegate != nil {
+		b.delegate.Register(healthbus.DomainName, healthbus.ActionStatusChanged, b.actionStatusChanged)
+	}
+}
+
+// actionStatusChanged tracks which targets are down. Suppressed targets are
+// down because of an upstream target, which is the one to remediate.
+func (b *Business) actionStatusChanged(ctx context.Context, data delegate.Data) error {
+	var params healthbus.ActionStatusChangedParms
+	if err := json.Unmarshal(data.RawParams, &params); err != nil {
+		return fmt.Errorf("expected an encoded %T: %w", params, err)
+	}
+
+	if params.To == healthbus.StatusDown && !params.Check.Suppressed {
+		b.markDown(params.Target, params.Check.Namespace, params.At)
+		return nil
+	}
+
+	b.markRecovered(ctx, params.Target)
+
+	return nil
+}
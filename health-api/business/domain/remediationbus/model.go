@@ -0,0 +1,184 @@
+package remediationbus
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Set of remediation action kinds.
+const (
+	KindWebhook = "webhook"
+	KindRestart = "restart"
+	KindJob     = "job"
+)
+
+// Set of attempt results.
+const (
+	ResultSucceeded = "succeeded"
+	ResultFailed    = "failed"
+	ResultExhausted = "exhausted"
+)
+
+// Set of rule defaults.
+const (
+	defaultAfter       = 5 * time.Minute
+	defaultCooldown    = 15 * time.Minute
+	defaultMaxAttempts = 3
+)
+
+// Rule runs an action for the targets matching Targets once they have been
+// down for After. While a target stays down the action is repeated every
+// Cooldown, up to MaxAttempts times; recovery resets the count. Exactly one
+// of Webhook, Restart and Job is set.
+type Rule struct {
+	Name        string         `yaml:"name" json:"name"`
+	Targets     []string       `yaml:"targets" json:"targets"`
+	After       time.Duration  `yaml:"after" json:"after"`
+	Cooldown    time.Duration  `yaml:"cooldown" json:"cooldown"`
+	MaxAttempts int            `yaml:"max_attempts" json:"max_attempts"`
+	Webhook     *WebhookAction `yaml:"webhook" json:"webhook,omitempty"`
+	Restart     *RestartAction `yaml:"restart" json:"restart,omitempty"`
+	Job         *JobAction     `yaml:"job" json:"job,omitempty"`
+
+	patterns []*regexp.Regexp
+}
+
+// WebhookAction posts the attempt as JSON to URL.
+type WebhookAction struct {
+	URL string `yaml:"url" json:"-"`
+}
+
+// RestartAction restarts a Deployment the way kubectl rollout restart does.
+type RestartAction struct {
+	Namespace  string `yaml:"namespace" json:"namespace"`
+	Deployment string `yaml:"deployment" json:"deployment"`
+}
+
+// JobAction runs a Job from the template of a CronJob the way kubectl
+// create job --from does.
+type JobAction struct {
+	Namespace string `yaml:"namespace" json:"namespace"`
+	CronJob   string `yaml:"cronjob" json:"cronjob"`
+}
+
+// Kind returns the kind of the rule's action.
+func (r Rule) Kind() string {
+	switch {
+	case r.Webhook != nil:
+		return KindWebhook
+	case r.Restart != nil:
+		return KindRestart
+	case r.Job != nil:
+		return KindJob
+	}
+	return ""
+}
+
+// matches reports whether the rule covers target. Targets are patterns in
+// which * stands for any run of characters, slashes included, so
+// "https://shop.example.com/*" covers every path of the host.
+func (r Rule) matches(target string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// globPattern compiles a target pattern.
+func globPattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// withDefaults returns the rule with unset durations and limits defaulted
+// and its target patterns compiled.
+func (r Rule) withDefaults() Rule {
+	r.patterns = make([]*regexp.Regexp, len(r.Targets))
+	for i, pattern := range r.Targets {
+		r.patterns[i] = globPattern(pattern)
+	}
+
+	if r.After == 0 {
+		r.After = defaultAfter
+	}
+	if r.Cooldown == 0 {
+		r.Cooldown = defaultCooldown
+	}
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = defaultMaxAttempts
+	}
+	return r
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return errors.New("name required")
+	}
+
+	if len(r.Targets) == 0 {
+		return errors.New("at least one target required")
+	}
+
+	if r.After < 0 || r.Cooldown < 0 || r.MaxAttempts < 0 {
+		return errors.New("after, cooldown and max_attempts must not be negative")
+	}
+
+	actions := 0
+	if r.Webhook != nil {
+		actions++
+		if u, err := url.Parse(r.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("webhook.url must be an http or https URL")
+		}
+	}
+	if r.Restart != nil {
+		actions++
+		if r.Restart.Namespace == "" || r.Restart.Deployment == "" {
+			return errors.New("restart.namespace and restart.deployment required")
+		}
+	}
+	if r.Job != nil {
+		actions++
+		if r.Job.Namespace == "" || r.Job.CronJob == "" {
+			return errors.New("job.namespace and job.cronjob required")
+		}
+	}
+	if actions != 1 {
+		return errors.New("exactly one of webhook, restart and job required")
+	}
+
+	return nil
+}
+
+// Attempt is the audit record of a remediation. Attempt counts the runs of
+// the rule within the current failure of the target. Exhausted records mark
+// the point where MaxAttempts was reached and no further runs follow.
+type Attempt struct {
+	ID           string    `json:"id"`
+	Rule         string    `json:"rule"`
+	Target       string    `json:"target"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Kind         string    `json:"kind"`
+	Object       string    `json:"object,omitempty"`
+	Attempt      int       `json:"attempt"`
+	MaxAttempts  int       `json:"max_attempts"`
+	FailingSince time.Time `json:"failing_since"`
+	At           time.Time `json:"at"`
+	Result       string    `json:"result"`
+	Error        string    `json:"error,omitempty"`
+	Duration     float64   `json:"duration_seconds"`
+}
+
+// QueryFilter narrows the audit records returned.
+type QueryFilter struct {
+	Target *string
+	Rule   *string
+	Since  *time.Time
+}
@@ -0,0 +1,382 @@
+// Package remediationbus provides business logic for automatic remediation:
+// configured actions that run when a target stays down, with cooldowns,
+// attempt limits and an audit record of every run.
+package remediationbus
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// Set of timings of the remediation loop.
+const (
+	evaluateInterval = 15 * time.Second
+	actionTimeout    = 30 * time.Second
+)
+
+// errNoCluster is the error of Kubernetes actions without a cluster.
+var errNoCluster = errors.New("kubernetes is not configured")
+
+// Storer defines the interface for the audit records.
+type Storer interface {
+	Create(ctx context.Context, a Attempt) error
+	Query(ctx context.Context, filter QueryFilter) ([]Attempt, error)
+}
+
+// Cluster runs the Kubernetes actions.
+type Cluster interface {
+	RestartDeployment(ctx context.Context, namespace string, name string) error
+	RunJob(ctx context.Context, namespace string, cronJob string) (string, error)
+}
+
+// Business runs remediation rules.
+type Business struct {
+	log        *logger.Logger
+	delegate   *delegate.Delegate
+	storer     Storer
+	cluster    Cluster
+	rules      []Rule
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	failing map[string]*failure
+}
+
+// failure tracks a down target and the runs of the rules covering it.
+type failure struct {
+	since     time.Time
+	namespace string
+	runs      map[string]*run
+}
+
+type run struct {
+	attempts  int
+	last      time.Time
+	exhausted bool
+}
+
+// NewBusiness creates a new remediation business layer and registers for
+// health status changes. The cluster may be nil when no rule restarts
+// Deployments or runs Jobs.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer, cluster Cluster, rules []Rule) *Business {
+	for i, r := range rules {
+		rules[i] = r.withDefaults()
+	}
+
+	b := Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+		cluster:  cluster,
+		rules:    rules,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		failing: make(map[string]*failure),
+	}
+
+	b.registerDelegateFunctions()
+
+	return &b
+}
+
+// LoadFile reads remediation rules from a YAML file with a top level
+// remediations list. Environment variables in the file are expanded.
+func LoadFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading remediation file: %w", err)
+	}
+
+	var file struct {
+		Remediations []Rule `yaml:"remediations"`
+	}
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &file); err != nil {
+		return nil, fmt.Errorf("parsing remediation file: %w", err)
+	}
+
+	seen := make(map[string]bool, len(file.Remediations))
+	for _, r := range file.Remediations {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("remediation %q: %w", r.Name, err)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("remediation %q: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+	}
+
+	return file.Remediations, nil
+}
+
+// NeedsCluster reports whether any of the rules acts on Kubernetes.
+func NeedsCluster(rules []Rule) bool {
+	for _, r := range rules {
+		if r.Restart != nil || r.Job != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the configured rules.
+func (b *Business) Rules() []Rule {
+	return b.rules
+}
+
+// Run evaluates the rules against the failing targets until ctx is
+// canceled.
+func (b *Business) Run(ctx context.Context) {
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			b.evaluate(ctx, now)
+		}
+	}
+}
+
+// Query returns the audit records matching the filter that are visible to
+// the caller, newest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Attempt, error) {
+	attempts, err := b.storer.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return tenant.Filter(tenant.Get(ctx), attempts, func(a Attempt) string { return a.Namespace }), nil
+}
+
+// =============================================================================
+
+// markDown records that target is down since at. A target already failing
+// keeps its start.
+func (b *Business) markDown(target string, namespace string, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.failing[target]; ok {
+		return
+	}
+
+	b.failing[target] = &failure{
+		since:     at,
+		namespace: namespace,
+		runs:      make(map[string]*run),
+	}
+}
+
+// markRecovered forgets a failing target, which resets its attempt counts.
+func (b *Business) markRecovered(ctx context.Context, target string) {
+	b.mu.Lock()
+	f, ok := b.failing[target]
+	delete(b.failing, target)
+	b.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	for name, r := range f.runs {
+		if r.attempts > 0 {
+			b.log.Info(ctx, "remediationbus", "status", "target recovered after remediation", "target", target, "rule", name, "attempts", r.attempts)
+		}
+	}
+}
+
+// evaluate runs every rule that is due for a failing target.
+func (b *Business) evaluate(ctx context.Context, now time.Time) {
+	var due []Attempt
+
+	b.mu.Lock()
+	for target, f := range b.failing {
+		for _, rule := range b.rules {
+			if !rule.matches(target) || now.Sub(f.since) < rule.After {
+				continue
+			}
+
+			r, ok := f.runs[rule.Name]
+			if !ok {
+				r = &run{}
+				f.runs[rule.Name] = r
+			}
+
+			if r.exhausted || (r.attempts > 0 && now.Sub(r.last) < rule.Cooldown) {
+				continue
+			}
+
+			a := Attempt{
+				ID:           newID(),
+				Rule:         rule.Name,
+				Target:       target,
+				Namespace:    f.namespace,
+				Kind:         rule.Kind(),
+				MaxAttempts:  rule.MaxAttempts,
+				FailingSince: f.since,
+				At:           now.UTC(),
+			}
+
+			// The last run had its cooldown to take effect; the target is
+			// still down, so give up until it recovers.
+			if r.attempts >= rule.MaxAttempts {
+				r.exhausted = true
+				a.Attempt = r.attempts
+				a.Result = ResultExhausted
+				due = append(due, a)
+				continue
+			}
+
+			r.attempts++
+			r.last = now
+			a.Attempt = r.attempts
+			due = append(due, a)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, a := range due {
+		if a.Result == "" {
+			a = b.execute(ctx, a)
+		}
+		b.audit(ctx, a)
+	}
+}
+
+// execute runs the action of the attempt's rule and returns the attempt
+// with its outcome.
+func (b *Business) execute(ctx context.Context, a Attempt) Attempt {
+	var rule Rule
+	for _, r := range b.rules {
+		if r.Name == a.Rule {
+			rule = r
+			break
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, actionTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	var err error
+	switch {
+	case rule.Webhook != nil:
+		err = b.postWebhook(ctx, rule.Webhook.URL, a)
+
+	case rule.Restart != nil:
+		a.Object = "deployment/" + rule.Restart.Namespace + "/" + rule.Restart.Deployment
+		if b.cluster == nil {
+			err = errNoCluster
+			break
+		}
+		err = b.cluster.RestartDeployment(ctx, rule.Restart.Namespace, rule.Restart.Deployment)
+
+	case rule.Job != nil:
+		a.Object = "cronjob/" + rule.Job.Namespace + "/" + rule.Job.CronJob
+		if b.cluster == nil {
+			err = errNoCluster
+			break
+		}
+		var job string
+		job, err = b.cluster.RunJob(ctx, rule.Job.Namespace, rule.Job.CronJob)
+		if err == nil {
+			a.Object = "job/" + rule.Job.Namespace + "/" + job
+		}
+	}
+
+	a.Duration = time.Since(start).Seconds()
+	a.Result = ResultSucceeded
+	if err != nil {
+		a.Result = ResultFailed
+		a.Error = err.Error()
+	}
+
+	return a
+}
+
+// audit logs and stores the attempt.
+func (b *Business) audit(ctx context.Context, a Attempt) {
+	args := []any{"id", a.ID, "rule", a.Rule, "target", a.Target, "kind", a.Kind, "object", a.Object, "attempt", a.Attempt, "max_attempts", a.MaxAttempts, "failing_since", a.FailingSince}
+
+	switch a.Result {
+	case ResultSucceeded:
+		b.log.Info(ctx, "remediationbus", append([]any{"status", "remediation ran"}, args...)...)
+	case ResultFailed:
+		b.log.Error(ctx, "remediationbus", append([]any{"status", "remediation failed", "error", a.Error}, args...)...)
+	case ResultExhausted:
+		b.log.Warn(ctx, "remediationbus", append([]any{"status", "remediation attempts exhausted"}, args...)...)
+	}
+
+	if err := b.storer.Create(ctx, a); err != nil {
+		b.log.Error(ctx, "remediationbus", "status", "storing audit record failed", "id", a.ID, "error", err)
+	}
+}
+
+// postWebhook posts the attempt to url, before its outcome is known.
+func (b *Business) postWebhook(ctx context.Context, url string, a Attempt) error {
+	payload := struct {
+		ID           string    `json:"id"`
+		Rule         string    `json:"rule"`
+		Target       string    `json:"target"`
+		Namespace    string    `json:"namespace,omitempty"`
+		Attempt      int       `json:"attempt"`
+		MaxAttempts  int       `json:"max_attempts"`
+		FailingSince time.Time `json:"failing_since"`
+	}{
+		ID:           a.ID,
+		Rule:         a.Rule,
+		Target:       a.Target,
+		Namespace:    a.Namespace,
+		Attempt:      a.Attempt,
+		MaxAttempts:  a.MaxAttempts,
+		FailingSince: a.FailingSince,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal attempt: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
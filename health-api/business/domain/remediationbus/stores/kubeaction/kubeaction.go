@@ -0,0 +1,133 @@
+// Package kubeaction implements the remediation actions on the Kubernetes
+// API.
+package kubeaction
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"health-api/foundation/kube"
+	"health-api/foundation/logger"
+)
+
+// restartedAtAnnotation is the pod template annotation kubectl rollout
+// restart sets; changing it rolls the Deployment's pods.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// Cluster implements remediationbus.Cluster using the Kubernetes API.
+type Cluster struct {
+	log    *logger.Logger
+	client *kube.Client
+}
+
+// NewCluster creates a new Kubernetes-backed remediation cluster.
+func NewCluster(log *logger.Logger, client *kube.Client) *Cluster {
+	return &Cluster{
+		log:    log,
+		client: client,
+	}
+}
+
+// RestartDeployment rolls the pods of the Deployment.
+func (c *Cluster) RestartDeployment(ctx context.Context, namespace string, name string) error {
+	patch := map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	}
+
+	path := "/apis/apps/v1/namespaces/" + url.PathEscape(namespace) + "/deployments/" + url.PathEscape(name)
+	if err := c.client.Patch(ctx, path, patch, nil); err != nil {
+		return fmt.Errorf("restart deployment: %w", err)
+	}
+
+	return nil
+}
+
+// RunJob creates a Job from the template of the CronJob and returns its
+// name.
+func (c *Cluster) RunJob(ctx context.Context, namespace string, cronJob string) (string, error) {
+	var cj cronJobObject
+	if err := c.client.Get(ctx, "/apis/batch/v1/namespaces/"+url.PathEscape(namespace)+"/cronjobs/"+url.PathEscape(cronJob), nil, &cj); err != nil {
+		return "", fmt.Errorf("get cronjob: %w", err)
+	}
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cj.Spec.JobTemplate.Metadata.Annotations {
+		annotations[k] = v
+	}
+
+	job := map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]any{
+			"name":        jobName(cronJob),
+			"namespace":   namespace,
+			"labels":      cj.Spec.JobTemplate.Metadata.Labels,
+			"annotations": annotations,
+			"ownerReferences": []map[string]any{{
+				"apiVersion": "batch/v1",
+				"kind":       "CronJob",
+				"name":       cj.Metadata.Name,
+				"uid":        cj.Metadata.UID,
+				"controller": true,
+			}},
+		},
+		"spec": cj.Spec.JobTemplate.Spec,
+	}
+
+	var created struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := c.client.Create(ctx, "/apis/batch/v1/namespaces/"+url.PathEscape(namespace)+"/jobs", job, &created); err != nil {
+		return "", fmt.Errorf("create job: %w", err)
+	}
+
+	return created.Metadata.Name, nil
+}
+
+// =============================================================================
+
+// cronJobObject is the batch/v1 CronJob, reduced to the fields used. The
+// Job spec is passed through untouched.
+type cronJobObject struct {
+	Metadata struct {
+		Name string `json:"name"`
+		UID  string `json:"uid"`
+	} `json:"metadata"`
+	Spec struct {
+		JobTemplate struct {
+			Metadata struct {
+				Labels      map[string]string `json:"labels"`
+				Annotations map[string]string `json:"annotations"`
+			} `json:"metadata"`
+			Spec map[string]any `json:"spec"`
+		} `json:"jobTemplate"`
+	} `json:"spec"`
+}
+
+// jobName returns a name for a manual run of the CronJob, within the 63
+// characters of a label value the Job controller derives from it.
+func jobName(cronJob string) string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	suffix := "-remediate-" + hex.EncodeToString(b)
+
+	if len(cronJob) > 63-len(suffix) {
+		cronJob = cronJob[:63-len(suffix)]
+	}
+
+	return cronJob + suffix
+}
@@ -0,0 +1,64 @@
+// Package remediationdb implements the remediation audit store on top of
+// jsondb.
+package remediationdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"health-api/business/domain/remediationbus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements remediationbus.Storer.
+type Store struct {
+	log      *logger.Logger
+	attempts *jsondb.Collection[remediationbus.Attempt]
+}
+
+// NewStore opens the remediations collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	attempts, err := jsondb.NewCollection[remediationbus.Attempt](db, "remediations")
+	if err != nil {
+		return nil, fmt.Errorf("opening remediations: %w", err)
+	}
+
+	return &Store{
+		log:      log,
+		attempts: attempts,
+	}, nil
+}
+
+// Create inserts an audit record.
+func (s *Store) Create(ctx context.Context, a remediationbus.Attempt) error {
+	if err := s.attempts.Insert(a.ID, a); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves the audit records matching the filter, newest first.
+func (s *Store) Query(ctx context.Context, filter remediationbus.QueryFilter) ([]remediationbus.Attempt, error) {
+	var attempts []remediationbus.Attempt
+	for _, a := range s.attempts.All() {
+		if filter.Target != nil && a.Target != *filter.Target {
+			continue
+		}
+		if filter.Rule != nil && a.Rule != *filter.Rule {
+			continue
+		}
+		if filter.Since != nil && a.At.Before(*filter.Since) {
+			continue
+		}
+		attempts = append(attempts, a)
+	}
+
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].At.After(attempts[j].At)
+	})
+
+	return attempts, nil
+}
@@ -0,0 +1,195 @@
+package reportbus
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// Calendar describes the hours an SLA counts. Days lists the weekdays
+// counted, as mon to sun, Monday to Friday when empty. Start and End bound
+// the counted hours of each day as HH:MM in Timezone, End 24:00 meaning
+// midnight. Holidays, as YYYY-MM-DD, are not counted at all. The calendar
+// applies to the targets matching one of the Targets patterns, see
+// path.Match.
+type Calendar struct {
+	Name     string   `yaml:"name"`
+	Timezone string   `yaml:"timezone"`
+	Days     []string `yaml:"days"`
+	Start    string   `yaml:"start"`
+	End      string   `yaml:"end"`
+	Holidays []string `yaml:"holidays"`
+	Targets  []string `yaml:"targets"`
+
+	loc      *time.Location
+	weekdays [7]bool
+	start    int
+	end      int
+}
+
+// Config shapes how availability is measured. Without calendars every hour
+// counts. Default names the calendar of targets no calendar claims.
+// ExcludeMaintenance leaves maintenance windows out of the measured time.
+type Config struct {
+	Calendars          []Calendar `yaml:"calendars"`
+	Default            string     `yaml:"default"`
+	ExcludeMaintenance bool       `yaml:"exclude_maintenance"`
+}
+
+// LoadConfig reads calendars from a YAML file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading calendars file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing calendars file: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// validate parses the calendars and checks the default exists.
+func (cfg *Config) validate() error {
+	for i := range cfg.Calendars {
+		if err := cfg.Calendars[i].parse(); err != nil {
+			return fmt.Errorf("calendar %q: %w", cfg.Calendars[i].Name, err)
+		}
+	}
+
+	if cfg.Default != "" && cfg.calendar(cfg.Default) == nil {
+		return fmt.Errorf("default calendar %q is not defined", cfg.Default)
+	}
+
+	return nil
+}
+
+// calendar returns the named calendar, or nil.
+func (cfg *Config) calendar(name string) *Calendar {
+	for i := range cfg.Calendars {
+		if cfg.Calendars[i].Name == name {
+			return &cfg.Calendars[i]
+		}
+	}
+	return nil
+}
+
+// calendarFor returns the calendar of target: the first whose patterns
+// match it, else the default. It returns nil when every hour counts.
+func (cfg *Config) calendarFor(target string) *Calendar {
+	for i, c := range cfg.Calendars {
+		for _, pattern := range c.Targets {
+			if ok, _ := path.Match(pattern, target); ok {
+				return &cfg.Calendars[i]
+			}
+		}
+	}
+
+	if cfg.Default != "" {
+		return cfg.calendar(cfg.Default)
+	}
+
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (c *Calendar) parse() error {
+	if c.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone: %w", err)
+	}
+	c.loc = loc
+
+	days := c.Days
+	if len(days) == 0 {
+		days = []string{"mon", "tue", "wed", "thu", "fri"}
+	}
+	for _, d := range days {
+		wd, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return fmt.Errorf("unknown day %q", d)
+		}
+		c.weekdays[wd] = true
+	}
+
+	if c.start, err = parseClock(c.Start, "00:00"); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if c.end, err = parseClock(c.End, "24:00"); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if c.end <= c.start {
+		return fmt.Errorf("end must be after start")
+	}
+
+	for _, h := range c.Holidays {
+		if _, err := time.Parse(time.DateOnly, h); err != nil {
+			return fmt.Errorf("holiday %q: expected YYYY-MM-DD", h)
+		}
+	}
+
+	return nil
+}
+
+// parseClock returns the minutes after midnight of an HH:MM time, or of def
+// when s is empty.
+func parseClock(s string, def string) (int, error) {
+	if s == "" {
+		s = def
+	}
+
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+
+	return h*60 + m, nil
+}
+
+// intervals returns the counted hours within [from, to). Days are walked in
+// the calendar's timezone, so the hours stay put across daylight saving
+// changes.
+func (c *Calendar) intervals(from time.Time, to time.Time) []interval {
+	var ivs []interval
+
+	local := from.In(c.loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, c.loc)
+
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !c.weekdays[day.Weekday()] || slices.Contains(c.Holidays, day.Format(time.DateOnly)) {
+			continue
+		}
+
+		y, m, d := day.Date()
+		iv := interval{
+			start: time.Date(y, m, d, 0, c.start, 0, 0, c.loc),
+			end:   time.Date(y, m, d, 0, c.end, 0, 0, c.loc),
+		}
+
+		if iv = iv.clip(from, to); iv.end.After(iv.start) {
+			ivs = append(ivs, iv)
+		}
+	}
+
+	return ivs
+}
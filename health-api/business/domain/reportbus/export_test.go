@@ -0,0 +1,37 @@
+package reportbus
+
+import "time"
+
+// Window is a time range [Start, End) for the tests.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Validate exposes validate so tests can check calendars.
+func Validate(cfg Config) error {
+	return cfg.validate()
+}
+
+// Measure validates cfg and returns, as SLA does, how long the SLA of
+// target counts within [from, to) less the maintenance cuts, and how much
+// of down falls within that time.
+func Measure(cfg Config, target string, from time.Time, to time.Time, cuts []Window, down Window) (time.Duration, time.Duration, error) {
+	if err := cfg.validate(); err != nil {
+		return 0, 0, err
+	}
+
+	ivs := []interval{{start: from, end: to}}
+	if c := cfg.calendarFor(target); c != nil {
+		ivs = c.intervals(from, to)
+	}
+
+	var cutIvs []interval
+	for _, c := range cuts {
+		cutIvs = append(cutIvs, interval{start: c.Start, end: c.End})
+	}
+
+	measured := subtract(ivs, cutIvs)
+
+	return length(measured), within(measured, down.Start, down.End), nil
+}
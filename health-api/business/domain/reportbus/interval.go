@@ -0,0 +1,75 @@
+package reportbus
+
+import (
+	"sort"
+	"time"
+)
+
+// interval is the half-open time range [start, end).
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// clip returns the part of iv within [from, to), which may be empty.
+func (iv interval) clip(from time.Time, to time.Time) interval {
+	if iv.start.Before(from) {
+		iv.start = from
+	}
+	if iv.end.After(to) {
+		iv.end = to
+	}
+	return iv
+}
+
+// subtract returns the parts of the sorted, disjoint intervals ivs not
+// covered by any of cuts.
+func subtract(ivs []interval, cuts []interval) []interval {
+	if len(cuts) == 0 {
+		return ivs
+	}
+
+	cuts = append([]interval(nil), cuts...)
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].start.Before(cuts[j].start) })
+
+	var out []interval
+	for _, iv := range ivs {
+		for _, cut := range cuts {
+			if !cut.end.After(iv.start) || !cut.start.Before(iv.end) {
+				continue
+			}
+			if cut.start.After(iv.start) {
+				out = append(out, interval{start: iv.start, end: cut.start})
+			}
+			iv.start = cut.end
+			if !iv.end.After(iv.start) {
+				break
+			}
+		}
+		if iv.end.After(iv.start) {
+			out = append(out, iv)
+		}
+	}
+
+	return out
+}
+
+// within returns how much of the intervals falls within [from, to).
+func within(ivs []interval, from time.Time, to time.Time) time.Duration {
+	var d time.Duration
+	for _, iv := range ivs {
+		if iv = iv.clip(from, to); iv.end.After(iv.start) {
+			d += iv.end.Sub(iv.start)
+		}
+	}
+	return d
+}
+
+// length returns the total length of the intervals.
+func length(ivs []interval) time.Duration {
+	var d time.Duration
+	for _, iv := range ivs {
+		d += iv.end.Sub(iv.start)
+	}
+	return d
+}
@@ -0,0 +1,99 @@
+package reportbus
+
+import (
+	"fmt"
+	"time"
+)
+
+// Set of report period kinds.
+const (
+	KindWeekly  = "weekly"
+	KindMonthly = "monthly"
+)
+
+// Period represents the time range a report covers.
+type Period struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// ParsePeriod parses a monthly period such as 2024-05 or an ISO week such
+// as 2024-W20.
+func ParsePeriod(s string) (Period, error) {
+	if t, err := time.Parse("2006-01", s); err == nil {
+		return Period{
+			Name:  s,
+			Start: t,
+			End:   t.AddDate(0, 1, 0),
+		}, nil
+	}
+
+	var year, week int
+	if _, err := fmt.Sscanf(s, "%d-W%d", &year, &week); err == nil && week >= 1 && week <= 53 {
+		start := isoWeekStart(year, week)
+		return Period{
+			Name:  fmt.Sprintf("%d-W%02d", year, week),
+			Start: start,
+			End:   start.AddDate(0, 0, 7),
+		}, nil
+	}
+
+	return Period{}, fmt.Errorf("invalid period %q, expected YYYY-MM or YYYY-Www", s)
+}
+
+// PreviousPeriod returns the last complete period of the given kind.
+func PreviousPeriod(kind string, now time.Time) (Period, error) {
+	switch kind {
+	case KindMonthly:
+		prev := now.AddDate(0, -1, 0)
+		return ParsePeriod(prev.Format("2006-01"))
+
+	case KindWeekly:
+		year, week := now.AddDate(0, 0, -7).ISOWeek()
+		return ParsePeriod(fmt.Sprintf("%d-W%02d", year, week))
+	}
+
+	return Period{}, fmt.Errorf("unknown period kind %q", kind)
+}
+
+// isoWeekStart returns the Monday starting the ISO week.
+func isoWeekStart(year, week int) time.Time {
+	// January 4th is always in week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
+
+// SLAReport represents availability over a period.
+type SLAReport struct {
+	Period      string      `json:"period"`
+	Start       time.Time   `json:"start"`
+	End         time.Time   `json:"end"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Targets     []TargetSLA `json:"targets"`
+	Teams       []TeamSLA   `json:"teams"`
+}
+
+// TargetSLA represents availability of a single target. MeasuredSeconds is
+// the time the SLA counts: the hours of Calendar, all hours without one,
+// less excluded maintenance windows.
+type TargetSLA struct {
+	Target              string  `json:"target"`
+	Team                string  `json:"team,omitempty"`
+	Calendar            string  `json:"calendar,omitempty"`
+	AvailabilityPercent float64 `json:"availability_percent"`
+	DowntimeSeconds     float64 `json:"downtime_seconds"`
+	MeasuredSeconds     float64 `json:"measured_seconds"`
+	Incidents           int     `json:"incidents"`
+}
+
+// TeamSLA represents the combined availability of a team's targets.
+type TeamSLA struct {
+	Team                string  `json:"team"`
+	Targets             int     `json:"targets"`
+	AvailabilityPercent float64 `json:"availability_percent"`
+	DowntimeSeconds     float64 `json:"downtime_seconds"`
+	MeasuredSeconds     float64 `json:"measured_seconds"`
+	Incidents           int     `json:"incidents"`
+}
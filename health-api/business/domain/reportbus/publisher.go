@@ -0,0 +1,85 @@
+package reportbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"health-api/foundation/s3"
+)
+
+// WebhookPublisher posts reports as JSON to a URL.
+type WebhookPublisher struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookPublisher constructs a publisher for the webhook URL.
+func NewWebhookPublisher(url string) *WebhookPublisher {
+	return &WebhookPublisher{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Publish implements the Publisher interface.
+func (p *WebhookPublisher) Publish(ctx context.Context, rpt SLAReport) error {
+	data, err := json.Marshal(rpt)
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// S3Publisher uploads reports as JSON objects to a bucket.
+type S3Publisher struct {
+	client *s3.Client
+	prefix string
+}
+
+// NewS3Publisher constructs a publisher storing reports under the prefix.
+func NewS3Publisher(client *s3.Client, prefix string) *S3Publisher {
+	return &S3Publisher{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Publish implements the Publisher interface.
+func (p *S3Publisher) Publish(ctx context.Context, rpt SLAReport) error {
+	data, err := json.Marshal(rpt)
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+
+	key := path.Join(p.prefix, "sla-"+rpt.Period+".json")
+
+	if err := p.client.PutObject(ctx, key, "application/json", data); err != nil {
+		return fmt.Errorf("upload report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package reportbus provides business logic for SLA reports computed from
+// incident history.
+package reportbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"health-api/business/domain/incidentbus"
+	"health-api/business/domain/maintenancebus"
+	"health-api/business/domain/targetbus"
+	"health-api/foundation/cron"
+	"health-api/foundation/logger"
+)
+
+// ErrNotStarted is returned for a period that lies in the future.
+var ErrNotStarted = errors.New("period has not started")
+
+// Publisher delivers generated reports to an external destination.
+type Publisher interface {
+	Publish(ctx context.Context, rpt SLAReport) error
+}
+
+// Business manages SLA report generation.
+type Business struct {
+	log            *logger.Logger
+	incidentBus    *incidentbus.Business
+	targetBus      *targetbus.Business
+	maintenanceBus *maintenancebus.Business
+	cfg            Config
+	publishers     []Publisher
+}
+
+// NewBusiness creates a new report business layer. The maintenance
+// business layer is optional; it is only consulted when cfg excludes
+// maintenance windows.
+func NewBusiness(log *logger.Logger, incidentBus *incidentbus.Business, targetBus *targetbus.Business, maintenanceBus *maintenancebus.Business, cfg Config, publishers ...Publisher) (*Business, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Business{
+		log:            log,
+		incidentBus:    incidentBus,
+		targetBus:      targetBus,
+		maintenanceBus: maintenanceBus,
+		cfg:            cfg,
+		publishers:     publishers,
+	}, nil
+}
+
+// SLA computes availability per target and per team for the period. The
+// current period is measured up to now. Only the hours of a target's
+// calendar count, less its maintenance windows when they are excluded;
+// downtime outside them is ignored.
+func (b *Business) SLA(ctx context.Context, period Period) (SLAReport, error) {
+	now := time.Now().UTC()
+
+	end := period.End
+	if end.After(now) {
+		end = now
+	}
+
+	if !end.After(period.Start) {
+		return SLAReport{}, fmt.Errorf("%w: %s", ErrNotStarted, period.Name)
+	}
+
+	incs, err := b.incidentBus.Query(ctx, incidentbus.QueryFilter{
+		StartDate: &period.Start,
+		EndDate:   &end,
+	})
+	if err != nil {
+		return SLAReport{}, fmt.Errorf("query incidents: %w", err)
+	}
+
+	tgts, err := b.targetBus.Query(ctx)
+	if err != nil {
+		return SLAReport{}, fmt.Errorf("query targets: %w", err)
+	}
+
+	var maintenance []maintenancebus.Occurrence
+	if b.cfg.ExcludeMaintenance && b.maintenanceBus != nil {
+		if maintenance, err = b.maintenanceBus.Occurrences(ctx, period.Start, end); err != nil {
+			return SLAReport{}, fmt.Errorf("query maintenance: %w", err)
+		}
+	}
+
+	byTarget := make(map[string]*TargetSLA)
+	for _, tgt := range tgts {
+		byTarget[tgt.Name] = &TargetSLA{Target: tgt.Name, Team: tgt.Team}
+	}
+	for _, inc := range incs {
+		if _, ok := byTarget[inc.Target]; !ok {
+			byTarget[inc.Target] = &TargetSLA{Target: inc.Target}
+		}
+	}
+
+	measured := make(map[string][]interval, len(byTarget))
+	for name, ts := range byTarget {
+		ivs := []interval{{start: period.Start, end: end}}
+		if c := b.cfg.calendarFor(name); c != nil {
+			ts.Calendar = c.Name
+			ivs = c.intervals(period.Start, end)
+		}
+
+		var cuts []interval
+		for _, o := range maintenance {
+			if o.Covers(name) {
+				cuts = append(cuts, interval{start: o.Start, end: o.End})
+			}
+		}
+
+		measured[name] = subtract(ivs, cuts)
+		ts.MeasuredSeconds = length(measured[name]).Seconds()
+	}
+
+	for _, inc := range incs {
+		ts := byTarget[inc.Target]
+		ts.Incidents++
+		ts.DowntimeSeconds += downtime(inc, measured[inc.Target], end).Seconds()
+	}
+
+	rpt := SLAReport{
+		Period:      period.Name,
+		Start:       period.Start,
+		End:         end,
+		GeneratedAt: now,
+		Targets:     make([]TargetSLA, 0, len(byTarget)),
+	}
+
+	teams := make(map[string]*TeamSLA)
+	for _, ts := range byTarget {
+		ts.AvailabilityPercent = availability(ts.DowntimeSeconds, ts.MeasuredSeconds)
+		rpt.Targets = append(rpt.Targets, *ts)
+
+		if ts.Team == "" {
+			continue
+		}
+
+		tm, ok := teams[ts.Team]
+		if !ok {
+			tm = &TeamSLA{Team: ts.Team}
+			teams[ts.Team] = tm
+		}
+		tm.Targets++
+		tm.Incidents += ts.Incidents
+		tm.DowntimeSeconds += ts.DowntimeSeconds
+		tm.MeasuredSeconds += ts.MeasuredSeconds
+	}
+
+	for _, tm := range teams {
+		tm.AvailabilityPercent = availability(tm.DowntimeSeconds, tm.MeasuredSeconds)
+		rpt.Teams = append(rpt.Teams, *tm)
+	}
+
+	sort.Slice(rpt.Targets, func(i, j int) bool { return rpt.Targets[i].Target < rpt.Targets[j].Target })
+	sort.Slice(rpt.Teams, func(i, j int) bool { return rpt.Teams[i].Team < rpt.Teams[j].Team })
+
+	return rpt, nil
+}
+
+// RunSchedule generates the report for the previous period of the given kind
+// each time the schedule fires and hands it to every publisher. It blocks
+// until ctx is canceled.
+func (b *Business) RunSchedule(ctx context.Context, sched cron.Schedule, kind string) {
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			b.log.Error(ctx, "reportbus", "status", "schedule never fires")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		if err := b.publish(ctx, kind); err != nil {
+			b.log.Error(ctx, "reportbus", "status", "scheduled report failed", "error", err)
+		}
+	}
+}
+
+func (b *Business) publish(ctx context.Context, kind string) error {
+	period, err := PreviousPeriod(kind, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("period: %w", err)
+	}
+
+	rpt, err := b.SLA(ctx, period)
+	if err != nil {
+		return fmt.Errorf("sla: %w", err)
+	}
+
+	for _, p := range b.publishers {
+		if err := p.Publish(ctx, rpt); err != nil {
+			b.log.Error(ctx, "reportbus", "status", "publish failed", "period", period.Name, "error", err)
+			continue
+		}
+	}
+
+	b.log.Info(ctx, "reportbus", "status", "report published", "period", period.Name, "publishers", len(b.publishers))
+
+	return nil
+}
+
+// downtime returns how much of the incident falls within the measured
+// intervals. An open incident lasts until end.
+func downtime(inc incidentbus.Incident, measured []interval, end time.Time) time.Duration {
+	to := end
+	if inc.EndedAt != nil && inc.EndedAt.Before(end) {
+		to = *inc.EndedAt
+	}
+
+	return within(measured, inc.StartedAt, to)
+}
+
+func availability(downtime, total float64) float64 {
+	if total <= 0 {
+		return 100
+	}
+	return 100 * (1 - downtime/total)
+}
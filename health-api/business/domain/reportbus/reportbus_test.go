@@ -0,0 +1,339 @@
+package reportbus_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"health-api/business/domain/reportbus"
+)
+
+func Test_Measure(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should be able to load the time zone: %s", err)
+	}
+
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, berlin)
+	}
+
+	office := reportbus.Calendar{
+		Name:     "office",
+		Timezone: "Europe/Berlin",
+		Start:    "09:00",
+		End:      "17:00",
+		Targets:  []string{"shop*"},
+	}
+
+	always := reportbus.Calendar{
+		Name:     "always",
+		Timezone: "Europe/Berlin",
+		Days:     []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+		Targets:  []string{"shop*"},
+	}
+
+	night := always
+	night.Start, night.End = "01:00", "04:00"
+
+	holiday := office
+	holiday.Holidays = []string{"2026-06-03"}
+
+	// 2026-06-01 is a Monday. Berlin skips 02:00-03:00 on Sunday March 29
+	// and repeats it on Sunday October 25.
+	tests := []struct {
+		name     string
+		cfg      reportbus.Config
+		target   string
+		from     time.Time
+		to       time.Time
+		cuts     []reportbus.Window
+		down     reportbus.Window
+		measured time.Duration
+		downtime time.Duration
+	}{
+		{
+			name:     "no calendar",
+			target:   "db",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			measured: 7 * 24 * time.Hour,
+		},
+		{
+			name:     "unclaimed target",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "db",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			measured: 7 * 24 * time.Hour,
+		},
+		{
+			name:     "default calendar",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}, Default: "office"},
+			target:   "db",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			measured: 40 * time.Hour,
+		},
+		{
+			name:     "business week",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			measured: 40 * time.Hour,
+		},
+		{
+			name:     "holiday",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{holiday}},
+			target:   "shop-eu",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			measured: 32 * time.Hour,
+		},
+		{
+			name:     "period within business hours",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 12, 0),
+			to:       at(6, 1, 15, 0),
+			measured: 3 * time.Hour,
+		},
+		{
+			name:     "period over the night",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 16, 0),
+			to:       at(6, 2, 10, 0),
+			measured: 2 * time.Hour,
+		},
+		{
+			name:     "period starting at closing time",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 17, 0),
+			to:       at(6, 2, 9, 0),
+			measured: 0,
+		},
+		{
+			name:     "period given in UTC",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     time.Date(2026, 6, 1, 7, 0, 0, 0, time.UTC),
+			to:       time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC),
+			measured: time.Hour,
+		},
+		{
+			name:     "business hours across the spring change",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(3, 27, 0, 0),
+			to:       at(3, 31, 0, 0),
+			measured: 16 * time.Hour,
+		},
+		{
+			name:     "short day",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{always}},
+			target:   "shop-eu",
+			from:     at(3, 29, 0, 0),
+			to:       at(3, 30, 0, 0),
+			measured: 23 * time.Hour,
+		},
+		{
+			name:     "long day",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{always}},
+			target:   "shop-eu",
+			from:     at(10, 25, 0, 0),
+			to:       at(10, 26, 0, 0),
+			measured: 25 * time.Hour,
+		},
+		{
+			name:     "hours over the skipped hour",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{night}},
+			target:   "shop-eu",
+			from:     at(3, 29, 0, 0),
+			to:       at(3, 30, 0, 0),
+			measured: 2 * time.Hour,
+		},
+		{
+			name:   "maintenance",
+			cfg:    reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target: "shop-eu",
+			from:   at(6, 1, 0, 0),
+			to:     at(6, 8, 0, 0),
+			cuts: []reportbus.Window{
+				{Start: at(6, 1, 10, 0), End: at(6, 1, 12, 0)},
+				{Start: at(6, 1, 16, 0), End: at(6, 1, 20, 0)},
+				{Start: at(6, 6, 10, 0), End: at(6, 6, 12, 0)},
+			},
+			measured: 37 * time.Hour,
+		},
+		{
+			name:     "downtime within business hours",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			down:     reportbus.Window{Start: at(6, 1, 8, 0), End: at(6, 1, 10, 0)},
+			measured: 40 * time.Hour,
+			downtime: time.Hour,
+		},
+		{
+			name:     "downtime over a weekend",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 15, 0, 0),
+			down:     reportbus.Window{Start: at(6, 5, 16, 0), End: at(6, 8, 10, 0)},
+			measured: 80 * time.Hour,
+			downtime: 2 * time.Hour,
+		},
+		{
+			name:     "downtime during maintenance",
+			cfg:      reportbus.Config{Calendars: []reportbus.Calendar{office}},
+			target:   "shop-eu",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 8, 0, 0),
+			cuts:     []reportbus.Window{{Start: at(6, 1, 9, 0), End: at(6, 1, 11, 0)}},
+			down:     reportbus.Window{Start: at(6, 1, 10, 0), End: at(6, 1, 12, 0)},
+			measured: 38 * time.Hour,
+			downtime: time.Hour,
+		},
+		{
+			name:     "downtime after the period",
+			target:   "db",
+			from:     at(6, 1, 0, 0),
+			to:       at(6, 2, 0, 0),
+			down:     reportbus.Window{Start: at(6, 1, 23, 0), End: at(6, 2, 5, 0)},
+			measured: 24 * time.Hour,
+			downtime: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			measured, downtime, err := reportbus.Measure(tt.cfg, tt.target, tt.from, tt.to, tt.cuts, tt.down)
+			if err != nil {
+				t.Fatalf("Should be able to measure: %s", err)
+			}
+
+			if measured != tt.measured || downtime != tt.downtime {
+				t.Errorf("Should measure %s with %s down, got %s with %s down", tt.measured, tt.downtime, measured, downtime)
+			}
+		})
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	valid := reportbus.Calendar{Name: "office", Timezone: "Europe/Berlin", Start: "09:00", End: "17:00"}
+
+	tests := []struct {
+		name   string
+		change func(c *reportbus.Calendar, cfg *reportbus.Config)
+		err    string
+	}{
+		{name: "valid", change: func(c *reportbus.Calendar, cfg *reportbus.Config) {}},
+		{name: "whole day", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Start, c.End = "", "" }},
+		{name: "midnight end", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.End = "24:00" }},
+		{name: "no name", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Name = "" }, err: "name is required"},
+		{name: "time zone", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Timezone = "Mars/Olympus" }, err: "timezone:"},
+		{name: "day", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Days = []string{"mon", "funday"} }, err: `unknown day "funday"`},
+		{name: "start", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Start = "9am" }, err: `start: invalid time "9am"`},
+		{name: "minutes", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Start = "09:60" }, err: `start: invalid time "09:60"`},
+		{name: "past midnight", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.End = "24:01" }, err: `end: invalid time "24:01"`},
+		{name: "end before start", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.End = "09:00" }, err: "end must be after start"},
+		{name: "holiday", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { c.Holidays = []string{"2026-13-01"} }, err: `holiday "2026-13-01"`},
+		{name: "default", change: func(c *reportbus.Calendar, cfg *reportbus.Config) { cfg.Default = "weekend" }, err: `default calendar "weekend" is not defined`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			var cfg reportbus.Config
+			tt.change(&c, &cfg)
+			cfg.Calendars = []reportbus.Calendar{c}
+
+			err := reportbus.Validate(cfg)
+
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Should accept the calendar: %s", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("Should fail with %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func Test_ParsePeriod(t *testing.T) {
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		period string
+		name   string
+		start  time.Time
+		end    time.Time
+		err    bool
+	}{
+		{period: "2026-05", name: "2026-05", start: day(2026, 5, 1), end: day(2026, 6, 1)},
+		{period: "2026-12", name: "2026-12", start: day(2026, 12, 1), end: day(2027, 1, 1)},
+		{period: "2024-02", name: "2024-02", start: day(2024, 2, 1), end: day(2024, 3, 1)},
+		{period: "2026-W01", name: "2026-W01", start: day(2025, 12, 29), end: day(2026, 1, 5)},
+		{period: "2026-W1", name: "2026-W01", start: day(2025, 12, 29), end: day(2026, 1, 5)},
+		{period: "2020-W53", name: "2020-W53", start: day(2020, 12, 28), end: day(2021, 1, 4)},
+		{period: "2026-W00", err: true},
+		{period: "2026-W54", err: true},
+		{period: "2026-13", err: true},
+		{period: "May", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			p, err := reportbus.ParsePeriod(tt.period)
+
+			if tt.err {
+				if err == nil {
+					t.Errorf("Should reject the period, got %+v", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Should be able to parse: %s", err)
+			}
+
+			if p.Name != tt.name || !p.Start.Equal(tt.start) || !p.End.Equal(tt.end) {
+				t.Errorf("Should cover %s from %s to %s, got %s from %s to %s", tt.name, tt.start, tt.end, p.Name, p.Start, p.End)
+			}
+		})
+	}
+}
+
+func Test_PreviousPeriod(t *testing.T) {
+	tests := []struct {
+		kind string
+		now  time.Time
+		want string
+	}{
+		{kind: reportbus.KindMonthly, now: time.Date(2026, 1, 15, 8, 0, 0, 0, time.UTC), want: "2025-12"},
+		{kind: reportbus.KindMonthly, now: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), want: "2026-02"},
+		{kind: reportbus.KindWeekly, now: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), want: "2026-W01"},
+		{kind: reportbus.KindWeekly, now: time.Date(2026, 1, 4, 23, 59, 0, 0, time.UTC), want: "2025-W52"},
+	}
+
+	for _, tt := range tests {
+		p, err := reportbus.PreviousPeriod(tt.kind, tt.now)
+		if err != nil {
+			t.Fatalf("Should be able to find the previous %s period: %s", tt.kind, err)
+		}
+
+		if p.Name != tt.want {
+			t.Errorf("Should report %s for %s at %s, got %s", tt.want, tt.kind, tt.now, p.Name)
+		}
+	}
+
+	if _, err := reportbus.PreviousPeriod("daily", time.Now()); err == nil {
+		t.Error("Should reject an unknown kind")
+	}
+}
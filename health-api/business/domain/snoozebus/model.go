@@ -0,0 +1,43 @@
+package snoozebus
+
+import "time"
+
+// MaxDuration bounds a snooze, so a forgotten one doesn't silence a target
+// for good.
+const MaxDuration = 7 * 24 * time.Hour
+
+// Snooze holds back the notifications of a target until Until, or until it
+// is ended early. The target's status is still reported. Snoozes are kept
+// once over as the audit trail of who silenced what.
+type Snooze struct {
+	ID        string     `json:"id"`
+	Target    string     `json:"target"`
+	Namespace string     `json:"namespace,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	By        string     `json:"by,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	Until     time.Time  `json:"until"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	EndedBy   string     `json:"ended_by,omitempty"`
+}
+
+// ActiveAt reports whether the snooze silences its target at t.
+func (s Snooze) ActiveAt(t time.Time) bool {
+	return s.EndedAt == nil && t.Before(s.Until)
+}
+
+// NewSnooze contains the information needed to snooze a target.
+type NewSnooze struct {
+	Target    string
+	Namespace string
+	Duration  time.Duration
+	Reason    string
+	By        string
+}
+
+// QueryFilter narrows the snoozes returned. Active keeps the snoozes in
+// effect now.
+type QueryFilter struct {
+	Target *string
+	Active bool
+}
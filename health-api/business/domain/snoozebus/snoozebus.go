@@ -0,0 +1,157 @@
+// Package snoozebus provides business logic for snoozes, periods during
+// which a target's notifications are held back while its status is still
+// reported.
+package snoozebus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// Set of error variables for snooze operations.
+var (
+	ErrInvalidDuration = errors.New("invalid snooze duration")
+	ErrNotSnoozed      = errors.New("target not snoozed")
+)
+
+// Storer defines the interface for snooze data access.
+type Storer interface {
+	Create(ctx context.Context, s Snooze) error
+	Update(ctx context.Context, s Snooze) error
+	Query(ctx context.Context, filter QueryFilter) ([]Snooze, error)
+}
+
+// Business manages snoozes.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+
+	// mu serializes writes so a target has at most one active snooze.
+	mu sync.Mutex
+}
+
+// NewBusiness creates a new snooze business layer.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create snoozes the target for the duration. Snoozing a snoozed target
+// ends its snooze and starts a new one.
+func (b *Business) Create(ctx context.Context, ns NewSnooze) (Snooze, error) {
+	if !tenant.Get(ctx).Allows(ns.Namespace) {
+		return Snooze{}, fmt.Errorf("create: namespace[%s]: %w", ns.Namespace, tenant.ErrNamespace)
+	}
+
+	if ns.Duration <= 0 || ns.Duration > MaxDuration {
+		return Snooze{}, fmt.Errorf("create: %w: must be positive and at most %s", ErrInvalidDuration, MaxDuration)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	if err := b.end(ctx, ns.Target, ns.By, now); err != nil && !errors.Is(err, ErrNotSnoozed) {
+		return Snooze{}, fmt.Errorf("create: %w", err)
+	}
+
+	s := Snooze{
+		ID:        newID(),
+		Target:    ns.Target,
+		Namespace: ns.Namespace,
+		Reason:    ns.Reason,
+		By:        ns.By,
+		StartedAt: now,
+		Until:     now.Add(ns.Duration),
+	}
+
+	if err := b.storer.Create(ctx, s); err != nil {
+		return Snooze{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.log.Info(ctx, "snoozebus", "status", "target snoozed", "id", s.ID, "target", s.Target, "until", s.Until, "by", s.By, "reason", s.Reason)
+
+	return s, nil
+}
+
+// End ends the target's snooze before it expires.
+func (b *Business) End(ctx context.Context, target string, by string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.end(ctx, target, by, time.Now().UTC()); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+
+	return nil
+}
+
+func (b *Business) end(ctx context.Context, target string, by string, now time.Time) error {
+	snoozes, err := b.Query(ctx, QueryFilter{Target: &target, Active: true})
+	if err != nil {
+		return err
+	}
+
+	if len(snoozes) == 0 {
+		return fmt.Errorf("target[%s]: %w", target, ErrNotSnoozed)
+	}
+
+	for _, s := range snoozes {
+		s.EndedAt = &now
+		s.EndedBy = by
+
+		if err := b.storer.Update(ctx, s); err != nil {
+			return fmt.Errorf("update: %w", err)
+		}
+
+		b.log.Info(ctx, "snoozebus", "status", "snooze ended", "id", s.ID, "target", s.Target, "by", by)
+	}
+
+	return nil
+}
+
+// Query retrieves the snoozes matching the filter that are visible to the
+// caller, newest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Snooze, error) {
+	snoozes, err := b.storer.Query(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return tenant.Filter(tenant.Get(ctx), snoozes, func(s Snooze) string { return s.Namespace }), nil
+}
+
+// Active returns the snooze in effect at t of every snoozed target,
+// whatever the caller's scope.
+func (b *Business) Active(ctx context.Context, t time.Time) (map[string]Snooze, error) {
+	snoozes, err := b.storer.Query(ctx, QueryFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	active := make(map[string]Snooze)
+	for _, s := range snoozes {
+		if s.ActiveAt(t) {
+			active[s.Target] = s
+		}
+	}
+
+	return active, nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
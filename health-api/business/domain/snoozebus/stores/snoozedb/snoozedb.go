@@ -0,0 +1,72 @@
+// Package snoozedb implements the snooze store on top of jsondb.
+package snoozedb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"health-api/business/domain/snoozebus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements snoozebus.Storer.
+type Store struct {
+	log     *logger.Logger
+	snoozes *jsondb.Collection[snoozebus.Snooze]
+}
+
+// NewStore opens the snoozes collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	snoozes, err := jsondb.NewCollection[snoozebus.Snooze](db, "snoozes")
+	if err != nil {
+		return nil, fmt.Errorf("opening snoozes: %w", err)
+	}
+
+	return &Store{
+		log:     log,
+		snoozes: snoozes,
+	}, nil
+}
+
+// Create inserts a new snooze.
+func (s *Store) Create(ctx context.Context, sn snoozebus.Snooze) error {
+	if err := s.snoozes.Insert(sn.ID, sn); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an existing snooze.
+func (s *Store) Update(ctx context.Context, sn snoozebus.Snooze) error {
+	if err := s.snoozes.Replace(sn.ID, sn); err != nil {
+		return fmt.Errorf("replace: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves the snoozes matching the filter, newest first.
+func (s *Store) Query(ctx context.Context, filter snoozebus.QueryFilter) ([]snoozebus.Snooze, error) {
+	now := time.Now()
+
+	var snoozes []snoozebus.Snooze
+	for _, sn := range s.snoozes.All() {
+		if filter.Target != nil && sn.Target != *filter.Target {
+			continue
+		}
+		if filter.Active && !sn.ActiveAt(now) {
+			continue
+		}
+		snoozes = append(snoozes, sn)
+	}
+
+	sort.Slice(snoozes, func(i, j int) bool {
+		return snoozes[i].StartedAt.After(snoozes[j].StartedAt)
+	})
+
+	return snoozes, nil
+}
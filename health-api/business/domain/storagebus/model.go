@@ -0,0 +1,72 @@
+package storagebus
+
+import (
+	"errors"
+	"path"
+)
+
+// Set of volume statuses.
+const (
+	StatusOK       = "ok"
+	StatusWarning  = "warning"
+	StatusCritical = "critical"
+)
+
+// VolumeStats is the usage of a PersistentVolumeClaim as the kubelet
+// reports it.
+type VolumeStats struct {
+	Namespace      string
+	PVC            string
+	CapacityBytes  float64
+	UsedBytes      float64
+	AvailableBytes float64
+	Inodes         float64
+	InodesUsed     float64
+}
+
+// Volume is the usage of a PersistentVolumeClaim rated against its
+// thresholds. A volume is rated by the fuller of its bytes and inodes.
+type Volume struct {
+	Namespace         string  `json:"namespace"`
+	PVC               string  `json:"pvc"`
+	CapacityBytes     float64 `json:"capacity_bytes"`
+	UsedBytes         float64 `json:"used_bytes"`
+	AvailableBytes    float64 `json:"available_bytes"`
+	UsedPercent       float64 `json:"used_percent"`
+	InodesUsedPercent float64 `json:"inodes_used_percent"`
+	Warning           float64 `json:"warning_percent"`
+	Critical          float64 `json:"critical_percent"`
+	Status            string  `json:"status"`
+}
+
+// Threshold sets the usage percentages at which the volumes matching Match
+// turn warning and critical. Match is a "<namespace>/<pvc>" pattern in
+// which * stands for any run of characters but /, e.g. "shop/data-*".
+type Threshold struct {
+	Match    string  `yaml:"match"`
+	Warning  float64 `yaml:"warning"`
+	Critical float64 `yaml:"critical"`
+}
+
+func (t Threshold) matches(namespace, pvc string) bool {
+	ok, _ := path.Match(t.Match, namespace+"/"+pvc)
+	return ok
+}
+
+func (t Threshold) validate() error {
+	if _, err := path.Match(t.Match, ""); err != nil {
+		return errors.New("match must be a <namespace>/<pvc> pattern")
+	}
+
+	if t.Warning <= 0 || t.Critical > 100 || t.Warning > t.Critical {
+		return errors.New("warning and critical must satisfy 0 < warning <= critical <= 100")
+	}
+
+	return nil
+}
+
+// QueryFilter narrows the volumes returned.
+type QueryFilter struct {
+	Namespace *string
+	Status    *string
+}
@@ -0,0 +1,173 @@
+// Package storagebus provides business logic for the usage of persistent
+// volumes, rated against per-claim thresholds so claims filling up are
+// reported before they run out.
+package storagebus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// ErrNotFound is returned when no usage is reported for a claim.
+var ErrNotFound = errors.New("volume not found")
+
+// Storer defines the interface for reading volume usage.
+type Storer interface {
+	QueryVolumes(ctx context.Context, at time.Time) ([]VolumeStats, error)
+}
+
+// Business rates volume usage.
+type Business struct {
+	log        *logger.Logger
+	storer     Storer
+	defaults   Threshold
+	thresholds []Threshold
+}
+
+// NewBusiness creates a new storage business layer. The first of the
+// thresholds matching a claim applies, and defaults to the rest.
+func NewBusiness(log *logger.Logger, storer Storer, defaults Threshold, thresholds []Threshold) (*Business, error) {
+	if err := defaults.validate(); err != nil {
+		return nil, fmt.Errorf("default threshold: %w", err)
+	}
+
+	return &Business{
+		log:        log,
+		storer:     storer,
+		defaults:   defaults,
+		thresholds: thresholds,
+	}, nil
+}
+
+// LoadFile reads per-claim thresholds from a YAML file with a top level
+// volumes list.
+func LoadFile(path string) ([]Threshold, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading storage file: %w", err)
+	}
+
+	var file struct {
+		Volumes []Threshold `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing storage file: %w", err)
+	}
+
+	for _, t := range file.Volumes {
+		if err := t.validate(); err != nil {
+			return nil, fmt.Errorf("volume %q: %w", t.Match, err)
+		}
+	}
+
+	return file.Volumes, nil
+}
+
+// Query returns the volumes matching the filter that are visible to the
+// caller, fullest first.
+func (b *Business) Query(ctx context.Context, filter QueryFilter) ([]Volume, error) {
+	stats, err := b.storer.QueryVolumes(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	scope := tenant.Get(ctx)
+
+	var volumes []Volume
+	for _, s := range stats {
+		if !scope.Allows(s.Namespace) {
+			continue
+		}
+		if filter.Namespace != nil && s.Namespace != *filter.Namespace {
+			continue
+		}
+
+		v := b.rate(s)
+		if filter.Status != nil && v.Status != *filter.Status {
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return fullness(volumes[i]) > fullness(volumes[j])
+	})
+
+	return volumes, nil
+}
+
+// QueryByClaim returns the volume of the claim.
+func (b *Business) QueryByClaim(ctx context.Context, namespace string, pvc string) (Volume, error) {
+	volumes, err := b.Query(ctx, QueryFilter{Namespace: &namespace})
+	if err != nil {
+		return Volume{}, err
+	}
+
+	for _, v := range volumes {
+		if v.PVC == pvc {
+			return v, nil
+		}
+	}
+
+	return Volume{}, fmt.Errorf("query: pvc[%s/%s]: %w", namespace, pvc, ErrNotFound)
+}
+
+// =============================================================================
+
+// rate computes the usage of the volume and rates it against the first
+// matching threshold.
+func (b *Business) rate(s VolumeStats) Volume {
+	t := b.defaults
+	for _, th := range b.thresholds {
+		if th.matches(s.Namespace, s.PVC) {
+			t = th
+			break
+		}
+	}
+
+	v := Volume{
+		Namespace:      s.Namespace,
+		PVC:            s.PVC,
+		CapacityBytes:  s.CapacityBytes,
+		UsedBytes:      s.UsedBytes,
+		AvailableBytes: s.AvailableBytes,
+		Warning:        t.Warning,
+		Critical:       t.Critical,
+		Status:         StatusOK,
+	}
+
+	if s.CapacityBytes > 0 {
+		v.UsedPercent = round(100 * s.UsedBytes / s.CapacityBytes)
+	}
+	if s.Inodes > 0 {
+		v.InodesUsedPercent = round(100 * s.InodesUsed / s.Inodes)
+	}
+
+	switch used := fullness(v); {
+	case used >= t.Critical:
+		v.Status = StatusCritical
+	case used >= t.Warning:
+		v.Status = StatusWarning
+	}
+
+	return v
+}
+
+// fullness is the usage a volume is rated by.
+func fullness(v Volume) float64 {
+	return math.Max(v.UsedPercent, v.InodesUsedPercent)
+}
+
+func round(percent float64) float64 {
+	return math.Round(percent*10) / 10
+}
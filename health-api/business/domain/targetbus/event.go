@@ -0,0 +1,85 @@
+package targetbus
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"health-api/business/sdk/delegate"
+)
+
+// DomainName represents the name of this domain.
+const DomainName = "target"
+
+// Set of delegate actions for targets.
+const (
+	ActionSaved  = "saved"
+	ActionPaused = "paused"
+)
+
+// ActionSavedParms represents the parameters for the saved action, fired
+// when a target is created or updated.
+type ActionSavedParms struct {
+	Target Target `json:"target"`
+}
+
+// String returns a string representation of the action parameters.
+func (sp *ActionSavedParms) String() string {
+	return "target=" + sp.Target.Name + " module=" + sp.Target.Module
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (sp *ActionSavedParms) Marshal() ([]byte, error) {
+	return json.Marshal(sp)
+}
+
+// ActionSavedData constructs the data for the saved action.
+func ActionSavedData(tgt Target) delegate.Data {
+	params := ActionSavedParms{
+		Target: tgt,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionSaved,
+		RawParams: rawParams,
+	}
+}
+
+// ActionPausedParms represents the parameters for the paused action, fired
+// when a target is paused or resumed. Target.Paused tells which.
+type ActionPausedParms struct {
+	Target Target `json:"target"`
+}
+
+// String returns a string representation of the action parameters.
+func (pp *ActionPausedParms) String() string {
+	return "target=" + pp.Target.Name + " paused=" + strconv.FormatBool(pp.Target.Paused)
+}
+
+// Marshal returns the event parameters encoded as JSON.
+func (pp *ActionPausedParms) Marshal() ([]byte, error) {
+	return json.Marshal(pp)
+}
+
+// ActionPausedData constructs the data for the paused action.
+func ActionPausedData(tgt Target) delegate.Data {
+	params := ActionPausedParms{
+		Target: tgt,
+	}
+
+	rawParams, err := params.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	return delegate.Data{
+		Domain:    DomainName,
+		Action:    ActionPaused,
+		RawParams: rawParams,
+	}
+}
@@ -0,0 +1,82 @@
+// Package targetdb implements the target store on top of jsondb.
+package targetdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"health-api/business/domain/targetbus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements targetbus.Storer.
+type Store struct {
+	log     *logger.Logger
+	targets *jsondb.Collection[targetbus.Target]
+}
+
+// NewStore opens the targets collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	targets, err := jsondb.NewCollection[targetbus.Target](db, "targets")
+	if err != nil {
+		return nil, fmt.Errorf("opening targets: %w", err)
+	}
+
+	return &Store{
+		log:     log,
+		targets: targets,
+	}, nil
+}
+
+// Create inserts a new target.
+func (s *Store) Create(ctx context.Context, tgt targetbus.Target) error {
+	if err := s.targets.Insert(tgt.Name, tgt); err != nil {
+		if errors.Is(err, jsondb.ErrExists) {
+			return targetbus.ErrExists
+		}
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces an existing target.
+func (s *Store) Update(ctx context.Context, tgt targetbus.Target) error {
+	if err := s.targets.Replace(tgt.Name, tgt); err != nil {
+		if errors.Is(err, jsondb.ErrNotFound) {
+			return targetbus.ErrNotFound
+		}
+		return fmt.Errorf("replace: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a target.
+func (s *Store) Delete(ctx context.Context, name string) error {
+	if err := s.targets.Delete(name); err != nil {
+		if errors.Is(err, jsondb.ErrNotFound) {
+			return targetbus.ErrNotFound
+		}
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves all targets ordered by name.
+func (s *Store) Query(ctx context.Context) ([]targetbus.Target, error) {
+	return s.targets.All(), nil
+}
+
+// QueryByName retrieves the target with the specified name.
+func (s *Store) QueryByName(ctx context.Context, name string) (targetbus.Target, error) {
+	tgt, ok := s.targets.Get(name)
+	if !ok {
+		return targetbus.Target{}, targetbus.ErrNotFound
+	}
+
+	return tgt, nil
+}
@@ -0,0 +1,671 @@
+// Package targetbus provides business logic for target metadata such as
+// ownership, runbooks and tags.
+package targetbus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"health-api/business/sdk/delegate"
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// Set of error variables for target operations.
+var (
+	ErrNotFound = errors.New("target not found")
+	ErrExists   = errors.New("target already exists")
+	ErrVersion  = errors.New("target version mismatch")
+)
+
+// Storer defines the interface for target data access.
+type Storer interface {
+	Create(ctx context.Context, tgt Target) error
+	Update(ctx context.Context, tgt Target) error
+	Delete(ctx context.Context, name string) error
+	Query(ctx context.Context) ([]Target, error)
+	QueryByName(ctx context.Context, name string) (Target, error)
+}
+
+// Business manages target metadata.
+type Business struct {
+	log      *logger.Logger
+	delegate *delegate.Delegate
+	storer   Storer
+
+	// mu serializes writes so conditional upserts compare against the
+	// version they replace.
+	mu sync.Mutex
+}
+
+// NewBusiness creates a new target business layer.
+func NewBusiness(log *logger.Logger, delegate *delegate.Delegate, storer Storer) *Business {
+	return &Business{
+		log:      log,
+		delegate: delegate,
+		storer:   storer,
+	}
+}
+
+// Create adds a new target. Callers scoped to a tenant may only create
+// targets in their own namespaces.
+func (b *Business) Create(ctx context.Context, nt NewTarget) (Target, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.create(ctx, nt)
+}
+
+func (b *Business) create(ctx context.Context, nt NewTarget) (Target, error) {
+	if !tenant.Get(ctx).Allows(nt.Namespace) {
+		return Target{}, fmt.Errorf("create: namespace[%s]: %w", nt.Namespace, tenant.ErrNamespace)
+	}
+
+	tgt := fromNewTarget(nt, time.Now().UTC())
+
+	if err := b.storer.Create(ctx, tgt); err != nil {
+		return Target{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.notifySaved(ctx, tgt)
+
+	return tgt, nil
+}
+
+// Update modifies an existing target.
+func (b *Business) Update(ctx context.Context, tgt Target, ut UpdateTarget) (Target, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.update(ctx, tgt, ut)
+}
+
+// Upsert creates the named target from ut or, if it exists, applies ut to
+// it, reporting whether the target was created. A non-zero version makes
+// the write conditional: it fails with ErrVersion unless the target exists
+// at that version.
+func (b *Business) Upsert(ctx context.Context, name string, ut UpdateTarget, version int) (Target, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tgt, err := b.QueryByName(ctx, name)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		if version != 0 {
+			return Target{}, false, fmt.Errorf("upsert: name[%s]: %w", name, ErrVersion)
+		}
+
+		tgt, err := b.create(ctx, ut.newTarget(name))
+		if err != nil {
+			return Target{}, false, fmt.Errorf("upsert: %w", err)
+		}
+		return tgt, true, nil
+
+	case err != nil:
+		return Target{}, false, fmt.Errorf("upsert: %w", err)
+	}
+
+	if version != 0 && tgt.Version != version {
+		return Target{}, false, fmt.Errorf("upsert: name[%s] version[%d]: %w", name, tgt.Version, ErrVersion)
+	}
+
+	if tgt, err = b.update(ctx, tgt, ut); err != nil {
+		return Target{}, false, fmt.Errorf("upsert: %w", err)
+	}
+
+	return tgt, false, nil
+}
+
+// update applies ut to tgt. An update that changes nothing leaves the
+// target, including its version, as it is so repeated requests are
+// idempotent.
+func (b *Business) update(ctx context.Context, tgt Target, ut UpdateTarget) (Target, error) {
+	before := toNewTarget(tgt)
+
+	if ut.Namespace != nil {
+		if !tenant.Get(ctx).Allows(*ut.Namespace) {
+			return Target{}, fmt.Errorf("update: namespace[%s]: %w", *ut.Namespace, tenant.ErrNamespace)
+		}
+		tgt.Namespace = *ut.Namespace
+	}
+	if ut.Team != nil {
+		tgt.Team = *ut.Team
+	}
+	if ut.RunbookURL != nil {
+		tgt.RunbookURL = *ut.RunbookURL
+	}
+	if ut.Severity != nil {
+		tgt.Severity = *ut.Severity
+	}
+	if ut.Tags != nil {
+		tgt.Tags = ut.Tags
+	}
+	if ut.Module != nil {
+		tgt.Module = *ut.Module
+	}
+	if ut.DependsOn != nil {
+		tgt.DependsOn = ut.DependsOn
+	}
+	if ut.LatencySLO != nil {
+		tgt.LatencySLO = *ut.LatencySLO
+	}
+	if ut.LogSelector != nil {
+		tgt.LogSelector = *ut.LogSelector
+	}
+	if ut.Workload != nil {
+		tgt.Workload = *ut.Workload
+	}
+
+	if toNewTarget(tgt).equal(before) {
+		return tgt, nil
+	}
+
+	tgt.Version++
+	tgt.DateUpdated = time.Now().UTC()
+
+	if err := b.storer.Update(ctx, tgt); err != nil {
+		return Target{}, fmt.Errorf("update: %w", err)
+	}
+
+	b.notifySaved(ctx, tgt)
+
+	return tgt, nil
+}
+
+// Delete removes the specified target.
+func (b *Business) Delete(ctx context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, err := b.QueryByName(ctx, name); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if err := b.storer.Delete(ctx, name); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Pause stops probing and alerting for the named target without deleting
+// it. A positive ttl resumes it automatically once ttl has passed; pausing
+// a paused target replaces its pause.
+func (b *Business) Pause(ctx context.Context, name string, ttl time.Duration, reason string) (Target, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tgt, err := b.QueryByName(ctx, name)
+	if err != nil {
+		return Target{}, fmt.Errorf("pause: %w", err)
+	}
+
+	tgt.Paused = true
+	tgt.PausedUntil = nil
+	tgt.PauseReason = reason
+	if ttl > 0 {
+		until := time.Now().UTC().Add(ttl)
+		tgt.PausedUntil = &until
+	}
+
+	if err := b.storer.Update(ctx, tgt); err != nil {
+		return Target{}, fmt.Errorf("pause: %w", err)
+	}
+
+	b.notifyPaused(ctx, tgt)
+
+	return tgt, nil
+}
+
+// Resume restarts probing and alerting for the named target. Resuming a
+// target that isn't paused changes nothing.
+func (b *Business) Resume(ctx context.Context, name string) (Target, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tgt, err := b.QueryByName(ctx, name)
+	if err != nil {
+		return Target{}, fmt.Errorf("resume: %w", err)
+	}
+
+	if !tgt.Paused {
+		return tgt, nil
+	}
+
+	tgt.Paused = false
+	tgt.PausedUntil = nil
+	tgt.PauseReason = ""
+
+	if err := b.storer.Update(ctx, tgt); err != nil {
+		return Target{}, fmt.Errorf("resume: %w", err)
+	}
+
+	b.notifyPaused(ctx, tgt)
+
+	return tgt, nil
+}
+
+// ExpirePauses resumes the targets whose pause ran out, so the resume is
+// stored and interested domains are told, not only reflected in queries.
+func (b *Business) ExpirePauses(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tgts, err := b.storer.Query(ctx)
+	if err != nil {
+		return fmt.Errorf("expire pauses: %w", err)
+	}
+
+	now := time.Now()
+
+	for _, tgt := range tgts {
+		if !tgt.Paused {
+			continue
+		}
+
+		tgt.identify()
+		if tgt.expirePause(now); tgt.Paused {
+			continue
+		}
+
+		if err := b.storer.Update(ctx, tgt); err != nil {
+			return fmt.Errorf("expire pauses: name[%s]: %w", tgt.Name, err)
+		}
+
+		b.log.Info(ctx, "targetbus", "status", "pause expired", "target", tgt.Name)
+		b.notifyPaused(ctx, tgt)
+	}
+
+	return nil
+}
+
+// RunPauseExpiry calls ExpirePauses every interval until ctx is canceled.
+func (b *Business) RunPauseExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := b.ExpirePauses(ctx); err != nil {
+			b.log.Error(ctx, "targetbus", "status", "expiring pauses failed", "error", err)
+		}
+	}
+}
+
+// Query retrieves all targets visible to the caller.
+func (b *Business) Query(ctx context.Context) ([]Target, error) {
+	tgts, err := b.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	now := time.Now()
+
+	tgts = tenant.Filter(tenant.Get(ctx), tgts, func(t Target) string { return t.Namespace })
+	for i := range tgts {
+		tgts[i].identify()
+		tgts[i].expirePause(now)
+	}
+
+	return tgts, nil
+}
+
+// QueryByName retrieves the specified target. A target outside the caller's
+// namespaces is reported as not found.
+func (b *Business) QueryByName(ctx context.Context, name string) (Target, error) {
+	tgt, err := b.storer.QueryByName(ctx, name)
+	if err != nil {
+		return Target{}, fmt.Errorf("query: name[%s]: %w", name, err)
+	}
+
+	if !tenant.Get(ctx).Allows(tgt.Namespace) {
+		return Target{}, fmt.Errorf("query: name[%s]: %w", name, ErrNotFound)
+	}
+
+	tgt.identify()
+	tgt.expirePause(time.Now())
+
+	return tgt, nil
+}
+
+// notifyPaused tells interested domains that a target was paused or
+// resumed.
+func (b *Business) notifyPaused(ctx context.Context, tgt Target) {
+	if b.delegate == nil {
+		return
+	}
+
+	if err := b.delegate.Call(ctx, ActionPausedData(tgt)); err != nil {
+		b.log.Error(ctx, "targetbus", "status", "delegate call failed", "error", err)
+	}
+}
+
+// notifySaved tells interested domains that a target was created or changed.
+func (b *Business) notifySaved(ctx context.Context, tgt Target) {
+	if b.delegate == nil {
+		return
+	}
+
+	if err := b.delegate.Call(ctx, ActionSavedData(tgt)); err != nil {
+		b.log.Error(ctx, "targetbus", "status", "delegate call failed", "error", err)
+	}
+}
+
+// LoadFile seeds targets from a YAML config file. Targets that already exist
+// are left untouched so API changes survive restarts.
+func (b *Business) LoadFile(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading targets file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing targets file: %w", err)
+	}
+
+	for _, nt := range file.Targets {
+		if nt.Name == "" {
+			continue
+		}
+
+		if _, err := b.Create(ctx, nt); err != nil {
+			if errors.Is(err, ErrExists) {
+				continue
+			}
+			return fmt.Errorf("loading target %s: %w", nt.Name, err)
+		}
+	}
+
+	b.log.Info(ctx, "targetbus", "status", "targets loaded", "path", path, "count", len(file.Targets))
+
+	return nil
+}
+
+// Export returns the targets visible to the caller in the format read by
+// Import and LoadFile.
+func (b *Business) Export(ctx context.Context) (File, error) {
+	tgts, err := b.Query(ctx)
+	if err != nil {
+		return File{}, fmt.Errorf("export: %w", err)
+	}
+
+	file := File{
+		Targets: make([]NewTarget, len(tgts)),
+	}
+	for i, tgt := range tgts {
+		file.Targets[i] = toNewTarget(tgt)
+	}
+
+	return file, nil
+}
+
+// Import makes the targets visible to the caller match the file: missing
+// targets are created, changed ones updated and those not in the file
+// deleted. Callers scoped to a tenant may only import targets in their own
+// namespaces; nothing is changed if any target is outside them.
+func (b *Business) Import(ctx context.Context, file File) (ImportResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	scope := tenant.Get(ctx)
+	for _, nt := range file.Targets {
+		if !scope.Allows(nt.Namespace) {
+			return ImportResult{}, fmt.Errorf("import: namespace[%s]: %w", nt.Namespace, tenant.ErrNamespace)
+		}
+	}
+
+	existing, err := b.Query(ctx)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("import: %w", err)
+	}
+
+	byName := make(map[string]Target, len(existing))
+	for _, tgt := range existing {
+		byName[tgt.Name] = tgt
+	}
+
+	var result ImportResult
+	for _, nt := range file.Targets {
+		tgt, ok := byName[nt.Name]
+		delete(byName, nt.Name)
+
+		switch {
+		case !ok:
+			if _, err := b.create(ctx, nt); err != nil {
+				return result, fmt.Errorf("import: name[%s]: %w", nt.Name, err)
+			}
+			result.Created++
+
+		case toNewTarget(tgt).equal(nt):
+			result.Unchanged++
+
+		default:
+			version := tgt.Version
+			tgt = fromNewTarget(nt, tgt.DateCreated)
+			tgt.Version = version + 1
+			tgt.DateUpdated = time.Now().UTC()
+			if err := b.storer.Update(ctx, tgt); err != nil {
+				return result, fmt.Errorf("import: name[%s]: %w", nt.Name, err)
+			}
+			b.notifySaved(ctx, tgt)
+			result.Updated++
+		}
+	}
+
+	for name := range byName {
+		if err := b.storer.Delete(ctx, name); err != nil {
+			return result, fmt.Errorf("import: name[%s]: %w", name, err)
+		}
+		result.Deleted++
+	}
+
+	b.log.Info(ctx, "targetbus", "status", "targets imported", "created", result.Created,
+		"updated", result.Updated, "deleted", result.Deleted, "unchanged", result.Unchanged)
+
+	return result, nil
+}
+
+// =============================================================================
+
+// Target represents metadata attached to a health check target. The ID is
+// derived from the name, so it is the same in every cluster and survives
+// export and import. Version increases with every change.
+type Target struct {
+	ID          string            `json:"id"`
+	Version     int               `json:"version"`
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Team        string            `json:"team,omitempty"`
+	RunbookURL  string            `json:"runbook_url,omitempty"`
+	Severity    string            `json:"severity,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Module      string            `json:"module,omitempty"`
+	DependsOn   []string          `json:"depends_on,omitempty"`
+	LatencySLO  float64           `json:"latency_slo_seconds,omitempty"`
+	LogSelector string            `json:"log_selector,omitempty"`
+	Workload    string            `json:"workload,omitempty"`
+	DateCreated time.Time         `json:"date_created"`
+	DateUpdated time.Time         `json:"date_updated"`
+
+	// Paused stops probing and alerting for the target until it is resumed
+	// or, when set, PausedUntil passes. Pausing is operational state: it
+	// doesn't change the version and isn't exported.
+	Paused      bool       `json:"paused,omitempty"`
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	PauseReason string     `json:"pause_reason,omitempty"`
+}
+
+// NewTarget contains the information needed to create a target.
+type NewTarget struct {
+	Name        string            `json:"name" yaml:"name" validate:"required"`
+	Namespace   string            `json:"namespace" yaml:"namespace,omitempty"`
+	Team        string            `json:"team" yaml:"team,omitempty"`
+	RunbookURL  string            `json:"runbook_url" yaml:"runbook_url,omitempty" validate:"url"`
+	Severity    string            `json:"severity" yaml:"severity,omitempty"`
+	Tags        map[string]string `json:"tags" yaml:"tags,omitempty"`
+	Module      string            `json:"module" yaml:"module,omitempty"`
+	DependsOn   []string          `json:"depends_on" yaml:"depends_on,omitempty"`
+	LatencySLO  float64           `json:"latency_slo_seconds" yaml:"latency_slo_seconds,omitempty" validate:"min=0"`
+	LogSelector string            `json:"log_selector" yaml:"log_selector,omitempty"`
+	Workload    string            `json:"workload" yaml:"workload,omitempty"`
+}
+
+// equal reports whether both describe the same target configuration.
+func (nt NewTarget) equal(other NewTarget) bool {
+	return nt.Name == other.Name &&
+		nt.Namespace == other.Namespace &&
+		nt.Team == other.Team &&
+		nt.RunbookURL == other.RunbookURL &&
+		nt.Severity == other.Severity &&
+		maps.Equal(nt.Tags, other.Tags) &&
+		nt.Module == other.Module &&
+		slices.Equal(nt.DependsOn, other.DependsOn) &&
+		nt.LatencySLO == other.LatencySLO &&
+		nt.LogSelector == other.LogSelector &&
+		nt.Workload == other.Workload
+}
+
+// fromNewTarget builds a target from its configuration with both dates set
+// to created.
+func fromNewTarget(nt NewTarget, created time.Time) Target {
+	return Target{
+		ID:          targetID(nt.Name),
+		Version:     1,
+		Name:        nt.Name,
+		Namespace:   nt.Namespace,
+		Team:        nt.Team,
+		RunbookURL:  nt.RunbookURL,
+		Severity:    nt.Severity,
+		Tags:        nt.Tags,
+		Module:      nt.Module,
+		DependsOn:   nt.DependsOn,
+		LatencySLO:  nt.LatencySLO,
+		LogSelector: nt.LogSelector,
+		Workload:    nt.Workload,
+		DateCreated: created,
+		DateUpdated: created,
+	}
+}
+
+// identify fills in the ID and version of targets stored before they were
+// introduced.
+func (tgt *Target) identify() {
+	if tgt.ID == "" {
+		tgt.ID = targetID(tgt.Name)
+	}
+	if tgt.Version == 0 {
+		tgt.Version = 1
+	}
+}
+
+// expirePause resumes a target whose pause ran out before now.
+func (tgt *Target) expirePause(now time.Time) {
+	if tgt.Paused && tgt.PausedUntil != nil && !now.Before(*tgt.PausedUntil) {
+		tgt.Paused = false
+		tgt.PausedUntil = nil
+		tgt.PauseReason = ""
+	}
+}
+
+// targetID returns the stable ID of the named target.
+func targetID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:8])
+}
+
+// toNewTarget returns the configuration of a target.
+func toNewTarget(tgt Target) NewTarget {
+	return NewTarget{
+		Name:        tgt.Name,
+		Namespace:   tgt.Namespace,
+		Team:        tgt.Team,
+		RunbookURL:  tgt.RunbookURL,
+		Severity:    tgt.Severity,
+		Tags:        tgt.Tags,
+		Module:      tgt.Module,
+		DependsOn:   tgt.DependsOn,
+		LatencySLO:  tgt.LatencySLO,
+		LogSelector: tgt.LogSelector,
+		Workload:    tgt.Workload,
+	}
+}
+
+// UpdateTarget contains the fields that can be changed on a target. Nil
+// fields are left unchanged.
+type UpdateTarget struct {
+	Namespace   *string           `json:"namespace"`
+	Team        *string           `json:"team"`
+	RunbookURL  *string           `json:"runbook_url" validate:"url"`
+	Severity    *string           `json:"severity"`
+	Tags        map[string]string `json:"tags"`
+	Module      *string           `json:"module"`
+	DependsOn   []string          `json:"depends_on"`
+	LatencySLO  *float64          `json:"latency_slo_seconds" validate:"min=0"`
+	LogSelector *string           `json:"log_selector"`
+	Workload    *string           `json:"workload"`
+}
+
+// newTarget returns the configuration of a target created from the update.
+func (ut UpdateTarget) newTarget(name string) NewTarget {
+	nt := NewTarget{
+		Name:      name,
+		Tags:      ut.Tags,
+		DependsOn: ut.DependsOn,
+	}
+	if ut.Namespace != nil {
+		nt.Namespace = *ut.Namespace
+	}
+	if ut.Team != nil {
+		nt.Team = *ut.Team
+	}
+	if ut.RunbookURL != nil {
+		nt.RunbookURL = *ut.RunbookURL
+	}
+	if ut.Severity != nil {
+		nt.Severity = *ut.Severity
+	}
+	if ut.Module != nil {
+		nt.Module = *ut.Module
+	}
+	if ut.LatencySLO != nil {
+		nt.LatencySLO = *ut.LatencySLO
+	}
+	if ut.LogSelector != nil {
+		nt.LogSelector = *ut.LogSelector
+	}
+	if ut.Workload != nil {
+		nt.Workload = *ut.Workload
+	}
+
+	return nt
+}
+
+// File is the YAML document holding a target inventory, as read from
+// TARGETS_FILE and exchanged by the export endpoint.
+type File struct {
+	Targets []NewTarget `json:"targets" yaml:"targets" validate:"required"`
+}
+
+// ImportResult counts the changes made by Import.
+type ImportResult struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Deleted   int `json:"deleted"`
+	Unchanged int `json:"unchanged"`
+}
@@ -0,0 +1,40 @@
+package viewbus
+
+import (
+	"time"
+
+	"health-api/business/sdk/tenant"
+)
+
+// View represents a saved filter and grouping of the health list. Filter is
+// a health filter expression and GroupBy the check fields the dashboard
+// groups the matching checks by. A view belongs to the tenant it was saved
+// in; shared views are visible to everyone in the tenant, others to their
+// owner only.
+type View struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Shared    bool      `json:"shared"`
+	Filter    string    `json:"filter,omitempty"`
+	GroupBy   []string  `json:"group_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewView contains the information needed to save a view.
+type NewView struct {
+	Name    string   `json:"name" validate:"required"`
+	Shared  bool     `json:"shared"`
+	Filter  string   `json:"filter"`
+	GroupBy []string `json:"group_by"`
+}
+
+// visibleTo reports whether the view can be seen by user within scope.
+func (v View) visibleTo(scope tenant.Scope, user string) bool {
+	if v.Tenant != scope.Name {
+		return false
+	}
+
+	return v.Shared || v.Owner == user
+}
@@ -0,0 +1,74 @@
+// Package viewdb implements the saved view store on top of jsondb.
+package viewdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"health-api/business/domain/viewbus"
+	"health-api/business/sdk/jsondb"
+	"health-api/foundation/logger"
+)
+
+// Store implements viewbus.Storer.
+type Store struct {
+	log   *logger.Logger
+	views *jsondb.Collection[viewbus.View]
+}
+
+// NewStore opens the views collection.
+func NewStore(log *logger.Logger, db *jsondb.DB) (*Store, error) {
+	views, err := jsondb.NewCollection[viewbus.View](db, "views")
+	if err != nil {
+		return nil, fmt.Errorf("opening views: %w", err)
+	}
+
+	return &Store{
+		log:   log,
+		views: views,
+	}, nil
+}
+
+// Create inserts a new view.
+func (s *Store) Create(ctx context.Context, v viewbus.View) error {
+	if err := s.views.Insert(v.ID, v); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a view.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if err := s.views.Delete(id); err != nil {
+		if errors.Is(err, jsondb.ErrNotFound) {
+			return viewbus.ErrNotFound
+		}
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// Query retrieves all views ordered by name.
+func (s *Store) Query(ctx context.Context) ([]viewbus.View, error) {
+	views := s.views.All()
+
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	return views, nil
+}
+
+// QueryByID retrieves the view with the specified ID.
+func (s *Store) QueryByID(ctx context.Context, id string) (viewbus.View, error) {
+	v, ok := s.views.Get(id)
+	if !ok {
+		return viewbus.View{}, viewbus.ErrNotFound
+	}
+
+	return v, nil
+}
@@ -0,0 +1,124 @@
+// Package viewbus provides business logic for saved views, named filter
+// and grouping configurations of the health list that the dashboard offers
+// to its users.
+package viewbus
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"health-api/business/sdk/tenant"
+	"health-api/foundation/logger"
+)
+
+// Set of error variables for view operations.
+var (
+	ErrNotFound = errors.New("view not found")
+	ErrNotOwner = errors.New("view owned by another user")
+)
+
+// Storer defines the interface for view data access.
+type Storer interface {
+	Create(ctx context.Context, v View) error
+	Delete(ctx context.Context, id string) error
+	Query(ctx context.Context) ([]View, error)
+	QueryByID(ctx context.Context, id string) (View, error)
+}
+
+// Business manages saved views.
+type Business struct {
+	log    *logger.Logger
+	storer Storer
+}
+
+// NewBusiness creates a new view business layer.
+func NewBusiness(log *logger.Logger, storer Storer) *Business {
+	return &Business{
+		log:    log,
+		storer: storer,
+	}
+}
+
+// Create saves a new view owned by owner within the caller's tenant.
+func (b *Business) Create(ctx context.Context, owner string, nv NewView) (View, error) {
+	v := View{
+		ID:        newID(),
+		Name:      nv.Name,
+		Owner:     owner,
+		Tenant:    tenant.Get(ctx).Name,
+		Shared:    nv.Shared,
+		Filter:    nv.Filter,
+		GroupBy:   nv.GroupBy,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := b.storer.Create(ctx, v); err != nil {
+		return View{}, fmt.Errorf("create: %w", err)
+	}
+
+	b.log.Info(ctx, "viewbus", "status", "view created", "id", v.ID, "name", v.Name, "owner", owner, "shared", v.Shared)
+
+	return v, nil
+}
+
+// Delete removes the specified view. Only its owner can delete it.
+func (b *Business) Delete(ctx context.Context, user string, id string) error {
+	v, err := b.QueryByID(ctx, user, id)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	if v.Owner != user {
+		return fmt.Errorf("delete: id[%s]: %w", id, ErrNotOwner)
+	}
+
+	if err := b.storer.Delete(ctx, id); err != nil {
+		return fmt.Errorf("delete: id[%s]: %w", id, err)
+	}
+
+	return nil
+}
+
+// Query retrieves the views visible to user: their own and the shared views
+// of their tenant.
+func (b *Business) Query(ctx context.Context, user string) ([]View, error) {
+	views, err := b.storer.Query(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	scope := tenant.Get(ctx)
+
+	visible := make([]View, 0, len(views))
+	for _, v := range views {
+		if v.visibleTo(scope, user) {
+			visible = append(visible, v)
+		}
+	}
+
+	return visible, nil
+}
+
+// QueryByID retrieves the specified view if it is visible to user.
+func (b *Business) QueryByID(ctx context.Context, user string, id string) (View, error) {
+	v, err := b.storer.QueryByID(ctx, id)
+	if err != nil {
+		return View{}, fmt.Errorf("query: id[%s]: %w", id, err)
+	}
+
+	if !v.visibleTo(tenant.Get(ctx), user) {
+		return View{}, fmt.Errorf("query: id[%s]: %w", id, ErrNotFound)
+	}
+
+	return v, nil
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
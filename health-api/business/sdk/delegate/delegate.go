@@ -0,0 +1,64 @@
+// Package delegate provides the ability to make function calls between
+// different domain packages when an import is not possible.
+package delegate
+
+import (
+	"context"
+
+	"health-api/foundation/logger"
+)
+
+// Data represents the event that is passed to the registered functions.
+type Data struct {
+	Domain    string
+	Action    string
+	RawParams []byte
+}
+
+// Func represents a function that can receive events.
+type Func func(context.Context, Data) error
+
+// Delegate manages the set of functions to be called by domain packages.
+type Delegate struct {
+	log   *logger.Logger
+	funcs map[string]map[string][]Func
+}
+
+// New constructs a delegate for indirect api access.
+func New(log *logger.Logger) *Delegate {
+	return &Delegate{
+		log:   log,
+		funcs: make(map[string]map[string][]Func),
+	}
+}
+
+// Register adds a function to be called for the specified domain and action.
+// Register is not safe for concurrent use and must be called during startup.
+func (d *Delegate) Register(domain string, action string, fn Func) {
+	aMap, ok := d.funcs[domain]
+	if !ok {
+		aMap = make(map[string][]Func)
+		d.funcs[domain] = aMap
+	}
+
+	aMap[action] = append(aMap[action], fn)
+}
+
+// Call executes all functions registered for the specified domain and
+// action. Errors are logged and do not stop the remaining functions.
+func (d *Delegate) Call(ctx context.Context, data Data) error {
+	d.log.Debug(ctx, "delegate call", "status", "started", "domain", data.Domain, "action", data.Action)
+	defer d.log.Debug(ctx, "delegate call", "status", "completed")
+
+	if dMap, ok := d.funcs[data.Domain]; ok {
+		if funcs, ok := dMap[data.Action]; ok {
+			for _, fn := range funcs {
+				if err := fn(ctx, data); err != nil {
+					d.log.Error(ctx, "delegate call", "domain", data.Domain, "action", data.Action, "error", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+// Package filterexpr parses filter expressions such as
+//
+//	status=down AND (team=payments OR probe=icmp)
+//
+// and matches them against records. A comparison is a field, an operator
+// and a value: = and != compare exactly, ~ matches when the value is a
+// substring, ignoring case. Comparisons combine with AND, OR and NOT,
+// which bind in the order NOT, AND, OR, and with parentheses. Keywords are
+// case-insensitive. Values containing spaces, parentheses or operators are
+// double-quoted, with \" and \\ as escapes.
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError reports an invalid expression. Pos is the 1-based position
+// of the offending character.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+// Lookup returns the values of a record's field. A field with several
+// values, such as a tag list, matches = when any value does.
+type Lookup func(field string) []string
+
+// Expr is a parsed expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses s. Fields are checked with known, which reports whether a
+// field name exists; nil accepts any field.
+func Parse(s string, known func(field string) bool) (*Expr, error) {
+	p := parser{src: s, known: known}
+	p.next()
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+
+	return &Expr{src: s, root: root}, nil
+}
+
+// Match reports whether the record behind lookup satisfies the expression.
+func (e *Expr) Match(lookup Lookup) bool {
+	return e.root.match(lookup)
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// =============================================================================
+
+type node interface {
+	match(lookup Lookup) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(l Lookup) bool { return n.left.match(l) && n.right.match(l) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(l Lookup) bool { return n.left.match(l) || n.right.match(l) }
+
+type notNode struct{ expr node }
+
+func (n notNode) match(l Lookup) bool { return !n.expr.match(l) }
+
+type cmpNode struct {
+	field string
+	op    string
+	value string
+}
+
+func (n cmpNode) match(l Lookup) bool {
+	values := l(n.field)
+
+	switch n.op {
+	case "=":
+		for _, v := range values {
+			if v == n.value {
+				return true
+			}
+		}
+		return len(values) == 0 && n.value == ""
+
+	case "!=":
+		return !cmpNode{field: n.field, op: "=", value: n.value}.match(l)
+
+	case "~":
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), strings.ToLower(n.value)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// =============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type parser struct {
+	src   string
+	off   int
+	tok   token
+	known func(field string) bool
+	err   *SyntaxError
+}
+
+func (p *parser) errorf(format string, args ...any) *SyntaxError {
+	return &SyntaxError{Pos: p.tok.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next scans the following token into p.tok. Scanning errors are kept in
+// p.err and surface as the token's position when parsing fails on it.
+func (p *parser) next() {
+	for p.off < len(p.src) && (p.src[p.off] == ' ' || p.src[p.off] == '\t' || p.src[p.off] == '\n') {
+		p.off++
+	}
+
+	start := p.off
+	if p.off >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	switch c := p.src[p.off]; {
+	case c == '(':
+		p.off++
+		p.tok = token{kind: tokLParen, text: "(", pos: start}
+
+	case c == ')':
+		p.off++
+		p.tok = token{kind: tokRParen, text: ")", pos: start}
+
+	case c == '=' || c == '~':
+		p.off++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+
+	case c == '!':
+		if p.off+1 < len(p.src) && p.src[p.off+1] == '=' {
+			p.off += 2
+			p.tok = token{kind: tokOp, text: "!=", pos: start}
+			return
+		}
+		p.off++
+		p.tok = token{kind: tokOp, text: "!", pos: start}
+
+	case c == '"':
+		p.scanString()
+
+	default:
+		for p.off < len(p.src) && !strings.ContainsRune(" \t\n()=~!\"", rune(p.src[p.off])) {
+			p.off++
+		}
+		word := p.src[start:p.off]
+
+		kind := tokWord
+		switch strings.ToUpper(word) {
+		case "AND":
+			kind = tokAnd
+		case "OR":
+			kind = tokOr
+		case "NOT":
+			kind = tokNot
+		}
+		p.tok = token{kind: kind, text: word, pos: start}
+	}
+}
+
+func (p *parser) scanString() {
+	start := p.off
+	p.off++
+
+	var b strings.Builder
+	for p.off < len(p.src) {
+		c := p.src[p.off]
+		switch {
+		case c == '"':
+			p.off++
+			p.tok = token{kind: tokString, text: b.String(), pos: start}
+			return
+		case c == '\\' && p.off+1 < len(p.src):
+			b.WriteByte(p.src[p.off+1])
+			p.off += 2
+		default:
+			b.WriteByte(c)
+			p.off++
+		}
+	}
+
+	p.err = &SyntaxError{Pos: start + 1, Msg: "unterminated string"}
+	p.tok = token{kind: tokEOF, pos: start}
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	switch p.tok.kind {
+	case tokNot:
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{expr}, nil
+
+	case tokLParen:
+		open := p.tok
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			if p.tok.kind == tokEOF {
+				return nil, &SyntaxError{Pos: open.pos + 1, Msg: "unclosed parenthesis"}
+			}
+			return nil, p.errorf("expected \")\", got %s", p.tok)
+		}
+		p.next()
+		return expr, nil
+
+	case tokWord:
+		return p.parseComparison()
+	}
+
+	return nil, p.errorf("expected a comparison, got %s", p.tok)
+}
+
+func (p *parser) parseComparison() (node, error) {
+	field := p.tok
+	if p.known != nil && !p.known(strings.ToLower(field.text)) {
+		return nil, p.errorf("unknown field %q", field.text)
+	}
+	p.next()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokOp || p.tok.text == "!" {
+		return nil, p.errorf("expected =, != or ~ after %q, got %s", field.text, p.tok)
+	}
+	op := p.tok.text
+	p.next()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return nil, p.errorf("expected a value after %q, got %s", op, p.tok)
+	}
+	value := p.tok.text
+	p.next()
+
+	return cmpNode{field: strings.ToLower(field.text), op: op, value: value}, nil
+}
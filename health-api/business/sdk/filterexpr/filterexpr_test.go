@@ -0,0 +1,125 @@
+package filterexpr_test
+
+import (
+	"errors"
+	"testing"
+
+	"health-api/business/sdk/filterexpr"
+)
+
+// record is a target as the expressions see it.
+var record = map[string][]string{
+	"status": {"down"},
+	"team":   {"payments"},
+	"probe":  {"http_2xx"},
+	"name":   {"Shop Checkout (EU)"},
+	"tags":   {"prod", "eu"},
+}
+
+func lookup(field string) []string {
+	return record[field]
+}
+
+func known(field string) bool {
+	_, ok := record[field]
+	return ok || field == "owner"
+}
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "status=down", want: true},
+		{expr: "status=healthy", want: false},
+		{expr: "status!=healthy", want: true},
+		{expr: "STATUS=down", want: true},
+		{expr: "status=Down", want: false},
+		{expr: "name~checkout", want: true},
+		{expr: "name~CHECKOUT", want: true},
+		{expr: "name~billing", want: false},
+		{expr: `name="Shop Checkout (EU)"`, want: true},
+		{expr: `name~"(eu)"`, want: true},
+		{expr: "tags=eu", want: true},
+		{expr: "tags=us", want: false},
+		{expr: "tags!=prod", want: false},
+		{expr: `owner=""`, want: true},
+		{expr: "owner!=jane", want: true},
+		{expr: "owner~jane", want: false},
+		{expr: "status=down and team=payments", want: true},
+		{expr: "status=down AND team=search", want: false},
+		{expr: "status=healthy OR team=payments", want: true},
+		{expr: "NOT status=healthy", want: true},
+		{expr: "not not status=down", want: true},
+		{expr: "status=down AND (team=search OR probe=http_2xx)", want: true},
+		{expr: "status=healthy OR team=payments AND probe=icmp", want: false},
+		{expr: "(status=healthy OR team=payments) AND probe=icmp", want: false},
+		{expr: "NOT status=down OR team=payments", want: true},
+		{expr: "NOT (status=down OR team=search)", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := filterexpr.Parse(tt.expr, known)
+			if err != nil {
+				t.Fatalf("Should be able to parse: %s", err)
+			}
+
+			if got := expr.Match(lookup); got != tt.want {
+				t.Errorf("Should match as %t, got %t", tt.want, got)
+			}
+
+			if expr.String() != tt.expr {
+				t.Errorf("Should keep the source, got %q", expr.String())
+			}
+		})
+	}
+}
+
+func Test_Escapes(t *testing.T) {
+	expr, err := filterexpr.Parse(`name="say \"hi\" \\ bye"`, nil)
+	if err != nil {
+		t.Fatalf("Should be able to parse: %s", err)
+	}
+
+	match := expr.Match(func(string) []string { return []string{`say "hi" \ bye`} })
+	if !match {
+		t.Error("Should unescape quotes and backslashes")
+	}
+}
+
+func Test_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{expr: "", pos: 1, msg: "expected a comparison, got end of expression"},
+		{expr: "status=", pos: 8, msg: `expected a value after "=", got end of expression`},
+		{expr: "status down", pos: 8, msg: `expected =, != or ~ after "status", got "down"`},
+		{expr: "status!down", pos: 7, msg: `expected =, != or ~ after "status", got "!"`},
+		{expr: "status=down AND", pos: 16, msg: "expected a comparison, got end of expression"},
+		{expr: "status=down team=payments", pos: 13, msg: `unexpected "team"`},
+		{expr: "status=down)", pos: 12, msg: `unexpected ")"`},
+		{expr: "(status=down", pos: 1, msg: "unclosed parenthesis"},
+		{expr: "(status=down team=x)", pos: 14, msg: `expected ")", got "team"`},
+		{expr: "colour=red", pos: 1, msg: `unknown field "colour"`},
+		{expr: `name="unterminated`, pos: 6, msg: "unterminated string"},
+		{expr: "team=AND", pos: 6, msg: `expected a value after "=", got "AND"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := filterexpr.Parse(tt.expr, known)
+
+			var se *filterexpr.SyntaxError
+			if !errors.As(err, &se) {
+				t.Fatalf("Should return a syntax error, got %v", err)
+			}
+
+			if se.Pos != tt.pos || se.Msg != tt.msg {
+				t.Errorf("Should report %q at %d, got %q at %d", tt.msg, tt.pos, se.Msg, se.Pos)
+			}
+		})
+	}
+}
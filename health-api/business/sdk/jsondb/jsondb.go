@@ -0,0 +1,284 @@
+// Package jsondb provides a small document store persisted as JSON files.
+// It backs the domain db stores when no external database is available.
+package jsondb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Set of error variables for collection operations.
+var (
+	ErrNotFound = errors.New("document not found")
+	ErrExists   = errors.New("document already exists")
+)
+
+// DB represents a directory of JSON collections. An empty directory keeps
+// all collections in memory only.
+type DB struct {
+	dir string
+}
+
+// Open prepares the data directory for use.
+func Open(dir string) (*DB, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating data dir: %w", err)
+		}
+	}
+
+	return &DB{dir: dir}, nil
+}
+
+// Persistent reports whether collections are written to disk.
+func (db *DB) Persistent() bool {
+	return db.dir != ""
+}
+
+// Collection is a set of documents of the same type keyed by ID.
+type Collection[T any] struct {
+	mu    sync.RWMutex
+	path  string
+	items map[string]T
+}
+
+// NewCollection opens the named collection, loading any persisted documents.
+func NewCollection[T any](db *DB, name string) (*Collection[T], error) {
+	c := Collection[T]{
+		items: make(map[string]T),
+	}
+
+	if db.dir == "" {
+		return &c, nil
+	}
+
+	c.path = filepath.Join(db.dir, name+".json")
+
+	data, err := os.ReadFile(c.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &c, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading collection %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, &c.items); err != nil {
+		return nil, fmt.Errorf("decoding collection %s: %w", name, err)
+	}
+
+	return &c, nil
+}
+
+// Get returns the document with the specified ID.
+func (c *Collection[T]) Get(id string) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v, ok := c.items[id]
+	return v, ok
+}
+
+// Put stores the document under the specified ID, replacing any existing one.
+func (c *Collection[T]) Put(id string, v T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, ok := c.items[id]
+
+	c.items[id] = v
+	if err := c.flush(); err != nil {
+		c.restore(id, old, ok)
+		return err
+	}
+
+	return nil
+}
+
+// PutMany stores the documents under their IDs, replacing existing ones,
+// and removes the documents drop reports true for, writing the collection
+// once. drop may be nil.
+func (c *Collection[T]) PutMany(docs map[string]T, drop func(T) bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := maps.Clone(c.items)
+
+	for id, v := range docs {
+		c.items[id] = v
+	}
+
+	if drop != nil {
+		for id, v := range c.items {
+			if drop(v) {
+				delete(c.items, id)
+			}
+		}
+	}
+
+	if err := c.flush(); err != nil {
+		c.items = prev
+		return err
+	}
+
+	return nil
+}
+
+// Insert stores a new document, failing with ErrExists if the ID is taken.
+func (c *Collection[T]) Insert(id string, v T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.items[id]; ok {
+		return ErrExists
+	}
+
+	c.items[id] = v
+	if err := c.flush(); err != nil {
+		delete(c.items, id)
+		return err
+	}
+
+	return nil
+}
+
+// Replace overwrites an existing document, failing with ErrNotFound if the
+// ID is unknown.
+func (c *Collection[T]) Replace(id string, v T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, ok := c.items[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	c.items[id] = v
+	if err := c.flush(); err != nil {
+		c.items[id] = old
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the document with the specified ID, failing with
+// ErrNotFound if the ID is unknown.
+func (c *Collection[T]) Delete(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, ok := c.items[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	delete(c.items, id)
+	if err := c.flush(); err != nil {
+		c.items[id] = old
+		return err
+	}
+
+	return nil
+}
+
+// All returns every document ordered by ID.
+func (c *Collection[T]) All() []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := make([]string, 0, len(c.items))
+	for id := range c.items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	items := make([]T, len(ids))
+	for i, id := range ids {
+		items[i] = c.items[id]
+	}
+
+	return items
+}
+
+// restore puts back what id held before a write that failed to flush.
+// The caller must hold the lock.
+func (c *Collection[T]) restore(id string, v T, ok bool) {
+	if ok {
+		c.items[id] = v
+		return
+	}
+	delete(c.items, id)
+}
+
+// flush writes the collection to disk. The collection is written to a
+// temporary file that is synced before it replaces the old one, and the
+// directory is synced after, so a crash leaves either the old or the new
+// collection rather than a truncated one. The caller must hold the lock, and must undo its change when
+// flush fails so memory and disk agree.
+func (c *Collection[T]) flush() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.items)
+	if err != nil {
+		return fmt.Errorf("encoding collection: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := writeSynced(tmp, data); err != nil {
+		return fmt.Errorf("writing collection: %w", err)
+	}
+
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("replacing collection: %w", err)
+	}
+
+	// The file is replaced at this point, so a failed directory sync is
+	// not reported: undoing the change in memory would leave it behind the
+	// file. Some filesystems don't support syncing directories at all.
+	syncDir(filepath.Dir(c.path))
+
+	return nil
+}
+
+// writeSynced writes data to the named file and syncs it to disk, removing
+// the file again if that fails.
+func writeSynced(name string, data []byte) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		os.Remove(name)
+	}
+
+	return err
+}
+
+// syncDir syncs a directory so a rename within it is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -0,0 +1,211 @@
+package jsondb_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"health-api/business/sdk/jsondb"
+)
+
+type doc struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func Test_Collection(t *testing.T) {
+	db, err := jsondb.Open("")
+	if err != nil {
+		t.Fatalf("Should be able to open the db: %s", err)
+	}
+
+	if db.Persistent() {
+		t.Error("Should keep a db without a directory in memory")
+	}
+
+	c, err := jsondb.NewCollection[doc](db, "docs")
+	if err != nil {
+		t.Fatalf("Should be able to open the collection: %s", err)
+	}
+
+	if err := c.Insert("b", doc{Name: "b"}); err != nil {
+		t.Fatalf("Should be able to insert: %s", err)
+	}
+	if err := c.Insert("b", doc{Name: "other"}); !errors.Is(err, jsondb.ErrExists) {
+		t.Errorf("Should refuse to insert a taken ID, got %v", err)
+	}
+
+	if err := c.Replace("a", doc{Name: "a"}); !errors.Is(err, jsondb.ErrNotFound) {
+		t.Errorf("Should refuse to replace an unknown ID, got %v", err)
+	}
+	if err := c.Put("a", doc{Name: "a"}); err != nil {
+		t.Fatalf("Should be able to put: %s", err)
+	}
+	if err := c.Replace("a", doc{Name: "a", Count: 2}); err != nil {
+		t.Fatalf("Should be able to replace: %s", err)
+	}
+
+	if got, ok := c.Get("a"); !ok || got.Count != 2 {
+		t.Errorf("Should get the replaced document, got %+v %t", got, ok)
+	}
+
+	all := c.All()
+	if len(all) != 2 || all[0].Name != "a" || all[1].Name != "b" {
+		t.Errorf("Should list the documents ordered by ID, got %+v", all)
+	}
+
+	if err := c.Delete("b"); err != nil {
+		t.Fatalf("Should be able to delete: %s", err)
+	}
+	if err := c.Delete("b"); !errors.Is(err, jsondb.ErrNotFound) {
+		t.Errorf("Should refuse to delete an unknown ID, got %v", err)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Should not get a deleted document")
+	}
+}
+
+func Test_PutMany(t *testing.T) {
+	db, err := jsondb.Open("")
+	if err != nil {
+		t.Fatalf("Should be able to open the db: %s", err)
+	}
+
+	c, err := jsondb.NewCollection[doc](db, "docs")
+	if err != nil {
+		t.Fatalf("Should be able to open the collection: %s", err)
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := c.Put(id, doc{Name: id, Count: 1}); err != nil {
+			t.Fatalf("Should be able to put: %s", err)
+		}
+	}
+
+	if err := c.PutMany(map[string]doc{"a": {Name: "a", Count: 5}, "d": {Name: "d", Count: 1}}, nil); err != nil {
+		t.Fatalf("Should be able to put many without drop: %s", err)
+	}
+	if len(c.All()) != 4 {
+		t.Errorf("Should add and replace documents, got %+v", c.All())
+	}
+
+	// The new documents are stored before drop runs, so drop sees them too.
+	drop := func(d doc) bool { return d.Count == 1 && d.Name != "e" }
+	if err := c.PutMany(map[string]doc{"e": {Name: "e", Count: 1}, "f": {Name: "f", Count: 1}}, drop); err != nil {
+		t.Fatalf("Should be able to put many with drop: %s", err)
+	}
+
+	var names []string
+	for _, d := range c.All() {
+		names = append(names, d.Name)
+	}
+	if strings.Join(names, ",") != "a,e" {
+		t.Errorf("Should keep a and e only, got %v", names)
+	}
+}
+
+func Test_Persistence(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+
+	db, err := jsondb.Open(dir)
+	if err != nil {
+		t.Fatalf("Should be able to open the db: %s", err)
+	}
+	if !db.Persistent() {
+		t.Error("Should persist a db with a directory")
+	}
+
+	c, err := jsondb.NewCollection[doc](db, "docs")
+	if err != nil {
+		t.Fatalf("Should be able to open the collection: %s", err)
+	}
+	if err := c.PutMany(map[string]doc{"a": {Name: "a"}, "b": {Name: "b", Count: 3}}, nil); err != nil {
+		t.Fatalf("Should be able to put many: %s", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "docs.json.tmp")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Should not leave the temporary file behind, got %v", err)
+	}
+
+	reopened, err := jsondb.NewCollection[doc](db, "docs")
+	if err != nil {
+		t.Fatalf("Should be able to reopen the collection: %s", err)
+	}
+	if got, ok := reopened.Get("b"); !ok || got.Count != 3 {
+		t.Errorf("Should load the persisted documents, got %+v %t", got, ok)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatalf("Should be able to write the broken collection: %s", err)
+	}
+	if _, err := jsondb.NewCollection[doc](db, "broken"); err == nil {
+		t.Error("Should fail to open a corrupt collection")
+	}
+}
+
+func Test_FailedFlush(t *testing.T) {
+	dir := t.TempDir()
+
+	db, err := jsondb.Open(dir)
+	if err != nil {
+		t.Fatalf("Should be able to open the db: %s", err)
+	}
+
+	c, err := jsondb.NewCollection[doc](db, "docs")
+	if err != nil {
+		t.Fatalf("Should be able to open the collection: %s", err)
+	}
+	if err := c.PutMany(map[string]doc{"a": {Name: "a", Count: 1}, "b": {Name: "b", Count: 1}}, nil); err != nil {
+		t.Fatalf("Should be able to put many: %s", err)
+	}
+
+	// A directory in place of the temporary file makes every flush fail,
+	// even for root.
+	tmp := filepath.Join(dir, "docs.json.tmp")
+	if err := os.Mkdir(tmp, 0o755); err != nil {
+		t.Fatalf("Should be able to block the temporary file: %s", err)
+	}
+
+	writes := []struct {
+		name  string
+		write func() error
+	}{
+		{name: "put new", write: func() error { return c.Put("c", doc{Name: "c"}) }},
+		{name: "put existing", write: func() error { return c.Put("a", doc{Name: "a", Count: 9}) }},
+		{name: "insert", write: func() error { return c.Insert("c", doc{Name: "c"}) }},
+		{name: "replace", write: func() error { return c.Replace("a", doc{Name: "a", Count: 9}) }},
+		{name: "delete", write: func() error { return c.Delete("b") }},
+		{name: "put many", write: func() error {
+			return c.PutMany(map[string]doc{"a": {Name: "a", Count: 9}, "c": {Name: "c"}}, func(d doc) bool { return d.Name == "b" })
+		}},
+	}
+
+	for _, w := range writes {
+		if err := w.write(); err == nil {
+			t.Errorf("%s: Should report the failed flush", w.name)
+		}
+
+		all := c.All()
+		if len(all) != 2 || all[0] != (doc{Name: "a", Count: 1}) || all[1] != (doc{Name: "b", Count: 1}) {
+			t.Errorf("%s: Should undo the change in memory, got %+v", w.name, all)
+		}
+	}
+
+	if err := os.Remove(tmp); err != nil {
+		t.Fatalf("Should be able to unblock the temporary file: %s", err)
+	}
+
+	if err := c.Put("c", doc{Name: "c"}); err != nil {
+		t.Fatalf("Should be able to put once flushing works again: %s", err)
+	}
+
+	reopened, err := jsondb.NewCollection[doc](db, "docs")
+	if err != nil {
+		t.Fatalf("Should be able to reopen the collection: %s", err)
+	}
+	if all := reopened.All(); len(all) != 3 || all[0].Count != 1 {
+		t.Errorf("Should persist only the writes that succeeded, got %+v", all)
+	}
+}
@@ -0,0 +1,77 @@
+// Package tenant scopes business queries to the Kubernetes namespaces a
+// caller is allowed to see.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrNamespace is returned when a caller writes outside its namespaces.
+var ErrNamespace = errors.New("namespace not permitted")
+
+// Scope describes the namespaces visible to a tenant. A scope without
+// namespaces is unrestricted.
+type Scope struct {
+	Name       string
+	Namespaces []string
+}
+
+// Restricted reports whether the scope limits what the caller can see.
+func (s Scope) Restricted() bool {
+	return len(s.Namespaces) > 0 && !slices.Contains(s.Namespaces, "*")
+}
+
+// Allows reports whether the namespace is visible within the scope.
+// Resources without a namespace are only visible to unrestricted callers.
+func (s Scope) Allows(namespace string) bool {
+	if !s.Restricted() {
+		return true
+	}
+
+	return slices.Contains(s.Namespaces, namespace)
+}
+
+// =============================================================================
+
+type ctxKey int
+
+const scopeKey ctxKey = 1
+
+// Set stores the scope in the context.
+func Set(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, scopeKey, s)
+}
+
+// Get returns the scope stored in the context. Contexts without a scope,
+// such as background workers, are unrestricted.
+func Get(ctx context.Context) Scope {
+	s, ok := ctx.Value(scopeKey).(Scope)
+	if !ok {
+		return Scope{}
+	}
+	return s
+}
+
+// Unscoped returns a context that sees every namespace. It is used where a
+// domain needs the full picture to compute results it filters afterwards.
+func Unscoped(ctx context.Context) context.Context {
+	return Set(ctx, Scope{})
+}
+
+// Filter returns the items whose namespace is visible within the scope.
+func Filter[T any](s Scope, items []T, namespace func(T) string) []T {
+	if !s.Restricted() {
+		return items
+	}
+
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if s.Allows(namespace(item)) {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
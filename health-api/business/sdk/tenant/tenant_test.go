@@ -0,0 +1,97 @@
+package tenant_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"health-api/business/sdk/tenant"
+)
+
+func Test_Allows(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      tenant.Scope
+		restricted bool
+		allows     map[string]bool
+	}{
+		{
+			name:       "unrestricted",
+			scope:      tenant.Scope{},
+			restricted: false,
+			allows:     map[string]bool{"shop": true, "billing": true, "": true},
+		},
+		{
+			name:       "wildcard",
+			scope:      tenant.Scope{Name: "sre", Namespaces: []string{"shop", "*"}},
+			restricted: false,
+			allows:     map[string]bool{"shop": true, "billing": true, "": true},
+		},
+		{
+			name:       "namespaces",
+			scope:      tenant.Scope{Name: "shop", Namespaces: []string{"shop", "shop-staging"}},
+			restricted: true,
+			allows:     map[string]bool{"shop": true, "shop-staging": true, "billing": false, "": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.Restricted(); got != tt.restricted {
+				t.Errorf("Should report restricted as %t, got %t", tt.restricted, got)
+			}
+
+			for ns, want := range tt.allows {
+				if got := tt.scope.Allows(ns); got != want {
+					t.Errorf("Should report namespace %q allowed as %t, got %t", ns, want, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_Context(t *testing.T) {
+	if s := tenant.Get(context.Background()); s.Restricted() {
+		t.Errorf("Should leave a context without a scope unrestricted, got %+v", s)
+	}
+
+	shop := tenant.Scope{Name: "shop", Namespaces: []string{"shop"}}
+	ctx := tenant.Set(context.Background(), shop)
+
+	if s := tenant.Get(ctx); s.Name != "shop" || !s.Allows("shop") || s.Allows("billing") {
+		t.Errorf("Should return the scope set on the context, got %+v", s)
+	}
+
+	if s := tenant.Get(tenant.Unscoped(ctx)); s.Restricted() {
+		t.Errorf("Should lift the scope of an unscoped context, got %+v", s)
+	}
+}
+
+func Test_Filter(t *testing.T) {
+	type item struct {
+		name      string
+		namespace string
+	}
+
+	items := []item{
+		{name: "web", namespace: "shop"},
+		{name: "ledger", namespace: "billing"},
+		{name: "node/worker-1"},
+		{name: "cache", namespace: "shop"},
+	}
+	namespace := func(i item) string { return i.namespace }
+
+	if got := tenant.Filter(tenant.Scope{}, items, namespace); len(got) != len(items) {
+		t.Errorf("Should keep every item without restrictions, got %v", got)
+	}
+
+	got := tenant.Filter(tenant.Scope{Name: "shop", Namespaces: []string{"shop"}}, items, namespace)
+
+	var names []string
+	for _, i := range got {
+		names = append(names, i.name)
+	}
+	if !slices.Equal(names, []string{"web", "cache"}) {
+		t.Errorf("Should keep the shop's items in order and drop unnamespaced ones, got %v", names)
+	}
+}
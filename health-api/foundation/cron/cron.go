@@ -0,0 +1,238 @@
+// Package cron parses standard five-field cron expressions and computes
+// their next activation time.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule represents a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar, hourStar    bool
+}
+
+// Set of descriptors accepted in place of a five-field expression.
+var descriptors = map[string]string{
+	"@yearly":  "0 0 1 1 *",
+	"@monthly": "0 0 1 * *",
+	"@weekly":  "0 0 * * 0",
+	"@daily":   "0 0 * * *",
+	"@hourly":  "0 * * * *",
+}
+
+// Parse parses a cron expression of the form "min hour dom month dow".
+func Parse(expr string) (Schedule, error) {
+	if d, ok := descriptors[expr]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var s Schedule
+	var err error
+
+	if s.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return Schedule{}, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return Schedule{}, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return Schedule{}, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseField(fields[3], 1, 12); err != nil {
+		return Schedule{}, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return Schedule{}, fmt.Errorf("day of week: %w", err)
+	}
+
+	// Sunday may be written as 0 or 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+	s.hourStar = fields[1] == "*"
+
+	return s, nil
+}
+
+// Next returns the first activation time strictly after t, following the
+// wall clock of t's location. Across daylight saving changes schedules with
+// a fixed hour behave like cron: a time the clock skips runs once when the
+// clock resumes, and a time it repeats runs only the first time. Schedules
+// for every hour keep running at their minutes throughout.
+func (s Schedule) Next(t time.Time) time.Time {
+	from := t.Truncate(time.Minute)
+	if t = from.Add(time.Minute); s.skipped(from, t) {
+		return t
+	}
+
+	// Five years covers every valid combination, including Feb 29.
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = date(t.Year(), t.Month()+1, 1, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = date(t.Year(), t.Month(), t.Day()+1, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			next := date(t.Year(), t.Month(), t.Day(), t.Hour()+1, t.Location())
+			if s.skipped(t, next) {
+				return next
+			}
+			t = next
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			next := t.Add(time.Minute)
+			if s.skipped(t, next) {
+				return next
+			}
+			t = next
+			continue
+		}
+		if !s.hourStar && repeated(t) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// skipped reports whether the clock jumped forward between the consecutive
+// times from and to over a time the schedule fixes.
+func (s Schedule) skipped(from time.Time, to time.Time) bool {
+	if s.hourStar {
+		return false
+	}
+
+	// Had the clock run normally it would read wall(from) plus the time
+	// elapsed; the readings from there up to wall(to) never happened.
+	end := wall(to)
+	for w := wall(from).Add(to.Sub(from)); w.Before(end); w = w.Add(time.Minute) {
+		if s.month&(1<<uint(w.Month())) != 0 && s.dayMatches(w) && s.hour&(1<<uint(w.Hour())) != 0 && s.minute&(1<<uint(w.Minute())) != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// date returns the start of the hour of the given day in loc. Where a
+// daylight saving change skips that hour, it returns the moment the clock
+// resumes rather than the earlier time time.Date may normalize to, which
+// would keep Next from moving on.
+func date(year int, month time.Month, day int, hour int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, loc)
+
+	want := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	if w := wall(t); w.Before(want) {
+		t = t.Add(want.Sub(w))
+	}
+
+	return t
+}
+
+// wall returns the wall clock reading of t as a time in UTC, so readings
+// in a location with daylight saving can be compared and stepped through.
+func wall(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// repeated reports whether the wall clock already read t's time earlier,
+// because a daylight saving change turned it back within the last hours.
+func repeated(t time.Time) bool {
+	_, offset := t.Zone()
+	_, before := t.Add(-3 * time.Hour).Zone()
+
+	shift := time.Duration(before-offset) * time.Second
+	if shift <= 0 {
+		return false
+	}
+
+	earlier := t.Add(-shift)
+	return earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+// dayMatches applies the cron rule that when both day fields are
+// restricted, either one matching is sufficient.
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part[i+1:])
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", bounds[0])
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", bounds[1])
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value out of range %d-%d", min, max)
+		}
+
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
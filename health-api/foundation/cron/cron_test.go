@@ -0,0 +1,141 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"health-api/foundation/cron"
+)
+
+func Test_Parse(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{expr: "* * * * *"},
+		{expr: "0 9 * * 1-5"},
+		{expr: "*/15 0-6/2 1,15 1-12 0,7"},
+		{expr: "@weekly"},
+		{expr: "* * * *", err: "expected 5 fields, got 4"},
+		{expr: "@often", err: "expected 5 fields, got 1"},
+		{expr: "60 * * * *", err: "minute: value out of range 0-59"},
+		{expr: "* 24 * * *", err: "hour: value out of range 0-23"},
+		{expr: "* * 0 * *", err: "day of month: value out of range 1-31"},
+		{expr: "* * * 13 *", err: "month: value out of range 1-12"},
+		{expr: "* * * * 8", err: "day of week: value out of range 0-7"},
+		{expr: "5-1 * * * *", err: "minute: value out of range 0-59"},
+		{expr: "*/0 * * * *", err: `minute: invalid step "0"`},
+		{expr: "a * * * *", err: `minute: invalid value "a"`},
+		{expr: "* 1-x * * *", err: `hour: invalid value "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := cron.Parse(tt.expr)
+
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Should be able to parse: %s", err)
+			case tt.err != "" && (err == nil || err.Error() != tt.err):
+				t.Errorf("Should fail with %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func Test_Next(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatalf("Should be able to parse %s: %s", s, err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{name: "step", expr: "*/15 * * * *", from: "2026-10-16T10:07:30Z", want: "2026-10-16T10:15:00Z"},
+		{name: "strictly after", expr: "*/15 * * * *", from: "2026-10-16T10:15:00Z", want: "2026-10-16T10:30:00Z"},
+		{name: "list", expr: "5,50 * * * *", from: "2026-10-16T10:10:00Z", want: "2026-10-16T10:50:00Z"},
+		{name: "weekdays over a weekend", expr: "0 9 * * 1-5", from: "2026-10-16T09:00:00Z", want: "2026-10-19T09:00:00Z"},
+		{name: "sunday as 7", expr: "0 0 * * 7", from: "2026-10-16T00:00:00Z", want: "2026-10-18T00:00:00Z"},
+		{name: "day of month or week", expr: "0 12 1 * 0", from: "2026-11-02T00:00:00Z", want: "2026-11-08T12:00:00Z"},
+		{name: "day of month or week first", expr: "0 12 1 * 0", from: "2026-11-29T12:00:00Z", want: "2026-12-01T12:00:00Z"},
+		{name: "short months", expr: "0 0 31 * *", from: "2026-04-01T00:00:00Z", want: "2026-05-31T00:00:00Z"},
+		{name: "leap day", expr: "0 0 29 2 *", from: "2026-03-01T00:00:00Z", want: "2028-02-29T00:00:00Z"},
+		{name: "year end", expr: "@yearly", from: "2026-12-31T23:59:00Z", want: "2027-01-01T00:00:00Z"},
+		{name: "last minute of the year", expr: "59 23 31 12 *", from: "2026-12-31T23:59:00Z", want: "2027-12-31T23:59:00Z"},
+		{name: "never", expr: "0 0 30 2 *", from: "2026-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := cron.Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Should be able to parse: %s", err)
+			}
+
+			got := s.Next(at(tt.from))
+
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Errorf("Should never fire, got %s", got)
+				}
+				return
+			}
+
+			if !got.Equal(at(tt.want)) {
+				t.Errorf("Should fire at %s, got %s", tt.want, got.UTC().Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func Test_NextDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Should be able to load the time zone: %s", err)
+	}
+
+	// In 2026 New York skips 02:00-03:00 on March 8 and repeats 01:00-02:00
+	// on November 1. Times are given in UTC, as EST is -5 and EDT -4.
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{name: "skipped time runs when the clock resumes", expr: "30 2 * * *", from: "2026-03-07T17:00:00Z", want: "2026-03-08T07:00:00Z"},
+		{name: "skipped time next day", expr: "30 2 * * *", from: "2026-03-08T07:00:00Z", want: "2026-03-09T06:30:00Z"},
+		{name: "skipped from the last minute before", expr: "30 2 * * *", from: "2026-03-08T06:59:30Z", want: "2026-03-08T07:00:00Z"},
+		{name: "time after the skip", expr: "0 3 * * *", from: "2026-03-08T05:00:00Z", want: "2026-03-08T07:00:00Z"},
+		{name: "every hour over the skip", expr: "*/30 * * * *", from: "2026-03-08T06:30:00Z", want: "2026-03-08T07:00:00Z"},
+		{name: "repeated time runs first", expr: "30 1 * * *", from: "2026-11-01T04:00:00Z", want: "2026-11-01T05:30:00Z"},
+		{name: "repeated time runs once", expr: "30 1 * * *", from: "2026-11-01T05:30:00Z", want: "2026-11-02T06:30:00Z"},
+		{name: "repeated hour start", expr: "0 1 * * *", from: "2026-11-01T05:00:00Z", want: "2026-11-02T06:00:00Z"},
+		{name: "time after the repeat", expr: "0 2 * * *", from: "2026-11-01T05:30:00Z", want: "2026-11-01T07:00:00Z"},
+		{name: "every hour over the repeat", expr: "*/30 * * * *", from: "2026-11-01T05:30:00Z", want: "2026-11-01T06:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := cron.Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Should be able to parse: %s", err)
+			}
+
+			from, _ := time.Parse(time.RFC3339, tt.from)
+			want, _ := time.Parse(time.RFC3339, tt.want)
+
+			if got := s.Next(from.In(ny)); !got.Equal(want) {
+				t.Errorf("Should fire at %s, got %s", want, got.UTC().Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Package heartbeat encodes and verifies the signed heartbeat datagrams
+// sent by constrained devices over UDP. A datagram is
+//
+//	version (1) | status (1) | unix time (8) | id length (1) | id | HMAC-SHA256 (32)
+//
+// with integers in big-endian order. The HMAC covers every byte before it.
+// Status 0 reports the device healthy; any other value is a failure code
+// of the device's choosing.
+package heartbeat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// Version is the datagram format version.
+const Version = 1
+
+// Set of sizes of the datagram fields.
+const (
+	headerSize = 11
+	macSize    = sha256.Size
+)
+
+// Set of errors returned by Decode.
+var (
+	ErrMalformed = errors.New("malformed datagram")
+	ErrSignature = errors.New("invalid signature")
+	ErrStale     = errors.New("stale datagram")
+)
+
+// Beat represents a verified heartbeat.
+type Beat struct {
+	ID     string
+	Status uint8
+	Time   time.Time
+}
+
+// OK reports whether the device reported itself healthy.
+func (b Beat) OK() bool {
+	return b.Status == 0
+}
+
+// Encode returns the datagram for b signed with key. IDs are cut to 255
+// bytes.
+func Encode(b Beat, key []byte) []byte {
+	id := b.ID
+	if len(id) > 255 {
+		id = id[:255]
+	}
+
+	data := make([]byte, headerSize, headerSize+len(id)+macSize)
+	data[0] = Version
+	data[1] = b.Status
+	binary.BigEndian.PutUint64(data[2:10], uint64(b.Time.Unix()))
+	data[10] = byte(len(id))
+	data = append(data, id...)
+
+	return append(data, sign(data, key)...)
+}
+
+// Decode verifies data with key and returns its heartbeat. Datagrams whose
+// time is more than maxSkew away from now are rejected as stale, so a
+// captured datagram can't be replayed later to keep a dead device up.
+func Decode(data []byte, key []byte, now time.Time, maxSkew time.Duration) (Beat, error) {
+	if len(data) < headerSize+macSize || data[0] != Version {
+		return Beat{}, ErrMalformed
+	}
+
+	n := int(data[10])
+	if n == 0 || len(data) != headerSize+n+macSize {
+		return Beat{}, ErrMalformed
+	}
+
+	body, mac := data[:headerSize+n], data[headerSize+n:]
+	if !hmac.Equal(mac, sign(body, key)) {
+		return Beat{}, ErrSignature
+	}
+
+	b := Beat{
+		ID:     string(data[headerSize : headerSize+n]),
+		Status: data[1],
+		Time:   time.Unix(int64(binary.BigEndian.Uint64(data[2:10])), 0).UTC(),
+	}
+
+	if skew := now.Sub(b.Time); skew > maxSkew || skew < -maxSkew {
+		return Beat{}, ErrStale
+	}
+
+	return b, nil
+}
+
+func sign(data []byte, key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,150 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Cache runs a set of informers and tells queries when their listers can
+// be trusted. Until every informer has synced, queries wait for it, or fail
+// at once when listing failed, so an unreachable API server or a rejected
+// token isn't mistaken for an empty cluster.
+type Cache struct {
+	informers []cache.SharedIndexInformer
+	synced    chan struct{}
+
+	mu     sync.Mutex
+	err    error
+	failed chan struct{}
+}
+
+// NewCache constructs an empty cache. Informers are added with Add before
+// Run is called.
+func NewCache() *Cache {
+	return &Cache{
+		synced: make(chan struct{}),
+		failed: make(chan struct{}),
+	}
+}
+
+// Factories returns a shared informer factory per namespace, or a single
+// cluster wide one when none are given, configured further by the options.
+// The factories aren't started; their informers are added to a Cache
+// instead.
+func (c *Client) Factories(namespaces []string, options ...informers.SharedInformerOption) []informers.SharedInformerFactory {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	factories := make([]informers.SharedInformerFactory, len(namespaces))
+	for i, ns := range namespaces {
+		opts := append([]informers.SharedInformerOption{informers.WithNamespace(ns)}, options...)
+		factories[i] = informers.NewSharedInformerFactoryWithOptions(c.clientset, 0, opts...)
+	}
+
+	return factories
+}
+
+// DynamicFactories is Factories for the dynamic client, e.g. for informers
+// on custom resources.
+func (c *Client) DynamicFactories(namespaces []string) []dynamicinformer.DynamicSharedInformerFactory {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	factories := make([]dynamicinformer.DynamicSharedInformerFactory, len(namespaces))
+	for i, ns := range namespaces {
+		factories[i] = dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamic, 0, ns, nil)
+	}
+
+	return factories
+}
+
+// MetadataFactories is Factories for the metadata client, for informers
+// caching only the metadata of objects, listed as tweak says.
+func (c *Client) MetadataFactories(namespaces []string, tweak metadatainformer.TweakListOptionsFunc) []metadatainformer.SharedInformerFactory {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	factories := make([]metadatainformer.SharedInformerFactory, len(namespaces))
+	for i, ns := range namespaces {
+		factories[i] = metadatainformer.NewFilteredSharedInformerFactory(c.metadata, 0, ns, tweak)
+	}
+
+	return factories
+}
+
+// Add registers an informer with the cache. It must be called before Run.
+func (c *Cache) Add(informer cache.SharedIndexInformer) {
+	informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		c.fail(err)
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+	})
+
+	c.informers = append(c.informers, informer)
+}
+
+// Run runs the informers until ctx is canceled.
+func (c *Cache) Run(ctx context.Context) {
+	hasSynced := make([]cache.InformerSynced, len(c.informers))
+	for i, informer := range c.informers {
+		go informer.RunWithContext(ctx)
+		hasSynced[i] = informer.HasSynced
+	}
+
+	if cache.WaitForCacheSync(ctx.Done(), hasSynced...) {
+		close(c.synced)
+	}
+
+	<-ctx.Done()
+}
+
+// Wait returns once every informer has synced. It fails when ctx ends
+// first, or when listing failed and the informers haven't synced since.
+func (c *Cache) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-c.synced:
+			return nil
+		default:
+		}
+
+		c.mu.Lock()
+		err, failed := c.err, c.failed
+		c.mu.Unlock()
+
+		if err != nil {
+			return fmt.Errorf("cache not synced: %w", err)
+		}
+
+		select {
+		case <-c.synced:
+			return nil
+		case <-failed:
+		case <-ctx.Done():
+			return fmt.Errorf("cache not synced: %w", ctx.Err())
+		}
+	}
+}
+
+// fail records the error of a failed list or watch and wakes the queries
+// waiting for the informers to sync.
+func (c *Cache) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = err
+	close(c.failed)
+	c.failed = make(chan struct{})
+}
@@ -0,0 +1,5 @@
+package kube
+
+// FromServiceAccount exposes fromServiceAccount so tests can point the
+// client at a fake service account directory.
+var FromServiceAccount = fromServiceAccount
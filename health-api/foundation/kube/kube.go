@@ -0,0 +1,272 @@
+// Package kube provides a Kubernetes API client built on client-go,
+// configured from the service account mounted into every pod. Typed
+// clients and informers come from Clientset, those for custom resources
+// from Dynamic and those reading object metadata only from Metadata; Get,
+// GetMetadata, Patch and Create reach any other path.
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+)
+
+// ServiceAccountDir is where Kubernetes mounts the service account token,
+// CA bundle and namespace.
+const ServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// metadataListAccept asks for a list as PartialObjectMetadataList, falling
+// back to the full list on API servers that don't support it.
+const metadataListAccept = "application/json;as=PartialObjectMetadataList;g=meta.k8s.io;v=v1,application/json"
+
+// requestTimeout bounds Get, GetMetadata, Patch and Create.
+const requestTimeout = 30 * time.Second
+
+// ErrNotInCluster is returned when the process does not run in a pod.
+var ErrNotInCluster = errors.New("not running in a kubernetes cluster")
+
+// ErrNotFound is returned when the API answers 404.
+var ErrNotFound = errors.New("kubernetes object not found")
+
+// Client reads and writes objects of the Kubernetes API.
+type Client struct {
+	namespace string
+	clientset kubernetes.Interface
+	dynamic   dynamic.Interface
+	metadata  metadata.Interface
+	rest      rest.Interface
+}
+
+// InCluster constructs a client from the pod's service account. The token
+// is read for every request, so projected tokens are picked up when the
+// kubelet rotates them.
+func InCluster() (*Client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, ErrNotInCluster
+	}
+
+	return fromServiceAccount("https://"+net.JoinHostPort(host, port), ServiceAccountDir)
+}
+
+// New constructs a client for the API server at host, e.g. one reached
+// through kubectl proxy. Requests carry the bearer token read from
+// tokenFile unless it is empty.
+func New(host string, tokenFile string, transport http.RoundTripper) *Client {
+	c, err := newClient(&rest.Config{
+		Host:      host,
+		Transport: transport,
+	}, tokenFile)
+	if err != nil {
+		// Without TLS settings the config can't be rejected.
+		panic(err)
+	}
+
+	return c
+}
+
+// Clientset returns the typed client-go clients, e.g. for informers.
+func (c *Client) Clientset() kubernetes.Interface {
+	return c.clientset
+}
+
+// Dynamic returns the client-go client for resources without typed
+// clients, such as custom resources.
+func (c *Client) Dynamic() dynamic.Interface {
+	return c.dynamic
+}
+
+// Metadata returns the client-go client for object metadata, e.g. for
+// informers on objects whose contents aren't needed.
+func (c *Client) Metadata() metadata.Interface {
+	return c.metadata
+}
+
+// Namespace returns the namespace the pod runs in, or "" for clients not
+// constructed by InCluster.
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
+// Get decodes the object or list at path, e.g. "/api/v1/namespaces/x/pods",
+// into out.
+func (c *Client) Get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, c.rest.Get().AbsPath(path), http.MethodGet, path, query, out)
+}
+
+// GetMetadata is Get for lists of which only the object metadata is
+// needed. The API server leaves out spec, status and data, which keeps
+// lists of large objects such as Secrets small.
+func (c *Client) GetMetadata(ctx context.Context, path string, query url.Values, out any) error {
+	req := c.rest.Get().AbsPath(path).SetHeader("Accept", metadataListAccept)
+	return c.do(ctx, req, http.MethodGet, path, query, out)
+}
+
+// Patch applies a strategic merge patch to the object at path and decodes
+// the patched object into out unless it is nil.
+func (c *Client) Patch(ctx context.Context, path string, patch any, out any) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("patch %s: encoding: %w", path, err)
+	}
+
+	req := c.rest.Patch(types.StrategicMergePatchType).AbsPath(path).Body(data)
+	return c.do(ctx, req, http.MethodPatch, path, nil, out)
+}
+
+// Create posts obj to the collection at path and decodes the created
+// object into out unless it is nil.
+func (c *Client) Create(ctx context.Context, path string, obj any, out any) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("post %s: encoding: %w", path, err)
+	}
+
+	req := c.rest.Post().AbsPath(path).SetHeader("Content-Type", "application/json").Body(data)
+	return c.do(ctx, req, http.MethodPost, path, nil, out)
+}
+
+// Check verifies that the API server is reachable and accepts the token.
+func (c *Client) Check(ctx context.Context) error {
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+	}
+	return c.Get(ctx, "/version", nil, &version)
+}
+
+// =============================================================================
+
+// fromServiceAccount constructs a client for the API server at host from
+// the token, CA bundle and namespace in dir.
+func fromServiceAccount(host string, dir string) (*Client, error) {
+	caFile := filepath.Join(dir, "ca.crt")
+
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading ca: %w", err)
+	}
+
+	ns, err := os.ReadFile(filepath.Join(dir, "namespace"))
+	if err != nil {
+		return nil, fmt.Errorf("reading namespace: %w", err)
+	}
+
+	c, err := newClient(&rest.Config{
+		Host:            host,
+		TLSClientConfig: rest.TLSClientConfig{CAData: ca},
+	}, filepath.Join(dir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", caFile, err)
+	}
+	c.namespace = strings.TrimSpace(string(ns))
+
+	return c, nil
+}
+
+// newClient completes cfg and constructs the clients from it. The token is
+// added by a wrapping transport rather than BearerTokenFile, which client-go
+// only rereads once a minute. cfg.Timeout stays unset: it bounds whole
+// responses and would cut the informers' watches off, so requestTimeout
+// bounds the requests made through do instead.
+func newClient(cfg *rest.Config, tokenFile string) (*Client, error) {
+	// The stores list every configured namespace at once; client-go's
+	// default of 5 requests per second would queue them.
+	cfg.QPS, cfg.Burst = 50, 100
+	if tokenFile != "" {
+		cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			return &tokenTransport{file: tokenFile, next: rt}
+		}
+	}
+
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("constructing clientset: %w", err)
+	}
+
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("constructing dynamic client: %w", err)
+	}
+
+	meta, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("constructing metadata client: %w", err)
+	}
+
+	c := Client{
+		clientset: clientset,
+		dynamic:   dyn,
+		metadata:  meta,
+		rest:      clientset.Discovery().RESTClient(),
+	}
+
+	return &c, nil
+}
+
+func (c *Client) do(ctx context.Context, req *rest.Request, method string, path string, query url.Values, out any) error {
+	for k, vs := range query {
+		for _, v := range vs {
+			req.Param(k, v)
+		}
+	}
+
+	verb := strings.ToLower(method)
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	var status int
+	data, err := req.Do(ctx).StatusCode(&status).Raw()
+	switch {
+	case apierrors.IsNotFound(err) || status == http.StatusNotFound:
+		return fmt.Errorf("%s %s: %w", verb, path, ErrNotFound)
+	case err != nil && status != 0:
+		return fmt.Errorf("%s %s: kubernetes returned status %d: %w", verb, path, status, err)
+	case err != nil:
+		return fmt.Errorf("%s %s: %w", verb, path, err)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: decoding: %w", verb, path, err)
+	}
+
+	return nil
+}
+
+// tokenTransport sets the bearer token read from file on every request.
+type tokenTransport struct {
+	file string
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := os.ReadFile(t.file)
+	if err != nil {
+		return nil, fmt.Errorf("reading token: %w", err)
+	}
+
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+
+	return t.next.RoundTrip(r)
+}
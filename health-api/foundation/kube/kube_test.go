@@ -0,0 +1,198 @@
+package kube_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"health-api/foundation/kube"
+)
+
+func Test_ServiceAccount(t *testing.T) {
+	var gotAuth []string
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"gitVersion":"v1.31.0"}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "ca.crt", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})))
+	writeFile(t, dir, "namespace", "monitoring\n")
+	writeFile(t, dir, "token", "first\n")
+
+	c, err := kube.FromServiceAccount(srv.URL, dir)
+	if err != nil {
+		t.Fatalf("Should be able to construct the client: %s", err)
+	}
+
+	if ns := c.Namespace(); ns != "monitoring" {
+		t.Errorf("Should read the pod's namespace, got %q", ns)
+	}
+
+	if err := c.Check(context.Background()); err != nil {
+		t.Fatalf("Should trust the API server signed by the service account CA: %s", err)
+	}
+
+	// The kubelet rotates projected tokens in place.
+	writeFile(t, dir, "token", "second\n")
+
+	if err := c.Check(context.Background()); err != nil {
+		t.Fatalf("Should keep working after the token rotated: %s", err)
+	}
+
+	want := []string{"Bearer first", "Bearer second"}
+	if len(gotAuth) != len(want) || gotAuth[0] != want[0] || gotAuth[1] != want[1] {
+		t.Errorf("Should send the current token with every request, got %q", gotAuth)
+	}
+}
+
+func Test_ServiceAccountCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	t.Run("unknown ca", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "ca.crt", selfSigned(t))
+		writeFile(t, dir, "namespace", "monitoring")
+		writeFile(t, dir, "token", "token")
+
+		c, err := kube.FromServiceAccount(srv.URL, dir)
+		if err != nil {
+			t.Fatalf("Should be able to construct the client: %s", err)
+		}
+
+		if err := c.Check(context.Background()); err == nil {
+			t.Error("Should not trust an API server signed by another CA")
+		}
+	})
+
+	t.Run("no certificates", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "ca.crt", "not a certificate")
+		writeFile(t, dir, "namespace", "monitoring")
+
+		if _, err := kube.FromServiceAccount(srv.URL, dir); err == nil {
+			t.Error("Should reject a CA bundle without certificates")
+		}
+	})
+
+	t.Run("missing ca", func(t *testing.T) {
+		if _, err := kube.FromServiceAccount(srv.URL, t.TempDir()); err == nil {
+			t.Error("Should fail without a CA bundle")
+		}
+	})
+}
+
+func Test_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/shop/pods/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind":"Status","reason":"NotFound"}`))
+		case "/api/v1/secrets":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind":"Status","reason":"Forbidden","message":"secrets is forbidden"}`))
+		case "/api/v1/pods":
+			if r.Header.Get("Accept") == "application/json" {
+				t.Error("Should ask for the metadata only list")
+			}
+			w.Write([]byte(`{"items":[{"metadata":{"name":"web"}}]}`))
+		case "/api/v1/nodes":
+			w.Write([]byte(`{"items":`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := kube.New(srv.URL, "", http.DefaultTransport)
+	ctx := context.Background()
+
+	var out map[string]any
+
+	if err := c.Get(ctx, "/api/v1/namespaces/shop/pods/missing", nil, &out); !errors.Is(err, kube.ErrNotFound) {
+		t.Errorf("Should report 404 as ErrNotFound, got %v", err)
+	}
+
+	err := c.Get(ctx, "/api/v1/secrets", nil, &out)
+	if err == nil || errors.Is(err, kube.ErrNotFound) {
+		t.Errorf("Should report 403 as an error other than not found, got %v", err)
+	}
+	if err != nil && (!strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "secrets is forbidden")) {
+		t.Errorf("Should include the status and the API's message, got %s", err)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Name string `json:"name"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := c.GetMetadata(ctx, "/api/v1/pods", nil, &list); err != nil || len(list.Items) != 1 {
+		t.Errorf("Should decode the metadata list, got %v %v", list, err)
+	}
+
+	if err := c.Get(ctx, "/api/v1/nodes", nil, &out); err == nil {
+		t.Error("Should report a truncated body")
+	}
+
+	if err := kube.New(srv.URL, filepath.Join(t.TempDir(), "token"), http.DefaultTransport).Check(ctx); err == nil {
+		t.Error("Should fail when the token can't be read")
+	}
+}
+
+// =============================================================================
+
+// selfSigned returns a PEM certificate unrelated to the one httptest
+// servers use.
+func selfSigned(t *testing.T) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Should be able to generate a key: %s", err)
+	}
+
+	tmpl := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Should be able to create a certificate: %s", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func writeFile(t *testing.T, dir string, name string, data string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+		t.Fatalf("Should be able to write %s: %s", name, err)
+	}
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ANSI colors used for console levels.
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorCyan   = "\033[36m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+)
+
+// NewConsole constructs a Logger writing human-readable lines with colored
+// levels, meant for local development:
+//
+//	15:04:05.000 INF startup status="api router started" host=:8080
+func NewConsole(w io.Writer, minLevel Level, serviceName string, traceIDFn func(context.Context) string) *Logger {
+	level := new(slog.LevelVar)
+	level.Set(slog.Level(minLevel))
+
+	log := NewWithHandler(&consoleHandler{
+		w:     w,
+		mu:    new(sync.Mutex),
+		level: level,
+	}, serviceName, traceIDFn)
+	log.level = level
+
+	return log
+}
+
+// consoleHandler is a slog.Handler writing one colored line per record.
+type consoleHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string
+}
+
+// Enabled implements slog.Handler.
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler.
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "%s%s%s %s %s", colorGray, r.Time.Local().Format("15:04:05.000"), colorReset, levelTag(r.Level), r.Message)
+
+	// The service name is the same on every line, so it is left out.
+	for _, a := range h.attrs {
+		if a.Key != "service" {
+			writeAttr(&buf, "", a)
+		}
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&buf, h.prefix, a)
+		return true
+	})
+
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+func levelTag(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed + "ERR" + colorReset
+	case level >= slog.LevelWarn:
+		return colorYellow + "WRN" + colorReset
+	case level >= slog.LevelInfo:
+		return colorCyan + "INF" + colorReset
+	default:
+		return colorGray + "DBG" + colorReset
+	}
+}
+
+func writeAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			writeAttr(buf, prefix+a.Key+".", ga)
+		}
+		return
+	}
+
+	var s string
+	switch a.Value.Kind() {
+	case slog.KindTime:
+		s = a.Value.Time().Format(time.RFC3339)
+	default:
+		s = a.Value.String()
+	}
+
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		s = fmt.Sprintf("%q", s)
+	}
+
+	fmt.Fprintf(buf, " %s%s%s=%s", colorGray, prefix+a.Key, colorReset, s)
+}
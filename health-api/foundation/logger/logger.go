@@ -8,7 +8,10 @@ import (
 	"log/slog"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Level represents the level of logging.
@@ -22,16 +25,36 @@ const (
 	LevelError = Level(slog.LevelError)
 )
 
-// Logger represents a logger for logging information.
+// String returns the lower case name of the level.
+func (l Level) String() string {
+	return strings.ToLower(slog.Level(l).String())
+}
+
+// ParseLevel parses a level name such as "debug" or "WARN".
+func ParseLevel(s string) (Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("parse level: %w", err)
+	}
+	return Level(l), nil
+}
+
+// Logger represents a logger for logging information. The minimum level
+// can be changed at runtime with SetLevel.
 type Logger struct {
 	handler   slog.Handler
+	level     *slog.LevelVar
+	sampler   *sampler
 	traceIDFn func(context.Context) string
 }
 
 // New constructs a new Logger.
 func New(w io.Writer, minLevel Level, serviceName string, traceIDFn func(context.Context) string) *Logger {
-	return NewWithHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{
-		Level: slog.Level(minLevel),
+	level := new(slog.LevelVar)
+	level.Set(slog.Level(minLevel))
+
+	log := NewWithHandler(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key == slog.TimeKey {
 				return slog.Attr{
@@ -54,6 +77,9 @@ func New(w io.Writer, minLevel Level, serviceName string, traceIDFn func(context
 			return a
 		},
 	}), serviceName, traceIDFn)
+	log.level = level
+
+	return log
 }
 
 // NewWithHandler constructs a new Logger with a custom handler.
@@ -63,12 +89,37 @@ func NewWithHandler(handler slog.Handler, serviceName string, traceIDFn func(con
 		{Key: "service", Value: slog.StringValue(serviceName)},
 	})
 
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+
 	return &Logger{
 		handler:   handler,
+		level:     level,
 		traceIDFn: traceIDFn,
 	}
 }
 
+// Level returns the current minimum level.
+func (log *Logger) Level() Level {
+	return Level(log.level.Level())
+}
+
+// SetSampling enables sampling of repeated identical entries. It must be
+// called before the logger is shared between goroutines.
+func (log *Logger) SetSampling(cfg Sampling) {
+	if cfg.First <= 0 || cfg.Window <= 0 {
+		log.sampler = nil
+		return
+	}
+	log.sampler = newSampler(cfg)
+}
+
+// SetLevel changes the minimum level of the logger and every logger
+// sharing it, taking effect immediately.
+func (log *Logger) SetLevel(l Level) {
+	log.level.Set(slog.Level(l))
+}
+
 // Debug logs at LevelDebug.
 func (log *Logger) Debug(ctx context.Context, msg string, args ...any) {
 	log.write(ctx, slog.LevelDebug, 3, msg, args...)
@@ -90,10 +141,27 @@ func (log *Logger) Error(ctx context.Context, msg string, args ...any) {
 }
 
 func (log *Logger) write(ctx context.Context, level slog.Level, caller int, msg string, args ...any) {
+	if level < log.level.Level() || !log.handler.Enabled(ctx, level) {
+		return
+	}
+
+	if log.sampler != nil {
+		ok, suppressed := log.sampler.allow(sampleKey(Level(level), msg, args), time.Now())
+		if !ok {
+			return
+		}
+		if suppressed > 0 {
+			args = append(args, "suppressed", suppressed)
+		}
+	}
+
 	slogRec := slog.NewRecord(time.Now().UTC(), level, msg, uintptr(caller))
 
-	// Add trace ID if available
-	if log.traceIDFn != nil {
+	// Correlate with the span recorded by this process when there is one,
+	// otherwise fall back to the trace ID provided by the caller.
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() && !sc.IsRemote() {
+		args = append(args, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	} else if log.traceIDFn != nil {
 		if traceID := log.traceIDFn(ctx); traceID != "" {
 			args = append(args, "trace_id", traceID)
 		}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Sampling limits repeated identical entries, such as the same error logged
+// for every request while a backend is down. Within each Window the First
+// occurrences of an entry are logged, then every Thereafter-th one. Logged
+// entries carry a "suppressed" count of the identical entries dropped since
+// the previous one. A zero First disables sampling.
+type Sampling struct {
+	First      int
+	Thereafter int
+	Window     time.Duration
+}
+
+// maxSampleKeys bounds the number of distinct entries tracked before
+// expired ones are pruned.
+const maxSampleKeys = 1000
+
+type sampleEntry struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+type sampler struct {
+	cfg Sampling
+
+	mu      sync.Mutex
+	entries map[string]*sampleEntry
+}
+
+func newSampler(cfg Sampling) *sampler {
+	return &sampler{
+		cfg:     cfg,
+		entries: make(map[string]*sampleEntry),
+	}
+}
+
+// allow reports whether the entry identified by key should be logged and,
+// if so, how many identical entries were suppressed since the last one.
+func (s *sampler) allow(key string, now time.Time) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || now.Sub(e.start) >= s.cfg.Window {
+		if !ok {
+			s.prune(now)
+			e = &sampleEntry{}
+			s.entries[key] = e
+		}
+
+		suppressed := e.suppressed
+		*e = sampleEntry{start: now, count: 1}
+
+		return true, suppressed
+	}
+
+	e.count++
+
+	over := e.count - s.cfg.First
+	if over <= 0 || (s.cfg.Thereafter > 0 && over%s.cfg.Thereafter == 0) {
+		suppressed := e.suppressed
+		e.suppressed = 0
+		return true, suppressed
+	}
+
+	e.suppressed++
+
+	return false, 0
+}
+
+// prune drops expired entries once too many are tracked. Their suppressed
+// counts are lost, which only happens with many distinct noisy entries.
+func (s *sampler) prune(now time.Time) {
+	if len(s.entries) < maxSampleKeys {
+		return
+	}
+
+	for key, e := range s.entries {
+		if now.Sub(e.start) >= s.cfg.Window {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// sampleKey identifies identical entries by level, message and attributes.
+func sampleKey(level Level, msg string, args []any) string {
+	return fmt.Sprint(level, msg, args)
+}
@@ -0,0 +1,376 @@
+// Package mqtt provides a minimal MQTT 3.1.1 subscriber. It connects,
+// subscribes at QoS 0 and delivers published messages until the context is
+// canceled or the connection fails; it does not publish or persist
+// sessions.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Set of MQTT control packet types, shifted into the fixed header.
+const (
+	typeConnect    = 0x10
+	typeConnAck    = 0x20
+	typePublish    = 0x30
+	typePubAck     = 0x40
+	typeSubscribe  = 0x82
+	typeSubAck     = 0x90
+	typePingReq    = 0xc0
+	typeDisconnect = 0xe0
+)
+
+// maxPacket bounds the size of packets read from the broker.
+const maxPacket = 1 << 20
+
+// Config holds the broker connection settings. URL uses the mqtt or mqtts
+// scheme, e.g. mqtts://broker:8883.
+type Config struct {
+	URL       string
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+}
+
+// Message is a message published on a subscribed topic.
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Subscribe connects to the broker, subscribes to the topic filters and
+// calls handle for every message received, one at a time. It returns when
+// ctx is canceled, with nil, or when the connection fails.
+func Subscribe(ctx context.Context, cfg Config, filters []string, handle func(Message)) error {
+	conn, err := dial(ctx, cfg.URL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.Write([]byte{typeDisconnect, 0})
+		conn.Close()
+	})
+	defer stop()
+
+	c := client{conn: conn, r: bufio.NewReader(conn)}
+
+	keepAlive := cfg.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = time.Minute
+	}
+
+	if err := c.connect(cfg, keepAlive); err != nil {
+		return closed(ctx, err)
+	}
+
+	if err := c.subscribe(filters); err != nil {
+		return closed(ctx, err)
+	}
+
+	go c.ping(ctx, keepAlive/2)
+
+	for {
+		typ, body, err := c.read()
+		if err != nil {
+			return closed(ctx, err)
+		}
+
+		// Anything but messages, such as PINGRESP, needs no action.
+		if typ&0xf0 != typePublish {
+			continue
+		}
+
+		msg, id, err := decodePublish(typ, body)
+		if err != nil {
+			return err
+		}
+		if id != 0 {
+			if err := c.write(typePubAck, binary.BigEndian.AppendUint16(nil, id)); err != nil {
+				return closed(ctx, err)
+			}
+		}
+
+		handle(msg)
+	}
+}
+
+// Match reports whether topic matches the filter, which may hold the +
+// (one level) and # (remaining levels) wildcards. It returns the levels the
+// wildcards matched, in order; # yields its levels joined by /.
+func Match(filter string, topic string) ([]string, bool) {
+	fl := strings.Split(filter, "/")
+	tl := strings.Split(topic, "/")
+
+	var captured []string
+	for i, f := range fl {
+		switch {
+		case f == "#":
+			return append(captured, strings.Join(tl[i:], "/")), true
+		case i >= len(tl):
+			return nil, false
+		case f == "+":
+			captured = append(captured, tl[i])
+		case f != tl[i]:
+			return nil, false
+		}
+	}
+
+	if len(fl) != len(tl) {
+		return nil, false
+	}
+
+	return captured, true
+}
+
+// =============================================================================
+
+type client struct {
+	conn net.Conn
+	r    *bufio.Reader
+	mu   sync.Mutex
+}
+
+func dial(ctx context.Context, rawURL string) (net.Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing url: %w", err)
+	}
+
+	var d net.Dialer
+
+	switch u.Scheme {
+	case "mqtt", "tcp":
+		addr := u.Host
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), "1883")
+		}
+		return d.DialContext(ctx, "tcp", addr)
+
+	case "mqtts", "ssl", "tls":
+		addr := u.Host
+		if u.Port() == "" {
+			addr = net.JoinHostPort(u.Hostname(), "8883")
+		}
+		td := tls.Dialer{NetDialer: &d, Config: &tls.Config{ServerName: u.Hostname()}}
+		return td.DialContext(ctx, "tcp", addr)
+	}
+
+	return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+}
+
+func (c *client) connect(cfg Config, keepAlive time.Duration) error {
+	flags := byte(0x02) // clean session
+	if cfg.Username != "" {
+		flags |= 0x80
+	}
+	if cfg.Password != "" {
+		flags |= 0x40
+	}
+
+	b := appendString(nil, "MQTT")
+	b = append(b, 4, flags)
+	b = binary.BigEndian.AppendUint16(b, uint16(min(keepAlive/time.Second, 0xffff)))
+	b = appendString(b, cfg.ClientID)
+	if cfg.Username != "" {
+		b = appendString(b, cfg.Username)
+	}
+	if cfg.Password != "" {
+		b = appendString(b, cfg.Password)
+	}
+
+	if err := c.write(typeConnect, b); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	typ, body, err := c.read()
+	if err != nil {
+		return fmt.Errorf("connack: %w", err)
+	}
+	if typ != typeConnAck || len(body) != 2 {
+		return fmt.Errorf("connack: unexpected packet 0x%02x", typ)
+	}
+	if body[1] != 0 {
+		return fmt.Errorf("connect refused: %s", refusal(body[1]))
+	}
+
+	return nil
+}
+
+func (c *client) subscribe(filters []string) error {
+	const packetID = 1
+
+	b := binary.BigEndian.AppendUint16(nil, packetID)
+	for _, f := range filters {
+		b = appendString(b, f)
+		b = append(b, 0)
+	}
+
+	if err := c.write(typeSubscribe, b); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+
+	// Messages may arrive before the SUBACK only on a resumed session,
+	// which clean sessions rule out.
+	typ, body, err := c.read()
+	if err != nil {
+		return fmt.Errorf("suback: %w", err)
+	}
+	if typ != typeSubAck || len(body) != 2+len(filters) {
+		return fmt.Errorf("suback: unexpected packet 0x%02x", typ)
+	}
+
+	for i, code := range body[2:] {
+		if code == 0x80 {
+			return fmt.Errorf("subscribe: filter %q refused", filters[i])
+		}
+	}
+
+	return nil
+}
+
+// ping keeps the connection alive while no messages flow.
+func (c *client) ping(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.write(typePingReq, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *client) write(typ byte, body []byte) error {
+	b := []byte{typ}
+	b = appendLength(b, len(body))
+	b = append(b, body...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.conn.Write(b)
+	return err
+}
+
+func (c *client) read() (byte, []byte, error) {
+	typ, err := c.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var n, shift int
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+	}
+
+	if n > maxPacket {
+		return 0, nil, fmt.Errorf("packet of %d bytes exceeds the limit", n)
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+
+	return typ, body, nil
+}
+
+// decodePublish returns the message of a PUBLISH packet and its packet ID,
+// which is zero for QoS 0.
+func decodePublish(typ byte, body []byte) (Message, uint16, error) {
+	if len(body) < 2 {
+		return Message{}, 0, errors.New("malformed publish")
+	}
+
+	n := int(binary.BigEndian.Uint16(body))
+	if len(body) < 2+n {
+		return Message{}, 0, errors.New("malformed publish topic")
+	}
+	msg := Message{Topic: string(body[2 : 2+n])}
+	rest := body[2+n:]
+
+	var id uint16
+	if qos := (typ >> 1) & 0x03; qos > 0 {
+		if len(rest) < 2 {
+			return Message{}, 0, errors.New("malformed publish packet id")
+		}
+		id = binary.BigEndian.Uint16(rest)
+		rest = rest[2:]
+	}
+
+	msg.Payload = rest
+
+	return msg, id, nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func appendLength(b []byte, n int) []byte {
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func refusal(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("code %d", code)
+}
+
+// closed hides the error of a connection closed because ctx was canceled.
+func closed(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
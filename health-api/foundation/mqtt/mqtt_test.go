@@ -0,0 +1,283 @@
+package mqtt_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"health-api/foundation/mqtt"
+)
+
+// broker accepts a single connection and hands it to serve.
+func broker(t *testing.T, serve func(conn net.Conn, r *bufio.Reader)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Should be able to listen: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		serve(conn, bufio.NewReader(conn))
+	}()
+
+	return "mqtt://" + ln.Addr().String()
+}
+
+// readPacket reads a control packet, decoding the variable-length
+// remaining length.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var n, shift int
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		shift += 7
+	}
+
+	body := make([]byte, n)
+	_, err = io.ReadFull(r, body)
+	return typ, body, err
+}
+
+// packet encodes a control packet.
+func packet(typ byte, body []byte) []byte {
+	b := []byte{typ}
+	for n := len(body); ; {
+		d := byte(n % 128)
+		if n /= 128; n > 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+		if n == 0 {
+			break
+		}
+	}
+	return append(b, body...)
+}
+
+// publish encodes a PUBLISH packet; a non-zero id sends it at QoS 1.
+func publish(topic string, id uint16, payload []byte) []byte {
+	typ := byte(0x30)
+	body := binary.BigEndian.AppendUint16(nil, uint16(len(topic)))
+	body = append(body, topic...)
+	if id != 0 {
+		typ |= 0x02
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	return packet(typ, append(body, payload...))
+}
+
+// handshake accepts the CONNECT and the SUBSCRIBE, granting every filter.
+func handshake(t *testing.T, conn net.Conn, r *bufio.Reader) (connect []byte, filters []string) {
+	typ, connect, err := readPacket(r)
+	if err != nil || typ != 0x10 {
+		t.Errorf("Should receive CONNECT, got 0x%02x %v", typ, err)
+		return nil, nil
+	}
+	conn.Write(packet(0x20, []byte{0, 0}))
+
+	typ, body, err := readPacket(r)
+	if err != nil || typ != 0x82 {
+		t.Errorf("Should receive SUBSCRIBE, got 0x%02x %v", typ, err)
+		return nil, nil
+	}
+
+	ack := slices.Clone(body[:2])
+	for rest := body[2:]; len(rest) > 2; {
+		n := int(binary.BigEndian.Uint16(rest))
+		filters = append(filters, string(rest[2:2+n]))
+		rest = rest[2+n+1:]
+		ack = append(ack, 0)
+	}
+	conn.Write(packet(0x90, ack))
+
+	return connect, filters
+}
+
+func Test_Subscribe(t *testing.T) {
+	large := bytes.Repeat([]byte("x"), 300)
+
+	type result struct {
+		connect []byte
+		filters []string
+		puback  []byte
+	}
+	done := make(chan result, 1)
+
+	url := broker(t, func(conn net.Conn, r *bufio.Reader) {
+		var res result
+		res.connect, res.filters = handshake(t, conn, r)
+
+		conn.Write(publish("plant/1/pump/health", 0, []byte("ok")))
+		conn.Write(publish("plant/2/pump/health", 7, large))
+
+		for {
+			typ, body, err := readPacket(r)
+			if err != nil || typ == 0xe0 {
+				break
+			}
+			if typ == 0x40 {
+				res.puback = body
+			}
+		}
+
+		done <- res
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var msgs []mqtt.Message
+	cfg := mqtt.Config{URL: url, ClientID: "health-api", Username: "probe", Password: "secret", KeepAlive: 30 * time.Second}
+
+	err := mqtt.Subscribe(ctx, cfg, []string{"plant/+/+/health", "site/#"}, func(msg mqtt.Message) {
+		msgs = append(msgs, msg)
+		if len(msgs) == 2 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatalf("Should return nil once canceled, got %s", err)
+	}
+
+	if len(msgs) != 2 || msgs[0].Topic != "plant/1/pump/health" || string(msgs[0].Payload) != "ok" {
+		t.Fatalf("Should deliver the messages in order, got %+v", msgs)
+	}
+	if msgs[1].Topic != "plant/2/pump/health" || !bytes.Equal(msgs[1].Payload, large) {
+		t.Errorf("Should deliver a QoS 1 message with a multi-byte length, got %s with %d bytes", msgs[1].Topic, len(msgs[1].Payload))
+	}
+
+	select {
+	case res := <-done:
+		if !slices.Equal(res.filters, []string{"plant/+/+/health", "site/#"}) {
+			t.Errorf("Should subscribe to the filters, got %v", res.filters)
+		}
+		if !bytes.Equal(res.puback, []byte{0, 7}) {
+			t.Errorf("Should acknowledge the QoS 1 message, got %v", res.puback)
+		}
+		// Protocol name, level 4, then flags: user name, password and clean
+		// session.
+		if len(res.connect) < 8 || res.connect[6] != 4 || res.connect[7] != 0xc2 {
+			t.Errorf("Should connect as MQTT 3.1.1 with credentials, got %v", res.connect)
+		}
+		if !bytes.Contains(res.connect, []byte("probe")) || !bytes.Contains(res.connect, []byte("secret")) {
+			t.Errorf("Should send the credentials, got %q", res.connect)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Should disconnect from the broker")
+	}
+}
+
+func Test_SubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		serve func(conn net.Conn, r *bufio.Reader)
+		want  string
+	}{
+		{
+			name: "refused",
+			serve: func(conn net.Conn, r *bufio.Reader) {
+				readPacket(r)
+				conn.Write(packet(0x20, []byte{0, 5}))
+			},
+			want: "connect refused: not authorized",
+		},
+		{
+			name: "filter refused",
+			serve: func(conn net.Conn, r *bufio.Reader) {
+				readPacket(r)
+				conn.Write(packet(0x20, []byte{0, 0}))
+				readPacket(r)
+				conn.Write(packet(0x90, []byte{0, 1, 0x80}))
+			},
+			want: `filter "site/#" refused`,
+		},
+		{
+			name: "malformed publish",
+			serve: func(conn net.Conn, r *bufio.Reader) {
+				handshake(t, conn, r)
+				conn.Write(packet(0x30, []byte{0, 9, 'a'}))
+				readPacket(r)
+			},
+			want: "malformed publish topic",
+		},
+		{
+			name: "oversized packet",
+			serve: func(conn net.Conn, r *bufio.Reader) {
+				handshake(t, conn, r)
+				conn.Write([]byte{0x30, 0xff, 0xff, 0xff, 0x7f})
+				readPacket(r)
+			},
+			want: "exceeds the limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := broker(t, tt.serve)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := mqtt.Subscribe(ctx, mqtt.Config{URL: url, ClientID: "health-api"}, []string{"site/#"}, func(mqtt.Message) {})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Should fail with %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := mqtt.Subscribe(context.Background(), mqtt.Config{URL: "ws://broker"}, nil, nil); err == nil {
+		t.Error("Should reject an unsupported scheme")
+	}
+}
+
+func Test_Match(t *testing.T) {
+	tests := []struct {
+		filter string
+		topic  string
+		want   []string
+		ok     bool
+	}{
+		{filter: "plant/pump/health", topic: "plant/pump/health", want: nil, ok: true},
+		{filter: "plant/pump/health", topic: "plant/fan/health", ok: false},
+		{filter: "plant/+/+/health", topic: "plant/1/pump/health", want: []string{"1", "pump"}, ok: true},
+		{filter: "plant/+/health", topic: "plant/1/pump/health", ok: false},
+		{filter: "plant/+/health", topic: "plant/1", ok: false},
+		{filter: "site/#", topic: "site/berlin/rack/4", want: []string{"berlin/rack/4"}, ok: true},
+		{filter: "site/+/#", topic: "site/berlin/rack/4", want: []string{"berlin", "rack/4"}, ok: true},
+		{filter: "site/#", topic: "site", want: []string{""}, ok: true},
+		{filter: "#", topic: "any/topic", want: []string{"any/topic"}, ok: true},
+	}
+
+	for _, tt := range tests {
+		got, ok := mqtt.Match(tt.filter, tt.topic)
+		if ok != tt.ok || !slices.Equal(got, tt.want) {
+			t.Errorf("Should match %s against %s as %v %t, got %v %t", tt.topic, tt.filter, tt.want, tt.ok, got, ok)
+		}
+	}
+}
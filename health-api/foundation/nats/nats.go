@@ -0,0 +1,241 @@
+// Package nats provides a minimal NATS publisher. It speaks the core text
+// protocol: it connects, answers the server's pings and publishes; it does
+// not subscribe, and JetStream acknowledgements are not awaited.
+package nats
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config holds the server connection settings. URL uses the nats or tls
+// scheme and may carry user:password or a token as user info.
+type Config struct {
+	URL  string
+	Name string
+}
+
+// Client publishes messages to a NATS server. It connects on first use and
+// reconnects once when a publish finds the connection broken.
+type Client struct {
+	cfg Config
+
+	mu   sync.Mutex
+	conn net.Conn
+	w    *bufio.Writer
+}
+
+// New constructs a client for the server. No connection is made until the
+// first publish.
+func New(cfg Config) *Client {
+	return &Client{cfg: cfg}
+}
+
+// Publish sends data on subject.
+func (c *Client) Publish(ctx context.Context, subject string, data []byte) error {
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return fmt.Errorf("invalid subject %q", subject)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.publish(ctx, subject, data)
+	if err == nil {
+		return nil
+	}
+
+	// The server may have dropped an idle connection; try a fresh one.
+	c.closeLocked()
+	if err := c.publish(ctx, subject, data); err != nil {
+		c.closeLocked()
+		return err
+	}
+
+	return nil
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closeLocked()
+	return nil
+}
+
+// =============================================================================
+
+func (c *Client) publish(ctx context.Context, subject string, data []byte) error {
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+		defer c.conn.SetWriteDeadline(time.Time{})
+	}
+
+	fmt.Fprintf(c.w, "PUB %s %d\r\n", subject, len(data))
+	c.w.Write(data)
+	c.w.WriteString("\r\n")
+
+	if err := c.w.Flush(); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	u, err := url.Parse(c.cfg.URL)
+	if err != nil {
+		return fmt.Errorf("parsing url: %w", err)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("reading info: %w", err)
+	}
+	payload, ok := strings.CutPrefix(strings.TrimSpace(line), "INFO ")
+	if !ok || json.Unmarshal([]byte(payload), &info) != nil {
+		conn.Close()
+		return fmt.Errorf("unexpected greeting %q", line)
+	}
+
+	// The server announces TLS in its plain-text INFO; the handshake
+	// follows it.
+	if info.TLSRequired || u.Scheme == "tls" {
+		tc := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tc.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("tls: %w", err)
+		}
+		conn = tc
+		r = bufio.NewReader(conn)
+	}
+
+	opts := map[string]any{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     c.cfg.Name,
+		"lang":     "go",
+		"protocol": 1,
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			opts["user"], opts["pass"] = u.User.Username(), pass
+		} else {
+			opts["auth_token"] = u.User.Username()
+		}
+	}
+
+	connect, err := json.Marshal(opts)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("encoding connect: %w", err)
+	}
+
+	// PING after CONNECT makes the server report authorization errors
+	// before the first message is lost.
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		conn.Close()
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("connect: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if msg, ok := strings.CutPrefix(line, "-ERR "); ok {
+			conn.Close()
+			return fmt.Errorf("connect: %s", strings.Trim(msg, "'"))
+		}
+	}
+
+	conn.SetDeadline(time.Time{})
+
+	c.conn = conn
+	c.w = bufio.NewWriter(conn)
+
+	go c.readLoop(conn, r)
+
+	return nil
+}
+
+// readLoop answers the server's pings on conn until it is closed, and
+// closes it when the server reports an error.
+func (c *Client) readLoop(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PING":
+			c.mu.Lock()
+			if c.conn == conn {
+				c.w.WriteString("PONG\r\n")
+				c.w.Flush()
+			}
+			c.mu.Unlock()
+
+		case strings.HasPrefix(line, "-ERR"):
+			conn.Close()
+		}
+	}
+
+	c.mu.Lock()
+	if c.conn == conn {
+		c.closeLocked()
+	}
+	c.mu.Unlock()
+}
+
+func (c *Client) closeLocked() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.w = nil
+}
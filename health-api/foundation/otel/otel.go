@@ -3,48 +3,83 @@ package otel
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// Set of supported exporter protocols.
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
 )
 
 // Config holds the OpenTelemetry configuration.
 type Config struct {
-	ServiceName string
-	ReporterURI string
+	ServiceName    string
+	ServiceVersion string
+	ReporterURI    string
+
+	// Probability is the fraction of new traces sampled, between 0 and 1.
+	// Traces started upstream follow the caller's sampling decision.
 	Probability float64
+
+	// Protocol selects OTLP over gRPC (default) or HTTP.
+	Protocol string
+
+	// Insecure disables TLS to the collector. CAFile adds a CA bundle to
+	// verify the collector when TLS is enabled.
+	Insecure bool
+	CAFile   string
+
+	// Kubernetes placement, usually provided by the downward API.
+	Namespace string
+	PodName   string
+	NodeName  string
+
+	// Attributes are added to the resource as-is.
+	Attributes map[string]string
 }
 
 // InitTracing initializes OpenTelemetry tracing.
 func InitTracing(cfg Config) (trace.Tracer, func(context.Context) error, error) {
+	// Set global propagator. Incoming trace context is propagated to
+	// backends even when spans are not exported.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
 	// If no reporter URI is provided, use noop tracer
 	if cfg.ReporterURI == "" {
 		tracer := trace.NewNoopTracerProvider().Tracer("")
 		return tracer, func(context.Context) error { return nil }, nil
 	}
 
+	if cfg.Probability < 0 || cfg.Probability > 1 {
+		return nil, nil, fmt.Errorf("sampling probability %v out of range [0, 1]", cfg.Probability)
+	}
+
 	// Create OTLP exporter
-	exporter, err := otlptracegrpc.New(
-		context.Background(),
-		otlptracegrpc.WithEndpoint(cfg.ReporterURI),
-		otlptracegrpc.WithInsecure(),
-	)
+	exporter, err := newExporter(cfg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("creating otlp exporter: %w", err)
 	}
 
-	// Set default probability if not specified
-	if cfg.Probability == 0 {
-		cfg.Probability = 0.05 // 5% sampling
-	}
-
 	// Create trace provider
 	traceProvider := sdktrace.NewTracerProvider(
 		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Probability))),
@@ -52,22 +87,80 @@ func InitTracing(cfg Config) (trace.Tracer, func(context.Context) error, error)
 			sdktrace.WithMaxExportBatchSize(100),
 			sdktrace.WithBatchTimeout(10*time.Second),
 		),
-		sdktrace.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String(cfg.ServiceName),
-		)),
+		sdktrace.WithResource(newResource(cfg)),
 	)
 
 	// Set global trace provider
 	otel.SetTracerProvider(traceProvider)
 
-	// Set global propagator
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
-
 	tracer := traceProvider.Tracer("")
 
 	return tracer, traceProvider.Shutdown, nil
 }
+
+// newExporter creates the OTLP exporter for the configured protocol.
+func newExporter(cfg Config) (*otlptrace.Exporter, error) {
+	var tlsCfg *tls.Config
+	if !cfg.Insecure {
+		tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading ca file: %w", err)
+			}
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+			}
+			tlsCfg.RootCAs = pool
+		}
+	}
+
+	switch cfg.Protocol {
+	case "", ProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.ReporterURI)}
+		if tlsCfg == nil {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+		return otlptracegrpc.New(context.Background(), opts...)
+
+	case ProtocolHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.ReporterURI)}
+		if tlsCfg == nil {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	}
+
+	return nil, fmt.Errorf("unknown protocol %q", cfg.Protocol)
+}
+
+// newResource describes this process for exported spans.
+func newResource(cfg Config) *resource.Resource {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+	}
+
+	add := func(key attribute.Key, value string) {
+		if value != "" {
+			attrs = append(attrs, key.String(value))
+		}
+	}
+
+	add(semconv.ServiceVersionKey, cfg.ServiceVersion)
+	add(semconv.K8SNamespaceNameKey, cfg.Namespace)
+	add(semconv.K8SPodNameKey, cfg.PodName)
+	add(semconv.K8SNodeNameKey, cfg.NodeName)
+
+	for k, v := range cfg.Attributes {
+		add(attribute.Key(k), v)
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...)
+}
@@ -0,0 +1,539 @@
+// Package rrule parses RFC 5545 recurrence rules and computes their
+// occurrences. It supports the DAILY, WEEKLY, MONTHLY and YEARLY
+// frequencies with INTERVAL, COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY
+// (including ordinals such as 1SU or -1FR), BYHOUR and BYMINUTE, which
+// covers calendar style schedules. Weeks start on Monday, and BYDAY
+// ordinals count within the month, also for YEARLY rules, which expand
+// within BYMONTH or the month of the start date.
+package rrule
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Set of supported frequencies.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// maxCount bounds COUNT. Rules with a count are walked from their start
+// to know when the count runs out, so a large one would be walked on every
+// lookup; use UNTIL for long-running rules.
+const maxCount = 1000
+
+// weekday is a BYDAY entry. A non-zero n selects the nth occurrence of the
+// day within the month, counting from the end when negative.
+type weekday struct {
+	n   int
+	day time.Weekday
+}
+
+// Rule represents a parsed recurrence rule.
+type Rule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byMonth    []time.Month
+	byMonthDay []int
+	byDay      []weekday
+	byHour     []int
+	byMinute   []int
+}
+
+var days = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=SU;BYHOUR=2". An "RRULE:"
+// prefix is accepted.
+func Parse(s string) (Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+
+	r := Rule{interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.freq = strings.ToUpper(value)
+			if !slices.Contains([]string{Daily, Weekly, Monthly, Yearly}, r.freq) {
+				return Rule{}, fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			r.interval, err = parsePositive(value)
+		case "COUNT":
+			r.count, err = parsePositive(value)
+			if err == nil && r.count > maxCount {
+				err = fmt.Errorf("must be at most %d", maxCount)
+			}
+		case "UNTIL":
+			r.until, err = parseUntil(value)
+		case "BYMONTH":
+			var months []int
+			months, err = parseList(value, 1, 12, false)
+			for _, m := range months {
+				r.byMonth = append(r.byMonth, time.Month(m))
+			}
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseList(value, 1, 31, true)
+		case "BYDAY":
+			r.byDay, err = parseDays(value)
+		case "BYHOUR":
+			r.byHour, err = parseList(value, 0, 23, false)
+		case "BYMINUTE":
+			r.byMinute, err = parseList(value, 0, 59, false)
+		case "WKST":
+			if strings.ToUpper(value) != "MO" {
+				return Rule{}, fmt.Errorf("unsupported WKST %q", value)
+			}
+		default:
+			return Rule{}, fmt.Errorf("unsupported rule part %q", name)
+		}
+
+		if err != nil {
+			return Rule{}, fmt.Errorf("%s: %w", strings.ToUpper(name), err)
+		}
+	}
+
+	if r.freq == "" {
+		return Rule{}, fmt.Errorf("FREQ is required")
+	}
+
+	if r.count > 0 && !r.until.IsZero() {
+		return Rule{}, fmt.Errorf("COUNT and UNTIL are mutually exclusive")
+	}
+
+	for _, wd := range r.byDay {
+		if wd.n != 0 && r.freq != Monthly && r.freq != Yearly {
+			return Rule{}, fmt.Errorf("BYDAY ordinals require FREQ=MONTHLY or YEARLY")
+		}
+	}
+
+	return r, nil
+}
+
+// First returns the first occurrence of the rule, starting at start. It
+// reports false when the rule never matches from start, e.g.
+// FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30, so such rules can be rejected when
+// they are created.
+func (r Rule) First(start time.Time) (time.Time, bool) {
+	return r.Next(start, start.Add(-time.Nanosecond))
+}
+
+// Last returns the latest occurrence of the rule, starting at start, that is
+// not after t. It reports false when there is none.
+func (r Rule) Last(start time.Time, t time.Time) (time.Time, bool) {
+	if r.count > 0 {
+		var last time.Time
+		var found bool
+
+		r.each(start, start, func(o time.Time) bool {
+			if o.After(t) {
+				return false
+			}
+			last, found = o, true
+			return true
+		})
+
+		return last, found
+	}
+
+	if !r.until.IsZero() && t.After(r.until) {
+		t = r.until
+	}
+	if t.Before(start) {
+		return time.Time{}, false
+	}
+
+	// Walk back from the period containing t; a rule that matches at all
+	// matches again within a cycle.
+	p := r.period(start, t)
+	for period := p; period >= 0 && period > p-r.cycle(); period-- {
+		occurrences := r.expand(start, period)
+		for i := len(occurrences) - 1; i >= 0; i-- {
+			if o := occurrences[i]; !o.After(t) && !o.Before(start) {
+				return o, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// Next returns the first occurrence of the rule, starting at start, that is
+// after t. It reports false when there is none.
+func (r Rule) Next(start time.Time, t time.Time) (time.Time, bool) {
+	var next time.Time
+	var found bool
+
+	r.each(start, t, func(o time.Time) bool {
+		if o.After(t) {
+			next, found = o, true
+			return false
+		}
+		return true
+	})
+
+	return next, found
+}
+
+// Between returns the occurrences of the rule, starting at start, that are
+// not before from and before to.
+func (r Rule) Between(start time.Time, from time.Time, to time.Time) []time.Time {
+	var occurrences []time.Time
+
+	r.each(start, from, func(o time.Time) bool {
+		if !o.Before(to) {
+			return false
+		}
+		if !o.Before(from) {
+			occurrences = append(occurrences, o)
+		}
+		return true
+	})
+
+	return occurrences
+}
+
+// each calls fn with every occurrence in order, from the period containing
+// from on, until fn returns false or the rule ends. Rules with a COUNT are
+// walked from start since the count runs from there. Occurrences keep the
+// wall clock time in start's location, so a 02:00 window stays at 02:00
+// across daylight saving changes.
+func (r Rule) each(start time.Time, from time.Time, fn func(time.Time) bool) {
+	first := 0
+	if r.count == 0 {
+		first = r.period(start, from)
+	}
+
+	var n int
+	var gap int
+
+	for period := first; gap < r.cycle(); period++ {
+		occurrences := r.expand(start, period)
+
+		gap++
+		for _, o := range occurrences {
+			if o.Before(start) {
+				continue
+			}
+			gap = 0
+
+			if !r.until.IsZero() && o.After(r.until) {
+				return
+			}
+			if n++; r.count > 0 && n > r.count {
+				return
+			}
+			if !fn(o) {
+				return
+			}
+		}
+	}
+}
+
+// period returns the index of the period containing t, counting from the
+// one containing start, or 0 when t is not after start.
+func (r Rule) period(start time.Time, t time.Time) int {
+	if !t.After(start) {
+		return 0
+	}
+
+	sy, sm, sd := start.Date()
+	ty, tm, td := t.In(start.Location()).Date()
+
+	var units int
+	switch r.freq {
+	case Daily:
+		units = dayNumber(ty, tm, td) - dayNumber(sy, sm, sd)
+	case Weekly:
+		units = (monday(ty, tm, td) - monday(sy, sm, sd)) / 7
+	case Monthly:
+		units = (ty-sy)*12 + int(tm-sm)
+	case Yearly:
+		units = ty - sy
+	}
+
+	return units / r.interval
+}
+
+// cycle returns the number of periods after which the calendar repeats
+// itself: 400 Gregorian years hold a whole number of weeks. A rule without
+// an occurrence for that many periods in a row has no further ones.
+func (r Rule) cycle() int {
+	switch r.freq {
+	case Daily:
+		return 146_097
+	case Weekly:
+		return 20_871
+	case Monthly:
+		return 4_800
+	default:
+		return 400
+	}
+}
+
+// expand returns the sorted occurrences within the nth period after start.
+func (r Rule) expand(start time.Time, n int) []time.Time {
+	step := n * r.interval
+	y, m, d := start.Date()
+
+	var dates []time.Time
+	switch r.freq {
+	case Daily:
+		dates = []time.Time{date(y, m, d+step, start.Location())}
+
+	case Weekly:
+		// Monday of the week containing start, then step weeks on.
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := date(y, m, d-offset+7*step, start.Location())
+
+		if len(r.byDay) == 0 {
+			dates = []time.Time{monday.AddDate(0, 0, offset)}
+			break
+		}
+		for i := range 7 {
+			day := monday.AddDate(0, 0, i)
+			if r.matchesDay(day, 0) {
+				dates = append(dates, day)
+			}
+		}
+
+	case Monthly:
+		first := date(y, m+time.Month(step), 1, start.Location())
+		dates = r.daysInMonth(first, d)
+
+	case Yearly:
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+		for _, month := range months {
+			dates = append(dates, r.daysInMonth(date(y+step, month, 1, start.Location()), d)...)
+		}
+	}
+
+	var occurrences []time.Time
+	for _, day := range dates {
+		if len(r.byMonth) > 0 && !slices.Contains(r.byMonth, day.Month()) {
+			continue
+		}
+		if r.freq == Daily && !r.matchesDay(day, 0) {
+			continue
+		}
+		if r.freq != Monthly && r.freq != Yearly && len(r.byMonthDay) > 0 && !matchesMonthDay(r.byMonthDay, day) {
+			continue
+		}
+
+		occurrences = append(occurrences, r.times(day, start)...)
+	}
+
+	slices.SortFunc(occurrences, func(a, b time.Time) int { return a.Compare(b) })
+
+	return occurrences
+}
+
+// daysInMonth returns the days of the month starting at first selected by
+// BYMONTHDAY or BYDAY, or the day of month of the start date.
+func (r Rule) daysInMonth(first time.Time, startDay int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+
+	var selected []time.Time
+	for d := 1; d <= last; d++ {
+		day := first.AddDate(0, 0, d-1)
+
+		switch {
+		case len(r.byMonthDay) > 0:
+			if !matchesMonthDay(r.byMonthDay, day) {
+				continue
+			}
+			if len(r.byDay) > 0 && !r.matchesDay(day, last) {
+				continue
+			}
+		case len(r.byDay) > 0:
+			if !r.matchesDay(day, last) {
+				continue
+			}
+		default:
+			if d != startDay {
+				continue
+			}
+		}
+
+		selected = append(selected, day)
+	}
+
+	return selected
+}
+
+// matchesDay reports whether day satisfies BYDAY. Ordinals count within
+// the month, which has last days.
+func (r Rule) matchesDay(day time.Time, last int) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+
+	for _, wd := range r.byDay {
+		if day.Weekday() != wd.day {
+			continue
+		}
+
+		switch {
+		case wd.n == 0:
+			return true
+		case wd.n > 0 && (day.Day()-1)/7+1 == wd.n:
+			return true
+		case wd.n < 0 && (last-day.Day())/7+1 == -wd.n:
+			return true
+		}
+	}
+
+	return false
+}
+
+// times returns the occurrences on day for BYHOUR and BYMINUTE, defaulting
+// to the time of day of start.
+func (r Rule) times(day time.Time, start time.Time) []time.Time {
+	hours := r.byHour
+	if len(hours) == 0 {
+		hours = []int{start.Hour()}
+	}
+
+	minutes := r.byMinute
+	if len(minutes) == 0 {
+		minutes = []int{start.Minute()}
+	}
+
+	y, m, d := day.Date()
+
+	times := make([]time.Time, 0, len(hours)*len(minutes))
+	for _, h := range hours {
+		for _, min := range minutes {
+			times = append(times, time.Date(y, m, d, h, min, start.Second(), 0, start.Location()))
+		}
+	}
+
+	return times
+}
+
+// =============================================================================
+
+func date(y int, m time.Month, d int, loc *time.Location) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// dayNumber returns the number of days from the unix epoch to the date,
+// free of daylight saving shifts.
+func dayNumber(y int, m time.Month, d int) int {
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// monday returns the day number of the Monday of the week holding the date.
+func monday(y int, m time.Month, d int) int {
+	n := dayNumber(y, m, d)
+	offset := (int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Weekday()) + 6) % 7
+	return n - offset
+}
+
+func matchesMonthDay(monthDays []int, day time.Time) bool {
+	last := day.AddDate(0, 1, -day.Day()).Day()
+
+	for _, md := range monthDays {
+		if md == day.Day() || (md < 0 && last+md+1 == day.Day()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parsePositive(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("must be a positive integer")
+	}
+	return n, nil
+}
+
+// parseUntil accepts the UTC and floating date-time forms and plain dates.
+// Floating times are taken as UTC.
+func parseUntil(s string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			if layout == "20060102" {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("must be a date such as 20260101T000000Z")
+}
+
+// parseList parses a comma-separated list of integers within [min, max],
+// or within [-max, -min] too when negative is set.
+func parseList(s string, min, max int, negative bool) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", item)
+		}
+
+		inRange := n >= min && n <= max
+		if negative && n <= -min && n >= -max {
+			inRange = true
+		}
+		if !inRange {
+			return nil, fmt.Errorf("value %d out of range", n)
+		}
+
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// parseDays parses BYDAY values such as SU, 1SU or -1FR.
+func parseDays(s string) ([]weekday, error) {
+	var list []weekday
+	for _, item := range strings.Split(strings.ToUpper(s), ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid day %q", item)
+		}
+
+		day, ok := days[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q", item)
+		}
+
+		var n int
+		if prefix := item[:len(item)-2]; prefix != "" {
+			var err error
+			if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid day ordinal %q", item)
+			}
+		}
+
+		list = append(list, weekday{n: n, day: day})
+	}
+	return list, nil
+}
@@ -0,0 +1,305 @@
+package rrule_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"health-api/foundation/rrule"
+)
+
+func Test_Between(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		to    string
+		want  []string
+	}{
+		{
+			name:  "daily interval",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			start: "2026-01-01T09:00:00Z",
+			to:    "2026-01-08T00:00:00Z",
+			want:  []string{"2026-01-01T09:00:00Z", "2026-01-03T09:00:00Z", "2026-01-05T09:00:00Z", "2026-01-07T09:00:00Z"},
+		},
+		{
+			name:  "daily hours and minutes",
+			rule:  "FREQ=DAILY;BYHOUR=2,14;BYMINUTE=30",
+			start: "2026-01-01T00:00:00Z",
+			to:    "2026-01-02T12:00:00Z",
+			want:  []string{"2026-01-01T02:30:00Z", "2026-01-01T14:30:00Z", "2026-01-02T02:30:00Z"},
+		},
+		{
+			name:  "weekly by day",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,WE",
+			start: "2026-01-01T10:00:00Z",
+			to:    "2026-01-15T00:00:00Z",
+			want:  []string{"2026-01-05T10:00:00Z", "2026-01-07T10:00:00Z", "2026-01-12T10:00:00Z", "2026-01-14T10:00:00Z"},
+		},
+		{
+			name:  "weekly interval skips the start week's earlier days",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+			start: "2026-01-01T10:00:00Z",
+			to:    "2026-02-01T00:00:00Z",
+			want:  []string{"2026-01-13T10:00:00Z", "2026-01-27T10:00:00Z"},
+		},
+		{
+			name:  "monthly last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: "2026-01-15T00:00:00Z",
+			to:    "2026-04-01T00:00:00Z",
+			want:  []string{"2026-01-31T00:00:00Z", "2026-02-28T00:00:00Z", "2026-03-31T00:00:00Z"},
+		},
+		{
+			name:  "monthly first sunday",
+			rule:  "FREQ=MONTHLY;BYDAY=1SU",
+			start: "2026-01-01T00:00:00Z",
+			to:    "2026-04-01T00:00:00Z",
+			want:  []string{"2026-01-04T00:00:00Z", "2026-02-01T00:00:00Z", "2026-03-01T00:00:00Z"},
+		},
+		{
+			name:  "monthly last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: "2026-01-01T00:00:00Z",
+			to:    "2026-04-01T00:00:00Z",
+			want:  []string{"2026-01-30T00:00:00Z", "2026-02-27T00:00:00Z", "2026-03-27T00:00:00Z"},
+		},
+		{
+			name:  "yearly leap day",
+			rule:  "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29",
+			start: "2026-01-01T00:00:00Z",
+			to:    "2033-01-01T00:00:00Z",
+			want:  []string{"2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z"},
+		},
+		{
+			name:  "count",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: "2026-01-01T09:00:00Z",
+			to:    "2026-02-01T00:00:00Z",
+			want:  []string{"2026-01-01T09:00:00Z", "2026-01-02T09:00:00Z", "2026-01-03T09:00:00Z"},
+		},
+		{
+			name:  "until",
+			rule:  "FREQ=DAILY;UNTIL=20260104T120000Z",
+			start: "2026-01-01T09:00:00Z",
+			to:    "2026-02-01T00:00:00Z",
+			want:  []string{"2026-01-01T09:00:00Z", "2026-01-02T09:00:00Z", "2026-01-03T09:00:00Z", "2026-01-04T09:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := rrule.Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Should be able to parse %q: %s", tt.rule, err)
+			}
+
+			start := parseTime(t, tt.start)
+
+			got := format(r.Between(start, start, parseTime(t, tt.to)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Should return the occurrences\ngot:  %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NextLast(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		at       string
+		wantLast string
+		wantNext string
+	}{
+		{
+			name:     "daily years after start",
+			rule:     "FREQ=DAILY;BYHOUR=2",
+			start:    "2020-01-01T02:00:00Z",
+			at:       "2026-06-15T12:00:00Z",
+			wantLast: "2026-06-15T02:00:00Z",
+			wantNext: "2026-06-16T02:00:00Z",
+		},
+		{
+			name:     "weekly interval between occurrences",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			start:    "2026-01-05T00:00:00Z",
+			at:       "2026-03-03T00:00:00Z",
+			wantLast: "2026-03-02T00:00:00Z",
+			wantNext: "2026-03-16T00:00:00Z",
+		},
+		{
+			name:     "monthly before the first occurrence of the month",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR",
+			start:    "2026-01-01T00:00:00Z",
+			at:       "2026-05-10T00:00:00Z",
+			wantLast: "2026-04-24T00:00:00Z",
+			wantNext: "2026-05-29T00:00:00Z",
+		},
+		{
+			name:     "yearly leap day",
+			rule:     "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29",
+			start:    "2000-01-01T00:00:00Z",
+			at:       "2026-01-01T00:00:00Z",
+			wantLast: "2024-02-29T00:00:00Z",
+			wantNext: "2028-02-29T00:00:00Z",
+		},
+		{
+			name:     "count ended",
+			rule:     "FREQ=DAILY;COUNT=3",
+			start:    "2026-01-01T09:00:00Z",
+			at:       "2027-01-01T00:00:00Z",
+			wantLast: "2026-01-03T09:00:00Z",
+		},
+		{
+			name:     "until ended",
+			rule:     "FREQ=WEEKLY;UNTIL=20260201",
+			start:    "2026-01-05T09:00:00Z",
+			at:       "2026-06-01T00:00:00Z",
+			wantLast: "2026-01-26T09:00:00Z",
+		},
+		{
+			name:     "before start",
+			rule:     "FREQ=DAILY",
+			start:    "2026-01-05T09:00:00Z",
+			at:       "2026-01-01T00:00:00Z",
+			wantNext: "2026-01-05T09:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := rrule.Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Should be able to parse %q: %s", tt.rule, err)
+			}
+
+			start := parseTime(t, tt.start)
+			at := parseTime(t, tt.at)
+
+			last, ok := r.Last(start, at)
+			if got := formatOK(last, ok); got != tt.wantLast {
+				t.Errorf("Should return the last occurrence %q, got %q", tt.wantLast, got)
+			}
+
+			next, ok := r.Next(start, at)
+			if got := formatOK(next, ok); got != tt.wantNext {
+				t.Errorf("Should return the next occurrence %q, got %q", tt.wantNext, got)
+			}
+		})
+	}
+}
+
+func Test_DST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Europe/Berlin not available: %s", err)
+	}
+
+	r, err := rrule.Parse("FREQ=DAILY;BYHOUR=3")
+	if err != nil {
+		t.Fatalf("Should be able to parse the rule: %s", err)
+	}
+
+	start := time.Date(2026, time.March, 27, 3, 0, 0, 0, berlin)
+
+	// 03:00 stays 03:00 local time as Berlin moves from UTC+1 to UTC+2.
+	spring := format(r.Between(start, start, time.Date(2026, time.March, 31, 0, 0, 0, 0, berlin)))
+	want := []string{"2026-03-27T02:00:00Z", "2026-03-28T02:00:00Z", "2026-03-29T01:00:00Z", "2026-03-30T01:00:00Z"}
+	if !slices.Equal(spring, want) {
+		t.Errorf("Should keep the wall clock time in spring\ngot:  %v\nwant: %v", spring, want)
+	}
+
+	// And back from UTC+2 to UTC+1, looked up months after the start.
+	from := time.Date(2026, time.October, 24, 0, 0, 0, 0, berlin)
+	autumn := format(r.Between(start, from, from.AddDate(0, 0, 2)))
+	want = []string{"2026-10-24T01:00:00Z", "2026-10-25T02:00:00Z"}
+	if !slices.Equal(autumn, want) {
+		t.Errorf("Should keep the wall clock time in autumn\ngot:  %v\nwant: %v", autumn, want)
+	}
+
+	last, ok := r.Last(start, time.Date(2026, time.March, 29, 1, 30, 0, 0, time.UTC))
+	if got := formatOK(last, ok); got != "2026-03-29T01:00:00Z" {
+		t.Errorf("Should find the occurrence just after the change, got %q", got)
+	}
+}
+
+func Test_First(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		want  string
+	}{
+		{name: "matching", rule: "FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR", start: "2026-01-01T00:00:00Z", want: "2026-02-13T00:00:00Z"},
+		{name: "day missing from month", rule: "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", start: "2026-01-01T00:00:00Z"},
+		{name: "ordinal outside month day", rule: "FREQ=MONTHLY;BYMONTHDAY=1;BYDAY=2MO", start: "2026-01-01T00:00:00Z"},
+		{name: "interval misses leap years", rule: "FREQ=YEARLY;INTERVAL=4;BYMONTH=2;BYMONTHDAY=29", start: "2025-01-01T00:00:00Z"},
+		{name: "daily day missing from month", rule: "FREQ=DAILY;BYMONTH=2;BYMONTHDAY=30", start: "2026-01-01T00:00:00Z"},
+		{name: "until before start", rule: "FREQ=DAILY;UNTIL=20250101", start: "2026-01-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := rrule.Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Should be able to parse %q: %s", tt.rule, err)
+			}
+
+			first, ok := r.First(parseTime(t, tt.start))
+			if got := formatOK(first, ok); got != tt.want {
+				t.Errorf("Should return the first occurrence %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	rules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=DAILY;COUNT=1001",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=DAILY;WKST=SU",
+	}
+
+	for _, rule := range rules {
+		if _, err := rrule.Parse(rule); err == nil {
+			t.Errorf("Should reject %q", rule)
+		}
+	}
+}
+
+// =============================================================================
+
+func parseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	v, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatalf("Should be able to parse %q: %s", s, err)
+	}
+	return v
+}
+
+func format(times []time.Time) []string {
+	out := make([]string, len(times))
+	for i, v := range times {
+		out[i] = v.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func formatOK(v time.Time, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return v.UTC().Format(time.RFC3339)
+}
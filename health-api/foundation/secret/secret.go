@@ -0,0 +1,70 @@
+// Package secret reads credentials from a directory mounted from a
+// Kubernetes Secret and watches it for rotation. Each key of the Secret is a
+// file named after the key.
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Values maps the keys read from a Secret to their contents.
+type Values map[string]string
+
+// Read reads keys from dir. Trailing newlines, which editors and shell
+// redirection tend to add, are trimmed.
+func Read(dir string, keys ...string) (Values, error) {
+	values := make(Values, len(keys))
+
+	for _, key := range keys {
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("key %q not found in %s", key, dir)
+			}
+			return nil, fmt.Errorf("read key %q: %w", key, err)
+		}
+
+		values[key] = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return values, nil
+}
+
+// Watch reads keys from dir every interval until ctx is canceled and calls
+// fn whenever the values differ from the last ones seen, starting with
+// current. Failed reads are passed to fn and keep the last values.
+//
+// The kubelet rotates a mounted Secret by swapping a symlink, so polling
+// the contents is reliable where file events are not.
+func Watch(ctx context.Context, interval time.Duration, dir string, keys []string, current Values, fn func(Values, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		values, err := Read(dir, keys...)
+		if err != nil {
+			fn(nil, err)
+			continue
+		}
+
+		if maps.Equal(values, current) {
+			continue
+		}
+
+		current = values
+		fn(values, nil)
+	}
+}
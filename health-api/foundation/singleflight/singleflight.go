@@ -0,0 +1,95 @@
+// Package singleflight deduplicates concurrent calls for the same key, so
+// identical requests arriving together share one execution. It follows
+// golang.org/x/sync/singleflight, adding type parameters and letting each
+// caller stop waiting when its own context ends.
+package singleflight
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// call is an execution in flight or just completed.
+type call[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+	dups int
+}
+
+// Group deduplicates calls by key. The zero value is ready to use.
+type Group[T any] struct {
+	// Timeout bounds each call. Callers joining a call may have deadlines
+	// longer than the first caller's, so the call gets its own; zero leaves
+	// calls unbounded.
+	Timeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// Do runs fn once for all callers of key that arrive while it is in
+// flight, and reports whether the result was shared with another caller.
+//
+// fn gets a context with the first caller's values but not its
+// cancellation or deadline, bounded by Timeout instead, so one caller going
+// away or running out of time does not fail the others. A caller whose
+// context ends stops waiting and gets its error.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func(ctx context.Context) (T, error)) (v T, shared bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return v, false, err
+	}
+
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call[T])
+	}
+
+	c, ok := g.calls[key]
+	if ok {
+		c.dups++
+	} else {
+		c = &call[T]{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(ctx, key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared = c.dups > 0
+		g.mu.Unlock()
+		return c.val, shared, c.err
+
+	case <-ctx.Done():
+		return v, ok, ctx.Err()
+	}
+}
+
+func (g *Group[T]) run(ctx context.Context, key string, c *call[T], fn func(ctx context.Context) (T, error)) {
+	callCtx := context.WithoutCancel(ctx)
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, g.Timeout)
+		defer cancel()
+	}
+
+	// fn runs outside the callers' goroutines, so a panic is reported to
+	// them as an error rather than taking down the process.
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("panic: %v", r)
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	c.val, c.err = fn(callCtx)
+}
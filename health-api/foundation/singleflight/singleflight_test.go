@@ -0,0 +1,199 @@
+package singleflight_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"health-api/foundation/singleflight"
+)
+
+func Test_Dedup(t *testing.T) {
+	var g singleflight.Group[int]
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	results := make([]int, callers)
+
+	// The first caller starts the call; the others join it while it is
+	// blocked.
+	started := make(chan struct{})
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i > 0 {
+				<-started
+			}
+
+			v, s, err := g.Do(context.Background(), "key", fn)
+			if err != nil {
+				t.Errorf("Should not fail, got %s", err)
+			}
+			if s {
+				shared.Add(1)
+			}
+			results[i] = v
+		}()
+
+		if i == 0 {
+			waitFor(t, func() bool { return calls.Load() == 1 })
+			close(started)
+		}
+	}
+
+	// Let the joiners reach Do before the call completes.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Should run fn once, ran it %d times", n)
+	}
+	if n := shared.Load(); n != callers {
+		t.Errorf("Should report the result as shared to every caller, got %d", n)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("Should return the result to caller %d, got %d", i, v)
+		}
+	}
+
+	// A later call runs fn again.
+	if _, s, _ := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 1, nil }); s {
+		t.Error("Should not share a call that started after the previous one ended")
+	}
+}
+
+func Test_CallerCancel(t *testing.T) {
+	var g singleflight.Group[string]
+
+	release := make(chan struct{})
+	var callErr error
+	done := make(chan struct{})
+
+	fn := func(ctx context.Context) (string, error) {
+		defer close(done)
+		select {
+		case <-release:
+			return "ok", nil
+		case <-ctx.Done():
+			callErr = ctx.Err()
+			return "", ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(ctx, "key", fn)
+		errc <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Should stop waiting when the caller's context ends, got %v", err)
+	}
+
+	// The call itself carries on for the others.
+	joined := make(chan string, 1)
+	go func() {
+		v, _, _ := g.Do(context.Background(), "key", fn)
+		joined <- v
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	<-done
+
+	if callErr != nil {
+		t.Errorf("Should not cancel the call with its first caller, got %s", callErr)
+	}
+	if v := <-joined; v != "ok" {
+		t.Errorf("Should hand the result to the caller still waiting, got %q", v)
+	}
+}
+
+func Test_Timeout(t *testing.T) {
+	g := singleflight.Group[string]{Timeout: 50 * time.Millisecond}
+
+	fn := func(ctx context.Context) (string, error) {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return "ok", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	// A first caller with a short deadline doesn't cut the call short for
+	// one with a longer deadline.
+	short, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	go g.Do(short, "key", fn)
+	time.Sleep(time.Millisecond)
+
+	v, _, err := g.Do(context.Background(), "key", fn)
+	if err != nil || v != "ok" {
+		t.Fatalf("Should not inherit the first caller's deadline, got %q %v", v, err)
+	}
+
+	// The call's own timeout still ends a hung call.
+	hung := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	if _, _, err := g.Do(context.Background(), "hung", hung); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Should end the call after the group's timeout, got %v", err)
+	}
+}
+
+func Test_Panic(t *testing.T) {
+	var g singleflight.Group[int]
+
+	_, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		panic("boom")
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("Should report the panic as an error, got %v", err)
+	}
+
+	v, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 1, nil })
+	if err != nil || v != 1 {
+		t.Errorf("Should run again after a panic, got %d %v", v, err)
+	}
+}
+
+// =============================================================================
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,112 @@
+package snmp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Set of BER tags used by SNMP messages.
+const (
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagOID         = 0x06
+	tagSequence    = 0x30
+	tagTimeTicks   = 0x43
+	tagTrapV2      = 0xa7
+)
+
+func tlv(tag byte, content []byte) []byte {
+	b := []byte{tag}
+
+	n := len(content)
+	switch {
+	case n < 0x80:
+		b = append(b, byte(n))
+	default:
+		var l []byte
+		for ; n > 0; n >>= 8 {
+			l = append([]byte{byte(n)}, l...)
+		}
+		b = append(b, 0x80|byte(len(l)))
+		b = append(b, l...)
+	}
+
+	return append(b, content...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+func sequence(parts ...[]byte) []byte {
+	return tlv(tagSequence, concat(parts...))
+}
+
+func octetString(v []byte) []byte {
+	return tlv(tagOctetString, v)
+}
+
+// integer encodes v in the fewest two's complement octets.
+func integer(v int) []byte {
+	b := []byte{byte(v)}
+	for v > 127 || v < -128 {
+		v >>= 8
+		b = append([]byte{byte(v)}, b...)
+	}
+	return tlv(tagInteger, b)
+}
+
+// unsigned returns the content octets of an unsigned 32 bit value, with a
+// leading zero when the high bit is set.
+func unsigned(v uint32) []byte {
+	b := []byte{byte(v)}
+	for v >>= 8; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	if b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
+
+func objectIdentifier(oid string) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(oid, "."), ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid oid %q", oid)
+	}
+
+	arcs := make([]uint32, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oid %q", oid)
+		}
+		arcs[i] = uint32(n)
+	}
+
+	if arcs[0] > 2 || (arcs[0] < 2 && arcs[1] > 39) {
+		return nil, fmt.Errorf("invalid oid %q", oid)
+	}
+
+	b := base128(arcs[0]*40 + arcs[1])
+	for _, a := range arcs[2:] {
+		b = append(b, base128(a)...)
+	}
+
+	return tlv(tagOID, b), nil
+}
+
+// base128 encodes an OID arc, seven bits per octet with the high bit set on
+// all but the last.
+func base128(v uint32) []byte {
+	b := []byte{byte(v & 0x7f)}
+	for v >>= 7; v > 0; v >>= 7 {
+		b = append([]byte{byte(v&0x7f) | 0x80}, b...)
+	}
+	return b
+}
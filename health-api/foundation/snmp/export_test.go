@@ -0,0 +1,9 @@
+package snmp
+
+// Set of encoders exposed so tests can check them against known octets.
+var (
+	Integer          = integer
+	ObjectIdentifier = objectIdentifier
+	TLV              = tlv
+	LocalizeKey      = localizeKey
+)
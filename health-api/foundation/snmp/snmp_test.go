@@ -0,0 +1,317 @@
+package snmp_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"health-api/foundation/snmp"
+)
+
+// node is a decoded BER TLV.
+type node struct {
+	tag     byte
+	content []byte
+}
+
+// decode splits b into its TLVs.
+func decode(t *testing.T, b []byte) []node {
+	t.Helper()
+
+	var nodes []node
+	for len(b) > 0 {
+		if len(b) < 2 {
+			t.Fatalf("Should be able to decode a TLV from %x", b)
+		}
+
+		tag, n, off := b[0], int(b[1]), 2
+		if n&0x80 != 0 {
+			octets := n & 0x7f
+			n = 0
+			for _, o := range b[2 : 2+octets] {
+				n = n<<8 | int(o)
+			}
+			off += octets
+		}
+
+		if len(b) < off+n {
+			t.Fatalf("Should be able to read %d content octets from %x", n, b)
+		}
+		nodes = append(nodes, node{tag: tag, content: b[off : off+n]})
+		b = b[off+n:]
+	}
+
+	return nodes
+}
+
+// receiver listens for a single trap.
+func receiver(t *testing.T) (string, func() []byte) {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Should be able to listen: %s", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	read := func() []byte {
+		pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+		buf := make([]byte, 65535)
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("Should receive the trap: %s", err)
+		}
+		return buf[:n]
+	}
+
+	return pc.LocalAddr().String(), read
+}
+
+// varBinds decodes the variable bindings of a trap PDU, mapping the
+// encoded OID to the encoded value.
+func varBinds(t *testing.T, pdu node) map[string]node {
+	t.Helper()
+
+	if pdu.tag != 0xa7 {
+		t.Fatalf("Should send an SNMPv2-Trap-PDU, got tag 0x%02x", pdu.tag)
+	}
+
+	fields := decode(t, pdu.content)
+	if len(fields) != 4 || !bytes.Equal(fields[1].content, []byte{0}) || !bytes.Equal(fields[2].content, []byte{0}) {
+		t.Fatalf("Should send request id, zero error status and index and the bindings, got %+v", fields)
+	}
+
+	vbs := make(map[string]node)
+	for _, vb := range decode(t, fields[3].content) {
+		pair := decode(t, vb.content)
+		vbs[hex.EncodeToString(pair[0].content)] = pair[1]
+	}
+	return vbs
+}
+
+// intOf decodes the content octets of a non-negative integer.
+func intOf(b []byte) int {
+	var n int
+	for _, o := range b {
+		n = n<<8 | int(o)
+	}
+	return n
+}
+
+func oid(t *testing.T, s string) string {
+	t.Helper()
+
+	b, err := snmp.ObjectIdentifier(s)
+	if err != nil {
+		t.Fatalf("Should be able to encode %s: %s", s, err)
+	}
+	return hex.EncodeToString(b[2:])
+}
+
+func Test_TrapV2c(t *testing.T) {
+	addr, read := receiver(t)
+
+	c, err := snmp.New(snmp.Config{Address: addr, Version: snmp.Version2c, Community: "probes"})
+	if err != nil {
+		t.Fatalf("Should be able to construct the client: %s", err)
+	}
+
+	err = c.Trap(context.Background(), "1.3.6.1.4.1.8072.9999.1", []snmp.VarBind{
+		{OID: "1.3.6.1.4.1.8072.9999.2", Value: "https://shop.example.com is down"},
+		{OID: "1.3.6.1.4.1.8072.9999.3", Value: 300},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to send the trap: %s", err)
+	}
+
+	msg := decode(t, read())
+	if len(msg) != 1 || msg[0].tag != 0x30 {
+		t.Fatalf("Should send a single message sequence, got %+v", msg)
+	}
+
+	fields := decode(t, msg[0].content)
+	if len(fields) != 3 || !bytes.Equal(fields[0].content, []byte{1}) || string(fields[1].content) != "probes" {
+		t.Fatalf("Should send version 2c with the community, got %+v", fields)
+	}
+
+	vbs := varBinds(t, fields[2])
+
+	if up, ok := vbs[oid(t, "1.3.6.1.2.1.1.3.0")]; !ok || up.tag != 0x43 {
+		t.Errorf("Should lead with sysUpTime as TimeTicks, got %+v", up)
+	}
+	if trap := vbs[oid(t, "1.3.6.1.6.3.1.1.4.1.0")]; trap.tag != 0x06 || hex.EncodeToString(trap.content) != oid(t, "1.3.6.1.4.1.8072.9999.1") {
+		t.Errorf("Should name the trap in snmpTrapOID, got %+v", trap)
+	}
+	if v := vbs[oid(t, "1.3.6.1.4.1.8072.9999.2")]; v.tag != 0x04 || string(v.content) != "https://shop.example.com is down" {
+		t.Errorf("Should bind the string, got %+v", v)
+	}
+	if v := vbs[oid(t, "1.3.6.1.4.1.8072.9999.3")]; v.tag != 0x02 || !bytes.Equal(v.content, []byte{0x01, 0x2c}) {
+		t.Errorf("Should bind the integer, got %+v", v)
+	}
+
+	if err := c.Trap(context.Background(), "1.3.6.1.4.1.8072.9999.1", []snmp.VarBind{{OID: "1.3", Value: 1.5}}); err == nil {
+		t.Error("Should reject an unsupported value type")
+	}
+}
+
+func Test_TrapV3(t *testing.T) {
+	addr, read := receiver(t)
+
+	engineID, _ := hex.DecodeString("80001f8804746573742d656e67696e65")
+
+	c, err := snmp.New(snmp.Config{
+		Address:      addr,
+		Version:      snmp.Version3,
+		User:         "monitor",
+		AuthProtocol: "SHA",
+		AuthPassword: "authpassword",
+		PrivProtocol: "AES",
+		PrivPassword: "privpassword",
+		EngineID:     "0x" + hex.EncodeToString(engineID),
+	})
+	if err != nil {
+		t.Fatalf("Should be able to construct the client: %s", err)
+	}
+
+	if err := c.Trap(context.Background(), "1.3.6.1.4.1.8072.9999.1", []snmp.VarBind{{OID: "1.3.6.1.4.1.8072.9999.2", Value: "down"}}); err != nil {
+		t.Fatalf("Should be able to send the trap: %s", err)
+	}
+
+	raw := read()
+	msg := decode(t, decode(t, raw)[0].content)
+	if len(msg) != 4 || !bytes.Equal(msg[0].content, []byte{3}) {
+		t.Fatalf("Should send an SNMPv3 message, got %+v", msg)
+	}
+
+	header := decode(t, msg[1].content)
+	if !bytes.Equal(header[2].content, []byte{0x03}) || !bytes.Equal(header[3].content, []byte{3}) {
+		t.Errorf("Should flag authPriv under the USM, got %+v", header)
+	}
+
+	sec := decode(t, decode(t, msg[2].content)[0].content)
+	if !bytes.Equal(sec[0].content, engineID) || string(sec[3].content) != "monitor" {
+		t.Fatalf("Should name the engine and user, got %+v", sec)
+	}
+	authParams, salt := sec[4].content, sec[5].content
+	if len(authParams) != 12 || len(salt) != 8 {
+		t.Fatalf("Should carry a 12 octet MAC and an 8 octet salt, got %d %d", len(authParams), len(salt))
+	}
+
+	// The MAC covers the message with the MAC itself zeroed.
+	zeroed := bytes.Replace(raw, authParams, make([]byte, len(authParams)), 1)
+	mac := hmac.New(sha1.New, snmp.LocalizeKey(sha1.New, "authpassword", engineID))
+	mac.Write(zeroed)
+	if !hmac.Equal(mac.Sum(nil)[:12], authParams) {
+		t.Error("Should sign the message with the localized auth key")
+	}
+
+	// AES-128 in CFB mode, keyed with the localized privacy key and an IV
+	// of the engine boots, the engine time and the salt (RFC 3826).
+	iv := binary.BigEndian.AppendUint32(nil, uint32(intOf(sec[1].content)))
+	iv = binary.BigEndian.AppendUint32(iv, uint32(intOf(sec[2].content)))
+	iv = append(iv, salt...)
+
+	block, err := aes.NewCipher(snmp.LocalizeKey(sha1.New, "privpassword", engineID)[:16])
+	if err != nil {
+		t.Fatalf("Should be able to build the cipher: %s", err)
+	}
+	scoped := make([]byte, len(msg[3].content))
+	cipher.NewCFBDecrypter(block, iv).XORKeyStream(scoped, msg[3].content)
+
+	pdu := decode(t, decode(t, scoped)[0].content)
+	if len(pdu) != 3 || !bytes.Equal(pdu[0].content, engineID) {
+		t.Fatalf("Should encrypt the scoped PDU of the engine, got %+v", pdu)
+	}
+	if v := varBinds(t, pdu[2])[oid(t, "1.3.6.1.4.1.8072.9999.2")]; string(v.content) != "down" {
+		t.Errorf("Should carry the bindings, got %+v", v)
+	}
+}
+
+func Test_Encoding(t *testing.T) {
+	ints := map[int]string{
+		0:    "020100",
+		127:  "02017f",
+		128:  "02020080",
+		300:  "0202012c",
+		-1:   "0201ff",
+		-128: "020180",
+		-129: "0202ff7f",
+	}
+	for v, want := range ints {
+		if got := hex.EncodeToString(snmp.Integer(v)); got != want {
+			t.Errorf("Should encode %d as %s, got %s", v, want, got)
+		}
+	}
+
+	oids := map[string]string{
+		"1.3.6.1.2.1.1.3.0":  "06082b06010201010300",
+		".1.3.6.1.4.1.8072":  "06072b06010401bf08",
+		"2.999.3":            "0603883703",
+		"1.3.6.1.4.1.268435": "06082b0601040190b113",
+	}
+	for s, want := range oids {
+		b, err := snmp.ObjectIdentifier(s)
+		if err != nil || hex.EncodeToString(b) != want {
+			t.Errorf("Should encode %s as %s, got %x %v", s, want, b, err)
+		}
+	}
+
+	for _, s := range []string{"1", "3.1", "1.40", "1.3.x", "1.3.4294967296"} {
+		if _, err := snmp.ObjectIdentifier(s); err == nil {
+			t.Errorf("Should reject the oid %q", s)
+		}
+	}
+
+	long := snmp.TLV(0x04, make([]byte, 300))
+	if !bytes.Equal(long[:4], []byte{0x04, 0x82, 0x01, 0x2c}) || len(long) != 304 {
+		t.Errorf("Should use the long length form past 127 octets, got %x", long[:4])
+	}
+}
+
+// Test_LocalizeKey checks the password to key algorithm against the
+// examples of RFC 3414 A.3.
+func Test_LocalizeKey(t *testing.T) {
+	engineID, _ := hex.DecodeString("000000000000000000000002")
+
+	if got := hex.EncodeToString(snmp.LocalizeKey(md5.New, "maplesyrup", engineID)); got != "526f5eed9fcce26f8964c2930787d82b" {
+		t.Errorf("Should localize the MD5 key as in RFC 3414 A.3.1, got %s", got)
+	}
+
+	if got := hex.EncodeToString(snmp.LocalizeKey(sha1.New, "maplesyrup", engineID)); got != "6695febc9288e36282235fc7151f128497b38f3f" {
+		t.Errorf("Should localize the SHA key as in RFC 3414 A.3.2, got %s", got)
+	}
+}
+
+func Test_New(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  snmp.Config
+		want string
+	}{
+		{name: "version", cfg: snmp.Config{Version: "v1"}, want: "unknown snmp version"},
+		{name: "user", cfg: snmp.Config{Version: snmp.Version3}, want: "requires a user"},
+		{name: "priv without auth", cfg: snmp.Config{Version: snmp.Version3, User: "u", PrivPassword: "p"}, want: "privacy requires authentication"},
+		{name: "auth protocol", cfg: snmp.Config{Version: snmp.Version3, User: "u", AuthPassword: "a", AuthProtocol: "SHA512"}, want: "unknown snmpv3 auth protocol"},
+		{name: "priv protocol", cfg: snmp.Config{Version: snmp.Version3, User: "u", AuthPassword: "a", PrivPassword: "p", PrivProtocol: "DES"}, want: "unknown snmpv3 privacy protocol"},
+		{name: "engine id", cfg: snmp.Config{Version: snmp.Version3, User: "u", EngineID: "zz"}, want: "decoding engine id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := snmp.New(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Should fail with %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package snmp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Set of SNMPv3 message flags.
+const (
+	flagAuth = 0x01
+	flagPriv = 0x02
+)
+
+// securityModelUSM identifies the user-based security model (RFC 3414).
+const securityModelUSM = 3
+
+// usm builds SNMPv3 messages for a user of the local engine.
+type usm struct {
+	engineID []byte
+	user     string
+	newHash  func() hash.Hash
+	macLen   int
+	authKey  []byte
+	privKey  []byte
+}
+
+func newUSM(cfg Config, engineID []byte) (*usm, error) {
+	if cfg.User == "" {
+		return nil, fmt.Errorf("snmpv3 requires a user")
+	}
+
+	u := usm{
+		engineID: engineID,
+		user:     cfg.User,
+	}
+
+	if cfg.AuthPassword == "" {
+		if cfg.PrivPassword != "" {
+			return nil, fmt.Errorf("snmpv3 privacy requires authentication")
+		}
+		return &u, nil
+	}
+
+	switch strings.ToUpper(cfg.AuthProtocol) {
+	case "MD5":
+		u.newHash, u.macLen = md5.New, 12
+	case "", "SHA", "SHA1":
+		u.newHash, u.macLen = sha1.New, 12
+	case "SHA256":
+		u.newHash, u.macLen = sha256.New, 24
+	default:
+		return nil, fmt.Errorf("unknown snmpv3 auth protocol %q", cfg.AuthProtocol)
+	}
+
+	u.authKey = localizeKey(u.newHash, cfg.AuthPassword, engineID)
+
+	if cfg.PrivPassword != "" {
+		switch strings.ToUpper(cfg.PrivProtocol) {
+		case "", "AES", "AES128":
+		default:
+			return nil, fmt.Errorf("unknown snmpv3 privacy protocol %q", cfg.PrivProtocol)
+		}
+
+		// AES-128 uses the first 16 octets of the localized key (RFC 3826).
+		u.privKey = localizeKey(u.newHash, cfg.PrivPassword, engineID)[:16]
+	}
+
+	return &u, nil
+}
+
+// message wraps the PDU in an SNMPv3 message, encrypting and signing it as
+// configured. The engine boots counter stays at 1 since the sender does not
+// persist it.
+func (u *usm) message(msgID int32, engineTime int, pdu []byte) ([]byte, error) {
+	const engineBoots = 1
+
+	var flags byte
+	if u.authKey != nil {
+		flags |= flagAuth
+	}
+	if u.privKey != nil {
+		flags |= flagPriv
+	}
+
+	scoped := sequence(
+		octetString(u.engineID),
+		octetString(nil),
+		pdu,
+	)
+
+	var privParams []byte
+	data := scoped
+	if u.privKey != nil {
+		salt := make([]byte, 8)
+		rand.Read(salt)
+		privParams = salt
+
+		iv := make([]byte, 0, aes.BlockSize)
+		iv = binary.BigEndian.AppendUint32(iv, engineBoots)
+		iv = binary.BigEndian.AppendUint32(iv, uint32(engineTime))
+		iv = append(iv, salt...)
+
+		block, err := aes.NewCipher(u.privKey)
+		if err != nil {
+			return nil, fmt.Errorf("privacy cipher: %w", err)
+		}
+
+		encrypted := make([]byte, len(scoped))
+		cipher.NewCFBEncrypter(block, iv).XORKeyStream(encrypted, scoped)
+		data = octetString(encrypted)
+	}
+
+	// The MAC is computed over the whole message with the authentication
+	// parameters zeroed, then written in their place.
+	authParams := make([]byte, u.macLen)
+
+	secParams := sequence(
+		octetString(u.engineID),
+		integer(engineBoots),
+		integer(engineTime),
+		octetString([]byte(u.user)),
+		octetString(authParams),
+		octetString(privParams),
+	)
+
+	msg := sequence(
+		integer(3),
+		sequence(
+			integer(int(msgID)),
+			integer(65507),
+			octetString([]byte{flags}),
+			integer(securityModelUSM),
+		),
+		octetString(secParams),
+		data,
+	)
+
+	if u.authKey != nil {
+		mac := hmac.New(u.newHash, u.authKey)
+		mac.Write(msg)
+		sum := mac.Sum(nil)[:u.macLen]
+
+		// The zeroed parameters are located through the security
+		// parameters, which end with them and the privacy parameters.
+		i := bytes.Index(msg, secParams)
+		if i < 0 {
+			return nil, fmt.Errorf("locating auth parameters")
+		}
+		off := i + len(secParams) - len(octetString(privParams)) - u.macLen
+		copy(msg[off:], sum)
+	}
+
+	return msg, nil
+}
+
+// localizeKey derives the key of the password for the engine (RFC 3414
+// A.2): the password is repeated over 1 MiB and hashed, then the digest is
+// hashed around the engine ID.
+func localizeKey(newHash func() hash.Hash, password string, engineID []byte) []byte {
+	h := newHash()
+
+	const size = 1 << 20
+	pw := []byte(password)
+	buf := make([]byte, 64)
+	for n := 0; n < size; n += len(buf) {
+		for i := range buf {
+			buf[i] = pw[(n+i)%len(pw)]
+		}
+		h.Write(buf)
+	}
+	ku := h.Sum(nil)
+
+	h.Reset()
+	h.Write(ku)
+	h.Write(engineID)
+	h.Write(ku)
+
+	return h.Sum(nil)
+}
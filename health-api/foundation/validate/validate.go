@@ -0,0 +1,227 @@
+// Package validate checks struct values against rules declared in their
+// `validate` struct tags:
+//
+//	required   the value must not be empty; pointers must be set
+//	min=N      numbers must be at least N; strings and slices need N elements
+//	max=N      numbers must be at most N; strings and slices allow N elements
+//	url        strings must be an absolute http or https URL
+//	duration   strings must parse with time.ParseDuration
+//
+// Rules other than required are skipped for empty values. Nil pointers are
+// skipped entirely, which suits optional fields of update payloads. Fields
+// are reported by their JSON name; nested structs and slices of structs are
+// checked too, e.g. results[1].target.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors maps field names to the reason they failed validation.
+type Errors map[string]string
+
+// Error implements the error interface.
+func (e Errors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + ": " + e[name]
+	}
+
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Check validates val, a struct or pointer to one. It returns Errors when
+// any field fails, or nil.
+func Check(val any) error {
+	v := reflect.ValueOf(val)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := make(Errors)
+	checkStruct(errs, "", v)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func checkStruct(errs Errors, prefix string, v reflect.Value) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(sf)
+		fv := v.Field(i)
+
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			if msg := checkField(fv, tag); msg != "" {
+				errs[name] = msg
+				continue
+			}
+		}
+
+		checkNested(errs, name, fv)
+	}
+}
+
+// checkNested descends into struct fields and slices of structs.
+func checkNested(errs Errors, name string, v reflect.Value) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[time.Time]() {
+			return
+		}
+		checkStruct(errs, name+".", v)
+
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			checkNested(errs, fmt.Sprintf("%s[%d]", name, i), v.Index(i))
+		}
+	}
+}
+
+// checkField applies the comma separated rules to v and returns the reason
+// the first failing rule gives.
+func checkField(v reflect.Value, tag string) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	rules := strings.Split(tag, ",")
+
+	if v.IsZero() {
+		if slices.Contains(rules, "required") {
+			return "is required"
+		}
+		return ""
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+
+		var msg string
+		switch name {
+		case "required":
+		case "min":
+			msg = checkBound(v, arg, true)
+		case "max":
+			msg = checkBound(v, arg, false)
+		case "url":
+			msg = checkURL(v)
+		case "duration":
+			msg = checkDuration(v)
+		default:
+			msg = fmt.Sprintf("unknown validation rule %q", name)
+		}
+
+		if msg != "" {
+			return msg
+		}
+	}
+
+	return ""
+}
+
+func checkBound(v reflect.Value, arg string, isMin bool) string {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid bound %q", arg)
+	}
+
+	var n float64
+	var unit string
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.String:
+		n, unit = float64(len(v.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = float64(v.Len()), " items"
+	default:
+		return ""
+	}
+
+	switch {
+	case isMin && n < bound:
+		return fmt.Sprintf("must be at least %s%s", arg, unit)
+	case !isMin && n > bound:
+		return fmt.Sprintf("must be at most %s%s", arg, unit)
+	}
+
+	return ""
+}
+
+func checkURL(v reflect.Value) string {
+	if v.Kind() != reflect.String {
+		return ""
+	}
+
+	u, err := url.Parse(v.String())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "must be an absolute http or https URL"
+	}
+
+	return ""
+}
+
+func checkDuration(v reflect.Value) string {
+	if v.Kind() != reflect.String {
+		return ""
+	}
+
+	if _, err := time.ParseDuration(v.String()); err != nil {
+		return "must be a duration such as 30s or 5m"
+	}
+
+	return ""
+}
+
+// fieldName returns the JSON name of the field.
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
@@ -0,0 +1,127 @@
+package validate_test
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"health-api/foundation/validate"
+)
+
+type result struct {
+	Target   string  `json:"target" validate:"required"`
+	Duration float64 `json:"duration_seconds" validate:"min=0"`
+}
+
+type payload struct {
+	Name     string            `json:"name" validate:"required"`
+	Tags     []string          `json:"tags" validate:"max=2"`
+	Minutes  int               `json:"minutes" validate:"min=1,max=60"`
+	Code     string            `json:"code" validate:"min=3,max=5"`
+	Runbook  string            `json:"runbook_url" validate:"url"`
+	Interval string            `json:"interval" validate:"duration"`
+	Labels   map[string]string `json:"labels" validate:"max=1"`
+	SLO      *float64          `json:"slo" validate:"required,min=0"`
+	Start    time.Time         `json:"start"`
+	Results  []result          `json:"results"`
+	Primary  *result           `json:"primary"`
+	hidden   string            `validate:"required"` // Unexported fields are not checked.
+}
+
+func valid() payload {
+	slo := 0.5
+	return payload{Name: "shop", SLO: &slo}
+}
+
+func Test_Check(t *testing.T) {
+	negative := -1.0
+
+	tests := []struct {
+		name string
+		edit func(*payload)
+		want validate.Errors
+	}{
+		{name: "valid", edit: func(p *payload) {}},
+		{name: "required", edit: func(p *payload) { p.Name = "" }, want: validate.Errors{"name": "is required"}},
+		{name: "nil pointer is skipped", edit: func(p *payload) { p.SLO = nil }},
+		{name: "pointer is checked", edit: func(p *payload) { p.SLO = &negative }, want: validate.Errors{"slo": "must be at least 0"}},
+		{name: "empty values skip the other rules", edit: func(p *payload) { p.Minutes, p.Code, p.Runbook, p.Interval = 0, "", "", "" }},
+		{name: "number below min", edit: func(p *payload) { p.Minutes = -5 }, want: validate.Errors{"minutes": "must be at least 1"}},
+		{name: "number above max", edit: func(p *payload) { p.Minutes = 61 }, want: validate.Errors{"minutes": "must be at most 60"}},
+		{name: "number on the bounds", edit: func(p *payload) { p.Minutes = 60 }},
+		{name: "string too short", edit: func(p *payload) { p.Code = "ab" }, want: validate.Errors{"code": "must be at least 3 characters"}},
+		{name: "string too long", edit: func(p *payload) { p.Code = "abcdef" }, want: validate.Errors{"code": "must be at most 5 characters"}},
+		{name: "slice too long", edit: func(p *payload) { p.Tags = []string{"a", "b", "c"} }, want: validate.Errors{"tags": "must be at most 2 items"}},
+		{name: "map too long", edit: func(p *payload) { p.Labels = map[string]string{"a": "1", "b": "2"} }, want: validate.Errors{"labels": "must be at most 1 items"}},
+		{name: "url", edit: func(p *payload) { p.Runbook = "https://wiki.example.com/shop" }},
+		{name: "relative url", edit: func(p *payload) { p.Runbook = "/wiki/shop" }, want: validate.Errors{"runbook_url": "must be an absolute http or https URL"}},
+		{name: "url scheme", edit: func(p *payload) { p.Runbook = "ftp://wiki.example.com" }, want: validate.Errors{"runbook_url": "must be an absolute http or https URL"}},
+		{name: "duration", edit: func(p *payload) { p.Interval = "1m30s" }},
+		{name: "bad duration", edit: func(p *payload) { p.Interval = "soon" }, want: validate.Errors{"interval": "must be a duration such as 30s or 5m"}},
+		{name: "nested slice", edit: func(p *payload) { p.Results = []result{{Target: "a"}, {Duration: -1}} }, want: validate.Errors{"results[1].target": "is required", "results[1].duration_seconds": "must be at least 0"}},
+		{name: "nested pointer", edit: func(p *payload) { p.Primary = &result{} }, want: validate.Errors{"primary.target": "is required"}},
+		{name: "every field", edit: func(p *payload) { p.Name, p.Minutes = "", 99 }, want: validate.Errors{"name": "is required", "minutes": "must be at most 60"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.edit(&p)
+
+			err := validate.Check(&p)
+
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Should pass validation, got %s", err)
+				}
+				return
+			}
+
+			var got validate.Errors
+			if !errors.As(err, &got) {
+				t.Fatalf("Should fail with field errors, got %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("Should report %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_CheckRules(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		want string
+	}{
+		{name: "unknown rule", val: struct {
+			Name string `json:"name" validate:"email"`
+		}{Name: "a"}, want: `validation failed: name: unknown validation rule "email"`},
+		{name: "bad bound", val: struct {
+			Name string `json:"name" validate:"min=x"`
+		}{Name: "a"}, want: `validation failed: name: invalid bound "x"`},
+		{name: "go name without json tag", val: struct {
+			Name string `validate:"required"`
+		}{}, want: "validation failed: Name: is required"},
+		{name: "sorted message", val: struct {
+			B string `json:"b" validate:"required"`
+			A string `json:"a" validate:"required"`
+		}{}, want: "validation failed: a: is required; b: is required"},
+		{name: "not a struct", val: "name"},
+		{name: "nil pointer", val: (*payload)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.Check(tt.val)
+
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Should pass validation, got %s", err)
+			case tt.want != "" && (err == nil || err.Error() != tt.want):
+				t.Errorf("Should fail with %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Set of binary encodings understood by Encoding.
+const (
+	EncodingMsgpack  = "msgpack"
+	EncodingProtobuf = "protobuf"
+)
+
+const (
+	mimeMsgpack  = "application/x-msgpack"
+	mimeProtobuf = "application/x-protobuf"
+)
+
+// Encoding returns the binary encoding requested by the client, taken from
+// the format query parameter or, failing that, the Accept header. It
+// returns an empty string for clients that want JSON.
+func Encoding(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case EncodingMsgpack:
+		return EncodingMsgpack
+	case EncodingProtobuf:
+		return EncodingProtobuf
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, mimeMsgpack), strings.Contains(accept, "application/msgpack"):
+		return EncodingMsgpack
+	case strings.Contains(accept, mimeProtobuf), strings.Contains(accept, "application/protobuf"):
+		return EncodingProtobuf
+	}
+
+	return ""
+}
+
+// MsgpackResponse encodes Data as MessagePack. Field names and omitempty
+// follow the json struct tags, so the document has the shape of the JSON
+// response. StatusCode defaults to 200 OK when not set.
+type MsgpackResponse struct {
+	Data       any
+	StatusCode int
+}
+
+// HTTPStatus returns the HTTP status code for the response.
+func (r MsgpackResponse) HTTPStatus() int {
+	if r.StatusCode == 0 {
+		return http.StatusOK
+	}
+	return r.StatusCode
+}
+
+func (r MsgpackResponse) Encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	enc.UseCompactFloats(true)
+
+	if err := enc.Encode(r.Data); err != nil {
+		return nil, "", fmt.Errorf("marshal msgpack: %w", err)
+	}
+	return buf.Bytes(), mimeMsgpack, nil
+}
+
+// ProtobufResponse carries a marshalled protobuf message. StatusCode
+// defaults to 200 OK when not set.
+type ProtobufResponse struct {
+	Data       []byte
+	StatusCode int
+}
+
+// HTTPStatus returns the HTTP status code for the response.
+func (r ProtobufResponse) HTTPStatus() int {
+	if r.StatusCode == 0 {
+		return http.StatusOK
+	}
+	return r.StatusCode
+}
+
+func (r ProtobufResponse) Encode() ([]byte, string, error) {
+	return r.Data, mimeProtobuf, nil
+}
@@ -0,0 +1,195 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// Egress selects the route outgoing connections take.
+type Egress struct {
+	// Proxy is an http://, https:// or socks5:// proxy URL. Empty keeps
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment and "direct"
+	// bypasses any proxy.
+	Proxy string
+
+	// Interface is the network interface name or local IP address
+	// outgoing connections are made from.
+	Interface string
+
+	// Family restricts connections to "ipv4" or "ipv6". Through a proxy
+	// it applies to the connection to the proxy.
+	Family string
+}
+
+// Set of address families accepted by Egress.
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// IsZero reports whether e leaves the route unchanged.
+func (e Egress) IsZero() bool {
+	return e.Proxy == "" && e.Interface == "" && e.Family == ""
+}
+
+// WithEgress returns a clone of t that routes requests as e selects.
+func WithEgress(t *http.Transport, e Egress) (*http.Transport, error) {
+	t = t.Clone()
+
+	if e.Interface != "" || e.Family != "" {
+		d, err := e.dialer()
+		if err != nil {
+			return nil, err
+		}
+		t.DialContext = d.DialContext
+	}
+
+	switch e.Proxy {
+	case "":
+	case "direct":
+		t.Proxy = nil
+	default:
+		u, err := e.proxyURL()
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	return t, nil
+}
+
+// DialContext returns a dial function for connections that don't speak
+// HTTP, such as SMTP. Only SOCKS5 proxies can carry them; an HTTP proxy is
+// an error.
+func (e Egress) DialContext() (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	d, err := e.dialer()
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Proxy == "" || e.Proxy == "direct" {
+		return d.DialContext, nil
+	}
+
+	u, err := e.proxyURL()
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("proxy %s can only carry HTTP, use a socks5 proxy", u.Redacted())
+	}
+
+	pd, err := proxy.FromURL(u, d)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %s: %w", u.Redacted(), err)
+	}
+
+	return pd.(proxy.ContextDialer).DialContext, nil
+}
+
+func (e Egress) proxyURL() (*url.URL, error) {
+	u, err := url.Parse(e.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("parsing proxy: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return u, nil
+	}
+
+	return nil, fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+}
+
+// dialer returns a dialer whose connections originate from e.Interface.
+// An interface name uses its first address of e.Family or, without one, its
+// first IPv4 address, or its first address when it has no IPv4 address.
+func (e Egress) dialer() (dialer, error) {
+	d := dialer{
+		Dialer: net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+
+	switch e.Family {
+	case "":
+	case FamilyIPv4:
+		d.suffix = "4"
+	case FamilyIPv6:
+		d.suffix = "6"
+	default:
+		return dialer{}, fmt.Errorf("unknown address family %q", e.Family)
+	}
+
+	if e.Interface == "" {
+		return d, nil
+	}
+
+	d.ip = net.ParseIP(e.Interface)
+	if d.ip == nil {
+		iface, err := net.InterfaceByName(e.Interface)
+		if err != nil {
+			return dialer{}, fmt.Errorf("source interface: %w", err)
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return dialer{}, fmt.Errorf("source interface %s: %w", e.Interface, err)
+		}
+
+		for _, a := range addrs {
+			ipNet, ok := a.(*net.IPNet)
+			if !ok || (e.Family == FamilyIPv4 && ipNet.IP.To4() == nil) || (e.Family == FamilyIPv6 && ipNet.IP.To4() != nil) {
+				continue
+			}
+			if d.ip == nil || (d.ip.To4() == nil && ipNet.IP.To4() != nil) {
+				d.ip = ipNet.IP
+			}
+		}
+
+		if d.ip == nil {
+			return dialer{}, fmt.Errorf("source interface %s has no address", e.Interface)
+		}
+	}
+
+	return d, nil
+}
+
+// dialer binds connections to a local address of the matching type, so
+// the same egress serves TCP and UDP, and pins them to an address family by
+// suffixing the network.
+type dialer struct {
+	net.Dialer
+	ip     net.IP
+	suffix string
+}
+
+func (d dialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+func (d dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if network == "tcp" || network == "udp" {
+		network += d.suffix
+	}
+
+	nd := d.Dialer
+	if d.ip != nil {
+		switch network {
+		case "udp", "udp4", "udp6":
+			nd.LocalAddr = &net.UDPAddr{IP: d.ip}
+		default:
+			nd.LocalAddr = &net.TCPAddr{IP: d.ip}
+		}
+	}
+	return nd.DialContext(ctx, network, addr)
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Links holds the links of an Envelope by relation, such as "self".
+type Links map[string]string
+
+// Envelope encodes a response in the versioned envelope of the v2 API:
+// the resource or list in data, counts and other information about it in
+// meta, and related URLs in links. StatusCode defaults to 200 OK when not
+// set.
+type Envelope struct {
+	Data       any
+	Meta       any
+	Links      Links
+	StatusCode int
+}
+
+// HTTPStatus returns the HTTP status code for the response.
+func (e Envelope) HTTPStatus() int {
+	if e.StatusCode == 0 {
+		return http.StatusOK
+	}
+	return e.StatusCode
+}
+
+func (e Envelope) Encode() ([]byte, string, error) {
+	body := struct {
+		Data  any   `json:"data"`
+		Meta  any   `json:"meta,omitempty"`
+		Links Links `json:"links,omitempty"`
+	}{
+		Data:  e.Data,
+		Meta:  e.Meta,
+		Links: e.Links,
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal envelope: %w", err)
+	}
+	return data, "application/json", nil
+}
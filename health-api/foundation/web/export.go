@@ -0,0 +1,158 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Set of export formats understood by Format.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+const mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// Format returns the export format requested by the client, taken from the
+// format query parameter or, failing that, the Accept header.
+func Format(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case FormatCSV:
+		return FormatCSV
+	case FormatXLSX:
+		return FormatXLSX
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "text/csv"):
+		return FormatCSV
+	case strings.Contains(accept, mimeXLSX):
+		return FormatXLSX
+	}
+
+	return FormatJSON
+}
+
+// CSVResponse encodes tabular data as CSV with a header row.
+type CSVResponse struct {
+	Header []string
+	Rows   [][]string
+}
+
+func (r CSVResponse) Encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(r.Header); err != nil {
+		return nil, "", fmt.Errorf("write csv header: %w", err)
+	}
+	if err := w.WriteAll(r.Rows); err != nil {
+		return nil, "", fmt.Errorf("write csv rows: %w", err)
+	}
+
+	return buf.Bytes(), "text/csv; charset=utf-8", nil
+}
+
+// XLSXResponse encodes tabular data as a single sheet Excel workbook.
+type XLSXResponse struct {
+	Header []string
+	Rows   [][]string
+}
+
+func (r XLSXResponse) Encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	sheet, err := r.sheetXML()
+	if err != nil {
+		return nil, "", err
+	}
+
+	parts := []struct {
+		name string
+		data string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/worksheets/sheet1.xml", sheet},
+	}
+
+	for _, p := range parts {
+		w, err := zw.Create(p.name)
+		if err != nil {
+			return nil, "", fmt.Errorf("create %s: %w", p.name, err)
+		}
+		if _, err := w.Write([]byte(p.data)); err != nil {
+			return nil, "", fmt.Errorf("write %s: %w", p.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, "", fmt.Errorf("close xlsx: %w", err)
+	}
+
+	return buf.Bytes(), mimeXLSX, nil
+}
+
+// sheetXML renders the rows as inline string cells.
+func (r XLSXResponse) sheetXML() (string, error) {
+	var b strings.Builder
+
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	rows := append([][]string{r.Header}, r.Rows...)
+	for i, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t>`, columnName(j), i+1)
+			if err := xml.EscapeText(&b, []byte(cell)); err != nil {
+				return "", fmt.Errorf("escape cell: %w", err)
+			}
+			b.WriteString(`</t></is></c>`)
+		}
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String(), nil
+}
+
+// columnName converts a zero based index into a spreadsheet column name.
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
@@ -0,0 +1,132 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Fields returns the field names asked for with ?fields=a,b, or nil when
+// the request asks for every field.
+func Fields(r *http.Request) []string {
+	var fields []string
+	for _, f := range strings.Split(r.URL.Query().Get("fields"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// selectFields wraps resp so that a successful JSON response only keeps
+// the fields the request asks for. Errors and other responses are left
+// alone.
+func selectFields(r *http.Request, resp Encoder) Encoder {
+	if _, ok := resp.(error); ok || resp == nil {
+		return resp
+	}
+
+	fields := Fields(r)
+	if len(fields) == 0 {
+		return resp
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	return fieldsEncoder{resp: resp, keep: keep}
+}
+
+// fieldsEncoder projects the JSON encoded by resp onto a set of fields.
+type fieldsEncoder struct {
+	resp Encoder
+	keep map[string]bool
+}
+
+// HTTPStatus returns the status of the wrapped response.
+func (e fieldsEncoder) HTTPStatus() int {
+	if v, ok := e.resp.(interface{ HTTPStatus() int }); ok {
+		return v.HTTPStatus()
+	}
+	return http.StatusOK
+}
+
+func (e fieldsEncoder) Encode() ([]byte, string, error) {
+	data, contentType, err := e.resp.Encode()
+	if err != nil {
+		return nil, "", err
+	}
+
+	status := e.HTTPStatus()
+	if status < 200 || status >= 300 || !strings.HasPrefix(contentType, "application/json") {
+		return data, contentType, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, "", fmt.Errorf("select fields: %w", err)
+	}
+
+	if data, err = json.Marshal(e.project(v)); err != nil {
+		return nil, "", fmt.Errorf("select fields: %w", err)
+	}
+
+	return data, contentType, nil
+}
+
+// project applies the selection to a decoded response. A list keeps the
+// fields of each of its items. An object with one of the fields is an item
+// itself; any other object is taken as an envelope such as a summary or
+// the v2 envelope, whose lists and objects are projected and whose other
+// fields are kept.
+func (e fieldsEncoder) project(v any) any {
+	switch v := v.(type) {
+	case []any:
+		return e.items(v)
+
+	case map[string]any:
+		for k := range v {
+			if e.keep[k] {
+				return e.item(v)
+			}
+		}
+
+		for k, val := range v {
+			switch val := val.(type) {
+			case []any:
+				v[k] = e.items(val)
+			case map[string]any:
+				v[k] = e.project(val)
+			}
+		}
+		return v
+	}
+
+	return v
+}
+
+func (e fieldsEncoder) items(list []any) []any {
+	for i, val := range list {
+		if obj, ok := val.(map[string]any); ok {
+			list[i] = e.item(obj)
+		}
+	}
+	return list
+}
+
+func (e fieldsEncoder) item(obj map[string]any) map[string]any {
+	out := make(map[string]any, len(e.keep))
+	for k, val := range obj {
+		if e.keep[k] {
+			out[k] = val
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package web
+
+import (
+	"slices"
+)
+
+// Group registers routes under a common path prefix with a shared set of
+// middleware. Group middleware runs inside the app-level middleware and
+// outside any route-specific middleware.
+type Group struct {
+	app    *App
+	prefix string
+	mw     []Middleware
+}
+
+// Group creates a route group rooted at prefix.
+func (a *App) Group(prefix string, mw ...Middleware) *Group {
+	return &Group{
+		app:    a,
+		prefix: prefix,
+		mw:     mw,
+	}
+}
+
+// Group creates a nested group that inherits the parent's prefix and
+// middleware.
+func (g *Group) Group(prefix string, mw ...Middleware) *Group {
+	return &Group{
+		app:    g.app,
+		prefix: g.prefix + prefix,
+		mw:     append(slices.Clone(g.mw), mw...),
+	}
+}
+
+// Use appends middleware to the group. It only affects routes registered
+// afterwards.
+func (g *Group) Use(mw ...Middleware) {
+	g.mw = append(g.mw, mw...)
+}
+
+// HandlerFunc registers a handler in the group.
+func (g *Group) HandlerFunc(method, path string, hdl HandlerFunc, mw ...Middleware) {
+	g.app.HandlerFunc(method, g.prefix+path, hdl, append(slices.Clone(g.mw), mw...)...)
+}
+
+// HandlerFuncNoMid registers a handler in the group without the app-level
+// middleware, for probes and scrapes that must not be logged, traced or
+// timed out. The group's own middleware still applies.
+func (g *Group) HandlerFuncNoMid(method, path string, hdl HandlerFunc) {
+	g.app.HandlerFuncNoMid(method, g.prefix+path, wrapMiddleware(g.mw, hdl))
+}
@@ -0,0 +1,106 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// PoolConfig tunes the connection pool of clients calling backends. Zero
+// values keep the defaults of http.DefaultTransport.
+type PoolConfig struct {
+	// MaxIdleConns limits idle connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections kept per host. The
+	// standard library keeps two, so bursts of concurrent queries open and
+	// close connections; raise it to the expected concurrency.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits connections per host, 0 for no limit.
+	MaxConnsPerHost int
+
+	// IdleConnTimeout closes connections idle for longer.
+	IdleConnTimeout time.Duration
+
+	// KeepAlive is the TCP keep-alive period, negative to disable.
+	KeepAlive time.Duration
+
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+
+	// DisableHTTP2 keeps HTTP/1.1 even when the server offers HTTP/2.
+	DisableHTTP2 bool
+
+	// CAFile holds PEM certificates trusted in addition to the system pool.
+	CAFile string
+
+	// InsecureSkipVerify disables certificate verification.
+	InsecureSkipVerify bool
+}
+
+// NewPoolTransport returns a transport configured from cfg. It is meant to
+// be shared by all clients of a process, so connections to the same host
+// are reused across them.
+func NewPoolTransport(cfg PoolConfig) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	keepAlive := 30 * time.Second
+	if cfg.KeepAlive != 0 {
+		keepAlive = cfg.KeepAlive
+	}
+	t.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: keepAlive,
+	}).DialContext
+
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	t.DisableKeepAlives = cfg.DisableKeepAlives
+
+	if cfg.CAFile != "" || cfg.InsecureSkipVerify {
+		tlsCfg := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+		}
+
+		if cfg.CAFile != "" {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("reading ca file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates in %s", cfg.CAFile)
+			}
+			tlsCfg.RootCAs = pool
+		}
+
+		t.TLSClientConfig = tlsCfg
+	}
+
+	// A custom TLS config or dialer turns off HTTP/2 unless it is asked
+	// for explicitly; an empty TLSNextProto map turns it off for good.
+	t.ForceAttemptHTTP2 = !cfg.DisableHTTP2
+	if cfg.DisableHTTP2 {
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return t, nil
+}
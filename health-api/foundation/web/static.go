@@ -0,0 +1,99 @@
+package web
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// FileServer serves the files in fsys under urlPath, e.g. an embedded
+// frontend build. Requests for missing files that accept HTML fall back to
+// index.html so client-side routes of a single page application resolve.
+// index.html is always revalidated; other assets are cached, and files under
+// assets/ (content-hashed by the frontend build) are cached indefinitely.
+func (a *App) FileServer(urlPath string, fsys fs.FS) {
+	prefix := strings.TrimSuffix(urlPath, "/")
+
+	h := func(ctx context.Context, r *http.Request) Encoder {
+		w := GetWriter(ctx)
+		if w == nil {
+			return nil
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		name = resolveFile(fsys, name)
+
+		if name == "" {
+			if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+				http.NotFound(w, r)
+				return nil
+			}
+			name = "index.html"
+		}
+
+		switch {
+		case name == "index.html":
+			w.Header().Set("Cache-Control", "no-cache")
+		case strings.HasPrefix(name, "assets/"):
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		default:
+			w.Header().Set("Cache-Control", "public, max-age=3600")
+		}
+
+		serveFile(w, r, fsys, name)
+		return nil
+	}
+
+	a.HandlerFunc(http.MethodGet, prefix+"/", h)
+}
+
+// resolveFile returns the file in fsys to serve for name, mapping
+// directories to their index.html. It returns an empty string when there is
+// no such file.
+func resolveFile(fsys fs.FS, name string) string {
+	if name == "" {
+		name = "."
+	}
+
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return ""
+	}
+
+	if info.IsDir() {
+		name = path.Join(name, "index.html")
+		if _, err := fs.Stat(fsys, name); err != nil {
+			return ""
+		}
+	}
+
+	return name
+}
+
+// serveFile writes the named file, avoiding the redirect http.ServeFileFS
+// issues for paths ending in index.html.
+func serveFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		http.ServeFileFS(w, r, fsys, name)
+		return
+	}
+
+	http.ServeContent(w, r, info.Name(), info.ModTime(), rs)
+}
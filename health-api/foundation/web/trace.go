@@ -0,0 +1,89 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader carries a trace ID between services that do not speak
+// W3C trace context.
+const requestIDHeader = "X-Request-ID"
+
+// requestIDPattern is what a caller's X-Request-ID must look like to be
+// used. The ID ends up in logs, responses and outgoing requests, so
+// anything longer or with other characters is replaced.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// extractTrace continues the caller's trace by extracting the W3C trace
+// context from the request headers.
+func extractTrace(ctx context.Context, r *http.Request) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// requestTraceID returns the trace ID to use for the request: the W3C trace
+// ID when present, then a well-formed X-Request-ID, and finally a newly
+// generated ID.
+func requestTraceID(ctx context.Context, r *http.Request) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+
+	if id := RequestID(r); id != "" {
+		return id
+	}
+
+	return generateTraceID()
+}
+
+// RequestID returns the request's X-Request-ID, or an empty string when it
+// is missing or not well-formed.
+func RequestID(r *http.Request) string {
+	if id := r.Header.Get(requestIDHeader); requestIDPattern.MatchString(id) {
+		return id
+	}
+	return ""
+}
+
+// generateTraceID returns a random ID in the W3C trace ID format.
+func generateTraceID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// =============================================================================
+
+// Transport propagates the request's trace context to outgoing requests,
+// e.g. calls to Grafana and Prometheus made while handling a request.
+type Transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport when base is nil.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	if id := GetTraceID(ctx); id != "" && req.Header.Get(requestIDHeader) == "" {
+		req.Header.Set(requestIDHeader, id)
+	}
+
+	return t.base.RoundTrip(req)
+}
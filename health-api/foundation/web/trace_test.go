@@ -0,0 +1,62 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"health-api/foundation/web"
+)
+
+func Test_RequestTraceID(t *testing.T) {
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	tests := []struct {
+		name      string
+		requestID string
+		want      string
+	}{
+		{name: "none"},
+		{name: "uuid", requestID: "5f0c2b1e-8a7d-4c39-9e21-0d3f6a4b7c18", want: "5f0c2b1e-8a7d-4c39-9e21-0d3f6a4b7c18"},
+		{name: "dotted", requestID: "edge.v1_42", want: "edge.v1_42"},
+		{name: "longest", requestID: strings.Repeat("a", 128), want: strings.Repeat("a", 128)},
+		{name: "too long", requestID: strings.Repeat("a", 129)},
+		{name: "spaces", requestID: "abc def"},
+		{name: "log injection", requestID: "abc\",\"level\":\"ERROR"},
+		{name: "control characters", requestID: "abc\x1b[31m"},
+		{name: "non ascii", requestID: "ид-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var traceID, requestID string
+
+			app := web.NewApp(nil)
+			app.HandlerFunc(http.MethodGet, "/trace", func(ctx context.Context, r *http.Request) web.Encoder {
+				traceID = web.GetTraceID(ctx)
+				requestID = web.RequestID(r)
+				return nil
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/trace", nil)
+			if tt.requestID != "" {
+				r.Header.Set("X-Request-ID", tt.requestID)
+			}
+			app.ServeHTTP(httptest.NewRecorder(), r)
+
+			if requestID != tt.want {
+				t.Errorf("Should take %q as the request ID, got %q", tt.want, requestID)
+			}
+
+			switch {
+			case tt.want != "" && traceID != tt.want:
+				t.Errorf("Should use the request ID as trace ID, got %q", traceID)
+			case tt.want == "" && !generated.MatchString(traceID):
+				t.Errorf("Should generate a trace ID, got %q", traceID)
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"time"
 
+	"health-api/foundation/validate"
+
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -24,9 +26,11 @@ type Encoder interface {
 
 // App is the entry point into our application.
 type App struct {
-	mux    *http.ServeMux
-	mw     []Middleware
-	tracer trace.Tracer
+	mux     *http.ServeMux
+	mw      []Middleware
+	tracer  trace.Tracer
+	cors    Middleware
+	options map[string]bool
 }
 
 // NewApp creates an App with the specified middleware.
@@ -38,22 +42,41 @@ func NewApp(tracer trace.Tracer, mw ...Middleware) *App {
 	}
 }
 
+// EnableCORS applies the CORS middleware to every route and registers an
+// OPTIONS handler for each route path so preflight requests reach it. It
+// must be called before any routes are registered.
+func (a *App) EnableCORS(mw Middleware) {
+	a.cors = mw
+	a.options = make(map[string]bool)
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	a.mux.ServeHTTP(w, r)
 }
 
-// HandlerFunc registers a handler function with middleware.
-func (a *App) HandlerFunc(method, group, path string, hdl HandlerFunc, mw ...Middleware) {
+// HandlerFunc registers a handler function with middleware. Routes that
+// share a prefix or middleware register through a Group instead.
+func (a *App) HandlerFunc(method, path string, hdl HandlerFunc, mw ...Middleware) {
 	// Wrap with route-specific middleware first
 	hdl = wrapMiddleware(mw, hdl)
 
+	// CORS sits just inside the app-level middleware
+	if a.cors != nil {
+		hdl = a.cors(hdl)
+	}
+
 	// Then wrap with app-level middleware
 	hdl = wrapMiddleware(a.mw, hdl)
 
 	// Convert to http.HandlerFunc
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		ctx := setWriter(r.Context(), w)
+		rw := newResponseWriter(w)
+		ctx := setWriter(r.Context(), rw)
+		ctx = setRoute(ctx, path)
+
+		// Continue the caller's trace if one was propagated
+		ctx = extractTrace(ctx, r)
 
 		// Add tracing span if tracer is available
 		if a.tracer != nil {
@@ -62,44 +85,67 @@ func (a *App) HandlerFunc(method, group, path string, hdl HandlerFunc, mw ...Mid
 			defer span.End()
 		}
 
-		// Generate trace ID if not present
+		// Set the trace ID if not present
 		if getTraceID(ctx) == "" {
-			ctx = setTraceID(ctx, generateTraceID())
+			ctx = setTraceID(ctx, requestTraceID(ctx, r))
 		}
 
 		// Call the handler
-		resp := hdl(ctx, r)
+		resp := selectFields(r, hdl(ctx, r))
 
 		// Write the response
-		if err := Respond(ctx, w, resp); err != nil {
+		if err := Respond(ctx, rw, resp); err != nil {
 			// Log error but don't fail - response may already be written
 		}
 	}
 
 	// Register with the mux
-	pattern := fmt.Sprintf("%s %s%s", method, group, path)
-	a.mux.HandleFunc(pattern, handler)
+	a.mux.HandleFunc(method+" "+path, handler)
+
+	a.registerOptions(method, path)
 }
 
 // HandlerFuncNoMid registers a handler without app-level middleware.
-func (a *App) HandlerFuncNoMid(method, group, path string, hdl HandlerFunc) {
+func (a *App) HandlerFuncNoMid(method, path string, hdl HandlerFunc) {
+	if a.cors != nil {
+		hdl = a.cors(hdl)
+	}
+
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		ctx := setWriter(r.Context(), w)
+		rw := newResponseWriter(w)
+		ctx := setWriter(r.Context(), rw)
+		ctx = setRoute(ctx, path)
+		ctx = extractTrace(ctx, r)
 
-		// Generate trace ID
+		// Set the trace ID
 		if getTraceID(ctx) == "" {
-			ctx = setTraceID(ctx, generateTraceID())
+			ctx = setTraceID(ctx, requestTraceID(ctx, r))
 		}
 
-		resp := hdl(ctx, r)
+		resp := selectFields(r, hdl(ctx, r))
 
-		if err := Respond(ctx, w, resp); err != nil {
+		if err := Respond(ctx, rw, resp); err != nil {
 			// Error already logged by middleware
 		}
 	}
 
-	pattern := fmt.Sprintf("%s %s%s", method, group, path)
-	a.mux.HandleFunc(pattern, handler)
+	a.mux.HandleFunc(method+" "+path, handler)
+
+	a.registerOptions(method, path)
+}
+
+// registerOptions adds an OPTIONS route for path the first time it is seen
+// so the CORS middleware can answer preflight requests.
+func (a *App) registerOptions(method, path string) {
+	if a.cors == nil || method == http.MethodOptions || a.options[path] {
+		return
+	}
+	a.options[path] = true
+
+	preflight := func(ctx context.Context, r *http.Request) Encoder {
+		return nil
+	}
+	a.HandlerFunc(http.MethodOptions, path, preflight)
 }
 
 // wrapMiddleware chains middleware around a handler.
@@ -115,6 +161,14 @@ func wrapMiddleware(mw []Middleware, handler HandlerFunc) HandlerFunc {
 
 // Respond encodes and writes the response.
 func Respond(ctx context.Context, w http.ResponseWriter, resp Encoder) error {
+	// The handler already wrote the response directly
+	if rw, ok := w.(*ResponseWriter); ok && rw.Written() {
+		if v := GetValues(ctx); v != nil {
+			v.StatusCode = rw.Status()
+		}
+		return nil
+	}
+
 	// Handle nil responses as 204 No Content
 	if resp == nil {
 		w.WriteHeader(http.StatusNoContent)
@@ -151,7 +205,9 @@ func Respond(ctx context.Context, w http.ResponseWriter, resp Encoder) error {
 	return nil
 }
 
-// Decode decodes the request body into the provided value.
+// Decode decodes the request body into the provided value and checks it
+// against its validate struct tags. Validation failures are returned as
+// validate.Errors.
 func Decode(r *http.Request, val any) error {
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
@@ -160,7 +216,7 @@ func Decode(r *http.Request, val any) error {
 		return fmt.Errorf("decode json: %w", err)
 	}
 
-	return nil
+	return validate.Check(val)
 }
 
 // Param extracts a path parameter from the request.
@@ -175,6 +231,7 @@ type Values struct {
 	TraceID    string
 	Now        time.Time
 	StatusCode int
+	Tenant     string
 }
 
 type ctxKey int
@@ -183,6 +240,7 @@ const (
 	key ctxKey = iota
 	writerKey
 	traceKey
+	routeKey
 )
 
 // SetValues stores the Values in the context.
@@ -226,20 +284,41 @@ func getTraceID(ctx context.Context) string {
 	return traceID
 }
 
+func setRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey, route)
+}
+
+// GetRoute returns the route template the request matched, such as
+// "/api/v1/health/{target}". Use it instead of the request path wherever
+// unbounded values would be a problem, e.g. metric labels.
+func GetRoute(ctx context.Context) string {
+	route, ok := ctx.Value(routeKey).(string)
+	if !ok {
+		return ""
+	}
+	return route
+}
+
 // GetTraceID returns the trace ID from the context.
 func GetTraceID(ctx context.Context) string {
 	return getTraceID(ctx)
 }
 
-func generateTraceID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
-}
-
 // =============================================================================
 
-// JSONResponse is a simple JSON response encoder.
+// JSONResponse is a simple JSON response encoder. StatusCode defaults to
+// 200 OK when not set.
 type JSONResponse struct {
-	Data any
+	Data       any
+	StatusCode int
+}
+
+// HTTPStatus returns the HTTP status code for the response.
+func (r JSONResponse) HTTPStatus() int {
+	if r.StatusCode == 0 {
+		return http.StatusOK
+	}
+	return r.StatusCode
 }
 
 func (r JSONResponse) Encode() ([]byte, string, error) {
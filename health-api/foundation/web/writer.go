@@ -0,0 +1,98 @@
+package web
+
+import (
+	"context"
+	"net/http"
+)
+
+// ResponseWriter wraps an http.ResponseWriter and records the status code
+// and number of bytes written, whether the response was produced by Respond
+// or written directly by a handler via GetWriter.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func newResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+// WriteHeader records the status code. Only the first call is forwarded.
+func (w *ResponseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the bytes written, implying a 200 status if none was set.
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (w *ResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status code written, or 0 if nothing was written yet.
+func (w *ResponseWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of body bytes written.
+func (w *ResponseWriter) Size() int {
+	return w.size
+}
+
+// Written reports whether the response header has been sent.
+func (w *ResponseWriter) Written() bool {
+	return w.status != 0
+}
+
+// GetResponseWriter returns the recording writer for the request, or nil
+// outside of an App handler.
+func GetResponseWriter(ctx context.Context) *ResponseWriter {
+	w, ok := ctx.Value(writerKey).(*ResponseWriter)
+	if !ok {
+		return nil
+	}
+	return w
+}
+
+// Status returns the status code the client receives for resp. A status
+// already written directly to the ResponseWriter takes precedence over the
+// one the Encoder reports.
+func Status(ctx context.Context, resp Encoder) int {
+	if w := GetResponseWriter(ctx); w != nil && w.Written() {
+		return w.Status()
+	}
+
+	switch v := resp.(type) {
+	case nil:
+		return http.StatusNoContent
+	case interface{ HTTPStatus() int }:
+		return v.HTTPStatus()
+	case error:
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusOK
+}